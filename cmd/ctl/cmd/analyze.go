@@ -6,9 +6,13 @@ import (
 	"fmt"
 	"image"
 	"image/jpeg"
+	"log/slog"
+	"maps"
 	"os"
+	"slices"
 
 	dicos "github.com/jpfielding/dicos.go/pkg/dicos"
+	"github.com/jpfielding/dicos.go/pkg/logging"
 	jpegli "github.com/jpfielding/jpegs/pkg/compress/jpegli"
 	jpegls "github.com/jpfielding/jpegs/pkg/compress/jpegls"
 	"github.com/spf13/cobra"
@@ -24,6 +28,10 @@ func NewAnalyzeCmd(ctx context.Context) *cobra.Command {
 			filePath, _ := cmd.Flags().GetString("file")
 			dumpFrame, _ := cmd.Flags().GetInt("dump-frame")
 			out, _ := cmd.Flags().GetString("out")
+			strict, _ := cmd.Flags().GetBool("strict")
+			bestEffort, _ := cmd.Flags().GetBool("best-effort")
+			extract, _ := cmd.Flags().GetStringSlice("extract")
+			forceCodec, _ := cmd.Flags().GetString("force-codec")
 
 			if filePath == "" && len(args) > 0 {
 				filePath = args[0]
@@ -33,7 +41,14 @@ func NewAnalyzeCmd(ctx context.Context) *cobra.Command {
 				return fmt.Errorf("file path is required. Use --file flag or provide as argument")
 			}
 
-			return runAnalyze(filePath, dumpFrame, out)
+			ctx := logging.AppendCtx(ctx, slog.String("file", filePath))
+			if forceCodec != "" {
+				if dicos.CodecByName(forceCodec) == nil {
+					return fmt.Errorf("unknown codec %q", forceCodec)
+				}
+				ctx = dicos.WithDecodeOptions(ctx, dicos.DecodeOptions{ForceCodec: forceCodec})
+			}
+			return runAnalyze(ctx, filePath, dumpFrame, out, dicos.ParseOptions{Strict: strict, BestEffort: bestEffort}, extract)
 		},
 	}
 
@@ -41,19 +56,45 @@ func NewAnalyzeCmd(ctx context.Context) *cobra.Command {
 	pf.StringP("file", "f", "", "DICOS/DICOM file path to analyze")
 	pf.Int("dump-frame", -1, "Index of frame to dump to disk")
 	pf.String("out", "", "Output path for dumped frame")
+	pf.Bool("strict", false, "Fail on the first encoding violation instead of listing them")
+	pf.Bool("best-effort", false, "Skip elements that cannot be parsed and analyze the rest")
+	pf.StringSlice("extract", nil, "Extractors whose derived fields to print, or \"all\"")
+	pf.String("force-codec", "", "Decode frames with this codec regardless of the transfer syntax (jpeg-ls, jpeg-li, rle, jpeg-2000, jpeg-baseline, jpeg-extended)")
+	cmd.MarkPersistentFlagFilename("file", "dcs", "dcm")
+	cmd.RegisterFlagCompletionFunc("extract", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return append(dicos.ExtractorNames(), "all"), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.RegisterFlagCompletionFunc("force-codec", cobra.FixedCompletions([]string{"jpeg-ls", "jpeg-li", "rle", "jpeg-2000", "jpeg-baseline", "jpeg-extended"}, cobra.ShellCompDirectiveNoFileComp))
 
 	return cmd
 }
 
 // runAnalyze performs the DICOS file analysis using pkg/dicos
-func runAnalyze(filePath string, dumpFrame int, outPath string) error {
-	// Use the new pkg/dicos API
-	ds, err := dicos.ReadFile(filePath)
+func runAnalyze(ctx context.Context, filePath string, dumpFrame int, outPath string, opts dicos.ParseOptions, extract []string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ds, issues, err := dicos.ParseWithIssues(ctx, f, opts)
 	if err != nil {
 		return fmt.Errorf("parse error: %w", err)
 	}
 
-	fmt.Printf("Total elements: %d\n\n", len(ds.Elements))
+	fmt.Printf("Total elements: %d\n", len(ds.Elements))
+	if len(ds.Trailing) > 0 {
+		fmt.Printf("Trailing data: %d bytes after the dataset\n", len(ds.Trailing))
+	}
+	fmt.Println()
+
+	if len(issues) > 0 {
+		fmt.Println("=== Parse Issues ===")
+		for _, issue := range issues {
+			fmt.Println(issue.Error())
+		}
+		fmt.Println()
+	}
 
 	// Print key metadata
 	fmt.Println("=== Key Metadata ===")
@@ -83,6 +124,22 @@ func runAnalyze(filePath string, dumpFrame int, outPath string) error {
 
 	fmt.Println()
 
+	if len(extract) > 0 {
+		if slices.Contains(extract, "all") {
+			extract = nil
+		}
+		fields, err := dicos.ExtractFields(ds, extract...)
+		if err != nil {
+			slog.WarnContext(ctx, "Extraction incomplete", slog.Any("error", err))
+		}
+		fmt.Println("=== Extracted Fields ===")
+		keys := slices.Sorted(maps.Keys(fields))
+		for _, k := range keys {
+			fmt.Printf("%s: %v\n", k, fields[k])
+		}
+		fmt.Println()
+	}
+
 	// Analyze pixel data
 	pd, err := ds.GetPixelData()
 	if err != nil {
@@ -178,11 +235,11 @@ func runAnalyze(filePath string, dumpFrame int, outPath string) error {
 
 	// Try decoding entire volume
 	fmt.Println("\n=== Volume Decode Test ===")
-	vol, err := dicos.DecodeVolume(ds)
+	vol, err := dicos.DecodeVolumeContext(ctx, ds)
 	if err != nil {
 		fmt.Printf("Volume decode error: %v\n", err)
 	} else {
-		minVal, maxVal := vol.MinMax()
+		minVal, maxVal := vol.SampleRange()
 		fmt.Printf("Volume: %dx%dx%d\n", vol.Width, vol.Height, vol.Depth)
 		fmt.Printf("Voxel range: min=%d, max=%d\n", minVal, maxVal)
 	}
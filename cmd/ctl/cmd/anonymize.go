@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+	"github.com/jpfielding/dicos.go/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+// NewAnonymizeCmd creates the anonymize cobra command
+func NewAnonymizeCmd(ctx context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "anonymize <file|dir>... --out <dir>",
+		Short: "De-identify DICOS files",
+		Long:  "Writes de-identified copies of DICOS files to an output directory using a selectable profile (basic, retain-device-info, retain-dates). A UID map can be loaded and saved so that repeated runs replace the same study, series and instance UIDs consistently. Prints a summary of the removed and modified tags.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags := cmd.Flags()
+			profile, _ := flags.GetString("anon-profile")
+			out, _ := flags.GetString("out")
+			recursive, _ := flags.GetBool("recursive")
+			uidMapIn, _ := flags.GetString("uid-map-in")
+			uidMapOut, _ := flags.GetString("uid-map-out")
+			if out == "" {
+				return fmt.Errorf("--out is required")
+			}
+
+			uids := dicos.UIDMap{}
+			if uidMapIn != "" {
+				b, err := os.ReadFile(uidMapIn)
+				if err != nil {
+					return err
+				}
+				if err := json.Unmarshal(b, &uids); err != nil {
+					return fmt.Errorf("%s: %w", uidMapIn, err)
+				}
+			}
+
+			opts := dicos.AnonymizeOptions{Profile: dicos.AnonymizeProfile(profile), UIDs: uids}
+			summary, err := runAnonymize(ctx, args, out, recursive, opts)
+			if err != nil {
+				return err
+			}
+			summary.print()
+
+			if uidMapOut != "" {
+				b, err := json.MarshalIndent(uids, "", "  ")
+				if err != nil {
+					return err
+				}
+				if err := os.WriteFile(uidMapOut, b, 0o600); err != nil {
+					return err
+				}
+				slog.InfoContext(ctx, "UID map written", slog.String("path", uidMapOut), slog.Int("uids", len(uids)))
+			}
+			if summary.failed > 0 {
+				return fmt.Errorf("%d of %d files failed", summary.failed, summary.files)
+			}
+			return nil
+		},
+	}
+	pf := cmd.PersistentFlags()
+	pf.String("anon-profile", string(dicos.ProfileBasic), "De-identification profile (basic, retain-device-info, retain-dates)")
+	pf.StringP("out", "o", "", "Directory to write de-identified files to, mirroring the input layout")
+	pf.BoolP("recursive", "r", false, "Descend into subdirectories of directory arguments")
+	pf.String("uid-map-in", "", "JSON UID map from a previous run to reuse")
+	pf.String("uid-map-out", "", "Write the original-to-new UID map as JSON to this path")
+	cmd.MarkPersistentFlagDirname("out")
+	cmd.MarkPersistentFlagFilename("uid-map-in", "json")
+	cmd.MarkPersistentFlagFilename("uid-map-out", "json")
+	profiles := make([]string, len(dicos.AnonymizeProfiles))
+	for i, p := range dicos.AnonymizeProfiles {
+		profiles[i] = string(p)
+	}
+	cmd.RegisterFlagCompletionFunc("anon-profile", cobra.FixedCompletions(profiles, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+// anonymizeSummary tallies an anonymize run
+type anonymizeSummary struct {
+	files, failed int
+	removed       map[dicos.Tag]int
+	modified      map[dicos.Tag]int
+}
+
+func (s *anonymizeSummary) add(report dicos.AnonymizeReport) {
+	for _, t := range report.Removed {
+		s.removed[t]++
+	}
+	for _, t := range report.Modified {
+		s.modified[t]++
+	}
+}
+
+func (s *anonymizeSummary) print() {
+	fmt.Printf("files=%d failed=%d\n", s.files, s.failed)
+	for _, section := range []struct {
+		name   string
+		counts map[dicos.Tag]int
+	}{{"removed", s.removed}, {"modified", s.modified}} {
+		tags := make([]dicos.Tag, 0, len(section.counts))
+		for t := range section.counts {
+			tags = append(tags, t)
+		}
+		sort.Slice(tags, func(i, j int) bool { return tags[i].Less(tags[j]) })
+		fmt.Printf("%s:\n", section.name)
+		for _, t := range tags {
+			fmt.Printf("  %v %-32s %d\n", t, t.LookupName(), section.counts[t])
+		}
+	}
+}
+
+// anonymizeInput is a file to anonymize and its path relative to the output directory
+type anonymizeInput struct {
+	path, rel string
+}
+
+// collectAnonymizeInputs expands directory arguments into their files
+func collectAnonymizeInputs(args []string, recursive bool) ([]anonymizeInput, error) {
+	var inputs []anonymizeInput
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			inputs = append(inputs, anonymizeInput{path: arg, rel: filepath.Base(arg)})
+			continue
+		}
+		err = filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path != arg && (!recursive || strings.HasPrefix(d.Name(), ".")) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			rel, err := filepath.Rel(arg, path)
+			if err != nil {
+				return err
+			}
+			inputs = append(inputs, anonymizeInput{path: path, rel: filepath.Join(filepath.Base(arg), rel)})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return inputs, nil
+}
+
+func runAnonymize(ctx context.Context, args []string, out string, recursive bool, opts dicos.AnonymizeOptions) (*anonymizeSummary, error) {
+	inputs, err := collectAnonymizeInputs(args, recursive)
+	if err != nil {
+		return nil, err
+	}
+	summary := &anonymizeSummary{removed: map[dicos.Tag]int{}, modified: map[dicos.Tag]int{}}
+	for _, in := range inputs {
+		ctx := logging.AppendCtx(ctx, slog.String("file", in.path))
+		summary.files++
+		ds, err := dicos.ReadFileContext(ctx, in.path)
+		if err != nil {
+			slog.WarnContext(ctx, "Skipping unreadable file", slog.Any("error", err))
+			summary.failed++
+			continue
+		}
+		anon, report, err := dicos.Anonymize(ds, opts)
+		if err != nil {
+			return nil, err // bad profile, same for every file
+		}
+		dst := filepath.Join(out, in.rel)
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return nil, err
+		}
+		if _, err := dicos.WriteFile(dst, anon); err != nil {
+			slog.WarnContext(ctx, "Failed to write anonymized file", slog.String("out", dst), slog.Any("error", err))
+			summary.failed++
+			continue
+		}
+		summary.add(report)
+		slog.DebugContext(ctx, "Anonymized", slog.String("out", dst))
+	}
+	return summary, nil
+}
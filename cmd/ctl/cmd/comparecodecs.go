@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+	"github.com/jpfielding/dicos.go/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+// CodecReport is the outcome of one codec over the frames of a file
+type CodecReport struct {
+	Codec  string        `json:"codec"`
+	Frames int           `json:"frames"`
+	Size   int64         `json:"size"`  // compressed bytes over all frames
+	Ratio  float64       `json:"ratio"` // uncompressed / compressed size
+	Took   time.Duration `json:"took"`  // encode time over all frames
+}
+
+// NewCompareCodecsCmd creates the compare-codecs cobra command
+func NewCompareCodecsCmd(ctx context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compare-codecs <file.dcs>",
+		Short: "Compare lossless codecs on the frames of a DICOS file",
+		Long:  "Decodes the frames of a DICOS file, compresses each with every lossless codec through CompareCompressionRatio, and prints the total size, ratio and encode time per codec, so archive settings can be chosen from real scans. --max-frames samples evenly spaced frames of large volumes.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags := cmd.Flags()
+			names, _ := flags.GetStringSlice("codecs")
+			maxFrames, _ := flags.GetInt("max-frames")
+			var codecs []dicos.Codec
+			for _, name := range names {
+				codec := dicos.CodecByName(name)
+				if codec == nil {
+					return fmt.Errorf("unknown codec %q", name)
+				}
+				codecs = append(codecs, codec)
+			}
+
+			ctx := logging.AppendCtx(ctx, slog.String("file", args[0]))
+			df, err := decodeAllFrames(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			reports, err := compareCodecs(ctx, df, sampleFrames(len(df.frames), maxFrames), codecs)
+			if err != nil {
+				return err
+			}
+
+			switch format, _ := flags.GetString("format"); format {
+			case "json":
+				j, _ := json.MarshalIndent(reports, "", "  ")
+				fmt.Println(string(j))
+			default:
+				fmt.Printf("%-12s %7s %14s %8s %12s\n", "CODEC", "FRAMES", "SIZE", "RATIO", "TIME")
+				for _, r := range reports {
+					fmt.Printf("%-12s %7d %14d %7.2fx %12s\n", r.Codec, r.Frames, r.Size, r.Ratio, r.Took.Round(time.Millisecond))
+				}
+			}
+			return nil
+		},
+	}
+	pf := cmd.PersistentFlags()
+	pf.StringSlice("codecs", []string{"jpeg-ls", "jpeg-li", "rle", "jpeg-2000"}, "Codecs to compare")
+	pf.Int("max-frames", 0, "Compare at most this many evenly spaced frames, 0 for all")
+	pf.StringP("format", "f", "text", "output format (text|json)")
+	cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+// sampleFrames returns the indexes of at most limit evenly spaced frames out
+// of n, or all of them when limit is not positive
+func sampleFrames(n, limit int) []int {
+	if limit <= 0 || limit > n {
+		limit = n
+	}
+	idx := make([]int, limit)
+	for i := range idx {
+		idx[i] = i * n / limit
+	}
+	return idx
+}
+
+// compareCodecs compresses the selected frames with each codec, starting
+// from the uncompressed size as the baseline
+func compareCodecs(ctx context.Context, df *decodedFrames, frames []int, codecs []dicos.Codec) ([]CodecReport, error) {
+	raw := int64(len(frames) * df.rows * df.cols * 2)
+	reports := []CodecReport{{Codec: "uncompressed", Frames: len(frames), Size: raw, Ratio: 1}}
+	for _, codec := range codecs {
+		r := CodecReport{Codec: codec.Name(), Frames: len(frames)}
+		for _, i := range frames {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			start := time.Now()
+			ratios, err := dicos.CompareCompressionRatio(df.rows, df.cols, df.frames[i], codec)
+			if err != nil {
+				return nil, fmt.Errorf("frame %d: %w", i, err)
+			}
+			r.Took += time.Since(start)
+			r.Size += int64(math.Round(float64(df.rows*df.cols*2) / ratios[codec.Name()]))
+		}
+		if r.Size > 0 {
+			r.Ratio = float64(raw) / float64(r.Size)
+		}
+		slog.DebugContext(ctx, "Codec compared", slog.String("codec", r.Codec), slog.Float64("ratio", r.Ratio), slog.Duration("took", r.Took))
+		reports = append(reports, r)
+	}
+	return reports, nil
+}
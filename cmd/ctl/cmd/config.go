@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix prefixes environment variables that override flag defaults,
+// e.g. DICOSCTL_LOG_LEVEL=DEBUG or DICOSCTL_PROFILE=lab
+const EnvPrefix = "DICOSCTL_"
+
+// DefaultConfigName is the config file looked up in the home directory
+const DefaultConfigName = ".dicosctl.yaml"
+
+// Config holds flag defaults loaded from ~/.dicosctl.yaml. Values are keyed by
+// flag name and applied only to flags not set on the command line.
+//
+//	profile: lab
+//	defaults:
+//	  log-level: INFO
+//	commands:
+//	  decode:
+//	    format: text
+//	profiles:
+//	  lab:
+//	    commands:
+//	      decode:
+//	        uri: https://lab-scanner.local/latest.dcs
+type Config struct {
+	Profile  string `yaml:"profile"` // profile used when --profile is not given
+	Values   `yaml:",inline"`
+	Profiles map[string]Values `yaml:"profiles"`
+}
+
+// Values are flag defaults for every command and per command name
+type Values struct {
+	Defaults map[string]string            `yaml:"defaults"`
+	Commands map[string]map[string]string `yaml:"commands"`
+}
+
+// lookup returns the value for a flag of the named command, preferring the
+// command section over the shared defaults
+func (v Values) lookup(command, flag string) (string, bool) {
+	if val, ok := v.Commands[command][flag]; ok {
+		return val, true
+	}
+	val, ok := v.Defaults[flag]
+	return val, ok
+}
+
+// DefaultConfigPath returns ~/.dicosctl.yaml, or "" if there is no home directory
+func DefaultConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, DefaultConfigName)
+}
+
+// LoadConfig reads a config file. A missing file yields an empty config.
+func LoadConfig(path string) (*Config, error) {
+	cfg := &Config{}
+	if path == "" {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// ProfileNames returns the configured profile names in order
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Apply sets every flag of cmd that was not given on the command line, in
+// order of precedence: environment, selected profile, then config defaults.
+func (c *Config) Apply(cmd *cobra.Command, profile string) error {
+	if profile == "" {
+		profile = c.Profile
+	}
+	var pv Values
+	if profile != "" {
+		p, ok := c.Profiles[profile]
+		if !ok {
+			return fmt.Errorf("unknown profile %q", profile)
+		}
+		pv = p
+	}
+
+	var errs []error
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if f.Changed {
+			return
+		}
+		val, ok := os.LookupEnv(envName(f.Name))
+		if !ok {
+			val, ok = pv.lookup(cmd.Name(), f.Name)
+		}
+		if !ok {
+			val, ok = c.lookup(cmd.Name(), f.Name)
+		}
+		if !ok {
+			return
+		}
+		if err := cmd.Flags().Set(f.Name, val); err != nil {
+			errs = append(errs, fmt.Errorf("flag --%s: %w", f.Name, err))
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// envName maps a flag name to its environment override, e.g. log-level -> DICOSCTL_LOG_LEVEL
+func envName(flag string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
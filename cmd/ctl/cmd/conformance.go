@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+	"github.com/spf13/cobra"
+)
+
+// NewConformanceCmd creates the conformance cobra command
+func NewConformanceCmd(ctx context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "conformance",
+		Short: "Generate a conformance statement skeleton for the library",
+		Long:  "Builds a sample of every supported IOD and writes Markdown tables of the transfer syntaxes and SOP classes supported and of the elements written per IOD and module, as the starting point of a product's DICOM/DICOS conformance statement.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cs, err := dicos.NewConformanceStatement()
+			if err != nil {
+				return err
+			}
+			var w io.Writer = os.Stdout
+			out, _ := cmd.Flags().GetString("out")
+			if out != "" {
+				f, err := os.Create(out)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				w = f
+			}
+			if err := cs.WriteMarkdown(w); err != nil {
+				return err
+			}
+			if out != "" {
+				slog.InfoContext(ctx, "Conformance statement written", slog.String("out", out), slog.Int("iods", len(cs.IODs)))
+			}
+			return nil
+		},
+	}
+	cmd.PersistentFlags().StringP("out", "o", "", "Markdown file to write (default stdout)")
+	return cmd
+}
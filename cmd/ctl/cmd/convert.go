@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/jpfielding/dicos.go/pkg/dicos/transfer"
+	"github.com/jpfielding/dicos.go/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+// NewConvertCmd creates the convert cobra command
+func NewConvertCmd(ctx context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "convert <in> <out>",
+		Short: "Convert a DICOS file to another codec or VR encoding",
+		Long:  "Re-encodes the pixel data of a DICOS file with --codec (jpegls, jpeg2k, rle, jpeg-li, the lossy jpeg-baseline or jpeg-extended, or none for uncompressed), optionally switches between explicit and implicit VR with --vr, and writes a new file. Reports the sizes before and after; --verify reads the output back and checks that elements and decoded pixels match the input.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := logging.AppendCtx(ctx, slog.String("file", args[0]))
+			flags := cmd.Flags()
+			codecName, _ := flags.GetString("codec")
+			vr, _ := flags.GetString("vr")
+			verify, _ := flags.GetBool("verify")
+
+			ds, err := dicos.ReadFileContext(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			target, err := convertTarget(ds.TransferSyntax(), codecName, vr)
+			if err != nil {
+				return err
+			}
+			out, err := convertDataset(ctx, ds, target)
+			if err != nil {
+				return err
+			}
+			if _, err := dicos.WriteFile(args[1], out); err != nil {
+				os.Remove(args[1]) // leave no partial file behind
+				return err
+			}
+
+			before, err := os.Stat(args[0])
+			if err != nil {
+				return err
+			}
+			after, err := os.Stat(args[1])
+			if err != nil {
+				return err
+			}
+			slog.InfoContext(ctx, "Converted",
+				slog.String("out", args[1]),
+				slog.String("from", ds.TransferSyntax().Name()),
+				slog.String("to", target.Name()),
+				slog.Int64("before", before.Size()),
+				slog.Int64("after", after.Size()),
+				slog.String("ratio", fmt.Sprintf("%.2f", float64(before.Size())/float64(max(after.Size(), 1)))))
+
+			if !verify {
+				return nil
+			}
+			if target.IsLossy() {
+				return fmt.Errorf("cannot verify %s, it is lossy", target.Name())
+			}
+			written, err := dicos.ReadFileContext(ctx, args[1])
+			if err != nil {
+				return err
+			}
+			diff, err := dicos.Compare(ds, written, dicos.CompareOptions{
+				Ignore:         []tag.Tag{tag.LossyImageCompression},
+				IgnoreFileMeta: true,
+				DecodePixels:   true,
+			})
+			if err != nil {
+				return err
+			}
+			if !diff.Equal() {
+				fmt.Print(diff)
+				return fmt.Errorf("round trip of %s changed %d elements or the pixel data", args[1], len(diff.Elements))
+			}
+			slog.InfoContext(ctx, "Verified lossless round trip", slog.String("out", args[1]))
+			return nil
+		},
+	}
+	pf := cmd.PersistentFlags()
+	pf.String("codec", "", "Target codec: jpegls, jpeg2k, rle, jpeg-li, jpeg-baseline, jpeg-extended or none; empty keeps the input encoding")
+	pf.String("vr", "", "Target VR encoding: explicit or implicit; empty keeps the input encoding")
+	pf.Bool("verify", false, "Read the output back and check it matches the input")
+	cmd.RegisterFlagCompletionFunc("codec", cobra.FixedCompletions([]string{"jpegls", "jpeg2k", "rle", "jpeg-li", "jpeg-baseline", "jpeg-extended", "none"}, cobra.ShellCompDirectiveNoFileComp))
+	cmd.RegisterFlagCompletionFunc("vr", cobra.FixedCompletions([]string{"explicit", "implicit"}, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+// convertTarget picks the transfer syntax written for the --codec and --vr
+// flags, starting from the transfer syntax of the input
+func convertTarget(from transfer.Syntax, codecName, vr string) (transfer.Syntax, error) {
+	target := from
+	switch codecName {
+	case "":
+	case "none":
+		if from != transfer.ImplicitVRLittleEndian {
+			target = transfer.ExplicitVRLittleEndian
+		}
+	default:
+		if codecName == "jpeg2k" {
+			codecName = "jpeg-2000"
+		}
+		codec := dicos.CodecByName(codecName)
+		if codec == nil {
+			return "", fmt.Errorf("unknown codec %q", codecName)
+		}
+		target = transfer.Syntax(codec.TransferSyntaxUID())
+	}
+
+	switch vr {
+	case "":
+	case "implicit":
+		if target.IsEncapsulated() {
+			return "", fmt.Errorf("implicit VR needs uncompressed pixel data, use --codec none")
+		}
+		target = transfer.ImplicitVRLittleEndian
+	case "explicit":
+		if target == transfer.ImplicitVRLittleEndian {
+			target = transfer.ExplicitVRLittleEndian
+		}
+	default:
+		return "", fmt.Errorf("unknown VR encoding %q, want explicit or implicit", vr)
+	}
+	return target, nil
+}
+
+// convertDataset re-encodes ds in target; datasets without pixel data, such
+// as a TDR, only change their TransferSyntaxUID
+func convertDataset(ctx context.Context, ds *dicos.Dataset, target transfer.Syntax) (*dicos.Dataset, error) {
+	if _, ok := ds.FindElement(tag.PixelData.Group, tag.PixelData.Element); ok {
+		return dicos.TranscodeContext(ctx, ds, target)
+	}
+	if target.IsEncapsulated() {
+		return nil, fmt.Errorf("no pixel data to compress with %s", target.Name())
+	}
+	out := dicos.CloneDataset(ds)
+	elem, ok := out.FindElement(tag.TransferSyntaxUID.Group, tag.TransferSyntaxUID.Element)
+	if !ok {
+		return nil, fmt.Errorf("missing transfer syntax")
+	}
+	elem.Value = string(target)
+	return out, nil
+}
@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/spf13/cobra"
+)
+
+// CheckResult is the outcome of one doctor check
+type CheckResult struct {
+	Group  string        `json:"group"`
+	Name   string        `json:"name"`
+	OK     bool          `json:"ok"`
+	Detail string        `json:"detail,omitempty"`
+	Took   time.Duration `json:"took"`
+}
+
+// NewDoctorCmd creates the doctor cobra command
+func NewDoctorCmd(ctx context.Context, gitsha string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Run an internal self-check",
+		Long:  "Round-trips every codec and IOD through encode, write, read and decode, checks that validation rules load, and reports environment info for support triage.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			results := runDoctor(ctx, gitsha)
+
+			failed := 0
+			for _, r := range results {
+				if !r.OK {
+					failed++
+				}
+			}
+			switch format, _ := cmd.Flags().GetString("format"); format {
+			case "json":
+				j, _ := json.MarshalIndent(results, "", "  ")
+				fmt.Println(string(j))
+			default:
+				for _, r := range results {
+					status := "PASS"
+					if !r.OK {
+						status = "FAIL"
+					}
+					fmt.Printf("%s  %-12s %-22s %s\n", status, r.Group, r.Name, r.Detail)
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d checks failed", failed, len(results))
+			}
+			return nil
+		},
+	}
+	pf := cmd.PersistentFlags()
+	pf.StringP("format", "f", "text", "output format (text|json)")
+	cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+// runDoctor runs every check, recovering from panics so one broken codec does
+// not hide the rest of the report
+func runDoctor(ctx context.Context, gitsha string) []CheckResult {
+	var results []CheckResult
+	check := func(group, name string, fn func() (string, error)) {
+		start := time.Now()
+		r := CheckResult{Group: group, Name: name}
+		func() {
+			defer func() {
+				if p := recover(); p != nil {
+					r.Detail = fmt.Sprintf("panic: %v", p)
+				}
+			}()
+			detail, err := fn()
+			r.OK, r.Detail = err == nil, detail
+			if err != nil {
+				r.Detail = err.Error()
+			}
+		}()
+		r.Took = time.Since(start)
+		results = append(results, r)
+	}
+
+	check("environment", "build", func() (string, error) {
+		version := "unknown"
+		if bi, ok := debug.ReadBuildInfo(); ok {
+			version = bi.Main.Version
+		}
+		return fmt.Sprintf("git=%s module=%s dicos=%s %s %s/%s cpus=%d", gitsha, version, dicos.Version(),
+			runtime.Version(), runtime.GOOS, runtime.GOARCH, runtime.NumCPU()), nil
+	})
+	check("environment", "temp dir", func() (string, error) {
+		f, err := os.CreateTemp("", "dicosctl-doctor-*.dcs")
+		if err != nil {
+			return "", err
+		}
+		defer os.Remove(f.Name())
+		defer f.Close()
+		return os.TempDir() + " is writable", nil
+	})
+
+	for _, name := range []string{"", "rle", "jpeg-ls", "jpeg-li", "jpeg-2000"} {
+		label := name
+		if label == "" {
+			label = "uncompressed"
+		}
+		check("codec", label, func() (string, error) {
+			return doctorCodec(ctx, dicos.CodecByName(name))
+		})
+	}
+
+	for _, iod := range doctorIODs() {
+		check("iod", iod.name, func() (string, error) {
+			return doctorIOD(ctx, iod.build)
+		})
+	}
+
+	check("validation", "rules", func() (string, error) {
+		tables := map[string][]dicos.IODRequirement{
+			"CT":  dicos.CTImageRequirements,
+			"DX":  dicos.DXImageRequirements,
+			"TDR": dicos.TDRRequirements,
+			"QR":  dicos.QRRequirements,
+		}
+		total := 0
+		for name, reqs := range tables {
+			if len(reqs) == 0 {
+				return "", fmt.Errorf("no %s requirements loaded", name)
+			}
+			total += len(reqs)
+		}
+		return fmt.Sprintf("%d requirements across %d IODs", total, len(tables)), nil
+	})
+	return results
+}
+
+// doctorCodec encodes a gradient frame, writes and re-reads it and checks
+// that every pixel survives
+func doctorCodec(ctx context.Context, codec dicos.Codec) (string, error) {
+	const rows, cols = 64, 64
+	data := make([]uint16, rows*cols)
+	for i := range data {
+		data[i] = uint16((i%cols)*64 + (i/cols)*16)
+	}
+
+	ts := string(dicos.ExplicitVRLittleEndian)
+	if codec != nil {
+		ts = codec.TransferSyntaxUID()
+	}
+	ds, err := dicos.NewDataset(
+		dicos.WithFileMeta(dicos.DICOSCTImageStorageUID, dicos.NewUID(), ts),
+		dicos.WithElement(tag.Rows, uint16(rows)),
+		dicos.WithElement(tag.Columns, uint16(cols)),
+		dicos.WithElement(tag.BitsAllocated, uint16(16)),
+		dicos.WithPixelData(rows, cols, 16, data, codec),
+	)
+	if err != nil {
+		return "", fmt.Errorf("encode: %w", err)
+	}
+	var buf bytes.Buffer
+	if _, err := dicos.Write(&buf, ds); err != nil {
+		return "", fmt.Errorf("write: %w", err)
+	}
+	parsed, err := dicos.ReadBufferContext(ctx, buf.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("read: %w", err)
+	}
+	pd, err := parsed.GetPixelDataContext(ctx)
+	if err != nil {
+		return "", fmt.Errorf("pixel data: %w", err)
+	}
+	decoded, err := dicos.DecodeFrameDataContext(ctx, pd, 0, rows, cols, dicos.GetTransferSyntax(parsed))
+	if err != nil {
+		return "", fmt.Errorf("decode: %w", err)
+	}
+	for i := range data {
+		if decoded[i] != data[i] {
+			return "", fmt.Errorf("pixel %d: got %d, want %d", i, decoded[i], data[i])
+		}
+	}
+	return fmt.Sprintf("%s round-trip, %d bytes", ts, buf.Len()), nil
+}
+
+type doctorIODCase struct {
+	name  string
+	build func() (*dicos.Dataset, error)
+}
+
+// doctorIODs returns a minimal sample of each IOD the library writes
+func doctorIODs() []doctorIODCase {
+	pixels := make([]uint16, 16*16)
+	return []doctorIODCase{
+		{"CT", func() (*dicos.Dataset, error) {
+			ct := dicos.NewCTImage()
+			ct.Rows, ct.Columns = 16, 16
+			ct.SetPixelData(16, 16, pixels)
+			return ct.GetDataset()
+		}},
+		{"DX", func() (*dicos.Dataset, error) {
+			dx := dicos.NewDXImage()
+			dx.SetPixelData(16, 16, pixels)
+			return dx.GetDataset()
+		}},
+		{"AIT2D", func() (*dicos.Dataset, error) {
+			ait := dicos.NewAIT2DImage()
+			ait.SetPixelData(16, 16, pixels)
+			return ait.GetDataset()
+		}},
+		{"AIT3D", func() (*dicos.Dataset, error) {
+			ait := dicos.NewAIT3DImage()
+			ait.SetPixelData(16, 16, 1, pixels)
+			return ait.GetDataset()
+		}},
+		{"TDR", func() (*dicos.Dataset, error) {
+			tdr := dicos.NewThreatDetectionReport()
+			tdr.PTOs = append(tdr.PTOs, dicos.PotentialThreatObject{Label: "SELF-TEST"})
+			return tdr.GetDataset()
+		}},
+		{"QR", func() (*dicos.Dataset, error) {
+			qr := dicos.NewQRMeasurement()
+			qr.Acquisition.TransmitterFrequency = []float64{3.41}
+			return qr.GetDataset()
+		}},
+	}
+}
+
+// doctorIOD builds, writes and re-reads a sample and checks that its elements
+// and SOP class survive
+func doctorIOD(ctx context.Context, build func() (*dicos.Dataset, error)) (string, error) {
+	ds, err := build()
+	if err != nil {
+		return "", fmt.Errorf("build: %w", err)
+	}
+	var buf bytes.Buffer
+	if _, err := dicos.Write(&buf, ds); err != nil {
+		return "", fmt.Errorf("write: %w", err)
+	}
+	parsed, err := dicos.ReadBufferContext(ctx, buf.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("read: %w", err)
+	}
+	if len(parsed.Elements) != len(ds.Elements) {
+		return "", fmt.Errorf("read %d elements, wrote %d", len(parsed.Elements), len(ds.Elements))
+	}
+	want, got := sopClassUID(ds), sopClassUID(parsed)
+	if want == "" || got != want {
+		return "", fmt.Errorf("SOP class %q, want %q", got, want)
+	}
+	return fmt.Sprintf("%s, %d elements", got, len(parsed.Elements)), nil
+}
+
+func sopClassUID(ds *dicos.Dataset) string {
+	if elem, ok := ds.FindElement(tag.SOPClassUID.Group, tag.SOPClassUID.Element); ok {
+		uid, _ := elem.GetString()
+		return uid
+	}
+	return ""
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/jpfielding/dicos.go/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+// NewDumpCmd creates the dump cobra command
+func NewDumpCmd(ctx context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dump <file>",
+		Short: "Print every element of a DICOS file as a tag tree",
+		Long:  "Walks all elements of a DICOS file, including the items of nested sequences, and prints the group/element, dictionary keyword (or private creator), VR, value length and a truncated value of each. --filter-tag limits the output to the given tags and the sequences holding them.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := logging.AppendCtx(ctx, slog.String("file", args[0]))
+			flags := cmd.Flags()
+			skipPixels, _ := flags.GetBool("skip-pixel-data")
+			filter, _ := flags.GetStringSlice("filter-tag")
+			var tags []tag.Tag
+			for _, s := range filter {
+				t, err := parseTagFlag(s)
+				if err != nil {
+					return err
+				}
+				tags = append(tags, t)
+			}
+
+			ds, err := dicos.ReadFileWithOptions(ctx, args[0], dicos.ParseOptions{SkipPixelData: skipPixels})
+			if err != nil {
+				return err
+			}
+			entries := dicos.Dump(ds, tags...)
+			if asJSON, _ := flags.GetBool("json"); asJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(entries)
+			}
+			return dicos.WriteDump(os.Stdout, entries)
+		},
+	}
+	pf := cmd.PersistentFlags()
+	pf.Bool("json", false, "Print the tree as JSON")
+	pf.StringSlice("filter-tag", nil, "Only print these tags, as keywords or GGGGEEEE")
+	pf.Bool("skip-pixel-data", false, "Do not read the pixel data")
+	return cmd
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+	"github.com/jpfielding/dicos.go/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+// NewExportCmd creates the export cobra command
+func NewExportCmd(ctx context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export <file.dcs> <out-dir>",
+		Short: "Export frames of a DICOS file as images",
+		Long:  "Writes each frame (or one with --frame) as <name>_<frame>.png, .tiff or .jp2. By default the format follows the source: 8-bit PNG for data that fits in 8 bits, 16-bit PNG for unsigned high bit-depth data and float TIFF for signed or rescaled data such as CT HU, so nothing is silently truncated. --format forces a mode; png8 windows high bit-depth data, png16 is windowed when --window or --level is given, and jp2 writes the stored values as lossless JPEG 2000 in a JP2 file.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags := cmd.Flags()
+			name, _ := flags.GetString("format")
+			format, err := dicos.ExportFormatByName(name)
+			if err != nil {
+				return err
+			}
+			frame, _ := flags.GetInt("frame")
+			opts := dicos.ExportOptions{Format: format}
+
+			ctx := logging.AppendCtx(ctx, slog.String("file", args[0]))
+			ds, err := dicos.ReadFileContext(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			if flags.Changed("window") || flags.Changed("level") {
+				win := dicos.WindowFromDataset(ds)
+				if flags.Changed("window") {
+					s, _ := flags.GetString("window")
+					if err := parseWindowFlag(s, &win); err != nil {
+						return err
+					}
+				}
+				if flags.Changed("level") {
+					win.Center, _ = flags.GetFloat64("level")
+				}
+				opts.Window = &win
+			}
+			if err := os.MkdirAll(args[1], 0o755); err != nil {
+				return err
+			}
+
+			frames := []int{frame}
+			if all, _ := flags.GetBool("all"); all || frame < 0 {
+				frames = frames[:0]
+				for i := range max(ds.NumberOfFrames(), 1) {
+					frames = append(frames, i)
+				}
+			}
+			base := strings.TrimSuffix(filepath.Base(args[0]), filepath.Ext(args[0]))
+			for _, i := range frames {
+				var buf bytes.Buffer
+				written, err := dicos.ExportFrame(ctx, ds, i, &buf, opts)
+				if err != nil {
+					return err
+				}
+				out := filepath.Join(args[1], fmt.Sprintf("%s_%04d%s", base, i, written.Ext()))
+				if err := os.WriteFile(out, buf.Bytes(), 0o644); err != nil {
+					return err
+				}
+				slog.DebugContext(ctx, "Frame exported", slog.Int("frame", i), slog.String("format", string(written)), slog.String("out", out))
+			}
+			slog.InfoContext(ctx, "Export complete", slog.Int("frames", len(frames)), slog.String("out", args[1]))
+			return nil
+		},
+	}
+	pf := cmd.PersistentFlags()
+	pf.String("format", "auto", "Output format: auto, png16, png8, tiff or jp2")
+	pf.Int("frame", -1, "Export only this frame index")
+	pf.Bool("all", false, "Export every frame (the default without --frame)")
+	pf.String("window", "", "Window for png8 and png16 as center,width or a width alone (defaults to the file's Window Center and Width)")
+	pf.Float64("level", 0, "Window level/center for png8 and png16 (defaults to the file's Window Center)")
+	cmd.MarkFlagsMutuallyExclusive("frame", "all")
+	cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"auto", "png16", "png8", "tiff", "jp2"}, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+// parseWindowFlag sets the window from center,width, or only its width when
+// a single value is given
+func parseWindowFlag(s string, win *dicos.Window) error {
+	center, width, both := strings.Cut(s, ",")
+	if !both {
+		center, width = "", center
+	}
+	w, err := strconv.ParseFloat(strings.TrimSpace(width), 64)
+	if err != nil || w <= 0 {
+		return fmt.Errorf("invalid window %q: want center,width or a positive width", s)
+	}
+	win.Width = w
+	if both {
+		c, err := strconv.ParseFloat(strings.TrimSpace(center), 64)
+		if err != nil {
+			return fmt.Errorf("invalid window %q: want center,width or a positive width", s)
+		}
+		win.Center = c
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// DefaultDrainTimeout bounds how long in-flight work may run after a shutdown signal
+const DefaultDrainTimeout = 30 * time.Second
+
+// Lifecycle coordinates shutdown for service-style subcommands (serve, watch, scp).
+//
+// When the signal context is cancelled the lifecycle stops admitting new work,
+// waits up to the drain timeout for in-flight work to finish, cancels whatever
+// is still running, and then runs the shutdown hooks (flush indexes, close
+// listeners) in reverse registration order.
+//
+//	lc := NewLifecycle(ctx, drainTimeout(cmd))
+//	lc.OnShutdown("index", idx.Flush)
+//	for conn := range accepted {
+//		lc.Go(func(ctx context.Context) { handle(ctx, conn) })
+//	}
+//	return lc.Wait()
+type Lifecycle struct {
+	signal  context.Context    // cancelled when shutdown begins
+	work    context.Context    // cancelled when the drain timeout expires
+	stop    context.CancelFunc // cancels work
+	timeout time.Duration
+
+	mu       sync.Mutex
+	draining bool
+	inflight sync.WaitGroup
+	hooks    []shutdownHook
+}
+
+type shutdownHook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// NewLifecycle creates a lifecycle that begins draining when ctx is done
+func NewLifecycle(ctx context.Context, drainTimeout time.Duration) *Lifecycle {
+	// in-flight work outlives the signal, so it gets its own cancellation
+	work, stop := context.WithCancel(context.WithoutCancel(ctx))
+	return &Lifecycle{signal: ctx, work: work, stop: stop, timeout: drainTimeout}
+}
+
+// Done is closed when shutdown begins; accept loops select on it to stop taking new work
+func (l *Lifecycle) Done() <-chan struct{} {
+	return l.signal.Done()
+}
+
+// Begin admits one unit of work and returns its context and a func to call when
+// it completes. It returns false once draining has started.
+func (l *Lifecycle) Begin() (context.Context, func(), bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.draining || l.signal.Err() != nil {
+		return nil, nil, false
+	}
+	l.inflight.Add(1)
+	return l.work, l.inflight.Done, true
+}
+
+// Go runs fn as tracked work, returning false if draining has started
+func (l *Lifecycle) Go(fn func(ctx context.Context)) bool {
+	ctx, done, ok := l.Begin()
+	if !ok {
+		return false
+	}
+	go func() {
+		defer done()
+		fn(ctx)
+	}()
+	return true
+}
+
+// OnShutdown registers a hook that runs after in-flight work has drained
+func (l *Lifecycle) OnShutdown(name string, fn func(ctx context.Context) error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, shutdownHook{name: name, fn: fn})
+}
+
+// Wait blocks until shutdown begins, then drains and runs the shutdown hooks
+func (l *Lifecycle) Wait() error {
+	<-l.signal.Done()
+	return l.Shutdown()
+}
+
+// Shutdown stops admitting work, drains in-flight work and runs the shutdown
+// hooks. It may be called directly when a service exits on its own.
+func (l *Lifecycle) Shutdown() error {
+	l.mu.Lock()
+	l.draining = true
+	hooks := l.hooks
+	l.mu.Unlock()
+
+	ctx := l.work
+	slog.InfoContext(ctx, "Draining in-flight work", slog.Duration("timeout", l.timeout))
+
+	drained := make(chan struct{})
+	go func() {
+		l.inflight.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+	case <-time.After(l.timeout):
+		slog.WarnContext(ctx, "Drain timed out, cancelling in-flight work")
+		l.stop()
+		<-drained
+	}
+	l.stop()
+
+	hookCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), l.timeout)
+	defer cancel()
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		if err := h.fn(hookCtx); err != nil {
+			slog.ErrorContext(ctx, "Shutdown hook failed", slog.String("hook", h.name), slog.Any("error", err))
+			errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+		}
+	}
+	slog.InfoContext(ctx, "Shutdown complete")
+	return errors.Join(errs...)
+}
+
+// drainTimeout returns the --drain-timeout flag
+func drainTimeout(cmd *cobra.Command) time.Duration {
+	d, err := cmd.Flags().GetDuration("drain-timeout")
+	if err != nil || d <= 0 {
+		return DefaultDrainTimeout
+	}
+	return d
+}
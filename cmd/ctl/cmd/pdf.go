@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+	"github.com/jpfielding/dicos.go/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+// NewPDFCmd creates the pdf cobra command
+func NewPDFCmd(ctx context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pdf <in> <out>",
+		Short: "Wrap a PDF report as an Encapsulated PDF instance, or extract it",
+		Long:  "A .pdf input is stored as an Encapsulated PDF instance; with --study it joins the patient and study of that DICOS file and references it as its source, so the report travels with the scan. Any other input is read as an Encapsulated PDF and its document written out.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := logging.AppendCtx(ctx, slog.String("file", args[0]))
+			if !strings.EqualFold(filepath.Ext(args[0]), ".pdf") {
+				ds, err := dicos.ReadFileContext(ctx, args[0])
+				if err != nil {
+					return err
+				}
+				doc, err := dicos.EncapsulatedPDFFromDataset(ds)
+				if err != nil {
+					return err
+				}
+				if err := os.WriteFile(args[1], doc.Document, 0o644); err != nil {
+					return err
+				}
+				slog.InfoContext(ctx, "PDF extracted", slog.String("out", args[1]), slog.String("title", doc.DocumentTitle))
+				return nil
+			}
+
+			flags := cmd.Flags()
+			doc := dicos.NewEncapsulatedPDF()
+			doc.DocumentTitle, _ = flags.GetString("title")
+			doc.BurnedInAnnotation, _ = flags.GetBool("identifying")
+			var err error
+			if doc.Document, err = os.ReadFile(args[0]); err != nil {
+				return err
+			}
+			if study, _ := flags.GetString("study"); study != "" {
+				ds, err := dicos.ReadFileWithOptions(ctx, study, dicos.ParseOptions{SkipPixelData: true})
+				if err != nil {
+					return err
+				}
+				if err := doc.AttachTo(ds); err != nil {
+					return err
+				}
+			}
+			if _, err := doc.Write(args[1]); err != nil {
+				return err
+			}
+			slog.InfoContext(ctx, "Encapsulated PDF written", slog.String("out", args[1]), slog.String("study", doc.Study.StudyInstanceUID))
+			return nil
+		},
+	}
+	pf := cmd.PersistentFlags()
+	pf.String("study", "", "DICOS file whose patient and study the report joins")
+	pf.String("title", "", "Document title")
+	pf.Bool("identifying", false, "The report identifies the subject (sets Burned In Annotation)")
+	return cmd
+}
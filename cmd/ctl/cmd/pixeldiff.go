@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"image/png"
+	"log/slog"
+	"os"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+	"github.com/jpfielding/dicos.go/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+// NewPixelDiffCmd creates the pixeldiff cobra command
+func NewPixelDiffCmd(ctx context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pixeldiff <a.dcs> <b.dcs>",
+		Short: "Compare decoded pixels of two DICOS files",
+		Long:  "Decodes every frame of two DICOS files and reports max/mean absolute difference and differing pixel counts, to verify that transcodes and codec changes are lossless. Optionally writes a heatmap PNG of the most different frame.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tolerance, _ := cmd.Flags().GetInt("tolerance")
+			heatmap, _ := cmd.Flags().GetString("heatmap")
+			ctx := logging.AppendCtx(ctx, slog.String("a", args[0]), slog.String("b", args[1]))
+			return runPixelDiff(ctx, args[0], args[1], tolerance, heatmap)
+		},
+	}
+	pf := cmd.PersistentFlags()
+	pf.Int("tolerance", 0, "Absolute difference allowed before a pixel counts as differing")
+	pf.String("heatmap", "", "Write a PNG heatmap of the most different frame to this path")
+	cmd.MarkPersistentFlagFilename("heatmap", "png")
+	return cmd
+}
+
+// decodedFrames holds every decoded frame of a file
+type decodedFrames struct {
+	rows, cols int
+	frames     [][]uint16
+}
+
+func decodeAllFrames(ctx context.Context, path string) (*decodedFrames, error) {
+	ds, err := dicos.ReadFileContext(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	pd, err := ds.GetPixelDataContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	df := &decodedFrames{rows: ds.Rows(), cols: ds.Columns()}
+	ts := dicos.GetTransferSyntax(ds)
+	for i := range pd.Frames {
+		data, err := dicos.DecodeFrameDataContext(ctx, pd, i, df.rows, df.cols, ts)
+		if err != nil {
+			return nil, fmt.Errorf("frame %d: %w", i, err)
+		}
+		df.frames = append(df.frames, data)
+	}
+	return df, nil
+}
+
+func runPixelDiff(ctx context.Context, pathA, pathB string, tolerance int, heatmapPath string) error {
+	a, err := decodeAllFrames(ctx, pathA)
+	if err != nil {
+		return fmt.Errorf("%s: %w", pathA, err)
+	}
+	b, err := decodeAllFrames(ctx, pathB)
+	if err != nil {
+		return fmt.Errorf("%s: %w", pathB, err)
+	}
+	if a.rows != b.rows || a.cols != b.cols {
+		return fmt.Errorf("dimensions differ: %dx%d vs %dx%d", a.rows, a.cols, b.rows, b.cols)
+	}
+	if len(a.frames) != len(b.frames) {
+		return fmt.Errorf("frame counts differ: %d vs %d", len(a.frames), len(b.frames))
+	}
+
+	var total dicos.FrameDiff
+	var meanSum float64
+	worst, worstMax := -1, -1
+	for i := range a.frames {
+		d, err := dicos.CompareFrames(a.frames[i], b.frames[i], tolerance)
+		if err != nil {
+			return fmt.Errorf("frame %d: %w", i, err)
+		}
+		if !d.Lossless() {
+			fmt.Printf("frame %d: %s\n", i, d)
+		}
+		if d.MaxAbs > worstMax {
+			worst, worstMax = i, d.MaxAbs
+		}
+		total.Pixels += d.Pixels
+		total.Differing += d.Differing
+		total.MaxAbs = max(total.MaxAbs, d.MaxAbs)
+		meanSum += d.MeanAbs * float64(d.Pixels)
+	}
+	if total.Pixels > 0 {
+		total.MeanAbs = meanSum / float64(total.Pixels)
+	}
+	fmt.Printf("frames=%d %s lossless=%v\n", len(a.frames), total, total.Lossless())
+
+	if heatmapPath != "" && worst >= 0 {
+		img, err := dicos.DiffHeatmap(a.frames[worst], b.frames[worst], a.rows, a.cols)
+		if err != nil {
+			return err
+		}
+		f, err := os.Create(heatmapPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := png.Encode(f, img); err != nil {
+			return err
+		}
+		fmt.Printf("heatmap of frame %d written to %s\n", worst, heatmapPath)
+	}
+
+	if total.Differing > 0 {
+		return fmt.Errorf("%d pixels differ by more than %d", total.Differing, tolerance)
+	}
+	return nil
+}
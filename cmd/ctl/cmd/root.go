@@ -22,7 +22,10 @@ func NewRoot(ctx context.Context, gitsha string) *cobra.Command {
 		Use:   "dicosctl",
 		Short: "a CLI to manage clearscan configuration/validation",
 		Long:  "the long story",
-		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := applyConfig(cmd); err != nil {
+				return err
+			}
 			logLevel, _ := cmd.Flags().GetString("log-level")
 
 			// Parse log level
@@ -35,7 +38,14 @@ func NewRoot(ctx context.Context, gitsha string) *cobra.Command {
 			if err := level.UnmarshalText([]byte(strings.ToUpper(logLevel))); err != nil {
 				slog.WarnContext(ctx, "Invalid log level, defaulting to INFO", "level", logLevel, "error", err)
 			}
-
+			if root, _ := cmd.Flags().GetString("uid-root"); root != "" {
+				f, err := dicos.NewUIDFactory(root)
+				if err != nil {
+					return err
+				}
+				dicos.SetUIDFactory(f)
+			}
+			return nil
 		},
 		Run: func(cmd *cobra.Command, args []string) {
 			printCommandTree(cmd, 0)
@@ -45,9 +55,36 @@ func NewRoot(ctx context.Context, gitsha string) *cobra.Command {
 		NewVersionCmd(ctx, gitsha),
 		NewDecodeCmd(ctx),
 		NewAnalyzeCmd(ctx),
+		NewDoctorCmd(ctx, gitsha),
+		NewPixelDiffCmd(ctx),
+		NewCompareCodecsCmd(ctx),
+		NewTagsCmd(ctx),
+		NewAnimateCmd(ctx),
+		NewExportCmd(ctx),
+		NewAnonymizeCmd(ctx),
+		NewSCPCmd(ctx),
+		NewPDFCmd(ctx),
+		NewTranscodeCmd(ctx),
+		NewConformanceCmd(ctx),
+		NewCompareCmd(ctx),
+		NewDumpCmd(ctx),
+		NewConvertCmd(ctx),
+		NewValidateCmd(ctx),
 	)
 	pf := cmd.PersistentFlags()
 	pf.String("log-level", "INFO", "Log level (DEBUG, INFO, WARN, ERROR)")
+	pf.String("config", DefaultConfigPath(), "Config file with flag defaults and profiles (env "+envName("config")+")")
+	pf.String("profile", "", "Config profile to apply (env "+envName("profile")+")")
+	pf.Duration("drain-timeout", DefaultDrainTimeout, "How long services wait for in-flight work on shutdown")
+	pf.String("uid-root", "", "Organization UID root for generated UIDs, "+dicos.DefaultUIDRoot+" when empty")
+	cmd.RegisterFlagCompletionFunc("log-level", cobra.FixedCompletions([]string{"DEBUG", "INFO", "WARN", "ERROR"}, cobra.ShellCompDirectiveNoFileComp))
+	cmd.RegisterFlagCompletionFunc("profile", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		cfg, err := LoadConfig(configPath(cmd))
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		return cfg.ProfileNames(), cobra.ShellCompDirectiveNoFileComp
+	})
 	return cmd
 }
 
@@ -113,7 +150,8 @@ func NewDecodeCmd(ctx context.Context) *cobra.Command {
 				in = f
 				defer f.Close()
 			}
-			dataset, _ := dicos.Parse(in)
+			ctx := logging.AppendCtx(ctx, slog.String("uri", dcsPath))
+			dataset, _ := dicos.ParseContext(ctx, in)
 			switch uioType, _ := cmd.Flags().GetString("format"); uioType {
 			case "text": // Dataset will nicely print the DICOM dataset data out of the box.
 				fmt.Println(dataset)
@@ -127,5 +165,28 @@ func NewDecodeCmd(ctx context.Context) *cobra.Command {
 	pf := cmd.PersistentFlags()
 	pf.StringP("uri", "u", "", "DICOS URI to fetch certificates from")
 	pf.StringP("format", "f", "json", "output format (text|json)")
+	cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp))
 	return cmd
 }
+
+// applyConfig loads the config file and fills in flags not set on the command line
+func applyConfig(cmd *cobra.Command) error {
+	cfg, err := LoadConfig(configPath(cmd))
+	if err != nil {
+		return err
+	}
+	profile, _ := cmd.Flags().GetString("profile")
+	if !cmd.Flags().Changed("profile") {
+		profile = os.Getenv(envName("profile"))
+	}
+	return cfg.Apply(cmd, profile)
+}
+
+// configPath returns the --config flag, or its environment override when not given
+func configPath(cmd *cobra.Command) string {
+	path, _ := cmd.Flags().GetString("config")
+	if env, ok := os.LookupEnv(envName("config")); ok && !cmd.Flags().Changed("config") {
+		path = env
+	}
+	return path
+}
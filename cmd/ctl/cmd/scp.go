@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+	dicosnet "github.com/jpfielding/dicos.go/pkg/dicos/net"
+	"github.com/spf13/cobra"
+)
+
+// NewSCPCmd creates the scp cobra command
+func NewSCPCmd(ctx context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scp --out <dir>",
+		Short: "Receive DICOS files over DICOM C-STORE",
+		Long:  "Runs a C-STORE SCP that accepts associations for the DICOS storage SOP classes and Verification (C-ECHO), and writes every received dataset to <out>/<SOPInstanceUID>.dcs. On SIGINT/SIGTERM it stops taking new datasets and waits up to --drain-timeout for stores in progress.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags := cmd.Flags()
+			addr, _ := flags.GetString("addr")
+			ae, _ := flags.GetString("ae")
+			out, _ := flags.GetString("out")
+			if out == "" {
+				return fmt.Errorf("--out is required")
+			}
+			if err := os.MkdirAll(out, 0o755); err != nil {
+				return err
+			}
+
+			tlsConfig, err := scpTLSConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			lc := NewLifecycle(ctx, drainTimeout(cmd))
+			srv := &dicosnet.Server{
+				AETitle:   ae,
+				TLSConfig: tlsConfig,
+				Handler: func(_ context.Context, req *dicosnet.StoreRequest) error {
+					_, done, ok := lc.Begin()
+					if !ok {
+						return &dicosnet.StatusError{Status: dicosnet.StatusOutOfResources}
+					}
+					defer done()
+					path := filepath.Join(out, filepath.Base(req.SOPInstanceUID)+dicos.GetExtension())
+					if _, err := dicos.WriteFile(path, req.Dataset); err != nil {
+						return err
+					}
+					attrs := []any{
+						slog.String("path", path),
+						slog.String("calling", req.CallingAE),
+						slog.String("sop_class", req.SOPClassUID),
+					}
+					if len(req.PeerCertificates) > 0 {
+						attrs = append(attrs, slog.String("peer", req.PeerCertificates[0].Subject.String()))
+					}
+					slog.InfoContext(ctx, "Stored dataset", attrs...)
+					return nil
+				},
+			}
+			lc.OnShutdown("scp", srv.Shutdown)
+
+			served := make(chan error, 1)
+			go func() { served <- srv.ListenAndServe(addr) }()
+			select {
+			case err := <-served:
+				// failed to listen, or the listener broke
+				return errors.Join(err, lc.Shutdown())
+			case <-lc.Done():
+			}
+			return lc.Wait()
+		},
+	}
+	pf := cmd.PersistentFlags()
+	pf.String("addr", ":11112", "TCP address to listen on")
+	pf.String("ae", "DICOS_SCP", "Called AE title to accept, empty accepts any")
+	pf.StringP("out", "o", "", "Directory to write received datasets to")
+	pf.String("tls-cert", "", "PEM certificate chain to serve TLS with, plain TCP when empty")
+	pf.String("tls-key", "", "PEM private key of --tls-cert")
+	pf.String("tls-ca", "", "PEM CAs trusted to sign client certificates, the system pool when empty")
+	pf.String("tls-profile", string(dicosnet.TLSProfileBCP195), "BCP 195 TLS profile")
+	pf.Bool("tls-verify-client", false, "Require a client certificate signed by --tls-ca (mutual authentication)")
+	cmd.MarkPersistentFlagDirname("out")
+	cmd.MarkPersistentFlagFilename("tls-cert", "pem", "crt")
+	cmd.MarkPersistentFlagFilename("tls-key", "pem", "key")
+	cmd.MarkPersistentFlagFilename("tls-ca", "pem", "crt")
+	profiles := make([]string, len(dicosnet.TLSProfiles))
+	for i, p := range dicosnet.TLSProfiles {
+		profiles[i] = string(p)
+	}
+	cmd.RegisterFlagCompletionFunc("tls-profile", cobra.FixedCompletions(profiles, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+// scpTLSConfig builds the server TLS configuration from the --tls-* flags,
+// nil when --tls-cert is not set
+func scpTLSConfig(cmd *cobra.Command) (*tls.Config, error) {
+	flags := cmd.Flags()
+	opts := dicosnet.TLSOptions{}
+	opts.CertFile, _ = flags.GetString("tls-cert")
+	opts.KeyFile, _ = flags.GetString("tls-key")
+	opts.CAFile, _ = flags.GetString("tls-ca")
+	profile, _ := flags.GetString("tls-profile")
+	opts.Profile = dicosnet.TLSProfile(profile)
+	opts.VerifyClient, _ = flags.GetBool("tls-verify-client")
+	if opts.CertFile == "" {
+		if opts.KeyFile != "" || opts.CAFile != "" || opts.VerifyClient {
+			return nil, fmt.Errorf("--tls-key, --tls-ca and --tls-verify-client need --tls-cert")
+		}
+		return nil, nil
+	}
+	return opts.ServerConfig()
+}
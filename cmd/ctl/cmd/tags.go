@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+	"github.com/jpfielding/dicos.go/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+// NewTagsCmd creates the tags cobra command
+func NewTagsCmd(ctx context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tags <file|dir>...",
+		Short: "Inventory the tags used across DICOS files",
+		Long:  "Reads the header of every DICOS file given, without pixel data, and lists each standard and private tag found with the number of files holding it, its total occurrences including sequence items, the VRs seen and example values. Private tags are listed per private creator. Unknown tags are flagged, to plan dictionary additions and spot vendor private tags that need support.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags := cmd.Flags()
+			recursive, _ := flags.GetBool("recursive")
+			privateOnly, _ := flags.GetBool("private")
+			examples, _ := flags.GetInt("examples")
+
+			inputs, err := collectAnonymizeInputs(args, recursive)
+			if err != nil {
+				return err
+			}
+			inv := &dicos.TagInventory{MaxExamples: examples}
+			failed := 0
+			for _, in := range inputs {
+				ctx := logging.AppendCtx(ctx, slog.String("file", in.path))
+				ds, err := dicos.ReadFileWithOptions(ctx, in.path, dicos.ParseOptions{SkipPixelData: true})
+				if err != nil {
+					slog.WarnContext(ctx, "Skipping unreadable file", slog.Any("error", err))
+					failed++
+					continue
+				}
+				inv.Add(ds)
+			}
+			stats := inv.Stats()
+			if privateOnly {
+				stats = inv.Private()
+			}
+
+			switch format, _ := flags.GetString("format"); format {
+			case "json":
+				j, _ := json.MarshalIndent(stats, "", "  ")
+				fmt.Println(string(j))
+			default:
+				fmt.Printf("files=%d failed=%d tags=%d\n", inv.Datasets, failed, len(stats))
+				fmt.Printf("%-11s %-5s %-36s %6s %6s  %s\n", "TAG", "VR", "NAME", "FILES", "COUNT", "EXAMPLES")
+				for _, s := range stats {
+					name := s.Keyword
+					switch {
+					case s.Creator != "":
+						name = "[" + s.Creator + "]"
+					case name == "":
+						name = "?"
+					}
+					fmt.Printf("%-11v %-5s %-36s %6d %6d  %s\n", s.Tag, strings.Join(s.VRs, "/"), name, s.Datasets, s.Occurrences, strings.Join(s.Examples, " | "))
+				}
+			}
+			return nil
+		},
+	}
+	pf := cmd.PersistentFlags()
+	pf.BoolP("recursive", "r", false, "Descend into subdirectories of directory arguments")
+	pf.Bool("private", false, "List only private data elements")
+	pf.Int("examples", 3, "Distinct example values to keep per tag")
+	pf.StringP("format", "f", "text", "output format (text|json)")
+	cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
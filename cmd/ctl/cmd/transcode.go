@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+	"github.com/jpfielding/dicos.go/pkg/dicos/transfer"
+	"github.com/jpfielding/dicos.go/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+// NewTranscodeCmd creates the transcode cobra command
+func NewTranscodeCmd(ctx context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "transcode <in> <out>",
+		Short: "Re-encode pixel data in another transfer syntax",
+		Long:  "Decodes the pixel data of a DICOS file and writes it again compressed with --codec (jpeg-ls, jpeg-li, rle, jpeg-2000, or the lossy jpeg-baseline for 8-bit thumbnails and jpeg-extended for 12-bit frames) or uncompressed with --codec native, to normalize files from different vendors.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := logging.AppendCtx(ctx, slog.String("file", args[0]))
+			name, _ := cmd.Flags().GetString("codec")
+			target := transfer.ExplicitVRLittleEndian
+			if name != "native" {
+				codec := dicos.CodecByName(name)
+				if codec == nil {
+					return fmt.Errorf("unknown codec %q", name)
+				}
+				target = transfer.Syntax(codec.TransferSyntaxUID())
+			}
+
+			ds, err := dicos.ReadFileContext(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			out, err := dicos.TranscodeContext(ctx, ds, target)
+			if err != nil {
+				return err
+			}
+			if _, err := dicos.WriteFile(args[1], out); err != nil {
+				return err
+			}
+			slog.InfoContext(ctx, "Transcoded",
+				slog.String("out", args[1]),
+				slog.String("from", ds.TransferSyntax().Name()),
+				slog.String("to", target.Name()))
+			return nil
+		},
+	}
+	cmd.PersistentFlags().String("codec", "jpeg-ls", "Target codec: jpeg-ls, jpeg-li, rle, jpeg-2000, jpeg-baseline, jpeg-extended or native")
+	cmd.RegisterFlagCompletionFunc("codec", cobra.FixedCompletions([]string{"jpeg-ls", "jpeg-li", "rle", "jpeg-2000", "jpeg-baseline", "jpeg-extended", "native"}, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
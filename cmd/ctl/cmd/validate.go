@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+	"github.com/jpfielding/dicos.go/pkg/dicos/dict"
+	"github.com/jpfielding/dicos.go/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+// ValidateFinding is an error or warning reported by validate
+type ValidateFinding struct {
+	Tag      dicos.Tag `json:"tag"`
+	Keyword  string    `json:"keyword,omitempty"`
+	Type     string    `json:"type"`
+	Message  string    `json:"message"`
+	Critical bool      `json:"critical,omitempty"`
+}
+
+// ValidateReport is the outcome of validating one file
+type ValidateReport struct {
+	File     string            `json:"file"`
+	IOD      string            `json:"iod,omitempty"`
+	Valid    bool              `json:"valid"`
+	Skipped  string            `json:"skipped,omitempty"` // why the file was not validated
+	Errors   []ValidateFinding `json:"errors,omitempty"`
+	Warnings []ValidateFinding `json:"warnings,omitempty"`
+}
+
+// NewValidateCmd creates the validate cobra command
+func NewValidateCmd(ctx context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate <file|dir>...",
+		Short: "Check DICOS files against the requirements of their IOD",
+		Long:  "Validates each file against the CT, DX, AIT 2D, AIT 3D, TDR or QR requirements chosen from its SOP Class UID, and prints its errors and warnings with tag keywords. Exits non-zero when a file is invalid or unreadable; with --strict also on any warning or on files with no validation rules, for CI gates.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags := cmd.Flags()
+			recursive, _ := flags.GetBool("recursive")
+			strict, _ := flags.GetBool("strict")
+
+			inputs, err := collectAnonymizeInputs(args, recursive)
+			if err != nil {
+				return err
+			}
+			var reports []ValidateReport
+			failed := 0
+			for _, in := range inputs {
+				ctx := logging.AppendCtx(ctx, slog.String("file", in.path))
+				r := validateFile(ctx, in.path)
+				if !r.Valid || strict && (r.Skipped != "" || len(r.Errors) > 0 || len(r.Warnings) > 0) {
+					failed++
+				}
+				reports = append(reports, r)
+			}
+
+			switch format, _ := flags.GetString("format"); format {
+			case "json":
+				j, _ := json.MarshalIndent(reports, "", "  ")
+				fmt.Println(string(j))
+			default:
+				for _, r := range reports {
+					printValidateReport(r)
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d files failed validation", failed, len(reports))
+			}
+			return nil
+		},
+	}
+	pf := cmd.PersistentFlags()
+	pf.BoolP("recursive", "r", false, "Descend into subdirectories of directory arguments")
+	pf.Bool("strict", false, "Also fail on warnings and on files without validation rules")
+	pf.StringP("format", "f", "text", "output format (text|json)")
+	cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+// validateFile reads path, with its pixel data since it is a required
+// attribute, and validates it
+func validateFile(ctx context.Context, path string) ValidateReport {
+	r := ValidateReport{File: path}
+	ds, err := dicos.ReadFileContext(ctx, path)
+	if err != nil {
+		r.Skipped = err.Error()
+		return r
+	}
+	iod, result, err := dicos.ValidateIOD(ds)
+	if err != nil {
+		r.Valid, r.Skipped = true, err.Error()
+		return r
+	}
+	r.IOD, r.Valid = iod, result.IsValid()
+	r.Errors = validateFindings(result.Errors)
+	r.Warnings = validateFindings(result.Warnings)
+	return r
+}
+
+// validateFindings adds the dictionary keyword to each validation error
+func validateFindings(errs []dicos.ValidationError) []ValidateFinding {
+	var out []ValidateFinding
+	for _, e := range errs {
+		f := ValidateFinding{Tag: e.Tag, Type: e.Type.String(), Message: e.Message, Critical: e.IsCritical}
+		if entry, ok := dict.Lookup(e.Tag); ok {
+			f.Keyword = entry.Keyword
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+func printValidateReport(r ValidateReport) {
+	status := "VALID"
+	switch {
+	case !r.Valid && r.IOD == "":
+		status = "UNREADABLE"
+	case !r.Valid:
+		status = "INVALID"
+	case r.Skipped != "":
+		status = "SKIPPED"
+	}
+	fmt.Printf("%-10s %-4s %s (errors=%d warnings=%d)\n", status, r.IOD, r.File, len(r.Errors), len(r.Warnings))
+	if r.Skipped != "" {
+		fmt.Printf("  %s\n", r.Skipped)
+	}
+	for _, f := range r.Errors {
+		fmt.Printf("  ERROR   %v %-32s %-7s %s\n", f.Tag, f.Keyword, f.Type, f.Message)
+	}
+	for _, f := range r.Warnings {
+		fmt.Printf("  WARNING %v %-32s %-7s %s\n", f.Tag, f.Keyword, f.Type, f.Message)
+	}
+}
@@ -25,7 +25,7 @@ type AIT2DImage struct {
 	ContentDate       module.Date
 	ContentTime       module.Time
 	SamplesPerPixel   int
-	PhotometricInterp string // MONOCHROME2
+	PhotometricInterp string // MONOCHROME2, or RGB
 	Rows              int
 	Columns           int
 	BitsAllocated     int
@@ -47,7 +47,7 @@ type AIT2DImage struct {
 // NewAIT2DImage creates a new AIT 2D Image with defaults
 func NewAIT2DImage() *AIT2DImage {
 	t := time.Now()
-	return &AIT2DImage{
+	ait := &AIT2DImage{
 		SamplesPerPixel:   1,
 		PhotometricInterp: "MONOCHROME2",
 		BitsAllocated:     16,
@@ -61,6 +61,8 @@ func NewAIT2DImage() *AIT2DImage {
 		VOILUT:            module.NewVOILUTModule(),
 		ScannerType:       "MILLIMETER_WAVE",
 	}
+	fillUIDs(&ait.Study.StudyInstanceUID, &ait.Series.SeriesInstanceUID, &ait.SOPCommon.SOPInstanceUID)
+	return ait
 }
 
 // SetPixelData sets native pixel data for the AIT 2D image.
@@ -79,11 +81,49 @@ func NewAIT2DImage() *AIT2DImage {
 //	ait.Codec = dicos.CodecJPEGLS
 //	ait.Write("output.dcs")
 func (ait *AIT2DImage) SetPixelData(rows, cols int, data []uint16) {
+	ait.setPixelData(rows, cols, ait.SamplesPerPixel, data)
+}
+
+// SetGray8PixelData sets 8-bit grayscale pixel data: one unsigned sample per
+// pixel, MONOCHROME2, 8 bits allocated and stored.
+//
+//	ait.SetGray8PixelData(480, 640, pixels) // []uint8
+func (ait *AIT2DImage) SetGray8PixelData(rows, cols int, data []uint8) {
+	ait.set8Bit(1, "MONOCHROME2")
+	ait.setPixelData(rows, cols, 1, widen8(data))
+}
+
+// SetRGBPixelData sets 8-bit RGB pixel data, such as a color rendering of
+// the scan, as three interleaved samples per pixel in R, G, B order. The VOI
+// LUT, which applies only to grayscale, is dropped. To compress it, set
+// ait.Codec to a codec that supports color, such as CodecJPEG2000.
+//
+//	ait.SetRGBPixelData(480, 640, rgb) // len(rgb) == 480*640*3
+//	ait.Codec = dicos.CodecJPEG2000
+func (ait *AIT2DImage) SetRGBPixelData(rows, cols int, data []uint8) {
+	ait.set8Bit(3, "RGB")
+	ait.VOILUT = nil
+	ait.setPixelData(rows, cols, 3, widen8(data))
+}
+
+// set8Bit sets the image attributes of unsigned 8-bit samples
+func (ait *AIT2DImage) set8Bit(samples int, photometric string) {
+	ait.SamplesPerPixel = samples
+	ait.PhotometricInterp = photometric
+	ait.BitsAllocated = 8
+	ait.BitsStored = 8
+	ait.HighBit = 7
+	ait.PixelRepresent = 0
+}
+
+// setPixelData splits data into native frames of rows x cols pixels of
+// samples each
+func (ait *AIT2DImage) setPixelData(rows, cols, samples int, data []uint16) {
 	ait.Rows = rows
 	ait.Columns = cols
 
-	pixelsPerFrame := rows * cols
-	numFrames := len(data) / pixelsPerFrame
+	samplesPerFrame := rows * cols * max(samples, 1)
+	numFrames := len(data) / samplesPerFrame
 	if numFrames < 1 {
 		numFrames = 1
 	}
@@ -94,8 +134,8 @@ func (ait *AIT2DImage) SetPixelData(rows, cols int, data []uint16) {
 	}
 
 	for i := 0; i < numFrames; i++ {
-		start := i * pixelsPerFrame
-		end := start + pixelsPerFrame
+		start := i * samplesPerFrame
+		end := start + samplesPerFrame
 		if end > len(data) {
 			end = len(data)
 		}
@@ -113,7 +153,7 @@ func (ait *AIT2DImage) GetDataset() (*Dataset, error) {
 
 	sopInstanceUID := ait.SOPCommon.SOPInstanceUID
 	if sopInstanceUID == "" {
-		sopInstanceUID = GenerateUID("1.2.826.0.1.3680043.8.498.")
+		sopInstanceUID = NewUID()
 		ait.SOPCommon.SOPInstanceUID = sopInstanceUID
 	}
 	ait.SOPCommon.SOPClassUID = DICOSAIT2DImageStorageUID
@@ -156,14 +196,18 @@ func (ait *AIT2DImage) GetDataset() (*Dataset, error) {
 		WithElement(tag.HighBit, ait.HighBit),
 		WithElement(tag.PixelRepresentation, ait.PixelRepresent),
 	)
+	if ait.SamplesPerPixel > 1 {
+		opts = append(opts, WithElement(tag.PlanarConfiguration, 0)) // interleaved
+	}
 
 	// TODO: Add AIT-specific tags when defined in tag package
 	// BodyRegion, PrivacyMask, ScanViewAngle, ScannerType
 
-	// Pixel Data
+	// Pixel Data, once the attributes describing it are consistent
+	opts = append(opts, ValidateImagePixel)
 	if ait.Codec != nil && ait.PixelData != nil && !ait.PixelData.IsEncapsulated {
 		flatData := ait.PixelData.GetFlatData()
-		opts = append(opts, WithPixelData(ait.Rows, ait.Columns, ait.BitsAllocated, flatData, ait.Codec))
+		opts = append(opts, WithPixelData(ait.Rows, ait.Columns, ait.BitsAllocated, flatData, ait.Codec), WithFrameMeta(ait.PixelData.FrameMeta()...))
 	} else if ait.PixelData != nil {
 		opts = append(opts, WithRawPixelData(ait.PixelData))
 	}
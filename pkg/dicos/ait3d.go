@@ -3,6 +3,7 @@ package dicos
 import (
 	"io"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/jpfielding/dicos.go/pkg/dicos/module"
@@ -41,6 +42,9 @@ type AIT3DImage struct {
 	CoordinateSystem string // DICOS_BODY_COORDINATE
 	ScannerType      string // MILLIMETER_WAVE, BACKSCATTER
 
+	// Surface Data, written as the Surface Mesh Module; see ParseSurfaces
+	Surfaces []Surface
+
 	// Volumetric Data
 	PixelData *PixelData
 	Codec     Codec // nil = uncompressed
@@ -49,7 +53,7 @@ type AIT3DImage struct {
 // NewAIT3DImage creates a new AIT 3D Image with defaults
 func NewAIT3DImage() *AIT3DImage {
 	t := time.Now()
-	return &AIT3DImage{
+	ait := &AIT3DImage{
 		SamplesPerPixel:   1,
 		PhotometricInterp: "MONOCHROME2",
 		BitsAllocated:     16,
@@ -68,6 +72,8 @@ func NewAIT3DImage() *AIT3DImage {
 		CoordinateSystem:  "DICOS_BODY_COORDINATE",
 		ScannerType:       "MILLIMETER_WAVE",
 	}
+	fillUIDs(&ait.Study.StudyInstanceUID, &ait.Series.SeriesInstanceUID, &ait.SOPCommon.SOPInstanceUID, &ait.FrameOfReference.FrameOfReferenceUID)
+	return ait
 }
 
 // SetPixelData sets native pixel data for 3D volumetric AIT image.
@@ -116,7 +122,7 @@ func (ait *AIT3DImage) GetDataset() (*Dataset, error) {
 
 	sopInstanceUID := ait.SOPCommon.SOPInstanceUID
 	if sopInstanceUID == "" {
-		sopInstanceUID = GenerateUID("1.2.826.0.1.3680043.8.498.")
+		sopInstanceUID = NewUID()
 		ait.SOPCommon.SOPInstanceUID = sopInstanceUID
 	}
 	ait.SOPCommon.SOPClassUID = DICOSAIT3DImageStorageUID
@@ -160,7 +166,7 @@ func (ait *AIT3DImage) GetDataset() (*Dataset, error) {
 		WithElement(tag.PhotometricInterpretation, ait.PhotometricInterp),
 		WithElement(tag.Rows, ait.Rows),
 		WithElement(tag.Columns, ait.Columns),
-		WithElement(tag.NumberOfFrames, ait.NumberOfFrames),
+		WithElement(tag.NumberOfFrames, strconv.Itoa(ait.NumberOfFrames)),
 		WithElement(tag.BitsAllocated, ait.BitsAllocated),
 		WithElement(tag.BitsStored, ait.BitsStored),
 		WithElement(tag.HighBit, ait.HighBit),
@@ -170,10 +176,15 @@ func (ait *AIT3DImage) GetDataset() (*Dataset, error) {
 	// TODO: Add AIT-specific tags when defined in tag package
 	// SurfaceType, CoordinateSystem, ScannerType
 
-	// Pixel Data
+	if len(ait.Surfaces) > 0 {
+		opts = append(opts, WithSurfaces(ait.Surfaces...))
+	}
+
+	// Pixel Data, once the attributes describing it are consistent
+	opts = append(opts, ValidateImagePixel)
 	if ait.Codec != nil && ait.PixelData != nil && !ait.PixelData.IsEncapsulated {
 		flatData := ait.PixelData.GetFlatData()
-		opts = append(opts, WithPixelData(ait.Rows, ait.Columns, ait.BitsAllocated, flatData, ait.Codec))
+		opts = append(opts, WithPixelData(ait.Rows, ait.Columns, ait.BitsAllocated, flatData, ait.Codec), WithFrameMeta(ait.PixelData.FrameMeta()...))
 	} else if ait.PixelData != nil {
 		opts = append(opts, WithRawPixelData(ait.PixelData))
 	}
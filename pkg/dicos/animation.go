@@ -0,0 +1,193 @@
+package dicos
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"time"
+)
+
+// Window maps stored pixel values to display gray levels. Stored values
+// are sign extended when Signed and rescaled (e.g. to HU for CT) before the
+// window is applied.
+type Window struct {
+	Center, Width    float64
+	Slope, Intercept float64
+	Signed           bool // samples are two's complement in BitsStored bits
+	BitsStored       int  // 0 means 16
+}
+
+// WindowFromDataset returns the dataset's window center/width, rescale and
+// sample format, falling back to the CT soft tissue defaults of GetWindowLevel
+func WindowFromDataset(ds *Dataset) Window {
+	center, width := GetWindowLevel(ds)
+	intercept, slope := GetRescale(ds)
+	return Window{
+		Center: float64(center), Width: float64(width),
+		Slope: slope, Intercept: intercept,
+		Signed: ds.PixelRepresentation() == 1, BitsStored: ds.BitsStored(),
+	}
+}
+
+// Modality returns the rescaled value of a stored sample
+func (w Window) Modality(stored uint16) float64 {
+	slope := w.Slope
+	if slope == 0 {
+		slope = 1
+	}
+	v := float64(stored)
+	if w.Signed {
+		bits := w.BitsStored
+		if bits == 0 {
+			bits = 16
+		}
+		shift := signShift(bits)
+		v = float64(int16(stored<<shift) >> shift)
+	}
+	return v*slope + w.Intercept
+}
+
+// level returns the position of a stored sample in the window, from 0 to 1
+func (w Window) level(stored uint16) float64 {
+	width := max(w.Width, 1)
+	v := (w.Modality(stored) - (w.Center - width/2)) / width
+	return min(max(v, 0), 1)
+}
+
+// Render windows a rows x cols frame into an 8-bit gray image
+func (w Window) Render(data []uint16, rows, cols int) (*image.Gray, error) {
+	if rows*cols > len(data) {
+		return nil, fmt.Errorf("frame has %d pixels, need %dx%d", len(data), rows, cols)
+	}
+	img := image.NewGray(image.Rect(0, 0, cols, rows))
+	for i := range rows * cols {
+		img.Pix[i] = uint8(w.level(data[i]) * 255)
+	}
+	return img, nil
+}
+
+// Render16 windows a rows x cols frame into a 16-bit gray image, keeping more
+// gray levels than Render for review in image tools
+func (w Window) Render16(data []uint16, rows, cols int) (*image.Gray16, error) {
+	if rows*cols > len(data) {
+		return nil, fmt.Errorf("frame has %d pixels, need %dx%d", len(data), rows, cols)
+	}
+	img := image.NewGray16(image.Rect(0, 0, cols, rows))
+	for i := range rows * cols {
+		img.SetGray16(i%cols, i/cols, color.Gray16{Y: uint16(w.level(data[i]) * 65535)})
+	}
+	return img, nil
+}
+
+// AnimationEncoder receives rendered frames in playback order. GIF is built
+// in; other containers such as MP4 plug in by implementing this interface,
+// e.g. by piping frames to an external encoder.
+type AnimationEncoder interface {
+	AddFrame(img *image.Gray, delay time.Duration) error
+	Close() error
+}
+
+// AnimationOptions controls how frames are rendered for playback
+type AnimationOptions struct {
+	Window   *Window       // display window; nil uses WindowFromDataset
+	Delay    time.Duration // time each frame is shown; 0 means 100ms
+	Step     int           // export every Step-th frame; 0 or 1 exports all
+	Annotate bool          // burn frame number and window/level into each frame
+	Label    string        // optional text burned in above the frame counter
+}
+
+// ExportAnimation decodes each frame of a multi-frame dataset (a DX sequence
+// or CT slice sweep), windows it to 8 bits and hands it to enc. enc is closed
+// when all frames have been added.
+//
+// Example:
+//
+//	f, _ := os.Create("sweep.gif")
+//	defer f.Close()
+//	err := dicos.ExportAnimation(ctx, ds, dicos.NewGIFEncoder(f), dicos.AnimationOptions{Annotate: true})
+func ExportAnimation(ctx context.Context, ds *Dataset, enc AnimationEncoder, opts AnimationOptions) error {
+	pd, err := ds.GetPixelDataContext(ctx)
+	if err != nil {
+		return err
+	}
+	rows, cols := ds.Rows(), ds.Columns()
+	if rows == 0 || cols == 0 {
+		return fmt.Errorf("missing image dimensions: %dx%d", rows, cols)
+	}
+	win := WindowFromDataset(ds)
+	if opts.Window != nil {
+		win = *opts.Window
+	}
+	delay := opts.Delay
+	if delay == 0 {
+		delay = 100 * time.Millisecond
+	}
+	step := max(opts.Step, 1)
+	scale := max(1, cols/256)
+	ts := GetTransferSyntax(ds)
+
+	for i := 0; i < len(pd.Frames); i += step {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		data, err := DecodeFrameDataContext(ctx, pd, i, rows, cols, ts)
+		if err != nil {
+			return fmt.Errorf("frame %d: %w", i, err)
+		}
+		img, err := win.Render(data, rows, cols)
+		if err != nil {
+			return fmt.Errorf("frame %d: %w", i, err)
+		}
+		if opts.Annotate {
+			y := 2 * scale
+			if opts.Label != "" {
+				drawText(img, 2*scale, y, scale, opts.Label, color.Gray{Y: 0xFF}, color.Gray{})
+				y += 7 * scale
+			}
+			text := fmt.Sprintf("%d/%d W:%.0f L:%.0f", i+1, len(pd.Frames), win.Width, win.Center)
+			drawText(img, 2*scale, y, scale, text, color.Gray{Y: 0xFF}, color.Gray{})
+		}
+		if err := enc.AddFrame(img, delay); err != nil {
+			return fmt.Errorf("frame %d: %w", i, err)
+		}
+	}
+	return enc.Close()
+}
+
+// grayPalette holds the 256 gray levels used for GIF frames
+var grayPalette = func() color.Palette {
+	p := make(color.Palette, 256)
+	for i := range p {
+		p[i] = color.Gray{Y: uint8(i)}
+	}
+	return p
+}()
+
+// gifEncoder buffers frames and writes a looping animated GIF on Close
+type gifEncoder struct {
+	w    io.Writer
+	anim gif.GIF
+}
+
+// NewGIFEncoder returns an AnimationEncoder that writes a looping animated GIF to w
+func NewGIFEncoder(w io.Writer) AnimationEncoder {
+	return &gifEncoder{w: w}
+}
+
+func (g *gifEncoder) AddFrame(img *image.Gray, delay time.Duration) error {
+	p := image.NewPaletted(img.Bounds(), grayPalette)
+	copy(p.Pix, img.Pix)
+	g.anim.Image = append(g.anim.Image, p)
+	g.anim.Delay = append(g.anim.Delay, int(delay/(10*time.Millisecond))) // GIF delays are in 1/100s
+	return nil
+}
+
+func (g *gifEncoder) Close() error {
+	if len(g.anim.Image) == 0 {
+		return fmt.Errorf("no frames to encode")
+	}
+	return gif.EncodeAll(g.w, &g.anim)
+}
@@ -0,0 +1,75 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"image/gif"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWindowRender(t *testing.T) {
+	w := Window{Center: 100, Width: 200, Slope: 1, Intercept: -1000}
+	img, err := w.Render([]uint16{900, 1100, 1300, 0}, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []uint8{0, 127, 255, 0}, img.Pix)
+
+	_, err = w.Render([]uint16{1, 2, 3}, 2, 2)
+	assert.Error(t, err)
+}
+
+func TestWindowRender_Signed(t *testing.T) {
+	// 12-bit two's complement: 0xF38 is -200, 0x0C8 is 200
+	w := Window{Center: 0, Width: 400, Slope: 1, Signed: true, BitsStored: 12}
+	assert.Equal(t, -200.0, w.Modality(0xF38))
+	assert.Equal(t, -200.0, w.Modality(0xFF38), "already sign extended")
+	img, err := w.Render([]uint16{0xF38, 0, 0x0C8, 0x7FF}, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []uint8{0, 127, 255, 255}, img.Pix)
+
+	img16, err := w.Render16([]uint16{0xF38, 0x0C8}, 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(0), img16.Gray16At(0, 0).Y)
+	assert.Equal(t, uint16(65535), img16.Gray16At(1, 0).Y)
+}
+
+func TestExportAnimation_GIF(t *testing.T) {
+	const rows, cols, frames = 32, 32, 5
+	data := make([]uint16, rows*cols*frames)
+	for i := range data {
+		data[i] = uint16(i / (rows * cols) * 100)
+	}
+	ds, err := NewDataset(
+		WithElement(tag.Rows, uint16(rows)),
+		WithElement(tag.Columns, uint16(cols)),
+		WithElement(tag.BitsAllocated, uint16(16)),
+		WithPixelData(rows, cols, 16, data, nil),
+	)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	opts := AnimationOptions{
+		Window:   &Window{Center: 200, Width: 400, Slope: 1},
+		Step:     2,
+		Annotate: true,
+	}
+	require.NoError(t, ExportAnimation(context.Background(), ds, NewGIFEncoder(&buf), opts))
+
+	anim, err := gif.DecodeAll(&buf)
+	require.NoError(t, err)
+	require.Len(t, anim.Image, 3, "every second frame of five")
+	assert.Equal(t, []int{10, 10, 10}, anim.Delay)
+
+	last := anim.Image[2]
+	assert.Equal(t, uint8(255), last.ColorIndexAt(cols-1, rows-1), "frame 5 at 400 is the top of the window")
+	assert.Equal(t, uint8(0), last.ColorIndexAt(1, 1), "annotation background is burned in")
+}
+
+func TestExportAnimation_NoFrames(t *testing.T) {
+	ds, err := NewDataset()
+	require.NoError(t, err)
+	assert.Error(t, ExportAnimation(context.Background(), ds, NewGIFEncoder(&bytes.Buffer{}), AnimationOptions{}))
+}
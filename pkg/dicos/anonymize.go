@@ -0,0 +1,210 @@
+package dicos
+
+import (
+	"fmt"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// AnonymizeProfile selects which identifying attributes Anonymize keeps
+type AnonymizeProfile string
+
+const (
+	// ProfileBasic removes or blanks identity, itinerary, date and device
+	// attributes and private tags, and replaces UIDs
+	ProfileBasic AnonymizeProfile = "basic"
+	// ProfileRetainDeviceInfo is ProfileBasic but keeps the equipment that
+	// produced the scan, for fleet and detector analytics
+	ProfileRetainDeviceInfo AnonymizeProfile = "retain-device-info"
+	// ProfileRetainDates is ProfileBasic but keeps study, series and content
+	// dates and times, for throughput and longitudinal analysis
+	ProfileRetainDates AnonymizeProfile = "retain-dates"
+)
+
+// AnonymizeProfiles lists the supported profiles
+var AnonymizeProfiles = []AnonymizeProfile{ProfileBasic, ProfileRetainDeviceInfo, ProfileRetainDates}
+
+// anonymizeAction is what Anonymize does with an attribute
+type anonymizeAction int
+
+const (
+	anonRemove anonymizeAction = iota // drop the element
+	anonEmpty                         // keep the element with a zero-length value
+	anonDummy                         // replace the value with a fixed placeholder
+	anonUID                           // replace the UID through the UIDMap
+	anonDate                          // blank unless the profile retains dates
+	anonDevice                        // remove unless the profile retains device info
+)
+
+// anonymizeActions are the attributes Anonymize touches in every profile;
+// all other standard attributes are kept
+var anonymizeActions = map[Tag]anonymizeAction{
+	tag.PatientName:                 anonDummy,
+	tag.PatientID:                   anonDummy,
+	tag.PatientBirthDate:            anonEmpty,
+	tag.PatientSex:                  anonEmpty,
+	tag.PatientAge:                  anonRemove,
+	tag.PatientComments:             anonRemove,
+	tag.IssuerOfPatientID:           anonRemove,
+	tag.AccessionNumber:             anonEmpty,
+	tag.StudyID:                     anonEmpty,
+	tag.InstitutionName:             anonRemove,
+	tag.InstitutionAddress:          anonRemove,
+	tag.InstitutionalDepartmentName: anonRemove,
+	tag.OperatorsName:               anonRemove,
+	tag.ImageComments:               anonRemove,
+
+	tag.OOIOwnerID:       anonRemove,
+	tag.OOIOwnerName:     anonRemove,
+	tag.OOIOwnerIDType:   anonRemove,
+	tag.FlightNumber:     anonRemove,
+	tag.DepartureAirport: anonRemove,
+	tag.ArrivalAirport:   anonRemove,
+	tag.CarrierName:      anonRemove,
+	tag.CarrierCode:      anonRemove,
+
+	tag.StudyInstanceUID:           anonUID,
+	tag.SeriesInstanceUID:          anonUID,
+	tag.SOPInstanceUID:             anonUID,
+	tag.MediaStorageSOPInstanceUID: anonUID,
+	tag.FrameOfReferenceUID:        anonUID,
+	tag.ReferencedSOPInstanceUID:   anonUID,
+
+	tag.StudyDate:                anonDate,
+	tag.StudyTime:                anonDate,
+	tag.SeriesDate:               anonDate,
+	tag.SeriesTime:               anonDate,
+	tag.ContentDate:              anonDate,
+	tag.ContentTime:              anonDate,
+	tag.AcquisitionDate:          anonDate,
+	tag.AcquisitionTime:          anonDate,
+	tag.AcquisitionDateTime:      anonDate,
+	tag.InstanceCreationDate:     anonDate,
+	tag.InstanceCreationTime:     anonDate,
+	tag.FrameAcquisitionDateTime: anonDate,
+
+	tag.Manufacturer:          anonDevice,
+	tag.ManufacturerModelName: anonDevice,
+	tag.DeviceSerialNumber:    anonDevice,
+	tag.StationName:           anonDevice,
+	tag.SoftwareVersions:      anonDevice,
+}
+
+// UIDMap maps original UIDs to their anonymized replacements. Reusing one
+// map across files, or saving and reloading it between runs, keeps studies
+// and series consistent for longitudinal analysis.
+type UIDMap map[string]string
+
+// Replace returns the replacement for uid, generating one on first use
+func (m UIDMap) Replace(uid string) string {
+	if uid == "" {
+		return ""
+	}
+	if r, ok := m[uid]; ok {
+		return r
+	}
+	r := NewUID()
+	m[uid] = r
+	return r
+}
+
+// AnonymizeOptions controls Anonymize
+type AnonymizeOptions struct {
+	Profile AnonymizeProfile // empty means ProfileBasic
+	UIDs    UIDMap           // UID replacements to reuse and extend; nil uses a fresh map
+}
+
+// AnonymizeReport lists the attributes Anonymize changed, including those
+// inside sequences. A tag appears once per occurrence.
+type AnonymizeReport struct {
+	Removed  []Tag
+	Modified []Tag
+}
+
+// Anonymize returns a de-identified copy of ds according to opts.Profile.
+// Identity and itinerary attributes are removed or blanked, UIDs are
+// replaced consistently through opts.UIDs, private tags are dropped, and
+// dates and device information are removed unless the profile retains them.
+// Patient Identity Removed (0012,0062) and De-identification Method
+// (0012,0063) record what was done. ds itself is not modified.
+//
+// Example:
+//
+//	uids := dicos.UIDMap{}
+//	for _, ds := range scans {
+//		anon, report, err := dicos.Anonymize(ds, dicos.AnonymizeOptions{Profile: dicos.ProfileRetainDates, UIDs: uids})
+//		...
+//	}
+func Anonymize(ds *Dataset, opts AnonymizeOptions) (*Dataset, AnonymizeReport, error) {
+	profile := opts.Profile
+	if profile == "" {
+		profile = ProfileBasic
+	}
+	switch profile {
+	case ProfileBasic, ProfileRetainDeviceInfo, ProfileRetainDates:
+	default:
+		return nil, AnonymizeReport{}, fmt.Errorf("unknown anonymize profile %q", profile)
+	}
+	uids := opts.UIDs
+	if uids == nil {
+		uids = UIDMap{}
+	}
+
+	out := CloneDataset(ds)
+	// replaced UIDs change the meta group's length; the writer does not need it
+	delete(out.Elements, tag.FileMetaInformationGroupLength)
+	var report AnonymizeReport
+	anonymizeDataset(out, profile, uids, &report)
+	out.Elements[tag.PatientIdentityRemoved] = &Element{Tag: tag.PatientIdentityRemoved, VR: "CS", Value: "YES"}
+	out.Elements[tag.DeidentificationMethod] = &Element{Tag: tag.DeidentificationMethod, VR: "LO", Value: "dicos.go " + string(profile)}
+	return out, report, nil
+}
+
+// anonymizeDataset applies the profile to ds and its sequence items in place
+func anonymizeDataset(ds *Dataset, profile AnonymizeProfile, uids UIDMap, report *AnonymizeReport) {
+	for _, t := range sortedTags(ds) {
+		elem := ds.Elements[t]
+		if t.IsPrivate() {
+			delete(ds.Elements, t)
+			report.Removed = append(report.Removed, t)
+			continue
+		}
+		if items, ok := elem.Value.([]*Dataset); ok {
+			for _, item := range items {
+				if item != nil {
+					anonymizeDataset(item, profile, uids, report)
+				}
+			}
+			continue
+		}
+
+		action, ok := anonymizeActions[t]
+		if !ok {
+			continue
+		}
+		switch {
+		case action == anonDate && profile == ProfileRetainDates,
+			action == anonDevice && profile == ProfileRetainDeviceInfo:
+			continue
+		case action == anonDate:
+			action = anonEmpty
+		case action == anonDevice:
+			action = anonRemove
+		}
+
+		switch action {
+		case anonRemove:
+			delete(ds.Elements, t)
+			report.Removed = append(report.Removed, t)
+			continue
+		case anonEmpty:
+			elem.Value = ""
+		case anonDummy:
+			elem.Value = "ANONYMOUS"
+		case anonUID:
+			s, _ := elem.GetString()
+			elem.Value = uids.Replace(s)
+		}
+		report.Modified = append(report.Modified, t)
+	}
+}
@@ -0,0 +1,80 @@
+package dicos
+
+import (
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func anonymizeTestDataset(t *testing.T) *Dataset {
+	ref, err := NewDataset(WithElement(tag.ReferencedSOPInstanceUID, "1.2.3.4"))
+	require.NoError(t, err)
+	ds, err := NewDataset(
+		WithElement(tag.PatientName, "DOE^JANE"),
+		WithElement(tag.PatientID, "PAT-001"),
+		WithElement(tag.FlightNumber, "UA123"),
+		WithElement(tag.StudyDate, "20260101"),
+		WithElement(tag.Manufacturer, "ACME"),
+		WithElement(tag.Modality, "CT"),
+		WithElement(tag.SOPInstanceUID, "1.2.3.4"),
+		WithElement(tag.New(0x0009, 0x1001), "secret"),
+		WithSequence(tag.ReferencedImageSequence, ref),
+	)
+	require.NoError(t, err)
+	return ds
+}
+
+func TestAnonymize_Profiles(t *testing.T) {
+	tests := []struct {
+		profile      AnonymizeProfile
+		date, device bool // retained
+	}{
+		{ProfileBasic, false, false},
+		{ProfileRetainDates, true, false},
+		{ProfileRetainDeviceInfo, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.profile), func(t *testing.T) {
+			ds := anonymizeTestDataset(t)
+			anon, report, err := Anonymize(ds, AnonymizeOptions{Profile: tt.profile})
+			require.NoError(t, err)
+
+			assert.Equal(t, "ANONYMOUS", anon.Elements[tag.PatientName].Value)
+			assert.False(t, HasElement(anon, tag.FlightNumber))
+			assert.False(t, HasElement(anon, tag.New(0x0009, 0x1001)), "private tags removed")
+			assert.Equal(t, "CT", anon.Elements[tag.Modality].Value)
+			assert.Equal(t, "YES", anon.Elements[tag.PatientIdentityRemoved].Value)
+
+			if tt.date {
+				assert.Equal(t, "20260101", anon.Elements[tag.StudyDate].Value)
+			} else {
+				assert.Equal(t, "", anon.Elements[tag.StudyDate].Value)
+			}
+			assert.Equal(t, tt.device, HasElement(anon, tag.Manufacturer))
+
+			assert.Contains(t, report.Removed, tag.FlightNumber)
+			assert.Contains(t, report.Modified, tag.PatientName)
+			assert.Equal(t, "DOE^JANE", ds.Elements[tag.PatientName].Value, "input unchanged")
+		})
+	}
+}
+
+func TestAnonymize_UIDMap(t *testing.T) {
+	uids := UIDMap{}
+	a, _, err := Anonymize(anonymizeTestDataset(t), AnonymizeOptions{UIDs: uids})
+	require.NoError(t, err)
+	b, _, err := Anonymize(anonymizeTestDataset(t), AnonymizeOptions{UIDs: uids})
+	require.NoError(t, err)
+
+	uid := a.Elements[tag.SOPInstanceUID].Value
+	assert.NotEqual(t, "1.2.3.4", uid)
+	assert.Equal(t, uid, b.Elements[tag.SOPInstanceUID].Value, "same map, same replacement")
+	ref := GetSequenceItems(a, tag.ReferencedImageSequence)[0]
+	assert.Equal(t, uid, ref.Elements[tag.ReferencedSOPInstanceUID].Value, "references follow the instance")
+	assert.Len(t, uids, 1)
+
+	_, _, err = Anonymize(a, AnonymizeOptions{Profile: "bogus"})
+	assert.Error(t, err)
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/jpfielding/dicos.go/pkg/dicos/module"
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -114,6 +116,32 @@ func TestDXImage_BasicWorkflow(t *testing.T) {
 	require.NotNil(t, dataset)
 }
 
+// TestDXImage_OOI demonstrates identifying the scanned bag, its owner and its
+// routing without raw tag writes.
+func TestDXImage_OOI(t *testing.T) {
+	dx := NewDXImage()
+	dx.Rows, dx.Columns = 4, 4
+	dx.OOI = module.NewOOIModule()
+	dx.OOI.OOIID = "BAG-0042"
+	dx.OOIOwner = &module.OOIOwnerModule{OwnerCategory: "CREW"}
+	dx.Itinerary = &module.ItineraryModule{FlightNumber: "LH400", DepartureAirport: "FRA", ArrivalAirport: "JFK", CarrierName: "Lufthansa"}
+
+	ds, err := dx.GetDataset()
+	require.NoError(t, err)
+	ds = rewrite(t, ds)
+	assert.Equal(t, "BAG-0042", attrString(ds, tag.OOIID))
+	assert.Equal(t, "BAG", attrString(ds, tag.OOITypeAttr))
+	assert.Equal(t, "CREW", attrString(ds, tag.OOIOwnerCategory))
+	assert.Equal(t, "LH400", attrString(ds, tag.FlightNumber))
+	assert.Equal(t, "JFK", attrString(ds, tag.ArrivalAirport))
+	assert.False(t, HasElement(ds, tag.OOIOwnerID), "unset attributes are not written")
+
+	ds, err = NewDXImage().GetDataset()
+	require.NoError(t, err)
+	assert.Nil(t, readOOIModule(ds))
+	assert.Nil(t, readItineraryModule(ds))
+}
+
 // ============================================================================
 // TDR (Threat Detection Report) API Documentation Tests
 // ============================================================================
@@ -188,6 +216,78 @@ func TestValidation_CTImage(t *testing.T) {
 	}
 }
 
+// TestValidateIOD picks the requirements from the SOP class
+func TestValidateIOD(t *testing.T) {
+	tdr := NewThreatDetectionReport()
+	ds, err := tdr.GetDataset()
+	require.NoError(t, err)
+	iod, result, err := ValidateIOD(ds)
+	require.NoError(t, err)
+	assert.Equal(t, "TDR", iod)
+	assert.Equal(t, ValidateTDR(ds), result)
+
+	ds.Elements[tag.SOPClassUID].Value = DICOSCTImageStorageUID
+	iod, result, err = ValidateIOD(ds)
+	require.NoError(t, err)
+	assert.Equal(t, "CT", iod)
+	assert.False(t, result.IsValid(), "a TDR lacks the CT image attributes")
+
+	ds.Elements[tag.SOPClassUID].Value = SecondaryCaptureImageStorageUID
+	_, _, err = ValidateIOD(ds)
+	assert.ErrorContains(t, err, "no validation rules")
+}
+
+// TestValidateAIT checks the AIT images by representation
+func TestValidateAIT(t *testing.T) {
+	ait2d := NewAIT2DImage()
+	ait2d.Series.Modality = "AIT"
+	ait2d.SetRGBPixelData(2, 2, make([]uint8, 2*2*3))
+	ds, err := ait2d.GetDataset()
+	require.NoError(t, err)
+	iod, result, err := ValidateIOD(ds)
+	require.NoError(t, err)
+	assert.Equal(t, "AIT2D", iod)
+	assert.True(t, result.IsValid(), result.String())
+	delete(ds.Elements, tag.PlanarConfiguration)
+	result = ValidateAIT2D(ds)
+	require.False(t, result.IsValid())
+	assert.Equal(t, tag.PlanarConfiguration, result.Errors[0].Tag)
+
+	// voxel volume
+	ait3d := NewAIT3DImage()
+	ait3d.Series.Modality = "AIT"
+	ait3d.SetPixelData(2, 2, 2, make([]uint16, 8))
+	ds, err = ait3d.GetDataset()
+	require.NoError(t, err)
+	result = Validate(ds)
+	assert.True(t, result.IsValid(), result.String())
+
+	// surfaces only, no volume
+	ait3d = NewAIT3DImage()
+	ait3d.Series.Modality = "AIT"
+	ait3d.Surfaces = []Surface{tetrahedron()}
+	ds, err = ait3d.GetDataset()
+	require.NoError(t, err)
+	require.NotContains(t, ds.Elements, tag.PixelData)
+	result = ValidateAIT3D(ds)
+	assert.True(t, result.IsValid(), result.String())
+	delete(ds.Elements, tag.NumberOfSurfaces)
+	result = ValidateAIT3D(ds)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, tag.NumberOfSurfaces, result.Errors[0].Tag)
+
+	// neither
+	ds, err = NewAIT3DImage().GetDataset()
+	require.NoError(t, err)
+	result = ValidateAIT3D(ds)
+	assert.Contains(t, result.AllMessages(), "ERROR: (7FE0,0010) Type 1C: Conditionally required attribute missing")
+
+	ds.Elements[tag.SOPClassUID].Value = SecondaryCaptureImageStorageUID
+	result = Validate(ds)
+	require.False(t, result.IsValid())
+	assert.Equal(t, tag.SOPClassUID, result.Errors[0].Tag)
+}
+
 // ============================================================================
 // Read/Write Roundtrip Tests
 // ============================================================================
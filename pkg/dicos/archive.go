@@ -0,0 +1,284 @@
+package dicos
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// ArchiveFormat is the container format of a study archive
+type ArchiveFormat string
+
+const (
+	ArchiveZip ArchiveFormat = "zip"
+	ArchiveTar ArchiveFormat = "tar"
+)
+
+// ManifestName is the name of the manifest inside a study archive
+const ManifestName = "manifest.json"
+
+// ErrManifestMismatch is returned when the content of a study archive does
+// not match its manifest
+var ErrManifestMismatch = errors.New("archive does not match its manifest")
+
+// ArchiveManifest describes the instances of a study archive
+type ArchiveManifest struct {
+	StudyInstanceUID string         `json:"study_instance_uid"`
+	Created          time.Time      `json:"created"`
+	Instances        []ArchiveEntry `json:"instances"`
+}
+
+// ArchiveEntry is one instance of a study archive
+type ArchiveEntry struct {
+	Path              string `json:"path"` // <SeriesInstanceUID>/<SOPInstanceUID>.dcs
+	SOPClassUID       string `json:"sop_class_uid"`
+	SOPInstanceUID    string `json:"sop_instance_uid"`
+	SeriesInstanceUID string `json:"series_instance_uid"`
+	TransferSyntaxUID string `json:"transfer_syntax_uid"`
+	Codec             string `json:"codec,omitempty"` // codec of encapsulated pixel data
+	Frames            int    `json:"frames,omitempty"`
+	Size              int64  `json:"size"`
+	SHA256            string `json:"sha256"`
+}
+
+// ExportStudyArchive writes every instance of a study to w as a zip or tar
+// archive, one Part 10 file per instance, followed by a JSON manifest with
+// their UIDs, codecs and SHA-256 hashes. All instances must share a Study
+// Instance UID. The manifest is returned.
+//
+// Example:
+//
+//	f, _ := os.Create("study.zip")
+//	defer f.Close()
+//	manifest, err := dicos.ExportStudyArchive(instances, f, dicos.ArchiveZip)
+func ExportStudyArchive(study []*Dataset, w io.Writer, format ArchiveFormat) (*ArchiveManifest, error) {
+	if len(study) == 0 {
+		return nil, fmt.Errorf("study archive needs at least one instance")
+	}
+	aw, err := newArchiveWriter(w, format)
+	if err != nil {
+		return nil, err
+	}
+	m := &ArchiveManifest{
+		StudyInstanceUID: stringValue(study[0], tag.StudyInstanceUID),
+		Created:          time.Now().UTC().Truncate(time.Second),
+	}
+	seen := make(map[string]bool, len(study))
+	for i, ds := range study {
+		entry := archiveEntry(ds)
+		if entry.SOPInstanceUID == "" {
+			return nil, fmt.Errorf("instance %d has no SOP Instance UID", i)
+		}
+		if seen[entry.SOPInstanceUID] {
+			return nil, fmt.Errorf("instance %d: SOP Instance UID %s is repeated", i, entry.SOPInstanceUID)
+		}
+		seen[entry.SOPInstanceUID] = true
+		if uid := stringValue(ds, tag.StudyInstanceUID); uid != m.StudyInstanceUID {
+			return nil, fmt.Errorf("instance %d belongs to study %q, not %q", i, uid, m.StudyInstanceUID)
+		}
+
+		var buf bytes.Buffer
+		if _, err := Write(&buf, ds); err != nil {
+			return nil, fmt.Errorf("instance %d: %w", i, err)
+		}
+		sum := sha256.Sum256(buf.Bytes())
+		entry.Size = int64(buf.Len())
+		entry.SHA256 = hex.EncodeToString(sum[:])
+		if err := aw.add(entry.Path, buf.Bytes(), m.Created); err != nil {
+			return nil, err
+		}
+		m.Instances = append(m.Instances, entry)
+	}
+
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := aw.add(ManifestName, manifest, m.Created); err != nil {
+		return nil, err
+	}
+	return m, aw.close()
+}
+
+// ImportStudyArchive reads a study archive written by ExportStudyArchive and
+// validates it against its manifest: every listed instance must be present
+// with its size and hash, parse, and carry the UIDs recorded, and nothing
+// else may be in the archive. Validation failures wrap ErrManifestMismatch.
+// Instances are returned in manifest order.
+func ImportStudyArchive(r io.Reader, format ArchiveFormat) ([]*Dataset, *ArchiveManifest, error) {
+	files := make(map[string][]byte)
+	var order []string
+	err := readArchive(r, format, func(name string, data []byte) error {
+		if _, dup := files[name]; dup {
+			return fmt.Errorf("%w: %s is repeated", ErrManifestMismatch, name)
+		}
+		files[name] = data
+		order = append(order, name)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	raw, ok := files[ManifestName]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: no %s", ErrManifestMismatch, ManifestName)
+	}
+	var m ArchiveManifest
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, nil, fmt.Errorf("reading %s: %w", ManifestName, err)
+	}
+	listed := map[string]bool{ManifestName: true}
+	out := make([]*Dataset, 0, len(m.Instances))
+	for _, entry := range m.Instances {
+		listed[entry.Path] = true
+		data, ok := files[entry.Path]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: %s is missing", ErrManifestMismatch, entry.Path)
+		}
+		sum := sha256.Sum256(data)
+		if int64(len(data)) != entry.Size || hex.EncodeToString(sum[:]) != entry.SHA256 {
+			return nil, nil, fmt.Errorf("%w: %s does not match its size or hash", ErrManifestMismatch, entry.Path)
+		}
+		ds, err := ReadBuffer(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", entry.Path, err)
+		}
+		got := archiveEntry(ds)
+		if got.SOPInstanceUID != entry.SOPInstanceUID || got.SOPClassUID != entry.SOPClassUID {
+			return nil, nil, fmt.Errorf("%w: %s holds SOP instance %s", ErrManifestMismatch, entry.Path, got.SOPInstanceUID)
+		}
+		if uid := stringValue(ds, tag.StudyInstanceUID); uid != m.StudyInstanceUID {
+			return nil, nil, fmt.Errorf("%w: %s belongs to study %q", ErrManifestMismatch, entry.Path, uid)
+		}
+		out = append(out, ds)
+	}
+	for _, name := range order {
+		if !listed[name] {
+			return nil, nil, fmt.Errorf("%w: %s is not listed", ErrManifestMismatch, name)
+		}
+	}
+	return out, &m, nil
+}
+
+// archiveEntry describes ds, without its size and hash
+func archiveEntry(ds *Dataset) ArchiveEntry {
+	e := ArchiveEntry{
+		SOPClassUID:       stringValue(ds, tag.SOPClassUID),
+		SOPInstanceUID:    stringValue(ds, tag.SOPInstanceUID),
+		SeriesInstanceUID: stringValue(ds, tag.SeriesInstanceUID),
+		TransferSyntaxUID: string(ds.TransferSyntax()),
+		Frames:            ds.NumberOfFrames(),
+	}
+	if ds.TransferSyntax().IsEncapsulated() {
+		if c := CodecByTransferSyntax(e.TransferSyntaxUID); c != nil {
+			e.Codec = c.Name()
+		}
+	}
+	e.Path = path.Join(e.SeriesInstanceUID, e.SOPInstanceUID+GetExtension())
+	return e
+}
+
+// archiveWriter adds files to a zip or tar archive
+type archiveWriter struct {
+	add   func(name string, data []byte, modified time.Time) error
+	close func() error
+}
+
+func newArchiveWriter(w io.Writer, format ArchiveFormat) (*archiveWriter, error) {
+	switch format {
+	case ArchiveZip:
+		zw := zip.NewWriter(w)
+		return &archiveWriter{
+			add: func(name string, data []byte, modified time.Time) error {
+				f, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+				if err != nil {
+					return err
+				}
+				_, err = f.Write(data)
+				return err
+			},
+			close: zw.Close,
+		}, nil
+	case ArchiveTar:
+		tw := tar.NewWriter(w)
+		return &archiveWriter{
+			add: func(name string, data []byte, modified time.Time) error {
+				hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), ModTime: modified, Format: tar.FormatPAX}
+				if err := tw.WriteHeader(hdr); err != nil {
+					return err
+				}
+				_, err := tw.Write(data)
+				return err
+			},
+			close: tw.Close,
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown archive format %q (zip, tar)", format)
+}
+
+// readArchive calls visit with the name and content of every regular file
+func readArchive(r io.Reader, format ArchiveFormat, visit func(name string, data []byte) error) error {
+	switch format {
+	case ArchiveZip:
+		// zip needs random access to its central directory
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+		if err != nil {
+			return err
+		}
+		for _, f := range zr.File {
+			if f.FileInfo().IsDir() {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return err
+			}
+			data, err := io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				return fmt.Errorf("%s: %w", f.Name, err)
+			}
+			if err := visit(f.Name, data); err != nil {
+				return err
+			}
+		}
+		return nil
+	case ArchiveTar:
+		tr := tar.NewReader(r)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if hdr.Typeflag != tar.TypeReg {
+				continue
+			}
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return fmt.Errorf("%s: %w", hdr.Name, err)
+			}
+			if err := visit(hdr.Name, data); err != nil {
+				return err
+			}
+		}
+	}
+	return fmt.Errorf("unknown archive format %q (zip, tar)", format)
+}
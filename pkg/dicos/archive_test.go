@@ -0,0 +1,135 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// studyInstances returns a native and a JPEG-LS CT of one study, as read back
+func studyInstances(t *testing.T) []*Dataset {
+	t.Helper()
+	study := GenerateUID("1.2.826.0.1.3680043.8.498.")
+	var out []*Dataset
+	for _, codec := range []Codec{nil, CodecJPEGLS} {
+		ct := NewCTImage()
+		ct.Codec = codec
+		ct.Study.StudyInstanceUID = study
+		ct.Rows, ct.Columns = 4, 4
+		data := make([]uint16, 4*4*3)
+		for i := range data {
+			data[i] = uint16(i)
+		}
+		ct.SetPixelData(4, 4, data)
+		var buf bytes.Buffer
+		_, err := ct.WriteTo(&buf)
+		require.NoError(t, err)
+		ds, err := ReadBufferContext(context.Background(), buf.Bytes())
+		require.NoError(t, err)
+		out = append(out, ds)
+	}
+	return out
+}
+
+func TestStudyArchive_RoundTrip(t *testing.T) {
+	study := studyInstances(t)
+	for _, format := range []ArchiveFormat{ArchiveZip, ArchiveTar} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			m, err := ExportStudyArchive(study, &buf, format)
+			require.NoError(t, err)
+			require.Len(t, m.Instances, 2)
+			assert.Equal(t, stringValue(study[0], tag.StudyInstanceUID), m.StudyInstanceUID)
+			assert.Empty(t, m.Instances[0].Codec)
+			assert.Equal(t, "jpeg-ls", m.Instances[1].Codec)
+			assert.Equal(t, 3, m.Instances[1].Frames)
+			assert.Len(t, m.Instances[1].SHA256, 64)
+
+			got, gotManifest, err := ImportStudyArchive(bytes.NewReader(buf.Bytes()), format)
+			require.NoError(t, err)
+			assert.Equal(t, m.Instances, gotManifest.Instances)
+			require.Len(t, got, 2)
+			for i := range got {
+				assert.Equal(t, stringValue(study[i], tag.SOPInstanceUID), stringValue(got[i], tag.SOPInstanceUID))
+				want, err := DecodeVolume(study[i])
+				require.NoError(t, err)
+				vol, err := DecodeVolume(got[i])
+				require.NoError(t, err)
+				assert.Equal(t, want.Data, vol.Data)
+			}
+		})
+	}
+}
+
+func TestStudyArchive_Export_Rejects(t *testing.T) {
+	study := studyInstances(t)
+	_, err := ExportStudyArchive(nil, &bytes.Buffer{}, ArchiveZip)
+	assert.Error(t, err)
+	_, err = ExportStudyArchive(study, &bytes.Buffer{}, "rar")
+	assert.ErrorContains(t, err, "unknown archive format")
+	_, err = ExportStudyArchive([]*Dataset{study[0], study[0]}, &bytes.Buffer{}, ArchiveZip)
+	assert.ErrorContains(t, err, "repeated")
+
+	other := studyInstances(t)
+	_, err = ExportStudyArchive([]*Dataset{study[0], other[1]}, &bytes.Buffer{}, ArchiveTar)
+	assert.ErrorContains(t, err, "belongs to study")
+}
+
+func TestStudyArchive_Import_Tampered(t *testing.T) {
+	var buf bytes.Buffer
+	m, err := ExportStudyArchive(studyInstances(t), &buf, ArchiveTar)
+	require.NoError(t, err)
+	first := m.Instances[0].Path
+
+	// tamper rewrites the archive, changing its files with edit
+	tamper := func(edit func(files map[string][]byte)) []byte {
+		files := map[string][]byte{}
+		var names []string
+		require.NoError(t, readArchive(bytes.NewReader(buf.Bytes()), ArchiveTar, func(name string, data []byte) error {
+			files[name] = data
+			names = append(names, name)
+			return nil
+		}))
+		edit(files)
+		var out bytes.Buffer
+		aw, err := newArchiveWriter(&out, ArchiveTar)
+		require.NoError(t, err)
+		for _, name := range append(names, "extra.txt") {
+			if data, ok := files[name]; ok {
+				require.NoError(t, aw.add(name, data, m.Created))
+			}
+		}
+		require.NoError(t, aw.close())
+		return out.Bytes()
+	}
+
+	tests := map[string]func(files map[string][]byte){
+		"modified instance": func(files map[string][]byte) {
+			files[first] = append([]byte(nil), files[first]...)
+			files[first][len(files[first])-1] ^= 0xFF
+		},
+		"missing instance": func(files map[string][]byte) { delete(files, first) },
+		"unlisted file":    func(files map[string][]byte) { files["extra.txt"] = []byte("hello") },
+		"missing manifest": func(files map[string][]byte) { delete(files, ManifestName) },
+		"swapped instances": func(files map[string][]byte) {
+			second := m.Instances[1].Path
+			files[first], files[second] = files[second], files[first]
+			var mm ArchiveManifest
+			require.NoError(t, json.Unmarshal(files[ManifestName], &mm))
+			mm.Instances[0].SHA256, mm.Instances[1].SHA256 = mm.Instances[1].SHA256, mm.Instances[0].SHA256
+			mm.Instances[0].Size, mm.Instances[1].Size = mm.Instances[1].Size, mm.Instances[0].Size
+			files[ManifestName], _ = json.Marshal(mm)
+		},
+	}
+	for name, edit := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := ImportStudyArchive(bytes.NewReader(tamper(edit)), ArchiveTar)
+			assert.ErrorIs(t, err, ErrManifestMismatch)
+		})
+	}
+}
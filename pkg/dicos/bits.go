@@ -0,0 +1,84 @@
+package dicos
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// ErrInvalidImagePixel is matched by errors.Is when the Image Pixel
+// attributes describing the samples contradict each other
+var ErrInvalidImagePixel = errors.New("invalid image pixel attributes")
+
+// ValidateImagePixel checks that the Image Pixel attributes of ds describe
+// samples a decoder can read: BitsAllocated 1 or a multiple of 8, BitsStored
+// from 1 to BitsAllocated, HighBit one less than BitsStored and
+// PixelRepresentation 0 or 1. Absent attributes take their defaults (see
+// Dataset.BitsAllocated). Failures wrap ErrInvalidImagePixel.
+//
+// It has the signature of an Option, so the IOD builders apply it before
+// pixel data is encoded:
+//
+//	ds, err := dicos.NewDataset(
+//		dicos.WithElement(tag.BitsAllocated, uint16(16)),
+//		dicos.WithElement(tag.BitsStored, uint16(12)),
+//		dicos.WithElement(tag.HighBit, uint16(11)),
+//		dicos.ValidateImagePixel,
+//		dicos.WithPixelData(rows, cols, 16, data, dicos.CodecJPEGLS),
+//	)
+func ValidateImagePixel(ds *Dataset) error {
+	allocated, stored := ds.BitsAllocated(), ds.BitsStored()
+	switch {
+	case allocated != 1 && (allocated <= 0 || allocated%8 != 0):
+		return fmt.Errorf("%w: bits allocated %d is not 1 or a multiple of 8", ErrInvalidImagePixel, allocated)
+	case stored < 1 || stored > allocated:
+		return fmt.Errorf("%w: bits stored %d is not between 1 and bits allocated %d", ErrInvalidImagePixel, stored, allocated)
+	}
+	if elem, ok := ds.FindElement(tag.HighBit.Group, tag.HighBit.Element); ok {
+		if hb, ok := elem.GetInt(); ok && hb != stored-1 {
+			return fmt.Errorf("%w: high bit %d is not bits stored %d - 1", ErrInvalidImagePixel, hb, stored)
+		}
+	}
+	if pr := ds.PixelRepresentation(); pr != 0 && pr != 1 {
+		return fmt.Errorf("%w: pixel representation %d is not 0 or 1", ErrInvalidImagePixel, pr)
+	}
+	return nil
+}
+
+// RequiredBitsStored returns the fewest bits that store every sample of
+// data, at least 1. Signed samples are read as int16, as decoded (see
+// Int16Samples), and need a sign bit.
+//
+// Example:
+//
+//	ct.BitsStored = uint16(dicos.RequiredBitsStored(data, false)) // 12 for 0-4095
+func RequiredBitsStored(data []uint16, signed bool) int {
+	n := 1
+	for _, v := range data {
+		if !signed {
+			n = max(n, bits.Len16(v))
+			continue
+		}
+		if s := int16(v); s < 0 {
+			n = max(n, bits.Len16(uint16(^s))+1)
+		} else {
+			n = max(n, bits.Len16(v)+1)
+		}
+	}
+	return n
+}
+
+// requiredBitsStored returns RequiredBitsStored for native pixel data, or 0
+// when pd is absent or encapsulated
+func requiredBitsStored(pd *PixelData, signed bool) int {
+	if pd == nil || pd.IsEncapsulated {
+		return 0
+	}
+	n := 1
+	for _, f := range pd.Frames {
+		n = max(n, RequiredBitsStored(f.Data, signed))
+	}
+	return n
+}
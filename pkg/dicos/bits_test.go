@@ -0,0 +1,96 @@
+package dicos
+
+import (
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateImagePixel(t *testing.T) {
+	for _, tc := range []struct {
+		allocated, stored, high, representation uint16
+		want                                    string
+	}{
+		{allocated: 16, stored: 16, high: 15},
+		{allocated: 16, stored: 12, high: 11, representation: 1},
+		{allocated: 8, stored: 8, high: 7},
+		{allocated: 1, stored: 1, high: 0},
+		{allocated: 12, stored: 12, high: 11, want: "not 1 or a multiple of 8"},
+		{allocated: 8, stored: 12, high: 11, want: "bits stored 12 is not between 1 and bits allocated 8"},
+		{allocated: 16, stored: 0, high: 0, want: "bits stored 0"},
+		{allocated: 16, stored: 12, high: 15, want: "high bit 15 is not bits stored 12 - 1"},
+		{allocated: 16, stored: 16, high: 15, representation: 2, want: "pixel representation 2"},
+	} {
+		ds, err := NewDataset(
+			WithElement(tag.BitsAllocated, tc.allocated),
+			WithElement(tag.BitsStored, tc.stored),
+			WithElement(tag.HighBit, tc.high),
+			WithElement(tag.PixelRepresentation, tc.representation),
+		)
+		require.NoError(t, err)
+		err = ValidateImagePixel(ds)
+		if tc.want == "" {
+			assert.NoError(t, err, "%+v", tc)
+			continue
+		}
+		assert.ErrorIs(t, err, ErrInvalidImagePixel)
+		assert.ErrorContains(t, err, tc.want)
+	}
+}
+
+func TestRequiredBitsStored(t *testing.T) {
+	assert.Equal(t, 1, RequiredBitsStored(nil, false))
+	assert.Equal(t, 1, RequiredBitsStored([]uint16{0, 1}, false))
+	assert.Equal(t, 12, RequiredBitsStored([]uint16{0, 4095}, false))
+	assert.Equal(t, 13, RequiredBitsStored([]uint16{4096}, false))
+	assert.Equal(t, 16, RequiredBitsStored([]uint16{0xFFFF}, false))
+	assert.Equal(t, 1, RequiredBitsStored(Uint16Samples([]int16{-1, 0}), true))
+	assert.Equal(t, 12, RequiredBitsStored(Uint16Samples([]int16{-2048, 2047}), true))
+	assert.Equal(t, 13, RequiredBitsStored(Uint16Samples([]int16{-2049}), true))
+	assert.Equal(t, 13, RequiredBitsStored(Uint16Samples([]int16{2048}), true))
+}
+
+// TestImageBuilders_ImagePixel checks that the CT and DX builders refuse
+// inconsistent attributes and derive BitsStored when asked
+func TestImageBuilders_ImagePixel(t *testing.T) {
+	data := make([]uint16, 16)
+	for i := range data {
+		data[i] = uint16(i * 200) // up to 3000, 12 bits
+	}
+
+	ct := NewCTImage()
+	ct.SetPixelData(4, 4, data)
+	ct.Image.KV[tag.HighBit] = uint16(11)
+	_, err := ct.GetDataset()
+	assert.ErrorIs(t, err, ErrInvalidImagePixel)
+
+	ct.DeriveBitsStored = true
+	ds, err := ct.GetDataset()
+	require.NoError(t, err)
+	assert.Equal(t, 12, ds.BitsStored())
+	hb, _ := ds.Elements[tag.HighBit].GetInt()
+	assert.Equal(t, 11, hb)
+
+	ct = NewCTImage()
+	hu := make([]int16, 16)
+	hu[0], hu[1] = -1000, 1000
+	ct.SetSignedPixelData(4, 4, hu)
+	ct.DeriveBitsStored = true
+	ds, err = ct.GetDataset()
+	require.NoError(t, err)
+	assert.Equal(t, 11, ds.BitsStored())
+
+	dx := NewDXImage()
+	dx.SetPixelData(4, 4, data)
+	dx.BitsStored = 20
+	_, err = dx.GetDataset()
+	assert.ErrorContains(t, err, "bits stored 20")
+
+	dx.DeriveBitsStored = true
+	ds, err = dx.GetDataset()
+	require.NoError(t, err)
+	assert.Equal(t, 12, ds.BitsStored())
+	assert.Equal(t, 11, dx.HighBit)
+}
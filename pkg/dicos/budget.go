@@ -0,0 +1,108 @@
+package dicos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrResourceLimit is matched by errors.Is when an operation would allocate
+// more than its MemoryBudget allows.
+var ErrResourceLimit = errors.New("resource limit exceeded")
+
+// ResourceKind names what an accounted allocation holds
+type ResourceKind string
+
+const (
+	ResourceElement ResourceKind = "element" // element value read while parsing
+	ResourceFrame   ResourceKind = "frame"   // pixel data fragment or decoded frame
+	ResourceVolume  ResourceKind = "volume"  // decoded multi-frame volume
+)
+
+// ResourceLimitError reports the allocation that would have exceeded a
+// MemoryBudget. It matches ErrResourceLimit with errors.Is.
+type ResourceLimitError struct {
+	Kind      ResourceKind
+	Requested int64 // bytes the allocation needed
+	Used      int64 // bytes already accounted
+	Limit     int64
+}
+
+func (e *ResourceLimitError) Error() string {
+	return fmt.Sprintf("%s allocation of %d bytes exceeds budget (%d of %d used)", e.Kind, e.Requested, e.Used, e.Limit)
+}
+
+func (e *ResourceLimitError) Unwrap() error {
+	return ErrResourceLimit
+}
+
+// MemoryBudget accounts the bytes allocated for elements, frames and volumes
+// by one operation and fails allocations past Limit, so a service decoding
+// untrusted files can contain a pathological input to its own request. A
+// budget is safe for concurrent use; share one across a parse and the decodes
+// that follow it to bound the whole operation.
+type MemoryBudget struct {
+	// Limit is the ceiling in bytes. Zero only accounts.
+	Limit int64
+	// OnAlloc, if set, is called for every accounted allocation, e.g. to
+	// feed metrics. It is not called for refused allocations.
+	OnAlloc func(kind ResourceKind, n int64)
+
+	mu   sync.Mutex
+	used int64
+}
+
+// Reserve accounts n bytes of kind, or returns a *ResourceLimitError
+// without accounting them if that would exceed the limit.
+func (b *MemoryBudget) Reserve(kind ResourceKind, n int64) error {
+	b.mu.Lock()
+	if b.Limit > 0 && (n > b.Limit || b.used > b.Limit-n) {
+		err := &ResourceLimitError{Kind: kind, Requested: n, Used: b.used, Limit: b.Limit}
+		b.mu.Unlock()
+		return err
+	}
+	b.used += n
+	b.mu.Unlock()
+	if b.OnAlloc != nil {
+		b.OnAlloc(kind, n)
+	}
+	return nil
+}
+
+// Used returns the bytes accounted so far
+func (b *MemoryBudget) Used() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.used
+}
+
+type memoryBudgetKey struct{}
+
+// WithMemoryBudget returns a context whose parses and decodes account their
+// allocations against b. Element values and pixel data fragments are checked
+// before they are read, so a forged length fails fast instead of allocating.
+//
+// Example:
+//
+//	budget := &dicos.MemoryBudget{Limit: 512 << 20}
+//	ctx := dicos.WithMemoryBudget(r.Context(), budget)
+//	ds, err := dicos.ParseContext(ctx, body)
+//	if err == nil {
+//		vol, err = dicos.DecodeVolumeContext(ctx, ds)
+//	}
+//	if errors.Is(err, dicos.ErrResourceLimit) {
+//		http.Error(w, "scan too large", http.StatusRequestEntityTooLarge)
+//	}
+func WithMemoryBudget(ctx context.Context, b *MemoryBudget) context.Context {
+	return context.WithValue(ctx, memoryBudgetKey{}, b)
+}
+
+// reserveMemory accounts n bytes against the budget carried by ctx, if any
+func reserveMemory(ctx context.Context, kind ResourceKind, n int64) error {
+	b, _ := ctx.Value(memoryBudgetKey{}).(*MemoryBudget)
+	if b == nil {
+		return nil
+	}
+	return b.Reserve(kind, n)
+}
@@ -0,0 +1,81 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryBudget_ParseAndDecode(t *testing.T) {
+	data := writeTestCT(t, 32, 32, 1, nil)
+
+	kinds := map[ResourceKind]int64{}
+	budget := &MemoryBudget{OnAlloc: func(kind ResourceKind, n int64) { kinds[kind] += n }}
+	ctx := WithMemoryBudget(context.Background(), budget)
+
+	ds, err := ParseContext(ctx, bytes.NewReader(data))
+	require.NoError(t, err)
+	_, err = DecodeVolumeContext(ctx, ds)
+	require.NoError(t, err)
+
+	assert.Positive(t, kinds[ResourceElement])
+	assert.GreaterOrEqual(t, kinds[ResourceFrame], int64(2*32*32))
+	assert.Equal(t, int64(2*32*32), kinds[ResourceVolume])
+	assert.Equal(t, kinds[ResourceElement]+kinds[ResourceFrame]+kinds[ResourceVolume], budget.Used())
+}
+
+func TestMemoryBudget_Limit(t *testing.T) {
+	data := writeTestCT(t, 32, 32, 1, nil)
+
+	// Enough to parse, not enough to also decode the volume
+	parsed := &MemoryBudget{}
+	ds, err := ParseContext(WithMemoryBudget(context.Background(), parsed), bytes.NewReader(data))
+	require.NoError(t, err)
+
+	budget := &MemoryBudget{Limit: parsed.Used() + 100}
+	ctx := WithMemoryBudget(context.Background(), budget)
+	ds, err = ParseContext(ctx, bytes.NewReader(data))
+	require.NoError(t, err)
+	_, err = DecodeVolumeContext(ctx, ds)
+	require.ErrorIs(t, err, ErrResourceLimit)
+
+	var le *ResourceLimitError
+	require.True(t, errors.As(err, &le))
+	assert.Equal(t, budget.Limit, le.Limit)
+	assert.LessOrEqual(t, budget.Used(), budget.Limit)
+
+	// Too small for the pixel data itself
+	ctx = WithMemoryBudget(context.Background(), &MemoryBudget{Limit: 1024})
+	_, err = ParseContext(ctx, bytes.NewReader(data))
+	require.ErrorIs(t, err, ErrResourceLimit)
+	require.True(t, errors.As(err, &le))
+	assert.Equal(t, ResourceFrame, le.Kind)
+}
+
+func TestMemoryBudget_ForgedLength(t *testing.T) {
+	// A private OB element claiming 3 GiB in a tiny file must fail on the
+	// budget before the reader tries to allocate it
+	forged := binary.LittleEndian.AppendUint16(nil, 0x0009)
+	forged = binary.LittleEndian.AppendUint16(forged, 0x1010)
+	forged = append(forged, "OB\x00\x00"...)
+	forged = binary.LittleEndian.AppendUint32(forged, 3<<30)
+	file := rawFile(
+		rawExplicit(0x0002, 0x0010, "UI", []byte("1.2.840.10008.1.2.1\x00")),
+		rawExplicit(0x0008, 0x0060, "CS", []byte("CT")),
+		forged,
+	)
+
+	ctx := WithMemoryBudget(context.Background(), &MemoryBudget{Limit: 64 << 20})
+	_, err := ParseContext(ctx, bytes.NewReader(file))
+	require.ErrorIs(t, err, ErrResourceLimit)
+
+	var le *ResourceLimitError
+	require.True(t, errors.As(err, &le))
+	assert.Equal(t, ResourceElement, le.Kind)
+	assert.Equal(t, int64(3<<30), le.Requested)
+}
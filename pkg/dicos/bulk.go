@@ -0,0 +1,197 @@
+package dicos
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrBulkDataExcluded is returned when reading a bulk payload that was dropped on read
+var ErrBulkDataExcluded = errors.New("bulk data was excluded on read")
+
+// BulkData is a large binary value, such as a vendor raw sinogram carried in a
+// private OB element, that is referenced in its source instead of held in memory.
+//
+// The reader produces BulkData for private OB/OW/UN elements at or above
+// ParseOptions.BulkDataThreshold. The payload stays readable for as long as the
+// source (typically an *os.File) remains open. The writer streams BulkData
+// values straight from the source and omits excluded ones.
+type BulkData struct {
+	Offset int64 // value offset in the source
+	Length int64 // value length in bytes
+	src    io.ReaderAt
+}
+
+// NewBulkData references length bytes at offset in src, for attaching large
+// payloads to a dataset without loading them
+func NewBulkData(src io.ReaderAt, offset, length int64) *BulkData {
+	return &BulkData{Offset: offset, Length: length, src: src}
+}
+
+// Excluded returns true if the payload was dropped on read and cannot be accessed
+func (b *BulkData) Excluded() bool {
+	return b.src == nil
+}
+
+// Open returns a reader over the payload
+func (b *BulkData) Open() (io.Reader, error) {
+	if b.Excluded() {
+		return nil, ErrBulkDataExcluded
+	}
+	return io.NewSectionReader(b.src, b.Offset, b.Length), nil
+}
+
+// WriteTo streams the payload to w without buffering it
+func (b *BulkData) WriteTo(w io.Writer) (int64, error) {
+	r, err := b.Open()
+	if err != nil {
+		return 0, err
+	}
+	return io.Copy(w, r)
+}
+
+// Bytes loads the whole payload into memory
+func (b *BulkData) Bytes() ([]byte, error) {
+	r, err := b.Open()
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, b.Length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// String describes the payload without reading it
+func (b *BulkData) String() string {
+	if b.Excluded() {
+		return fmt.Sprintf("Bulk Data (%d bytes, excluded)", b.Length)
+	}
+	return fmt.Sprintf("Bulk Data (%d bytes at offset %d)", b.Length, b.Offset)
+}
+
+// MarshalJSON describes the payload without reading it
+func (b *BulkData) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Offset   int64 `json:"offset"`
+		Length   int64 `json:"length"`
+		Excluded bool  `json:"excluded"`
+	}{b.Offset, b.Length, b.Excluded()})
+}
+
+// isBulkCandidate returns true if an element should be read as BulkData
+func (r *Reader) isBulkCandidate(tag Tag, vr string, vl uint32) bool {
+	if r.opts.BulkDataThreshold <= 0 || vl == 0xFFFFFFFF || !tag.IsPrivate() {
+		return false
+	}
+	switch vr {
+	case "OB", "OW", "UN":
+		return int64(vl) >= r.opts.BulkDataThreshold
+	}
+	return false
+}
+
+// readBulkData records the position of a bulk value and skips over it.
+// Sources that are not an io.ReaderAt cannot be revisited, so their payloads are excluded.
+func (r *Reader) readBulkData(vl uint32) (*BulkData, error) {
+	bd := &BulkData{Offset: r.cr.n, Length: int64(vl)}
+	if ra, ok := r.src.(io.ReaderAt); ok && !r.opts.ExcludeBulkData {
+		bd.src = ra
+	}
+	if err := r.cr.skip(int64(vl)); err != nil {
+		return nil, err
+	}
+	return bd, nil
+}
+
+// countingReader tracks the offset of the next byte read from the source and
+// supports a small lookahead for sniffing encodings
+type countingReader struct {
+	r         io.Reader
+	n         int64
+	pending   []byte // bytes peeked but not yet consumed
+	recording bool
+	rec       []byte // bytes read since record was called
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	if len(c.pending) > 0 {
+		n := copy(p, c.pending)
+		c.pending = c.pending[n:]
+		c.n += int64(n)
+		c.keep(p[:n])
+		return n, nil
+	}
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	c.keep(p[:n])
+	return n, err
+}
+
+// record starts keeping a copy of the bytes read, replacing any earlier recording
+func (c *countingReader) record() {
+	c.recording, c.rec = true, c.rec[:0]
+}
+
+func (c *countingReader) keep(p []byte) {
+	if c.recording {
+		c.rec = append(c.rec, p...)
+	}
+}
+
+// peek returns the next n bytes without consuming them
+func (c *countingReader) peek(n int) ([]byte, error) {
+	for len(c.pending) < n {
+		buf := make([]byte, n-len(c.pending))
+		read, err := c.r.Read(buf)
+		c.pending = append(c.pending, buf[:read]...)
+		if err != nil {
+			return c.pending, err
+		}
+	}
+	return c.pending[:n], nil
+}
+
+// skip advances n bytes, seeking when the source allows it. It fails with
+// io.ErrUnexpectedEOF when fewer than n bytes remain. Recorded bytes are read
+// rather than sought over, so they can be kept as trailing data.
+func (c *countingReader) skip(n int64) error {
+	if k := min(n, int64(len(c.pending))); k > 0 {
+		c.keep(c.pending[:k])
+		c.pending = c.pending[k:]
+		c.n += k
+		n -= k
+	}
+	if s, ok := c.r.(io.Seeker); ok && !c.recording {
+		return c.seek(s, n)
+	}
+	copied, err := io.CopyN(io.Discard, c, n)
+	if err == io.EOF || err == nil && copied < n {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// seek advances n bytes within the size of s
+func (c *countingReader) seek(s io.Seeker, n int64) error {
+	cur, err := s.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	end, err := s.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if cur+n > end {
+		// left at the end, as a short read would
+		c.n += end - cur
+		return io.ErrUnexpectedEOF
+	}
+	if _, err := s.Seek(cur+n, io.SeekStart); err != nil {
+		return err
+	}
+	c.n += n
+	return nil
+}
@@ -0,0 +1,119 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSinogramTag = Tag{Group: 0x0009, Element: 0x1010}
+
+func writeBulkTestFile(t *testing.T, payload []byte) []byte {
+	t.Helper()
+	ds, err := NewDataset(
+		WithFileMeta(DICOSCTImageStorageUID, "1.2.3.4", string(ExplicitVRLittleEndian)),
+		WithElement(testSinogramTag, payload),
+	)
+	require.NoError(t, err)
+	ds.Elements[testSinogramTag].VR = "OB"
+
+	var buf bytes.Buffer
+	_, err = Write(&buf, ds)
+	require.NoError(t, err)
+	return buf.Bytes()
+}
+
+func TestParseWithOptions_LazyBulkData(t *testing.T) {
+	payload := make([]byte, 4096)
+	for i := range payload {
+		payload[i] = byte(i * 7)
+	}
+	file := writeBulkTestFile(t, payload)
+
+	ds, err := ParseWithOptions(context.Background(), bytes.NewReader(file), ParseOptions{BulkDataThreshold: 1024})
+	require.NoError(t, err)
+
+	elem, ok := ds.FindElement(testSinogramTag.Group, testSinogramTag.Element)
+	require.True(t, ok)
+	bd, ok := elem.Value.(*BulkData)
+	require.True(t, ok, "expected *BulkData, got %T", elem.Value)
+	assert.False(t, bd.Excluded())
+	assert.Equal(t, int64(len(payload)), bd.Length)
+
+	data, err := bd.Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, payload, data)
+
+	// Streaming the dataset back out reproduces the payload
+	var out bytes.Buffer
+	_, err = Write(&out, ds)
+	require.NoError(t, err)
+	reread, err := ReadBuffer(out.Bytes())
+	require.NoError(t, err)
+	elem, ok = reread.FindElement(testSinogramTag.Group, testSinogramTag.Element)
+	require.True(t, ok)
+	assert.Equal(t, payload, elem.Value)
+}
+
+func TestParseWithOptions_ExcludeBulkData(t *testing.T) {
+	file := writeBulkTestFile(t, make([]byte, 2048))
+
+	ds, err := ParseWithOptions(context.Background(), bytes.NewReader(file), ParseOptions{
+		BulkDataThreshold: 1024,
+		ExcludeBulkData:   true,
+	})
+	require.NoError(t, err)
+
+	elem, ok := ds.FindElement(testSinogramTag.Group, testSinogramTag.Element)
+	require.True(t, ok)
+	bd := elem.Value.(*BulkData)
+	assert.True(t, bd.Excluded())
+	_, err = bd.Bytes()
+	assert.ErrorIs(t, err, ErrBulkDataExcluded)
+
+	var out bytes.Buffer
+	_, err = Write(&out, ds)
+	require.NoError(t, err)
+	reread, err := ReadBuffer(out.Bytes())
+	require.NoError(t, err)
+	assert.False(t, HasElement(reread, testSinogramTag), "excluded payloads are stripped on write")
+}
+
+func TestParseWithOptions_BulkDataFromStream(t *testing.T) {
+	file := writeBulkTestFile(t, make([]byte, 2048))
+
+	// A plain io.Reader cannot be revisited, so the payload is excluded
+	ds, err := ParseWithOptions(context.Background(), bytes.NewBuffer(file), ParseOptions{BulkDataThreshold: 1024})
+	require.NoError(t, err)
+	elem, _ := ds.FindElement(testSinogramTag.Group, testSinogramTag.Element)
+	assert.True(t, elem.Value.(*BulkData).Excluded())
+	assert.True(t, HasElement(ds, Tag{Group: 0x0002, Element: 0x0010}))
+}
+
+func TestParseWithOptions_TruncatedBulkData(t *testing.T) {
+	file := writeBulkTestFile(t, make([]byte, 4096))
+	short := file[:len(file)-1000]
+
+	_, err := ParseWithOptions(context.Background(), bytes.NewReader(short), ParseOptions{BulkDataThreshold: 1024})
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF, "seekable source")
+	_, err = ParseWithOptions(context.Background(), bytes.NewBuffer(short), ParseOptions{BulkDataThreshold: 1024})
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF, "stream")
+
+	// after the pixel data, a forged bulk length keeps the rest as trailing data
+	forged := rawExplicitLong(0x7FE1, 0x1010, "OB", nil)
+	binary.LittleEndian.PutUint32(forged[8:], 4096)
+	forged = append(forged, bytes.Repeat([]byte{0xAB}, 100)...)
+	file = rawFile(
+		rawExplicit(0x0002, 0x0010, "UI", []byte("1.2.840.10008.1.2.1\x00")),
+		rawExplicitLong(0x7FE0, 0x0010, "OW", make([]byte, 8)),
+		forged,
+	)
+	ds, err := ParseWithOptions(context.Background(), bytes.NewReader(file), ParseOptions{BulkDataThreshold: 64})
+	require.NoError(t, err)
+	assert.Equal(t, forged, ds.Trailing)
+}
@@ -0,0 +1,280 @@
+package dicos
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrNotRecipient is returned when no envelope is addressed to a decryption key
+var ErrNotRecipient = errors.New("not a recipient of the encrypted content")
+
+// Object identifiers of the CMS (RFC 5652) envelopes written and read here
+var (
+	oidData          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidEnvelopedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 3}
+	oidRSAEncryption = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	oidRSAESOAEP     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 7}
+	oidMGF1          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 8}
+	oidSHA1          = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+	oidSHA256        = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidAES128CBC     = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC     = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC     = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+// cmsContentInfo is the outer ContentInfo; Content is the [0] wrapper
+type cmsContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue
+}
+
+// cmsEnvelopedData is EnvelopedData without originator info or
+// unprotected attributes. Recipient infos are kept raw since only key
+// transport recipients are understood.
+type cmsEnvelopedData struct {
+	Version              int
+	RecipientInfos       []asn1.RawValue `asn1:"set"`
+	EncryptedContentInfo cmsEncryptedContentInfo
+}
+
+type cmsKeyTransRecipientInfo struct {
+	Version                int
+	RID                    cmsIssuerAndSerial
+	KeyEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedKey           []byte
+}
+
+type cmsIssuerAndSerial struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+type cmsEncryptedContentInfo struct {
+	ContentType                asn1.ObjectIdentifier
+	ContentEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedContent           []byte `asn1:"optional,tag:0"`
+}
+
+// cmsOAEPParams is RSAES-OAEP-params (RFC 4055); absent fields mean SHA-1
+type cmsOAEPParams struct {
+	Hash pkix.AlgorithmIdentifier `asn1:"optional,explicit,tag:0"`
+	MGF  pkix.AlgorithmIdentifier `asn1:"optional,explicit,tag:1"`
+}
+
+// sealEnvelope encrypts content with a fresh AES-256-CBC key and wraps the
+// key for each RSA recipient with RSAES-OAEP and SHA-256, returning a DER
+// CMS EnvelopedData
+func sealEnvelope(content []byte, recipients []*x509.Certificate) ([]byte, error) {
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("no recipient certificates")
+	}
+	key := make([]byte, 32)
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	pad := aes.BlockSize - len(content)%aes.BlockSize
+	sealed := append(bytes.Clone(content), bytes.Repeat([]byte{byte(pad)}, pad)...)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(sealed, sealed)
+
+	oaep, err := oaepAlgorithm()
+	if err != nil {
+		return nil, err
+	}
+	var infos []asn1.RawValue
+	for _, cert := range recipients {
+		pub, ok := cert.PublicKey.(*rsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("recipient %q has a %T key, only RSA is supported", cert.Subject.CommonName, cert.PublicKey)
+		}
+		wrapped, err := rsa.EncryptOAEP(crypto.SHA256.New(), rand.Reader, pub, key, nil)
+		if err != nil {
+			return nil, fmt.Errorf("wrapping key for %q: %w", cert.Subject.CommonName, err)
+		}
+		info, err := asn1.Marshal(cmsKeyTransRecipientInfo{
+			RID:                    cmsIssuerAndSerial{Issuer: asn1.RawValue{FullBytes: cert.RawIssuer}, SerialNumber: cert.SerialNumber},
+			KeyEncryptionAlgorithm: oaep,
+			EncryptedKey:           wrapped,
+		})
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, asn1.RawValue{FullBytes: info})
+	}
+
+	ivParam, err := asn1.Marshal(iv)
+	if err != nil {
+		return nil, err
+	}
+	enveloped, err := asn1.Marshal(cmsEnvelopedData{
+		RecipientInfos: infos,
+		EncryptedContentInfo: cmsEncryptedContentInfo{
+			ContentType:                oidData,
+			ContentEncryptionAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: ivParam}},
+			EncryptedContent:           sealed,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(cmsContentInfo{
+		ContentType: oidEnvelopedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: enveloped},
+	})
+}
+
+// oaepAlgorithm identifies RSAES-OAEP with SHA-256 and MGF1 with SHA-256
+func oaepAlgorithm() (pkix.AlgorithmIdentifier, error) {
+	sha256 := pkix.AlgorithmIdentifier{Algorithm: oidSHA256, Parameters: asn1.NullRawValue}
+	hash, err := asn1.Marshal(sha256)
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, err
+	}
+	params, err := asn1.Marshal(cmsOAEPParams{
+		Hash: sha256,
+		MGF:  pkix.AlgorithmIdentifier{Algorithm: oidMGF1, Parameters: asn1.RawValue{FullBytes: hash}},
+	})
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, err
+	}
+	return pkix.AlgorithmIdentifier{Algorithm: oidRSAESOAEP, Parameters: asn1.RawValue{FullBytes: params}}, nil
+}
+
+// openEnvelope decrypts a CMS EnvelopedData with the key of the recipient
+// cert. Key transport with RSAES-OAEP or PKCS #1 v1.5 and content
+// encrypted with AES-CBC are understood. It returns ErrNotRecipient when no
+// recipient info names cert.
+func openEnvelope(der []byte, cert *x509.Certificate, key crypto.Decrypter) ([]byte, error) {
+	var ci cmsContentInfo
+	if rest, err := asn1.Unmarshal(der, &ci); err != nil {
+		return nil, fmt.Errorf("parsing CMS content info: %w", err)
+	} else if len(rest) > 0 && !bytes.Equal(rest, []byte{0}) { // OB values are padded to even length
+		return nil, fmt.Errorf("%d bytes after CMS content info", len(rest))
+	}
+	if !ci.ContentType.Equal(oidEnvelopedData) {
+		return nil, fmt.Errorf("CMS content type %v is not enveloped data", ci.ContentType)
+	}
+	var ed cmsEnvelopedData
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &ed); err != nil {
+		return nil, fmt.Errorf("parsing CMS enveloped data: %w", err)
+	}
+
+	var cek []byte
+	for _, raw := range ed.RecipientInfos {
+		var ri cmsKeyTransRecipientInfo
+		if raw.Class != asn1.ClassUniversal || raw.Tag != asn1.TagSequence {
+			continue // key agreement, KEK or password recipients
+		}
+		if _, err := asn1.Unmarshal(raw.FullBytes, &ri); err != nil || ri.RID.SerialNumber == nil {
+			continue // identified by subject key identifier
+		}
+		if !bytes.Equal(ri.RID.Issuer.FullBytes, cert.RawIssuer) || ri.RID.SerialNumber.Cmp(cert.SerialNumber) != 0 {
+			continue
+		}
+		opts, err := keyTransportOptions(ri.KeyEncryptionAlgorithm)
+		if err != nil {
+			return nil, err
+		}
+		if cek, err = key.Decrypt(rand.Reader, ri.EncryptedKey, opts); err != nil {
+			return nil, fmt.Errorf("unwrapping content key: %w", err)
+		}
+		break
+	}
+	if cek == nil {
+		return nil, ErrNotRecipient
+	}
+
+	eci := ed.EncryptedContentInfo
+	alg := eci.ContentEncryptionAlgorithm
+	want := map[string]int{oidAES128CBC.String(): 16, oidAES192CBC.String(): 24, oidAES256CBC.String(): 32}[alg.Algorithm.String()]
+	if want == 0 {
+		return nil, fmt.Errorf("unsupported content encryption algorithm %v", alg.Algorithm)
+	}
+	if len(cek) != want {
+		return nil, fmt.Errorf("content key is %d bytes, %v needs %d", len(cek), alg.Algorithm, want)
+	}
+	var iv []byte
+	if _, err := asn1.Unmarshal(alg.Parameters.FullBytes, &iv); err != nil || len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("invalid AES-CBC initialization vector")
+	}
+	data := eci.EncryptedContent
+	if len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("encrypted content of %d bytes is not whole AES blocks", len(data))
+	}
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(out, data)
+	pad := int(out[len(out)-1])
+	if pad == 0 || pad > aes.BlockSize || !bytes.Equal(out[len(out)-pad:], bytes.Repeat([]byte{byte(pad)}, pad)) {
+		return nil, fmt.Errorf("invalid padding in decrypted content")
+	}
+	return out[:len(out)-pad], nil
+}
+
+// keyTransportOptions returns the decrypter options of an RSA key
+// transport algorithm
+func keyTransportOptions(alg pkix.AlgorithmIdentifier) (crypto.DecrypterOpts, error) {
+	switch {
+	case alg.Algorithm.Equal(oidRSAEncryption):
+		return &rsa.PKCS1v15DecryptOptions{}, nil
+	case alg.Algorithm.Equal(oidRSAESOAEP):
+		var params cmsOAEPParams
+		if len(alg.Parameters.FullBytes) > 0 {
+			if _, err := asn1.Unmarshal(alg.Parameters.FullBytes, &params); err != nil {
+				return nil, fmt.Errorf("parsing RSAES-OAEP parameters: %w", err)
+			}
+		}
+		hash, err := oaepHash(params.Hash.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+		mgfHash := hash
+		if params.MGF.Algorithm != nil {
+			var mgf pkix.AlgorithmIdentifier
+			if !params.MGF.Algorithm.Equal(oidMGF1) {
+				return nil, fmt.Errorf("unsupported OAEP mask generation %v", params.MGF.Algorithm)
+			}
+			if _, err := asn1.Unmarshal(params.MGF.Parameters.FullBytes, &mgf); err != nil {
+				return nil, fmt.Errorf("parsing MGF1 hash: %w", err)
+			}
+			if mgfHash, err = oaepHash(mgf.Algorithm); err != nil {
+				return nil, err
+			}
+		} else {
+			mgfHash = crypto.SHA1
+		}
+		return &rsa.OAEPOptions{Hash: hash, MGFHash: mgfHash}, nil
+	}
+	return nil, fmt.Errorf("unsupported key transport algorithm %v", alg.Algorithm)
+}
+
+// oaepHash maps an OAEP hash identifier to its hash, where absent is SHA-1
+func oaepHash(oid asn1.ObjectIdentifier) (crypto.Hash, error) {
+	switch {
+	case oid == nil, oid.Equal(oidSHA1):
+		return crypto.SHA1, nil
+	case oid.Equal(oidSHA256):
+		return crypto.SHA256, nil
+	}
+	return 0, fmt.Errorf("unsupported OAEP hash %v", oid)
+}
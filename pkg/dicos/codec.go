@@ -1,10 +1,18 @@
 package dicos
 
 import (
-	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
 	"image"
+	"image/color"
+	"image/jpeg"
 	"io"
+	"log/slog"
+	"sync"
 
+	"github.com/jpfielding/dicos.go/pkg/dicos/transfer"
 	"github.com/jpfielding/jpegs/pkg/compress/jpeg2k"
 	"github.com/jpfielding/jpegs/pkg/compress/jpegli"
 	"github.com/jpfielding/jpegs/pkg/compress/jpegls"
@@ -32,6 +40,13 @@ import (
 //   - JPEG 2000:
 //     Wavelet-based compression with lossless/lossy modes. Use CodecJPEG2000.
 //
+//   - JPEG Baseline (Process 1):
+//     Lossy 8-bit compression for thumbnails and constrained links. Use
+//     CodecJPEGBaseline.
+//
+//   - JPEG Extended (Process 2 & 4):
+//     Lossy 12-bit compression of grayscale frames. Use CodecJPEGExtended.
+//
 // Example - Using a codec:
 //
 //	ct := dicos.NewCTImage()
@@ -63,6 +78,58 @@ type Codec interface {
 	TransferSyntaxUID() string
 }
 
+// ContextDecoder is implemented by codecs that accept a context while decoding,
+// for cancellation or to log with per-request fields. Implementations check
+// ctx.Err() as they go, per row, segment or block of input, since decoding
+// runs on the caller's goroutine. Every built-in codec implements it.
+type ContextDecoder interface {
+	DecodeContext(ctx context.Context, data []byte, width, height int) (image.Image, error)
+}
+
+// SampleFormat describes how grayscale samples are stored in pixel data
+type SampleFormat struct {
+	BitsAllocated int  // 8 or 16
+	BitsStored    int  // significant bits, at most BitsAllocated
+	Signed        bool // PixelRepresentation 1: two's complement samples
+}
+
+// SampleEncoder is implemented by codecs that encode raw samples with their
+// stored precision and sign rather than an unsigned image.Image. WithPixelData
+// prefers it so that signed CT data is described correctly in the codestream.
+type SampleEncoder interface {
+	EncodeSamples(w io.Writer, data []uint16, width, height int, f SampleFormat) error
+}
+
+// ErrUnsupportedPixelFormat is returned when a codec cannot represent the
+// requested sample format losslessly.
+var ErrUnsupportedPixelFormat = errors.New("unsupported pixel format")
+
+// decodeWithCodec decodes one frame with c, preferring DecodeContext when
+// available, and logs the outcome with ctx.
+func decodeWithCodec(ctx context.Context, c Codec, data []byte, width, height int) (image.Image, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	img, err := watchDecode(ctx, c, data, func(ctx context.Context) (image.Image, error) {
+		if cd, ok := c.(ContextDecoder); ok {
+			return cd.DecodeContext(ctx, data, width, height)
+		}
+		return c.Decode(data, width, height)
+	})
+	if err != nil {
+		logger(ctx).DebugContext(ctx, "Codec decode failed",
+			slog.String("codec", c.Name()),
+			slog.Int("dataLen", len(data)),
+			slog.Any("error", err))
+		return nil, err
+	}
+	logger(ctx).DebugContext(ctx, "Codec decoded frame",
+		slog.String("codec", c.Name()),
+		slog.Int("dataLen", len(data)))
+	return img, nil
+}
+
 // jpegLSCodec implements Codec for JPEG-LS
 type jpegLSCodec struct{}
 
@@ -71,7 +138,64 @@ func (c *jpegLSCodec) Encode(w io.Writer, img image.Image) error {
 }
 
 func (c *jpegLSCodec) Decode(data []byte, width, height int) (image.Image, error) {
-	return jpegls.Decode(bytes.NewReader(data))
+	return c.DecodeContext(context.Background(), data, width, height)
+}
+
+// DecodeContext checks the frame header before decoding, so a forged size
+// cannot allocate more than the frame, and returns a decoder panic on a
+// malformed stream as an error. A stream smaller than the frame is still
+// decoded. The stream is read through ctx, so a cancelled decode stops within
+// a few KiB.
+func (c *jpegLSCodec) DecodeContext(ctx context.Context, data []byte, width, height int) (img image.Image, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			img, err = nil, fmt.Errorf("jpeg-ls: malformed stream: %v", r)
+		}
+	}()
+	w, h, precision, err := jpegLSFrameHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	if w == 0 || h == 0 || precision < 2 || precision > 16 {
+		return nil, fmt.Errorf("jpeg-ls: invalid frame header %dx%d with precision %d", w, h, precision)
+	}
+	if width > 0 && height > 0 && (w > width || h > height) {
+		return nil, fmt.Errorf("jpeg-ls: stream is %dx%d, frame is %dx%d", w, h, width, height)
+	}
+	return jpegls.Decode(newContextReader(ctx, data))
+}
+
+// jpegLSFrameHeader returns the size and precision of the SOF55 marker
+// segment that starts a JPEG-LS stream
+func jpegLSFrameHeader(data []byte) (width, height, precision int, err error) {
+	if len(data) < 2 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 0, 0, 0, fmt.Errorf("jpeg-ls: missing SOI marker")
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 0, 0, 0, fmt.Errorf("jpeg-ls: expected a marker at offset %d", i)
+		}
+		marker, length := data[i+1], int(data[i+2])<<8|int(data[i+3])
+		if marker == 0xFF { // fill byte
+			i++
+			continue
+		}
+		if length < 2 || i+2+length > len(data) {
+			return 0, 0, 0, fmt.Errorf("jpeg-ls: truncated marker segment %02X", marker)
+		}
+		switch marker {
+		case 0xF7: // SOF55
+			if length < 8 {
+				return 0, 0, 0, fmt.Errorf("jpeg-ls: short frame header")
+			}
+			seg := data[i+4:]
+			return int(seg[3])<<8 | int(seg[4]), int(seg[1])<<8 | int(seg[2]), int(seg[0]), nil
+		case 0xDA, 0xD9: // SOS or EOI before the frame header
+			return 0, 0, 0, fmt.Errorf("jpeg-ls: no frame header")
+		}
+		i += 2 + length
+	}
+	return 0, 0, 0, fmt.Errorf("jpeg-ls: no frame header")
 }
 
 func (c *jpegLSCodec) Name() string {
@@ -90,7 +214,13 @@ func (c *jpegLiCodec) Encode(w io.Writer, img image.Image) error {
 }
 
 func (c *jpegLiCodec) Decode(data []byte, width, height int) (image.Image, error) {
-	return jpegli.Decode(bytes.NewReader(data))
+	return c.DecodeContext(context.Background(), data, width, height)
+}
+
+// DecodeContext reads the stream through ctx, so a cancelled decode stops
+// within a few KiB
+func (c *jpegLiCodec) DecodeContext(ctx context.Context, data []byte, width, height int) (image.Image, error) {
+	return jpegli.Decode(newContextReader(ctx, data))
 }
 
 func (c *jpegLiCodec) Name() string {
@@ -109,7 +239,45 @@ func (c *rleCodec) Encode(w io.Writer, img image.Image) error {
 }
 
 func (c *rleCodec) Decode(data []byte, width, height int) (image.Image, error) {
-	return rle.Decode(data, width, height)
+	return c.DecodeContext(context.Background(), data, width, height)
+}
+
+// DecodeContext decodes the high and low byte segments of a 16-bit frame one
+// at a time, checking ctx between them
+func (c *rleCodec) DecodeContext(ctx context.Context, data []byte, width, height int) (image.Image, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(data) < 64 || binary.LittleEndian.Uint32(data) != 2 {
+		return rle.Decode(data, width, height)
+	}
+	var planes [2]*image.Gray
+	for i := range planes {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		start, end := binary.LittleEndian.Uint32(data[4+4*i:]), uint32(len(data))
+		if i == 0 {
+			end = binary.LittleEndian.Uint32(data[8:])
+		}
+		if start < 64 || start > end || end > uint32(len(data)) {
+			return nil, fmt.Errorf("rle: invalid segment offset/length for segment %d", i)
+		}
+		// each segment is decoded as a single-segment frame of its own
+		seg := make([]byte, 64+end-start)
+		seg[0], seg[4] = 1, 64
+		copy(seg[64:], data[start:end])
+		img, err := rle.Decode(seg, width, height)
+		if err != nil {
+			return nil, fmt.Errorf("rle: segment %d: %w", i, err)
+		}
+		planes[i] = img.(*image.Gray)
+	}
+	out := image.NewGray16(image.Rect(0, 0, width, height))
+	for i := range planes[0].Pix {
+		out.Pix[2*i], out.Pix[2*i+1] = planes[0].Pix[i], planes[1].Pix[i]
+	}
+	return out, nil
 }
 
 func (c *rleCodec) Name() string {
@@ -120,15 +288,341 @@ func (c *rleCodec) TransferSyntaxUID() string {
 	return "1.2.840.10008.1.2.5" // RLE Lossless
 }
 
+// jpegBaselineCodec implements Codec for lossy JPEG Baseline (Process 1) with
+// the standard library encoder. It encodes 8-bit grayscale only: color frames
+// would need YBR_FULL_422 rather than the RGB the builders write. 12-bit
+// frames are encoded by jpegExtendedCodec.
+type jpegBaselineCodec struct {
+	quality int // 1-100, jpeg.DefaultQuality when 0
+}
+
+// Encode compresses an 8-bit grayscale image
+func (c *jpegBaselineCodec) Encode(w io.Writer, img image.Image) error {
+	if _, ok := img.(*image.Gray); !ok {
+		return fmt.Errorf("jpeg-baseline: %w: %T, only 8-bit grayscale is encoded", ErrUnsupportedPixelFormat, img)
+	}
+	quality := c.quality
+	if quality == 0 {
+		quality = jpeg.DefaultQuality
+	}
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+}
+
+// EncodeSamples encodes one frame of unsigned samples of at most 8 bits.
+// 12-bit samples need JPEG Extended (Process 4), see CodecJPEGExtended.
+func (c *jpegBaselineCodec) EncodeSamples(w io.Writer, data []uint16, width, height int, f SampleFormat) error {
+	switch {
+	case f.BitsStored > 8:
+		return fmt.Errorf("jpeg-baseline: %w: %d bits stored need JPEG Extended (Process 4), use jpeg-extended", ErrUnsupportedPixelFormat, f.BitsStored)
+	case f.BitsAllocated != 8:
+		return fmt.Errorf("jpeg-baseline: %w: %d bits allocated, use 8 bits allocated", ErrUnsupportedPixelFormat, f.BitsAllocated)
+	case f.Signed:
+		return fmt.Errorf("jpeg-baseline: %w: signed samples", ErrUnsupportedPixelFormat)
+	}
+	if len(data) < width*height {
+		return fmt.Errorf("jpeg-baseline: frame has %d samples, want %d", len(data), width*height)
+	}
+	gray := image.NewGray(image.Rect(0, 0, width, height))
+	for i := range gray.Pix {
+		if data[i] > 0xFF {
+			return fmt.Errorf("jpeg-baseline: %w: sample %d (%d) exceeds 8 bits", ErrUnsupportedPixelFormat, i, data[i])
+		}
+		gray.Pix[i] = uint8(data[i])
+	}
+	return c.Encode(w, gray)
+}
+
+func (c *jpegBaselineCodec) Decode(data []byte, width, height int) (image.Image, error) {
+	return c.DecodeContext(context.Background(), data, width, height)
+}
+
+// DecodeContext decodes a Baseline or Extended codestream. 8-bit codestreams
+// go to image/jpeg, read through ctx; 12-bit grayscale ones to the Process 4
+// decoder, as Gray16, which checks ctx per row of blocks.
+func (c *jpegBaselineCodec) DecodeContext(ctx context.Context, data []byte, width, height int) (image.Image, error) {
+	var img image.Image
+	var err error
+	if _, precision, herr := jpegFrameHeader(data); herr == nil && precision == 12 {
+		img, err = decodeJPEGExtended(ctx, data)
+	} else {
+		img, err = jpeg.Decode(newContextReader(ctx, data))
+		if _, ok := err.(jpeg.UnsupportedError); ok {
+			return nil, fmt.Errorf("jpeg-baseline: %w: %v", ErrUnsupportedPixelFormat, err)
+		}
+		if err != nil {
+			err = fmt.Errorf("jpeg-baseline: %w", err)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if b := img.Bounds(); width > 0 && height > 0 && (b.Dx() != width || b.Dy() != height) {
+		return nil, fmt.Errorf("jpeg-baseline: codestream is %dx%d, frame is %dx%d", b.Dx(), b.Dy(), width, height)
+	}
+	return img, nil
+}
+
+func (c *jpegBaselineCodec) Name() string {
+	return "jpeg-baseline"
+}
+
+func (c *jpegBaselineCodec) TransferSyntaxUID() string {
+	return "1.2.840.10008.1.2.4.50" // JPEG Baseline (Process 1)
+}
+
+// NewJPEGBaselineCodec returns the JPEG Baseline codec encoding at quality,
+// from 1 to 100. CodecJPEGBaseline uses jpeg.DefaultQuality.
+func NewJPEGBaselineCodec(quality int) Codec {
+	return &jpegBaselineCodec{quality: min(max(quality, 1), 100)}
+}
+
+// jpegExtendedCodec implements Codec for lossy JPEG Extended (Process 2 & 4).
+// Grayscale frames of up to 12 bits stored in 16 bits allocated are encoded
+// at 12-bit precision (SOF1); 8-bit frames are written as Baseline, which
+// every Extended decoder reads.
+type jpegExtendedCodec struct {
+	jpegBaselineCodec
+}
+
+// Encode compresses an 8-bit grayscale image, or a Gray16 image whose
+// samples fit in 12 bits
+func (c *jpegExtendedCodec) Encode(w io.Writer, img image.Image) error {
+	g, ok := img.(*image.Gray16)
+	if !ok {
+		return c.jpegBaselineCodec.Encode(w, img)
+	}
+	b := g.Bounds()
+	data := make([]uint16, 0, b.Dx()*b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			data = append(data, g.Gray16At(x, y).Y)
+		}
+	}
+	return c.EncodeSamples(w, data, b.Dx(), b.Dy(), SampleFormat{BitsAllocated: 16, BitsStored: 12})
+}
+
+// EncodeSamples encodes one frame of unsigned samples of at most 12 bits
+func (c *jpegExtendedCodec) EncodeSamples(w io.Writer, data []uint16, width, height int, f SampleFormat) error {
+	if f.BitsAllocated == 8 {
+		return c.jpegBaselineCodec.EncodeSamples(w, data, width, height, f)
+	}
+	switch {
+	case f.BitsStored > 12:
+		return fmt.Errorf("jpeg-extended: %w: %d bits stored, at most 12 are encoded", ErrUnsupportedPixelFormat, f.BitsStored)
+	case f.BitsAllocated != 16:
+		return fmt.Errorf("jpeg-extended: %w: %d bits allocated, use 8 or 16 bits allocated", ErrUnsupportedPixelFormat, f.BitsAllocated)
+	case f.Signed:
+		return fmt.Errorf("jpeg-extended: %w: signed samples", ErrUnsupportedPixelFormat)
+	}
+	if len(data) < width*height {
+		return fmt.Errorf("jpeg-extended: frame has %d samples, want %d", len(data), width*height)
+	}
+	for i, v := range data[:width*height] {
+		if v > 0x0FFF {
+			return fmt.Errorf("jpeg-extended: %w: sample %d (%d) exceeds 12 bits", ErrUnsupportedPixelFormat, i, v)
+		}
+	}
+	quality := c.quality
+	if quality == 0 {
+		quality = jpeg.DefaultQuality
+	}
+	return encodeJPEGExtended(w, data, width, height, quality)
+}
+
+func (c *jpegExtendedCodec) Name() string {
+	return "jpeg-extended"
+}
+
+func (c *jpegExtendedCodec) TransferSyntaxUID() string {
+	return "1.2.840.10008.1.2.4.51" // JPEG Extended (Process 2 & 4)
+}
+
+// NewJPEGExtendedCodec returns the JPEG Extended codec encoding at quality,
+// from 1 to 100. CodecJPEGExtended uses jpeg.DefaultQuality.
+func NewJPEGExtendedCodec(quality int) Codec {
+	return &jpegExtendedCodec{jpegBaselineCodec{quality: min(max(quality, 1), 100)}}
+}
+
 // jpeg2kCodec implements Codec for JPEG 2000
 type jpeg2kCodec struct{}
 
+// Encode writes Gray, Gray16 and RGBA images as lossless T.800 codestreams,
+// RGBA without its alpha and with the reversible component transform
 func (c *jpeg2kCodec) Encode(w io.Writer, img image.Image) error {
-	return jpeg2k.Encode(w, img, nil)
+	b := img.Bounds()
+	j := &jpeg2000Image{width: b.Dx(), height: b.Dy()}
+	switch src := img.(type) {
+	case *image.Gray:
+		j.precision, j.comps = 8, [][]int32{make([]int32, 0, j.width*j.height)}
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				j.comps[0] = append(j.comps[0], int32(src.GrayAt(x, y).Y))
+			}
+		}
+	case *image.Gray16:
+		j.precision, j.comps = 16, [][]int32{make([]int32, 0, j.width*j.height)}
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				j.comps[0] = append(j.comps[0], int32(src.Gray16At(x, y).Y))
+			}
+		}
+	case *image.RGBA:
+		j.precision, j.comps = 8, make([][]int32, 3)
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				px := src.RGBAAt(x, y)
+				j.comps[0] = append(j.comps[0], int32(px.R))
+				j.comps[1] = append(j.comps[1], int32(px.G))
+				j.comps[2] = append(j.comps[2], int32(px.B))
+			}
+		}
+	default:
+		return fmt.Errorf("jpeg-2000: %w: %T", ErrUnsupportedPixelFormat, img)
+	}
+	return encodeJPEG2000(w, j, 0, 0)
 }
 
+// Decode decodes a codestream, or a JP2 file wrapping one. Tiled codestreams
+// are decoded tile by tile and composed into the frame. Signed components
+// are returned as Gray16 holding the sign-extended two's complement samples,
+// as in native pixel data.
+// The header is checked against the frame before decoding, and a decoder
+// panic on a malformed codestream is returned as an error.
 func (c *jpeg2kCodec) Decode(data []byte, width, height int) (image.Image, error) {
-	return jpeg2k.Decode(bytes.NewReader(data))
+	return c.DecodeContext(context.Background(), data, width, height)
+}
+
+// DecodeContext is Decode checking ctx before each tile
+func (c *jpeg2kCodec) DecodeContext(ctx context.Context, data []byte, width, height int) (img image.Image, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			img, err = nil, fmt.Errorf("jpeg-2000: malformed codestream: %v", r)
+		}
+	}()
+	if IsJP2(data) {
+		// some writers store the JP2 file rather than its codestream
+		if data, _, err = ReadJP2(data); err != nil {
+			return nil, fmt.Errorf("jpeg-2000: %w", err)
+		}
+	}
+	siz, cod, qcd, err := jpeg2k.ParseCodestreamHeader(data)
+	if err != nil {
+		return nil, fmt.Errorf("jpeg-2000: %w", err)
+	}
+	if err := checkJPEG2000Header(siz, cod, width, height); err != nil {
+		return nil, err
+	}
+	img, err = decodeJPEG2000Tiles(ctx, data, siz, cod, qcd)
+	if err != nil {
+		return nil, err
+	}
+	if b := img.Bounds(); b.Dx() != int(siz.XSiz-siz.XOsiz) || b.Dy() != int(siz.YSiz-siz.YOsiz) {
+		return nil, fmt.Errorf("jpeg-2000: decoded %dx%d, header declares %dx%d", b.Dx(), b.Dy(), siz.XSiz-siz.XOsiz, siz.YSiz-siz.YOsiz)
+	}
+	if len(siz.Components) != 1 || !siz.Components[0].Signed {
+		return img, nil
+	}
+	offset := 1 << (siz.Components[0].Precision - 1)
+	b := img.Bounds()
+	out := image.NewGray16(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			var u int
+			switch src := img.(type) {
+			case *image.Gray:
+				u = int(src.GrayAt(x, y).Y)
+			case *image.Gray16:
+				u = int(src.Gray16At(x, y).Y)
+			default:
+				return nil, fmt.Errorf("jpeg-2000: signed component decoded as %T", img)
+			}
+			out.SetGray16(x, y, color.Gray16{Y: uint16(int16(u - offset))})
+		}
+	}
+	return out, nil
+}
+
+// EncodeSamples encodes one grayscale frame with the SIZ precision and sign
+// taken from f
+func (c *jpeg2kCodec) EncodeSamples(w io.Writer, data []uint16, width, height int, f SampleFormat) error {
+	if f.BitsAllocated != 8 && f.BitsAllocated != 16 {
+		return fmt.Errorf("jpeg-2000: %w: %d bits allocated", ErrUnsupportedPixelFormat, f.BitsAllocated)
+	}
+	if f.BitsStored < 1 || f.BitsStored > f.BitsAllocated {
+		return fmt.Errorf("jpeg-2000: %w: %d bits stored in %d allocated", ErrUnsupportedPixelFormat, f.BitsStored, f.BitsAllocated)
+	}
+	if f.BitsAllocated == 16 && f.BitsStored <= 8 {
+		// The decoder returns 8-bit precision as image.Gray
+		return fmt.Errorf("jpeg-2000: %w: %d bits stored in 16 allocated, use 8 bits allocated", ErrUnsupportedPixelFormat, f.BitsStored)
+	}
+	if len(data) < width*height {
+		return fmt.Errorf("jpeg-2000: frame has %d samples, want %d", len(data), width*height)
+	}
+
+	mask := 1<<f.BitsStored - 1
+	img := &jpeg2000Image{width: width, height: height, precision: f.BitsStored, signed: f.Signed}
+	img.comps = [][]int32{make([]int32, width*height)}
+	for i := range img.comps[0] {
+		v := int(data[i])
+		if !f.Signed {
+			if v > mask {
+				return fmt.Errorf("jpeg-2000: %w: sample %d (%d) exceeds %d bits stored", ErrUnsupportedPixelFormat, i, v, f.BitsStored)
+			}
+			img.comps[0][i] = int32(v)
+			continue
+		}
+		// accept sign-extended or masked storage of the high bits
+		s := v & mask
+		if s > mask>>1 {
+			s -= mask + 1
+		}
+		if v != s&0xFFFF && v != s&mask {
+			return fmt.Errorf("jpeg-2000: %w: sample %d (%#04x) does not fit %d signed bits", ErrUnsupportedPixelFormat, i, v, f.BitsStored)
+		}
+		img.comps[0][i] = int32(s)
+	}
+	return encodeJPEG2000(w, img, 0, 0)
+}
+
+// checkJPEG2000Header rejects codestream headers the decoder cannot handle
+// safely: sizes that disagree with the frame, precisions DICOM does not allow,
+// decomposition levels beyond the 32 of ITU-T T.800, and tile grids that do
+// not cover the image or have more tiles than Isot can index.
+func checkJPEG2000Header(siz *jpeg2k.SIZMarker, cod *jpeg2k.CODMarker, width, height int) error {
+	if siz == nil || cod == nil {
+		return fmt.Errorf("jpeg-2000: missing SIZ or COD marker")
+	}
+	if siz.XOsiz >= siz.XSiz || siz.YOsiz >= siz.YSiz {
+		return fmt.Errorf("jpeg-2000: empty image area %dx%d at offset %d,%d", siz.XSiz, siz.YSiz, siz.XOsiz, siz.YOsiz)
+	}
+	w, h := int(siz.XSiz-siz.XOsiz), int(siz.YSiz-siz.YOsiz)
+	if width > 0 && height > 0 && (w != width || h != height) {
+		return fmt.Errorf("jpeg-2000: codestream is %dx%d, frame is %dx%d", w, h, width, height)
+	}
+	if n := len(siz.Components); n != 1 && n != 3 {
+		return fmt.Errorf("jpeg-2000: %d components, want 1 or 3", n)
+	}
+	for i, comp := range siz.Components {
+		if comp.Precision < 1 || comp.Precision > 16 {
+			return fmt.Errorf("jpeg-2000: component %d precision %d outside 1-16", i, comp.Precision)
+		}
+	}
+	if cod.DecompLevels > 32 {
+		return fmt.Errorf("jpeg-2000: %d decomposition levels, at most 32", cod.DecompLevels)
+	}
+	if siz.XTsiz == 0 || siz.YTsiz == 0 {
+		return fmt.Errorf("jpeg-2000: empty tile size %dx%d", siz.XTsiz, siz.YTsiz)
+	}
+	if siz.XTOsiz > siz.XOsiz || siz.YTOsiz > siz.YOsiz ||
+		int64(siz.XTOsiz)+int64(siz.XTsiz) <= int64(siz.XOsiz) || int64(siz.YTOsiz)+int64(siz.YTsiz) <= int64(siz.YOsiz) {
+		return fmt.Errorf("jpeg-2000: tile grid offset %d,%d does not cover the image at %d,%d", siz.XTOsiz, siz.YTOsiz, siz.XOsiz, siz.YOsiz)
+	}
+	nx := (int64(siz.XSiz) - int64(siz.XTOsiz) + int64(siz.XTsiz) - 1) / int64(siz.XTsiz)
+	ny := (int64(siz.YSiz) - int64(siz.YTOsiz) + int64(siz.YTsiz) - 1) / int64(siz.YTsiz)
+	if n := nx * ny; n > maxJPEG2000Tiles || n != int64(siz.NumTiles()) {
+		return fmt.Errorf("jpeg-2000: %dx%d tiles of %dx%d, at most %d", nx, ny, siz.XTsiz, siz.YTsiz, maxJPEG2000Tiles)
+	}
+	return nil
 }
 
 func (c *jpeg2kCodec) Name() string {
@@ -139,6 +633,9 @@ func (c *jpeg2kCodec) TransferSyntaxUID() string {
 	return "1.2.840.10008.1.2.4.90" // JPEG 2000 Lossless Only
 }
 
+// codecsMu guards codecsByName and codecsByTS, which RegisterCodec extends
+var codecsMu sync.RWMutex
+
 // codecsByName maps codec names to implementations
 var codecsByName = map[string]Codec{
 	"jpeg-ls":   &jpegLSCodec{},
@@ -146,15 +643,23 @@ var codecsByName = map[string]Codec{
 	"rle":       &rleCodec{},
 	"jpeg-2000": &jpeg2kCodec{},
 	"jpeg2000":  &jpeg2kCodec{}, // alias
+	"jpegls":    &jpegLSCodec{}, // alias
+	"jpegli":    &jpegLiCodec{}, // alias
+
+	"jpeg-baseline": &jpegBaselineCodec{},
+	"jpeg":          &jpegBaselineCodec{}, // alias
+	"jpeg-extended": &jpegExtendedCodec{},
 }
 
 // codecsByTS maps transfer syntax UIDs to implementations
 var codecsByTS = map[string]Codec{
-	"1.2.840.10008.1.2.4.80": &jpegLSCodec{}, // JPEG-LS Lossless
-	"1.2.840.10008.1.2.4.81": &jpegLSCodec{}, // JPEG-LS Near-Lossless
-	"1.2.840.10008.1.2.4.70": &jpegLiCodec{}, // JPEG Lossless First-Order
-	"1.2.840.10008.1.2.5":    &rleCodec{},    // RLE Lossless
-	"1.2.840.10008.1.2.4.90": &jpeg2kCodec{}, // JPEG 2000 Lossless
+	"1.2.840.10008.1.2.4.80": &jpegLSCodec{},       // JPEG-LS Lossless
+	"1.2.840.10008.1.2.4.81": &jpegLSCodec{},       // JPEG-LS Near-Lossless
+	"1.2.840.10008.1.2.4.70": &jpegLiCodec{},       // JPEG Lossless First-Order
+	"1.2.840.10008.1.2.5":    &rleCodec{},          // RLE Lossless
+	"1.2.840.10008.1.2.4.90": &jpeg2kCodec{},       // JPEG 2000 Lossless
+	"1.2.840.10008.1.2.4.50": &jpegBaselineCodec{}, // JPEG Baseline
+	"1.2.840.10008.1.2.4.51": &jpegExtendedCodec{}, // JPEG Extended
 }
 
 // Predefined codec instances for convenience.
@@ -166,20 +671,36 @@ var codecsByTS = map[string]Codec{
 //
 // CodecJPEGLS is the recommended choice for DICOS per NEMA standards, providing
 // excellent compression ratios with lossless quality.
+//
+// CodecJPEG2000 writes ITU-T T.800 codestreams with the reversible 5/3
+// wavelet and one quality layer, and still reads the coefficient payload
+// that earlier versions of this library wrote.
 var (
 	CodecJPEGLS   Codec = codecsByName["jpeg-ls"]   // JPEG-LS Lossless (recommended)
 	CodecJPEGLi   Codec = codecsByName["jpeg-li"]   // JPEG Lossless Process 14
 	CodecRLE      Codec = codecsByName["rle"]       // RLE Lossless
 	CodecJPEG2000 Codec = codecsByName["jpeg-2000"] // JPEG 2000 Lossless
+
+	// CodecJPEGBaseline is lossy, for operator thumbnails and links that
+	// cannot carry lossless frames. WithPixelData records the compression in
+	// LossyImageCompression, LossyImageCompressionRatio and
+	// LossyImageCompressionMethod.
+	CodecJPEGBaseline Codec = codecsByName["jpeg-baseline"] // JPEG Baseline, 8-bit lossy
+
+	// CodecJPEGExtended is lossy like CodecJPEGBaseline and also encodes 12-bit
+	// grayscale frames, such as CT slices, at full precision.
+	CodecJPEGExtended Codec = codecsByName["jpeg-extended"] // JPEG Extended, 8/12-bit lossy
 )
 
 // CodecByName returns a codec by its name identifier.
 //
 // Supported names:
-//   - "jpeg-ls" - JPEG-LS Lossless (recommended for DICOS)
-//   - "jpeg-li" - JPEG Lossless First-Order (Process 14)
+//   - "jpeg-ls", "jpegls" - JPEG-LS Lossless (recommended for DICOS)
+//   - "jpeg-li", "jpegli" - JPEG Lossless First-Order (Process 14)
 //   - "rle" - RLE Lossless
 //   - "jpeg-2000", "jpeg2000" - JPEG 2000 Lossless
+//   - "jpeg-baseline", "jpeg" - JPEG Baseline, 8-bit lossy
+//   - "jpeg-extended" - JPEG Extended, 8 or 12-bit lossy
 //
 // Returns nil if the codec name is not recognized.
 //
@@ -190,6 +711,8 @@ var (
 //		log.Fatal("Unknown codec")
 //	}
 func CodecByName(name string) Codec {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
 	return codecsByName[name]
 }
 
@@ -201,6 +724,8 @@ func CodecByName(name string) Codec {
 //   - "1.2.840.10008.1.2.4.70" - JPEG Lossless First-Order (Process 14)
 //   - "1.2.840.10008.1.2.5" - RLE Lossless
 //   - "1.2.840.10008.1.2.4.90" - JPEG 2000 Lossless
+//   - "1.2.840.10008.1.2.4.50" - JPEG Baseline (Process 1)
+//   - "1.2.840.10008.1.2.4.51" - JPEG Extended (Process 2 & 4)
 //
 // Returns nil if the transfer syntax is not supported or is uncompressed
 // (Explicit/Implicit VR Little Endian).
@@ -214,5 +739,44 @@ func CodecByName(name string) Codec {
 //		// Compressed pixel data, use codec to decompress
 //	}
 func CodecByTransferSyntax(ts string) Codec {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
 	return codecsByTS[ts]
 }
+
+// RegisterCodec makes c the codec of the encapsulated transfer syntax uid,
+// and of its name. Register codecs from main or a setup function before the
+// first read or write, rather than from init. The reader decodes
+// frames of uid with it, and Transcode, the IOD builders reading a dataset
+// back and CodecByName resolve it. Registering a built-in UID replaces the
+// built-in codec, e.g. with cgo OpenJPEG bindings for JPEG 2000; the
+// predefined CodecJPEG2000 and friends are unchanged. It panics if c is nil
+// or uid is empty or a native syntax.
+//
+// Example:
+//
+//	func main() {
+//		dicos.RegisterCodec("1.2.840.10008.1.2.4.90", openjpeg.Codec{})
+//		dicos.RegisterCodec("1.2.840.10008.1.2.4.91", openjpeg.Codec{})
+//		...
+//	}
+func RegisterCodec(uid string, c Codec) {
+	if c == nil || uid == "" || !transfer.Syntax(uid).IsEncapsulated() {
+		panic("dicos: RegisterCodec with nil codec or native transfer syntax " + uid)
+	}
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	codecsByTS[uid] = c
+	if name := c.Name(); name != "" {
+		codecsByName[name] = c
+	}
+}
+
+// registered returns the codec registered for the transfer syntax of the
+// built-in codec c, which may be a plug-in replacing it
+func registered(c Codec) Codec {
+	if r := CodecByTransferSyntax(c.TransferSyntaxUID()); r != nil {
+		return r
+	}
+	return c
+}
@@ -11,7 +11,7 @@ import (
 //
 // Recommendations based on NEMA DICOS standards and typical use cases:
 //   - CT, DX, AIT2D, AIT3D: JPEG-LS Lossless (best compression for medical imaging)
-//   - TDR: No compression (structured report, small size)
+//   - TDR, QR: No compression (no pixel data, small size)
 //   - Default: JPEG-LS Lossless
 //
 // Example:
@@ -25,8 +25,8 @@ func RecommendedCodec(modality string) Codec {
 		return CodecJPEGLS // JPEG-LS recommended for DICOS per NEMA
 	case "AIT2D", "AIT3D":
 		return CodecJPEGLS // Millimeter wave imaging benefits from lossless compression
-	case "TDR", "SR":
-		return nil // Structured reports don't have pixel data
+	case "TDR", "SR", "QR":
+		return nil // Reports and QR measurements don't have pixel data
 	default:
 		return CodecJPEGLS // Safe default for medical imaging
 	}
@@ -18,6 +18,7 @@ func TestRecommendedCodec(t *testing.T) {
 		{"AIT3D", false},
 		{"TDR", true},
 		{"SR", true},
+		{"QR", true},
 		{"UNKNOWN", false}, // Defaults to JPEG-LS
 	}
 
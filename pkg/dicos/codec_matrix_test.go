@@ -0,0 +1,192 @@
+package dicos
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+)
+
+// The codec matrix encodes a set of reference images with every codec and
+// decodes each result with every codec, so interop regressions show up as
+// codecs are added. Set DICOS_CODEC_MATRIX to a file path to keep the matrix
+// as a markdown artifact:
+//
+//	DICOS_CODEC_MATRIX=/tmp/matrix.md go test ./pkg/dicos -run TestCodecMatrix
+
+// referenceImage is one input to the codec matrix
+type referenceImage struct {
+	name string
+	img  image.Image
+}
+
+// matrixCell is the outcome of decoding one encoder's output with one decoder
+type matrixCell struct {
+	exact  bool
+	result string // "exact", "max err N", or the error
+}
+
+func referenceImages() []referenceImage {
+	const w, h = 17, 13 // odd sizes catch edge handling
+	rng := rand.New(rand.NewSource(1))
+	gray8 := image.NewGray(image.Rect(0, 0, w, h))
+	ramp12 := image.NewGray16(image.Rect(0, 0, w, h))
+	noise16 := image.NewGray16(image.Rect(0, 0, w, h))
+	signed := image.NewGray16(image.Rect(0, 0, w, h))
+	flat := image.NewGray16(image.Rect(0, 0, w, h))
+	rgb := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := y*w + x
+			gray8.SetGray(x, y, color.Gray{Y: uint8(i * 255 / (w * h))})
+			ramp12.SetGray16(x, y, color.Gray16{Y: uint16(i * 4095 / (w * h))})
+			noise16.SetGray16(x, y, color.Gray16{Y: uint16(rng.Intn(1 << 16))})
+			signed.SetGray16(x, y, color.Gray16{Y: uint16(int16(i*8 - 1000))}) // two's complement HU
+			flat.SetGray16(x, y, color.Gray16{Y: 1024})
+			rgb.SetRGBA(x, y, color.RGBA{R: uint8(x * 15), G: uint8(y * 19), B: uint8(i), A: 255})
+		}
+	}
+	return []referenceImage{
+		{"gray8 gradient", gray8},
+		{"gray16 12-bit ramp", ramp12},
+		{"gray16 noise", noise16},
+		{"gray16 signed", signed},
+		{"gray16 flat", flat},
+		{"rgb8", rgb},
+	}
+}
+
+// matrixCodecs lists every codec once, without the name aliases
+func matrixCodecs() []Codec {
+	return []Codec{CodecJPEGLS, CodecJPEGLi, CodecRLE, CodecJPEG2000}
+}
+
+// compareImages reports the largest per-channel difference in 16-bit units
+func compareImages(want, got image.Image) (int, error) {
+	if want.Bounds().Size() != got.Bounds().Size() {
+		return 0, fmt.Errorf("size %v, want %v", got.Bounds().Size(), want.Bounds().Size())
+	}
+	wb, gb := want.Bounds(), got.Bounds()
+	maxErr := 0
+	for y := 0; y < wb.Dy(); y++ {
+		for x := 0; x < wb.Dx(); x++ {
+			wr, wg, wbl, _ := want.At(wb.Min.X+x, wb.Min.Y+y).RGBA()
+			gr, gg, gbl, _ := got.At(gb.Min.X+x, gb.Min.Y+y).RGBA()
+			for _, d := range []int{int(wr) - int(gr), int(wg) - int(gg), int(wbl) - int(gbl)} {
+				maxErr = max(maxErr, d, -d)
+			}
+		}
+	}
+	return maxErr, nil
+}
+
+// runCodecMatrix fills matrix[image][encoder][decoder]. A panicking codec is
+// recorded in its cell rather than aborting the run.
+func runCodecMatrix(images []referenceImage, codecs []Codec) map[string]map[string]map[string]matrixCell {
+	matrix := map[string]map[string]map[string]matrixCell{}
+	for _, ref := range images {
+		byEncoder := map[string]map[string]matrixCell{}
+		matrix[ref.name] = byEncoder
+		b := ref.img.Bounds()
+		for _, enc := range codecs {
+			row := map[string]matrixCell{}
+			byEncoder[enc.Name()] = row
+			var buf bytes.Buffer
+			if err := safeCodecCall(func() error { return enc.Encode(&buf, ref.img) }); err != nil {
+				for _, dec := range codecs {
+					row[dec.Name()] = matrixCell{result: "encode: " + err.Error()}
+				}
+				continue
+			}
+			for _, dec := range codecs {
+				var got image.Image
+				err := safeCodecCall(func() (err error) {
+					got, err = dec.Decode(buf.Bytes(), b.Dx(), b.Dy())
+					return err
+				})
+				if err != nil {
+					row[dec.Name()] = matrixCell{result: "decode: " + err.Error()}
+					continue
+				}
+				maxErr, err := compareImages(ref.img, got)
+				switch {
+				case err != nil:
+					row[dec.Name()] = matrixCell{result: err.Error()}
+				case maxErr == 0:
+					row[dec.Name()] = matrixCell{exact: true, result: "exact"}
+				default:
+					row[dec.Name()] = matrixCell{result: fmt.Sprintf("max err %d", maxErr)}
+				}
+			}
+		}
+	}
+	return matrix
+}
+
+func safeCodecCall(fn func() error) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return fn()
+}
+
+// formatCodecMatrix renders one markdown table per reference image with
+// encoders as rows and decoders as columns
+func formatCodecMatrix(images []referenceImage, codecs []Codec, matrix map[string]map[string]map[string]matrixCell) string {
+	var sb strings.Builder
+	sb.WriteString("# Codec compatibility matrix\n")
+	for _, ref := range images {
+		fmt.Fprintf(&sb, "\n## %s\n\n| encoder \\ decoder |", ref.name)
+		for _, dec := range codecs {
+			fmt.Fprintf(&sb, " %s |", dec.Name())
+		}
+		sb.WriteString("\n|---|")
+		sb.WriteString(strings.Repeat("---|", len(codecs)))
+		sb.WriteString("\n")
+		for _, enc := range codecs {
+			fmt.Fprintf(&sb, "| %s |", enc.Name())
+			for _, dec := range codecs {
+				result := strings.ReplaceAll(matrix[ref.name][enc.Name()][dec.Name()].result, "|", "/")
+				fmt.Fprintf(&sb, " %s |", result)
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+func TestCodecMatrix(t *testing.T) {
+	images := referenceImages()
+	codecs := matrixCodecs()
+	matrix := runCodecMatrix(images, codecs)
+	report := formatCodecMatrix(images, codecs, matrix)
+	if path := os.Getenv("DICOS_CODEC_MATRIX"); path != "" {
+		if err := os.WriteFile(path, []byte(report), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		t.Logf("codec matrix written to %s", path)
+	}
+
+	// Every codec must round-trip its own grayscale output losslessly; RGB is
+	// only supported by JPEG 2000 (see rgbCodecs)
+	for _, ref := range images {
+		_, isRGB := ref.img.(*image.RGBA)
+		for _, c := range codecs {
+			if isRGB && !rgbCodecs[c.Name()] {
+				continue
+			}
+			if cell := matrix[ref.name][c.Name()][c.Name()]; !cell.exact {
+				t.Errorf("%s: %s round trip: %s", ref.name, c.Name(), cell.result)
+			}
+		}
+	}
+	if t.Failed() {
+		t.Log("\n" + report)
+	}
+}
@@ -0,0 +1,203 @@
+package dicos
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// codecDecisionCreator is the private creator reserving the codec decision
+// elements
+const codecDecisionCreator = "DICOS.GO CODEC"
+
+const (
+	// selectSampleSize bounds the side of the central region trial encoded
+	// by SelectCodecFor
+	selectSampleSize = 256
+	// selectRunFraction is the share of equal neighbours below which RLE is
+	// not worth a trial
+	selectRunFraction = 0.25
+	// selectMargin is how much better than JPEG-LS, the recommended codec,
+	// another codec must compress to be chosen
+	selectMargin = 1.02
+)
+
+// CodecDecision explains the codec chosen by SelectCodecFor: the statistics
+// of the sampled pixels, the trial compression ratio of every candidate and
+// the reason for the choice.
+type CodecDecision struct {
+	Codec       Codec              `json:"-"`
+	Name        string             `json:"codec"`
+	Rows        int                `json:"rows"`         // rows of the sampled region
+	Cols        int                `json:"cols"`         // columns of the sampled region
+	Entropy     float64            `json:"entropy"`      // bits per pixel of the value histogram
+	Gradient    float64            `json:"gradient"`     // mean absolute difference of horizontal neighbours
+	RunFraction float64            `json:"run_fraction"` // share of pixels equal to their left neighbour
+	Ratios      map[string]float64 `json:"ratios"`       // trial compression ratio by codec name
+	Reason      string             `json:"reason"`
+	Skipped     []string           `json:"skipped,omitempty"` // candidates ruled out by the statistics
+}
+
+// String returns a one line summary of the decision
+func (d CodecDecision) String() string {
+	return fmt.Sprintf("%s: %s (entropy %.2f bits, gradient %.1f, runs %.0f%%)",
+		d.Name, d.Reason, d.Entropy, d.Gradient, 100*d.RunFraction)
+}
+
+// SelectCodecFor picks the lossless codec that compresses frame best among
+// JPEG-LS, RLE and JPEG 2000. It measures the entropy, gradient and run
+// statistics of the central region of the frame (at most 256x256 pixels),
+// rules out RLE when the region has too few runs to benefit, and trial
+// encodes the remaining candidates with CompareCodecs. JPEG-LS is kept
+// unless another codec beats its ratio by more than 2%.
+//
+// Pass one frame for a per-frame choice, or all frames of an instance to
+// decide for the instance from its middle frame. The returned decision can be
+// stored with the instance by WithCodecDecision.
+//
+// Example:
+//
+//	d, err := dicos.SelectCodecFor(pixelData, 512, 512)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	ct.Codec = d.Codec
+//	fmt.Println(d) // jpeg-ls: trial ratio 2.41x, no candidate better by more than 2% (...)
+func SelectCodecFor(frame []uint16, rows, cols int) (CodecDecision, error) {
+	if rows <= 0 || cols <= 0 {
+		return CodecDecision{}, fmt.Errorf("invalid frame size %dx%d", rows, cols)
+	}
+	pixels := rows * cols
+	if len(frame) < pixels {
+		return CodecDecision{}, fmt.Errorf("data too small: need %d pixels, got %d", pixels, len(frame))
+	}
+	if frames := len(frame) / pixels; frames > 1 {
+		mid := frames / 2 * pixels
+		frame = frame[mid : mid+pixels]
+	}
+
+	sample, sr, sc := centerRegion(frame, rows, cols, selectSampleSize)
+	d := CodecDecision{Rows: sr, Cols: sc, Ratios: map[string]float64{}}
+	d.Entropy, d.Gradient, d.RunFraction = pixelStatistics(sample, sr, sc)
+
+	candidates := []Codec{CodecJPEGLS, CodecJPEG2000}
+	if d.RunFraction >= selectRunFraction {
+		candidates = append(candidates, CodecRLE)
+	} else {
+		d.Skipped = append(d.Skipped, CodecRLE.Name())
+	}
+	comparisons, err := CompareCodecs(sr, sc, sample, candidates...)
+	if err != nil {
+		return CodecDecision{}, err
+	}
+
+	best := comparisons[0] // JPEG-LS
+	for _, c := range comparisons {
+		d.Ratios[c.Name] = c.Ratio
+		if c.Ratio > best.Ratio*selectMargin {
+			best = c
+		}
+	}
+	d.Codec, d.Name = best.Codec, best.Name
+	if best.Codec == CodecJPEGLS {
+		d.Reason = fmt.Sprintf("trial ratio %.2fx, no candidate better by more than %.0f%%", best.Ratio, 100*(selectMargin-1))
+	} else {
+		d.Reason = fmt.Sprintf("trial ratio %.2fx beats jpeg-ls %.2fx", best.Ratio, d.Ratios[CodecJPEGLS.Name()])
+	}
+	return d, nil
+}
+
+// centerRegion returns the central region of a frame, at most size pixels
+// on a side, with its rows and columns
+func centerRegion(frame []uint16, rows, cols, size int) ([]uint16, int, int) {
+	if rows <= size && cols <= size {
+		return frame, rows, cols
+	}
+	sr, sc := min(rows, size), min(cols, size)
+	top, left := (rows-sr)/2, (cols-sc)/2
+	out := make([]uint16, 0, sr*sc)
+	for y := top; y < top+sr; y++ {
+		out = append(out, frame[y*cols+left:y*cols+left+sc]...)
+	}
+	return out, sr, sc
+}
+
+// pixelStatistics returns the histogram entropy in bits per pixel, the mean
+// absolute horizontal gradient and the share of pixels equal to their left
+// neighbour
+func pixelStatistics(data []uint16, rows, cols int) (entropy, gradient, runs float64) {
+	counts := make(map[uint16]int)
+	var diff float64
+	var equal, pairs int
+	for y := range rows {
+		row := data[y*cols : (y+1)*cols]
+		for x, v := range row {
+			counts[v]++
+			if x == 0 {
+				continue
+			}
+			pairs++
+			if v == row[x-1] {
+				equal++
+			}
+			diff += math.Abs(float64(v) - float64(row[x-1]))
+		}
+	}
+	n := float64(len(data))
+	for _, c := range counts {
+		p := float64(c) / n
+		entropy -= p * math.Log2(p)
+	}
+	if pairs > 0 {
+		gradient = diff / float64(pairs)
+		runs = float64(equal) / float64(pairs)
+	}
+	return entropy, gradient, runs
+}
+
+// WithCodecDecision records a codec decision in the private block
+// (0011,11xx) reserved by "DICOS.GO CODEC", as the codec name and the
+// decision in JSON. Read it back with GetCodecDecision.
+func WithCodecDecision(d CodecDecision) Option {
+	return func(ds *Dataset) error {
+		if creator, ok := ds.Elements[tag.CodecDecisionCreator]; ok {
+			if s, _ := creator.GetString(); s != codecDecisionCreator {
+				return fmt.Errorf("private block (0011,11xx) is reserved by %q", s)
+			}
+		}
+		record, err := json.Marshal(d)
+		if err != nil {
+			return err
+		}
+		for _, opt := range []Option{
+			withVR(tag.CodecDecisionCreator, "LO", codecDecisionCreator),
+			withVR(tag.CodecDecisionName, "LO", d.Name),
+			withVR(tag.CodecDecisionRecord, "UT", string(record)),
+		} {
+			if err := opt(ds); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// GetCodecDecision returns the codec decision stored by WithCodecDecision,
+// with Codec resolved from its name
+func GetCodecDecision(ds *Dataset) (CodecDecision, bool) {
+	creator, ok := ds.Elements[tag.CodecDecisionCreator]
+	if !ok {
+		return CodecDecision{}, false
+	}
+	if s, _ := creator.GetString(); s != codecDecisionCreator {
+		return CodecDecision{}, false
+	}
+	var d CodecDecision
+	if err := json.Unmarshal([]byte(stringValue(ds, tag.CodecDecisionRecord)), &d); err != nil {
+		return CodecDecision{}, false
+	}
+	d.Codec = CodecByName(d.Name)
+	return d, true
+}
@@ -0,0 +1,88 @@
+package dicos
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectCodecFor(t *testing.T) {
+	const rows, cols = 64, 64
+	rng := rand.New(rand.NewSource(1))
+	noisy := make([]uint16, rows*cols)
+	for i := range noisy {
+		noisy[i] = uint16(1000 + i%cols*8 + rng.Intn(64))
+	}
+	blocky := make([]uint16, rows*cols)
+	for i := range blocky {
+		blocky[i] = uint16(i / cols / 16 * 500)
+	}
+
+	for name, tc := range map[string]struct {
+		data    []uint16
+		tryRLE  bool
+		entropy func(float64) bool
+	}{
+		"noisy":  {data: noisy, tryRLE: false, entropy: func(e float64) bool { return e > 6 }},
+		"blocky": {data: blocky, tryRLE: true, entropy: func(e float64) bool { return e == 2 }},
+	} {
+		t.Run(name, func(t *testing.T) {
+			d, err := SelectCodecFor(tc.data, rows, cols)
+			require.NoError(t, err)
+			require.NotNil(t, d.Codec)
+			assert.Equal(t, d.Codec.Name(), d.Name)
+			assert.True(t, tc.entropy(d.Entropy), "entropy %.2f", d.Entropy)
+			assert.Equal(t, rows, d.Rows)
+			assert.NotEmpty(t, d.Reason)
+			assert.Contains(t, d.Ratios, "jpeg-ls")
+			assert.Contains(t, d.Ratios, "jpeg-2000")
+			_, triedRLE := d.Ratios["rle"]
+			assert.Equal(t, tc.tryRLE, triedRLE)
+			for _, ratio := range d.Ratios {
+				assert.LessOrEqual(t, ratio, d.Ratios[d.Name]*selectMargin, "a better codec was passed over")
+			}
+		})
+	}
+
+	_, err := SelectCodecFor(noisy[:10], rows, cols)
+	assert.Error(t, err)
+}
+
+func TestSelectCodecFor_SamplesLargeAndMultiFrame(t *testing.T) {
+	const rows, cols = 300, 400
+	data := make([]uint16, 3*rows*cols)
+	for i := range data[rows*cols : 2*rows*cols] {
+		data[rows*cols+i] = uint16(i % 7)
+	}
+	d, err := SelectCodecFor(data, rows, cols)
+	require.NoError(t, err)
+	assert.Equal(t, 256, d.Rows)
+	assert.Equal(t, 256, d.Cols)
+	assert.Greater(t, d.Entropy, 2.0, "statistics come from the middle frame")
+}
+
+func TestWithCodecDecision(t *testing.T) {
+	d, err := SelectCodecFor(make([]uint16, 16*16), 16, 16)
+	require.NoError(t, err)
+
+	ds, err := NewDataset(WithElement(tag.Modality, "CT"), WithCodecDecision(d))
+	require.NoError(t, err)
+	assert.Equal(t, d.Name, stringValue(ds, tag.CodecDecisionName))
+
+	ds = rewrite(t, ds)
+	got, ok := GetCodecDecision(ds)
+	require.True(t, ok)
+	assert.Equal(t, d, got)
+
+	empty, err := NewDataset()
+	require.NoError(t, err)
+	_, ok = GetCodecDecision(empty)
+	assert.False(t, ok)
+
+	taken, err := NewDataset(withVR(tag.CodecDecisionCreator, "LO", "SOMEONE ELSE"))
+	require.NoError(t, err)
+	assert.Error(t, WithCodecDecision(d)(taken))
+}
@@ -0,0 +1,135 @@
+package dicos
+
+import (
+	"bytes"
+	"image"
+	"maps"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pluginCodec stands in for an external codec, counting the frames it decodes
+type pluginCodec struct {
+	Codec
+	name    string
+	uid     string
+	decoded atomic.Int32
+}
+
+func (c *pluginCodec) Name() string              { return c.name }
+func (c *pluginCodec) TransferSyntaxUID() string { return c.uid }
+
+func (c *pluginCodec) Decode(data []byte, width, height int) (image.Image, error) {
+	c.decoded.Add(1)
+	return c.Codec.Decode(data, width, height)
+}
+
+// restoreCodecs undoes the registrations of a test
+func restoreCodecs(t *testing.T) {
+	codecsMu.RLock()
+	byName, byTS := maps.Clone(codecsByName), maps.Clone(codecsByTS)
+	codecsMu.RUnlock()
+	t.Cleanup(func() {
+		codecsMu.Lock()
+		defer codecsMu.Unlock()
+		codecsByName, codecsByTS = byName, byTS
+	})
+}
+
+func TestRegisterCodec(t *testing.T) {
+	restoreCodecs(t)
+	const uid = "1.2.826.0.1.3680043.10.1.1" // a private syntax carrying RLE frames
+	plugin := &pluginCodec{Codec: CodecRLE, name: "acme-rle", uid: uid}
+	RegisterCodec(uid, plugin)
+	assert.Same(t, plugin, CodecByName("acme-rle"))
+	assert.Same(t, plugin, CodecByTransferSyntax(uid))
+
+	ds, err := ReadBuffer(writeTestCT(t, 8, 8, 1, plugin))
+	require.NoError(t, err)
+	assert.Equal(t, uid, string(ds.TransferSyntax()))
+	vol, err := DecodeVolume(ds)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), plugin.decoded.Load())
+	assert.Equal(t, uint16(63), vol.Data[63])
+	assert.Contains(t, SupportedTransferSyntaxes(), TransferSyntaxSupport{
+		UID: TransferSyntax(uid), Name: TransferSyntax(uid).Name(), Encapsulated: true, Codec: "acme-rle", Read: true, Write: true,
+	})
+}
+
+func TestRegisterCodec_ReplacesBuiltin(t *testing.T) {
+	restoreCodecs(t)
+	data := writeTestCT(t, 8, 8, 1, CodecJPEGLS)
+	plugin := &pluginCodec{Codec: CodecJPEGLS, name: "jpeg-ls-plugin", uid: CodecJPEGLS.TransferSyntaxUID()}
+	RegisterCodec(plugin.uid, plugin)
+	assert.NotSame(t, plugin, CodecJPEGLS, "predefined codecs are unchanged")
+
+	ds, err := ReadBuffer(data)
+	require.NoError(t, err)
+	pd, err := ds.GetPixelData()
+	require.NoError(t, err)
+	assert.Same(t, plugin, sniffCodec(pd.Frames[0].CompressedData))
+	_, err = DecodeVolume(ds)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), plugin.decoded.Load())
+
+	// frames sniffed with no transfer syntax resolve to the plug-in as well
+	_, err = DecodeFrameData(pd, 0, 8, 8, "")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), plugin.decoded.Load())
+}
+
+func TestRegisterCodec_Invalid(t *testing.T) {
+	assert.Panics(t, func() { RegisterCodec("1.2.840.10008.1.2.4.80", nil) })
+	assert.Panics(t, func() { RegisterCodec("", CodecRLE) })
+	assert.Panics(t, func() { RegisterCodec(string(ExplicitVRLittleEndian), CodecRLE) })
+	assert.Nil(t, CodecByTransferSyntax(string(ExplicitVRLittleEndian)))
+}
+
+func TestJPEGLS_FrameHeader(t *testing.T) {
+	img := image.NewGray16(image.Rect(0, 0, 8, 8))
+	var buf bytes.Buffer
+	require.NoError(t, CodecJPEGLS.Encode(&buf, img))
+	valid := buf.Bytes()
+	sof := bytes.Index(valid, []byte{0xFF, 0xF7})
+	require.Positive(t, sof)
+
+	_, err := CodecJPEGLS.Decode(valid, 16, 16)
+	require.NoError(t, err, "a stream smaller than the frame is decoded")
+	_, err = CodecJPEGLS.Decode(valid, 4, 4)
+	assert.ErrorContains(t, err, "stream is 8x8, frame is 4x4")
+
+	forged := bytes.Clone(valid)
+	forged[sof+5], forged[sof+6] = 0xFF, 0xFF // height
+	_, err = CodecJPEGLS.Decode(forged, 8, 8)
+	assert.ErrorContains(t, err, "stream is 8x65535")
+
+	for _, data := range [][]byte{valid[:sof], valid[:sof+6], {0xFF, 0xD8, 0x00}} {
+		_, err = CodecJPEGLS.Decode(data, 8, 8)
+		assert.Error(t, err)
+	}
+}
+
+func FuzzJPEGLSDecode(f *testing.F) {
+	img := image.NewGray16(image.Rect(0, 0, 8, 8))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i * 29)
+	}
+	var buf bytes.Buffer
+	require.NoError(f, CodecJPEGLS.Encode(&buf, img))
+	valid := buf.Bytes()
+	f.Add(valid)
+	for _, off := range []int{4, 7, 9, 11, 20, len(valid) - 3} {
+		b := bytes.Clone(valid)
+		b[off] ^= 0xFF
+		f.Add(b)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		img, err := CodecJPEGLS.Decode(data, 8, 8)
+		if err == nil {
+			assert.NotNil(t, img)
+		}
+	})
+}
@@ -0,0 +1,119 @@
+package dicos
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// 8-bit and color samples travel through the pixel pipeline in []uint16
+// like any other, one sample per element: an 8-bit sample holds 0-255, and
+// an RGB pixel is three consecutive samples R, G, B (PlanarConfiguration 0).
+// Native frames of such data are written one byte per sample (OB).
+
+// WithGray8PixelData adds 8-bit grayscale pixel data, such as an AIT
+// rendering, with the Image Pixel attributes that describe it: one sample
+// per pixel, MONOCHROME2, 8 bits allocated and stored, unsigned. Frames are
+// split as by WithPixelData.
+//
+// Example:
+//
+//	ds, _ := dicos.NewDataset(dicos.WithGray8PixelData(480, 640, pixels, nil))
+func WithGray8PixelData(rows, cols int, data []uint8, codec Codec) Option {
+	return func(ds *Dataset) error {
+		if err := withImagePixel(ds, 1, "MONOCHROME2"); err != nil {
+			return err
+		}
+		return WithPixelData(rows, cols, 8, widen8(data), codec)(ds)
+	}
+}
+
+// WithRGBPixelData adds 8-bit RGB pixel data, three interleaved samples per
+// pixel in R, G, B order, with the Image Pixel attributes that describe it:
+// three samples per pixel, RGB, PlanarConfiguration 0, 8 bits allocated and
+// stored, unsigned. A codec must support color, see CodecJPEG2000; others
+// fail when the dataset is built.
+//
+// Example:
+//
+//	ds, _ := dicos.NewDataset(dicos.WithRGBPixelData(480, 640, rgb, dicos.CodecJPEG2000))
+func WithRGBPixelData(rows, cols int, data []uint8, codec Codec) Option {
+	return func(ds *Dataset) error {
+		if err := withImagePixel(ds, 3, "RGB"); err != nil {
+			return err
+		}
+		return WithPixelData(rows, cols, 8, widen8(data), codec)(ds)
+	}
+}
+
+// withImagePixel sets the Image Pixel attributes of 8-bit unsigned data
+func withImagePixel(ds *Dataset, samples int, photometric string) error {
+	opts := []Option{
+		WithElement(tag.SamplesPerPixel, uint16(samples)),
+		WithElement(tag.PhotometricInterpretation, photometric),
+		WithElement(tag.BitsAllocated, uint16(8)),
+		WithElement(tag.BitsStored, uint16(8)),
+		WithElement(tag.HighBit, uint16(7)),
+		WithElement(tag.PixelRepresentation, uint16(0)),
+	}
+	if samples > 1 {
+		opts = append(opts, WithElement(tag.PlanarConfiguration, uint16(0)))
+	} else {
+		delete(ds.Elements, tag.PlanarConfiguration)
+	}
+	for _, opt := range opts {
+		if err := opt(ds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Uint8Samples returns 8-bit samples as bytes, for reading back data set
+// with WithGray8PixelData or WithRGBPixelData
+func Uint8Samples(data []uint16) []uint8 {
+	out := make([]uint8, len(data))
+	for i, v := range data {
+		out[i] = uint8(v)
+	}
+	return out
+}
+
+// widen8 returns 8-bit samples as the []uint16 of the pixel pipeline
+func widen8(data []uint8) []uint16 {
+	out := make([]uint16, len(data))
+	for i, v := range data {
+		out[i] = uint16(v)
+	}
+	return out
+}
+
+// rgbImage returns interleaved 8-bit RGB samples as an image
+func rgbImage(data []uint16, rows, cols int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, cols, rows))
+	for i := range min(rows*cols, len(data)/3) {
+		img.Pix[i*4] = uint8(data[i*3])
+		img.Pix[i*4+1] = uint8(data[i*3+1])
+		img.Pix[i*4+2] = uint8(data[i*3+2])
+		img.Pix[i*4+3] = 0xFF
+	}
+	return img
+}
+
+// encodeRGBFrame compresses one frame of interleaved 8-bit RGB samples with
+// codec, padded by the codec's FragmentPadding policy
+func encodeRGBFrame(codec Codec, data []uint16, rows, cols, bitsAllocated int) ([]byte, error) {
+	if !rgbCodecs[codec.Name()] {
+		return nil, fmt.Errorf("codec %s does not support RGB", codec.Name())
+	}
+	if bitsAllocated != 8 {
+		return nil, fmt.Errorf("RGB pixel data must be 8-bit, got %d bits allocated", bitsAllocated)
+	}
+	var buf bytes.Buffer
+	if err := codec.Encode(&buf, rgbImage(data, rows, cols)); err != nil {
+		return nil, fmt.Errorf("%s encode error: %w", codec.Name(), err)
+	}
+	return padFragment(buf.Bytes(), paddingOf(codec)), nil
+}
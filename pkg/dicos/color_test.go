@@ -0,0 +1,187 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRGB returns frames of rows x cols interleaved RGB samples
+func testRGB(rows, cols, frames int) []uint8 {
+	data := make([]uint8, rows*cols*3*frames)
+	for i := range data {
+		data[i] = uint8(i*7 + i/3)
+	}
+	return data
+}
+
+// roundTrip writes a dataset built from opts with codec's transfer syntax
+// and reads it back
+func roundTrip(t *testing.T, codec Codec, opts ...Option) *Dataset {
+	t.Helper()
+	return rewrite(t, newTestDataset(t, DICOSAIT2DImageStorageUID, codec, opts...))
+}
+
+func TestWithGray8PixelData(t *testing.T) {
+	const rows, cols, frames = 6, 5, 2
+	gray := make([]uint8, rows*cols*frames)
+	for i := range gray {
+		gray[i] = uint8(i * 9)
+	}
+	for _, codec := range []Codec{nil, CodecJPEGLS, CodecRLE, CodecJPEG2000} {
+		name := "native"
+		opts := []Option{WithElement(tag.Rows, uint16(rows)), WithElement(tag.Columns, uint16(cols)), WithElement(tag.NumberOfFrames, "2")}
+		if codec != nil {
+			name = codec.Name()
+		}
+		t.Run(name, func(t *testing.T) {
+			ds := roundTrip(t, codec, append(opts, WithGray8PixelData(rows, cols, gray, codec))...)
+			assert.Equal(t, 8, ds.BitsAllocated())
+			assert.Equal(t, 1, ds.SamplesPerPixel())
+			assert.Equal(t, "MONOCHROME2", stringValue(ds, tag.PhotometricInterpretation))
+			if codec == nil {
+				raw, ok := ds.Elements[tag.PixelData].Value.([]byte)
+				require.True(t, ok, "native 8-bit pixel data is read as bytes")
+				assert.Len(t, raw, rows*cols*frames, "one byte per sample")
+			}
+
+			vol, err := DecodeVolume(ds)
+			require.NoError(t, err)
+			assert.Equal(t, 1, vol.Samples)
+			assert.Equal(t, gray, Uint8Samples(vol.Data))
+		})
+	}
+}
+
+func TestWithRGBPixelData(t *testing.T) {
+	const rows, cols, frames = 4, 6, 2
+	rgb := testRGB(rows, cols, frames)
+	for _, codec := range []Codec{nil, CodecJPEG2000} {
+		name := "native"
+		opts := []Option{WithElement(tag.Rows, uint16(rows)), WithElement(tag.Columns, uint16(cols)), WithElement(tag.NumberOfFrames, "2")}
+		if codec != nil {
+			name = codec.Name()
+		}
+		t.Run(name, func(t *testing.T) {
+			ds := roundTrip(t, codec, append(opts, WithRGBPixelData(rows, cols, rgb, codec))...)
+			assert.Equal(t, 3, ds.SamplesPerPixel())
+			assert.Equal(t, "RGB", stringValue(ds, tag.PhotometricInterpretation))
+			pc, _ := ds.Elements[tag.PlanarConfiguration].GetInt()
+			assert.Equal(t, 0, pc)
+
+			vol, err := DecodeVolume(ds)
+			require.NoError(t, err)
+			require.Equal(t, 3, vol.Samples)
+			assert.Equal(t, rgb, Uint8Samples(vol.Data))
+			i := ((1*rows+2)*cols + 3) * 3
+			assert.Equal(t, uint16(rgb[i+1]), vol.GetSample(3, 2, 1, 1))
+			assert.Equal(t, uint16(rgb[i]), vol.Get(3, 2, 1))
+			img := vol.RGBA(1)
+			require.NotNil(t, img)
+			c := img.RGBAAt(3, 2)
+			assert.Equal(t, [3]uint8{rgb[i], rgb[i+1], rgb[i+2]}, [3]uint8{c.R, c.G, c.B})
+
+			var streamed []uint8
+			err = DecodeSlices(context.Background(), ds, func(z int, slice []uint16) error {
+				assert.Len(t, slice, rows*cols*3)
+				streamed = append(streamed, Uint8Samples(slice)...)
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, rgb, streamed)
+		})
+	}
+
+	_, err := NewDataset(WithRGBPixelData(rows, cols, rgb, CodecJPEGLS))
+	assert.ErrorContains(t, err, "does not support RGB")
+}
+
+func TestSetRGBPixelData(t *testing.T) {
+	const rows, cols = 4, 6
+	rgb := testRGB(rows, cols, 1)
+	for _, codec := range []Codec{nil, CodecJPEG2000} {
+		ait := NewAIT2DImage()
+		ait.SetRGBPixelData(rows, cols, rgb)
+		ait.Codec = codec
+		var buf bytes.Buffer
+		_, err := ait.WriteTo(&buf)
+		require.NoError(t, err)
+		ds, err := ReadBuffer(buf.Bytes())
+		require.NoError(t, err)
+		assert.Equal(t, 3, ds.SamplesPerPixel())
+		assert.NotContains(t, ds.Elements, tag.WindowCenter)
+		vol, err := DecodeVolume(ds)
+		require.NoError(t, err)
+		assert.Equal(t, rgb, Uint8Samples(vol.Data))
+
+		ct := NewCTImage()
+		ct.SetRGBPixelData(rows, cols, rgb)
+		ct.Codec = codec
+		buf.Reset()
+		_, err = ct.WriteTo(&buf)
+		require.NoError(t, err)
+		ds, err = ReadBuffer(buf.Bytes())
+		require.NoError(t, err)
+		assert.Equal(t, "RGB", stringValue(ds, tag.PhotometricInterpretation))
+		vol, err = DecodeVolume(ds)
+		require.NoError(t, err)
+		assert.Equal(t, rgb, Uint8Samples(vol.Data))
+	}
+
+	// 8-bit grayscale, then back to 16 bits
+	ct := NewCTImage()
+	ct.SetGray8PixelData(rows, cols, rgb[:rows*cols])
+	ds, err := ct.GetDataset()
+	require.NoError(t, err)
+	assert.Equal(t, 8, ds.BitsAllocated())
+	assert.Equal(t, "OB", ds.Elements[tag.PixelData].VR)
+	ct.SetPixelData(rows, cols, make([]uint16, rows*cols))
+	ds, err = ct.GetDataset()
+	require.NoError(t, err)
+	assert.Equal(t, 16, ds.BitsAllocated())
+	assert.Equal(t, "OW", ds.Elements[tag.PixelData].VR)
+}
+
+// TestVolume_PlanarConfiguration reads native color frames stored one plane
+// per sample into interleaved voxels
+func TestVolume_PlanarConfiguration(t *testing.T) {
+	planar := []uint16{1, 2, 3, 4, 10, 20, 30, 40, 100, 200, 250, 255}
+	ds, err := NewDataset(
+		WithElement(tag.Rows, uint16(2)),
+		WithElement(tag.Columns, uint16(2)),
+		WithElement(tag.SamplesPerPixel, uint16(3)),
+		WithElement(tag.PlanarConfiguration, uint16(1)),
+		WithElement(tag.BitsAllocated, uint16(8)),
+		WithPixelData(2, 2, 8, planar, nil),
+	)
+	require.NoError(t, err)
+	vol, err := VolumeFromDataset(ds)
+	require.NoError(t, err)
+	assert.Equal(t, []uint16{1, 10, 100, 2, 20, 200, 3, 30, 250, 4, 40, 255}, vol.Data)
+	assert.Equal(t, []uint16{10, 20, 30, 40}, vol.Channel(1))
+}
+
+func TestVolume_Samples(t *testing.T) {
+	vol := NewVolumeSamples(2, 3, 2, 3)
+	for i := range vol.Data {
+		vol.Data[i] = uint16(i)
+	}
+	vol.SetSample(1, 2, 1, 2, 999)
+	assert.Equal(t, uint16(999), vol.GetSample(1, 2, 1, 2))
+	assert.Equal(t, uint16(0), vol.GetSample(1, 2, 1, 3), "out of range sample")
+
+	// coronal slice at y=0 holds the three samples of each voxel
+	assert.Equal(t, []uint16{0, 1, 2, 3, 4, 5, 18, 19, 20, 21, 22, 23}, vol.Slice(1, 0))
+
+	// transforms move whole voxels
+	flipped := vol.Transform(FlipH)
+	require.Equal(t, 3, flipped.Samples)
+	for s := range 3 {
+		assert.Equal(t, vol.GetSample(0, 1, 1, s), flipped.GetSample(1, 1, 1, s))
+	}
+	assert.Nil(t, NewVolume(2, 2, 1).RGBA(0))
+}
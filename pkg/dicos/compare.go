@@ -0,0 +1,255 @@
+package dicos
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// DiffKind tells how an element differs between two datasets
+type DiffKind int
+
+const (
+	// DiffAdded elements are only in the second dataset
+	DiffAdded DiffKind = iota + 1
+	// DiffRemoved elements are only in the first dataset
+	DiffRemoved
+	// DiffChanged elements are in both with a different VR or value
+	DiffChanged
+)
+
+func (k DiffKind) String() string {
+	switch k {
+	case DiffAdded:
+		return "added"
+	case DiffRemoved:
+		return "removed"
+	case DiffChanged:
+		return "changed"
+	default:
+		return "unknown"
+	}
+}
+
+// CompareOptions controls Compare
+type CompareOptions struct {
+	// Ignore lists tags left out of the comparison, at any depth, such as
+	// SOPInstanceUID after an anonymizer
+	Ignore []Tag
+	// IgnoreFileMeta leaves out the File Meta Information (group 0002),
+	// which a transcode or a different writer changes
+	IgnoreFileMeta bool
+	// DecodePixels checksums the decoded samples instead of the stored
+	// frames, so a lossless transcode has matching pixel data
+	DecodePixels bool
+}
+
+// ElementDiff is an element added, removed or changed between two datasets
+type ElementDiff struct {
+	// Path locates the element: its tag, after the sequence and item it is
+	// nested in, e.g. "(4010,1010)[0].(4010,1028)"
+	Path string
+	Tag  Tag
+	Kind DiffKind
+	Old  *Element // nil when added
+	New  *Element // nil when removed
+}
+
+func (d ElementDiff) String() string {
+	name := d.Tag.LookupName()
+	if name != "" {
+		name = " " + name
+	}
+	switch d.Kind {
+	case DiffAdded:
+		return fmt.Sprintf("+ %s%s: %s", d.Path, name, diffValue(d.New))
+	case DiffRemoved:
+		return fmt.Sprintf("- %s%s: %s", d.Path, name, diffValue(d.Old))
+	default:
+		return fmt.Sprintf("~ %s%s: %s -> %s", d.Path, name, diffValue(d.Old), diffValue(d.New))
+	}
+}
+
+// diffValue renders an element as its VR and value
+func diffValue(e *Element) string {
+	return e.VR + " " + valueString(e)
+}
+
+// PixelDataDiff reports pixel data whose checksums differ
+type PixelDataDiff struct {
+	OldChecksum string // hex SHA-256, empty when absent
+	NewChecksum string
+	Decoded     bool // the checksums are of decoded samples
+}
+
+func (d PixelDataDiff) String() string {
+	what := "stored"
+	if d.Decoded {
+		what = "decoded"
+	}
+	return fmt.Sprintf("~ PixelData %s checksum: %s -> %s", what, shortChecksum(d.OldChecksum), shortChecksum(d.NewChecksum))
+}
+
+// shortChecksum abbreviates a checksum for display
+func shortChecksum(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s[:min(len(s), 16)]
+}
+
+// DatasetDiff is the result of Compare
+type DatasetDiff struct {
+	Elements  []ElementDiff  // in tag order, nested elements after their sequence
+	PixelData *PixelDataDiff // nil when the pixel data match
+}
+
+// Equal returns true when no difference was found
+func (d DatasetDiff) Equal() bool {
+	return len(d.Elements) == 0 && d.PixelData == nil
+}
+
+// String lists the differences one per line
+func (d DatasetDiff) String() string {
+	var b strings.Builder
+	for _, e := range d.Elements {
+		b.WriteString(e.String())
+		b.WriteString("\n")
+	}
+	if d.PixelData != nil {
+		b.WriteString(d.PixelData.String())
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Compare returns the elements added, removed and changed from a to b, and
+// whether their pixel data differ. Values are compared as written, so a value
+// read from a file matches the same value given to a builder. Sequences are
+// compared item by item; a different item count is a change of the sequence.
+// Pixel data is compared by checksum, of the stored frames or, with
+// DecodePixels, of the decoded samples.
+//
+// Example:
+//
+//	diff, err := dicos.Compare(before, after, dicos.CompareOptions{
+//		IgnoreFileMeta: true,
+//		DecodePixels:   true,
+//	})
+//	if err == nil && !diff.Equal() {
+//		fmt.Print(diff)
+//	}
+func Compare(a, b *Dataset, opts CompareOptions) (DatasetDiff, error) {
+	ignore := make(map[Tag]bool, len(opts.Ignore)+1)
+	for _, t := range opts.Ignore {
+		ignore[t] = true
+	}
+	ignore[tag.PixelData] = true // compared by checksum below
+
+	skip := func(t Tag) bool {
+		return ignore[t] || opts.IgnoreFileMeta && t.Group == 0x0002
+	}
+	diff := DatasetDiff{Elements: compareElements(a, b, "", skip)}
+
+	pa, err := pixelChecksum(a, opts.DecodePixels)
+	if err != nil {
+		return diff, fmt.Errorf("first dataset: %w", err)
+	}
+	pb, err := pixelChecksum(b, opts.DecodePixels)
+	if err != nil {
+		return diff, fmt.Errorf("second dataset: %w", err)
+	}
+	if pa != pb {
+		diff.PixelData = &PixelDataDiff{OldChecksum: pa, NewChecksum: pb, Decoded: opts.DecodePixels}
+	}
+	return diff, nil
+}
+
+// compareElements diffs the elements of two datasets, or sequence items,
+// whose path is prefix
+func compareElements(a, b *Dataset, prefix string, skip func(Tag) bool) []ElementDiff {
+	merged := &Dataset{Elements: make(map[Tag]*Element, len(a.Elements))}
+	for t, e := range a.Elements {
+		merged.Elements[t] = e
+	}
+	for t, e := range b.Elements {
+		merged.Elements[t] = e
+	}
+
+	var out []ElementDiff
+	for _, t := range sortedTags(merged) {
+		if skip(t) {
+			continue
+		}
+		path := prefix + t.String()
+		old, inA := a.Elements[t]
+		cur, inB := b.Elements[t]
+		switch {
+		case !inB:
+			out = append(out, ElementDiff{Path: path, Tag: t, Kind: DiffRemoved, Old: old})
+		case !inA:
+			out = append(out, ElementDiff{Path: path, Tag: t, Kind: DiffAdded, New: cur})
+		default:
+			oldItems, oldSeq := old.Value.([]*Dataset)
+			curItems, curSeq := cur.Value.([]*Dataset)
+			if oldSeq && curSeq && len(oldItems) == len(curItems) {
+				for i := range oldItems {
+					out = append(out, compareElements(oldItems[i], curItems[i], fmt.Sprintf("%s[%d].", path, i), skip)...)
+				}
+				continue
+			}
+			if !sameElement(old, cur) {
+				out = append(out, ElementDiff{Path: path, Tag: t, Kind: DiffChanged, Old: old, New: cur})
+			}
+		}
+	}
+	return out
+}
+
+// sameElement returns true when two elements have the same VR and would be
+// written with the same value
+func sameElement(a, b *Element) bool {
+	if a.VR != b.VR {
+		return false
+	}
+	va, _, errA := encodeValue(a.Value, a.VR)
+	vb, _, errB := encodeValue(b.Value, b.VR)
+	if errA != nil || errB != nil {
+		return formatValue(a.Value) == formatValue(b.Value)
+	}
+	return bytes.Equal(va, vb)
+}
+
+// pixelChecksum returns the hex SHA-256 of the pixel data of ds, of its
+// stored frames or of its decoded samples, or "" when it has none
+func pixelChecksum(ds *Dataset, decode bool) (string, error) {
+	if _, ok := ds.FindElement(tag.PixelData.Group, tag.PixelData.Element); !ok {
+		return "", nil
+	}
+	h := sha256.New()
+	if decode {
+		vol, err := DecodeVolume(ds)
+		if err != nil {
+			return "", err
+		}
+		binary.Write(h, binary.LittleEndian, vol.Data)
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+	pd, err := ds.GetPixelData()
+	if err != nil {
+		return "", err
+	}
+	for _, f := range pd.Frames {
+		if pd.IsEncapsulated {
+			h.Write(f.CompressedData)
+		} else {
+			binary.Write(h, binary.LittleEndian, f.Data)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,110 @@
+package dicos
+
+import (
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/jpfielding/dicos.go/pkg/dicos/transfer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare_Elements(t *testing.T) {
+	a, err := ReadBuffer(writeTestCT(t, 4, 4, 1, nil))
+	require.NoError(t, err)
+	b := CloneDataset(a)
+
+	diff, err := Compare(a, b, CompareOptions{})
+	require.NoError(t, err)
+	assert.True(t, diff.Equal(), diff.String())
+
+	require.NoError(t, WithElement(tag.PatientID, "READER-002")(b))
+	require.NoError(t, WithElement(tag.StudyDescription, "Checkpoint 4")(b))
+	delete(b.Elements, tag.PatientAge)
+	require.NoError(t, WithElement(tag.PatientAge, "")(a))
+
+	diff, err = Compare(a, b, CompareOptions{})
+	require.NoError(t, err)
+	require.Len(t, diff.Elements, 3, diff.String())
+	assert.Equal(t, ElementDiff{Path: "(0008,1030)", Tag: tag.StudyDescription, Kind: DiffChanged,
+		Old: a.Elements[tag.StudyDescription], New: b.Elements[tag.StudyDescription]}, diff.Elements[0])
+	assert.Equal(t, DiffChanged, diff.Elements[1].Kind)
+	assert.Equal(t, "READER-001", diff.Elements[1].Old.Value)
+	assert.Equal(t, DiffRemoved, diff.Elements[2].Kind)
+	assert.Nil(t, diff.PixelData)
+	assert.Contains(t, diff.String(), "~ (0010,0020) PatientID: LO READER-001 -> LO READER-002")
+
+	diff, err = Compare(a, b, CompareOptions{Ignore: []Tag{tag.PatientID, tag.StudyDescription, tag.PatientAge}})
+	require.NoError(t, err)
+	assert.True(t, diff.Equal(), diff.String())
+
+	// values compare as written: a value read with its padding matches the
+	// value given to a builder
+	require.NoError(t, WithElement(tag.StationName, "LANE1 ")(a))
+	require.NoError(t, WithElement(tag.StationName, "LANE1")(b))
+	assert.True(t, sameElement(a.Elements[tag.StationName], b.Elements[tag.StationName]))
+}
+
+func TestCompare_Sequences(t *testing.T) {
+	item := func(label string) *Dataset {
+		ds, err := NewDataset(WithElement(tag.ThreatCategoryDescription, label))
+		require.NoError(t, err)
+		return ds
+	}
+	a, err := NewDataset(WithSequence(tag.PTOSequence, item("KNIFE"), item("GUN")))
+	require.NoError(t, err)
+	b, err := NewDataset(WithSequence(tag.PTOSequence, item("KNIFE"), item("BOTTLE")))
+	require.NoError(t, err)
+
+	diff, err := Compare(a, b, CompareOptions{})
+	require.NoError(t, err)
+	require.Len(t, diff.Elements, 1)
+	assert.Equal(t, "(4010,1010)[1].(4010,1028)", diff.Elements[0].Path)
+	assert.Equal(t, tag.ThreatCategoryDescription, diff.Elements[0].Tag)
+
+	c, err := NewDataset(WithSequence(tag.PTOSequence, item("KNIFE")))
+	require.NoError(t, err)
+	diff, err = Compare(a, c, CompareOptions{})
+	require.NoError(t, err)
+	require.Len(t, diff.Elements, 1)
+	assert.Equal(t, tag.PTOSequence, diff.Elements[0].Tag, "item count change")
+	assert.Contains(t, diff.String(), "SQ 2 items -> SQ 1 items")
+}
+
+func TestCompare_PixelData(t *testing.T) {
+	a, err := ReadBuffer(writeTestCT(t, 4, 4, 1, nil))
+	require.NoError(t, err)
+	b, err := Transcode(a, transfer.JPEGLSLossless)
+	require.NoError(t, err)
+
+	diff, err := Compare(a, b, CompareOptions{IgnoreFileMeta: true})
+	require.NoError(t, err)
+	require.NotNil(t, diff.PixelData, "stored frames differ")
+	assert.False(t, diff.PixelData.Decoded)
+	require.Len(t, diff.Elements, 1, diff.String())
+	assert.Equal(t, tag.LossyImageCompression, diff.Elements[0].Tag)
+	assert.Equal(t, DiffAdded, diff.Elements[0].Kind)
+
+	diff, err = Compare(a, b, CompareOptions{IgnoreFileMeta: true, DecodePixels: true, Ignore: []Tag{tag.LossyImageCompression}})
+	require.NoError(t, err)
+	assert.True(t, diff.Equal(), "lossless transcode: %s", diff)
+
+	pd, err := b.GetPixelData()
+	require.NoError(t, err)
+	vol, err := DecodeVolume(b)
+	require.NoError(t, err)
+	vol.Data[0]++
+	require.NoError(t, WithPixelData(4, 4, 16, vol.Data, CodecJPEGLS)(b))
+	require.NotSame(t, pd, b.Elements[tag.PixelData].Value)
+	diff, err = Compare(a, b, CompareOptions{IgnoreFileMeta: true, DecodePixels: true, Ignore: []Tag{tag.LossyImageCompression}})
+	require.NoError(t, err)
+	require.NotNil(t, diff.PixelData)
+	assert.True(t, diff.PixelData.Decoded)
+	assert.NotEqual(t, diff.PixelData.OldChecksum, diff.PixelData.NewChecksum)
+
+	delete(b.Elements, tag.PixelData)
+	diff, err = Compare(a, b, CompareOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, diff.PixelData.NewChecksum)
+	assert.Contains(t, diff.String(), "-> (none)")
+}
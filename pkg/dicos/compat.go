@@ -68,16 +68,14 @@ func DecodeFrame(fi *FrameInfo, rows, cols, bitsAllocated, pixelRep int) (*Nativ
 	data := make([]int, pixelCount)
 
 	// Sample a few pixels to debug
-	var minR, maxR uint32 = 0xFFFFFFFF, 0
+	var minR, maxR uint16 = 0xFFFF, 0
 
-	// IMPORTANT: Use RGBA() to extract values because our JPEG-LS decoder
-	// writes to the image but Gray16At().Y sometimes returns 0
-	// The analyze tool uses RGBA() and it works correctly
+	// Go through At, not Gray16At, because our JPEG-LS decoder may return
+	// either gray model; frameSample keeps 8-bit samples unscaled
 	for y := 0; y < bounds.Dy(); y++ {
 		for x := 0; x < bounds.Dx(); x++ {
 			idx := y*bounds.Dx() + x
-			r, _, _, _ := decoded.At(x, y).RGBA()
-			// RGBA() returns values in 0-65535 range
+			r := frameSample(decoded, x, y)
 			data[idx] = int(r)
 			if r < minR {
 				minR = r
@@ -0,0 +1,296 @@
+package dicos
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/dict"
+	"github.com/jpfielding/dicos.go/pkg/dicos/module"
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// ConformanceStatement is the skeleton of a DICOM/DICOS conformance
+// statement for the library: the transfer syntaxes it reads and writes, and
+// for each IOD its SOP class and the elements its builder writes, grouped by
+// module. Products built on the library start from it and describe their own
+// use of each attribute.
+type ConformanceStatement struct {
+	Version          string
+	TransferSyntaxes []TransferSyntaxSupport
+	IODs             []IODConformance
+}
+
+// IODConformance lists the modules written for an IOD
+type IODConformance struct {
+	IODSupport
+	Modules []ModuleConformance
+}
+
+// ModuleConformance lists the elements written for a module, in tag order
+type ModuleConformance struct {
+	Name     string
+	Elements []ElementConformance
+}
+
+// ElementConformance describes an element written by an IOD builder
+type ElementConformance struct {
+	Tag     tag.Tag
+	Keyword string // empty for tags missing from the dictionary
+	VR      string
+	Type    AttributeType // from the IOD's Validate requirements, 0 when not checked
+	Depth   int           // sequence nesting, 0 for top level elements
+}
+
+// Module names for elements not written by a module of the IOD builder
+const (
+	fileMetaModule   = "File Meta Information"
+	imagePixelModule = "Image Pixel"
+	otherModule      = "Additional Attributes"
+)
+
+// NewConformanceStatement builds a sample of every supported IOD with its
+// builder and records what is written. Elements are attributed to the modules
+// of the builder (its module.IODModule fields); the rest are File Meta
+// Information, Image Pixel or Additional Attributes.
+//
+// Example:
+//
+//	cs, err := dicos.NewConformanceStatement()
+//	if err != nil {
+//		return err
+//	}
+//	cs.WriteMarkdown(os.Stdout)
+func NewConformanceStatement() (*ConformanceStatement, error) {
+	cs := &ConformanceStatement{
+		Version:          Version(),
+		TransferSyntaxes: SupportedTransferSyntaxes(),
+	}
+	for _, iod := range SupportedIODs() {
+		builder := iodSample(iod.Name)
+		if builder == nil {
+			return nil, fmt.Errorf("no sample builder for IOD %s", iod.Name)
+		}
+		ds, err := builder.GetDataset()
+		if err != nil {
+			return nil, fmt.Errorf("building %s sample: %w", iod.Name, err)
+		}
+		cs.IODs = append(cs.IODs, IODConformance{
+			IODSupport: iod,
+			Modules:    conformanceModules(ds, builderModules(builder), iodRequirements[iod.Name]),
+		})
+	}
+	return cs, nil
+}
+
+// datasetBuilder is implemented by the IOD builders
+type datasetBuilder interface {
+	GetDataset() (*Dataset, error)
+}
+
+// iodSample returns a builder for the named IOD with the least content that
+// makes it write its optional parts, such as pixel data and sequences
+func iodSample(name string) datasetBuilder {
+	pixels := make([]uint16, 4*4)
+	switch name {
+	case "CT":
+		ct := NewCTImage()
+		ct.SetPixelData(4, 4, pixels)
+		return ct
+	case "DX":
+		dx := NewDXImage()
+		dx.SetPixelData(4, 4, pixels)
+		return dx
+	case "AIT2D":
+		ait := NewAIT2DImage()
+		ait.SetPixelData(4, 4, pixels)
+		return ait
+	case "AIT3D":
+		ait := NewAIT3DImage()
+		ait.SetPixelData(4, 4, 1, pixels)
+		return ait
+	case "TDR":
+		tdr := NewThreatDetectionReport()
+		tdr.PTOs = append(tdr.PTOs, PotentialThreatObject{Label: "SAMPLE"})
+		return tdr
+	case "QR":
+		qr := NewQRMeasurement()
+		qr.Acquisition.TransmitterFrequency = []float64{3.41}
+		return qr
+	case "SC":
+		sc := NewSecondaryCaptureImage()
+		sc.Image = image.NewRGBA(image.Rect(0, 0, 4, 4))
+		return sc
+	case "PDF":
+		doc := NewEncapsulatedPDF()
+		doc.Document = []byte("%PDF-1.4\n%%EOF\n")
+		return doc
+	}
+	return nil
+}
+
+// namedModule is a module of an IOD builder
+type namedModule struct {
+	name string
+	tags map[tag.Tag]bool
+}
+
+// builderModules returns the module.IODModule fields of builder, in field
+// order, each with the tags it writes
+func builderModules(builder datasetBuilder) []namedModule {
+	v := reflect.ValueOf(builder).Elem()
+	var out []namedModule
+	for i := 0; i < v.NumField(); i++ {
+		if !v.Type().Field(i).IsExported() {
+			continue
+		}
+		f := v.Field(i)
+		if f.Kind() != reflect.Pointer {
+			f = f.Addr()
+		}
+		if f.IsNil() {
+			continue
+		}
+		m, ok := f.Interface().(module.IODModule)
+		if !ok {
+			continue
+		}
+		nm := namedModule{name: moduleName(f.Type().Elem().Name()), tags: map[tag.Tag]bool{}}
+		for _, e := range m.ToTags() {
+			nm.tags[e.Tag] = true
+		}
+		out = append(out, nm)
+	}
+	return out
+}
+
+// moduleName turns a module type name such as FrameOfReferenceModule or
+// CTImageModule into "Frame of Reference" or "CT Image"
+func moduleName(typeName string) string {
+	s := []rune(strings.TrimSuffix(typeName, "Module"))
+	var b strings.Builder
+	for i, r := range s {
+		if i > 0 && unicode.IsUpper(r) &&
+			(unicode.IsLower(s[i-1]) || i+1 < len(s) && unicode.IsLower(s[i+1])) {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(r)
+	}
+	name := strings.ReplaceAll(b.String(), " Of ", " of ")
+	if name == "VOILUT" {
+		return "VOI LUT"
+	}
+	return name
+}
+
+// conformanceModules groups the elements of ds by the module that wrote them
+func conformanceModules(ds *Dataset, modules []namedModule, requirements []IODRequirement) []ModuleConformance {
+	types := make(map[tag.Tag]AttributeType, len(requirements))
+	for _, r := range requirements {
+		types[r.Tag] = r.Type
+	}
+	imagePixel := map[tag.Tag]bool{tag.PlanarConfiguration: true}
+	for _, r := range ImagePixelModuleRequirements {
+		imagePixel[r.Tag] = true
+	}
+
+	names := []string{fileMetaModule}
+	for _, m := range modules {
+		names = append(names, m.name)
+	}
+	names = append(names, imagePixelModule, otherModule)
+	byName := map[string][]ElementConformance{}
+	for _, t := range sortedTags(ds) {
+		name := otherModule
+		switch {
+		case t.Group == 0x0002:
+			name = fileMetaModule
+		case imagePixel[t]:
+			name = imagePixelModule
+		default:
+			for _, m := range modules {
+				if m.tags[t] {
+					name = m.name
+					break
+				}
+			}
+		}
+		elems := conformanceElements(ds.Elements[t], 0)
+		elems[0].Type = types[t]
+		byName[name] = append(byName[name], elems...)
+	}
+
+	var out []ModuleConformance
+	for _, name := range names {
+		if elems, ok := byName[name]; ok {
+			out = append(out, ModuleConformance{Name: name, Elements: elems})
+			delete(byName, name) // two fields of one module type are listed once
+		}
+	}
+	return out
+}
+
+// conformanceElements describes elem and, for a sequence, the elements of
+// its first item
+func conformanceElements(elem *Element, depth int) []ElementConformance {
+	e := ElementConformance{Tag: elem.Tag, VR: elem.VR, Depth: depth}
+	if entry, ok := dict.Lookup(elem.Tag); ok {
+		e.Keyword = entry.Keyword
+	}
+	out := []ElementConformance{e}
+	if items, ok := elem.Value.([]*Dataset); ok && len(items) > 0 {
+		for _, t := range sortedTags(items[0]) {
+			out = append(out, conformanceElements(items[0].Elements[t], depth+1)...)
+		}
+	}
+	return out
+}
+
+// WriteMarkdown writes the statement as Markdown tables, ready to be
+// completed with product specific details
+func (cs *ConformanceStatement) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# DICOS Conformance Statement\n\nLibrary: dicos.go %s\n\n", cs.Version)
+
+	b.WriteString("## Transfer Syntaxes\n\n| Name | UID | Read | Write |\n|------|-----|------|-------|\n")
+	for _, ts := range cs.TransferSyntaxes {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", ts.Name, ts.UID, yesNo(ts.Read), yesNo(ts.Write))
+	}
+
+	b.WriteString("\n## SOP Classes\n\n| IOD | SOP Class UID | Validated |\n|-----|---------------|-----------|\n")
+	for _, iod := range cs.IODs {
+		fmt.Fprintf(&b, "| %s | %s | %s |\n", iod.Name, iod.SOPClassUID, yesNo(iod.Validate))
+	}
+
+	for _, iod := range cs.IODs {
+		fmt.Fprintf(&b, "\n## %s IOD\n", iod.Name)
+		for _, m := range iod.Modules {
+			fmt.Fprintf(&b, "\n### %s\n\n| Attribute | Tag | VR | Type |\n|-----------|-----|----|------|\n", m.Name)
+			for _, e := range m.Elements {
+				keyword := e.Keyword
+				if keyword == "" {
+					keyword = "(unknown)"
+				}
+				typ := ""
+				if e.Type != 0 {
+					typ = strings.TrimPrefix(e.Type.String(), "Type ")
+				}
+				fmt.Fprintf(&b, "| %s%s | (%04X,%04X) | %s | %s |\n",
+					strings.Repeat(">", e.Depth), keyword, e.Tag.Group, e.Tag.Element, e.VR, typ)
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// yesNo renders a support flag in a conformance table
+func yesNo(ok bool) string {
+	if ok {
+		return "Yes"
+	}
+	return "No"
+}
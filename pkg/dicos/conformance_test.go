@@ -0,0 +1,70 @@
+package dicos
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConformanceStatement(t *testing.T) {
+	cs, err := NewConformanceStatement()
+	require.NoError(t, err)
+	require.Len(t, cs.IODs, len(SupportedIODs()))
+	assert.Equal(t, SupportedTransferSyntaxes(), cs.TransferSyntaxes)
+
+	modules := func(iod IODConformance) map[string]ModuleConformance {
+		out := map[string]ModuleConformance{}
+		for _, m := range iod.Modules {
+			out[m.Name] = m
+		}
+		return out
+	}
+
+	ct := modules(cs.IODs[0])
+	require.Equal(t, "CT", cs.IODs[0].Name)
+	assert.Equal(t, "File Meta Information", cs.IODs[0].Modules[0].Name)
+	for _, name := range []string{"Patient", "General Study", "Frame of Reference", "CT Image", "VOI LUT", "Image Pixel"} {
+		assert.Contains(t, ct, name)
+	}
+	assert.Contains(t, ct["CT Image"].Elements, ElementConformance{Tag: tag.RescaleSlope, Keyword: "RescaleSlope", VR: "DS", Type: Type1})
+	assert.Contains(t, ct["Patient"].Elements, ElementConformance{Tag: tag.PatientBirthDate, Keyword: "PatientBirthDate", VR: "DA"})
+	pixel := ct["Image Pixel"].Elements
+	assert.Equal(t, tag.PixelData, pixel[len(pixel)-1].Tag)
+
+	var tdr IODConformance
+	for _, iod := range cs.IODs {
+		if iod.Name == "TDR" {
+			tdr = iod
+		}
+	}
+	other := modules(tdr)["Additional Attributes"].Elements
+	assert.Contains(t, other, ElementConformance{Tag: tag.PTOSequence, Keyword: "PTOSequence", VR: "SQ"})
+	assert.Contains(t, other, ElementConformance{Tag: tag.ThreatCategoryDescription, Keyword: "ThreatCategoryDescription", VR: "UT", Depth: 1})
+
+	var buf bytes.Buffer
+	require.NoError(t, cs.WriteMarkdown(&buf))
+	out := buf.String()
+	assert.Contains(t, out, "| JPEG-LS Lossless | 1.2.840.10008.1.2.4.80 | Yes | Yes |")
+	assert.Contains(t, out, "| PDF | "+EncapsulatedPDFStorageUID+" | No |")
+	assert.Contains(t, out, "## CT IOD\n\n### File Meta Information")
+	assert.Contains(t, out, "| StudyInstanceUID | (0020,000D) | UI | 1 |")
+	assert.Contains(t, out, "| >PotentialThreatObjectID | (4010,1006) | LO |  |")
+}
+
+func TestModuleName(t *testing.T) {
+	for typ, want := range map[string]string{
+		"PatientModule":          "Patient",
+		"GeneralStudyModule":     "General Study",
+		"CTImageModule":          "CT Image",
+		"DXDetectorModule":       "DX Detector",
+		"FrameOfReferenceModule": "Frame of Reference",
+		"SOPCommonModule":        "SOP Common",
+		"OOIOwnerModule":         "OOI Owner",
+		"VOILUTModule":           "VOI LUT",
+	} {
+		assert.Equal(t, want, moduleName(typ))
+	}
+}
@@ -9,7 +9,7 @@ import (
 // QuickValidate performs basic structural validation of a DICOM dataset.
 //
 // This is a lightweight check for common issues, not a full DICOM compliance check.
-// For comprehensive validation, use ValidateCT(), ValidateDX(), or ValidateTDR().
+// For comprehensive validation, use Validate(), which picks the IOD rules.
 //
 // Checks:
 //   - SOP Class UID and SOP Instance UID present
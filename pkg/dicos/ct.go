@@ -5,6 +5,7 @@ import (
 	"io"
 	"log/slog"
 	"os"
+	"reflect"
 	"time"
 
 	"github.com/jpfielding/dicos.go/pkg/dicos/module"
@@ -58,6 +59,11 @@ type CTImage struct {
 	CTImageMod       *module.CTImageModule // Renamed to avoid conflict
 	VOILUT           *module.VOILUTModule  // Window/level presets
 
+	// Object of inspection: the bag, its owner and its routing; nil writes none
+	OOI       *module.OOIModule
+	OOIOwner  *module.OOIOwnerModule
+	Itinerary *module.ItineraryModule
+
 	ContentDate module.Date
 	ContentTime module.Time
 
@@ -94,6 +100,10 @@ type CTImage struct {
 	RescaleSlope     interface{} // float64 or string (DS)
 	RescaleType      string
 	Codec            Codec // nil = uncompressed
+
+	// DeriveBitsStored sets BitsStored and HighBit in GetDataset to the
+	// fewest bits that hold the native pixel data, see RequiredBitsStored
+	DeriveBitsStored bool
 }
 
 // CTImageModule is a legacy simple container for CT Image module attributes.
@@ -180,9 +190,8 @@ func NewCTImage() *CTImage {
 	now := time.Now()
 
 	// Generate UIDs
-	ct.Study.StudyInstanceUID = GenerateUID("1.2.826.0.1.3680043.8.498.")
-	ct.Series.SeriesInstanceUID = GenerateUID("1.2.826.0.1.3680043.8.498.")
-	ct.SOPCommon.SOPInstanceUID = GenerateUID("1.2.826.0.1.3680043.8.498.")
+	fillUIDs(&ct.Study.StudyInstanceUID, &ct.Series.SeriesInstanceUID, &ct.SOPCommon.SOPInstanceUID,
+		&ct.FrameOfReference.FrameOfReferenceUID)
 	ct.SOPCommon.SOPClassUID = "1.2.840.10008.5.1.4.1.1.2" // CT Image Storage
 
 	ct.Study.StudyDate = module.NewDate(now)
@@ -263,6 +272,7 @@ func (ct *CTImage) GetDataset() (*Dataset, error) {
 	if ct.VOILUT != nil {
 		opts = append(opts, WithModule(ct.VOILUT.ToTags()))
 	}
+	opts = append(opts, ooiOptions(ct.OOI, ct.OOIOwner, ct.Itinerary)...)
 
 	// 4. Content Date/Time
 	opts = append(opts,
@@ -271,6 +281,15 @@ func (ct *CTImage) GetDataset() (*Dataset, error) {
 	)
 
 	// 5. Image Attributes
+	if ct.DeriveBitsStored {
+		if n := requiredBitsStored(ct.PixelData, ct.PixelRepresent == 1); n > 0 {
+			ct.BitsStored, ct.HighBit = uint16(n), uint16(n-1)
+			if _, ok := ct.Image.KV[tag.BitsStored]; ok {
+				ct.Image.KV[tag.BitsStored] = ct.BitsStored
+				ct.Image.KV[tag.HighBit] = ct.HighBit
+			}
+		}
+	}
 	opts = append(opts,
 		WithElement(tag.SamplesPerPixel, ct.SamplesPerPixel),
 		WithElement(tag.PhotometricInterpretation, ct.PhotometricInterp),
@@ -285,18 +304,23 @@ func (ct *CTImage) GetDataset() (*Dataset, error) {
 		WithElement(tag.RescaleType, ct.RescaleType),
 	)
 
-	// 6. Legacy image KV pairs
+	// 6. Legacy image KV pairs; *Element values keep their VR (see ParseCT)
 	for t, v := range ct.Image.KV {
 		if t == tag.Rows || t == tag.Columns {
 			continue
 		}
+		if elem, ok := v.(*Element); ok {
+			opts = append(opts, withVR(t, elem.VR, elem.Value))
+			continue
+		}
 		opts = append(opts, WithElement(t, v))
 	}
 
-	// 7. Pixel Data
+	// 7. Pixel Data, once the attributes describing it are consistent
+	opts = append(opts, ValidateImagePixel)
 	if ct.Codec != nil && ct.PixelData != nil && !ct.PixelData.IsEncapsulated {
 		flatData := ct.PixelData.GetFlatData()
-		opts = append(opts, WithPixelData(ct.Rows, ct.Columns, int(ct.BitsAllocated), flatData, ct.Codec))
+		opts = append(opts, WithPixelData(ct.Rows, ct.Columns, int(ct.BitsAllocated), flatData, ct.Codec), WithFrameMeta(ct.PixelData.FrameMeta()...))
 	} else if ct.PixelData != nil {
 		opts = append(opts, WithRawPixelData(ct.PixelData))
 	}
@@ -324,6 +348,137 @@ func (ct *CTImage) Write(path string) (int64, error) {
 	return ct.WriteTo(f)
 }
 
+// ParseCT maps a CT dataset, e.g. one read with ReadFile, back to a CTImage so
+// it can be edited and rewritten with GetDataset. The Patient, Study, Series,
+// Equipment, SOPCommon and CTImageMod modules are always filled;
+// FrameOfReference, ImagePlane, VOILUT, OOI, OOIOwner and Itinerary are set
+// when their attributes are present and nil otherwise. Window presets are read into VOILUT only.
+//
+// Pixel data is kept as read: encapsulated frames stay compressed and Codec is
+// set to the codec of the transfer syntax, so they are written back without
+// recompression. Elements no module covers, such as private tags, are kept in
+// Image.KV with their original VR.
+//
+// Example:
+//
+//	ds, _ := dicos.ReadFile("scan.dcs")
+//	ct, err := dicos.ParseCT(ds)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	ct.Patient.PatientID = "BAG-0002"
+//	ct.Write("edited.dcs")
+func ParseCT(ds *Dataset) (*CTImage, error) {
+	if ds == nil {
+		return nil, fmt.Errorf("dicos: ParseCT: nil dataset")
+	}
+	if !IsCT(ds) {
+		return nil, fmt.Errorf("dicos: ParseCT: SOP class %q is not a CT image", attrString(ds, tag.SOPClassUID))
+	}
+
+	patient, study, series := readPatientModule(ds), readStudyModule(ds), readSeriesModule(ds)
+	equipment, sop := readEquipmentModule(ds), readSOPCommonModule(ds)
+	intercept, slope := GetRescaleExplicit(ds)
+	ct := &CTImage{
+		Patient:           &patient,
+		Study:             &study,
+		Series:            &series,
+		Equipment:         &equipment,
+		SOPCommon:         &sop,
+		CTImageMod:        readCTImageModule(ds),
+		Image:             &CTImageModule{KV: make(map[tag.Tag]interface{})},
+		SamplesPerPixel:   uint16(ds.SamplesPerPixel()),
+		PhotometricInterp: attrString(ds, tag.PhotometricInterpretation),
+		BitsAllocated:     uint16(ds.BitsAllocated()),
+		BitsStored:        uint16(ds.BitsStored()),
+		HighBit:           uint16(attrInt(ds, tag.HighBit)),
+		PixelRepresent:    uint16(ds.PixelRepresentation()),
+		Rows:              ds.Rows(),
+		Columns:           ds.Columns(),
+		RescaleIntercept:  intercept,
+		RescaleSlope:      slope,
+		RescaleType:       attrString(ds, tag.RescaleType),
+	}
+	ct.ContentDate, _ = module.ParseDate(attrString(ds, tag.ContentDate))
+	ct.ContentTime, _ = module.ParseTime(attrString(ds, tag.ContentTime))
+
+	if HasElement(ds, tag.FrameOfReferenceUID) {
+		ct.FrameOfReference = &module.FrameOfReferenceModule{}
+		ct.FrameOfReference.FromDataset(ds)
+	}
+	if HasElement(ds, tag.PixelSpacing) || HasElement(ds, tag.ImageOrientationPatient) || HasElement(ds, tag.ImagePositionPatient) {
+		ct.ImagePlane = readImagePlaneModule(ds)
+	}
+	if HasElement(ds, tag.WindowCenter) {
+		ct.VOILUT = readVOILUTModule(ds)
+	}
+	ct.OOI, ct.OOIOwner, ct.Itinerary = readOOIModule(ds), readOOIOwnerModule(ds), readItineraryModule(ds)
+
+	if HasElement(ds, tag.PixelData) {
+		pd, err := ds.GetPixelData()
+		if err != nil {
+			return nil, fmt.Errorf("dicos: ParseCT: %w", err)
+		}
+		if pd.IsEncapsulated {
+			ts := string(ds.TransferSyntax())
+			if ct.Codec = CodecByTransferSyntax(ts); ct.Codec == nil {
+				return nil, fmt.Errorf("dicos: ParseCT: no codec writes transfer syntax %s", ts)
+			}
+		}
+		ct.PixelData = pd
+	}
+
+	// Keep whatever the modules do not write
+	covered := map[Tag]bool{tag.PixelData: true}
+	for _, m := range []module.IODModule{ct.Patient, ct.Study, ct.Series, ct.Equipment, ct.SOPCommon, ct.CTImageMod} {
+		for _, el := range m.ToTags() {
+			covered[el.Tag] = true
+		}
+	}
+	for _, m := range []module.IODModule{ct.FrameOfReference, ct.ImagePlane, ct.VOILUT, ct.OOI, ct.OOIOwner, ct.Itinerary} {
+		if m != nil && !reflect.ValueOf(m).IsNil() {
+			for _, el := range m.ToTags() {
+				covered[el.Tag] = true
+			}
+		}
+	}
+	for _, t := range []Tag{
+		tag.ContentDate, tag.ContentTime, tag.SamplesPerPixel, tag.PhotometricInterpretation,
+		tag.BitsAllocated, tag.BitsStored, tag.HighBit, tag.PixelRepresentation,
+		tag.Rows, tag.Columns, tag.RescaleIntercept, tag.RescaleSlope, tag.RescaleType,
+	} {
+		covered[t] = true
+	}
+	for t, elem := range ds.Elements {
+		if t.Group != 0x0002 && !covered[t] {
+			ct.Image.KV[t] = elem
+		}
+	}
+	return ct, nil
+}
+
+// readCTImageModule fills the CT Image Module; the window is left to VOILUT
+func readCTImageModule(ds *Dataset) *module.CTImageModule {
+	m := &module.CTImageModule{}
+	m.FromDataset(ds)
+	m.WindowCenter, m.WindowWidth = 0, 0
+	return m
+}
+
+// readImagePlaneModule fills the Image Plane Module
+func readImagePlaneModule(ds *Dataset) *module.ImagePlaneModule {
+	m := &module.ImagePlaneModule{}
+	m.FromDataset(ds)
+	return m
+}
+
+// readVOILUTModule fills the VOI LUT Module window presets
+func readVOILUTModule(ds *Dataset) *module.VOILUTModule {
+	m := &module.VOILUTModule{}
+	m.FromDataset(ds)
+	return m
+}
+
 // SetPixelData sets native (uncompressed) pixel data for the CT image.
 //
 // Parameters:
@@ -337,8 +492,11 @@ func (ct *CTImage) Write(path string) (int64, error) {
 //
 // This method:
 //   - Updates ct.PixelData with uncompressed Frame structs
-//   - Sets image attributes (Rows, Columns, BitsAllocated, etc.) in legacy Image.KV
-//   - Configures 16-bit grayscale MONOCHROME2 format
+//   - Sets image attributes (Rows, Columns, BitsAllocated, etc.) on ct and in
+//     the legacy Image.KV
+//   - Configures 16-bit grayscale MONOCHROME2 format, taking BitsStored and
+//     PixelRepresentation from ct so set those first for signed data; see
+//     SetGray8PixelData and SetRGBPixelData for 8-bit and color data
 //
 // To compress the pixel data, set ct.Codec before calling GetDataset():
 //
@@ -348,23 +506,69 @@ func (ct *CTImage) Write(path string) (int64, error) {
 //
 // For already-compressed data, populate ct.PixelData directly with encapsulated frames.
 func (ct *CTImage) SetPixelData(rows, cols int, data []uint16) {
+	ct.setPixelData(rows, cols, 1, "MONOCHROME2", 16, ct.BitsStored, ct.PixelRepresent, data)
+}
+
+// SetSignedPixelData sets signed pixel data, such as Hounsfield units, and
+// marks it with PixelRepresentation 1 so every codec and reader keeps the
+// sign. Set ct.BitsStored first when fewer than 16 bits are significant.
+//
+//	ct.SetSignedPixelData(512, 512, hu)
+//	ct.Codec = dicos.CodecJPEGLS
+func (ct *CTImage) SetSignedPixelData(rows, cols int, data []int16) {
+	ct.PixelRepresent = 1
+	ct.SetPixelData(rows, cols, Uint16Samples(data))
+}
+
+// SetGray8PixelData sets 8-bit grayscale pixel data: one unsigned sample per
+// pixel, MONOCHROME2, 8 bits allocated and stored. Frames are split as by
+// SetPixelData.
+//
+//	ct.SetGray8PixelData(512, 512, pixels) // []uint8
+func (ct *CTImage) SetGray8PixelData(rows, cols int, data []uint8) {
+	ct.setPixelData(rows, cols, 1, "MONOCHROME2", 8, 8, 0, widen8(data))
+}
+
+// SetRGBPixelData sets 8-bit RGB pixel data, three interleaved samples per
+// pixel in R, G, B order, with PlanarConfiguration 0. To compress it, set
+// ct.Codec to a codec that supports color, such as CodecJPEG2000.
+//
+//	ct.SetRGBPixelData(512, 512, rgb) // len(rgb) == 512*512*3 per frame
+//	ct.Codec = dicos.CodecJPEG2000
+func (ct *CTImage) SetRGBPixelData(rows, cols int, data []uint8) {
+	ct.setPixelData(rows, cols, 3, "RGB", 8, 8, 0, widen8(data))
+}
+
+// setPixelData sets native frames of rows x cols pixels of samples each,
+// and the Image Pixel attributes describing them in both the convenience
+// fields and the legacy Image.KV
+func (ct *CTImage) setPixelData(rows, cols, samples int, photometric string, bitsAllocated, bitsStored, pixelRepresentation uint16, data []uint16) {
+	ct.Rows, ct.Columns = rows, cols
+	ct.SamplesPerPixel = uint16(samples)
+	ct.PhotometricInterp = photometric
+	ct.BitsAllocated = bitsAllocated
+	ct.BitsStored = bitsStored
+	ct.HighBit = bitsStored - 1
+	ct.PixelRepresent = pixelRepresentation
+
 	// Update image module tags
 	ct.Image.KV[tag.Rows] = uint16(rows)
 	ct.Image.KV[tag.Columns] = uint16(cols)
-	ct.Image.KV[tag.SamplesPerPixel] = uint16(1)
-	ct.Image.KV[tag.PhotometricInterpretation] = "MONOCHROME2"
-	ct.Image.KV[tag.BitsAllocated] = uint16(16)
-	ct.Image.KV[tag.BitsStored] = uint16(16)
-	ct.Image.KV[tag.HighBit] = uint16(15)
-	ct.Image.KV[tag.PixelRepresentation] = uint16(0)
-
-	// Create PixelData struct
-	// For native, we create one frame with all data?
-	// Or multiple frames if 3D?
-	// Assuming single frame or multi-frame flattened.
-	// If data length > rows*cols, it's multi-frame.
-	pixelsPerFrame := rows * cols
-	numFrames := len(data) / pixelsPerFrame
+	ct.Image.KV[tag.SamplesPerPixel] = uint16(samples)
+	ct.Image.KV[tag.PhotometricInterpretation] = photometric
+	ct.Image.KV[tag.BitsAllocated] = bitsAllocated
+	ct.Image.KV[tag.BitsStored] = bitsStored
+	ct.Image.KV[tag.HighBit] = bitsStored - 1
+	ct.Image.KV[tag.PixelRepresentation] = pixelRepresentation // 1 for signed HU values
+	if samples > 1 {
+		ct.Image.KV[tag.PlanarConfiguration] = uint16(0) // interleaved
+	} else {
+		delete(ct.Image.KV, tag.PlanarConfiguration)
+	}
+
+	// If data length > rows*cols*samples, it's multi-frame.
+	samplesPerFrame := rows * cols * samples
+	numFrames := len(data) / samplesPerFrame
 	ct.Image.KV[tag.NumberOfFrames] = fmt.Sprintf("%d", numFrames) // IS VR
 
 	pd := &PixelData{
@@ -373,8 +577,8 @@ func (ct *CTImage) SetPixelData(rows, cols int, data []uint16) {
 	}
 
 	for i := range numFrames {
-		start := i * pixelsPerFrame
-		end := start + pixelsPerFrame
+		start := i * samplesPerFrame
+		end := start + samplesPerFrame
 		if end > len(data) {
 			end = len(data)
 		}
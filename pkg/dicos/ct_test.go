@@ -7,6 +7,7 @@ import (
 
 	"github.com/jpfielding/dicos.go/pkg/dicos"
 	"github.com/jpfielding/dicos.go/pkg/dicos/module"
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -74,3 +75,109 @@ func TestCTImage_WriteCompressed(t *testing.T) {
 	syntax := dicos.GetTransferSyntax(ds)
 	assert.Equal(t, dicos.JPEGLSLossless, syntax, "Expected JPEG-LS Lossless transfer syntax")
 }
+
+func TestParseCT_RoundTrip(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		codec dicos.Codec
+	}{
+		{"native", nil},
+		{"jpeg-ls", dicos.CodecJPEGLS},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			when := time.Date(2024, 3, 5, 14, 7, 9, 250000000, time.UTC)
+			ct := dicos.NewCTImage()
+			ct.Codec = tt.codec
+			ct.Patient.PatientID = "BAG-0001"
+			ct.Patient.SetPatientName("Bag", "Checked", "", "", "")
+			ct.Study.StudyDate, ct.Study.StudyTime = module.NewDate(when), module.NewTime(when)
+			ct.Series.SeriesDate, ct.Series.SeriesTime = module.NewDate(when), module.NewTime(when)
+			ct.Series.SeriesDescription = "Parse"
+			ct.SOPCommon.InstanceCreationDate, ct.SOPCommon.InstanceCreationTime = module.NewDate(when), module.NewTime(when)
+			ct.ContentDate, ct.ContentTime = module.NewDate(when), module.NewTime(when)
+			ct.FrameOfReference.FrameOfReferenceUID = "1.2.3.4"
+			ct.ImagePlane.PixelSpacing = [2]float64{0.5, 0.75}
+			ct.ImagePlane.ImagePositionPatient = [3]float64{-10, 20, 30.5}
+			ct.ImagePlane.SliceThickness = 1.25
+			ct.CTImageMod.KVP = 140
+			ct.CTImageMod.ConvolutionKernel = "SOFT"
+			ct.RescaleIntercept = -1024.0
+			ct.OOI = &module.OOIModule{OOIID: "BAG-0001", OOIType: "BAG", OOISize: "CHECKED", OOILabel: "0016123456"}
+			ct.OOIOwner = &module.OOIOwnerModule{OwnerID: "P1234567", OwnerIDType: "PASSPORT", OwnerCategory: "PASSENGER"}
+			ct.Itinerary = &module.ItineraryModule{FlightNumber: "UA123", DepartureAirport: "IAD", ArrivalAirport: "SFO", CarrierCode: "UA"}
+			ct.Image.KV[tag.SeriesEnergy] = 2
+			ct.Image.KV[tag.Tag{Group: 0x0019, Element: 0x0010}] = "ACME"
+
+			rows, cols, frames := 8, 8, 3
+			data := make([]uint16, rows*cols*frames)
+			for i := range data {
+				data[i] = uint16(i * 7)
+			}
+			ct.Rows, ct.Columns = rows, cols
+			ct.SetPixelData(rows, cols, data)
+
+			var buf bytes.Buffer
+			_, err := ct.WriteTo(&buf)
+			require.NoError(t, err)
+			ds, err := dicos.ReadBuffer(buf.Bytes())
+			require.NoError(t, err)
+
+			got, err := dicos.ParseCT(ds)
+			require.NoError(t, err)
+			assert.Equal(t, ct.Patient, got.Patient)
+			assert.Equal(t, ct.Study, got.Study)
+			assert.Equal(t, ct.Series, got.Series)
+			assert.Equal(t, ct.Equipment, got.Equipment)
+			assert.Equal(t, ct.SOPCommon, got.SOPCommon)
+			assert.Equal(t, ct.FrameOfReference, got.FrameOfReference)
+			assert.Equal(t, ct.ImagePlane, got.ImagePlane)
+			assert.Equal(t, ct.VOILUT, got.VOILUT)
+			assert.Equal(t, ct.OOI, got.OOI)
+			assert.Equal(t, ct.OOIOwner, got.OOIOwner)
+			assert.Equal(t, ct.Itinerary, got.Itinerary)
+			assert.NotContains(t, got.Image.KV, tag.FlightNumber, "covered by Itinerary")
+			assert.Equal(t, ct.CTImageMod.KVP, got.CTImageMod.KVP)
+			assert.Equal(t, ct.CTImageMod.ConvolutionKernel, got.CTImageMod.ConvolutionKernel)
+			assert.Equal(t, ct.ContentDate, got.ContentDate)
+			assert.Equal(t, ct.ContentTime, got.ContentTime)
+			assert.Equal(t, rows, got.Rows)
+			assert.Equal(t, cols, got.Columns)
+			assert.Equal(t, -1024.0, got.RescaleIntercept)
+			assert.Equal(t, "HU", got.RescaleType)
+			assert.Equal(t, tt.codec, got.Codec)
+			assert.Contains(t, got.Image.KV, tag.SeriesEnergy)
+			assert.Contains(t, got.Image.KV, tag.Tag{Group: 0x0019, Element: 0x0010})
+
+			// Rewriting keeps the elements and the pixels
+			var again bytes.Buffer
+			_, err = got.WriteTo(&again)
+			require.NoError(t, err)
+			ds2, err := dicos.ReadBuffer(again.Bytes())
+			require.NoError(t, err)
+			for tg, elem := range ds.Elements {
+				if tg.Group == 0x0002 {
+					continue
+				}
+				require.Contains(t, ds2.Elements, tg, "%v", tg)
+				assert.Equal(t, elem.VR, ds2.Elements[tg].VR, "%v", tg)
+			}
+			vol, err := dicos.DecodeVolume(ds2)
+			require.NoError(t, err)
+			require.Equal(t, frames, vol.Depth)
+			for i, v := range data {
+				require.Equal(t, v, vol.Data[i], "voxel %d", i)
+			}
+		})
+	}
+}
+
+func TestParseCT_NotACT(t *testing.T) {
+	_, err := dicos.ParseCT(nil)
+	assert.Error(t, err)
+
+	tdr := dicos.NewThreatDetectionReport()
+	ds, err := tdr.GetDataset()
+	require.NoError(t, err)
+	_, err = dicos.ParseCT(ds)
+	assert.ErrorContains(t, err, "is not a CT image")
+}
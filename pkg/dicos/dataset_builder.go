@@ -6,9 +6,12 @@ import (
 	"image"
 	"image/color"
 	"log/slog"
+	"strconv"
 
+	"github.com/jpfielding/dicos.go/pkg/dicos/dict"
 	"github.com/jpfielding/dicos.go/pkg/dicos/module"
 	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/jpfielding/dicos.go/pkg/dicos/transfer"
 )
 
 // Option configures a Dataset during construction using the functional options pattern.
@@ -198,6 +201,21 @@ func WithModule(tags []module.IODElement) Option {
 	}
 }
 
+// ooiOptions writes the object of inspection modules an IOD has set
+func ooiOptions(ooi *module.OOIModule, owner *module.OOIOwnerModule, itinerary *module.ItineraryModule) []Option {
+	var opts []Option
+	if ooi != nil {
+		opts = append(opts, WithModule(ooi.ToTags()))
+	}
+	if owner != nil {
+		opts = append(opts, WithModule(owner.ToTags()))
+	}
+	if itinerary != nil {
+		opts = append(opts, WithModule(itinerary.ToTags()))
+	}
+	return opts
+}
+
 // WithPixelData adds pixel data to the dataset, either uncompressed (native) or compressed (encapsulated).
 //
 // Parameters:
@@ -205,7 +223,7 @@ func WithModule(tags []module.IODElement) Option {
 //   - cols: Image width in pixels
 //   - bitsAllocated: Bits per pixel (8 or 16)
 //   - data: Pixel values in row-major order (left-to-right, top-to-bottom)
-//   - codec: Compression codec (nil for uncompressed, or CodecJPEGLS, CodecJPEG2K, etc.)
+//   - codec: Compression codec (nil for uncompressed, or CodecJPEGLS, CodecJPEG2000, etc.)
 //
 // Native (Uncompressed) Format - codec=nil:
 //   - Data stored directly as OW (Other Word) or OB (Other Byte)
@@ -216,16 +234,23 @@ func WithModule(tags []module.IODElement) Option {
 // Encapsulated (Compressed) Format - codec != nil:
 //   - Each frame compressed independently using specified codec
 //   - Stored as OB with Basic Offset Table
-//   - Compressed data padded to even length per DICOM spec
+//   - Compressed data padded to even length per DICOM spec, unless the
+//     codec asks otherwise (see FragmentPadding)
 //   - Smaller file size, recommended for DICOS (use JPEG-LS per NEMA)
+//   - Codecs implementing SampleEncoder (JPEG 2000) take BitsStored and
+//     PixelRepresentation from options applied before this one, so signed
+//     data is described correctly; unsupported formats fail with
+//     ErrUnsupportedPixelFormat
 //
 // Multi-Frame Handling:
 //
 // The length of the data slice determines the number of frames:
 //
-//	numFrames = len(data) / (rows * cols)
+//	numFrames = len(data) / (rows * cols * samplesPerPixel)
 //
 // For a 512x512 image with 3 frames, data should contain 512*512*3 = 786,432 pixels.
+// SamplesPerPixel is taken from options applied before this one; for RGB
+// each pixel is three interleaved samples, see WithRGBPixelData.
 //
 // Pixel Ordering:
 //
@@ -257,18 +282,21 @@ func WithPixelData(rows, cols, bitsAllocated int, data []uint16, codec Codec) Op
 			return nil
 		}
 
-		pixelsPerFrame := rows * cols
+		samples := ds.SamplesPerPixel()
+		pixelsPerFrame := rows * cols * samples
 		numFrames := len(data) / pixelsPerFrame
 		compress := codec != nil
 
 		pd := &PixelData{
 			IsEncapsulated: compress,
 			Frames:         make([]Frame, numFrames),
+			Padding:        paddingOf(codec),
 		}
 
 		if compress {
 			offsets := make([]uint32, numFrames)
 			currentOffset := uint32(0)
+			compressedSize := 0
 
 			for i := 0; i < numFrames; i++ {
 				offsets[i] = currentOffset
@@ -276,49 +304,32 @@ func WithPixelData(rows, cols, bitsAllocated int, data []uint16, codec Codec) Op
 				end := start + pixelsPerFrame
 				sliceData := data[start:end]
 
-				var buf bytes.Buffer
-				var img image.Image
-
-				if bitsAllocated > 8 {
-					gray16 := image.NewGray16(image.Rect(0, 0, cols, rows))
-
-					if i == 0 && len(sliceData) > 10 {
-						slog.Debug("ENCODE Frame 0", "first_pixels_subset", sliceData[:10])
-					}
-
-					for j, val := range sliceData {
-						x := j % cols
-						y := j / cols
-						gray16.SetGray16(x, y, color.Gray16{Y: val})
-					}
-					img = gray16
-				} else {
-					gray8 := image.NewGray(image.Rect(0, 0, cols, rows))
-					for j, val := range sliceData {
-						x := j % cols
-						y := j / cols
-						gray8.SetGray(x, y, color.Gray{Y: uint8(val)})
-					}
-					img = gray8
+				if i == 0 && len(sliceData) > 10 {
+					slog.Debug("ENCODE Frame 0", "first_pixels_subset", sliceData[:10])
 				}
-
-				if err := codec.Encode(&buf, img); err != nil {
-					return fmt.Errorf("%s encode error: %w", codec.Name(), err)
+				var compressedData []byte
+				var err error
+				if samples == 3 {
+					compressedData, err = encodeRGBFrame(codec, sliceData, rows, cols, bitsAllocated)
+				} else {
+					compressedData, err = encodeGrayFrame(codec, sliceData, rows, cols, sampleFormat(ds, bitsAllocated))
 				}
-
-				compressedData := buf.Bytes()
-				if len(compressedData)%2 != 0 {
-					compressedData = append(compressedData, 0x00)
+				if err != nil {
+					return err
 				}
 
 				pd.Frames[i] = Frame{
 					CompressedData: compressedData,
 				}
 
+				compressedSize += len(compressedData)
 				frameSize := uint32(len(compressedData)) + 8
 				currentOffset += frameSize
 			}
 			pd.Offsets = offsets
+			if err := withLossyCompression(ds, codec, len(data)*((bitsAllocated+7)/8), compressedSize); err != nil {
+				return err
+			}
 
 			t := Tag{Group: 0x7FE0, Element: 0x0010}
 			ds.Elements[t] = &Element{
@@ -355,14 +366,106 @@ func WithPixelData(rows, cols, bitsAllocated int, data []uint16, codec Codec) Op
 	}
 }
 
-// WithRawPixelData adds pre-constructed PixelData to the dataset
+// lossyMethods are the LossyImageCompressionMethod values of the lossy
+// transfer syntaxes (PS3.3 C.7.6.1.1.5.1)
+var lossyMethods = map[transfer.Syntax]string{
+	transfer.JPEGBaseline:       "ISO_10918_1",
+	transfer.JPEGExtended:       "ISO_10918_1",
+	transfer.JPEGLSNearLossless: "ISO_14495_1",
+	transfer.JPEG2000:           "ISO_15444_1",
+}
+
+// withLossyCompression records in ds that codec compressed raw bytes of
+// pixel data to compressed bytes with loss. A ratio and method already
+// recorded by an earlier lossy compression are kept, and the new ones
+// appended, so the history of the pixels stays visible.
+func withLossyCompression(ds *Dataset, codec Codec, raw, compressed int) error {
+	ts := transfer.Syntax(codec.TransferSyntaxUID())
+	if !ts.IsLossy() || compressed <= 0 {
+		return nil
+	}
+	ratio := strconv.FormatFloat(float64(raw)/float64(compressed), 'f', 2, 64)
+	method := lossyMethods[ts]
+	if attrString(ds, tag.LossyImageCompression) == "01" {
+		if prev := attrString(ds, tag.LossyImageCompressionRatio); prev != "" {
+			ratio = prev + `\` + ratio
+		}
+		if prev := attrString(ds, tag.LossyImageCompressionMethod); prev != "" {
+			method = prev + `\` + method
+		}
+	}
+	for _, opt := range []Option{
+		WithElement(tag.LossyImageCompression, "01"),
+		WithElement(tag.LossyImageCompressionRatio, ratio),
+		WithElement(tag.LossyImageCompressionMethod, method),
+	} {
+		if err := opt(ds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sampleFormat describes the samples of ds, whose BitsStored and
+// PixelRepresentation may not be set yet
+func sampleFormat(ds *Dataset, bitsAllocated int) SampleFormat {
+	f := SampleFormat{
+		BitsAllocated: bitsAllocated,
+		BitsStored:    bitsAllocated,
+		Signed:        ds.PixelRepresentation() == 1,
+	}
+	if _, ok := ds.FindElement(0x0028, 0x0101); ok {
+		f.BitsStored = ds.BitsStored()
+	}
+	return f
+}
+
+// encodeGrayFrame compresses one grayscale frame with codec, padded by the
+// codec's FragmentPadding policy. SampleEncoder codecs get the full sample format; others get a Gray
+// or Gray16 image.
+func encodeGrayFrame(codec Codec, data []uint16, rows, cols int, f SampleFormat) ([]byte, error) {
+	var buf bytes.Buffer
+	if se, ok := codec.(SampleEncoder); ok {
+		if err := se.EncodeSamples(&buf, data, cols, rows, f); err != nil {
+			return nil, fmt.Errorf("%s encode error: %w", codec.Name(), err)
+		}
+	} else {
+		var img image.Image
+		if f.BitsAllocated > 8 {
+			gray16 := image.NewGray16(image.Rect(0, 0, cols, rows))
+			for j, val := range data {
+				x := j % cols
+				y := j / cols
+				gray16.SetGray16(x, y, color.Gray16{Y: val})
+			}
+			img = gray16
+		} else {
+			gray8 := image.NewGray(image.Rect(0, 0, cols, rows))
+			for j, val := range data {
+				x := j % cols
+				y := j / cols
+				gray8.SetGray(x, y, color.Gray{Y: uint8(val)})
+			}
+			img = gray8
+		}
+		if err := codec.Encode(&buf, img); err != nil {
+			return nil, fmt.Errorf("%s encode error: %w", codec.Name(), err)
+		}
+	}
+
+	return padFragment(buf.Bytes(), paddingOf(codec)), nil
+}
+
+// WithRawPixelData adds pre-constructed PixelData to the dataset. Native
+// frames are written as bytes (OB) when BitsAllocated, set by an earlier
+// option, is 8 or less.
 func WithRawPixelData(pd *PixelData) Option {
 	return func(ds *Dataset) error {
 		if pd == nil {
 			return nil
 		}
 		vr := "OB"
-		if !pd.IsEncapsulated && len(pd.Frames) > 0 && len(pd.Frames[0].Data) > 0 {
+		if !pd.IsEncapsulated && len(pd.Frames) > 0 && len(pd.Frames[0].Data) > 0 && ds.BitsAllocated() > 8 {
 			vr = "OW"
 		}
 		t := Tag{Group: 0x7FE0, Element: 0x0010}
@@ -375,113 +478,22 @@ func WithRawPixelData(pd *PixelData) Option {
 	}
 }
 
-// GetVR returns the Value Representation (VR) for a standard tag
+// GetVR returns the Value Representation (VR) for a standard tag from the
+// data dictionary, or UN for a tag it does not know
 func GetVR(t tag.Tag) string {
-	if t.Group == 0x0002 {
-		if t.Element == 0x0000 {
-			return "UL"
-		}
-		if t.Element == 0x0001 {
-			return "OB"
-		}
-		if t == tag.TransferSyntaxUID {
-			return "UI"
-		}
-		return "UI"
-	}
-
 	switch t {
-	case tag.PatientName:
-		return "PN"
-	case tag.PatientID:
-		return "LO"
-	case tag.PatientBirthDate:
-		return "DA"
-	case tag.PatientSex:
-		return "CS"
-
-	case tag.StudyDate:
-		return "DA"
-	case tag.StudyTime:
-		return "TM"
-	case tag.AccessionNumber:
-		return "SH"
-	case tag.StudyDescription:
-		return "LO"
-	case tag.StudyInstanceUID:
-		return "UI"
-	case tag.StudyID:
-		return "SH"
-
-	case tag.Modality:
+	// Integrity Attestation Private Tags (Group 0011), reserved by IntegrityCreator
+	case tag.IntegrityAlgorithm:
 		return "CS"
-	case tag.SeriesInstanceUID:
-		return "UI"
-	case tag.SeriesNumber:
-		return "IS"
-	case tag.SeriesDescription:
+	case tag.IntegrityTags:
+		return "AT"
+	case tag.IntegrityDigest:
+		return "OB"
+	// Codec Decision Private Tags (Group 0011), reserved by CodecDecisionCreator
+	case tag.CodecDecisionName:
 		return "LO"
-
-	case tag.SamplesPerPixel:
-		return "US"
-	case tag.PhotometricInterpretation:
-		return "CS"
-	case tag.Rows:
-		return "US"
-	case tag.Columns:
-		return "US"
-	case tag.BitsAllocated:
-		return "US"
-	case tag.BitsStored:
-		return "US"
-	case tag.HighBit:
-		return "US"
-	case tag.PixelRepresentation:
-		return "US"
-	case tag.NumberOfFrames:
-		return "IS"
-
-	case tag.RescaleIntercept:
-		return "DS"
-	case tag.RescaleSlope:
-		return "DS"
-	case tag.RescaleType:
-		return "LO"
-	case tag.WindowCenter:
-		return "DS"
-	case tag.WindowWidth:
-		return "DS"
-
-	case tag.PixelSpacing:
-		return "DS"
-	case tag.SliceThickness:
-		return "DS"
-	case tag.SpacingBetweenSlices:
-		return "DS"
-	case tag.ImagePositionPatient:
-		return "DS"
-	case tag.ImageOrientationPatient:
-		return "DS"
-	case tag.SliceLocation:
-		return "DS"
-
-	case tag.ContentDate:
-		return "DA"
-	case tag.ContentTime:
-		return "TM"
-	case tag.InstanceNumber:
-		return "IS"
-	case tag.ImageType:
-		return "CS"
-
-	case tag.SOPClassUID:
-		return "UI"
-	case tag.SOPInstanceUID:
-		return "UI"
-
-	case tag.PixelData:
-		return "OW"
+	case tag.CodecDecisionRecord:
+		return "UT"
 	}
-
-	return "UN"
+	return dict.VR(t)
 }
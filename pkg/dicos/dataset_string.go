@@ -15,27 +15,28 @@ func (e *Element) String() string {
 		tagName = " " + tagName
 	}
 
-	valStr := ""
-	switch v := e.Value.(type) {
+	valStr := formatValue(e.Value)
+
+	return fmt.Sprintf("[%s] %s%s: %s", e.Tag, e.VR, tagName, valStr)
+}
+
+// formatValue renders an element value, summarizing pixel and binary data
+func formatValue(value any) string {
+	switch v := value.(type) {
 	case *PixelData:
-		valStr = fmt.Sprintf("Pixel Data (%d frames)", len(v.Frames))
+		return fmt.Sprintf("Pixel Data (%d frames)", len(v.Frames))
+	case *BulkData:
+		return v.String()
 	case []uint16:
 		if len(v) > 10 {
-			valStr = fmt.Sprintf("Array of %d params", len(v))
-		} else {
-			valStr = fmt.Sprintf("%v", v)
+			return fmt.Sprintf("Array of %d params", len(v))
 		}
 	case []byte:
 		if len(v) > 20 {
-			valStr = fmt.Sprintf("Binary Data (%d bytes)", len(v))
-		} else {
-			valStr = fmt.Sprintf("%v", v)
+			return fmt.Sprintf("Binary Data (%d bytes)", len(v))
 		}
-	default:
-		valStr = fmt.Sprintf("%v", v)
 	}
-
-	return fmt.Sprintf("[%s] %s%s: %s", e.Tag, e.VR, tagName, valStr)
+	return fmt.Sprintf("%v", value)
 }
 
 // MarshalJSON returns a JSON representation of the Element
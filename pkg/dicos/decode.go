@@ -1,14 +1,32 @@
 package dicos
 
 import (
+	"context"
 	"fmt"
 	"image"
+	"image/color"
 	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
 )
 
 // DecodeVolume decodes all frames from a Dataset into a Volume
 // Handles both native (uncompressed) and encapsulated (JPEG-LS, JPEG Lossless) pixel data
+// Voxels hold the stored sample values: compressed 8-bit frames are not
+// rescaled to 16 bits, so they match native 8-bit pixel data. Signed samples
+// (PixelRepresentation 1) are sign extended from BitsStored and the volume is
+// marked Signed, so int16 voxels hold the stored values, e.g. Hounsfield units
+// before any rescale.
 func DecodeVolume(ds *Dataset) (*Volume, error) {
+	return DecodeVolumeContext(context.Background(), ds)
+}
+
+// DecodeVolumeContext is DecodeVolume with a context that is checked between
+// frames and carried into log records emitted while decoding. Compressed
+// frames are decoded in parallel, see DecodeOptions.Workers.
+func DecodeVolumeContext(ctx context.Context, ds *Dataset) (*Volume, error) {
 	rows := GetRows(ds)
 	cols := GetColumns(ds)
 
@@ -16,7 +34,7 @@ func DecodeVolume(ds *Dataset) (*Volume, error) {
 		return nil, fmt.Errorf("invalid dimensions: %dx%d", cols, rows)
 	}
 
-	pd, err := ds.GetPixelData()
+	pd, err := ds.GetPixelDataContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -30,166 +48,202 @@ func DecodeVolume(ds *Dataset) (*Volume, error) {
 		numFrames = 1
 	}
 
-	vol := NewVolume(cols, rows, numFrames)
+	samples := max(ds.SamplesPerPixel(), 1)
+	if err := reserveMemory(ctx, ResourceVolume, int64(cols)*int64(rows)*int64(numFrames)*int64(samples)*2); err != nil {
+		return nil, err
+	}
+	vol := NewVolumeSamples(cols, rows, numFrames, samples)
+	vol.setGeometry(ds)
 
 	if pd.IsEncapsulated {
-		// Determine Transfer Syntax
-		ts := GetTransferSyntax(ds)
-
-		// Decode each compressed frame
-		for z, frame := range pd.Frames {
-			var img image.Image
-			// This nested check is redundant but kept as per instruction
-			if pd.IsEncapsulated {
-				decoded, err := decodeCompressedFrame(frame.CompressedData, rows, cols, ts)
-				if err != nil {
-					return nil, fmt.Errorf("decoding frame %d: %w", z, err)
-				}
-				img = decoded
-			} else {
-				// Should not happen
-				return nil, fmt.Errorf("unexpected non-encapsulated data in encapsulated block for frame %d", z)
-			}
-
-			bounds := img.Bounds()
-			imgWidth := bounds.Dx()
-			imgHeight := bounds.Dy()
-			sliceOffset := z * vol.Width * vol.Height
-
-			// Log dimension mismatch if any (first frame only)
-			if z == 0 && (imgWidth != vol.Width || imgHeight != vol.Height) {
-				slog.Warn("Decoded image mismatch",
-					"width", imgWidth, "height", imgHeight,
-					"expected_width", vol.Width, "expected_height", vol.Height)
-			}
-
-			// Extract pixel values using RGBA (proven to work correctly)
-			// Use the actual image dimensions for iteration
-			for y := 0; y < imgHeight && y < vol.Height; y++ {
-				for x := 0; x < imgWidth && x < vol.Width; x++ {
-					r, _, _, _ := img.At(x, y).RGBA() // Use 'img' here
-					// RGBA() returns 0-65535, store as uint16
-					// Use vol.Width for stride (not imgWidth) to match Volume layout
-					if idx := sliceOffset + y*vol.Width + x; idx < len(vol.Data) {
-						vol.Data[idx] = uint16(r)
-					}
-				}
-			}
+		// Frames decode in parallel, each straight into its slice of the volume
+		frameSize := vol.Width * vol.Height * samples
+		err := decodeFrames(ctx, pd, GetTransferSyntax(ds), rows, cols, samples, decodeWorkers(ctx),
+			func(z int) []uint16 { return vol.Data[z*frameSize : (z+1)*frameSize] }, nil)
+		if err != nil {
+			return nil, err
 		}
 	} else {
 		// Native pixel data - copy directly
-		idx := 0
-		for _, frame := range pd.Frames {
-			for _, val := range frame.Data {
-				if idx < len(vol.Data) {
-					vol.Data[idx] = val
-					idx++
-				}
-			}
-		}
+		vol.copyNative(ds, pd)
 	}
 
+	vol.applyPixelRepresentation(ds)
+
 	return vol, nil
 }
 
+// DecodeOptions overrides how compressed frames pick their codec, for files
+// whose TransferSyntaxUID does not match the actual encoding
+type DecodeOptions struct {
+	// ForceCodec decodes every compressed frame with the named codec (see
+	// CodecByName), ignoring the transfer syntax and the frame contents
+	ForceCodec string
+	// Override, if set, chooses the codec for each frame from the declared
+	// transfer syntax and the codec sniffed from the frame (nil when not
+	// recognized). Returning nil keeps the normal selection.
+	Override func(ts TransferSyntax, sniffed Codec) Codec
+	// Workers is the number of frames decoded in parallel by DecodeVolume and
+	// DecodeSlices. Zero uses GOMAXPROCS.
+	Workers int
+	// Logger receives the decoder's diagnostics instead of the logger of the
+	// context (see WithLogger)
+	Logger *slog.Logger
+}
+
+type decodeOptionsKey struct{}
+
+// WithDecodeOptions returns a context whose frame decodes follow opts
+func WithDecodeOptions(ctx context.Context, opts DecodeOptions) context.Context {
+	if opts.Logger != nil {
+		ctx = WithLogger(ctx, opts.Logger)
+	}
+	return context.WithValue(ctx, decodeOptionsKey{}, opts)
+}
+
+// DecodeVolumeWithOptions is DecodeVolumeContext with codec selection
+// overridden by opts.
+//
+// Example:
+//
+//	// The vendor labels JPEG-LS frames as JPEG Lossless
+//	vol, err := dicos.DecodeVolumeWithOptions(ctx, ds, dicos.DecodeOptions{ForceCodec: "jpegls"})
+func DecodeVolumeWithOptions(ctx context.Context, ds *Dataset, opts DecodeOptions) (*Volume, error) {
+	if opts.ForceCodec != "" && CodecByName(opts.ForceCodec) == nil {
+		return nil, fmt.Errorf("unknown codec %q", opts.ForceCodec)
+	}
+	return DecodeVolumeContext(WithDecodeOptions(ctx, opts), ds)
+}
+
 // decodeCompressedFrame detects compression type and decodes
-func decodeCompressedFrame(data []byte, rows, cols int, ts TransferSyntax) (image.Image, error) {
+func decodeCompressedFrame(ctx context.Context, data []byte, rows, cols int, ts TransferSyntax) (image.Image, error) {
 	if len(data) < 2 {
 		return nil, fmt.Errorf("compressed data too short: %d bytes", len(data))
 	}
+	opts, _ := ctx.Value(decodeOptionsKey{}).(DecodeOptions)
+	if opts.ForceCodec != "" {
+		codec := CodecByName(opts.ForceCodec)
+		if codec == nil {
+			return nil, fmt.Errorf("unknown codec %q", opts.ForceCodec)
+		}
+		return decodeWithCodec(ctx, codec, data, cols, rows)
+	}
+
+	sniffedCodec := sniffCodec(data)
+	if opts.Override != nil {
+		if codec := opts.Override(ts, sniffedCodec); codec != nil {
+			return decodeWithCodec(ctx, codec, data, cols, rows)
+		}
+	}
 
 	// 1. Use Transfer Syntax if available via codec registry
 	tsUID := string(ts)
 	if codec := CodecByTransferSyntax(tsUID); codec != nil {
-		return codec.Decode(data, cols, rows)
-	}
-
-	// 2. Fallback to sniffing if TS is unknown or generic
-	var sniffedCodec Codec
-
-	// Strict check for JPEG SOI (FF D8) or J2K SOC (FF 4F) at start
-	if len(data) > 2 {
-		// Scan only if starts with FF D8 (JPEG)
-		if data[0] == 0xFF && data[1] == 0xD8 {
-			// Scan for SOF marker to distinguish JPEG-LS vs JPEG Lossless
-			for i := 0; i < len(data)-1; i++ {
-				if data[i] == 0xFF {
-					switch data[i+1] {
-					case 0xF7: // SOF55 - JPEG-LS
-						sniffedCodec = CodecJPEGLS
-					case 0xC3: // SOF3 - JPEG Lossless
-						sniffedCodec = CodecJPEGLi
-					}
-					if sniffedCodec != nil {
-						break
-					}
-				}
-			}
-		} else if data[0] == 0xFF && data[1] == 0x4F {
-			// J2K SOC marker
-			sniffedCodec = CodecJPEG2000
+		if sniffedCodec != nil && sniffedCodec.Name() != codec.Name() {
+			logger(ctx).WarnContext(ctx, "Frame encoding disagrees with transfer syntax",
+				slog.String("ts", tsUID),
+				slog.String("declared", codec.Name()),
+				slog.String("sniffed", sniffedCodec.Name()))
 		}
+		return decodeWithCodec(ctx, codec, data, cols, rows)
 	}
 
+	// 2. Fallback to sniffing if TS is unknown or generic
+	logger(ctx).DebugContext(ctx, "No codec for transfer syntax, sniffing frame",
+		slog.String("ts", tsUID),
+		slog.Int("dataLen", len(data)))
 	if sniffedCodec != nil {
-		return sniffedCodec.Decode(data, cols, rows)
+		return decodeWithCodec(ctx, sniffedCodec, data, cols, rows)
 	}
 
 	// Check for RLE (header is 64 bytes)
 	if len(data) >= 64 {
-		img, err := CodecRLE.Decode(data, cols, rows)
+		img, err := decodeWithCodec(ctx, CodecRLE, data, cols, rows)
 		if err == nil {
 			return img, nil
 		}
-
-		slog.Debug("RLE decode attempt failed",
-			slog.Any("error", err),
-			slog.Int("dataLen", len(data)),
-			slog.Int("cols", cols),
-			slog.Int("rows", rows))
 	}
 
 	// Fallback: Try JPEG Lossless first (more common in DICOM), then JPEG-LS
-	img, err := CodecJPEGLi.Decode(data, cols, rows)
+	img, err := decodeWithCodec(ctx, CodecJPEGLi, data, cols, rows)
 	if err == nil {
 		return img, nil
 	}
 
-	return CodecJPEGLS.Decode(data, cols, rows)
+	return decodeWithCodec(ctx, CodecJPEGLS, data, cols, rows)
+}
+
+// sniffCodec identifies the codec of a frame from its leading markers, or
+// returns nil. RLE has no signature and is never sniffed. A codec registered
+// for the transfer syntax of the sniffed one is returned in its place.
+func sniffCodec(data []byte) Codec {
+	if IsJP2(data) {
+		return registered(CodecJPEG2000)
+	}
+	if len(data) <= 2 || data[0] != 0xFF {
+		return nil
+	}
+	switch data[1] {
+	case 0xD8: // JPEG SOI: scan for the SOF marker to tell the JPEG processes apart
+		for i := 0; i < len(data)-1; i++ {
+			if data[i] == 0xFF {
+				switch data[i+1] {
+				case 0xF7: // SOF55 - JPEG-LS
+					return registered(CodecJPEGLS)
+				case 0xC3: // SOF3 - JPEG Lossless
+					return registered(CodecJPEGLi)
+				case 0xC0: // SOF0 - JPEG Baseline
+					return registered(CodecJPEGBaseline)
+				case 0xC1: // SOF1 - JPEG Extended
+					return registered(CodecJPEGExtended)
+				}
+			}
+		}
+	case 0x4F: // J2K SOC marker
+		return registered(CodecJPEG2000)
+	}
+	return nil
+}
+
+// frameSample returns the stored sample at x, y of a decoded frame, in the
+// same domain as native pixel data: 8-bit gray frames keep their 0-255 values
+// and 16-bit gray frames their 0-65535 values, without the rescaling of
+// color.Color.RGBA. Other color models fall back to the red channel of RGBA.
+func frameSample(img image.Image, x, y int) uint16 {
+	switch c := img.At(x, y).(type) {
+	case color.Gray:
+		return uint16(c.Y)
+	case color.Gray16:
+		return c.Y
+	}
+	r, _, _, _ := img.At(x, y).RGBA()
+	return uint16(r)
 }
 
 // DecodeFrameData decodes a single frame from pixel data
 // Returns raw uint16 pixel values
 func DecodeFrameData(pd *PixelData, frameIndex int, rows, cols int, ts TransferSyntax) ([]uint16, error) {
+	return DecodeFrameDataContext(context.Background(), pd, frameIndex, rows, cols, ts)
+}
+
+// DecodeFrameDataContext is DecodeFrameData with a context that is checked
+// before decoding and carried into log records emitted by the codec path.
+func DecodeFrameDataContext(ctx context.Context, pd *PixelData, frameIndex int, rows, cols int, ts TransferSyntax) ([]uint16, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	if frameIndex < 0 || frameIndex >= len(pd.Frames) {
 		return nil, fmt.Errorf("frame index %d out of range (0-%d)", frameIndex, len(pd.Frames)-1)
 	}
 
-	frame := pd.Frames[frameIndex]
 	pixelCount := rows * cols
+	if err := reserveMemory(ctx, ResourceFrame, int64(pixelCount)*2); err != nil {
+		return nil, err
+	}
 	data := make([]uint16, pixelCount)
-
-	if pd.IsEncapsulated {
-		decoded, err := decodeCompressedFrame(frame.CompressedData, rows, cols, ts)
-		if err != nil {
-			return nil, fmt.Errorf("decode failed: %w", err)
-		}
-
-		bounds := decoded.Bounds()
-		for y := 0; y < bounds.Dy() && y < rows; y++ {
-			for x := 0; x < bounds.Dx() && x < cols; x++ {
-				r, _, _, _ := decoded.At(x, y).RGBA()
-				idx := y*cols + x
-				if idx < len(data) {
-					data[idx] = uint16(r)
-				}
-			}
-		}
-	} else {
-		// Native - copy directly
-		copy(data, frame.Data)
+	if err := decodeFrameInto(ctx, pd, frameIndex, rows, cols, 1, ts, data); err != nil {
+		return nil, err
 	}
-
 	return data, nil
 }
 
@@ -198,58 +252,40 @@ func GetWindowLevel(ds *Dataset) (center, width int) {
 	center, width = 40, 400 // CT soft tissue defaults
 
 	if elem, ok := ds.FindElement(0x0028, 0x1050); ok { // Window Center
-		if s, ok := elem.GetString(); ok {
-			fmt.Sscanf(s, "%d", &center)
-		} else if v, ok := elem.GetInt(); ok {
-			center = v
+		if v, ok := elem.GetFloats(); ok {
+			center = int(v[0])
 		}
 	}
 
 	if elem, ok := ds.FindElement(0x0028, 0x1051); ok { // Window Width
-		if s, ok := elem.GetString(); ok {
-			fmt.Sscanf(s, "%d", &width)
-		} else if v, ok := elem.GetInt(); ok {
-			width = v
+		if v, ok := elem.GetFloats(); ok {
+			width = int(v[0])
 		}
 	}
 
 	return
 }
 
-// GetPixelSpacing returns the pixel spacing in mm
+// GetPixelSpacing returns the in-plane pixel spacing in mm.
+// Projection images without Pixel Spacing are corrected for magnification;
+// see GetCalibratedSpacing for the precedence and provenance.
 func GetPixelSpacing(ds *Dataset) (row, col float64) {
-	row, col = 1.0, 1.0 // Defaults
-
-	if elem, ok := ds.FindElement(0x0028, 0x0030); ok { // Pixel Spacing
-		if s, ok := elem.GetString(); ok {
-			fmt.Sscanf(s, "%f\\%f", &row, &col)
-		}
-	}
-
-	return
+	sp := GetCalibratedSpacing(ds)
+	return sp.Row, sp.Col
 }
 
 // GetSliceThickness returns the slice thickness in mm
 func GetSliceThickness(ds *Dataset) float64 {
-	if elem, ok := ds.FindElement(0x0018, 0x0050); ok {
-		if s, ok := elem.GetString(); ok {
-			var thickness float64
-			fmt.Sscanf(s, "%f", &thickness)
-			return thickness
-		}
+	if v := dsValues(ds, tag.SliceThickness); len(v) > 0 {
+		return v[0]
 	}
 	return 1.0 // Default
 }
 
 // GetImagePositionPatient returns the position of the image origin
 func GetImagePositionPatient(ds *Dataset) []float64 {
-	if elem, ok := ds.FindElement(0x0020, 0x0032); ok {
-		if s, ok := elem.GetString(); ok {
-			var x, y, z float64
-			if _, err := fmt.Sscanf(s, "%f\\%f\\%f", &x, &y, &z); err == nil {
-				return []float64{x, y, z}
-			}
-		}
+	if v := dsValues(ds, tag.ImagePositionPatient); len(v) >= 3 {
+		return v[:3]
 	}
 	// Default to 0,0,0
 	return []float64{0.0, 0.0, 0.0}
@@ -257,14 +293,43 @@ func GetImagePositionPatient(ds *Dataset) []float64 {
 
 // GetImageOrientationPatient returns the orientation cosines
 func GetImageOrientationPatient(ds *Dataset) []float64 {
-	if elem, ok := ds.FindElement(0x0020, 0x0037); ok {
-		if s, ok := elem.GetString(); ok {
-			var r1, r2, r3, c1, c2, c3 float64
-			if _, err := fmt.Sscanf(s, "%f\\%f\\%f\\%f\\%f\\%f", &r1, &r2, &r3, &c1, &c2, &c3); err == nil {
-				return []float64{r1, r2, r3, c1, c2, c3}
-			}
-		}
+	if v := dsValues(ds, tag.ImageOrientationPatient); len(v) >= 6 {
+		return v[:6]
 	}
 	// Default to Identity
 	return []float64{1.0, 0.0, 0.0, 0.0, 1.0, 0.0}
 }
+
+// dsValues returns the numeric values of a DS/FL/FD element.
+// Strings are split on backslash; elements written without a known VR
+// (read back as raw bytes) are treated as strings. Returns nil if absent or unparseable.
+func dsValues(ds *Dataset, t tag.Tag) []float64 {
+	elem, ok := ds.FindElement(t.Group, t.Element)
+	if !ok {
+		return nil
+	}
+	if f, ok := elem.GetFloats(); ok {
+		return f
+	}
+
+	var s string
+	switch v := elem.Value.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return nil
+	}
+
+	parts := strings.Split(strings.Trim(s, " \x00"), "\\")
+	res := make([]float64, 0, len(parts))
+	for _, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return nil
+		}
+		res = append(res, f)
+	}
+	return res
+}
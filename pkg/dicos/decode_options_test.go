@@ -0,0 +1,64 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mislabeledCT returns JPEG-LS frames declared as JPEG Lossless
+func mislabeledCT(t *testing.T) *Dataset {
+	t.Helper()
+	ds, err := ReadBufferContext(context.Background(), writeTestCT(t, 8, 8, 1, CodecJPEGLS))
+	require.NoError(t, err)
+	ts, ok := ds.FindElement(0x0002, 0x0010)
+	require.True(t, ok)
+	ts.Value = CodecJPEGLi.TransferSyntaxUID()
+	return ds
+}
+
+func TestDecodeOptions_ForceCodec(t *testing.T) {
+	ds := mislabeledCT(t)
+
+	vol, err := DecodeVolumeWithOptions(context.Background(), ds, DecodeOptions{ForceCodec: "jpegls"})
+	require.NoError(t, err)
+	for i, v := range vol.Data {
+		require.Equal(t, uint16(i), v, "voxel %d", i)
+	}
+
+	_, err = DecodeVolumeWithOptions(context.Background(), ds, DecodeOptions{ForceCodec: "jpeg-xl"})
+	assert.ErrorContains(t, err, `unknown codec "jpeg-xl"`)
+}
+
+func TestDecodeOptions_Override(t *testing.T) {
+	ds := mislabeledCT(t)
+
+	var declared TransferSyntax
+	vol, err := DecodeVolumeWithOptions(context.Background(), ds, DecodeOptions{
+		Override: func(ts TransferSyntax, sniffed Codec) Codec {
+			declared = ts
+			return sniffed
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, TransferSyntax(CodecJPEGLi.TransferSyntaxUID()), declared)
+	assert.Equal(t, uint16(63), vol.Data[63])
+}
+
+func TestDecodeOptions_LogsSniffDisagreement(t *testing.T) {
+	ds := mislabeledCT(t)
+
+	var logs bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(logging.Logger(&logs, false, slog.LevelWarn))
+	defer slog.SetDefault(prev)
+
+	_, _ = DecodeVolumeContext(context.Background(), ds)
+	assert.Contains(t, logs.String(), "Frame encoding disagrees with transfer syntax")
+	assert.Contains(t, logs.String(), `"sniffed":"jpeg-ls"`)
+}
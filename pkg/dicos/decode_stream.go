@@ -0,0 +1,194 @@
+package dicos
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"runtime"
+	"sync"
+)
+
+// DecodeSlices decodes the frames of ds one slice at a time and calls fn with
+// each, in frame order, without materializing the whole volume. Frames are
+// decoded ahead of fn on DecodeOptions.Workers goroutines, and at most that
+// many slices are held at once. The slice passed to fn is reused once fn
+// returns, so fn must copy anything it keeps. An error from fn stops decoding
+// and is returned. Signed samples are sign extended as by DecodeVolume, and
+// color slices hold SamplesPerPixel samples per pixel.
+//
+// Example:
+//
+//	err := dicos.DecodeSlices(ctx, ds, func(z int, slice []uint16) error {
+//		return writeSlicePNG(fmt.Sprintf("slice-%04d.png", z), slice)
+//	})
+func DecodeSlices(ctx context.Context, ds *Dataset, fn func(z int, slice []uint16) error) error {
+	rows := GetRows(ds)
+	cols := GetColumns(ds)
+	if rows == 0 || cols == 0 {
+		return fmt.Errorf("invalid dimensions: %dx%d", cols, rows)
+	}
+	pd, err := ds.GetPixelDataContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	bitsStored := 16
+	if ds.PixelRepresentation() == 1 {
+		bitsStored = ds.BitsStored()
+	}
+	samples := max(ds.SamplesPerPixel(), 1)
+	workers := min(decodeWorkers(ctx), max(len(pd.Frames), 1))
+	if err := reserveMemory(ctx, ResourceFrame, int64(workers)*int64(rows)*int64(cols)*int64(samples)*2); err != nil {
+		return err
+	}
+	// one buffer per slot, returned before the slot is released
+	free := make(chan []uint16, workers)
+	for range workers {
+		free <- make([]uint16, rows*cols*samples)
+	}
+	return decodeFrames(ctx, pd, GetTransferSyntax(ds), rows, cols, samples, workers,
+		func(int) []uint16 { return <-free },
+		func(z int, slice []uint16) error {
+			defer func() { free <- slice }()
+			signExtend(slice, bitsStored)
+			return fn(z, slice)
+		})
+}
+
+// decodeWorkers returns the number of goroutines decoding frames, from the
+// DecodeOptions carried by ctx
+func decodeWorkers(ctx context.Context) int {
+	if opts, _ := ctx.Value(decodeOptionsKey{}).(DecodeOptions); opts.Workers > 0 {
+		return opts.Workers
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// frameResult is a decoded frame or the error decoding it
+type frameResult struct {
+	slice []uint16
+	err   error
+}
+
+// decodeFrames decodes every frame of pd on up to workers goroutines, each
+// into the buffer returned by dst, and calls emit, when not nil, with them in
+// frame order. At most workers frames are decoded ahead of emit. The first
+// error in frame order cancels the remaining frames and is returned.
+func decodeFrames(ctx context.Context, pd *PixelData, ts TransferSyntax, rows, cols, samples, workers int,
+	dst func(z int) []uint16, emit func(z int, slice []uint16) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+
+	results := make([]chan frameResult, len(pd.Frames))
+	for z := range results {
+		results[z] = make(chan frameResult, 1)
+	}
+	slots := make(chan struct{}, max(workers, 1))
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for z := range pd.Frames {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				results[z] <- frameResult{err: ctx.Err()}
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				buf := dst(z)
+				err := decodeFrameInto(ctx, pd, z, rows, cols, samples, ts, buf)
+				results[z] <- frameResult{slice: buf, err: err}
+			}()
+		}
+	}()
+
+	for z := range results {
+		r := <-results[z]
+		if r.err != nil {
+			return r.err
+		}
+		if emit != nil {
+			if err := emit(z, r.slice); err != nil {
+				return err
+			}
+		}
+		<-slots
+	}
+	return nil
+}
+
+// decodeFrameInto decodes frame z of pd into dst, which holds rows*cols
+// pixels of samples each
+func decodeFrameInto(ctx context.Context, pd *PixelData, z, rows, cols, samples int, ts TransferSyntax, dst []uint16) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	frame := pd.Frames[z]
+	if !pd.IsEncapsulated {
+		n := copy(dst, frame.Data)
+		clear(dst[n:])
+		return nil
+	}
+	img, err := decodeCompressedFrame(ctx, frame.CompressedData, rows, cols, ts)
+	if err != nil {
+		return fmt.Errorf("decoding frame %d: %w", z, err)
+	}
+	if b := img.Bounds(); z == 0 && (b.Dx() != cols || b.Dy() != rows) {
+		logger(ctx).WarnContext(ctx, "Decoded image mismatch",
+			"width", b.Dx(), "height", b.Dy(),
+			"expected_width", cols, "expected_height", rows)
+	}
+	copyFrame(dst, img, rows, cols, samples)
+	return nil
+}
+
+// copyFrame copies the samples of a decoded frame into dst, a rows x cols
+// slice, clipping or zero filling when the sizes disagree. Gray16 and Gray
+// images are read from their pixel buffers; other images go through
+// frameSample. With 3 samples, 8-bit R, G, B are interleaved per pixel.
+func copyFrame(dst []uint16, img image.Image, rows, cols, samples int) {
+	b := img.Bounds()
+	w, h := min(b.Dx(), cols), min(b.Dy(), rows)
+	if w < cols || h < rows {
+		clear(dst)
+	}
+	if samples == 3 {
+		for y := range h {
+			row := dst[y*cols*3 : (y*cols+w)*3]
+			for x := range w {
+				r, g, bl, _ := img.At(b.Min.X+x, b.Min.Y+y).RGBA()
+				row[x*3], row[x*3+1], row[x*3+2] = uint16(r>>8), uint16(g>>8), uint16(bl>>8)
+			}
+		}
+		return
+	}
+	switch m := img.(type) {
+	case *image.Gray16:
+		for y := range h {
+			src := m.Pix[m.PixOffset(b.Min.X, b.Min.Y+y):]
+			row := dst[y*cols : y*cols+w]
+			for x := range row {
+				row[x] = uint16(src[2*x])<<8 | uint16(src[2*x+1])
+			}
+		}
+	case *image.Gray:
+		for y := range h {
+			src := m.Pix[m.PixOffset(b.Min.X, b.Min.Y+y):]
+			row := dst[y*cols : y*cols+w]
+			for x := range row {
+				row[x] = uint16(src[x])
+			}
+		}
+	default:
+		for y := range h {
+			row := dst[y*cols : y*cols+w]
+			for x := range row {
+				row[x] = frameSample(img, b.Min.X+x, b.Min.Y+y)
+			}
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package dicos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDecodeVolume_BitDepths checks that encapsulated frames decode to the
+// same sample values as native pixel data, whether the codec returns an
+// 8-bit or a 16-bit gray image
+func TestDecodeVolume_BitDepths(t *testing.T) {
+	const rows, cols = 4, 4
+	data := make([]uint16, 2*rows*cols)
+	for i := range data {
+		data[i] = uint16(i * 7 % 256)
+	}
+	build := func(bits int, opts ...Option) *Dataset {
+		ds, err := NewDataset(append([]Option{
+			WithElement(tag.Rows, uint16(rows)),
+			WithElement(tag.Columns, uint16(cols)),
+			WithElement(tag.BitsAllocated, uint16(bits)),
+			WithElement(tag.BitsStored, uint16(bits)),
+			WithElement(tag.NumberOfFrames, "2"),
+		}, opts...)...)
+		require.NoError(t, err)
+		return ds
+	}
+
+	for _, bits := range []int{8, 16} {
+		for _, codec := range []Codec{nil, CodecJPEGLS, CodecJPEGLi, CodecRLE, CodecJPEG2000} {
+			name := "native"
+			if codec != nil {
+				name = codec.Name()
+			}
+			t.Run(fmt.Sprintf("%s/%d", name, bits), func(t *testing.T) {
+				ds := build(bits, WithPixelData(rows, cols, bits, data, codec))
+				vol, err := DecodeVolume(ds)
+				require.NoError(t, err)
+				assert.Equal(t, data, vol.Data)
+
+				pd, err := ds.GetPixelData()
+				require.NoError(t, err)
+				frame, err := DecodeFrameData(pd, 1, rows, cols, ds.TransferSyntax())
+				require.NoError(t, err)
+				assert.Equal(t, data[rows*cols:], frame)
+			})
+		}
+	}
+
+	// One instance whose frames were encoded at different depths
+	frames := make([]Frame, 2)
+	for i, bits := range []int{8, 16} {
+		b, err := encodeGrayFrame(CodecJPEGLS, data[i*rows*cols:(i+1)*rows*cols], rows, cols, SampleFormat{bits, bits, false})
+		require.NoError(t, err)
+		frames[i] = Frame{CompressedData: b}
+	}
+	ds := build(16, WithRawPixelData(&PixelData{IsEncapsulated: true, Frames: frames}))
+	vol, err := DecodeVolume(ds)
+	require.NoError(t, err)
+	assert.Equal(t, data, vol.Data)
+}
+
+func TestDecodeSlices(t *testing.T) {
+	const rows, cols, frames = 8, 8, 6
+	data := make([]uint16, frames*rows*cols)
+	for i := range data {
+		data[i] = uint16(i * 37 % 4096)
+	}
+	for _, codec := range []Codec{nil, CodecJPEGLS, CodecRLE} {
+		name := "native"
+		if codec != nil {
+			name = codec.Name()
+		}
+		ds, err := NewDataset(
+			WithElement(tag.Rows, uint16(rows)),
+			WithElement(tag.Columns, uint16(cols)),
+			WithElement(tag.NumberOfFrames, fmt.Sprint(frames)),
+			WithPixelData(rows, cols, 16, data, codec),
+		)
+		require.NoError(t, err)
+
+		for _, workers := range []int{1, 3, 16} {
+			t.Run(fmt.Sprintf("%s/%d", name, workers), func(t *testing.T) {
+				ctx := WithDecodeOptions(context.Background(), DecodeOptions{Workers: workers})
+				vol, err := DecodeVolumeContext(ctx, ds)
+				require.NoError(t, err)
+				assert.Equal(t, data, vol.Data)
+
+				var got []uint16
+				var order []int
+				err = DecodeSlices(ctx, ds, func(z int, slice []uint16) error {
+					order = append(order, z)
+					got = append(got, slice...)
+					return nil
+				})
+				require.NoError(t, err)
+				assert.Equal(t, []int{0, 1, 2, 3, 4, 5}, order)
+				assert.Equal(t, data, got)
+			})
+		}
+	}
+
+	t.Run("stop", func(t *testing.T) {
+		ds, err := NewDataset(
+			WithElement(tag.Rows, uint16(rows)),
+			WithElement(tag.Columns, uint16(cols)),
+			WithElement(tag.NumberOfFrames, fmt.Sprint(frames)),
+			WithPixelData(rows, cols, 16, data, CodecJPEGLS),
+		)
+		require.NoError(t, err)
+		stop := errors.New("stop")
+		calls := 0
+		err = DecodeSlices(context.Background(), ds, func(z int, slice []uint16) error {
+			calls++
+			if z == 2 {
+				return stop
+			}
+			return nil
+		})
+		assert.ErrorIs(t, err, stop)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("bad frame", func(t *testing.T) {
+		ds, err := NewDataset(
+			WithElement(tag.Rows, uint16(rows)),
+			WithElement(tag.Columns, uint16(cols)),
+			WithElement(tag.NumberOfFrames, "2"),
+			WithRawPixelData(&PixelData{IsEncapsulated: true, Frames: []Frame{
+				{CompressedData: []byte{0xFF, 0xD8, 0xFF, 0xF7, 0x00}},
+				{CompressedData: []byte{0xFF, 0xD8, 0xFF, 0xF7, 0x00}},
+			}}),
+		)
+		require.NoError(t, err)
+		_, err = DecodeVolume(ds)
+		assert.ErrorContains(t, err, "decoding frame 0")
+	})
+}
+
+func TestCopyFrame(t *testing.T) {
+	gray16 := image.NewGray16(image.Rect(0, 0, 3, 2))
+	gray := image.NewGray(image.Rect(0, 0, 3, 2))
+	rgba := image.NewRGBA64(image.Rect(0, 0, 3, 2))
+	for y := range 2 {
+		for x := range 3 {
+			v := uint16(y*3 + x + 1)
+			gray16.SetGray16(x, y, color.Gray16{Y: v * 1000})
+			gray.SetGray(x, y, color.Gray{Y: uint8(v * 10)})
+			rgba.SetRGBA64(x, y, color.RGBA64{R: v * 1000, G: v * 1000, B: v * 1000, A: 0xFFFF})
+		}
+	}
+	for name, tc := range map[string]struct {
+		img   image.Image
+		scale uint16
+	}{
+		"gray16": {img: gray16, scale: 1000},
+		"gray":   {img: gray, scale: 10},
+		"rgba":   {img: rgba, scale: 1000},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// clipped to two columns and zero filled to three rows
+			dst := []uint16{9, 9, 9, 9, 9, 9}
+			copyFrame(dst, tc.img, 3, 2, 1)
+			s := tc.scale
+			assert.Equal(t, []uint16{1 * s, 2 * s, 4 * s, 5 * s, 0, 0}, dst)
+		})
+	}
+}
@@ -0,0 +1,357 @@
+package dicos
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// MediaStorageDirectoryStorageUID is the SOP Class of a DICOMDIR
+const MediaStorageDirectoryStorageUID = "1.2.840.10008.1.3.10"
+
+// DICOMDIRName is the name of the DICOMDIR at the root of a File-set
+const DICOMDIRName = "DICOMDIR"
+
+// fileIDComponent is one level of a File ID (PS3.10 8.2 and 8.5): up to 8
+// upper case letters, digits and underscores
+var fileIDComponent = regexp.MustCompile(`^[A-Z0-9_]{1,8}$`)
+
+// FileSetEntry is an instance of a File-set and its File ID, the components
+// of its path below the File-set root, e.g. DICOS, ST000001, SE000001, IM000001
+type FileSetEntry struct {
+	FileID  []string
+	Dataset *Dataset
+}
+
+// Path returns the path of the entry below root on the local file system
+func (e FileSetEntry) Path(root string) string {
+	return filepath.Join(append([]string{root}, e.FileID...)...)
+}
+
+// ExportFileSet writes instances to dir as a DICOM File-set for removable
+// media: each instance under DICOS/STnnnnnn/SEnnnnnn/IMnnnnnn, one directory
+// per study and series, and a DICOMDIR indexing them by patient, study,
+// series and instance. fileSetID labels the File-set and may be empty. The
+// entries are returned in DICOMDIR order.
+//
+// Example:
+//
+//	entries, err := dicos.ExportFileSet("/media/usb", "CASE_1234", instances)
+func ExportFileSet(dir, fileSetID string, instances []*Dataset) ([]FileSetEntry, error) {
+	if len(instances) == 0 {
+		return nil, fmt.Errorf("file-set needs at least one instance")
+	}
+	entries, err := fileSetEntries(instances)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		path := e.Path(dir)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, err
+		}
+		ds, err := withFileMetaGroupLength(e.Dataset)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := WriteFile(path, ds); err != nil {
+			return nil, fmt.Errorf("%s: %w", strings.Join(e.FileID, "/"), err)
+		}
+	}
+
+	f, err := os.Create(filepath.Join(dir, DICOMDIRName))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if _, err := WriteDICOMDIR(f, fileSetID, entries); err != nil {
+		return nil, err
+	}
+	return entries, f.Close()
+}
+
+// fileSetEntries assigns File IDs to instances, grouped by study and series
+// in the order they first appear
+func fileSetEntries(instances []*Dataset) ([]FileSetEntry, error) {
+	type series struct {
+		uid       string
+		instances []*Dataset
+	}
+	type study struct {
+		uid    string
+		series []*series
+	}
+	var studies []*study
+	seen := make(map[string]bool, len(instances))
+	for i, ds := range instances {
+		uid := stringValue(ds, tag.SOPInstanceUID)
+		if uid == "" {
+			return nil, fmt.Errorf("instance %d has no SOP Instance UID", i)
+		}
+		if seen[uid] {
+			return nil, fmt.Errorf("instance %d: SOP Instance UID %s is repeated", i, uid)
+		}
+		seen[uid] = true
+
+		studyUID, seriesUID := stringValue(ds, tag.StudyInstanceUID), stringValue(ds, tag.SeriesInstanceUID)
+		var st *study
+		for _, s := range studies {
+			if s.uid == studyUID {
+				st = s
+			}
+		}
+		if st == nil {
+			st = &study{uid: studyUID}
+			studies = append(studies, st)
+		}
+		var se *series
+		for _, s := range st.series {
+			if s.uid == seriesUID {
+				se = s
+			}
+		}
+		if se == nil {
+			se = &series{uid: seriesUID}
+			st.series = append(st.series, se)
+		}
+		se.instances = append(se.instances, ds)
+	}
+
+	entries := make([]FileSetEntry, 0, len(instances))
+	for i, st := range studies {
+		for j, se := range st.series {
+			for k, ds := range se.instances {
+				entries = append(entries, FileSetEntry{
+					FileID:  []string{"DICOS", fmt.Sprintf("ST%06d", i+1), fmt.Sprintf("SE%06d", j+1), fmt.Sprintf("IM%06d", k+1)},
+					Dataset: ds,
+				})
+			}
+		}
+	}
+	return entries, nil
+}
+
+// WriteDICOMDIR writes the DICOMDIR of a File-set (PS3.3 F.2, Basic Directory
+// IOD) indexing entries under PATIENT, STUDY, SERIES and IMAGE records. The
+// records follow the grouping of the entries by Patient ID, Study Instance UID
+// and Series Instance UID, in the order each first appears, and are linked by
+// the byte offsets the standard requires.
+func WriteDICOMDIR(w io.Writer, fileSetID string, entries []FileSetEntry) (int64, error) {
+	if len(fileSetID) > 16 {
+		return 0, fmt.Errorf("file-set ID %q is longer than 16 characters", fileSetID)
+	}
+	root, err := directoryTree(entries)
+	if err != nil {
+		return 0, err
+	}
+
+	ds, err := NewDataset(
+		WithFileMeta(MediaStorageDirectoryStorageUID, NewUID(), string(ExplicitVRLittleEndian)),
+		withVR(tag.FileSetID, "CS", fileSetID),
+		withVR(tag.OffsetOfTheFirstDirectoryRecordOfTheRoot, "UL", uint32(0)),
+		withVR(tag.OffsetOfTheLastDirectoryRecordOfTheRoot, "UL", uint32(0)),
+		withVR(tag.FileSetConsistencyFlag, "US", uint16(0)),
+	)
+	if err != nil {
+		return 0, err
+	}
+	ds, err = withFileMetaGroupLength(ds)
+	if err != nil {
+		return 0, err
+	}
+
+	// Records are items of one sequence, depth first. Their offsets, from
+	// the start of the file to each item tag, only depend on the encoded
+	// length of what precedes them, which zero offsets do not change.
+	var records []*Dataset
+	var flatten func(nodes []*directoryNode)
+	flatten = func(nodes []*directoryNode) {
+		for _, n := range nodes {
+			records = append(records, n.record)
+			flatten(n.children)
+		}
+	}
+	flatten(root)
+
+	offset := uint32(128 + 4) // preamble and DICM
+	for _, t := range sortedTags(ds) {
+		n, err := writeElement(io.Discard, ds.Elements[t], false)
+		if err != nil {
+			return 0, err
+		}
+		offset += uint32(n)
+	}
+	offset += 12 // sequence element header, undefined length
+	offsets := make(map[*Dataset]uint32, len(records))
+	for _, r := range records {
+		n, err := writeDataSetBody(io.Discard, r, false)
+		if err != nil {
+			return 0, err
+		}
+		offsets[r] = offset
+		offset += 8 + uint32(n) // item tag and length
+	}
+
+	var link func(nodes []*directoryNode)
+	link = func(nodes []*directoryNode) {
+		for i, n := range nodes {
+			var next, lower uint32
+			if i+1 < len(nodes) {
+				next = offsets[nodes[i+1].record]
+			}
+			if len(n.children) > 0 {
+				lower = offsets[n.children[0].record]
+			}
+			n.record.Elements[tag.OffsetOfTheNextDirectoryRecord].Value = next
+			n.record.Elements[tag.OffsetOfReferencedLowerLevelDirectoryEntity].Value = lower
+			link(n.children)
+		}
+	}
+	link(root)
+	ds.Elements[tag.OffsetOfTheFirstDirectoryRecordOfTheRoot].Value = offsets[root[0].record]
+	ds.Elements[tag.OffsetOfTheLastDirectoryRecordOfTheRoot].Value = offsets[root[len(root)-1].record]
+	if err := WithSequence(tag.DirectoryRecordSequence, records...)(ds); err != nil {
+		return 0, err
+	}
+	return Write(w, ds)
+}
+
+// directoryNode is a directory record and the records of its lower level
+// entity
+type directoryNode struct {
+	key      string
+	record   *Dataset
+	children []*directoryNode
+}
+
+// directoryTree builds the PATIENT, STUDY, SERIES and IMAGE records of entries
+func directoryTree(entries []FileSetEntry) ([]*directoryNode, error) {
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("file-set needs at least one instance")
+	}
+	child := func(nodes *[]*directoryNode, key string, record func() (*Dataset, error)) (*directoryNode, error) {
+		for _, n := range *nodes {
+			if n.key == key {
+				return n, nil
+			}
+		}
+		r, err := record()
+		if err != nil {
+			return nil, err
+		}
+		n := &directoryNode{key: key, record: r}
+		*nodes = append(*nodes, n)
+		return n, nil
+	}
+
+	var root []*directoryNode
+	for i, e := range entries {
+		if len(e.FileID) == 0 || len(e.FileID) > 8 {
+			return nil, fmt.Errorf("entry %d: file ID needs 1 to 8 components, got %d", i, len(e.FileID))
+		}
+		for _, c := range e.FileID {
+			if !fileIDComponent.MatchString(c) {
+				return nil, fmt.Errorf("entry %d: file ID component %q is not 1 to 8 of A-Z, 0-9 and _", i, c)
+			}
+		}
+		ds := e.Dataset
+		patient, err := child(&root, stringValue(ds, tag.PatientID), func() (*Dataset, error) {
+			return directoryRecord("PATIENT", ds, tag.PatientName, tag.PatientID)
+		})
+		if err != nil {
+			return nil, err
+		}
+		study, err := child(&patient.children, stringValue(ds, tag.StudyInstanceUID), func() (*Dataset, error) {
+			return directoryRecord("STUDY", ds, tag.StudyDate, tag.StudyTime, tag.AccessionNumber,
+				tag.StudyDescription, tag.StudyInstanceUID, tag.StudyID)
+		})
+		if err != nil {
+			return nil, err
+		}
+		series, err := child(&study.children, stringValue(ds, tag.SeriesInstanceUID), func() (*Dataset, error) {
+			return directoryRecord("SERIES", ds, tag.Modality, tag.SeriesInstanceUID, tag.SeriesNumber)
+		})
+		if err != nil {
+			return nil, err
+		}
+		image, err := directoryRecord("IMAGE", ds, tag.InstanceNumber)
+		if err != nil {
+			return nil, err
+		}
+		if err := applyOptions(image,
+			withVR(tag.ReferencedFileID, "CS", e.FileID),
+			withVR(tag.ReferencedSOPClassUIDInFile, "UI", stringValue(ds, tag.SOPClassUID)),
+			withVR(tag.ReferencedSOPInstanceUIDInFile, "UI", stringValue(ds, tag.SOPInstanceUID)),
+			withVR(tag.ReferencedTransferSyntaxUIDInFile, "UI", string(ds.TransferSyntax())),
+		); err != nil {
+			return nil, err
+		}
+		series.children = append(series.children, &directoryNode{record: image})
+	}
+	return root, nil
+}
+
+// directoryRecord returns a record of type typ with the keys copied from ds,
+// left empty when ds lacks them
+func directoryRecord(typ string, ds *Dataset, keys ...Tag) (*Dataset, error) {
+	r, err := NewDataset(
+		withVR(tag.OffsetOfTheNextDirectoryRecord, "UL", uint32(0)),
+		withVR(tag.OffsetOfReferencedLowerLevelDirectoryEntity, "UL", uint32(0)),
+		withVR(tag.DirectoryRecordType, "CS", typ),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if cs, ok := ds.Elements[tag.SpecificCharacterSet]; ok {
+		r.Elements[tag.SpecificCharacterSet] = cs
+	}
+	for _, t := range keys {
+		if elem, ok := ds.Elements[t]; ok {
+			r.Elements[t] = elem
+		} else if err := withVR(t, GetVR(t), "")(r); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// applyOptions applies opts to ds in order
+func applyOptions(ds *Dataset, opts ...Option) error {
+	for _, opt := range opts {
+		if err := opt(ds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withFileMetaGroupLength returns a shallow copy of ds whose File Meta
+// Information Group Length (0002,0000) holds the length of its other group
+// 0002 elements, adding the File Meta Information Version if missing
+func withFileMetaGroupLength(ds *Dataset) (*Dataset, error) {
+	out := &Dataset{Elements: maps.Clone(ds.Elements)}
+	if _, ok := out.Elements[tag.FileMetaInformationVersion]; !ok {
+		if err := withVR(tag.FileMetaInformationVersion, "OB", []byte{0x00, 0x01})(out); err != nil {
+			return nil, err
+		}
+	}
+	delete(out.Elements, tag.FileMetaInformationGroupLength)
+	var length uint32
+	for _, t := range sortedTags(out) {
+		if t.Group != 0x0002 {
+			break
+		}
+		n, err := writeElement(io.Discard, out.Elements[t], false)
+		if err != nil {
+			return nil, err
+		}
+		length += uint32(n)
+	}
+	return out, withVR(tag.FileMetaInformationGroupLength, "UL", length)(out)
+}
@@ -0,0 +1,99 @@
+package dicos
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportFileSet(t *testing.T) {
+	// two series of one study, and a third series of another study
+	instances := append(studyInstances(t), studyInstances(t)[0])
+	dir := t.TempDir()
+	entries, err := ExportFileSet(dir, "CASE_1234", instances)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, []string{"DICOS", "ST000001", "SE000002", "IM000001"}, entries[1].FileID)
+	assert.Equal(t, []string{"DICOS", "ST000002", "SE000001", "IM000001"}, entries[2].FileID)
+
+	raw, err := os.ReadFile(filepath.Join(dir, DICOMDIRName))
+	require.NoError(t, err)
+	dir0, err := ReadBuffer(raw)
+	require.NoError(t, err)
+	assert.Equal(t, MediaStorageDirectoryStorageUID, stringValue(dir0, tag.MediaStorageSOPClassUID))
+	assert.Equal(t, "CASE_1234", stringValue(dir0, tag.FileSetID))
+	records := GetSequenceItems(dir0, tag.DirectoryRecordSequence)
+	var types []string
+	for _, r := range records {
+		types = append(types, stringValue(r, tag.DirectoryRecordType))
+	}
+	assert.Equal(t, []string{"PATIENT", "STUDY", "SERIES", "IMAGE", "SERIES", "IMAGE", "STUDY", "SERIES", "IMAGE"}, types)
+
+	// find the items in the file independently of the offsets recorded
+	offsetOf := func(ds *Dataset, t Tag) uint32 {
+		v, _ := ds.Elements[t].Value.(uint32)
+		return v
+	}
+	first := offsetOf(dir0, tag.OffsetOfTheFirstDirectoryRecordOfTheRoot)
+	index := make(map[uint32]int)
+	for pos := first; !bytes.Equal(raw[pos:pos+4], []byte{0xFE, 0xFF, 0xDD, 0xE0}); {
+		require.Equal(t, []byte{0xFE, 0xFF, 0x00, 0xE0}, raw[pos:pos+4], "item tag at %d", pos)
+		index[pos] = len(index)
+		pos += 8 + binary.LittleEndian.Uint32(raw[pos+4:])
+	}
+	require.Len(t, index, len(records))
+	assert.Equal(t, first, offsetOf(dir0, tag.OffsetOfTheLastDirectoryRecordOfTheRoot), "one patient")
+
+	// walk the records through their offsets, depth first
+	var walk func(offset uint32) []string
+	walk = func(offset uint32) []string {
+		var out []string
+		for offset != 0 {
+			i, ok := index[offset]
+			require.True(t, ok, "offset %d is not an item", offset)
+			out = append(out, types[i])
+			out = append(out, walk(offsetOf(records[i], tag.OffsetOfReferencedLowerLevelDirectoryEntity))...)
+			offset = offsetOf(records[i], tag.OffsetOfTheNextDirectoryRecord)
+		}
+		return out
+	}
+	assert.Equal(t, types, walk(first))
+
+	// image records reference the instance files
+	var image int
+	for _, r := range records {
+		if stringValue(r, tag.DirectoryRecordType) != "IMAGE" {
+			continue
+		}
+		fileID := strings.Split(stringValue(r, tag.ReferencedFileID), `\`)
+		assert.Equal(t, entries[image].FileID, fileID)
+		ds, err := ReadFile(filepath.Join(append([]string{dir}, fileID...)...))
+		require.NoError(t, err)
+		assert.Equal(t, stringValue(instances[image], tag.SOPInstanceUID), stringValue(r, tag.ReferencedSOPInstanceUIDInFile))
+		assert.Equal(t, stringValue(ds, tag.SOPInstanceUID), stringValue(r, tag.ReferencedSOPInstanceUIDInFile))
+		assert.Equal(t, string(ds.TransferSyntax()), stringValue(r, tag.ReferencedTransferSyntaxUIDInFile))
+		assert.Contains(t, ds.Elements, tag.FileMetaInformationGroupLength)
+		image++
+	}
+	assert.Equal(t, 3, image)
+}
+
+func TestWriteDICOMDIR_Errors(t *testing.T) {
+	ds := studyInstances(t)[0]
+	var buf bytes.Buffer
+	_, err := WriteDICOMDIR(&buf, "A_FILE_SET_ID_TOO_LONG", []FileSetEntry{{FileID: []string{"IM1"}, Dataset: ds}})
+	assert.ErrorContains(t, err, "longer than 16")
+	_, err = WriteDICOMDIR(&buf, "", []FileSetEntry{{FileID: []string{"images", "im1.dcm"}, Dataset: ds}})
+	assert.ErrorContains(t, err, "file ID component")
+	_, err = WriteDICOMDIR(&buf, "", nil)
+	assert.Error(t, err)
+	_, err = ExportFileSet(t.TempDir(), "", []*Dataset{ds, ds})
+	assert.ErrorContains(t, err, "repeated")
+}
@@ -24,7 +24,9 @@
 package dicos
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"log/slog"
@@ -51,16 +53,20 @@ const (
 
 // SOP Class UIDs for DICOS modalities
 const (
-	CTImageStorageUID = "1.2.840.10008.5.1.4.1.1.2"
-	DXImageStorageUID = "1.2.840.10008.5.1.4.1.1.1.1"
-	TDRStorageUID     = "1.2.840.10008.5.1.4.1.1.88.67" // Comprehensive SR
+	CTImageStorageUID               = "1.2.840.10008.5.1.4.1.1.2"
+	DXImageStorageUID               = "1.2.840.10008.5.1.4.1.1.1.1"
+	TDRStorageUID                   = "1.2.840.10008.5.1.4.1.1.88.67" // Comprehensive SR
+	SecondaryCaptureImageStorageUID = "1.2.840.10008.5.1.4.1.1.7"
+	EncapsulatedPDFStorageUID       = "1.2.840.10008.5.1.4.1.1.104.1"
 
 	// DICOS-specific
 	DICOSCTImageStorageUID    = "1.2.840.10008.5.1.4.1.1.501.1"
 	DICOSDXImageStorageUID    = "1.2.840.10008.5.1.4.1.1.501.2"
+	DICOSDXForPresentationUID = "1.2.840.10008.5.1.4.1.1.501.2.1"
 	DICOSTDRStorageUID        = "1.2.840.10008.5.1.4.1.1.501.3"
 	DICOSAIT2DImageStorageUID = "1.2.840.10008.5.1.4.1.1.501.4"
 	DICOSAIT3DImageStorageUID = "1.2.840.10008.5.1.4.1.1.501.5"
+	DICOSQRStorageUID         = "1.2.840.10008.5.1.4.1.1.501.6"
 )
 
 // ReadFile reads a DICOM/DICOS file from disk and returns a parsed Dataset.
@@ -73,6 +79,9 @@ const (
 //
 // Returns an error if the file cannot be opened, read, or parsed.
 //
+// The whole file is loaded into memory; use NewStreamingReader to process
+// volumes too large for that one frame at a time.
+//
 // Example:
 //
 //	ds, err := dicos.ReadFile("/path/to/scan.dcs")
@@ -82,6 +91,12 @@ const (
 //	modality := dicos.GetModality(ds)
 //	fmt.Printf("Modality: %s\n", modality)
 func ReadFile(path string) (*Dataset, error) {
+	return ReadFileContext(context.Background(), path)
+}
+
+// ReadFileContext is ReadFile with a context used for cancellation and
+// carried into log records emitted while parsing.
+func ReadFileContext(ctx context.Context, path string) (*Dataset, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("opening file: %w", err)
@@ -93,7 +108,27 @@ func ReadFile(path string) (*Dataset, error) {
 		return nil, fmt.Errorf("reading file: %w", err)
 	}
 
-	return Parse(bytes.NewReader(data))
+	return ParseContext(ctx, bytes.NewReader(data))
+}
+
+// ReadFileWithOptions reads path using opts. The file is parsed as it is
+// read rather than loaded first, so with SkipPixelData or OnlyTags the bytes
+// past the wanted elements are never read. Bulk payloads are excluded, as the
+// file is closed on return.
+//
+// Example:
+//
+//	ds, err := dicos.ReadFileWithOptions(ctx, path, dicos.ParseOptions{
+//		SkipPixelData: true,
+//		OnlyTags:      []dicos.Tag{tag.StudyInstanceUID},
+//	})
+func ReadFileWithOptions(ctx context.Context, path string, opts ParseOptions) (*Dataset, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening file: %w", err)
+	}
+	defer f.Close()
+	return ParseWithOptions(ctx, bufio.NewReaderSize(f, 64<<10), opts)
 }
 
 // ReadBuffer reads a DICOM/DICOS file from a byte slice and returns a parsed Dataset.
@@ -111,7 +146,13 @@ func ReadFile(path string) (*Dataset, error) {
 //		log.Fatal(err)
 //	}
 func ReadBuffer(data []byte) (*Dataset, error) {
-	return Parse(bytes.NewReader(data))
+	return ReadBufferContext(context.Background(), data)
+}
+
+// ReadBufferContext is ReadBuffer with a context used for cancellation and
+// carried into log records emitted while parsing.
+func ReadBufferContext(ctx context.Context, data []byte) (*Dataset, error) {
+	return ParseContext(ctx, bytes.NewReader(data))
 }
 
 // GetExtension returns the standard DICOS file extension ".dcs".
@@ -181,6 +222,17 @@ func IsAIT3D(ds *Dataset) bool {
 	return checkSOPClass(ds, DICOSAIT3DImageStorageUID)
 }
 
+// IsQR returns true if the dataset represents a QR (Quadrupole Resonance) measurement.
+//
+// Checks the SOP Class UID (0008,0016) for:
+//   - DICOS QR: "1.2.840.10008.5.1.4.1.1.501.6"
+//
+// QR measurements hold the spectrum and detected substances of a quadrupole
+// resonance scan; they carry no pixel data.
+func IsQR(ds *Dataset) bool {
+	return checkSOPClass(ds, DICOSQRStorageUID)
+}
+
 // GetModality returns the Modality (0008,0060) value from the dataset.
 //
 // Common DICOS modality values:
@@ -306,10 +358,8 @@ func GetInstanceNumber(ds *Dataset) int {
 		if v, ok := elem.GetInt(); ok {
 			return v
 		}
-		if s, ok := elem.GetString(); ok {
-			var n int
-			fmt.Sscanf(strings.TrimSpace(s), "%d", &n)
-			return n
+		if v, ok := elem.GetFloats(); ok {
+			return int(v[0])
 		}
 	}
 	return 0
@@ -324,10 +374,8 @@ func GetInstanceNumber(ds *Dataset) int {
 // Returns 0 if not present.
 func GetKVP(ds *Dataset) float64 {
 	if elem, ok := ds.FindElement(tag.KVP.Group, tag.KVP.Element); ok {
-		if s, ok := elem.GetString(); ok {
-			var kvp float64
-			fmt.Sscanf(strings.TrimSpace(s), "%f", &kvp)
-			return kvp
+		if v, ok := elem.GetFloats(); ok {
+			return v[0]
 		}
 	}
 	return 0
@@ -408,7 +456,8 @@ func GetSeriesEnergyDescription(ds *Dataset) string {
 //
 // This heuristic approach handles various vendor implementations where energy level
 // encoding differs. The KVP threshold (110kV) is based on typical dual-energy CT
-// protocols (80kV low, 140kV high).
+// protocols (80kV low, 140kV high). A level found by steps 2-5 is recorded as
+// a WarnInferredEnergyLevel warning, see Dataset.Warnings.
 //
 // Example:
 //
@@ -431,43 +480,54 @@ func GetEnergyLevel(ds *Dataset) string {
 		return "le"
 	}
 
+	level, source := inferEnergyLevel(ds)
+	if level != "" {
+		ds.warn(WarnInferredEnergyLevel, tag.SeriesEnergy,
+			"no SeriesEnergy, energy %q inferred from %s", level, source)
+	}
+	return level
+}
+
+// inferEnergyLevel applies the fallbacks of GetEnergyLevel, returning the
+// level and the attribute it was inferred from
+func inferEnergyLevel(ds *Dataset) (level, source string) {
 	// 2. Check SeriesEnergyDescription
 	energyDesc := strings.ToLower(GetSeriesEnergyDescription(ds))
 	if strings.Contains(energyDesc, "high") || strings.Contains(energyDesc, "he") {
-		return "he"
+		return "he", "SeriesEnergyDescription"
 	}
 	if strings.Contains(energyDesc, "low") || strings.Contains(energyDesc, "le") {
-		return "le"
+		return "le", "SeriesEnergyDescription"
 	}
 
 	// 3. Check ImageComments
 	comments := strings.ToLower(GetImageComments(ds))
 	if strings.Contains(comments, "high_energy") || strings.Contains(comments, "he") {
-		return "he"
+		return "he", "ImageComments"
 	}
 	if strings.Contains(comments, "low_energy") || strings.Contains(comments, "le") {
-		return "le"
+		return "le", "ImageComments"
 	}
 
 	// 4. Check KVP value (typical dual-energy: HE=140kV, LE=80kV)
 	kvp := GetKVP(ds)
 	if kvp >= 110 {
-		return "he"
+		return "he", "KVP"
 	}
 	if kvp > 0 && kvp < 110 {
-		return "le"
+		return "le", "KVP"
 	}
 
 	// 5. Check SeriesDescription for hints
 	desc := strings.ToLower(GetSeriesDescription(ds))
 	if strings.Contains(desc, "_he") || strings.Contains(desc, "density2") || strings.Contains(desc, "high") {
-		return "he"
+		return "he", "SeriesDescription"
 	}
 	if strings.Contains(desc, "_le") || strings.Contains(desc, "density1") || strings.Contains(desc, "low") {
-		return "le"
+		return "le", "SeriesDescription"
 	}
 
-	return ""
+	return "", ""
 }
 
 // GetPixelData extracts pixel data from the PixelData (7FE0,0010) element.
@@ -475,7 +535,8 @@ func GetEnergyLevel(ds *Dataset) string {
 // Returns *PixelData in either native (uncompressed) or encapsulated (compressed) format:
 //
 // Native Format (IsEncapsulated=false):
-//   - Frame.Data contains []uint16 pixel values in row-major order
+//   - Frame.Data contains []uint16 pixel values in row-major order, with
+//     SamplesPerPixel samples per pixel as stored (see PlanarConfiguration)
 //   - Pixels ordered left-to-right, top-to-bottom within each frame
 //   - Multi-frame images have frames stored sequentially
 //
@@ -502,6 +563,12 @@ func GetEnergyLevel(ds *Dataset) string {
 //	// Access pixel values
 //	frame0 := pd.Frames[0].Data // First frame pixels
 func (ds *Dataset) GetPixelData() (*PixelData, error) {
+	return ds.GetPixelDataContext(context.Background())
+}
+
+// GetPixelDataContext is GetPixelData with a context carried into the log
+// records emitted while converting native pixel data.
+func (ds *Dataset) GetPixelDataContext(ctx context.Context) (*PixelData, error) {
 	elem, ok := ds.FindElement(tag.PixelData.Group, tag.PixelData.Element)
 	if !ok {
 		return nil, fmt.Errorf("no pixel data element found")
@@ -531,7 +598,7 @@ func (ds *Dataset) GetPixelData() (*PixelData, error) {
 	numFrames := GetNumberOfFrames(ds)
 	bitsAllocated := GetBitsAllocated(ds)
 
-	slog.Debug("Converting uncompressed pixel data",
+	logger(ctx).DebugContext(ctx, "Converting uncompressed pixel data",
 		slog.Int("rows", rows),
 		slog.Int("cols", cols),
 		slog.Int("numFrames", numFrames),
@@ -548,15 +615,18 @@ func (ds *Dataset) GetPixelData() (*PixelData, error) {
 	}
 
 	bytesPerPixel := (bitsAllocated + 7) / 8
-	pixelsPerFrame := rows * cols
+	pixelsPerFrame := rows * cols * ds.SamplesPerPixel()
 	frameSizeInBytes := pixelsPerFrame * bytesPerPixel
 
-	slog.Debug("Calculated frame metrics",
+	logger(ctx).DebugContext(ctx, "Calculated frame metrics",
 		slog.Int("bytesPerPixel", bytesPerPixel),
 		slog.Int("frameSizeInBytes", frameSizeInBytes),
 		slog.Int("pixelsPerFrame", pixelsPerFrame))
 
 	for i := 0; i < numFrames; i++ {
+		if err := reserveMemory(ctx, ResourceFrame, int64(pixelsPerFrame)*2); err != nil {
+			return nil, err
+		}
 		u16Data := make([]uint16, pixelsPerFrame)
 
 		if len(u16Raw) > 0 {
@@ -593,6 +663,7 @@ func (ds *Dataset) GetPixelData() (*PixelData, error) {
 			Data: u16Data,
 		}
 	}
+	applyFrameMeta(ds, pd)
 
 	return pd, nil
 }
@@ -619,7 +690,9 @@ func (ds *Dataset) GetPixelData() (*PixelData, error) {
 // for CT datasets: if PixelRepresentation=0 and RescaleIntercept is missing, it returns
 // intercept=-32768 to correct this offset.
 //
-// To disable this heuristic, use GetRescaleExplicit(ds) instead.
+// When the heuristic applies it is recorded as a WarnImplicitRescaleIntercept
+// warning, see Dataset.Warnings. To disable it, use GetRescaleExplicit(ds)
+// instead.
 //
 // Default Values:
 //   - If RescaleIntercept (0028,1052) is absent: 0.0 (or -32768.0 for unsigned CT via heuristic)
@@ -638,10 +711,10 @@ func GetRescale(ds *Dataset) (intercept, slope float64) {
 
 	var foundIntercept bool
 	if elem, ok := ds.FindElement(tag.RescaleIntercept.Group, tag.RescaleIntercept.Element); ok {
-		if s, ok := elem.GetString(); ok {
-			fmt.Sscanf(s, "%f", &intercept)
-			foundIntercept = true
+		if v, ok := elem.GetFloats(); ok {
+			intercept = v[0]
 		}
+		foundIntercept = true
 	}
 	// If tag is absent, check for implicit intercept via heuristic
 	// CT images typically have specific defaults or are signed.
@@ -652,12 +725,14 @@ func GetRescale(ds *Dataset) (intercept, slope float64) {
 		if pixelRep == 0 {
 			// Heuristic: Unsigned CT likely implies shifted values
 			intercept = -32768.0
+			ds.warn(WarnImplicitRescaleIntercept, tag.RescaleIntercept,
+				"unsigned CT without RescaleIntercept, assuming -32768")
 		}
 	}
 
 	if elem, ok := ds.FindElement(tag.RescaleSlope.Group, tag.RescaleSlope.Element); ok {
-		if s, ok := elem.GetString(); ok {
-			fmt.Sscanf(s, "%f", &slope)
+		if v, ok := elem.GetFloats(); ok {
+			slope = v[0]
 		}
 	}
 
@@ -684,14 +759,14 @@ func GetRescaleExplicit(ds *Dataset) (intercept, slope float64) {
 	intercept, slope = 0, 1 // Default values
 
 	if elem, ok := ds.FindElement(tag.RescaleIntercept.Group, tag.RescaleIntercept.Element); ok {
-		if s, ok := elem.GetString(); ok {
-			fmt.Sscanf(s, "%f", &intercept)
+		if v, ok := elem.GetFloats(); ok {
+			intercept = v[0]
 		}
 	}
 
 	if elem, ok := ds.FindElement(tag.RescaleSlope.Group, tag.RescaleSlope.Element); ok {
-		if s, ok := elem.GetString(); ok {
-			fmt.Sscanf(s, "%f", &slope)
+		if v, ok := elem.GetFloats(); ok {
+			slope = v[0]
 		}
 	}
 
@@ -0,0 +1,82 @@
+// Package dict is the DICOM/DICOS data dictionary: the keyword, Value
+// Representation, Value Multiplicity and retirement status of each standard
+// tag, including the DICOS groups 4010 and 6100.
+//
+// The entries are generated from dicom.dic, which uses the DCMTK dictionary
+// layout, by running go generate in this directory.
+package dict
+
+//go:generate go run gen.go
+
+import (
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// Entry describes one data element of the dictionary
+type Entry struct {
+	Tag     tag.Tag
+	VR      string   // VR used when writing, empty for item delimiters
+	VRs     []string // all allowed VRs, e.g. US and SS for pixel values
+	VM      string   // e.g. "1", "1-n", "3-3n"
+	Keyword string
+	Retired bool
+}
+
+var (
+	byTag     = make(map[tag.Tag]*Entry, len(entries))
+	byKeyword = make(map[string]*Entry, len(entries))
+)
+
+func init() {
+	for i := range entries {
+		e := &entries[i]
+		byTag[e.Tag] = e
+		byKeyword[e.Keyword] = e
+	}
+}
+
+// Lookup returns the dictionary entry for t. Besides the listed tags it
+// resolves group lengths (gggg,0000), private creators (gggg,0010-00FF) in odd
+// groups and the repeating overlay groups 6000-601E.
+func Lookup(t tag.Tag) (Entry, bool) {
+	if e, ok := byTag[t]; ok {
+		return *e, true
+	}
+	switch {
+	case t.Element == 0x0000:
+		return Entry{Tag: t, VR: "UL", VRs: []string{"UL"}, VM: "1", Keyword: "GenericGroupLength", Retired: true}, true
+	case t.Group%2 == 1 && t.Element >= 0x0010 && t.Element <= 0x00FF:
+		return Entry{Tag: t, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "PrivateCreator"}, true
+	case t.Group&0xFF00 == 0x6000 && t.Group%2 == 0 && t.Group <= 0x601E:
+		if e, ok := byTag[tag.Tag{Group: 0x6000, Element: t.Element}]; ok {
+			r := *e
+			r.Tag = t
+			return r, true
+		}
+	}
+	return Entry{}, false
+}
+
+// ByKeyword returns the dictionary entry with the given keyword, e.g.
+// "PatientID". Repeating overlay entries are returned for group 6000.
+func ByKeyword(keyword string) (Entry, bool) {
+	if e, ok := byKeyword[keyword]; ok {
+		return *e, true
+	}
+	return Entry{}, false
+}
+
+// VR returns the VR to write for t, or UN when the dictionary does not know it
+func VR(t tag.Tag) string {
+	if e, ok := Lookup(t); ok && e.VR != "" {
+		return e.VR
+	}
+	return "UN"
+}
+
+// Entries returns a copy of the listed entries in tag order
+func Entries() []Entry {
+	out := make([]Entry, len(entries))
+	copy(out, entries)
+	return out
+}
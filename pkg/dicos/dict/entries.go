@@ -0,0 +1,1154 @@
+// Code generated by gen.go; DO NOT EDIT.
+
+package dict
+
+import "github.com/jpfielding/dicos.go/pkg/dicos/tag"
+
+var entries = []Entry{
+	{Tag: tag.Tag{Group: 0x0002, Element: 0x0000}, VR: "UL", VRs: []string{"UL"}, VM: "1", Keyword: "FileMetaInformationGroupLength", Retired: false},
+	{Tag: tag.Tag{Group: 0x0002, Element: 0x0001}, VR: "OB", VRs: []string{"OB"}, VM: "1", Keyword: "FileMetaInformationVersion", Retired: false},
+	{Tag: tag.Tag{Group: 0x0002, Element: 0x0002}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "MediaStorageSOPClassUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0002, Element: 0x0003}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "MediaStorageSOPInstanceUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0002, Element: 0x0010}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "TransferSyntaxUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0002, Element: 0x0012}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "ImplementationClassUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0002, Element: 0x0013}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "ImplementationVersionName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0002, Element: 0x0016}, VR: "AE", VRs: []string{"AE"}, VM: "1", Keyword: "SourceApplicationEntityTitle", Retired: false},
+	{Tag: tag.Tag{Group: 0x0002, Element: 0x0017}, VR: "AE", VRs: []string{"AE"}, VM: "1", Keyword: "SendingApplicationEntityTitle", Retired: false},
+	{Tag: tag.Tag{Group: 0x0002, Element: 0x0018}, VR: "AE", VRs: []string{"AE"}, VM: "1", Keyword: "ReceivingApplicationEntityTitle", Retired: false},
+	{Tag: tag.Tag{Group: 0x0002, Element: 0x0026}, VR: "UR", VRs: []string{"UR"}, VM: "1", Keyword: "SourcePresentationAddress", Retired: false},
+	{Tag: tag.Tag{Group: 0x0002, Element: 0x0027}, VR: "UR", VRs: []string{"UR"}, VM: "1", Keyword: "SendingPresentationAddress", Retired: false},
+	{Tag: tag.Tag{Group: 0x0002, Element: 0x0028}, VR: "UR", VRs: []string{"UR"}, VM: "1", Keyword: "ReceivingPresentationAddress", Retired: false},
+	{Tag: tag.Tag{Group: 0x0002, Element: 0x0100}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "PrivateInformationCreatorUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0002, Element: 0x0102}, VR: "OB", VRs: []string{"OB"}, VM: "1", Keyword: "PrivateInformation", Retired: false},
+	{Tag: tag.Tag{Group: 0x0004, Element: 0x1130}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "FileSetID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0004, Element: 0x1141}, VR: "CS", VRs: []string{"CS"}, VM: "1-8", Keyword: "FileSetDescriptorFileID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0004, Element: 0x1142}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "SpecificCharacterSetOfFileSetDescriptorFile", Retired: false},
+	{Tag: tag.Tag{Group: 0x0004, Element: 0x1200}, VR: "UL", VRs: []string{"UL"}, VM: "1", Keyword: "OffsetOfTheFirstDirectoryRecordOfTheRootDirectoryEntity", Retired: false},
+	{Tag: tag.Tag{Group: 0x0004, Element: 0x1202}, VR: "UL", VRs: []string{"UL"}, VM: "1", Keyword: "OffsetOfTheLastDirectoryRecordOfTheRootDirectoryEntity", Retired: false},
+	{Tag: tag.Tag{Group: 0x0004, Element: 0x1212}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "FileSetConsistencyFlag", Retired: false},
+	{Tag: tag.Tag{Group: 0x0004, Element: 0x1220}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "DirectoryRecordSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0004, Element: 0x1400}, VR: "UL", VRs: []string{"UL"}, VM: "1", Keyword: "OffsetOfTheNextDirectoryRecord", Retired: false},
+	{Tag: tag.Tag{Group: 0x0004, Element: 0x1410}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "RecordInUseFlag", Retired: false},
+	{Tag: tag.Tag{Group: 0x0004, Element: 0x1420}, VR: "UL", VRs: []string{"UL"}, VM: "1", Keyword: "OffsetOfReferencedLowerLevelDirectoryEntity", Retired: false},
+	{Tag: tag.Tag{Group: 0x0004, Element: 0x1430}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "DirectoryRecordType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0004, Element: 0x1432}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "PrivateRecordUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0004, Element: 0x1500}, VR: "CS", VRs: []string{"CS"}, VM: "1-8", Keyword: "ReferencedFileID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0004, Element: 0x1510}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "ReferencedSOPClassUIDInFile", Retired: false},
+	{Tag: tag.Tag{Group: 0x0004, Element: 0x1511}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "ReferencedSOPInstanceUIDInFile", Retired: false},
+	{Tag: tag.Tag{Group: 0x0004, Element: 0x1512}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "ReferencedTransferSyntaxUIDInFile", Retired: false},
+	{Tag: tag.Tag{Group: 0x0004, Element: 0x151A}, VR: "UI", VRs: []string{"UI"}, VM: "1-n", Keyword: "ReferencedRelatedGeneralSOPClassUIDInFile", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0001}, VR: "UL", VRs: []string{"UL"}, VM: "1", Keyword: "LengthToEnd", Retired: true},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0005}, VR: "CS", VRs: []string{"CS"}, VM: "1-n", Keyword: "SpecificCharacterSet", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0006}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "LanguageCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0008}, VR: "CS", VRs: []string{"CS"}, VM: "2-n", Keyword: "ImageType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0012}, VR: "DA", VRs: []string{"DA"}, VM: "1", Keyword: "InstanceCreationDate", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0013}, VR: "TM", VRs: []string{"TM"}, VM: "1", Keyword: "InstanceCreationTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0014}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "InstanceCreatorUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0015}, VR: "DT", VRs: []string{"DT"}, VM: "1", Keyword: "InstanceCoercionDateTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0016}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "SOPClassUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0018}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "SOPInstanceUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x001A}, VR: "UI", VRs: []string{"UI"}, VM: "1-n", Keyword: "RelatedGeneralSOPClassUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x001B}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "OriginalSpecializedSOPClassUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0020}, VR: "DA", VRs: []string{"DA"}, VM: "1", Keyword: "StudyDate", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0021}, VR: "DA", VRs: []string{"DA"}, VM: "1", Keyword: "SeriesDate", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0022}, VR: "DA", VRs: []string{"DA"}, VM: "1", Keyword: "AcquisitionDate", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0023}, VR: "DA", VRs: []string{"DA"}, VM: "1", Keyword: "ContentDate", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0024}, VR: "DA", VRs: []string{"DA"}, VM: "1", Keyword: "OverlayDate", Retired: true},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0025}, VR: "DA", VRs: []string{"DA"}, VM: "1", Keyword: "CurveDate", Retired: true},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x002A}, VR: "DT", VRs: []string{"DT"}, VM: "1", Keyword: "AcquisitionDateTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0030}, VR: "TM", VRs: []string{"TM"}, VM: "1", Keyword: "StudyTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0031}, VR: "TM", VRs: []string{"TM"}, VM: "1", Keyword: "SeriesTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0032}, VR: "TM", VRs: []string{"TM"}, VM: "1", Keyword: "AcquisitionTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0033}, VR: "TM", VRs: []string{"TM"}, VM: "1", Keyword: "ContentTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0034}, VR: "TM", VRs: []string{"TM"}, VM: "1", Keyword: "OverlayTime", Retired: true},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0035}, VR: "TM", VRs: []string{"TM"}, VM: "1", Keyword: "CurveTime", Retired: true},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0050}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "AccessionNumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0051}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "IssuerOfAccessionNumberSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0052}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "QueryRetrieveLevel", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0053}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "QueryRetrieveView", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0054}, VR: "AE", VRs: []string{"AE"}, VM: "1-n", Keyword: "RetrieveAETitle", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0055}, VR: "AE", VRs: []string{"AE"}, VM: "1", Keyword: "StationAETitle", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0056}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "InstanceAvailability", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0058}, VR: "UI", VRs: []string{"UI"}, VM: "1-n", Keyword: "FailedSOPInstanceUIDList", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0060}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "Modality", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0061}, VR: "CS", VRs: []string{"CS"}, VM: "1-n", Keyword: "ModalitiesInStudy", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0062}, VR: "UI", VRs: []string{"UI"}, VM: "1-n", Keyword: "SOPClassesInStudy", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0064}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "ConversionType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0068}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "PresentationIntentType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0070}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "Manufacturer", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0080}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "InstitutionName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0081}, VR: "ST", VRs: []string{"ST"}, VM: "1", Keyword: "InstitutionAddress", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0082}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "InstitutionCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0090}, VR: "PN", VRs: []string{"PN"}, VM: "1", Keyword: "ReferringPhysicianName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0092}, VR: "ST", VRs: []string{"ST"}, VM: "1", Keyword: "ReferringPhysicianAddress", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0094}, VR: "SH", VRs: []string{"SH"}, VM: "1-n", Keyword: "ReferringPhysicianTelephoneNumbers", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0096}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ReferringPhysicianIdentificationSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x009C}, VR: "PN", VRs: []string{"PN"}, VM: "1-n", Keyword: "ConsultingPhysicianName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0100}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "CodeValue", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0101}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ExtendedCodeValue", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0102}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "CodingSchemeDesignator", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0103}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "CodingSchemeVersion", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0104}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "CodeMeaning", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0105}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "MappingResource", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0106}, VR: "DT", VRs: []string{"DT"}, VM: "1", Keyword: "ContextGroupVersion", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0107}, VR: "DT", VRs: []string{"DT"}, VM: "1", Keyword: "ContextGroupLocalVersion", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x010B}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "ContextGroupExtensionFlag", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x010C}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "CodingSchemeUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x010D}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "ContextGroupExtensionCreatorUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x010F}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "ContextIdentifier", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0110}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "CodingSchemeIdentificationSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0112}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "CodingSchemeRegistry", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0114}, VR: "ST", VRs: []string{"ST"}, VM: "1", Keyword: "CodingSchemeExternalID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0115}, VR: "ST", VRs: []string{"ST"}, VM: "1", Keyword: "CodingSchemeName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0116}, VR: "ST", VRs: []string{"ST"}, VM: "1", Keyword: "CodingSchemeResponsibleOrganization", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0117}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "ContextUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0118}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "MappingResourceUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0119}, VR: "UC", VRs: []string{"UC"}, VM: "1", Keyword: "LongCodeValue", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0120}, VR: "UR", VRs: []string{"UR"}, VM: "1", Keyword: "URNCodeValue", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0121}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "EquivalentCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0122}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "MappingResourceName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0123}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ContextGroupIdentificationSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0124}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "MappingResourceIdentificationSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0201}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "TimezoneOffsetFromUTC", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0300}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PrivateDataElementCharacteristicsSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0301}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "PrivateGroupReference", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0302}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "PrivateCreatorReference", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0303}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "BlockIdentifyingInformationStatus", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0304}, VR: "US", VRs: []string{"US"}, VM: "1-n", Keyword: "NonidentifyingPrivateElements", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0305}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "DeidentificationActionSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0306}, VR: "US", VRs: []string{"US"}, VM: "1-n", Keyword: "IdentifyingPrivateElements", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x0307}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "DeidentificationAction", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1010}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "StationName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1030}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "StudyDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1032}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ProcedureCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x103E}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "SeriesDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x103F}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "SeriesDescriptionCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1040}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "InstitutionalDepartmentName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1048}, VR: "PN", VRs: []string{"PN"}, VM: "1-n", Keyword: "PhysiciansOfRecord", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1050}, VR: "PN", VRs: []string{"PN"}, VM: "1-n", Keyword: "PerformingPhysicianName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1060}, VR: "PN", VRs: []string{"PN"}, VM: "1-n", Keyword: "NameOfPhysiciansReadingStudy", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1070}, VR: "PN", VRs: []string{"PN"}, VM: "1-n", Keyword: "OperatorsName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1072}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "OperatorIdentificationSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1080}, VR: "LO", VRs: []string{"LO"}, VM: "1-n", Keyword: "AdmittingDiagnosesDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1090}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ManufacturerModelName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1110}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ReferencedStudySequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1111}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ReferencedPerformedProcedureStepSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1115}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ReferencedSeriesSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1120}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ReferencedPatientSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1125}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ReferencedVisitSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1130}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ReferencedOverlaySequence", Retired: true},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1134}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ReferencedStereometricInstanceSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x113A}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ReferencedWaveformSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1140}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ReferencedImageSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1145}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ReferencedCurveSequence", Retired: true},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x114A}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ReferencedInstanceSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x114B}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ReferencedRealWorldValueMappingInstanceSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1150}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "ReferencedSOPClassUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1155}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "ReferencedSOPInstanceUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1160}, VR: "IS", VRs: []string{"IS"}, VM: "1-n", Keyword: "ReferencedFrameNumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1161}, VR: "UL", VRs: []string{"UL"}, VM: "1-n", Keyword: "SimpleFrameList", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1162}, VR: "UL", VRs: []string{"UL"}, VM: "3-3n", Keyword: "CalculatedFrameList", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1163}, VR: "FD", VRs: []string{"FD"}, VM: "2", Keyword: "TimeRange", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1164}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "FrameExtractionSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1167}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "MultiFrameSourceSOPInstanceUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1190}, VR: "UR", VRs: []string{"UR"}, VM: "1", Keyword: "RetrieveURL", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1195}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "TransactionUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1196}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "WarningReason", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1197}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "FailureReason", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1198}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "FailedSOPSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1199}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ReferencedSOPSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1200}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "StudiesContainingOtherReferencedInstancesSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x1250}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "RelatedSeriesSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x2111}, VR: "ST", VRs: []string{"ST"}, VM: "1", Keyword: "DerivationDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x2112}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "SourceImageSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x2120}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "StageName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x2122}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "StageNumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x2124}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "NumberOfStages", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x2127}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "ViewName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x2128}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "ViewNumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x2129}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "NumberOfEventTimers", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x212A}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "NumberOfViewsInStage", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x2130}, VR: "DS", VRs: []string{"DS"}, VM: "1-n", Keyword: "EventElapsedTimes", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x2132}, VR: "LO", VRs: []string{"LO"}, VM: "1-n", Keyword: "EventTimerNames", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x2133}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "EventTimerSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x2134}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "EventTimeOffset", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x2135}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "EventCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x2142}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "StartTrim", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x2143}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "StopTrim", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x2144}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "RecommendedDisplayFrameRate", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x2218}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "AnatomicRegionSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x2220}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "AnatomicRegionModifierSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x2228}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PrimaryAnatomicStructureSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x3001}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "AlternateRepresentationSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x3010}, VR: "UI", VRs: []string{"UI"}, VM: "1-n", Keyword: "IrradiationEventUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x3011}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "SourceIrradiationEventSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x3012}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "RadiopharmaceuticalAdministrationEventUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x9007}, VR: "CS", VRs: []string{"CS"}, VM: "4", Keyword: "FrameType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x9092}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ReferencedImageEvidenceSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x9121}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ReferencedRawDataSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x9123}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "CreatorVersionUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x9124}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "DerivationImageSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x9154}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "SourceImageEvidenceSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x9205}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "PixelPresentation", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x9206}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "VolumetricProperties", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x9207}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "VolumeBasedCalculationTechnique", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x9208}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "ComplexImageComponent", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x9209}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "AcquisitionContrast", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x9215}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "DerivationCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x9237}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ReferencedPresentationStateSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x9410}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ReferencedOtherPlaneSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x9458}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "FrameDisplaySequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x9459}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "RecommendedDisplayFrameRateInFloat", Retired: false},
+	{Tag: tag.Tag{Group: 0x0008, Element: 0x9460}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "SkipFrameRangeFlag", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x0010}, VR: "PN", VRs: []string{"PN"}, VM: "1", Keyword: "PatientName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x0020}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "PatientID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x0021}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "IssuerOfPatientID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x0022}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "TypeOfPatientID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x0024}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "IssuerOfPatientIDQualifiersSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x0026}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "SourcePatientGroupIdentificationSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x0027}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "GroupOfPatientsIdentificationSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x0028}, VR: "US", VRs: []string{"US"}, VM: "3", Keyword: "SubjectRelativePositionInImage", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x0030}, VR: "DA", VRs: []string{"DA"}, VM: "1", Keyword: "PatientBirthDate", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x0032}, VR: "TM", VRs: []string{"TM"}, VM: "1", Keyword: "PatientBirthTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x0033}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "PatientBirthDateInAlternativeCalendar", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x0034}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "PatientDeathDateInAlternativeCalendar", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x0035}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "PatientAlternativeCalendar", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x0040}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "PatientSex", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x0050}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PatientInsurancePlanCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x0101}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PatientPrimaryLanguageCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x0200}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "QualityControlSubject", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x0212}, VR: "UC", VRs: []string{"UC"}, VM: "1", Keyword: "StrainDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x0213}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "StrainNomenclature", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x1000}, VR: "LO", VRs: []string{"LO"}, VM: "1-n", Keyword: "OtherPatientIDs", Retired: true},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x1001}, VR: "PN", VRs: []string{"PN"}, VM: "1-n", Keyword: "OtherPatientNames", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x1002}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "OtherPatientIDsSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x1005}, VR: "PN", VRs: []string{"PN"}, VM: "1", Keyword: "PatientBirthName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x1010}, VR: "AS", VRs: []string{"AS"}, VM: "1", Keyword: "PatientAge", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x1020}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "PatientSize", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x1021}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PatientSizeCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x1022}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "PatientBodyMassIndex", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x1023}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "MeasuredAPDimension", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x1024}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "MeasuredLateralDimension", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x1030}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "PatientWeight", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x1040}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "PatientAddress", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x1060}, VR: "PN", VRs: []string{"PN"}, VM: "1", Keyword: "PatientMotherBirthName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x1080}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "MilitaryRank", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x1081}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "BranchOfService", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x2000}, VR: "LO", VRs: []string{"LO"}, VM: "1-n", Keyword: "MedicalAlerts", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x2110}, VR: "LO", VRs: []string{"LO"}, VM: "1-n", Keyword: "Allergies", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x2150}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "CountryOfResidence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x2152}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "RegionOfResidence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x2154}, VR: "SH", VRs: []string{"SH"}, VM: "1-n", Keyword: "PatientTelephoneNumbers", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x2155}, VR: "LT", VRs: []string{"LT"}, VM: "1", Keyword: "PatientTelecomInformation", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x2160}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "EthnicGroup", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x2180}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "Occupation", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x21A0}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "SmokingStatus", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x21B0}, VR: "LT", VRs: []string{"LT"}, VM: "1", Keyword: "AdditionalPatientHistory", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x21C0}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "PregnancyStatus", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x21D0}, VR: "DA", VRs: []string{"DA"}, VM: "1", Keyword: "LastMenstrualDate", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x21F0}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "PatientReligiousPreference", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x2201}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "PatientSpeciesDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x2202}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PatientSpeciesCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x2203}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "PatientSexNeutered", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x2210}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "AnatomicalOrientationType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x2292}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "PatientBreedDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x2293}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PatientBreedCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x2294}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "BreedRegistrationSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x2295}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "BreedRegistrationNumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x2296}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "BreedRegistryCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x2297}, VR: "PN", VRs: []string{"PN"}, VM: "1", Keyword: "ResponsiblePerson", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x2298}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "ResponsiblePersonRole", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x2299}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ResponsibleOrganization", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x4000}, VR: "LT", VRs: []string{"LT"}, VM: "1", Keyword: "PatientComments", Retired: false},
+	{Tag: tag.Tag{Group: 0x0010, Element: 0x9431}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "ExaminedBodyThickness", Retired: false},
+	{Tag: tag.Tag{Group: 0x0012, Element: 0x0010}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ClinicalTrialSponsorName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0012, Element: 0x0020}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ClinicalTrialProtocolID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0012, Element: 0x0021}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ClinicalTrialProtocolName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0012, Element: 0x0030}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ClinicalTrialSiteID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0012, Element: 0x0031}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ClinicalTrialSiteName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0012, Element: 0x0040}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ClinicalTrialSubjectID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0012, Element: 0x0042}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ClinicalTrialSubjectReadingID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0012, Element: 0x0050}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ClinicalTrialTimePointID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0012, Element: 0x0051}, VR: "ST", VRs: []string{"ST"}, VM: "1", Keyword: "ClinicalTrialTimePointDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0012, Element: 0x0060}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ClinicalTrialCoordinatingCenterName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0012, Element: 0x0062}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "PatientIdentityRemoved", Retired: false},
+	{Tag: tag.Tag{Group: 0x0012, Element: 0x0063}, VR: "LO", VRs: []string{"LO"}, VM: "1-n", Keyword: "DeidentificationMethod", Retired: false},
+	{Tag: tag.Tag{Group: 0x0012, Element: 0x0064}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "DeidentificationMethodCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0012, Element: 0x0071}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ClinicalTrialSeriesID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0012, Element: 0x0072}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ClinicalTrialSeriesDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0012, Element: 0x0081}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ClinicalTrialProtocolEthicsCommitteeName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0012, Element: 0x0082}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ClinicalTrialProtocolEthicsCommitteeApprovalNumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x0012, Element: 0x0083}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ConsentForClinicalTrialUseSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0012, Element: 0x0084}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "DistributionType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0012, Element: 0x0085}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "ConsentForDistributionFlag", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x0010}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ContrastBolusAgent", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x0015}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "BodyPartExamined", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x0020}, VR: "CS", VRs: []string{"CS"}, VM: "1-n", Keyword: "ScanningSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x0021}, VR: "CS", VRs: []string{"CS"}, VM: "1-n", Keyword: "SequenceVariant", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x0022}, VR: "CS", VRs: []string{"CS"}, VM: "1-n", Keyword: "ScanOptions", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x0023}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "MRAcquisitionType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x0024}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "SequenceName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x0025}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "AngioFlag", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x0050}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "SliceThickness", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x0060}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "KVP", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x0070}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "CountsAccumulated", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x0071}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "AcquisitionTerminationCondition", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x0072}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "EffectiveDuration", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x0073}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "AcquisitionStartCondition", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x0074}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "AcquisitionStartConditionData", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x0075}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "AcquisitionTerminationConditionData", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x0083}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "NumberOfAverages", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x0088}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "SpacingBetweenSlices", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x0090}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "DataCollectionDiameter", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1000}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "DeviceSerialNumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1002}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "DeviceUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1003}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "DeviceID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1004}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "PlateID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1005}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "GeneratorID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1006}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "GridID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1007}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "CassetteID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1008}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "GantryID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1009}, VR: "UT", VRs: []string{"UT"}, VM: "1", Keyword: "UniqueDeviceIdentifier", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x100A}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "UDISequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x100B}, VR: "UI", VRs: []string{"UI"}, VM: "1-n", Keyword: "ManufacturerDeviceClassUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1010}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "SecondaryCaptureDeviceID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1012}, VR: "DA", VRs: []string{"DA"}, VM: "1", Keyword: "DateOfSecondaryCapture", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1014}, VR: "TM", VRs: []string{"TM"}, VM: "1", Keyword: "TimeOfSecondaryCapture", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1016}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "SecondaryCaptureDeviceManufacturer", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1018}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "SecondaryCaptureDeviceManufacturerModelName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1019}, VR: "LO", VRs: []string{"LO"}, VM: "1-n", Keyword: "SecondaryCaptureDeviceSoftwareVersions", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1020}, VR: "LO", VRs: []string{"LO"}, VM: "1-n", Keyword: "SoftwareVersions", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1022}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "VideoImageFormatAcquired", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1023}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "DigitalImageFormatAcquired", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1030}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ProtocolName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1040}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ContrastBolusRoute", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1041}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "ContrastBolusVolume", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1042}, VR: "TM", VRs: []string{"TM"}, VM: "1", Keyword: "ContrastBolusStartTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1043}, VR: "TM", VRs: []string{"TM"}, VM: "1", Keyword: "ContrastBolusStopTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1044}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "ContrastBolusTotalDose", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1050}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "SpatialResolution", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1060}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "TriggerTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1061}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "TriggerSourceOrType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1062}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "NominalInterval", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1063}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "FrameTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1065}, VR: "DS", VRs: []string{"DS"}, VM: "1-n", Keyword: "FrameTimeVector", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1066}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "FrameDelay", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1100}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "ReconstructionDiameter", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1110}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "DistanceSourceToDetector", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1111}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "DistanceSourceToPatient", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1114}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "EstimatedRadiographicMagnificationFactor", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1120}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "GantryDetectorTilt", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1121}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "GantryDetectorSlew", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1130}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "TableHeight", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1131}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "TableTraverse", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1134}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "TableMotion", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1135}, VR: "DS", VRs: []string{"DS"}, VM: "1-n", Keyword: "TableVerticalIncrement", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1136}, VR: "DS", VRs: []string{"DS"}, VM: "1-n", Keyword: "TableLateralIncrement", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1137}, VR: "DS", VRs: []string{"DS"}, VM: "1-n", Keyword: "TableLongitudinalIncrement", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1138}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "TableAngle", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x113A}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "TableType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1140}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "RotationDirection", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1141}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "AngularPosition", Retired: true},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1142}, VR: "DS", VRs: []string{"DS"}, VM: "1-n", Keyword: "RadialPosition", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1143}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "ScanArc", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1144}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "AngularStep", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1145}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "CenterOfRotationOffset", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1147}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "FieldOfViewShape", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1149}, VR: "IS", VRs: []string{"IS"}, VM: "1-2", Keyword: "FieldOfViewDimensions", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1150}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "ExposureTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1151}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "XRayTubeCurrent", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1152}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "Exposure", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1153}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "ExposureInuAs", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1154}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "AveragePulseWidth", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1155}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "RadiationSetting", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1156}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "RectificationType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x115A}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "RadiationMode", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x115E}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "ImageAndFluoroscopyAreaDoseProduct", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1160}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "FilterType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1161}, VR: "LO", VRs: []string{"LO"}, VM: "1-n", Keyword: "TypeOfFilters", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1162}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "IntensifierSize", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1164}, VR: "DS", VRs: []string{"DS"}, VM: "2", Keyword: "ImagerPixelSpacing", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1166}, VR: "CS", VRs: []string{"CS"}, VM: "1-n", Keyword: "Grid", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1170}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "GeneratorPower", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1180}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "CollimatorGridName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1181}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "CollimatorType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1182}, VR: "IS", VRs: []string{"IS"}, VM: "1-2", Keyword: "FocalDistance", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1183}, VR: "DS", VRs: []string{"DS"}, VM: "1-2", Keyword: "XFocusCenter", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1184}, VR: "DS", VRs: []string{"DS"}, VM: "1-2", Keyword: "YFocusCenter", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1190}, VR: "DS", VRs: []string{"DS"}, VM: "1-n", Keyword: "FocalSpots", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1191}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "AnodeTargetMaterial", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x11A0}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "BodyPartThickness", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x11A2}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "CompressionForce", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1200}, VR: "DA", VRs: []string{"DA"}, VM: "1-n", Keyword: "DateOfLastCalibration", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1201}, VR: "TM", VRs: []string{"TM"}, VM: "1-n", Keyword: "TimeOfLastCalibration", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1202}, VR: "DT", VRs: []string{"DT"}, VM: "1", Keyword: "DateTimeOfLastCalibration", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1210}, VR: "SH", VRs: []string{"SH"}, VM: "1-n", Keyword: "ConvolutionKernel", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1242}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "ActualFrameDuration", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1243}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "CountRate", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1260}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "PlateType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1261}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "PhosphorType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1271}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "WaterEquivalentDiameter", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1272}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "WaterEquivalentDiameterCalculationMethodCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1300}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "ScanVelocity", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1301}, VR: "CS", VRs: []string{"CS"}, VM: "1-n", Keyword: "WholeBodyTechnique", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1302}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "ScanLength", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1400}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "AcquisitionDeviceProcessingDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1401}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "AcquisitionDeviceProcessingCode", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1402}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "CassetteOrientation", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1403}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "CassetteSize", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1404}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "ExposuresOnPlate", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1405}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "RelativeXRayExposure", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1411}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "ExposureIndex", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1412}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "TargetExposureIndex", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1413}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "DeviationIndex", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1450}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "ColumnAngulation", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1460}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "TomoLayerHeight", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1470}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "TomoAngle", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1480}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "TomoTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1490}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "TomoType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1491}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "TomoClass", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1495}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "NumberOfTomosynthesisSourceImages", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1500}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "PositionerMotion", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1508}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "PositionerType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1510}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "PositionerPrimaryAngle", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1511}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "PositionerSecondaryAngle", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1600}, VR: "CS", VRs: []string{"CS"}, VM: "1-3", Keyword: "ShutterShape", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1602}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "ShutterLeftVerticalEdge", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1604}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "ShutterRightVerticalEdge", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1606}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "ShutterUpperHorizontalEdge", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1608}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "ShutterLowerHorizontalEdge", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1610}, VR: "IS", VRs: []string{"IS"}, VM: "2", Keyword: "CenterOfCircularShutter", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1612}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "RadiusOfCircularShutter", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1620}, VR: "IS", VRs: []string{"IS"}, VM: "2-2n", Keyword: "VerticesOfThePolygonalShutter", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1700}, VR: "CS", VRs: []string{"CS"}, VM: "1-3", Keyword: "CollimatorShape", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1702}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "CollimatorLeftVerticalEdge", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1704}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "CollimatorRightVerticalEdge", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1706}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "CollimatorUpperHorizontalEdge", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1708}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "CollimatorLowerHorizontalEdge", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1710}, VR: "IS", VRs: []string{"IS"}, VM: "2", Keyword: "CenterOfCircularCollimator", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1712}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "RadiusOfCircularCollimator", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x1720}, VR: "IS", VRs: []string{"IS"}, VM: "2-2n", Keyword: "VerticesOfThePolygonalCollimator", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x5100}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "PatientPosition", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x5101}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "ViewPosition", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x6000}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "Sensitivity", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7000}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "DetectorConditionsNominalFlag", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7001}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "DetectorTemperature", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7004}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "DetectorType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7005}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "DetectorConfiguration", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7006}, VR: "LT", VRs: []string{"LT"}, VM: "1", Keyword: "DetectorDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7008}, VR: "LT", VRs: []string{"LT"}, VM: "1", Keyword: "DetectorMode", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x700A}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "DetectorID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x700C}, VR: "DA", VRs: []string{"DA"}, VM: "1", Keyword: "DateOfLastDetectorCalibration", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x700E}, VR: "TM", VRs: []string{"TM"}, VM: "1", Keyword: "TimeOfLastDetectorCalibration", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7010}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "ExposuresOnDetectorSinceLastCalibration", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7011}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "ExposuresOnDetectorSinceManufactured", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7012}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "DetectorTimeSinceLastExposure", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7014}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "DetectorActiveTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7016}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "DetectorActivationOffsetFromExposure", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x701A}, VR: "DS", VRs: []string{"DS"}, VM: "2", Keyword: "DetectorBinning", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7020}, VR: "DS", VRs: []string{"DS"}, VM: "2", Keyword: "DetectorElementPhysicalSize", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7022}, VR: "DS", VRs: []string{"DS"}, VM: "2", Keyword: "DetectorElementSpacing", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7024}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "DetectorActiveShape", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7026}, VR: "DS", VRs: []string{"DS"}, VM: "1-2", Keyword: "DetectorActiveDimensions", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7028}, VR: "DS", VRs: []string{"DS"}, VM: "2", Keyword: "DetectorActiveOrigin", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x702A}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "DetectorManufacturerName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x702B}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "DetectorManufacturerModelName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7030}, VR: "DS", VRs: []string{"DS"}, VM: "2", Keyword: "FieldOfViewOrigin", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7032}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "FieldOfViewRotation", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7034}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "FieldOfViewHorizontalFlip", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7036}, VR: "FL", VRs: []string{"FL"}, VM: "2", Keyword: "PixelDataAreaOriginRelativeToFOV", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7038}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "PixelDataAreaRotationAngleRelativeToFOV", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7040}, VR: "LT", VRs: []string{"LT"}, VM: "1", Keyword: "GridAbsorbingMaterial", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7041}, VR: "LT", VRs: []string{"LT"}, VM: "1", Keyword: "GridSpacingMaterial", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7042}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "GridThickness", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7044}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "GridPitch", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7046}, VR: "IS", VRs: []string{"IS"}, VM: "2", Keyword: "GridAspectRatio", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7048}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "GridPeriod", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x704C}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "GridFocalDistance", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7050}, VR: "CS", VRs: []string{"CS"}, VM: "1-n", Keyword: "FilterMaterial", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7052}, VR: "DS", VRs: []string{"DS"}, VM: "1-n", Keyword: "FilterThicknessMinimum", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7054}, VR: "DS", VRs: []string{"DS"}, VM: "1-n", Keyword: "FilterThicknessMaximum", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7056}, VR: "FL", VRs: []string{"FL"}, VM: "1-n", Keyword: "FilterBeamPathLengthMinimum", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7058}, VR: "FL", VRs: []string{"FL"}, VM: "1-n", Keyword: "FilterBeamPathLengthMaximum", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7060}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "ExposureControlMode", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7062}, VR: "LT", VRs: []string{"LT"}, VM: "1", Keyword: "ExposureControlModeDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7064}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "ExposureStatus", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x7065}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "PhototimerSetting", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x8150}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "ExposureTimeInuS", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x8151}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "XRayTubeCurrentInuA", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9004}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "ContentQualification", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9052}, VR: "FD", VRs: []string{"FD"}, VM: "1-2", Keyword: "SpectralWidth", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9073}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "AcquisitionDuration", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9074}, VR: "DT", VRs: []string{"DT"}, VM: "1", Keyword: "FrameAcquisitionDateTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9098}, VR: "FD", VRs: []string{"FD"}, VM: "1-2", Keyword: "TransmitterFrequency", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9100}, VR: "CS", VRs: []string{"CS"}, VM: "1-2", Keyword: "ResonantNucleus", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9151}, VR: "DT", VRs: []string{"DT"}, VM: "1", Keyword: "FrameReferenceDateTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9220}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "FrameAcquisitionDuration", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9301}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "CTAcquisitionTypeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9302}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "AcquisitionType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9303}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "TubeAngle", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9304}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "CTAcquisitionDetailsSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9305}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "RevolutionTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9306}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "SingleCollimationWidth", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9307}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "TotalCollimationWidth", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9308}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "CTTableDynamicsSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9309}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "TableSpeed", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9310}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "TableFeedPerRotation", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9311}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "SpiralPitchFactor", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9312}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "CTGeometrySequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9313}, VR: "FD", VRs: []string{"FD"}, VM: "3", Keyword: "DataCollectionCenterPatient", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9314}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "CTReconstructionSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9315}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "ReconstructionAlgorithm", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9316}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "ConvolutionKernelGroup", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9317}, VR: "FD", VRs: []string{"FD"}, VM: "2", Keyword: "ReconstructionFieldOfView", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9318}, VR: "FD", VRs: []string{"FD"}, VM: "3", Keyword: "ReconstructionTargetCenterPatient", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9319}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "ReconstructionAngle", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9320}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "ImageFilter", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9321}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "CTExposureSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9322}, VR: "FD", VRs: []string{"FD"}, VM: "2", Keyword: "ReconstructionPixelSpacing", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9323}, VR: "CS", VRs: []string{"CS"}, VM: "1-n", Keyword: "ExposureModulationType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9324}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "EstimatedDoseSaving", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9325}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "CTXRayDetailsSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9326}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "CTPositionSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9327}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "TablePosition", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9328}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "ExposureTimeInms", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9329}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "CTImageFrameTypeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9330}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "XRayTubeCurrentInmA", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9332}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "ExposureInmAs", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9333}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "ConstantVolumeFlag", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9334}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "FluoroscopyFlag", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9335}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "DistanceSourceToDataCollectionCenter", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9337}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "ContrastBolusAgentNumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9345}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "CTDIvol", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9346}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "CTDIPhantomTypeCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9351}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "CalciumScoringMassFactorPatient", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9352}, VR: "FL", VRs: []string{"FL"}, VM: "3", Keyword: "CalciumScoringMassFactorDevice", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9353}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "EnergyWeightingFactor", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9360}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "CTAdditionalXRaySourceSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9401}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ProjectionPixelCalibrationSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9402}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "DistanceSourceToIsocenter", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9403}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "DistanceObjectToTableTop", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9404}, VR: "FL", VRs: []string{"FL"}, VM: "2", Keyword: "ObjectPixelSpacingInCenterOfBeam", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9405}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PositionerPositionSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9406}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "TablePositionSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9407}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "CollimatorShapeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9412}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "XAXRFFrameCharacteristicsSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9417}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "FrameAcquisitionSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9420}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "XRayReceptorType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9423}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "AcquisitionProtocolName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9424}, VR: "LT", VRs: []string{"LT"}, VM: "1", Keyword: "AcquisitionProtocolDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9425}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "ContrastBolusIngredientOpaque", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9426}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "DistanceReceptorPlaneToDetectorHousing", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9427}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "IntensifierActiveShape", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9428}, VR: "FL", VRs: []string{"FL"}, VM: "1-2", Keyword: "IntensifierActiveDimensions", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9429}, VR: "FL", VRs: []string{"FL"}, VM: "2", Keyword: "PhysicalDetectorSize", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9430}, VR: "FL", VRs: []string{"FL"}, VM: "2", Keyword: "PositionOfIsocenterProjection", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9432}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "FieldOfViewSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9433}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "FieldOfViewDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9447}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "ColumnAngulationPatient", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9449}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "BeamAngle", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9451}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "FrameDetectorParametersSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9452}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "CalculatedAnatomyThickness", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9455}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "CalibrationSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9456}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ObjectThicknessSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9457}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "PlaneIdentification", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9461}, VR: "FL", VRs: []string{"FL"}, VM: "1-2", Keyword: "FieldOfViewDimensionsInFloat", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9476}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "XRayGeometrySequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9477}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "IrradiationEventIdentificationSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9504}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "XRay3DFrameTypeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9506}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ContributingSourcesSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9507}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "XRay3DAcquisitionSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9508}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "PrimaryPositionerScanArc", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9509}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "SecondaryPositionerScanArc", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9510}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "PrimaryPositionerScanStartAngle", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9511}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "SecondaryPositionerScanStartAngle", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9514}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "PrimaryPositionerIncrement", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9515}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "SecondaryPositionerIncrement", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9516}, VR: "DT", VRs: []string{"DT"}, VM: "1", Keyword: "StartAcquisitionDateTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9517}, VR: "DT", VRs: []string{"DT"}, VM: "1", Keyword: "EndAcquisitionDateTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9524}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ApplicationName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9525}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ApplicationVersion", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9526}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ApplicationManufacturer", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9527}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "AlgorithmType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9528}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "AlgorithmDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9530}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "XRay3DReconstructionSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9531}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ReconstructionDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0x9538}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PerProjectionAcquisitionSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0xA001}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ContributingEquipmentSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0xA002}, VR: "DT", VRs: []string{"DT"}, VM: "1", Keyword: "ContributionDateTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0018, Element: 0xA003}, VR: "ST", VRs: []string{"ST"}, VM: "1", Keyword: "ContributionDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x000D}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "StudyInstanceUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x000E}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "SeriesInstanceUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x0010}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "StudyID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x0011}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "SeriesNumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x0012}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "AcquisitionNumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x0013}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "InstanceNumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x0019}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "ItemNumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x0020}, VR: "CS", VRs: []string{"CS"}, VM: "2", Keyword: "PatientOrientation", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x0022}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "OverlayNumber", Retired: true},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x0024}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "CurveNumber", Retired: true},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x0026}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "LUTNumber", Retired: true},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x0030}, VR: "DS", VRs: []string{"DS"}, VM: "3", Keyword: "ImagePosition", Retired: true},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x0032}, VR: "DS", VRs: []string{"DS"}, VM: "3", Keyword: "ImagePositionPatient", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x0035}, VR: "DS", VRs: []string{"DS"}, VM: "6", Keyword: "ImageOrientation", Retired: true},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x0037}, VR: "DS", VRs: []string{"DS"}, VM: "6", Keyword: "ImageOrientationPatient", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x0050}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "Location", Retired: true},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x0052}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "FrameOfReferenceUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x0060}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "Laterality", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x0062}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "ImageLaterality", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x0070}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ImageGeometryType", Retired: true},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x0080}, VR: "CS", VRs: []string{"CS"}, VM: "1-n", Keyword: "MaskingImage", Retired: true},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x0100}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "TemporalPositionIdentifier", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x0105}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "NumberOfTemporalPositions", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x0110}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "TemporalResolution", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x0200}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "SynchronizationFrameOfReferenceUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x0242}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "SOPInstanceUIDOfConcatenationSource", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x1000}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "SeriesInStudy", Retired: true},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x1002}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "ImagesInAcquisition", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x1040}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "PositionReferenceIndicator", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x1041}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "SliceLocation", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x1200}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "NumberOfPatientRelatedStudies", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x1202}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "NumberOfPatientRelatedSeries", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x1204}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "NumberOfPatientRelatedInstances", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x1206}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "NumberOfStudyRelatedSeries", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x1208}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "NumberOfStudyRelatedInstances", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x1209}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "NumberOfSeriesRelatedInstances", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x4000}, VR: "LT", VRs: []string{"LT"}, VM: "1", Keyword: "ImageComments", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9056}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "StackID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9057}, VR: "UL", VRs: []string{"UL"}, VM: "1", Keyword: "InStackPositionNumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9071}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "FrameAnatomySequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9072}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "FrameLaterality", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9111}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "FrameContentSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9113}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PlanePositionSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9116}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PlaneOrientationSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9128}, VR: "UL", VRs: []string{"UL"}, VM: "1", Keyword: "TemporalPositionIndex", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9153}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "NominalCardiacTriggerDelayTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9156}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "FrameAcquisitionNumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9157}, VR: "UL", VRs: []string{"UL"}, VM: "1-n", Keyword: "DimensionIndexValues", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9158}, VR: "LT", VRs: []string{"LT"}, VM: "1", Keyword: "FrameComments", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9161}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "ConcatenationUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9162}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "InConcatenationNumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9163}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "InConcatenationTotalNumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9164}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "DimensionOrganizationUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9165}, VR: "AT", VRs: []string{"AT"}, VM: "1", Keyword: "DimensionIndexPointer", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9167}, VR: "AT", VRs: []string{"AT"}, VM: "1", Keyword: "FunctionalGroupPointer", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9170}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "UnassignedSharedConvertedAttributesSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9171}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "UnassignedPerFrameConvertedAttributesSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9172}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ConversionSourceAttributesSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9213}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "DimensionIndexPrivateCreator", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9221}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "DimensionOrganizationSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9222}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "DimensionIndexSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9228}, VR: "UL", VRs: []string{"UL"}, VM: "1", Keyword: "ConcatenationFrameOffsetNumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9238}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "FunctionalGroupPrivateCreator", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9241}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "NominalPercentageOfCardiacPhase", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9245}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "NominalPercentageOfRespiratoryPhase", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9246}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "StartingRespiratoryAmplitude", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9247}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "StartingRespiratoryPhase", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9248}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "EndingRespiratoryAmplitude", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9249}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "EndingRespiratoryPhase", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9250}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "RespiratoryTriggerType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9251}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "RRIntervalTimeNominal", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9252}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "ActualCardiacTriggerDelayTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9253}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "RespiratorySynchronizationSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9254}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "RespiratoryIntervalTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9255}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "NominalRespiratoryTriggerDelayTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9256}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "RespiratoryTriggerDelayThreshold", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9257}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "ActualRespiratoryTriggerDelayTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9301}, VR: "FD", VRs: []string{"FD"}, VM: "3", Keyword: "ImagePositionVolume", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9302}, VR: "FD", VRs: []string{"FD"}, VM: "6", Keyword: "ImageOrientationVolume", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9307}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "UltrasoundAcquisitionGeometry", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9308}, VR: "FD", VRs: []string{"FD"}, VM: "3", Keyword: "ApexPosition", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9309}, VR: "FD", VRs: []string{"FD"}, VM: "16", Keyword: "VolumeToTransducerMappingMatrix", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x930A}, VR: "FD", VRs: []string{"FD"}, VM: "16", Keyword: "VolumeToTableMappingMatrix", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x930B}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "VolumeToTransducerRelationship", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x930C}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "PatientFrameOfReferenceSource", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x930D}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "TemporalPositionTimeOffset", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x930E}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PlanePositionVolumeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x930F}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PlaneOrientationVolumeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9310}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "TemporalPositionSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9311}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "DimensionOrganizationType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9312}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "VolumeFrameOfReferenceUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9313}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "TableFrameOfReferenceUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9421}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "DimensionDescriptionLabel", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9450}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PatientOrientationInFrameSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9453}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "FrameLabel", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9518}, VR: "US", VRs: []string{"US"}, VM: "1-n", Keyword: "AcquisitionIndex", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9529}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ContributingSOPInstancesReferenceSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0020, Element: 0x9536}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "ReconstructionIndex", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0002}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "SamplesPerPixel", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0003}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "SamplesPerPixelUsed", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0004}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "PhotometricInterpretation", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0006}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "PlanarConfiguration", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0008}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "NumberOfFrames", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0009}, VR: "AT", VRs: []string{"AT"}, VM: "1-n", Keyword: "FrameIncrementPointer", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x000A}, VR: "AT", VRs: []string{"AT"}, VM: "1-n", Keyword: "FrameDimensionPointer", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0010}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "Rows", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0011}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "Columns", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0014}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "UltrasoundColorDataPresent", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0030}, VR: "DS", VRs: []string{"DS"}, VM: "2", Keyword: "PixelSpacing", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0031}, VR: "DS", VRs: []string{"DS"}, VM: "2", Keyword: "ZoomFactor", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0032}, VR: "DS", VRs: []string{"DS"}, VM: "2", Keyword: "ZoomCenter", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0034}, VR: "IS", VRs: []string{"IS"}, VM: "2", Keyword: "PixelAspectRatio", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0051}, VR: "CS", VRs: []string{"CS"}, VM: "1-n", Keyword: "CorrectedImage", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0100}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "BitsAllocated", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0101}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "BitsStored", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0102}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "HighBit", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0103}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "PixelRepresentation", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0106}, VR: "US", VRs: []string{"US", "SS"}, VM: "1", Keyword: "SmallestImagePixelValue", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0107}, VR: "US", VRs: []string{"US", "SS"}, VM: "1", Keyword: "LargestImagePixelValue", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0108}, VR: "US", VRs: []string{"US", "SS"}, VM: "1", Keyword: "SmallestPixelValueInSeries", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0109}, VR: "US", VRs: []string{"US", "SS"}, VM: "1", Keyword: "LargestPixelValueInSeries", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0120}, VR: "US", VRs: []string{"US", "SS"}, VM: "1", Keyword: "PixelPaddingValue", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0121}, VR: "US", VRs: []string{"US", "SS"}, VM: "1", Keyword: "PixelPaddingRangeLimit", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0122}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "FloatPixelPaddingValue", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0123}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "DoubleFloatPixelPaddingValue", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0124}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "FloatPixelPaddingRangeLimit", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0125}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "DoubleFloatPixelPaddingRangeLimit", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0300}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "QualityControlImage", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0301}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "BurnedInAnnotation", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0302}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "RecognizableVisualFeatures", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0303}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "LongitudinalTemporalInformationModified", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0304}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "ReferencedColorPaletteInstanceUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0A02}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "PixelSpacingCalibrationType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x0A04}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "PixelSpacingCalibrationDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1040}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "PixelIntensityRelationship", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1041}, VR: "SS", VRs: []string{"SS"}, VM: "1", Keyword: "PixelIntensityRelationshipSign", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1050}, VR: "DS", VRs: []string{"DS"}, VM: "1-n", Keyword: "WindowCenter", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1051}, VR: "DS", VRs: []string{"DS"}, VM: "1-n", Keyword: "WindowWidth", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1052}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "RescaleIntercept", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1053}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "RescaleSlope", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1054}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "RescaleType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1055}, VR: "LO", VRs: []string{"LO"}, VM: "1-n", Keyword: "WindowCenterWidthExplanation", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1056}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "VOILUTFunction", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1090}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "RecommendedViewingMode", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1101}, VR: "US", VRs: []string{"US", "SS"}, VM: "3", Keyword: "RedPaletteColorLookupTableDescriptor", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1102}, VR: "US", VRs: []string{"US", "SS"}, VM: "3", Keyword: "GreenPaletteColorLookupTableDescriptor", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1103}, VR: "US", VRs: []string{"US", "SS"}, VM: "3", Keyword: "BluePaletteColorLookupTableDescriptor", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1104}, VR: "US", VRs: []string{"US"}, VM: "3", Keyword: "AlphaPaletteColorLookupTableDescriptor", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1199}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "PaletteColorLookupTableUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1201}, VR: "OW", VRs: []string{"OW"}, VM: "1", Keyword: "RedPaletteColorLookupTableData", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1202}, VR: "OW", VRs: []string{"OW"}, VM: "1", Keyword: "GreenPaletteColorLookupTableData", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1203}, VR: "OW", VRs: []string{"OW"}, VM: "1", Keyword: "BluePaletteColorLookupTableData", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1204}, VR: "OW", VRs: []string{"OW"}, VM: "1", Keyword: "AlphaPaletteColorLookupTableData", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1221}, VR: "OW", VRs: []string{"OW"}, VM: "1", Keyword: "SegmentedRedPaletteColorLookupTableData", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1222}, VR: "OW", VRs: []string{"OW"}, VM: "1", Keyword: "SegmentedGreenPaletteColorLookupTableData", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1223}, VR: "OW", VRs: []string{"OW"}, VM: "1", Keyword: "SegmentedBluePaletteColorLookupTableData", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1224}, VR: "OW", VRs: []string{"OW"}, VM: "1", Keyword: "SegmentedAlphaPaletteColorLookupTableData", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1230}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "StoredValueColorRangeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1231}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "MinimumStoredValueMapped", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1232}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "MaximumStoredValueMapped", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1300}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "BreastImplantPresent", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1350}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "PartialView", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1351}, VR: "ST", VRs: []string{"ST"}, VM: "1", Keyword: "PartialViewDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1352}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PartialViewCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x135A}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "SpatialLocationsPreserved", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1401}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "DataFrameAssignmentSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1402}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "DataPathAssignment", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1403}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "BitsMappedToColorLookupTable", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1404}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "BlendingLUT1Sequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1405}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "BlendingLUT1TransferFunction", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1406}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "BlendingWeightConstant", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1407}, VR: "US", VRs: []string{"US"}, VM: "3", Keyword: "BlendingLookupTableDescriptor", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1408}, VR: "OW", VRs: []string{"OW"}, VM: "1", Keyword: "BlendingLookupTableData", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x140B}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "EnhancedPaletteColorLookupTableSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x140C}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "BlendingLUT2Sequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x140D}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "BlendingLUT2TransferFunction", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x140E}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "DataPathID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x140F}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "RGBLUTTransferFunction", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x1410}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "AlphaLUTTransferFunction", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x2000}, VR: "OB", VRs: []string{"OB"}, VM: "1", Keyword: "ICCProfile", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x2002}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "ColorSpace", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x2110}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "LossyImageCompression", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x2112}, VR: "DS", VRs: []string{"DS"}, VM: "1-n", Keyword: "LossyImageCompressionRatio", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x2114}, VR: "CS", VRs: []string{"CS"}, VM: "1-n", Keyword: "LossyImageCompressionMethod", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x3000}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ModalityLUTSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x3002}, VR: "US", VRs: []string{"US", "SS"}, VM: "3", Keyword: "LUTDescriptor", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x3003}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "LUTExplanation", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x3004}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ModalityLUTType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x3006}, VR: "US", VRs: []string{"US", "SS", "OW"}, VM: "1-n", Keyword: "LUTData", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x3010}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "VOILUTSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x3110}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "SoftcopyVOILUTSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x6010}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "RepresentativeFrameNumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x6020}, VR: "US", VRs: []string{"US"}, VM: "1-n", Keyword: "FrameNumbersOfInterest", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x6022}, VR: "LO", VRs: []string{"LO"}, VM: "1-n", Keyword: "FrameOfInterestDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x6023}, VR: "CS", VRs: []string{"CS"}, VM: "1-n", Keyword: "FrameOfInterestType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x6040}, VR: "US", VRs: []string{"US"}, VM: "1-n", Keyword: "RWavePointer", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x6100}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "MaskSubtractionSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x6101}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "MaskOperation", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x6102}, VR: "US", VRs: []string{"US"}, VM: "2-2n", Keyword: "ApplicableFrameRange", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x6110}, VR: "US", VRs: []string{"US"}, VM: "1-n", Keyword: "MaskFrameNumbers", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x6112}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "ContrastFrameAveraging", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x6114}, VR: "FL", VRs: []string{"FL"}, VM: "2", Keyword: "MaskSubPixelShift", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x6120}, VR: "SS", VRs: []string{"SS"}, VM: "1", Keyword: "TIDOffset", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x6190}, VR: "ST", VRs: []string{"ST"}, VM: "1", Keyword: "MaskOperationExplanation", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x7000}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "EquipmentAdministratorSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x7001}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "NumberOfDisplaySubsystems", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x9001}, VR: "UL", VRs: []string{"UL"}, VM: "1", Keyword: "DataPointRows", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x9002}, VR: "UL", VRs: []string{"UL"}, VM: "1", Keyword: "DataPointColumns", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x9003}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "SignalDomainColumns", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x9099}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "LargestMonochromePixelValue", Retired: true},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x9108}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "DataRepresentation", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x9110}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PixelMeasuresSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x9132}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "FrameVOILUTSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x9145}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PixelValueTransformationSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x9235}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "SignalDomainRows", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x9411}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "DisplayFilterPercentage", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x9415}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "FramePixelShiftSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x9416}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "SubtractionItemID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x9422}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PixelIntensityRelationshipLUTSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x9443}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "FramePixelDataPropertiesSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x9444}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "GeometricalProperties", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x9445}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "GeometricMaximumDistortion", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x9446}, VR: "CS", VRs: []string{"CS"}, VM: "1-n", Keyword: "ImageProcessingApplied", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x9454}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "MaskSelectionMode", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x9474}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "LUTFunction", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x9478}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "MaskVisibilityPercentage", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x9501}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PixelShiftSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x9502}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "RegionPixelShiftSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x9503}, VR: "SS", VRs: []string{"SS"}, VM: "2-2n", Keyword: "VerticesOfTheRegion", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x9505}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "MultiFramePresentationSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x9506}, VR: "US", VRs: []string{"US"}, VM: "2-2n", Keyword: "PixelShiftFrameRange", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x9507}, VR: "US", VRs: []string{"US"}, VM: "2-2n", Keyword: "LUTFrameRange", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x9520}, VR: "DS", VRs: []string{"DS"}, VM: "16", Keyword: "ImageToEquipmentMappingMatrix", Retired: false},
+	{Tag: tag.Tag{Group: 0x0028, Element: 0x9537}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "EquipmentCoordinateSystemIdentification", Retired: false},
+	{Tag: tag.Tag{Group: 0x0032, Element: 0x1032}, VR: "PN", VRs: []string{"PN"}, VM: "1", Keyword: "RequestingPhysician", Retired: false},
+	{Tag: tag.Tag{Group: 0x0032, Element: 0x1033}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "RequestingService", Retired: false},
+	{Tag: tag.Tag{Group: 0x0032, Element: 0x1060}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "RequestedProcedureDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0032, Element: 0x1064}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "RequestedProcedureCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0001}, VR: "AE", VRs: []string{"AE"}, VM: "1-n", Keyword: "ScheduledStationAETitle", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0002}, VR: "DA", VRs: []string{"DA"}, VM: "1", Keyword: "ScheduledProcedureStepStartDate", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0003}, VR: "TM", VRs: []string{"TM"}, VM: "1", Keyword: "ScheduledProcedureStepStartTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0004}, VR: "DA", VRs: []string{"DA"}, VM: "1", Keyword: "ScheduledProcedureStepEndDate", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0005}, VR: "TM", VRs: []string{"TM"}, VM: "1", Keyword: "ScheduledProcedureStepEndTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0006}, VR: "PN", VRs: []string{"PN"}, VM: "1", Keyword: "ScheduledPerformingPhysicianName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0007}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ScheduledProcedureStepDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0008}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ScheduledProtocolCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0009}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "ScheduledProcedureStepID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0010}, VR: "SH", VRs: []string{"SH"}, VM: "1-n", Keyword: "ScheduledStationName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0011}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "ScheduledProcedureStepLocation", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0012}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "PreMedication", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0020}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "ScheduledProcedureStepStatus", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0100}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ScheduledProcedureStepSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0241}, VR: "AE", VRs: []string{"AE"}, VM: "1", Keyword: "PerformedStationAETitle", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0242}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "PerformedStationName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0243}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "PerformedLocation", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0244}, VR: "DA", VRs: []string{"DA"}, VM: "1", Keyword: "PerformedProcedureStepStartDate", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0245}, VR: "TM", VRs: []string{"TM"}, VM: "1", Keyword: "PerformedProcedureStepStartTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0250}, VR: "DA", VRs: []string{"DA"}, VM: "1", Keyword: "PerformedProcedureStepEndDate", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0251}, VR: "TM", VRs: []string{"TM"}, VM: "1", Keyword: "PerformedProcedureStepEndTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0252}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "PerformedProcedureStepStatus", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0253}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "PerformedProcedureStepID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0254}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "PerformedProcedureStepDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0255}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "PerformedProcedureTypeDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0260}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PerformedProtocolCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0261}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "PerformedProtocolType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0270}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ScheduledStepAttributesSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0275}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "RequestAttributesSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0280}, VR: "ST", VRs: []string{"ST"}, VM: "1", Keyword: "CommentsOnThePerformedProcedureStep", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0281}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PerformedProcedureStepDiscontinuationReasonCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0293}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "QuantitySequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0294}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "Quantity", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0295}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "MeasuringUnitsSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0296}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "BillingItemSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0302}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "EntranceDose", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0303}, VR: "US", VRs: []string{"US"}, VM: "1-2", Keyword: "ExposedArea", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0306}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "DistanceSourceToEntrance", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0310}, VR: "ST", VRs: []string{"ST"}, VM: "1", Keyword: "CommentsOnRadiationDose", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0312}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "XRayOutput", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0314}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "HalfValueLayer", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0316}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "OrganDose", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0318}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "OrganExposed", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0555}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "AcquisitionContextSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x0556}, VR: "ST", VRs: []string{"ST"}, VM: "1", Keyword: "AcquisitionContextDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x08EA}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "MeasurementUnitsCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x1001}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "RequestedProcedureID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x1002}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ReasonForTheRequestedProcedure", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x1003}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "RequestedProcedurePriority", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x1004}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "PatientTransportArrangements", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x1005}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "RequestedProcedureLocation", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x1400}, VR: "LT", VRs: []string{"LT"}, VM: "1", Keyword: "RequestedProcedureComments", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x2016}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "PlacerOrderNumberImagingServiceRequest", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x2017}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "FillerOrderNumberImagingServiceRequest", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x2400}, VR: "LT", VRs: []string{"LT"}, VM: "1", Keyword: "ImagingServiceRequestComments", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x9096}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "RealWorldValueMappingSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x9210}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "LUTLabel", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x9211}, VR: "US", VRs: []string{"US", "SS"}, VM: "1", Keyword: "RealWorldValueLastValueMapped", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x9212}, VR: "FD", VRs: []string{"FD"}, VM: "1-n", Keyword: "RealWorldValueLUTData", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x9216}, VR: "US", VRs: []string{"US", "SS"}, VM: "1", Keyword: "RealWorldValueFirstValueMapped", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x9224}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "RealWorldValueIntercept", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0x9225}, VR: "FD", VRs: []string{"FD"}, VM: "1", Keyword: "RealWorldValueSlope", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA010}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "RelationshipType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA027}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "VerifyingOrganization", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA030}, VR: "DT", VRs: []string{"DT"}, VM: "1", Keyword: "VerificationDateTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA032}, VR: "DT", VRs: []string{"DT"}, VM: "1", Keyword: "ObservationDateTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA040}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "ValueType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA043}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ConceptNameCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA050}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "ContinuityOfContent", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA073}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "VerifyingObserverSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA075}, VR: "PN", VRs: []string{"PN"}, VM: "1", Keyword: "VerifyingObserverName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA078}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "AuthorObserverSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA07A}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ParticipantSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA07C}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "CustodialOrganizationSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA088}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "VerifyingObserverIdentificationCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA120}, VR: "DT", VRs: []string{"DT"}, VM: "1", Keyword: "DateTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA121}, VR: "DA", VRs: []string{"DA"}, VM: "1", Keyword: "Date", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA122}, VR: "TM", VRs: []string{"TM"}, VM: "1", Keyword: "Time", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA123}, VR: "PN", VRs: []string{"PN"}, VM: "1", Keyword: "PersonName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA124}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "UID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA130}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "TemporalRangeType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA132}, VR: "UL", VRs: []string{"UL"}, VM: "1-n", Keyword: "ReferencedSamplePositions", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA136}, VR: "US", VRs: []string{"US"}, VM: "1-n", Keyword: "ReferencedFrameNumbers", Retired: true},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA138}, VR: "DS", VRs: []string{"DS"}, VM: "1-n", Keyword: "ReferencedTimeOffsets", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA13A}, VR: "DT", VRs: []string{"DT"}, VM: "1-n", Keyword: "ReferencedDateTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA160}, VR: "UT", VRs: []string{"UT"}, VM: "1", Keyword: "TextValue", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA161}, VR: "FD", VRs: []string{"FD"}, VM: "1-n", Keyword: "FloatingPointValue", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA162}, VR: "SL", VRs: []string{"SL"}, VM: "1-n", Keyword: "RationalNumeratorValue", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA163}, VR: "UL", VRs: []string{"UL"}, VM: "1-n", Keyword: "RationalDenominatorValue", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA168}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ConceptCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA170}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PurposeOfReferenceCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA180}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "AnnotationGroupNumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA195}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ModifierCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA300}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "MeasuredValueSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA301}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "NumericValueQualifierCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA30A}, VR: "DS", VRs: []string{"DS"}, VM: "1-n", Keyword: "NumericValue", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA360}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PredecessorDocumentsSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA370}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ReferencedRequestSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA372}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PerformedProcedureCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA375}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "CurrentRequestedProcedureEvidenceSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA385}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PertinentOtherEvidenceSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA390}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "HL7StructuredDocumentReferenceSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA491}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "CompletionFlag", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA492}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "CompletionFlagDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA493}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "VerificationFlag", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA504}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ContentTemplateSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA525}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "IdenticalDocumentsSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xA730}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ContentSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xDB00}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "TemplateIdentifier", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xDB73}, VR: "UL", VRs: []string{"UL"}, VM: "1-n", Keyword: "ReferencedContentItemIdentifier", Retired: false},
+	{Tag: tag.Tag{Group: 0x0040, Element: 0xE001}, VR: "ST", VRs: []string{"ST"}, VM: "1", Keyword: "HL7InstanceIdentifier", Retired: false},
+	{Tag: tag.Tag{Group: 0x0042, Element: 0x0010}, VR: "ST", VRs: []string{"ST"}, VM: "1", Keyword: "DocumentTitle", Retired: false},
+	{Tag: tag.Tag{Group: 0x0042, Element: 0x0011}, VR: "OB", VRs: []string{"OB"}, VM: "1", Keyword: "EncapsulatedDocument", Retired: false},
+	{Tag: tag.Tag{Group: 0x0042, Element: 0x0012}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "MIMETypeOfEncapsulatedDocument", Retired: false},
+	{Tag: tag.Tag{Group: 0x0042, Element: 0x0013}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "SourceInstanceSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0042, Element: 0x0014}, VR: "LO", VRs: []string{"LO"}, VM: "1-n", Keyword: "ListOfMIMETypes", Retired: false},
+	{Tag: tag.Tag{Group: 0x0042, Element: 0x0015}, VR: "UL", VRs: []string{"UL"}, VM: "1", Keyword: "EncapsulatedDocumentLength", Retired: false},
+	{Tag: tag.Tag{Group: 0x0062, Element: 0x0001}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "SegmentationType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0062, Element: 0x0002}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "SegmentSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0062, Element: 0x0003}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "SegmentedPropertyCategoryCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0062, Element: 0x0004}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "SegmentNumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x0062, Element: 0x0005}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "SegmentLabel", Retired: false},
+	{Tag: tag.Tag{Group: 0x0062, Element: 0x0006}, VR: "ST", VRs: []string{"ST"}, VM: "1", Keyword: "SegmentDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0062, Element: 0x0008}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "SegmentAlgorithmType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0062, Element: 0x0009}, VR: "LO", VRs: []string{"LO"}, VM: "1-n", Keyword: "SegmentAlgorithmName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0062, Element: 0x000A}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "SegmentIdentificationSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0062, Element: 0x000B}, VR: "US", VRs: []string{"US"}, VM: "1-n", Keyword: "ReferencedSegmentNumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x0062, Element: 0x000C}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "RecommendedDisplayGrayscaleValue", Retired: false},
+	{Tag: tag.Tag{Group: 0x0062, Element: 0x000D}, VR: "US", VRs: []string{"US"}, VM: "3", Keyword: "RecommendedDisplayCIELabValue", Retired: false},
+	{Tag: tag.Tag{Group: 0x0062, Element: 0x000E}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "MaximumFractionalValue", Retired: false},
+	{Tag: tag.Tag{Group: 0x0062, Element: 0x000F}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "SegmentedPropertyTypeCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0062, Element: 0x0010}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "SegmentationFractionalType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0062, Element: 0x0013}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "SegmentsOverlap", Retired: false},
+	{Tag: tag.Tag{Group: 0x0062, Element: 0x0020}, VR: "UT", VRs: []string{"UT"}, VM: "1", Keyword: "TrackingID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0062, Element: 0x0021}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "TrackingUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0066, Element: 0x0001}, VR: "UL", VRs: []string{"UL"}, VM: "1", Keyword: "NumberOfSurfaces", Retired: false},
+	{Tag: tag.Tag{Group: 0x0066, Element: 0x0002}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "SurfaceSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0066, Element: 0x0003}, VR: "UL", VRs: []string{"UL"}, VM: "1", Keyword: "SurfaceNumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x0066, Element: 0x0004}, VR: "LT", VRs: []string{"LT"}, VM: "1", Keyword: "SurfaceComments", Retired: false},
+	{Tag: tag.Tag{Group: 0x0066, Element: 0x0009}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "SurfaceProcessing", Retired: false},
+	{Tag: tag.Tag{Group: 0x0066, Element: 0x000C}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "RecommendedPresentationOpacity", Retired: false},
+	{Tag: tag.Tag{Group: 0x0066, Element: 0x000D}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "RecommendedPresentationType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0066, Element: 0x000E}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "FiniteVolume", Retired: false},
+	{Tag: tag.Tag{Group: 0x0066, Element: 0x0010}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "Manifold", Retired: false},
+	{Tag: tag.Tag{Group: 0x0066, Element: 0x0011}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "SurfacePointsSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0066, Element: 0x0012}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "SurfacePointsNormalsSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0066, Element: 0x0013}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "SurfaceMeshPrimitivesSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0066, Element: 0x0015}, VR: "UL", VRs: []string{"UL"}, VM: "1", Keyword: "NumberOfSurfacePoints", Retired: false},
+	{Tag: tag.Tag{Group: 0x0066, Element: 0x0016}, VR: "OF", VRs: []string{"OF"}, VM: "1", Keyword: "PointCoordinatesData", Retired: false},
+	{Tag: tag.Tag{Group: 0x0066, Element: 0x001A}, VR: "FL", VRs: []string{"FL"}, VM: "6", Keyword: "PointsBoundingBoxCoordinates", Retired: false},
+	{Tag: tag.Tag{Group: 0x0066, Element: 0x001E}, VR: "UL", VRs: []string{"UL"}, VM: "1", Keyword: "NumberOfVectors", Retired: false},
+	{Tag: tag.Tag{Group: 0x0066, Element: 0x001F}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "VectorDimensionality", Retired: false},
+	{Tag: tag.Tag{Group: 0x0066, Element: 0x0021}, VR: "OF", VRs: []string{"OF"}, VM: "1", Keyword: "VectorCoordinateData", Retired: false},
+	{Tag: tag.Tag{Group: 0x0066, Element: 0x002F}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "AlgorithmFamilyCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0066, Element: 0x0036}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "AlgorithmName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0066, Element: 0x0041}, VR: "OL", VRs: []string{"OL"}, VM: "1", Keyword: "LongTrianglePointIndexList", Retired: false},
+	{Tag: tag.Tag{Group: 0x0066, Element: 0x0043}, VR: "OL", VRs: []string{"OL"}, VM: "1", Keyword: "LongVertexPointIndexList", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0001}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "GraphicAnnotationSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0002}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "GraphicLayer", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0003}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "BoundingBoxAnnotationUnits", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0004}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "AnchorPointAnnotationUnits", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0005}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "GraphicAnnotationUnits", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0006}, VR: "ST", VRs: []string{"ST"}, VM: "1", Keyword: "UnformattedTextValue", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0008}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "TextObjectSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0009}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "GraphicObjectSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0010}, VR: "FL", VRs: []string{"FL"}, VM: "2", Keyword: "BoundingBoxTopLeftHandCorner", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0011}, VR: "FL", VRs: []string{"FL"}, VM: "2", Keyword: "BoundingBoxBottomRightHandCorner", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0012}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "BoundingBoxTextHorizontalJustification", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0014}, VR: "FL", VRs: []string{"FL"}, VM: "2", Keyword: "AnchorPoint", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0015}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "AnchorPointVisibility", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0020}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "GraphicDimensions", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0021}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "NumberOfGraphicPoints", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0022}, VR: "FL", VRs: []string{"FL"}, VM: "2-n", Keyword: "GraphicData", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0023}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "GraphicType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0024}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "GraphicFilled", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0041}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "ImageHorizontalFlip", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0042}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "ImageRotation", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0052}, VR: "SL", VRs: []string{"SL"}, VM: "2", Keyword: "DisplayedAreaTopLeftHandCorner", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0053}, VR: "SL", VRs: []string{"SL"}, VM: "2", Keyword: "DisplayedAreaBottomRightHandCorner", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x005A}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "DisplayedAreaSelectionSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0060}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "GraphicLayerSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0062}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "GraphicLayerOrder", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0066}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "GraphicLayerRecommendedDisplayGrayscaleValue", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0068}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "GraphicLayerDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0080}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "ContentLabel", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0081}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ContentDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0082}, VR: "DA", VRs: []string{"DA"}, VM: "1", Keyword: "PresentationCreationDate", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0083}, VR: "TM", VRs: []string{"TM"}, VM: "1", Keyword: "PresentationCreationTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0084}, VR: "PN", VRs: []string{"PN"}, VM: "1", Keyword: "ContentCreatorName", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0100}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "PresentationSizeMode", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0101}, VR: "DS", VRs: []string{"DS"}, VM: "2", Keyword: "PresentationPixelSpacing", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0102}, VR: "IS", VRs: []string{"IS"}, VM: "2", Keyword: "PresentationPixelAspectRatio", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0103}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "PresentationPixelMagnificationRatio", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0401}, VR: "US", VRs: []string{"US"}, VM: "3", Keyword: "GraphicLayerRecommendedDisplayCIELabValue", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0402}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "BlendingSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0070, Element: 0x0403}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "RelativeOpacity", Retired: false},
+	{Tag: tag.Tag{Group: 0x0088, Element: 0x0130}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "StorageMediaFileSetID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0088, Element: 0x0140}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "StorageMediaFileSetUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0088, Element: 0x0200}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "IconImageSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0400, Element: 0x0005}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "MACIDNumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x0400, Element: 0x0010}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "MACCalculationTransferSyntaxUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0400, Element: 0x0015}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "MACAlgorithm", Retired: false},
+	{Tag: tag.Tag{Group: 0x0400, Element: 0x0020}, VR: "AT", VRs: []string{"AT"}, VM: "1-n", Keyword: "DataElementsSigned", Retired: false},
+	{Tag: tag.Tag{Group: 0x0400, Element: 0x0100}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "DigitalSignatureUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0400, Element: 0x0105}, VR: "DT", VRs: []string{"DT"}, VM: "1", Keyword: "DigitalSignatureDateTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0400, Element: 0x0110}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "CertificateType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0400, Element: 0x0115}, VR: "OB", VRs: []string{"OB"}, VM: "1", Keyword: "CertificateOfSigner", Retired: false},
+	{Tag: tag.Tag{Group: 0x0400, Element: 0x0120}, VR: "OB", VRs: []string{"OB"}, VM: "1", Keyword: "Signature", Retired: false},
+	{Tag: tag.Tag{Group: 0x0400, Element: 0x0305}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "CertifiedTimestampType", Retired: false},
+	{Tag: tag.Tag{Group: 0x0400, Element: 0x0310}, VR: "OB", VRs: []string{"OB"}, VM: "1", Keyword: "CertifiedTimestamp", Retired: false},
+	{Tag: tag.Tag{Group: 0x0400, Element: 0x0401}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "DigitalSignaturePurposeCodeSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0400, Element: 0x0402}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ReferencedDigitalSignatureSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0400, Element: 0x0403}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ReferencedSOPInstanceMACSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0400, Element: 0x0404}, VR: "OB", VRs: []string{"OB"}, VM: "1", Keyword: "MAC", Retired: false},
+	{Tag: tag.Tag{Group: 0x0400, Element: 0x0500}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "EncryptedAttributesSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0400, Element: 0x0510}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "EncryptedContentTransferSyntaxUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x0400, Element: 0x0520}, VR: "OB", VRs: []string{"OB"}, VM: "1", Keyword: "EncryptedContent", Retired: false},
+	{Tag: tag.Tag{Group: 0x0400, Element: 0x0550}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ModifiedAttributesSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0400, Element: 0x0561}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "OriginalAttributesSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x0400, Element: 0x0562}, VR: "DT", VRs: []string{"DT"}, VM: "1", Keyword: "AttributeModificationDateTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x0400, Element: 0x0563}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ModifyingSystem", Retired: false},
+	{Tag: tag.Tag{Group: 0x0400, Element: 0x0564}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "SourceOfPreviousValues", Retired: false},
+	{Tag: tag.Tag{Group: 0x0400, Element: 0x0565}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "ReasonForTheAttributeModification", Retired: false},
+	{Tag: tag.Tag{Group: 0x2050, Element: 0x0010}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PresentationLUTSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x2050, Element: 0x0020}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "PresentationLUTShape", Retired: false},
+	{Tag: tag.Tag{Group: 0x2050, Element: 0x0500}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ReferencedPresentationLUTSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x3006, Element: 0x0002}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "StructureSetLabel", Retired: false},
+	{Tag: tag.Tag{Group: 0x3006, Element: 0x0004}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "StructureSetName", Retired: false},
+	{Tag: tag.Tag{Group: 0x3006, Element: 0x0006}, VR: "ST", VRs: []string{"ST"}, VM: "1", Keyword: "StructureSetDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x3006, Element: 0x0008}, VR: "DA", VRs: []string{"DA"}, VM: "1", Keyword: "StructureSetDate", Retired: false},
+	{Tag: tag.Tag{Group: 0x3006, Element: 0x0009}, VR: "TM", VRs: []string{"TM"}, VM: "1", Keyword: "StructureSetTime", Retired: false},
+	{Tag: tag.Tag{Group: 0x3006, Element: 0x0010}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ReferencedFrameOfReferenceSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x3006, Element: 0x0016}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ContourImageSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x3006, Element: 0x0020}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "StructureSetROISequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x3006, Element: 0x0022}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "ROINumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x3006, Element: 0x0024}, VR: "UI", VRs: []string{"UI"}, VM: "1", Keyword: "ReferencedFrameOfReferenceUID", Retired: false},
+	{Tag: tag.Tag{Group: 0x3006, Element: 0x0026}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "ROIName", Retired: false},
+	{Tag: tag.Tag{Group: 0x3006, Element: 0x0028}, VR: "ST", VRs: []string{"ST"}, VM: "1", Keyword: "ROIDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x3006, Element: 0x002A}, VR: "IS", VRs: []string{"IS"}, VM: "3", Keyword: "ROIDisplayColor", Retired: false},
+	{Tag: tag.Tag{Group: 0x3006, Element: 0x0036}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "ROIGenerationAlgorithm", Retired: false},
+	{Tag: tag.Tag{Group: 0x3006, Element: 0x0039}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ROIContourSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x3006, Element: 0x0040}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ContourSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x3006, Element: 0x0042}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "ContourGeometricType", Retired: false},
+	{Tag: tag.Tag{Group: 0x3006, Element: 0x0046}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "NumberOfContourPoints", Retired: false},
+	{Tag: tag.Tag{Group: 0x3006, Element: 0x0048}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "ContourNumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x3006, Element: 0x0050}, VR: "DS", VRs: []string{"DS"}, VM: "3-3n", Keyword: "ContourData", Retired: false},
+	{Tag: tag.Tag{Group: 0x3006, Element: 0x0080}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "RTROIObservationsSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x3006, Element: 0x0082}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "ObservationNumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x3006, Element: 0x0084}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "ReferencedROINumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x3006, Element: 0x00A4}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "RTROIInterpretedType", Retired: false},
+	{Tag: tag.Tag{Group: 0x3006, Element: 0x00A6}, VR: "PN", VRs: []string{"PN"}, VM: "1", Keyword: "ROIInterpreter", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x0001}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "LowEnergyDetector", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x0002}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "HighEnergyDetector", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x0003}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "DetectorBinNumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x0005}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "LowerEnergy", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x0006}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "EnergyResolution", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x0007}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "HigherEnergy", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1001}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "ATDAbility", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1006}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "PotentialThreatObjectID", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1007}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "RouteSegmentSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1009}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "ThreatROIType", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x100A}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "AlarmDecision", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x100B}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "ScanningConfiguration", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x100C}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ExposureSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x100D}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ProcessedBinNumberSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x100E}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "TotalProcessedBinNumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1010}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PTOSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1011}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PTORepresentationSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1012}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "OOIType", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1013}, VR: "FL", VRs: []string{"FL"}, VM: "3", Keyword: "OOISize", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1014}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "NumberOfAlarmObjects", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1015}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ATDAssessmentSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1016}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "ThreatConfidenceScore", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1017}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "ATDAssessmentProbability", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1018}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "OOIOwnerType", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1019}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "OOIVolume", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x101A}, VR: "FL", VRs: []string{"FL"}, VM: "1", Keyword: "OOIMass", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x101D}, VR: "FL", VRs: []string{"FL"}, VM: "3-3n", Keyword: "BoundingPolygon", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1020}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ThreatROISequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1021}, VR: "CS", VRs: []string{"CS"}, VM: "1-n", Keyword: "AbortReason", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1023}, VR: "FL", VRs: []string{"FL"}, VM: "3", Keyword: "BoundingBoxTopLeft", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1024}, VR: "FL", VRs: []string{"FL"}, VM: "3", Keyword: "BoundingBoxBottomRight", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1026}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "TransportClassificationSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1027}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "AssessmentRequestSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1028}, VR: "UT", VRs: []string{"UT"}, VM: "1", Keyword: "ThreatCategoryDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1029}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "OperatorAssessmentSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1030}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "OOIOwnerID", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1031}, VR: "PN", VRs: []string{"PN"}, VM: "1", Keyword: "OOIOwnerName", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1032}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "OOIOwnerIDType", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1033}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "OOIOwnerCategory", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1034}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "OOIID", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1035}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "OOITypeAttr", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1036}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "OOISizeAttr", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1037}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "OOILabel", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1040}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "FlightNumber", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1041}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "ITDType", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1042}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "ITDSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1043}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "DepartureAirport", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1044}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "ArrivalAirport", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1045}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "CarrierName", Retired: false},
+	{Tag: tag.Tag{Group: 0x4010, Element: 0x1046}, VR: "SH", VRs: []string{"SH"}, VM: "1", Keyword: "CarrierCode", Retired: false},
+	{Tag: tag.Tag{Group: 0x5200, Element: 0x9229}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "SharedFunctionalGroupsSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x5200, Element: 0x9230}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "PerFrameFunctionalGroupsSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x5400, Element: 0x0100}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "WaveformSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0x5400, Element: 0x1004}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "WaveformBitsAllocated", Retired: false},
+	{Tag: tag.Tag{Group: 0x5400, Element: 0x1006}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "WaveformSampleInterpretation", Retired: false},
+	{Tag: tag.Tag{Group: 0x5400, Element: 0x100A}, VR: "OW", VRs: []string{"OW", "OB"}, VM: "1", Keyword: "WaveformPaddingValue", Retired: false},
+	{Tag: tag.Tag{Group: 0x5400, Element: 0x1010}, VR: "OW", VRs: []string{"OW", "OB"}, VM: "1", Keyword: "WaveformData", Retired: false},
+	{Tag: tag.Tag{Group: 0x5600, Element: 0x0020}, VR: "OF", VRs: []string{"OF"}, VM: "1", Keyword: "SpectroscopyData", Retired: false},
+	{Tag: tag.Tag{Group: 0x6000, Element: 0x0010}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "OverlayRows", Retired: false},
+	{Tag: tag.Tag{Group: 0x6000, Element: 0x0011}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "OverlayColumns", Retired: false},
+	{Tag: tag.Tag{Group: 0x6000, Element: 0x0015}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "NumberOfFramesInOverlay", Retired: false},
+	{Tag: tag.Tag{Group: 0x6000, Element: 0x0022}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "OverlayDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x6000, Element: 0x0040}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "OverlayType", Retired: false},
+	{Tag: tag.Tag{Group: 0x6000, Element: 0x0045}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "OverlaySubtype", Retired: false},
+	{Tag: tag.Tag{Group: 0x6000, Element: 0x0050}, VR: "SS", VRs: []string{"SS"}, VM: "2", Keyword: "OverlayOrigin", Retired: false},
+	{Tag: tag.Tag{Group: 0x6000, Element: 0x0051}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "ImageFrameOrigin", Retired: false},
+	{Tag: tag.Tag{Group: 0x6000, Element: 0x0100}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "OverlayBitsAllocated", Retired: false},
+	{Tag: tag.Tag{Group: 0x6000, Element: 0x0102}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "OverlayBitPosition", Retired: false},
+	{Tag: tag.Tag{Group: 0x6000, Element: 0x1001}, VR: "CS", VRs: []string{"CS"}, VM: "1", Keyword: "OverlayActivationLayer", Retired: false},
+	{Tag: tag.Tag{Group: 0x6000, Element: 0x1301}, VR: "IS", VRs: []string{"IS"}, VM: "1", Keyword: "ROIArea", Retired: false},
+	{Tag: tag.Tag{Group: 0x6000, Element: 0x1302}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "ROIMean", Retired: false},
+	{Tag: tag.Tag{Group: 0x6000, Element: 0x1303}, VR: "DS", VRs: []string{"DS"}, VM: "1", Keyword: "ROIStandardDeviation", Retired: false},
+	{Tag: tag.Tag{Group: 0x6000, Element: 0x1500}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "OverlayLabel", Retired: false},
+	{Tag: tag.Tag{Group: 0x6000, Element: 0x3000}, VR: "OW", VRs: []string{"OW", "OB"}, VM: "1", Keyword: "OverlayData", Retired: false},
+	{Tag: tag.Tag{Group: 0x6000, Element: 0x4000}, VR: "LT", VRs: []string{"LT"}, VM: "1", Keyword: "OverlayComments", Retired: true},
+	{Tag: tag.Tag{Group: 0x6100, Element: 0x0030}, VR: "US", VRs: []string{"US"}, VM: "1", Keyword: "SeriesEnergy", Retired: false},
+	{Tag: tag.Tag{Group: 0x6100, Element: 0x0031}, VR: "LO", VRs: []string{"LO"}, VM: "1", Keyword: "SeriesEnergyDescription", Retired: false},
+	{Tag: tag.Tag{Group: 0x7FE0, Element: 0x0008}, VR: "OF", VRs: []string{"OF"}, VM: "1", Keyword: "FloatPixelData", Retired: false},
+	{Tag: tag.Tag{Group: 0x7FE0, Element: 0x0009}, VR: "OD", VRs: []string{"OD"}, VM: "1", Keyword: "DoubleFloatPixelData", Retired: false},
+	{Tag: tag.Tag{Group: 0x7FE0, Element: 0x0010}, VR: "OW", VRs: []string{"OW", "OB"}, VM: "1", Keyword: "PixelData", Retired: false},
+	{Tag: tag.Tag{Group: 0xFFFA, Element: 0xFFFA}, VR: "SQ", VRs: []string{"SQ"}, VM: "1", Keyword: "DigitalSignaturesSequence", Retired: false},
+	{Tag: tag.Tag{Group: 0xFFFC, Element: 0xFFFC}, VR: "OB", VRs: []string{"OB"}, VM: "1", Keyword: "DataSetTrailingPadding", Retired: false},
+	{Tag: tag.Tag{Group: 0xFFFE, Element: 0xE000}, VR: "", VRs: nil, VM: "1", Keyword: "Item", Retired: false},
+	{Tag: tag.Tag{Group: 0xFFFE, Element: 0xE00D}, VR: "", VRs: nil, VM: "1", Keyword: "ItemDelimitationItem", Retired: false},
+	{Tag: tag.Tag{Group: 0xFFFE, Element: 0xE0DD}, VR: "", VRs: nil, VM: "1", Keyword: "SequenceDelimitationItem", Retired: false},
+}
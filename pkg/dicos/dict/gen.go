@@ -0,0 +1,146 @@
+//go:build ignore
+
+// gen reads dicom.dic and writes entries.go
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// dcmtkVRs expands the DCMTK pseudo VRs
+var dcmtkVRs = map[string][]string{
+	"ox": {"OW", "OB"},
+	"xs": {"US", "SS"},
+	"lt": {"US", "SS", "OW"},
+	"na": nil,
+}
+
+type entry struct {
+	group, element uint16
+	vrs            []string
+	vm, keyword    string
+	retired        bool
+}
+
+func main() {
+	f, err := os.Open("dicom.dic")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	var entries []entry
+	seen := map[[2]uint16]int{}
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		e, ok, err := parseLine(line)
+		if err != nil {
+			log.Fatalf("dicom.dic:%d: %v", n, err)
+		}
+		if !ok {
+			continue
+		}
+		key := [2]uint16{e.group, e.element}
+		if prev, dup := seen[key]; dup {
+			log.Fatalf("dicom.dic:%d: (%04X,%04X) already defined on line %d", n, e.group, e.element, prev)
+		}
+		seen[key] = n
+		entries = append(entries, e)
+	}
+	if err := sc.Err(); err != nil {
+		log.Fatal(err)
+	}
+	slices.SortFunc(entries, func(a, b entry) int {
+		if a.group != b.group {
+			return int(a.group) - int(b.group)
+		}
+		return int(a.element) - int(b.element)
+	})
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen.go; DO NOT EDIT.\n\n")
+	buf.WriteString("package dict\n\n")
+	buf.WriteString("import \"github.com/jpfielding/dicos.go/pkg/dicos/tag\"\n\n")
+	buf.WriteString("var entries = []Entry{\n")
+	for _, e := range entries {
+		vr := ""
+		if len(e.vrs) > 0 {
+			vr = e.vrs[0]
+		}
+		vrs := "nil"
+		if len(e.vrs) > 0 {
+			quoted := make([]string, len(e.vrs))
+			for i, v := range e.vrs {
+				quoted[i] = strconv.Quote(v)
+			}
+			vrs = "[]string{" + strings.Join(quoted, ", ") + "}"
+		}
+		fmt.Fprintf(&buf, "\t{Tag: tag.Tag{Group: 0x%04X, Element: 0x%04X}, VR: %q, VRs: %s, VM: %q, Keyword: %q, Retired: %t},\n",
+			e.group, e.element, vr, vrs, e.vm, e.keyword, e.retired)
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("entries.go", src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parseLine parses "(gggg,eeee) VR Keyword VM Version", reporting false for
+// entries that cannot be represented as a single tag
+func parseLine(line string) (entry, bool, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 {
+		return entry{}, false, fmt.Errorf("expected tag, VR, keyword and VM: %q", line)
+	}
+	tagField := strings.Trim(fields[0], "()")
+	ge := strings.Split(tagField, ",")
+	if len(ge) != 2 || strings.Contains(ge[0], "\"") || strings.Contains(ge[1], "\"") ||
+		strings.Contains(ge[1], "-") || strings.Contains(ge[1], "x") {
+		return entry{}, false, nil // private or ranged element
+	}
+	group := strings.Replace(ge[0], "xx", "00", 1)
+	g, err := strconv.ParseUint(group, 16, 16)
+	if err != nil {
+		return entry{}, false, fmt.Errorf("group %q: %w", ge[0], err)
+	}
+	el, err := strconv.ParseUint(ge[1], 16, 16)
+	if err != nil {
+		return entry{}, false, fmt.Errorf("element %q: %w", ge[1], err)
+	}
+
+	e := entry{group: uint16(g), element: uint16(el), vm: fields[3], keyword: fields[2]}
+	if vrs, ok := dcmtkVRs[fields[1]]; ok {
+		e.vrs = vrs
+	} else {
+		for _, vr := range strings.Split(fields[1], "/") {
+			if len(vr) != 2 || strings.ToUpper(vr) != vr {
+				return entry{}, false, fmt.Errorf("invalid VR %q", fields[1])
+			}
+			e.vrs = append(e.vrs, vr)
+		}
+	}
+	if strings.HasPrefix(e.keyword, "RETIRED_") {
+		e.keyword = strings.TrimPrefix(e.keyword, "RETIRED_")
+		e.retired = true
+	}
+	if len(fields) > 4 && strings.HasSuffix(strings.ToLower(fields[4]), "retired") {
+		e.retired = true
+	}
+	return e, true, nil
+}
@@ -0,0 +1,147 @@
+package dicos
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/module"
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// Energy levels of a dual-energy series, as read by GetEnergyLevel and
+// written by SetEnergyLevel
+const (
+	EnergyLow  = "le"
+	EnergyHigh = "he"
+)
+
+// DXEnergyBin is the energy window of the detector bin a DX image was
+// acquired with (group 4010). Zero energies are not written.
+type DXEnergyBin struct {
+	Number        int     // DetectorBinNumber, 1-based
+	LowerKeV      float64 // LowerEnergy
+	HigherKeV     float64 // HigherEnergy
+	ResolutionKeV float64 // EnergyResolution
+}
+
+// energyOptions writes the series energy tags of level and the detector
+// indicators and bin of a DX image
+func energyOptions(level string, bin *DXEnergyBin) []Option {
+	if level == "" && bin == nil {
+		return nil
+	}
+	opts := []Option{func(ds *Dataset) error { return SetEnergyLevel(ds, level) }}
+	switch strings.ToLower(level) {
+	case EnergyLow, "low":
+		opts = append(opts, withVR(tag.LowEnergyDetector, "CS", "YES"), withVR(tag.HighEnergyDetector, "CS", "NO"))
+	case EnergyHigh, "high":
+		opts = append(opts, withVR(tag.LowEnergyDetector, "CS", "NO"), withVR(tag.HighEnergyDetector, "CS", "YES"))
+	}
+	if bin == nil {
+		return opts
+	}
+	opts = append(opts, withVR(tag.DetectorBinNumber, "US", uint16(bin.Number)))
+	for t, v := range map[tag.Tag]float64{
+		tag.LowerEnergy:      bin.LowerKeV,
+		tag.HigherEnergy:     bin.HigherKeV,
+		tag.EnergyResolution: bin.ResolutionKeV,
+	} {
+		if v != 0 {
+			opts = append(opts, withVR(t, "DS", strconv.FormatFloat(v, 'f', -1, 64)))
+		}
+	}
+	return opts
+}
+
+// DualEnergyDX builds the paired low and high energy series of a dual-energy
+// DX acquisition. The two images share the Patient, Study, Equipment and
+// Frame of Reference, so a viewer can overlay them, and each has its own
+// series tagged with its energy level and detector bin.
+//
+// Example:
+//
+//	de := dicos.NewDualEnergyDX()
+//	de.Patient.PatientID = "BAG-001"
+//	de.Low.EnergyBin.LowerKeV, de.Low.EnergyBin.HigherKeV = 20, 70
+//	de.High.EnergyBin.LowerKeV, de.High.EnergyBin.HigherKeV = 70, 160
+//	de.SetPixelData(1024, 768, lowPixels, highPixels)
+//	lowPath, highPath, err := de.Write("scans/") // <SOPInstanceUID>.dcs each
+type DualEnergyDX struct {
+	Patient          module.PatientModule
+	Study            module.GeneralStudyModule
+	Equipment        module.GeneralEquipmentModule
+	FrameOfReference module.FrameOfReferenceModule
+
+	Low  *DXImage // series 1, SeriesEnergy 1
+	High *DXImage // series 2, SeriesEnergy 2
+}
+
+// NewDualEnergyDX returns a pair of DX images with the defaults of
+// NewDXImage, one study and frame of reference, and detector bins 1 and 2
+func NewDualEnergyDX() *DualEnergyDX {
+	de := &DualEnergyDX{
+		Study: module.NewGeneralStudyModule(),
+		Low:   NewDXImage(),
+		High:  NewDXImage(),
+	}
+	fillUIDs(&de.Study.StudyInstanceUID, &de.FrameOfReference.FrameOfReferenceUID)
+	for i, dx := range []*DXImage{de.Low, de.High} {
+		dx.Series.SeriesNumber = i + 1
+		dx.InstanceNumber = 1
+		dx.EnergyBin = &DXEnergyBin{Number: i + 1}
+	}
+	de.Low.EnergyLevel, de.Low.Series.SeriesDescription = EnergyLow, "Low Energy"
+	de.High.EnergyLevel, de.High.Series.SeriesDescription = EnergyHigh, "High Energy"
+	return de
+}
+
+// SetPixelData sets the pixel data of both images, which must have the same
+// size
+func (de *DualEnergyDX) SetPixelData(rows, cols int, low, high []uint16) {
+	de.Low.SetPixelData(rows, cols, low)
+	de.High.SetPixelData(rows, cols, high)
+}
+
+// GetDatasets builds the low and high energy datasets, copying the shared
+// modules into each image first
+func (de *DualEnergyDX) GetDatasets() (low, high *Dataset, err error) {
+	if de.Low == nil || de.High == nil {
+		return nil, nil, fmt.Errorf("dual-energy DX needs both a low and a high energy image")
+	}
+	if de.Low.Rows != de.High.Rows || de.Low.Columns != de.High.Columns {
+		return nil, nil, fmt.Errorf("dual-energy DX images differ in size: %dx%d and %dx%d",
+			de.Low.Columns, de.Low.Rows, de.High.Columns, de.High.Rows)
+	}
+	fillUIDs(&de.Study.StudyInstanceUID, &de.FrameOfReference.FrameOfReferenceUID)
+	for _, dx := range []*DXImage{de.Low, de.High} {
+		dx.Patient, dx.Study, dx.Equipment = de.Patient, de.Study, de.Equipment
+		ref := de.FrameOfReference
+		dx.FrameOfReference = &ref
+	}
+	if low, err = de.Low.GetDataset(); err != nil {
+		return nil, nil, fmt.Errorf("low energy: %w", err)
+	}
+	if high, err = de.High.GetDataset(); err != nil {
+		return nil, nil, fmt.Errorf("high energy: %w", err)
+	}
+	return low, high, nil
+}
+
+// Write saves both images to dir as <SOPInstanceUID>.dcs and returns their
+// paths
+func (de *DualEnergyDX) Write(dir string) (lowPath, highPath string, err error) {
+	low, high, err := de.GetDatasets()
+	if err != nil {
+		return "", "", err
+	}
+	paths := make([]string, 2)
+	for i, ds := range []*Dataset{low, high} {
+		paths[i] = filepath.Join(dir, stringValue(ds, tag.SOPInstanceUID)+GetExtension())
+		if _, err := WriteFile(paths[i], ds); err != nil {
+			return "", "", err
+		}
+	}
+	return paths[0], paths[1], nil
+}
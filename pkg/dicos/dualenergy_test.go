@@ -0,0 +1,92 @@
+package dicos
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDualEnergyDX(t *testing.T) {
+	de := NewDualEnergyDX()
+	de.Patient.PatientID = "BAG-001"
+	de.Low.EnergyBin.LowerKeV, de.Low.EnergyBin.HigherKeV = 20, 70
+	de.High.EnergyBin.LowerKeV, de.High.EnergyBin.HigherKeV, de.High.EnergyBin.ResolutionKeV = 70, 160, 2.5
+	low, high := make([]uint16, 16*8), make([]uint16, 16*8)
+	for i := range low {
+		low[i], high[i] = uint16(i), uint16(2*i)
+	}
+	de.SetPixelData(8, 16, low, high)
+
+	lds, hds, err := de.GetDatasets()
+	require.NoError(t, err)
+
+	assert.Equal(t, "le", GetEnergyLevel(lds))
+	assert.Equal(t, "he", GetEnergyLevel(hds))
+	assert.Equal(t, "Low Energy", GetSeriesEnergyDescription(lds))
+	assert.Equal(t, 2, GetSeriesEnergy(hds))
+	assert.Empty(t, lds.Warnings(), "the level is tagged, not inferred")
+
+	// shared study and frame of reference, separate series and instances
+	for _, tg := range []Tag{tag.StudyInstanceUID, tag.FrameOfReferenceUID, tag.PatientID} {
+		assert.NotEmpty(t, stringValue(lds, tg), "%v", tg)
+		assert.Equal(t, stringValue(lds, tg), stringValue(hds, tg), "%v", tg)
+	}
+	for _, tg := range []Tag{tag.SeriesInstanceUID, tag.SOPInstanceUID, tag.SeriesNumber} {
+		assert.NotEqual(t, stringValue(lds, tg), stringValue(hds, tg), "%v", tg)
+	}
+
+	// detector indicators and energy bins
+	assert.Equal(t, "YES", stringValue(lds, tag.LowEnergyDetector))
+	assert.Equal(t, "NO", stringValue(lds, tag.HighEnergyDetector))
+	assert.Equal(t, "YES", stringValue(hds, tag.HighEnergyDetector))
+	assert.Equal(t, uint16(1), lds.Elements[tag.DetectorBinNumber].Value)
+	assert.Equal(t, uint16(2), hds.Elements[tag.DetectorBinNumber].Value)
+	q, ok := GetLowerEnergy(hds)
+	require.True(t, ok)
+	assert.Equal(t, 70.0, q.Value)
+	q, ok = GetHigherEnergy(lds)
+	require.True(t, ok)
+	assert.Equal(t, 70.0, q.Value)
+	assert.Equal(t, "2.5", stringValue(hds, tag.EnergyResolution))
+	assert.NotContains(t, lds.Elements, tag.EnergyResolution, "zero energies are not written")
+
+	// written and read back
+	dir := t.TempDir()
+	lowPath, highPath, err := de.Write(dir)
+	require.NoError(t, err)
+	for path, level := range map[string]string{lowPath: "le", highPath: "he"} {
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		ds, err := ParseWithOptions(context.Background(), f, ParseOptions{})
+		f.Close()
+		require.NoError(t, err)
+		assert.Equal(t, level, GetEnergyLevel(ds))
+		vol, err := DecodeVolume(ds)
+		require.NoError(t, err)
+		assert.Equal(t, 16, vol.Width)
+	}
+}
+
+func TestDualEnergyDX_Errors(t *testing.T) {
+	de := NewDualEnergyDX()
+	de.Low.SetPixelData(8, 8, make([]uint16, 64))
+	de.High.SetPixelData(8, 16, make([]uint16, 128))
+	_, _, err := de.GetDatasets()
+	assert.ErrorContains(t, err, "differ in size")
+
+	de = NewDualEnergyDX()
+	de.High.EnergyLevel = "medium"
+	_, _, err = de.GetDatasets()
+	assert.ErrorContains(t, err, "high energy: invalid energy level")
+
+	// a single energy DX writes no energy tags
+	ds, err := NewDXImage().GetDataset()
+	require.NoError(t, err)
+	assert.NotContains(t, ds.Elements, tag.SeriesEnergy)
+	assert.NotContains(t, ds.Elements, tag.DetectorBinNumber)
+	assert.NotContains(t, ds.Elements, tag.FrameOfReferenceUID)
+}
@@ -0,0 +1,118 @@
+package dicos
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/dict"
+)
+
+// DumpEntry is an element of a dataset as listed by Dump
+type DumpEntry struct {
+	Tag     Tag    `json:"tag"`
+	Keyword string `json:"keyword,omitempty"` // dictionary keyword, empty when unknown
+	Creator string `json:"creator,omitempty"` // private creator of a private element
+	VR      string `json:"vr"`
+	// Length is the value length as written, or -1 for sequences and
+	// encapsulated pixel data, which are written with undefined length
+	Length int           `json:"length"`
+	Value  string        `json:"value,omitempty"` // truncated for display
+	Items  [][]DumpEntry `json:"items,omitempty"` // entries of each sequence item
+}
+
+// Dump lists every element of ds in tag order, with the elements of sequence
+// items nested under their sequence. When tags are given, only those elements
+// are listed, along with the sequences leading to them.
+//
+// Example:
+//
+//	for _, e := range dicos.Dump(ds) {
+//		fmt.Println(e.Tag, e.VR, e.Keyword, e.Value)
+//	}
+func Dump(ds *Dataset, tags ...Tag) []DumpEntry {
+	var keep map[Tag]bool
+	if len(tags) > 0 {
+		keep = make(map[Tag]bool, len(tags))
+		for _, t := range tags {
+			keep[t] = true
+		}
+	}
+	return dumpDataset(ds, keep)
+}
+
+func dumpDataset(ds *Dataset, keep map[Tag]bool) []DumpEntry {
+	var out []DumpEntry
+	for _, t := range sortedTags(ds) {
+		elem := ds.Elements[t]
+		e := DumpEntry{Tag: t, Creator: privateCreator(ds, t), VR: elem.VR, Value: valueString(elem)}
+		if entry, ok := dict.Lookup(t); ok {
+			e.Keyword = entry.Keyword
+		}
+		matched := keep == nil || keep[t]
+		if items, ok := elem.Value.([]*Dataset); ok {
+			inner := keep
+			if matched {
+				inner = nil // a listed sequence is shown whole
+			}
+			for _, item := range items {
+				entries := dumpDataset(item, inner)
+				matched = matched || len(entries) > 0
+				e.Items = append(e.Items, entries)
+			}
+		}
+		if !matched {
+			continue
+		}
+		e.Length = dumpLength(elem)
+		out = append(out, e)
+	}
+	return out
+}
+
+// dumpLength returns the value length written for elem
+func dumpLength(elem *Element) int {
+	if bd, ok := elem.Value.(*BulkData); ok {
+		return int(bd.Length + bd.Length%2)
+	}
+	val, undefined, err := encodeValue(elem.Value, elem.VR)
+	if undefined || err != nil {
+		return -1
+	}
+	return len(val)
+}
+
+// WriteDump writes entries as text, one element per line with its tag, VR,
+// keyword, length and value. Sequence items are indented under their
+// sequence with an "Item" line each; empty items are left out.
+func WriteDump(w io.Writer, entries []DumpEntry) error {
+	var b strings.Builder
+	writeDumpEntries(&b, entries, 0)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeDumpEntries(b *strings.Builder, entries []DumpEntry, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for _, e := range entries {
+		name := e.Keyword
+		switch {
+		case e.Creator != "":
+			name = "[" + e.Creator + "]"
+		case name == "":
+			name = "?"
+		}
+		length := "undefined"
+		if e.Length >= 0 {
+			length = fmt.Sprint(e.Length)
+		}
+		fmt.Fprintf(b, "%s%v %-2s %-*s %9s  %s\n", indent, e.Tag, e.VR, max(36-len(indent), 0), name, length, e.Value)
+		for i, item := range e.Items {
+			if len(item) == 0 {
+				continue
+			}
+			fmt.Fprintf(b, "%s  > Item #%d\n", indent, i)
+			writeDumpEntries(b, item, depth+1)
+		}
+	}
+}
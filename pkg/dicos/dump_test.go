@@ -0,0 +1,61 @@
+package dicos
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDump(t *testing.T) {
+	item := func(id, label string) *Dataset {
+		ds, err := NewDataset(
+			WithElement(tag.PotentialThreatObjectID, id),
+			WithElement(tag.ThreatCategoryDescription, label),
+		)
+		require.NoError(t, err)
+		return ds
+	}
+	ds, err := NewDataset(
+		WithElement(tag.PatientID, "DUMP-1"),
+		WithElement(tag.Rows, uint16(4)),
+		WithSequence(tag.PTOSequence, item("1", "KNIFE"), item("2", "GUN")),
+		WithPixelData(2, 2, 16, []uint16{1, 2, 3, 4}, CodecRLE),
+	)
+	require.NoError(t, err)
+
+	entries := Dump(ds)
+	require.Len(t, entries, 4)
+	assert.Equal(t, DumpEntry{Tag: tag.PatientID, Keyword: "PatientID", VR: "LO", Length: 6, Value: "DUMP-1"}, entries[0])
+	assert.Equal(t, 2, entries[1].Length, "US")
+	pto := entries[2]
+	assert.Equal(t, -1, pto.Length)
+	assert.Equal(t, "2 items", pto.Value)
+	require.Len(t, pto.Items, 2)
+	assert.Equal(t, "GUN", pto.Items[1][1].Value)
+	assert.Equal(t, tag.PixelData, entries[3].Tag)
+	assert.Equal(t, -1, entries[3].Length, "encapsulated")
+
+	// filtering keeps the sequences leading to a listed tag
+	entries = Dump(ds, tag.ThreatCategoryDescription)
+	require.Len(t, entries, 1)
+	assert.Equal(t, tag.PTOSequence, entries[0].Tag)
+	require.Len(t, entries[0].Items[0], 1)
+	assert.Equal(t, "KNIFE", entries[0].Items[0][0].Value)
+
+	// a listed sequence is shown whole
+	entries = Dump(ds, tag.PTOSequence)
+	require.Len(t, entries, 1)
+	assert.Len(t, entries[0].Items[0], 2)
+
+	assert.Empty(t, Dump(ds, tag.StudyDate))
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteDump(&buf, Dump(ds)))
+	out := buf.String()
+	assert.Contains(t, out, "(0010,0020) LO PatientID")
+	assert.Contains(t, out, "  > Item #1\n  (4010,1006) LO")
+	assert.Regexp(t, `\(4010,1010\) SQ PTOSequence +undefined  2 items`, out)
+}
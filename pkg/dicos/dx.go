@@ -24,6 +24,15 @@ type DXImage struct {
 	Detector    *module.DXDetectorModule    // Detector parameters
 	Acquisition *module.DXAcquisitionModule // X-ray acquisition parameters
 
+	// FrameOfReference is shared by images that can be overlaid, such as
+	// the two energies of a DualEnergyDX; nil writes none
+	FrameOfReference *module.FrameOfReferenceModule
+
+	// Object of inspection: the bag, its owner and its routing; nil writes none
+	OOI       *module.OOIModule
+	OOIOwner  *module.OOIOwnerModule
+	Itinerary *module.ItineraryModule
+
 	// Image Attributes
 	InstanceNumber    int
 	ContentDate       module.Date
@@ -38,6 +47,10 @@ type DXImage struct {
 	HighBit           int
 	PixelRepresent    int // 0 unsigned, 1 signed
 
+	// DeriveBitsStored sets BitsStored and HighBit in GetDataset to the
+	// fewest bits that hold the native pixel data, see RequiredBitsStored
+	DeriveBitsStored bool
+
 	// Windowing (legacy - prefer VOILUT module)
 	WindowCenter float64
 	WindowWidth  float64
@@ -45,6 +58,10 @@ type DXImage struct {
 	// DX Specifics
 	PresentationIntentType string // PRESENTATION or PROCESSING
 
+	// Dual energy, see DualEnergyDX
+	EnergyLevel string       // EnergyLow or EnergyHigh, written as SeriesEnergy; empty for single energy
+	EnergyBin   *DXEnergyBin // detector bin of this energy, nil writes none
+
 	// Pixel Data
 	PixelData *PixelData
 	Codec     Codec // nil = uncompressed
@@ -56,7 +73,7 @@ type DXImage struct {
 // NewDXImage creates a new DX Image with default values
 func NewDXImage() *DXImage {
 	t := time.Now()
-	return &DXImage{
+	dx := &DXImage{
 		SamplesPerPixel:        1,
 		PhotometricInterp:      "MONOCHROME2",
 		BitsAllocated:          16,
@@ -75,6 +92,8 @@ func NewDXImage() *DXImage {
 		Acquisition:            module.NewDXAcquisitionModule(),
 		AdditionalTags:         make(map[tag.Tag]interface{}),
 	}
+	fillUIDs(&dx.Study.StudyInstanceUID, &dx.Series.SeriesInstanceUID, &dx.SOPCommon.SOPInstanceUID)
+	return dx
 }
 
 // SetPixelData sets native pixel data for the DX image.
@@ -133,12 +152,12 @@ func (dx *DXImage) GetDataset() (*Dataset, error) {
 
 	sopInstanceUID := dx.SOPCommon.SOPInstanceUID
 	if sopInstanceUID == "" {
-		sopInstanceUID = GenerateUID("1.2.826.0.1.3680043.8.498.")
+		sopInstanceUID = NewUID()
 		dx.SOPCommon.SOPInstanceUID = sopInstanceUID
 	}
-	dx.SOPCommon.SOPClassUID = "1.2.840.10008.5.1.4.1.1.501.2.1"
+	dx.SOPCommon.SOPClassUID = DICOSDXForPresentationUID
 	if dx.Study.StudyInstanceUID == "" {
-		dx.Study.StudyInstanceUID = GenerateUID("1.2.826.0.1.3680043.8.498.")
+		dx.Study.StudyInstanceUID = NewUID()
 	}
 
 	// DX Storage
@@ -158,8 +177,18 @@ func (dx *DXImage) GetDataset() (*Dataset, error) {
 	if dx.Acquisition != nil {
 		opts = append(opts, WithModule(dx.Acquisition.ToTags()))
 	}
+	if dx.FrameOfReference != nil {
+		opts = append(opts, WithModule(dx.FrameOfReference.ToTags()))
+	}
+	opts = append(opts, ooiOptions(dx.OOI, dx.OOIOwner, dx.Itinerary)...)
+	opts = append(opts, energyOptions(dx.EnergyLevel, dx.EnergyBin)...)
 
 	// 3. Image Pixel Module & Common
+	if dx.DeriveBitsStored {
+		if n := requiredBitsStored(dx.PixelData, dx.PixelRepresent == 1); n > 0 {
+			dx.BitsStored, dx.HighBit = n, n-1
+		}
+	}
 	opts = append(opts,
 		WithElement(tag.Rows, dx.Rows),
 		WithElement(tag.Columns, dx.Columns),
@@ -183,10 +212,11 @@ func (dx *DXImage) GetDataset() (*Dataset, error) {
 		opts = append(opts, WithElement(t, v))
 	}
 
-	// 4. Pixel Data
+	// 4. Pixel Data, once the attributes describing it are consistent
+	opts = append(opts, ValidateImagePixel)
 	if dx.Codec != nil && dx.PixelData != nil && !dx.PixelData.IsEncapsulated {
 		flatData := dx.PixelData.GetFlatData()
-		opts = append(opts, WithPixelData(dx.Rows, dx.Columns, dx.BitsAllocated, flatData, dx.Codec))
+		opts = append(opts, WithPixelData(dx.Rows, dx.Columns, dx.BitsAllocated, flatData, dx.Codec), WithFrameMeta(dx.PixelData.FrameMeta()...))
 	} else if dx.PixelData != nil {
 		opts = append(opts, WithRawPixelData(dx.PixelData))
 	}
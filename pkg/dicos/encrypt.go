@@ -0,0 +1,185 @@
+package dicos
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// IdentityTags are the patient and OOI owner attributes that identify a
+// passenger, the default subset for EncryptAttributes
+var IdentityTags = []Tag{
+	tag.PatientName,
+	tag.PatientID,
+	tag.IssuerOfPatientID,
+	tag.PatientBirthDate,
+	tag.PatientSex,
+	tag.PatientAge,
+	tag.PatientComments,
+	tag.OOIOwnerID,
+	tag.OOIOwnerName,
+	tag.OOIOwnerIDType,
+	tag.OOIOwnerCategory,
+}
+
+// DecryptionKey is a recipient of encrypted attributes: its certificate,
+// which names the envelope addressed to it, and the matching private key
+type DecryptionKey struct {
+	Certificate *x509.Certificate
+	Key         crypto.Decrypter // e.g. *rsa.PrivateKey
+}
+
+// EncryptAttributes moves the given attributes of ds, IdentityTags when none
+// are given, into an item of the Encrypted Attributes Sequence (PS3.15
+// Annex E) that only the recipients can read, and empties them in ds so
+// Type 2 attributes stay present. The item holds a CMS envelope of the
+// original values, keyed to each recipient's RSA certificate. Calling it
+// again for other tags and recipients adds another item.
+//
+// Example:
+//
+//	cert, _ := x509.ParseCertificate(der) // the screening authority
+//	if err := dicos.EncryptAttributes(ct, []*x509.Certificate{cert}); err != nil {
+//		return err
+//	}
+//	dicos.WriteFile("bag.dcs", ct)
+func EncryptAttributes(ds *Dataset, recipients []*x509.Certificate, tags ...Tag) error {
+	if len(tags) == 0 {
+		tags = IdentityTags
+	}
+	original := &Dataset{Elements: make(map[Tag]*Element)}
+	for _, t := range tags {
+		if t.Group == tag.EncryptedAttributesSequence.Group || t.Group == 0x0002 || t == pixelDataTag {
+			return fmt.Errorf("cannot encrypt element %v", t)
+		}
+		if elem, ok := ds.Elements[t]; ok {
+			original.Elements[t] = elem
+		}
+	}
+	if len(original.Elements) == 0 {
+		return fmt.Errorf("none of the %d attributes to encrypt are present", len(tags))
+	}
+
+	modified := &Dataset{Elements: map[Tag]*Element{
+		tag.ModifiedAttributesSequence: {Tag: tag.ModifiedAttributesSequence, VR: "SQ", Value: []*Dataset{original}},
+	}}
+	var body bytes.Buffer
+	if _, err := writeDataSetBody(&body, modified, false); err != nil {
+		return err
+	}
+	envelope, err := sealEnvelope(body.Bytes(), recipients)
+	if err != nil {
+		return err
+	}
+	if len(envelope)%2 != 0 {
+		envelope = append(envelope, 0)
+	}
+	item := &Dataset{Elements: make(map[Tag]*Element)}
+	for _, opt := range []Option{
+		withVR(tag.EncryptedContentTransferSyntaxUID, "UI", string(ExplicitVRLittleEndian)),
+		withVR(tag.EncryptedContent, "OB", envelope),
+	} {
+		if err := opt(item); err != nil {
+			return err
+		}
+	}
+
+	var items []*Dataset
+	if elem, ok := ds.Elements[tag.EncryptedAttributesSequence]; ok {
+		items, _ = elem.Value.([]*Dataset)
+	}
+	ds.Elements[tag.EncryptedAttributesSequence] = &Element{
+		Tag: tag.EncryptedAttributesSequence, VR: "SQ", Value: append(slices.Clone(items), item),
+	}
+	for t, elem := range original.Elements {
+		ds.Elements[t] = &Element{Tag: t, VR: elem.VR}
+	}
+	return nil
+}
+
+// DecryptAttributes restores the attributes of every Encrypted Attributes
+// Sequence item addressed to key and returns how many were restored. The
+// sequence is kept, so the dataset now holds the values in the clear as
+// well. It returns ErrNotRecipient when ds has encrypted attributes but none
+// for key, and restores nothing when an envelope cannot be opened.
+func DecryptAttributes(ds *Dataset, key DecryptionKey) (int, error) {
+	if key.Certificate == nil || key.Key == nil {
+		return 0, fmt.Errorf("decryption needs a certificate and its private key")
+	}
+	elem, ok := ds.Elements[tag.EncryptedAttributesSequence]
+	if !ok {
+		return 0, nil
+	}
+	items, _ := elem.Value.([]*Dataset)
+
+	restored := make(map[Tag]*Element)
+	for i, item := range items {
+		if ts := stringValue(item, tag.EncryptedContentTransferSyntaxUID); ts != string(ExplicitVRLittleEndian) {
+			return 0, fmt.Errorf("encrypted attributes item %d: unsupported transfer syntax %q", i, ts)
+		}
+		content, ok := item.Elements[tag.EncryptedContent]
+		if !ok {
+			return 0, fmt.Errorf("encrypted attributes item %d has no encrypted content", i)
+		}
+		der, _ := content.Value.([]byte)
+		body, err := openEnvelope(der, key.Certificate, key.Key)
+		if errors.Is(err, ErrNotRecipient) {
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("encrypted attributes item %d: %w", i, err)
+		}
+		decrypted, err := parseDataSetBody(body)
+		if err != nil {
+			return 0, fmt.Errorf("encrypted attributes item %d: %w", i, err)
+		}
+		mod, ok := decrypted.Elements[tag.ModifiedAttributesSequence]
+		if !ok {
+			return 0, fmt.Errorf("encrypted attributes item %d has no modified attributes", i)
+		}
+		originals, _ := mod.Value.([]*Dataset)
+		if len(originals) != 1 {
+			return 0, fmt.Errorf("encrypted attributes item %d has %d modified attribute items, want 1", i, len(originals))
+		}
+		for t, e := range originals[0].Elements {
+			restored[t] = e
+		}
+	}
+	if len(restored) == 0 && len(items) > 0 {
+		return 0, ErrNotRecipient
+	}
+	for t, e := range restored {
+		ds.Elements[t] = e
+	}
+	return len(restored), nil
+}
+
+// parseDataSetBody reads a dataset encoded in Explicit VR Little Endian
+// without a preamble or file meta group
+func parseDataSetBody(data []byte) (*Dataset, error) {
+	r := NewReader(bytes.NewReader(data))
+	r.transferSyntax = string(ExplicitVRLittleEndian)
+	ds := &Dataset{Elements: make(map[Tag]*Element)}
+	for {
+		t, err := r.readTag()
+		if err == io.EOF {
+			return ds, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tag: %w", err)
+		}
+		elem, err := r.readElementWithTag(t)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read element %v: %w", t, err)
+		}
+		if err := r.put(ds, elem); err != nil {
+			return nil, err
+		}
+	}
+}
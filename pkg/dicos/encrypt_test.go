@@ -0,0 +1,151 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRecipient returns a self-signed RSA certificate and its key
+func testRecipient(t *testing.T, name string, serial int64) DecryptionKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageKeyEncipherment,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return DecryptionKey{Certificate: cert, Key: key}
+}
+
+func identityDataset(t *testing.T) *Dataset {
+	t.Helper()
+	ds, err := NewDataset(
+		WithFileMeta(DICOSCTImageStorageUID, "1.2.3.4", string(ExplicitVRLittleEndian)),
+		WithElement(tag.SOPClassUID, DICOSCTImageStorageUID),
+		WithElement(tag.SOPInstanceUID, "1.2.3.4"),
+		WithElement(tag.PatientName, "DOE^JANE"),
+		WithElement(tag.PatientID, "P-12345"),
+		WithElement(tag.OOIOwnerName, "DOE^JANE"),
+		WithElement(tag.OOIOwnerID, "X1234567"),
+		WithElement(tag.StudyDescription, "CHECKED BAG"),
+	)
+	require.NoError(t, err)
+	return ds
+}
+
+func TestEncryptAttributes_RoundTrip(t *testing.T) {
+	authority, airline := testRecipient(t, "authority", 1), testRecipient(t, "airline", 2)
+	ds := identityDataset(t)
+	require.NoError(t, EncryptAttributes(ds, []*x509.Certificate{authority.Certificate, airline.Certificate}))
+
+	// identity is emptied but present, other attributes are untouched
+	for _, tg := range []Tag{tag.PatientName, tag.PatientID, tag.OOIOwnerName, tag.OOIOwnerID} {
+		require.Contains(t, ds.Elements, tg)
+		assert.Nil(t, ds.Elements[tg].Value, "%v", tg)
+	}
+	assert.Equal(t, "CHECKED BAG", stringValue(ds, tag.StudyDescription))
+	assert.NotContains(t, ds.Elements, tag.PatientBirthDate)
+
+	var buf bytes.Buffer
+	_, err := Write(&buf, ds)
+	require.NoError(t, err)
+	assert.NotContains(t, buf.String(), "X1234567")
+
+	for _, key := range []DecryptionKey{authority, airline} {
+		got, err := ParseWithOptions(context.Background(), bytes.NewReader(buf.Bytes()), ParseOptions{Decryption: &key})
+		require.NoError(t, err)
+		assert.Equal(t, "DOE^JANE", stringValue(got, tag.PatientName))
+		assert.Equal(t, "P-12345", stringValue(got, tag.PatientID))
+		assert.Equal(t, "X1234567", stringValue(got, tag.OOIOwnerID))
+		assert.Contains(t, got.Elements, tag.EncryptedAttributesSequence)
+	}
+
+	// without a key the values stay empty
+	got, err := ParseWithOptions(context.Background(), bytes.NewReader(buf.Bytes()), ParseOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, stringValue(got, tag.PatientID))
+}
+
+func TestDecryptAttributes_NotRecipient(t *testing.T) {
+	authority, stranger := testRecipient(t, "authority", 1), testRecipient(t, "stranger", 7)
+	ds := identityDataset(t)
+	require.NoError(t, EncryptAttributes(ds, []*x509.Certificate{authority.Certificate}))
+
+	n, err := DecryptAttributes(ds, stranger)
+	assert.ErrorIs(t, err, ErrNotRecipient)
+	assert.Zero(t, n)
+
+	// read as is
+	var buf bytes.Buffer
+	_, err = Write(&buf, ds)
+	require.NoError(t, err)
+	got, err := ParseWithOptions(context.Background(), bytes.NewReader(buf.Bytes()), ParseOptions{Decryption: &stranger})
+	require.NoError(t, err)
+	assert.Empty(t, stringValue(got, tag.PatientName))
+
+	// a dataset without encrypted attributes has nothing to restore
+	n, err = DecryptAttributes(identityDataset(t), stranger)
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestEncryptAttributes_SeparateItems(t *testing.T) {
+	authority, airline := testRecipient(t, "authority", 1), testRecipient(t, "airline", 2)
+	ds := identityDataset(t)
+	require.NoError(t, EncryptAttributes(ds, []*x509.Certificate{authority.Certificate}, tag.PatientName, tag.PatientID))
+	require.NoError(t, EncryptAttributes(ds, []*x509.Certificate{airline.Certificate, authority.Certificate}, tag.OOIOwnerID))
+	items, _ := ds.Elements[tag.EncryptedAttributesSequence].Value.([]*Dataset)
+	require.Len(t, items, 2)
+
+	airlineView := CloneDataset(ds)
+	n, err := DecryptAttributes(airlineView, airline)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, "X1234567", stringValue(airlineView, tag.OOIOwnerID))
+	assert.Empty(t, stringValue(airlineView, tag.PatientName))
+
+	n, err = DecryptAttributes(ds, authority)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, "DOE^JANE", stringValue(ds, tag.PatientName))
+}
+
+func TestEncryptAttributes_Errors(t *testing.T) {
+	authority := testRecipient(t, "authority", 1)
+	certs := []*x509.Certificate{authority.Certificate}
+
+	ds := identityDataset(t)
+	assert.ErrorContains(t, EncryptAttributes(ds, certs, tag.PatientBirthDate), "none of the 1 attributes")
+	assert.Error(t, EncryptAttributes(ds, certs, tag.TransferSyntaxUID))
+	assert.ErrorContains(t, EncryptAttributes(ds, nil), "no recipient")
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{SerialNumber: big.NewInt(3), Subject: pkix.Name{CommonName: "ec"}}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	ec, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	assert.ErrorContains(t, EncryptAttributes(ds, []*x509.Certificate{ec}), "only RSA")
+	assert.Equal(t, "P-12345", stringValue(ds, tag.PatientID), "a failed encryption changes nothing")
+}
@@ -0,0 +1,191 @@
+package dicos_test
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// exampleCT writes a small two-slice CT scan, as a scanner would hand it over
+func exampleCT(codec dicos.Codec) []byte {
+	const rows, cols = 16, 16
+	data := make([]uint16, 2*rows*cols)
+	for i := range data {
+		data[i] = uint16(1000 + i%cols*10 + i/(rows*cols)*100)
+	}
+	ct := dicos.NewCTImage()
+	ct.SOPCommon.SOPClassUID = dicos.DICOSCTImageStorageUID
+	ct.Series.Modality = "CT"
+	ct.Patient.PatientID = "BAG-0001"
+	ct.Rows, ct.Columns = rows, cols
+	ct.SetPixelData(rows, cols, data)
+	ct.Codec = codec
+	var buf bytes.Buffer
+	if _, err := ct.WriteTo(&buf); err != nil {
+		log.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// Read a scan and decode its frames to a volume
+func Example_readAndDecodeVolume() {
+	ds, err := dicos.Parse(bytes.NewReader(exampleCT(dicos.CodecJPEGLS)))
+	if err != nil {
+		log.Fatal(err)
+	}
+	vol, err := dicos.DecodeVolume(ds)
+	if err != nil {
+		log.Fatal(err)
+	}
+	lo, hi := vol.MinMax()
+	fmt.Println("transfer syntax:", ds.TransferSyntax().Name())
+	fmt.Printf("volume: %dx%dx%d\n", vol.Width, vol.Height, vol.Depth)
+	fmt.Println("range:", lo, hi)
+	// Output:
+	// transfer syntax: JPEG-LS Lossless
+	// volume: 16x16x2
+	// range: 1000 1250
+}
+
+// Build a compressed CT image and check what was written
+func Example_buildCompressedCT() {
+	const rows, cols = 64, 64
+	data := make([]uint16, rows*cols)
+	for i := range data {
+		data[i] = uint16(i % cols * 16)
+	}
+	ct := dicos.NewCTImage()
+	ct.SOPCommon.SOPClassUID = dicos.DICOSCTImageStorageUID
+	ct.Series.SeriesDescription = "Checkpoint 3, lane 2"
+	ct.Rows, ct.Columns = rows, cols
+	ct.SetPixelData(rows, cols, data)
+	ct.Codec = dicos.CodecJPEGLS
+
+	var buf bytes.Buffer
+	if _, err := ct.WriteTo(&buf); err != nil {
+		log.Fatal(err)
+	}
+	ds, err := dicos.Parse(&buf)
+	if err != nil {
+		log.Fatal(err)
+	}
+	pd, err := ds.GetPixelData()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("encapsulated:", pd.IsEncapsulated, "frames:", len(pd.Frames))
+	fmt.Println("compressed below raw size:", len(pd.Frames[0].CompressedData) < rows*cols*2)
+	// Output:
+	// encapsulated: true frames: 1
+	// compressed below raw size: true
+}
+
+// Report a threat found in a scan
+func Example_buildTDR() {
+	tdr := dicos.NewThreatDetectionReport()
+	tdr.AlarmDecision = "ALARM"
+	tdr.ReferencedSOPClassUID = dicos.DICOSCTImageStorageUID
+	tdr.ReferencedSOPInstanceUID = "1.2.3.4.1"
+	tdr.PTOs = []dicos.PotentialThreatObject{{
+		ID:          1,
+		Label:       "KNIFE",
+		OOIType:     "KNIFE",
+		Probability: 0.9,
+		BoundingBox: &dicos.BoundingBox{TopLeft: [3]float32{10, 20, 0}, BottomRight: [3]float32{40, 60, 1}},
+	}}
+	ds, err := tdr.GetDataset()
+	if err != nil {
+		log.Fatal(err)
+	}
+	got, err := dicos.ParseTDR(ds)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(got.AlarmDecision, len(got.PTOs), got.PTOs[0].Label)
+	// Output:
+	// ALARM 1 KNIFE
+}
+
+// Validate a dataset against the CT IOD
+func Example_validate() {
+	ds, err := dicos.Parse(bytes.NewReader(exampleCT(nil)))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("valid:", dicos.ValidateCT(ds).IsValid())
+
+	delete(ds.Elements, tag.SOPInstanceUID)
+	result := dicos.ValidateCT(ds)
+	fmt.Println("valid:", result.IsValid())
+	for _, e := range result.CriticalErrors() {
+		fmt.Println(e)
+	}
+	// Output:
+	// valid: true
+	// valid: false
+	// (0008,0018) Type 1: Required attribute missing
+}
+
+// Transcode a scan to RLE and prove that no pixel changed
+func Example_transcode() {
+	original, err := dicos.Parse(bytes.NewReader(exampleCT(dicos.CodecJPEGLS)))
+	if err != nil {
+		log.Fatal(err)
+	}
+	vol, err := dicos.DecodeVolume(original)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ct, err := dicos.ParseCT(original)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ct.SetPixelData(vol.Height, vol.Width, vol.Data)
+	ct.Codec = dicos.CodecRLE
+	var buf bytes.Buffer
+	if _, err := ct.WriteTo(&buf); err != nil {
+		log.Fatal(err)
+	}
+
+	transcoded, err := dicos.Parse(&buf)
+	if err != nil {
+		log.Fatal(err)
+	}
+	out, err := dicos.DecodeVolume(transcoded)
+	if err != nil {
+		log.Fatal(err)
+	}
+	diff, err := dicos.CompareFrames(vol.Data, out.Data, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(transcoded.TransferSyntax().Name())
+	fmt.Println("lossless:", diff.Lossless())
+	// Output:
+	// RLE Lossless
+	// lossless: true
+}
+
+// De-identify a scan before it leaves the checkpoint
+func Example_anonymize() {
+	ds, err := dicos.Parse(bytes.NewReader(exampleCT(nil)))
+	if err != nil {
+		log.Fatal(err)
+	}
+	anon, _, err := dicos.Anonymize(ds, dicos.AnonymizeOptions{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	id, _ := anon.Elements[tag.PatientIdentityRemoved].GetString()
+	fmt.Println("identity removed:", id)
+	patientID, _ := anon.Elements[tag.PatientID].GetString()
+	fmt.Printf("patient ID: %q\n", patientID)
+	fmt.Println("same instance UID:", anon.Elements[tag.SOPInstanceUID].Value == ds.Elements[tag.SOPInstanceUID].Value)
+	// Output:
+	// identity removed: YES
+	// patient ID: "ANONYMOUS"
+	// same instance UID: false
+}
@@ -0,0 +1,196 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"log/slog"
+	"math"
+)
+
+// ExportFormat is the image format a frame is exported to
+type ExportFormat string
+
+const (
+	ExportAuto  ExportFormat = ""      // chosen by NegotiateExportFormat
+	ExportPNG16 ExportFormat = "png16" // 16-bit grayscale PNG of the stored values, or windowed
+	ExportPNG8  ExportFormat = "png8"  // 8-bit grayscale PNG, windowed unless the source fits in 8 bits
+	ExportTIFF  ExportFormat = "tiff"  // 32-bit float TIFF of the rescaled (modality) values
+	ExportJP2   ExportFormat = "jp2"   // lossless JPEG 2000 in a JP2 file of the stored values
+)
+
+// ExportFormatByName parses an export format name; "auto" and "" select ExportAuto
+func ExportFormatByName(name string) (ExportFormat, error) {
+	switch f := ExportFormat(name); f {
+	case "auto", ExportAuto:
+		return ExportAuto, nil
+	case ExportPNG16, ExportPNG8, ExportTIFF, ExportJP2:
+		return f, nil
+	}
+	return ExportAuto, fmt.Errorf("unknown export format %q (auto, png16, png8, tiff, jp2)", name)
+}
+
+// Ext returns the file extension for the format, including the dot
+func (f ExportFormat) Ext() string {
+	switch f {
+	case ExportTIFF:
+		return ".tiff"
+	case ExportJP2:
+		return ".jp2"
+	}
+	return ".png"
+}
+
+// ExportOptions controls ExportFrame
+type ExportOptions struct {
+	Format ExportFormat // ExportAuto negotiates from the source
+	// Window windows ExportPNG8, where nil uses WindowFromDataset, and
+	// ExportPNG16, where nil writes the stored values
+	Window *Window
+}
+
+// NegotiateExportFormat picks the output format that keeps every value of
+// the dataset's frames: unsigned samples without a rescale export as PNG,
+// 8-bit when BitsStored fits and 16-bit otherwise, while signed or rescaled
+// samples export as float TIFF so modality values such as HU survive.
+func NegotiateExportFormat(ds *Dataset) ExportFormat {
+	intercept, slope := GetRescale(ds)
+	if slope != 1 || intercept != 0 || ds.PixelRepresentation() == 1 {
+		return ExportTIFF
+	}
+	if ds.BitsStored() <= 8 {
+		return ExportPNG8
+	}
+	return ExportPNG16
+}
+
+// ExportFrame decodes one grayscale frame and writes it to w in opts.Format,
+// or the negotiated format when none is given. The format written is
+// returned so callers can name the output. Forcing ExportPNG8 on data with
+// more than 8 bits stored windows it and is logged as a warning; a window
+// given for ExportPNG16 maps it onto the full 16-bit range.
+//
+// Example:
+//
+//	f, _ := os.Create("slice.out")
+//	format, err := dicos.ExportFrame(ctx, ds, 0, f, dicos.ExportOptions{})
+//	// rename to "slice" + format.Ext()
+func ExportFrame(ctx context.Context, ds *Dataset, frame int, w io.Writer, opts ExportOptions) (ExportFormat, error) {
+	if spp := ds.SamplesPerPixel(); spp != 1 {
+		return "", fmt.Errorf("exporting %d samples per pixel is not supported", spp)
+	}
+	rows, cols := ds.Rows(), ds.Columns()
+	if rows == 0 || cols == 0 {
+		return "", fmt.Errorf("invalid image dimensions: %dx%d", cols, rows)
+	}
+	pd, err := ds.GetPixelDataContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	data, err := DecodeFrameDataContext(ctx, pd, frame, rows, cols, ds.TransferSyntax())
+	if err != nil {
+		return "", err
+	}
+
+	format := opts.Format
+	if format == ExportAuto {
+		format = NegotiateExportFormat(ds)
+	}
+	bitsStored := ds.BitsStored()
+	switch format {
+	case ExportPNG16:
+		if opts.Window != nil {
+			img, err := opts.Window.Render16(data, rows, cols)
+			if err != nil {
+				return "", err
+			}
+			return format, png.Encode(w, img)
+		}
+		img := image.NewGray16(image.Rect(0, 0, cols, rows))
+		for i := range rows * cols {
+			binary.BigEndian.PutUint16(img.Pix[i*2:], data[i])
+		}
+		return format, png.Encode(w, img)
+	case ExportPNG8:
+		var img *image.Gray
+		if opts.Window == nil && NegotiateExportFormat(ds) == ExportPNG8 {
+			img = image.NewGray(image.Rect(0, 0, cols, rows))
+			for i := range rows * cols {
+				img.Pix[i] = uint8(data[i])
+			}
+		} else {
+			if bitsStored > 8 {
+				logger(ctx).WarnContext(ctx, "Exporting high bit-depth frame as windowed 8-bit",
+					slog.Int("frame", frame), slog.Int("bitsStored", bitsStored))
+			}
+			win := WindowFromDataset(ds)
+			if opts.Window != nil {
+				win = *opts.Window
+			}
+			if img, err = win.Render(data, rows, cols); err != nil {
+				return "", err
+			}
+		}
+		return format, png.Encode(w, img)
+	case ExportTIFF:
+		win := WindowFromDataset(ds)
+		values := make([]float32, rows*cols)
+		for i := range values {
+			values[i] = float32(win.Modality(data[i]))
+		}
+		return format, writeFloatTIFF(w, values, rows, cols)
+	case ExportJP2:
+		var cs bytes.Buffer
+		if err := CodecJPEG2000.(SampleEncoder).EncodeSamples(&cs, data, cols, rows, sampleFormat(ds, ds.BitsAllocated())); err != nil {
+			return "", err
+		}
+		return format, WriteJP2(w, cs.Bytes())
+	}
+	return "", fmt.Errorf("unknown export format %q", format)
+}
+
+// writeFloatTIFF writes a little-endian, uncompressed, single-strip TIFF of
+// 32-bit IEEE float grayscale samples
+func writeFloatTIFF(w io.Writer, values []float32, rows, cols int) error {
+	type entry struct{ tag, typ, value uint32 }
+	const (
+		tiffShort = 3
+		tiffLong  = 4
+	)
+	stripBytes := uint32(len(values) * 4)
+	entries := []entry{
+		{256, tiffLong, uint32(cols)}, // ImageWidth
+		{257, tiffLong, uint32(rows)}, // ImageLength
+		{258, tiffShort, 32},          // BitsPerSample
+		{259, tiffShort, 1},           // Compression: none
+		{262, tiffShort, 1},           // PhotometricInterpretation: BlackIsZero
+		{273, tiffLong, 0},            // StripOffsets, set below
+		{277, tiffShort, 1},           // SamplesPerPixel
+		{278, tiffLong, uint32(rows)}, // RowsPerStrip
+		{279, tiffLong, stripBytes},   // StripByteCounts
+		{339, tiffShort, 3},           // SampleFormat: IEEE float
+	}
+	ifdSize := 2 + len(entries)*12 + 4
+	entries[5].value = uint32(8 + ifdSize)
+
+	b := make([]byte, 0, 8+ifdSize+int(stripBytes))
+	b = append(b, 'I', 'I', 42, 0)
+	b = binary.LittleEndian.AppendUint32(b, 8)
+	b = binary.LittleEndian.AppendUint16(b, uint16(len(entries)))
+	for _, e := range entries {
+		b = binary.LittleEndian.AppendUint16(b, uint16(e.tag))
+		b = binary.LittleEndian.AppendUint16(b, uint16(e.typ))
+		b = binary.LittleEndian.AppendUint32(b, 1)
+		b = binary.LittleEndian.AppendUint32(b, e.value) // SHORTs are left-justified
+	}
+	b = binary.LittleEndian.AppendUint32(b, 0) // no further IFDs
+	for _, v := range values {
+		b = binary.LittleEndian.AppendUint32(b, math.Float32bits(v))
+	}
+	_, err := w.Write(b)
+	return err
+}
@@ -0,0 +1,141 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"image/png"
+	"math"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exportDataset returns a 4x4 single-frame dataset with the given sample format
+func exportDataset(t *testing.T, bitsAllocated, bitsStored, pixelRep int, data []uint16, extra ...Option) *Dataset {
+	t.Helper()
+	opts := append([]Option{
+		WithElement(tag.Rows, 4),
+		WithElement(tag.Columns, 4),
+		WithElement(tag.SamplesPerPixel, 1),
+		WithElement(tag.BitsAllocated, bitsAllocated),
+		WithElement(tag.BitsStored, bitsStored),
+		WithElement(tag.PixelRepresentation, pixelRep),
+		WithPixelData(4, 4, bitsAllocated, data, nil),
+	}, extra...)
+	ds, err := NewDataset(opts...)
+	require.NoError(t, err)
+	return ds
+}
+
+func ramp(n int, step uint16) []uint16 {
+	data := make([]uint16, n)
+	for i := range data {
+		data[i] = uint16(i) * step
+	}
+	return data
+}
+
+func TestNegotiateExportFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		ds   *Dataset
+		want ExportFormat
+	}{
+		{"8-bit", exportDataset(t, 8, 8, 0, ramp(16, 1)), ExportPNG8},
+		{"12-bit unsigned", exportDataset(t, 16, 12, 0, ramp(16, 200)), ExportPNG16},
+		{"signed", exportDataset(t, 16, 16, 1, ramp(16, 1)), ExportTIFF},
+		{"rescaled", exportDataset(t, 16, 12, 0, ramp(16, 1), WithElement(tag.RescaleIntercept, "-1024")), ExportTIFF},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NegotiateExportFormat(tt.ds))
+		})
+	}
+}
+
+func TestExportFrame_PNG16(t *testing.T) {
+	data := ramp(16, 250) // up to 3750, beyond 8 bits
+	ds := exportDataset(t, 16, 12, 0, data)
+
+	var buf bytes.Buffer
+	format, err := ExportFrame(context.Background(), ds, 0, &buf, ExportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, ExportPNG16, format)
+
+	img, err := png.Decode(&buf)
+	require.NoError(t, err)
+	gray, ok := img.(*image.Gray16)
+	require.True(t, ok, "decoded %T", img)
+	for i, v := range data {
+		assert.Equal(t, v, gray.Gray16At(i%4, i/4).Y)
+	}
+}
+
+func TestExportFrame_WindowedPNG16(t *testing.T) {
+	ds := exportDataset(t, 16, 12, 0, ramp(16, 250))
+
+	var buf bytes.Buffer
+	format, err := ExportFrame(context.Background(), ds, 0, &buf, ExportOptions{
+		Format: ExportPNG16,
+		Window: &Window{Center: 1000, Width: 1000},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ExportPNG16, format)
+
+	img, err := png.Decode(&buf)
+	require.NoError(t, err)
+	gray, ok := img.(*image.Gray16)
+	require.True(t, ok, "decoded %T", img)
+	assert.Equal(t, uint16(0), gray.Gray16At(0, 0).Y)     // 0, below the window
+	assert.Equal(t, uint16(32767), gray.Gray16At(0, 1).Y) // 1000, the center
+	assert.Equal(t, uint16(65535), gray.Gray16At(3, 3).Y) // 3750, above the window
+}
+
+func TestExportFrame_TIFF(t *testing.T) {
+	data := make([]uint16, 16)
+	for i := range data {
+		data[i] = uint16(int16(i*100 - 800))
+	}
+	ds := exportDataset(t, 16, 16, 1, data,
+		WithElement(tag.RescaleIntercept, "-1024"),
+		WithElement(tag.RescaleSlope, "0.5"))
+
+	var buf bytes.Buffer
+	format, err := ExportFrame(context.Background(), ds, 0, &buf, ExportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, ExportTIFF, format)
+
+	b := buf.Bytes()
+	require.Equal(t, []byte{'I', 'I', 42, 0}, b[:4])
+	pixels := b[len(b)-16*4:]
+	for i := range data {
+		want := float32(float64(i*100-800)*0.5 - 1024)
+		assert.Equal(t, want, math.Float32frombits(binary.LittleEndian.Uint32(pixels[i*4:])), "pixel %d", i)
+	}
+}
+
+func TestExportFrame_ForcePNG8(t *testing.T) {
+	ds := exportDataset(t, 16, 12, 0, ramp(16, 250))
+
+	var buf bytes.Buffer
+	format, err := ExportFrame(context.Background(), ds, 0, &buf, ExportOptions{
+		Format: ExportPNG8,
+		Window: &Window{Center: 1875, Width: 3750},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ExportPNG8, format)
+
+	img, err := png.Decode(&buf)
+	require.NoError(t, err)
+	gray, ok := img.(*image.Gray)
+	require.True(t, ok, "decoded %T", img)
+	assert.Equal(t, uint8(0), gray.GrayAt(0, 0).Y)
+	assert.Equal(t, uint8(255), gray.GrayAt(3, 3).Y)
+
+	_, err = ExportFormatByName("jpeg")
+	assert.ErrorContains(t, err, "unknown export format")
+}
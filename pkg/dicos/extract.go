@@ -0,0 +1,164 @@
+package dicos
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// Extractor derives named fields from a dataset for indexes and reports,
+// e.g. bag throughput metrics or decoded vendor private tags. Sites add their
+// own with RegisterExtractor instead of forking the CLI.
+type Extractor interface {
+	// Name identifies the extractor and prefixes its fields
+	Name() string
+	// Extract returns the derived fields; keys absent from ds are omitted
+	Extract(ds *Dataset) map[string]any
+}
+
+// ExtractorFunc adapts a function to an Extractor
+type ExtractorFunc struct {
+	ExtractorName string
+	Fn            func(ds *Dataset) map[string]any
+}
+
+// Name returns the extractor name
+func (f ExtractorFunc) Name() string { return f.ExtractorName }
+
+// Extract calls the function
+func (f ExtractorFunc) Extract(ds *Dataset) map[string]any { return f.Fn(ds) }
+
+var (
+	extractorsMu sync.RWMutex
+	// extractors starts with the built-in image and acquisition extractors
+	extractors = map[string]Extractor{
+		"image":       ExtractorFunc{ExtractorName: "image", Fn: extractImage},
+		"acquisition": ExtractorFunc{ExtractorName: "acquisition", Fn: extractAcquisition},
+	}
+)
+
+// RegisterExtractor makes an extractor available by name. Register from main
+// or a setup function before extracting, rather than from init. It panics if
+// the name is empty or already registered.
+//
+// Example:
+//
+//	func main() {
+//		dicos.RegisterExtractor(dicos.ExtractorFunc{
+//			ExtractorName: "acme",
+//			Fn: func(ds *dicos.Dataset) map[string]any {
+//				if e, ok := ds.FindElement(0x0009, 0x1010); ok {
+//					return map[string]any{"belt_speed": e.Value}
+//				}
+//				return nil
+//			},
+//		})
+//		...
+//	}
+func RegisterExtractor(e Extractor) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	if e == nil || e.Name() == "" {
+		panic("dicos: RegisterExtractor with nil or unnamed extractor")
+	}
+	if _, dup := extractors[e.Name()]; dup {
+		panic("dicos: RegisterExtractor called twice for " + e.Name())
+	}
+	extractors[e.Name()] = e
+}
+
+// ExtractorByName returns a registered extractor, or nil
+func ExtractorByName(name string) Extractor {
+	extractorsMu.RLock()
+	defer extractorsMu.RUnlock()
+	return extractors[name]
+}
+
+// ExtractorNames returns the names of the registered extractors in order
+func ExtractorNames() []string {
+	extractorsMu.RLock()
+	defer extractorsMu.RUnlock()
+	names := make([]string, 0, len(extractors))
+	for name := range extractors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ExtractFields runs the named extractors, or all registered extractors when
+// no names are given, and merges their fields keyed "extractor.field". An
+// extractor that panics is reported as an error without losing the fields
+// of the others.
+func ExtractFields(ds *Dataset, names ...string) (map[string]any, error) {
+	if len(names) == 0 {
+		names = ExtractorNames()
+	}
+	fields := map[string]any{}
+	var errs []error
+	for _, name := range names {
+		e := ExtractorByName(name)
+		if e == nil {
+			errs = append(errs, fmt.Errorf("unknown extractor %q", name))
+			continue
+		}
+		out, err := runExtractor(e, ds)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for k, v := range out {
+			fields[name+"."+k] = v
+		}
+	}
+	return fields, errors.Join(errs...)
+}
+
+func runExtractor(e Extractor, ds *Dataset) (out map[string]any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("extractor %s panicked: %v", e.Name(), r)
+		}
+	}()
+	return e.Extract(ds), nil
+}
+
+// extractImage reports the image geometry
+func extractImage(ds *Dataset) map[string]any {
+	out := map[string]any{
+		"modality": ds.Modality(),
+		"rows":     ds.Rows(),
+		"columns":  ds.Columns(),
+		"frames":   ds.NumberOfFrames(),
+	}
+	if sp := GetCalibratedSpacing(ds); sp.Source != SpacingDefault {
+		out["pixel_spacing_mm"] = []float64{sp.Row, sp.Col}
+	}
+	return out
+}
+
+// extractAcquisition reports the acquisition parameters present, in their
+// standard units
+func extractAcquisition(ds *Dataset) map[string]any {
+	out := map[string]any{}
+	for key, t := range map[string]Tag{
+		"slice_thickness_mm": tag.SliceThickness,
+		"kvp_kv":             tag.KVP,
+		"lower_energy_kev":   tag.LowerEnergy,
+		"higher_energy_kev":  tag.HigherEnergy,
+	} {
+		if q, ok := GetQuantity(ds, t); ok {
+			out[key] = q.Value
+		}
+	}
+	if q, ok := GetTubeCurrent(ds); ok {
+		out["tube_current_ma"] = q.Value
+	}
+	if q, ok := GetExposureTime(ds); ok {
+		out["exposure_time_ms"] = q.Value
+	}
+	return out
+}
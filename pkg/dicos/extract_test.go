@@ -0,0 +1,48 @@
+package dicos
+
+import (
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractFields(t *testing.T) {
+	RegisterExtractor(ExtractorFunc{ExtractorName: "test-vendor", Fn: func(ds *Dataset) map[string]any {
+		if e, ok := ds.FindElement(0x0009, 0x1010); ok {
+			return map[string]any{"belt_speed": e.Value}
+		}
+		return nil
+	}})
+	RegisterExtractor(ExtractorFunc{ExtractorName: "test-broken", Fn: func(ds *Dataset) map[string]any {
+		panic("boom")
+	}})
+	defer func() {
+		extractorsMu.Lock()
+		delete(extractors, "test-vendor")
+		delete(extractors, "test-broken")
+		extractorsMu.Unlock()
+	}()
+	assert.Panics(t, func() { RegisterExtractor(ExtractorFunc{ExtractorName: "image"}) }, "duplicate name")
+
+	ds, err := NewDataset(
+		WithElement(tag.Modality, "CT"),
+		WithElement(tag.SliceThickness, "1.25"),
+		WithElement(tag.New(0x0009, 0x1010), "0.5"),
+	)
+	require.NoError(t, err)
+
+	fields, err := ExtractFields(ds, "image", "acquisition", "test-vendor")
+	require.NoError(t, err)
+	assert.Equal(t, "CT", fields["image.modality"])
+	assert.Equal(t, 1.25, fields["acquisition.slice_thickness_mm"])
+	assert.Equal(t, "0.5", fields["test-vendor.belt_speed"])
+
+	fields, err = ExtractFields(ds)
+	assert.ErrorContains(t, err, "test-broken panicked")
+	assert.Contains(t, fields, "test-vendor.belt_speed", "others still run")
+
+	_, err = ExtractFields(ds, "missing")
+	assert.Error(t, err)
+}
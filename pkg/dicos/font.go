@@ -0,0 +1,60 @@
+package dicos
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+)
+
+// glyphs is a 3x5 bitmap font for burning annotations into rendered frames.
+// Each glyph is five rows, top to bottom; bit 2 is the leftmost column.
+var glyphs = map[rune][5]uint8{
+	'0': {7, 5, 5, 5, 7}, '1': {2, 6, 2, 2, 7}, '2': {7, 1, 7, 4, 7}, '3': {7, 1, 7, 1, 7},
+	'4': {5, 5, 7, 1, 1}, '5': {7, 4, 7, 1, 7}, '6': {7, 4, 7, 5, 7}, '7': {7, 1, 1, 1, 1},
+	'8': {7, 5, 7, 5, 7}, '9': {7, 5, 7, 1, 7},
+	'A': {2, 5, 7, 5, 5}, 'B': {6, 5, 6, 5, 6}, 'C': {3, 4, 4, 4, 3}, 'D': {6, 5, 5, 5, 6},
+	'E': {7, 4, 6, 4, 7}, 'F': {7, 4, 6, 4, 4}, 'G': {3, 4, 5, 5, 3}, 'H': {5, 5, 7, 5, 5},
+	'I': {7, 2, 2, 2, 7}, 'J': {1, 1, 1, 5, 2}, 'K': {5, 5, 6, 5, 5}, 'L': {4, 4, 4, 4, 7},
+	'M': {5, 7, 7, 5, 5}, 'N': {6, 5, 5, 5, 5}, 'O': {2, 5, 5, 5, 2}, 'P': {6, 5, 6, 4, 4},
+	'Q': {2, 5, 5, 6, 3}, 'R': {6, 5, 6, 5, 5}, 'S': {3, 4, 2, 1, 6}, 'T': {7, 2, 2, 2, 2},
+	'U': {5, 5, 5, 5, 7}, 'V': {5, 5, 5, 5, 2}, 'W': {5, 5, 7, 7, 5}, 'X': {5, 5, 2, 5, 5},
+	'Y': {5, 5, 2, 2, 2}, 'Z': {7, 1, 2, 4, 7},
+	':': {0, 2, 0, 2, 0}, '/': {1, 1, 2, 4, 4}, '.': {0, 0, 0, 0, 2}, '-': {0, 0, 7, 0, 0},
+	'=': {0, 7, 0, 7, 0}, '#': {5, 7, 5, 7, 5}, '(': {1, 2, 2, 2, 1}, ')': {4, 2, 2, 2, 4},
+	'%': {5, 1, 2, 4, 5}, ',': {0, 0, 0, 2, 4},
+}
+
+// drawText burns text into img at (x, y) with each font pixel drawn as a
+// scale x scale block, over a background box for legibility. Characters
+// without a glyph render as spaces; letters are drawn in upper case.
+func drawText(img draw.Image, x, y, scale int, text string, fg, bg color.Color) {
+	text = strings.ToUpper(text)
+	advance := 4 * scale
+	box := image.Rect(x-scale, y-scale, x+len(text)*advance, y+6*scale).Intersect(img.Bounds())
+	for py := box.Min.Y; py < box.Max.Y; py++ {
+		for px := box.Min.X; px < box.Max.X; px++ {
+			img.Set(px, py, bg)
+		}
+	}
+
+	for i, r := range text {
+		g, ok := glyphs[r]
+		if !ok {
+			continue
+		}
+		ox := x + i*advance
+		for row, bits := range g {
+			for col := 0; col < 3; col++ {
+				if bits&(4>>col) == 0 {
+					continue
+				}
+				for dy := 0; dy < scale; dy++ {
+					for dx := 0; dx < scale; dx++ {
+						img.Set(ox+col*scale+dx, y+row*scale+dy, fg)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package dicos
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/transfer"
+)
+
+// FrameReader decodes single frames of a DICOS file by seeking to them, so
+// a viewer showing slice 742 of a CT never loads the other slices. The
+// elements before the pixel data are parsed once when it is opened.
+//
+// Encapsulated frames are located with the Basic Offset Table when it has an
+// entry per frame. Without one, the item headers are scanned once, seeking
+// past the fragments, to build an offset table of the fragments. Native
+// frames are located from the image size.
+//
+// Example:
+//
+//	fr, err := dicos.OpenFrameReader("scan.dcs")
+//	if err != nil {
+//		return err
+//	}
+//	defer fr.Close()
+//	slice, err := fr.Frame(742)
+type FrameReader struct {
+	mu     sync.Mutex // guards the position of src
+	src    io.ReadSeeker
+	closer io.Closer
+	ds     *Dataset
+	ts     TransferSyntax
+
+	rows, cols, samples int
+	bitsStored          int // sign extended from, 16 for unsigned samples
+
+	// native frames are frameSize bytes each from valueStart
+	valueStart, frameSize int64
+	// encapsulated frames are the fragments of spans
+	spans []frameSpan
+}
+
+// frameSpan is the byte range of the items of one encapsulated frame. An end
+// of -1 runs to the sequence delimiter.
+type frameSpan struct {
+	start, end int64
+}
+
+// OpenFrameReader opens path for reading single frames. Close releases it.
+func OpenFrameReader(path string) (*FrameReader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening file: %w", err)
+	}
+	fr, err := NewFrameReader(context.Background(), f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	fr.closer = f
+	return fr, nil
+}
+
+// NewFrameReader reads the elements of the DICOS file in r up to its pixel
+// data and locates the frames. r must stay usable while frames are read.
+func NewFrameReader(ctx context.Context, r io.ReadSeeker) (*FrameReader, error) {
+	base, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	reader := NewReader(r)
+	reader.ctx = ctx
+	reader.opts = ParseOptions{SkipPixelData: true}
+	ds, err := reader.ReadDataset()
+	if err != nil {
+		return nil, err
+	}
+	ts := ds.TransferSyntax()
+	if ts == transfer.DeflatedExplicitVR || ts == transfer.ExplicitVRBigEndian {
+		return nil, fmt.Errorf("cannot seek to frames in %s", ts.Name())
+	}
+	_, vl, err := reader.readElementHeader(pixelDataTag)
+	if err != nil {
+		return nil, fmt.Errorf("no pixel data element found")
+	}
+
+	fr := &FrameReader{
+		src:        r,
+		ds:         ds,
+		ts:         ts,
+		rows:       GetRows(ds),
+		cols:       GetColumns(ds),
+		samples:    max(ds.SamplesPerPixel(), 1),
+		bitsStored: 16,
+		valueStart: base + reader.cr.n,
+	}
+	if fr.rows == 0 || fr.cols == 0 {
+		return nil, fmt.Errorf("invalid dimensions: %dx%d", fr.cols, fr.rows)
+	}
+	if ds.PixelRepresentation() == 1 {
+		fr.bitsStored = ds.BitsStored()
+	}
+	frames := max(ds.NumberOfFrames(), 1)
+
+	if vl != undefinedLength {
+		bytesPerSample := (ds.BitsAllocated() + 7) / 8
+		if bytesPerSample != 1 && bytesPerSample != 2 {
+			return nil, fmt.Errorf("%w: %d bits allocated", ErrUnsupportedPixelFormat, ds.BitsAllocated())
+		}
+		fr.frameSize = int64(fr.rows * fr.cols * fr.samples * bytesPerSample)
+		if have := int64(vl) / fr.frameSize; have < int64(frames) {
+			return nil, fmt.Errorf("pixel data truncated: %d bytes hold %d of %d frames", vl, have, frames)
+		}
+		fr.spans = make([]frameSpan, frames)
+		return fr, nil
+	}
+	if fr.spans, err = fr.locateFrames(frames); err != nil {
+		return nil, err
+	}
+	return fr, nil
+}
+
+// locateFrames returns the spans of the encapsulated frames, which start
+// with the Basic Offset Table item at valueStart
+func (fr *FrameReader) locateFrames(frames int) ([]frameSpan, error) {
+	if _, err := fr.src.Seek(fr.valueStart, io.SeekStart); err != nil {
+		return nil, err
+	}
+	t, length, err := readItemHeader(fr.src)
+	if err != nil || t != itemTag || length == undefinedLength {
+		return nil, fmt.Errorf("encapsulated pixel data has no offset table item")
+	}
+	bot, err := readBytes(fr.src, int64(length))
+	if err != nil {
+		return nil, fmt.Errorf("reading basic offset table: %w", err)
+	}
+	first := fr.valueStart + 8 + int64(length)
+
+	if offsets, ok := parseOffsetTable(bot); ok && len(offsets) == frames {
+		spans := make([]frameSpan, frames)
+		for i := range spans {
+			spans[i] = frameSpan{start: first + int64(offsets[i]), end: -1}
+			if i+1 < frames {
+				spans[i].end = first + int64(offsets[i+1])
+			}
+		}
+		return spans, nil
+	}
+
+	// build the table from the item headers, seeking past each fragment
+	var fragments []frameSpan
+	pos := first
+	for {
+		t, length, err := readItemHeader(fr.src)
+		if err != nil {
+			return nil, fmt.Errorf("scanning encapsulated pixel data: %w", err)
+		}
+		if t == seqDelimTag {
+			break
+		}
+		if t != itemTag || length == undefinedLength {
+			return nil, fmt.Errorf("unexpected tag %v in encapsulated pixel data", t)
+		}
+		if pos, err = fr.src.Seek(int64(length), io.SeekCurrent); err != nil {
+			return nil, err
+		}
+		fragments = append(fragments, frameSpan{start: pos - int64(length) - 8, end: pos})
+	}
+	switch {
+	case len(fragments) == frames:
+		return fragments, nil
+	case frames == 1 && len(fragments) > 0:
+		return []frameSpan{{start: fragments[0].start, end: -1}}, nil
+	}
+	return nil, fmt.Errorf("%d fragments for %d frames and no offset table", len(fragments), frames)
+}
+
+// readItemHeader reads the tag and length of an item or delimiter
+func readItemHeader(r io.Reader) (Tag, uint32, error) {
+	var h [8]byte
+	if _, err := io.ReadFull(r, h[:]); err != nil {
+		return Tag{}, 0, err
+	}
+	t := Tag{Group: binary.LittleEndian.Uint16(h[0:]), Element: binary.LittleEndian.Uint16(h[2:])}
+	return t, binary.LittleEndian.Uint32(h[4:]), nil
+}
+
+// Dataset returns the elements of the file without the pixel data
+func (fr *FrameReader) Dataset() *Dataset {
+	return fr.ds
+}
+
+// NumFrames returns the number of frames
+func (fr *FrameReader) NumFrames() int {
+	return len(fr.spans)
+}
+
+// Frame decodes frame n, as DecodeSlices would: signed samples are sign
+// extended and color frames hold SamplesPerPixel samples per pixel.
+func (fr *FrameReader) Frame(n int) ([]uint16, error) {
+	return fr.FrameContext(context.Background(), n)
+}
+
+// FrameContext is Frame with a context that is checked before decoding and
+// carried into log records emitted by the codec path
+func (fr *FrameReader) FrameContext(ctx context.Context, n int) ([]uint16, error) {
+	if n < 0 || n >= len(fr.spans) {
+		return nil, fmt.Errorf("frame index %d out of range (0-%d)", n, len(fr.spans)-1)
+	}
+	data, err := fr.RawFrame(ctx, n)
+	if err != nil {
+		return nil, err
+	}
+	pixels := fr.rows * fr.cols * fr.samples
+	if err := reserveMemory(ctx, ResourceFrame, int64(pixels)*2); err != nil {
+		return nil, err
+	}
+	dst := make([]uint16, pixels)
+	if fr.frameSize > 0 {
+		if int64(len(data)) == 2*int64(pixels) {
+			for i := range dst {
+				dst[i] = binary.LittleEndian.Uint16(data[2*i:])
+			}
+		} else {
+			for i := range dst {
+				dst[i] = uint16(data[i])
+			}
+		}
+	} else {
+		img, err := decodeCompressedFrame(ctx, data, fr.rows, fr.cols, fr.ts)
+		if err != nil {
+			return nil, fmt.Errorf("decoding frame %d: %w", n, err)
+		}
+		copyFrame(dst, img, fr.rows, fr.cols, fr.samples)
+	}
+	signExtend(dst, fr.bitsStored)
+	return dst, nil
+}
+
+// RawFrame returns the stored bytes of frame n: the native samples, or the
+// codestream of an encapsulated frame with its fragments joined
+func (fr *FrameReader) RawFrame(ctx context.Context, n int) ([]byte, error) {
+	if n < 0 || n >= len(fr.spans) {
+		return nil, fmt.Errorf("frame index %d out of range (0-%d)", n, len(fr.spans)-1)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+
+	if fr.frameSize > 0 {
+		if err := reserveMemory(ctx, ResourceFrame, fr.frameSize); err != nil {
+			return nil, err
+		}
+		if _, err := fr.src.Seek(fr.valueStart+int64(n)*fr.frameSize, io.SeekStart); err != nil {
+			return nil, err
+		}
+		data, err := readBytes(fr.src, fr.frameSize)
+		if err != nil {
+			return nil, fmt.Errorf("reading frame %d: %w", n, err)
+		}
+		return data, nil
+	}
+
+	span := fr.spans[n]
+	pos, err := fr.src.Seek(span.start, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+	var data []byte
+	for span.end < 0 || pos < span.end {
+		t, length, err := readItemHeader(fr.src)
+		if err != nil {
+			return nil, fmt.Errorf("reading frame %d: %w", n, err)
+		}
+		if t == seqDelimTag {
+			break
+		}
+		if t != itemTag || length == undefinedLength {
+			return nil, fmt.Errorf("reading frame %d: unexpected tag %v in encapsulated pixel data", n, t)
+		}
+		if err := reserveMemory(ctx, ResourceFrame, int64(length)); err != nil {
+			return nil, err
+		}
+		fragment, err := readBytes(fr.src, int64(length))
+		if err != nil {
+			return nil, fmt.Errorf("reading frame %d: %w", n, err)
+		}
+		data = append(data, fragment...)
+		pos += 8 + int64(length)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("frame %d has no fragments", n)
+	}
+	return data, nil
+}
+
+// Close closes the file opened by OpenFrameReader. It does nothing for a
+// FrameReader made with NewFrameReader.
+func (fr *FrameReader) Close() error {
+	if fr.closer == nil {
+		return nil
+	}
+	return fr.closer.Close()
+}
@@ -0,0 +1,122 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingSeeker counts the bytes read through it
+type countingSeeker struct {
+	io.ReadSeeker
+	n int
+}
+
+func (c *countingSeeker) Read(p []byte) (int, error) {
+	n, err := c.ReadSeeker.Read(p)
+	c.n += n
+	return n, err
+}
+
+func TestFrameReader(t *testing.T) {
+	const rows, cols, frames = 16, 16, 12
+	for _, codec := range []Codec{nil, CodecJPEGLS, CodecRLE, CodecJPEG2000} {
+		name := "native"
+		if codec != nil {
+			name = codec.Name()
+		}
+		t.Run(name, func(t *testing.T) {
+			data := writeTestCT(t, rows, cols, frames, codec)
+			ds, err := ReadBuffer(data)
+			require.NoError(t, err)
+			want, err := DecodeVolume(ds)
+			require.NoError(t, err)
+
+			src := &countingSeeker{ReadSeeker: bytes.NewReader(data)}
+			fr, err := NewFrameReader(context.Background(), src)
+			require.NoError(t, err)
+			assert.Equal(t, frames, fr.NumFrames())
+			assert.NotContains(t, fr.Dataset().Elements, tag.PixelData)
+			assert.Equal(t, rows, GetRows(fr.Dataset()))
+
+			src.n = 0
+			got, err := fr.Frame(frames - 2)
+			require.NoError(t, err)
+			assert.Equal(t, want.Data[(frames-2)*rows*cols:(frames-1)*rows*cols], got)
+			assert.Less(t, src.n, len(data)/4, "one of 12 frames read")
+
+			got, err = fr.Frame(0)
+			require.NoError(t, err)
+			assert.Equal(t, want.Data[:rows*cols], got)
+			_, err = fr.Frame(frames)
+			assert.ErrorContains(t, err, "frame index 12 out of range (0-11)")
+		})
+	}
+}
+
+func TestFrameReader_NoOffsetTable(t *testing.T) {
+	const rows, cols, frames = 8, 8, 3
+	ds, err := ReadBuffer(writeTestCT(t, rows, cols, frames, CodecJPEGLS))
+	require.NoError(t, err)
+	want, err := DecodeVolume(ds)
+	require.NoError(t, err)
+	pd, err := ds.GetPixelData()
+	require.NoError(t, err)
+	pd.Offsets = nil // an empty Basic Offset Table item
+
+	path := filepath.Join(t.TempDir(), "no-bot.dcs")
+	_, err = WriteFile(path, ds)
+	require.NoError(t, err)
+	fr, err := OpenFrameReader(path)
+	require.NoError(t, err)
+	defer fr.Close()
+	require.Equal(t, frames, fr.NumFrames())
+	for z := range frames {
+		got, err := fr.Frame(z)
+		require.NoError(t, err)
+		assert.Equal(t, want.Data[z*rows*cols:(z+1)*rows*cols], got, "frame %d", z)
+	}
+	raw, err := fr.RawFrame(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, pd.Frames[1].CompressedData, raw)
+}
+
+func TestFrameReader_Signed(t *testing.T) {
+	hu := []int16{-1024, -1, 0, 3071}
+	ct := NewCTImage()
+	ct.Codec = CodecJPEGLS
+	ct.SetSignedPixelData(2, 2, hu)
+	ds, err := ct.GetDataset()
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	_, err = Write(&buf, ds)
+	require.NoError(t, err)
+
+	fr, err := NewFrameReader(context.Background(), bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	got, err := fr.Frame(0)
+	require.NoError(t, err)
+	for i, v := range hu {
+		assert.Equal(t, v, int16(got[i]))
+	}
+}
+
+func TestFrameReader_Errors(t *testing.T) {
+	_, err := OpenFrameReader(filepath.Join(t.TempDir(), "missing.dcs"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	ds, err := NewDataset(WithFileMeta(CTImageStorageUID, "1.2.3.4", string(ExplicitVRLittleEndian)), WithElement(tag.PatientID, "ID1"))
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	_, err = Write(&buf, ds)
+	require.NoError(t, err)
+	_, err = NewFrameReader(context.Background(), bytes.NewReader(buf.Bytes()))
+	assert.ErrorContains(t, err, "no pixel data")
+}
@@ -0,0 +1,335 @@
+package dicos
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"io"
+	"maps"
+	"strconv"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/jpfielding/dicos.go/pkg/dicos/transfer"
+)
+
+// FrameWriter writes a multi-frame DICOS file one frame at a time, for
+// scanners that produce slices in real time. The elements of the dataset are
+// written first, each frame is compressed and written as it is appended, and
+// Close ends the pixel data. Only the frame being appended is held in memory.
+//
+// The dataset gives the Image Pixel module; its pixel data, if any, is not
+// written. A NumberOfFrames in the dataset is the number of frames Close
+// expects. Without one, w must be an io.WriteSeeker such as an *os.File:
+// NumberOfFrames is written as a placeholder and filled in by Close, as is
+// the length of native pixel data. The Basic Offset Table of encapsulated
+// frames is filled in when the writer can seek and the number of frames is
+// known, and left empty otherwise.
+//
+// Example:
+//
+//	f, _ := os.Create("scan.dcs")
+//	defer f.Close()
+//	fw, err := dicos.NewFrameWriter(f, ds, dicos.CodecJPEGLS)
+//	for slice := range scanner.Slices() {
+//		if err := fw.AppendSamples(slice); err != nil {
+//			return err
+//		}
+//	}
+//	return fw.Close()
+type FrameWriter struct {
+	w      io.Writer
+	ws     io.WriteSeeker // nil when w cannot seek
+	start  int64          // position of w before the preamble
+	n      int64          // bytes written
+	codec  Codec
+	format SampleFormat
+
+	rows, cols, samples int
+	frameSize           int64 // bytes of a native frame
+
+	want    int    // frames declared in NumberOfFrames, 0 when filled in by Close
+	frames  int    // frames appended
+	offsets []byte // Basic Offset Table, when it is filled in by Close
+	next    uint32 // offset of the next fragment from the first
+
+	numberOfFramesAt int64 // value offset of the NumberOfFrames placeholder
+	lengthAt         int64 // offset of the native pixel data length
+	botAt            int64 // value offset of the Basic Offset Table
+	closed           bool
+}
+
+// numberOfFramesWidth is the width of the NumberOfFrames placeholder, the
+// longest IS value
+const numberOfFramesWidth = 12
+
+// NewFrameWriter writes the elements of ds to w, in the transfer syntax of
+// codec, or of ds when codec is nil, followed by the start of its pixel data.
+// ds is not modified.
+func NewFrameWriter(w io.Writer, ds *Dataset, codec Codec) (*FrameWriter, error) {
+	fw := &FrameWriter{
+		w:       w,
+		codec:   codec,
+		rows:    GetRows(ds),
+		cols:    GetColumns(ds),
+		samples: max(ds.SamplesPerPixel(), 1),
+	}
+	bitsAllocated := ds.BitsAllocated()
+	if fw.rows == 0 || fw.cols == 0 {
+		return nil, fmt.Errorf("invalid dimensions: %dx%d", fw.cols, fw.rows)
+	}
+	if bitsAllocated != 8 && bitsAllocated != 16 {
+		return nil, fmt.Errorf("%w: %d bits allocated", ErrUnsupportedPixelFormat, bitsAllocated)
+	}
+	fw.format = sampleFormat(ds, bitsAllocated)
+	fw.frameSize = int64(fw.rows*fw.cols*fw.samples) * int64(bitsAllocated/8)
+	if ws, ok := w.(io.WriteSeeker); ok {
+		start, err := ws.Seek(0, io.SeekCurrent)
+		if err == nil {
+			fw.ws, fw.start = ws, start
+		}
+	}
+	if t, ok := lastTag(ds); ok && pixelDataTag.Less(t) {
+		return nil, fmt.Errorf("element %v follows the pixel data and cannot be streamed", t)
+	}
+
+	hdr := &Dataset{Elements: maps.Clone(ds.Elements)}
+	delete(hdr.Elements, pixelDataTag)
+	ts := ds.TransferSyntax()
+	if codec != nil {
+		ts = transfer.Syntax(codec.TransferSyntaxUID())
+		if method, ok := lossyMethods[ts]; ok {
+			if err := withLossyMethod(hdr, method); err != nil {
+				return nil, err
+			}
+		}
+	} else if ts.IsEncapsulated() {
+		ts = transfer.ExplicitVRLittleEndian
+	}
+	opts := []Option{WithElement(tag.TransferSyntaxUID, string(ts))}
+	placeholder := ""
+	if fw.want = attrInt(ds, tag.NumberOfFrames); fw.want <= 0 {
+		if fw.ws == nil {
+			return nil, fmt.Errorf("NumberOfFrames is needed to stream frames to a writer that cannot seek")
+		}
+		fw.want = 0
+		placeholder = fmt.Sprintf("%-*d", numberOfFramesWidth, 0)
+		opts = append(opts, WithElement(tag.NumberOfFrames, placeholder))
+	}
+	for _, opt := range opts {
+		if err := opt(hdr); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	if _, err := Write(&buf, hdr); err != nil {
+		return nil, err
+	}
+	implicit := ts == transfer.ImplicitVRLittleEndian
+	if placeholder != "" {
+		var elem bytes.Buffer
+		if _, err := writeElement(&elem, hdr.Elements[tag.NumberOfFrames], implicit); err != nil {
+			return nil, err
+		}
+		at := bytes.Index(buf.Bytes(), elem.Bytes())
+		if at < 0 {
+			return nil, fmt.Errorf("NumberOfFrames placeholder not found in the header")
+		}
+		fw.numberOfFramesAt = int64(at + elem.Len() - numberOfFramesWidth)
+	}
+
+	// the pixel data element header, and the offset table item
+	vr := "OB"
+	if codec == nil && bitsAllocated > 8 {
+		vr = "OW"
+	}
+	buf.Write(appendTag(nil, pixelDataTag))
+	if !implicit {
+		buf.WriteString(vr)
+		buf.Write([]byte{0, 0})
+	}
+	if codec == nil {
+		fw.lengthAt = int64(buf.Len())
+		length := fw.frameSize * int64(fw.want)
+		binary.Write(&buf, binary.LittleEndian, uint32(length+length%2))
+	} else {
+		binary.Write(&buf, binary.LittleEndian, uint32(undefinedLength))
+		if fw.ws != nil && fw.want > 0 {
+			fw.offsets = make([]byte, 4*fw.want)
+		}
+		buf.Write(appendTag(nil, itemTag))
+		binary.Write(&buf, binary.LittleEndian, uint32(len(fw.offsets)))
+		fw.botAt = int64(buf.Len())
+		buf.Write(fw.offsets)
+	}
+	return fw, fw.write(buf.Bytes())
+}
+
+// lastTag returns the highest tag of ds
+func lastTag(ds *Dataset) (Tag, bool) {
+	var last Tag
+	for t := range ds.Elements {
+		if last.Less(t) {
+			last = t
+		}
+	}
+	return last, len(ds.Elements) > 0
+}
+
+// withLossyMethod records that frames are compressed with loss by method.
+// The ratio, known only once every frame is written, is left out.
+func withLossyMethod(ds *Dataset, method string) error {
+	if prev := attrString(ds, tag.LossyImageCompressionMethod); prev != "" && attrString(ds, tag.LossyImageCompression) == "01" {
+		method = prev + `\` + method
+	}
+	if err := WithElement(tag.LossyImageCompression, "01")(ds); err != nil {
+		return err
+	}
+	return WithElement(tag.LossyImageCompressionMethod, method)(ds)
+}
+
+func (fw *FrameWriter) write(p []byte) error {
+	n, err := fw.w.Write(p)
+	fw.n += int64(n)
+	return err
+}
+
+// Frames returns the number of frames appended
+func (fw *FrameWriter) Frames() int {
+	return fw.frames
+}
+
+// AppendFrame compresses and writes img as the next frame. Gray and Gray16
+// images keep their sample values; with 3 samples per pixel, the 8-bit RGB
+// of any image is written.
+func (fw *FrameWriter) AppendFrame(img image.Image) error {
+	b := img.Bounds()
+	if b.Dx() != fw.cols || b.Dy() != fw.rows {
+		return fmt.Errorf("frame %d is %dx%d, want %dx%d", fw.frames, b.Dx(), b.Dy(), fw.cols, fw.rows)
+	}
+	data := make([]uint16, 0, fw.rows*fw.cols*fw.samples)
+	if fw.samples == 3 {
+		for _, v := range interleavedRGB(img) {
+			data = append(data, uint16(v))
+		}
+		return fw.AppendSamples(data)
+	}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			data = append(data, frameSample(img, x, y))
+		}
+	}
+	return fw.AppendSamples(data)
+}
+
+// AppendSamples compresses and writes the next frame from its samples, in
+// the layout of WithPixelData
+func (fw *FrameWriter) AppendSamples(data []uint16) error {
+	if fw.closed {
+		return fmt.Errorf("frame writer is closed")
+	}
+	if want := fw.rows * fw.cols * fw.samples; len(data) != want {
+		return fmt.Errorf("frame %d has %d samples, want %d", fw.frames, len(data), want)
+	}
+	if fw.want > 0 && fw.frames == fw.want {
+		return fmt.Errorf("frame %d exceeds NumberOfFrames %d", fw.frames, fw.want)
+	}
+
+	if fw.codec == nil {
+		buf := make([]byte, 0, fw.frameSize)
+		for _, v := range data {
+			if fw.format.BitsAllocated == 8 {
+				buf = append(buf, uint8(v))
+			} else {
+				buf = binary.LittleEndian.AppendUint16(buf, v)
+			}
+		}
+		if err := fw.write(buf); err != nil {
+			return err
+		}
+		fw.frames++
+		return nil
+	}
+
+	var fragment []byte
+	var err error
+	if fw.samples == 3 {
+		fragment, err = encodeRGBFrame(fw.codec, data, fw.rows, fw.cols, fw.format.BitsAllocated)
+	} else {
+		fragment, err = encodeGrayFrame(fw.codec, data, fw.rows, fw.cols, fw.format)
+	}
+	if err != nil {
+		return err
+	}
+	if fw.offsets != nil {
+		binary.LittleEndian.PutUint32(fw.offsets[4*fw.frames:], fw.next)
+	}
+	item := appendTag(make([]byte, 0, 8+len(fragment)), itemTag)
+	item = binary.LittleEndian.AppendUint32(item, uint32(len(fragment)))
+	if err := fw.write(append(item, fragment...)); err != nil {
+		return err
+	}
+	fw.next += uint32(len(item) + len(fragment))
+	fw.frames++
+	return nil
+}
+
+// Close ends the pixel data and fills in what was left open: the number of
+// frames, the native pixel data length and the Basic Offset Table. It
+// returns an error if fewer frames than NumberOfFrames were appended. Close
+// does not close w.
+func (fw *FrameWriter) Close() error {
+	if fw.closed {
+		return nil
+	}
+	fw.closed = true
+	if fw.frames == 0 {
+		return fmt.Errorf("no frames were appended")
+	}
+	if fw.want > 0 && fw.frames != fw.want {
+		return fmt.Errorf("%d of NumberOfFrames %d frames were appended", fw.frames, fw.want)
+	}
+
+	length := fw.frameSize * int64(fw.frames)
+	if fw.codec == nil {
+		if length%2 != 0 {
+			if err := fw.write([]byte{0}); err != nil {
+				return err
+			}
+		}
+	} else if err := fw.write(append(appendTag(nil, seqDelimTag), 0, 0, 0, 0)); err != nil {
+		return err
+	}
+	if fw.ws == nil {
+		return nil
+	}
+
+	end := fw.n
+	if fw.want == 0 {
+		if err := fw.patch(fw.numberOfFramesAt, fmt.Appendf(nil, "%-*s", numberOfFramesWidth, strconv.Itoa(fw.frames))); err != nil {
+			return err
+		}
+		if fw.codec == nil {
+			if err := fw.patch(fw.lengthAt, binary.LittleEndian.AppendUint32(nil, uint32(length+length%2))); err != nil {
+				return err
+			}
+		}
+	}
+	if fw.offsets != nil {
+		if err := fw.patch(fw.botAt, fw.offsets); err != nil {
+			return err
+		}
+	}
+	_, err := fw.ws.Seek(fw.start+end, io.SeekStart)
+	return err
+}
+
+// patch overwrites the bytes at offset, from the start of the file
+func (fw *FrameWriter) patch(offset int64, p []byte) error {
+	if _, err := fw.ws.Seek(fw.start+offset, io.SeekStart); err != nil {
+		return err
+	}
+	_, err := fw.ws.Write(p)
+	return err
+}
@@ -0,0 +1,157 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamHeader is the dataset of a CT whose frames are streamed
+func streamHeader(t *testing.T, rows, cols int) *Dataset {
+	t.Helper()
+	ct := NewCTImage()
+	ct.Patient.PatientID = "STREAM-001"
+	ct.SetPixelData(rows, cols, make([]uint16, rows*cols))
+	ds, err := ct.GetDataset()
+	require.NoError(t, err)
+	delete(ds.Elements, tag.PixelData)
+	delete(ds.Elements, tag.NumberOfFrames)
+	return ds
+}
+
+// streamSlices are frames of distinct samples
+func streamSlices(rows, cols, frames int) [][]uint16 {
+	out := make([][]uint16, frames)
+	for z := range out {
+		out[z] = make([]uint16, rows*cols)
+		for i := range out[z] {
+			out[z][i] = uint16(z*1000 + i)
+		}
+	}
+	return out
+}
+
+func TestFrameWriter(t *testing.T) {
+	const rows, cols, frames = 8, 12, 5
+	want := streamSlices(rows, cols, frames)
+	for _, codec := range []Codec{nil, CodecJPEGLS, CodecRLE} {
+		name := "native"
+		if codec != nil {
+			name = codec.Name()
+		}
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "stream.dcs")
+			f, err := os.Create(path)
+			require.NoError(t, err)
+			defer f.Close()
+			_, err = f.WriteString("leading bytes are not part of the file") // offsets are relative to the start
+			require.NoError(t, err)
+
+			fw, err := NewFrameWriter(f, streamHeader(t, rows, cols), codec)
+			require.NoError(t, err)
+			for _, slice := range want {
+				require.NoError(t, fw.AppendSamples(slice))
+			}
+			assert.Equal(t, frames, fw.Frames())
+			require.NoError(t, fw.Close())
+			require.NoError(t, f.Close())
+
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			ds, err := ReadBuffer(data[len("leading bytes are not part of the file"):])
+			require.NoError(t, err)
+			assert.Equal(t, frames, ds.NumberOfFrames())
+			assert.Equal(t, "STREAM-001", attrString(ds, tag.PatientID))
+			vol, err := DecodeVolume(ds)
+			require.NoError(t, err)
+			for z := range frames {
+				assert.Equal(t, want[z], vol.Data[z*rows*cols:(z+1)*rows*cols], "frame %d", z)
+			}
+			if codec != nil {
+				pd, err := ds.GetPixelData()
+				require.NoError(t, err)
+				assert.Empty(t, pd.Offsets, "no offset table without NumberOfFrames")
+				assert.Equal(t, codec.TransferSyntaxUID(), string(ds.TransferSyntax()))
+			}
+		})
+	}
+}
+
+func TestFrameWriter_OffsetTable(t *testing.T) {
+	const rows, cols, frames = 8, 8, 4
+	ds := streamHeader(t, rows, cols)
+	require.NoError(t, WithElement(tag.NumberOfFrames, strconv.Itoa(frames))(ds))
+	path := filepath.Join(t.TempDir(), "bot.dcs")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+	fw, err := NewFrameWriter(f, ds, CodecJPEGLS)
+	require.NoError(t, err)
+	for _, slice := range streamSlices(rows, cols, frames) {
+		require.NoError(t, fw.AppendSamples(slice))
+	}
+	require.NoError(t, fw.Close())
+
+	got, err := ReadFile(path)
+	require.NoError(t, err)
+	pd, err := got.GetPixelData()
+	require.NoError(t, err)
+	require.Len(t, pd.Offsets, frames, "offset table filled in")
+	assert.Equal(t, uint32(0), pd.Offsets[0])
+	assert.Less(t, pd.Offsets[frames-2], pd.Offsets[frames-1])
+}
+
+func TestFrameWriter_NotSeekable(t *testing.T) {
+	const rows, cols, frames = 4, 4, 3
+	ds := streamHeader(t, rows, cols)
+	var buf bytes.Buffer
+	_, err := NewFrameWriter(&buf, ds, CodecJPEGLS)
+	assert.ErrorContains(t, err, "NumberOfFrames is needed")
+
+	require.NoError(t, WithElement(tag.NumberOfFrames, strconv.Itoa(frames))(ds))
+	buf.Reset()
+	fw, err := NewFrameWriter(&buf, ds, CodecJPEGLS)
+	require.NoError(t, err)
+	header := buf.Len()
+	img := image.NewGray16(image.Rect(0, 0, cols, rows))
+	for z := range frames {
+		img.Pix[1] = uint8(z)
+		require.NoError(t, fw.AppendFrame(img))
+		assert.Greater(t, buf.Len(), header, "frame %d written when appended", z)
+		header = buf.Len()
+	}
+	assert.ErrorContains(t, fw.AppendSamples(make([]uint16, rows*cols)), "exceeds NumberOfFrames 3")
+	require.NoError(t, fw.Close())
+
+	fr, err := NewFrameReader(context.Background(), bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, frames, fr.NumFrames())
+	got, err := fr.Frame(2)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(2), got[0])
+}
+
+func TestFrameWriter_Errors(t *testing.T) {
+	ds := streamHeader(t, 4, 4)
+	require.NoError(t, WithElement(tag.NumberOfFrames, "2")(ds))
+	var buf bytes.Buffer
+	fw, err := NewFrameWriter(&buf, ds, nil)
+	require.NoError(t, err)
+	assert.ErrorContains(t, fw.AppendSamples(make([]uint16, 3)), "frame 0 has 3 samples, want 16")
+	assert.ErrorContains(t, fw.AppendFrame(image.NewGray(image.Rect(0, 0, 2, 2))), "frame 0 is 2x2, want 4x4")
+	require.NoError(t, fw.AppendSamples(make([]uint16, 16)))
+	assert.ErrorContains(t, fw.Close(), "1 of NumberOfFrames 2 frames were appended")
+	assert.ErrorContains(t, fw.AppendSamples(make([]uint16, 16)), "closed")
+
+	require.NoError(t, withVR(Tag{Group: 0xFFFC, Element: 0xFFFC}, "OB", []byte{0, 0})(ds))
+	_, err = NewFrameWriter(&buf, ds, nil)
+	assert.ErrorContains(t, err, "follows the pixel data")
+}
@@ -0,0 +1,229 @@
+package dicos
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/module"
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// FrameMeta is optional per-frame context for multi-frame pixel data. It is
+// written to and read from the Per-frame Functional Groups Sequence
+// (5200,9230), so it survives an encode/decode round trip alongside the
+// frame it describes.
+type FrameMeta struct {
+	AcquisitionTime time.Time   // Frame Content: Frame Acquisition DateTime (0018,9074)
+	Position        *[3]float64 // Plane Position (Patient): Image Position (Patient) (0020,0032), mm
+	EnergyBin       string      // Frame Content: Frame Label (0020,9453), e.g. "LOW" or "HIGH"
+}
+
+// FrameMeta returns the metadata of every frame, nil where a frame has none
+func (pd *PixelData) FrameMeta() []*FrameMeta {
+	metas := make([]*FrameMeta, len(pd.Frames))
+	for i := range pd.Frames {
+		metas[i] = pd.Frames[i].Meta
+	}
+	return metas
+}
+
+// HasFrameMeta returns true if any frame carries metadata
+func (pd *PixelData) HasFrameMeta() bool {
+	for i := range pd.Frames {
+		if pd.Frames[i].Meta != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// WithFrameMeta attaches per-frame metadata to the pixel data already in the
+// dataset, so it must follow WithPixelData or WithRawPixelData. meta[i]
+// describes frame i; nil entries leave a frame without metadata.
+//
+// Example:
+//
+//	ds, err := dicos.NewDataset(
+//		dicos.WithPixelData(rows, cols, 16, data, dicos.CodecJPEGLS),
+//		dicos.WithFrameMeta(
+//			&dicos.FrameMeta{EnergyBin: "LOW", Position: &[3]float64{0, 0, 0}},
+//			&dicos.FrameMeta{EnergyBin: "HIGH", Position: &[3]float64{0, 0, 2.5}},
+//		),
+//	)
+func WithFrameMeta(meta ...*FrameMeta) Option {
+	return func(ds *Dataset) error {
+		if !slices.ContainsFunc(meta, func(m *FrameMeta) bool { return m != nil }) {
+			return nil
+		}
+		elem, ok := ds.FindElement(tag.PixelData.Group, tag.PixelData.Element)
+		if !ok {
+			return fmt.Errorf("frame metadata requires pixel data")
+		}
+		pd, ok := elem.GetPixelData()
+		if !ok {
+			return fmt.Errorf("frame metadata requires decoded pixel data, got %T", elem.Value)
+		}
+		if len(meta) > len(pd.Frames) {
+			return fmt.Errorf("frame metadata for %d frames, pixel data has %d", len(meta), len(pd.Frames))
+		}
+		for i, m := range meta {
+			pd.Frames[i].Meta = m
+		}
+		return nil
+	}
+}
+
+// withFunctionalGroups returns ds with its Per-frame Functional Groups
+// Sequence updated from the frame metadata of its pixel data. Existing items
+// are copied and only the Frame Content and Plane Position groups of frames
+// with metadata are replaced. ds itself is never modified; it is returned
+// unchanged when no frame has metadata.
+func withFunctionalGroups(ds *Dataset) (*Dataset, error) {
+	elem, ok := ds.FindElement(tag.PixelData.Group, tag.PixelData.Element)
+	if !ok {
+		return ds, nil
+	}
+	pd, ok := elem.GetPixelData()
+	if !ok || !pd.HasFrameMeta() {
+		return ds, nil
+	}
+
+	var existing []*Dataset
+	if seq, ok := ds.FindElement(tag.PerFrameFunctionalGroupsSequence.Group, tag.PerFrameFunctionalGroupsSequence.Element); ok {
+		existing, _ = seq.Value.([]*Dataset)
+	}
+	items := make([]*Dataset, len(pd.Frames))
+	for i := range items {
+		item := &Dataset{Elements: make(map[Tag]*Element)}
+		if len(existing) == len(pd.Frames) && existing[i] != nil {
+			for t, e := range existing[i].Elements {
+				item.Elements[t] = e
+			}
+		}
+		if m := pd.Frames[i].Meta; m != nil {
+			if err := m.apply(item); err != nil {
+				return nil, fmt.Errorf("frame %d: %w", i, err)
+			}
+		}
+		items[i] = item
+	}
+
+	out := &Dataset{Elements: make(map[Tag]*Element, len(ds.Elements)+1)}
+	for t, e := range ds.Elements {
+		out.Elements[t] = e
+	}
+	if err := WithSequence(tag.PerFrameFunctionalGroupsSequence, items...)(out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// apply writes the metadata into a per-frame functional group item
+func (m *FrameMeta) apply(item *Dataset) error {
+	var content []Option
+	if !m.AcquisitionTime.IsZero() {
+		content = append(content, withVR(tag.FrameAcquisitionDateTime, "DT", module.NewDateTime(m.AcquisitionTime).String()))
+	}
+	if m.EnergyBin != "" {
+		content = append(content, withVR(tag.FrameLabel, "LO", m.EnergyBin))
+	}
+	if len(content) > 0 {
+		fc, err := NewDataset(content...)
+		if err != nil {
+			return err
+		}
+		if err := WithSequence(tag.FrameContentSequence, fc)(item); err != nil {
+			return err
+		}
+	}
+
+	if m.Position != nil {
+		parts := make([]string, 3)
+		for i, v := range m.Position {
+			parts[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+		pp, err := NewDataset(withVR(tag.ImagePositionPatient, "DS", strings.Join(parts, "\\")))
+		if err != nil {
+			return err
+		}
+		if err := WithSequence(tag.PlanePositionSequence, pp)(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withVR adds an element with an explicit VR
+func withVR(t tag.Tag, vr string, value interface{}) Option {
+	return func(ds *Dataset) error {
+		ds.Elements[t] = &Element{Tag: t, VR: vr, Value: value}
+		return nil
+	}
+}
+
+// applyFrameMeta fills in frame metadata from the dataset's Per-frame
+// Functional Groups Sequence, leaving frames that already have metadata alone
+func applyFrameMeta(ds *Dataset, pd *PixelData) {
+	seq, ok := ds.FindElement(tag.PerFrameFunctionalGroupsSequence.Group, tag.PerFrameFunctionalGroupsSequence.Element)
+	if !ok {
+		return
+	}
+	items, _ := seq.Value.([]*Dataset)
+	for i := 0; i < len(items) && i < len(pd.Frames); i++ {
+		if pd.Frames[i].Meta == nil && items[i] != nil {
+			pd.Frames[i].Meta = parseFrameMeta(items[i])
+		}
+	}
+}
+
+// parseFrameMeta reads the metadata of one per-frame functional group item,
+// returning nil when the item carries none of it
+func parseFrameMeta(item *Dataset) *FrameMeta {
+	m := &FrameMeta{}
+	found := false
+	if fc := firstItem(item, tag.FrameContentSequence); fc != nil {
+		if elem, ok := fc.FindElement(tag.FrameAcquisitionDateTime.Group, tag.FrameAcquisitionDateTime.Element); ok {
+			if s, ok := elem.GetString(); ok {
+				if t, err := parseDT(s); err == nil {
+					m.AcquisitionTime, found = t, true
+				}
+			}
+		}
+		if elem, ok := fc.FindElement(tag.FrameLabel.Group, tag.FrameLabel.Element); ok {
+			if s, ok := elem.GetString(); ok && s != "" {
+				m.EnergyBin, found = s, true
+			}
+		}
+	}
+	if pp := firstItem(item, tag.PlanePositionSequence); pp != nil {
+		if v := dsValues(pp, tag.ImagePositionPatient); len(v) == 3 {
+			m.Position, found = &[3]float64{v[0], v[1], v[2]}, true
+		}
+	}
+	if !found {
+		return nil
+	}
+	return m
+}
+
+// firstItem returns the first item of a sequence element, or nil
+func firstItem(ds *Dataset, t tag.Tag) *Dataset {
+	elem, ok := ds.FindElement(t.Group, t.Element)
+	if !ok {
+		return nil
+	}
+	items, _ := elem.Value.([]*Dataset)
+	if len(items) == 0 {
+		return nil
+	}
+	return items[0]
+}
+
+// parseDT parses a DICOM DT value, with optional fraction and UTC offset
+func parseDT(s string) (time.Time, error) {
+	dt, err := module.ParseDateTime(s)
+	return dt.Time, err
+}
@@ -0,0 +1,159 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/module"
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameMeta_RoundTrip(t *testing.T) {
+	const rows, cols = 4, 4
+	data := make([]uint16, rows*cols*3)
+	acquired := time.Date(2024, 3, 1, 12, 30, 15, 250000000, time.UTC)
+	metas := []*FrameMeta{
+		{AcquisitionTime: acquired, Position: &[3]float64{-10.5, 20, 0}, EnergyBin: "LOW"},
+		nil,
+		{Position: &[3]float64{-10.5, 20, 5}, EnergyBin: "HIGH"},
+	}
+
+	for name, codec := range map[string]Codec{"native": nil, "jpeg-ls": CodecJPEGLS} {
+		t.Run(name, func(t *testing.T) {
+			ds, err := NewDataset(
+				WithElement(tag.Rows, uint16(rows)),
+				WithElement(tag.Columns, uint16(cols)),
+				WithElement(tag.BitsAllocated, uint16(16)),
+				WithElement(tag.NumberOfFrames, "3"),
+				WithPixelData(rows, cols, 16, data, codec),
+				WithFrameMeta(metas...),
+			)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			_, err = Write(&buf, ds)
+			require.NoError(t, err)
+			_, ok := ds.Elements[tag.PerFrameFunctionalGroupsSequence]
+			assert.False(t, ok, "writing does not modify the dataset")
+
+			parsed, err := ReadBufferContext(context.Background(), buf.Bytes())
+			require.NoError(t, err)
+			pd, err := parsed.GetPixelDataContext(context.Background())
+			require.NoError(t, err)
+			require.Len(t, pd.Frames, 3)
+
+			got := pd.FrameMeta()
+			require.NotNil(t, got[0])
+			assert.True(t, acquired.Equal(got[0].AcquisitionTime))
+			assert.Equal(t, [3]float64{-10.5, 20, 0}, *got[0].Position)
+			assert.Equal(t, "LOW", got[0].EnergyBin)
+			assert.Nil(t, got[1])
+			assert.Equal(t, "HIGH", got[2].EnergyBin)
+			assert.True(t, got[2].AcquisitionTime.IsZero())
+		})
+	}
+}
+
+func TestFrameMeta_PreservesOtherFunctionalGroups(t *testing.T) {
+	other := Tag{Group: 0x0028, Element: 0x9110} // Pixel Measures Sequence
+	measures, err := NewDataset(WithElement(tag.PixelSpacing, "0.5\\0.5"))
+	require.NoError(t, err)
+	group, err := NewDataset(WithSequence(other, measures))
+	require.NoError(t, err)
+
+	ds, err := NewDataset(
+		WithElement(tag.Rows, uint16(2)),
+		WithElement(tag.Columns, uint16(2)),
+		WithElement(tag.BitsAllocated, uint16(16)),
+		WithSequence(tag.PerFrameFunctionalGroupsSequence, group),
+		WithPixelData(2, 2, 16, make([]uint16, 4), nil),
+		WithFrameMeta(&FrameMeta{EnergyBin: "HIGH"}),
+	)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	_, err = Write(&buf, ds)
+	require.NoError(t, err)
+	parsed, err := ReadBufferContext(context.Background(), buf.Bytes())
+	require.NoError(t, err)
+
+	elem, ok := parsed.Elements[tag.PerFrameFunctionalGroupsSequence]
+	require.True(t, ok)
+	items, ok := elem.Value.([]*Dataset)
+	require.True(t, ok)
+	require.Len(t, items, 1)
+	spacing := firstItem(items[0], other)
+	require.NotNil(t, spacing, "existing functional groups are kept")
+	assert.Equal(t, []float64{0.5, 0.5}, dsValues(spacing, tag.PixelSpacing))
+	assert.Equal(t, "HIGH", parseFrameMeta(items[0]).EnergyBin)
+}
+
+func TestWithFrameMeta_Errors(t *testing.T) {
+	_, err := NewDataset(WithFrameMeta(&FrameMeta{EnergyBin: "LOW"}))
+	assert.Error(t, err, "no pixel data")
+
+	_, err = NewDataset(
+		WithPixelData(2, 2, 16, make([]uint16, 4), nil),
+		WithFrameMeta(&FrameMeta{}, &FrameMeta{}),
+	)
+	assert.Error(t, err, "more metadata than frames")
+
+	_, err = NewDataset(WithFrameMeta(nil, nil))
+	assert.NoError(t, err, "nil metadata is a no-op")
+}
+
+func TestParseDateTime(t *testing.T) {
+	east := time.FixedZone("", 5*3600+30*60)
+	tests := []struct {
+		in     string
+		want   time.Time
+		offset bool
+	}{
+		{"2024", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		{"202403", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), false},
+		{"2024030112", time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), false},
+		{"20240301123015.25", time.Date(2024, 3, 1, 12, 30, 15, 250000000, time.UTC), false},
+		{"20240301123015.000001+0530", time.Date(2024, 3, 1, 12, 30, 15, 1000, east), true},
+		{"20240301123015-0800 ", time.Date(2024, 3, 1, 20, 30, 15, 0, time.UTC), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			dt, err := module.ParseDateTime(tt.in)
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(dt.Time), "got %v", dt.Time)
+			assert.Equal(t, tt.offset, dt.HasOffset)
+
+			again, err := module.ParseDateTime(dt.String())
+			require.NoError(t, err)
+			assert.True(t, dt.Equal(again.Time), "round trip of %s", dt)
+		})
+	}
+
+	for _, bad := range []string{"", "202", "20241301", "20240230", "20240301250000", "20240301123015.1234567", "20240301+053"} {
+		_, err := module.ParseDateTime(bad)
+		assert.Error(t, err, bad)
+	}
+	assert.Equal(t, "20240301123015.250000", module.DateTime{Time: tests[3].want}.String())
+	assert.Equal(t, "20240301123015.000001+0530", module.NewDateTime(tests[4].want).String())
+}
+
+func TestParseTime(t *testing.T) {
+	tm, err := module.ParseTime("123015.0625")
+	require.NoError(t, err)
+	assert.Equal(t, module.Time{Hour: 12, Minute: 30, Second: 15, Nano: 62500000}, tm)
+	assert.Equal(t, "123015.062500", tm.String())
+	assert.Equal(t, 12*time.Hour+30*time.Minute+15*time.Second+62500*time.Microsecond, tm.Duration())
+
+	tm, err = module.ParseTime("12:30:15")
+	require.NoError(t, err)
+	assert.Equal(t, module.Time{Hour: 12, Minute: 30, Second: 15}, tm)
+
+	for _, bad := range []string{"1", "123", "1230.5", "240000", "123015.", "12301x"} {
+		_, err := module.ParseTime(bad)
+		assert.Error(t, err, bad)
+	}
+}
@@ -0,0 +1,217 @@
+// Package geom converts between the (column, row, frame) voxel indices that
+// TDR bounding boxes and polygons are written in and patient coordinates in
+// mm, using the image plane attributes of the scan the TDR references.
+// Orientations may be oblique; only the row and column directions must not be
+// parallel.
+package geom
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+)
+
+// Geometry places a scan's voxel grid in patient coordinates
+type Geometry struct {
+	Position     [3]float64 // ImagePositionPatient of the first pixel of the first frame
+	Orientation  [6]float64 // ImageOrientationPatient: row then column direction cosines
+	RowSpacing   float64    // mm between rows, from PixelSpacing
+	ColSpacing   float64    // mm between columns, from PixelSpacing
+	SliceSpacing float64    // mm between frames along the slice normal, from SliceThickness
+}
+
+// FromDataset reads the geometry of a CT or DX scan from its
+// ImagePositionPatient, ImageOrientationPatient, PixelSpacing and
+// SliceThickness
+func FromDataset(ds *dicos.Dataset) (Geometry, error) {
+	var g Geometry
+	copy(g.Position[:], dicos.GetImagePositionPatient(ds))
+	copy(g.Orientation[:], dicos.GetImageOrientationPatient(ds))
+	g.RowSpacing, g.ColSpacing = dicos.GetPixelSpacing(ds)
+	g.SliceSpacing = dicos.GetSliceThickness(ds)
+	return g, g.Validate()
+}
+
+// FromVolume returns the geometry of a decoded volume
+func FromVolume(v *dicos.Volume) Geometry {
+	return Geometry{
+		Position:     [3]float64{v.OriginX, v.OriginY, v.OriginZ},
+		Orientation:  v.Orientation,
+		RowSpacing:   v.SpacingY,
+		ColSpacing:   v.SpacingX,
+		SliceSpacing: v.SpacingZ,
+	}
+}
+
+// Validate reports a geometry that cannot be inverted: a non-positive
+// spacing or parallel row and column directions
+func (g Geometry) Validate() error {
+	if g.RowSpacing <= 0 || g.ColSpacing <= 0 || g.SliceSpacing <= 0 {
+		return fmt.Errorf("spacing must be positive, got %gx%gx%g", g.ColSpacing, g.RowSpacing, g.SliceSpacing)
+	}
+	if math.Abs(det(g.matrix())) < 1e-12 {
+		return fmt.Errorf("orientation %v has no slice normal", g.Orientation)
+	}
+	return nil
+}
+
+// Normal returns the unit slice normal, the cross product of the row and
+// column directions
+func (g Geometry) Normal() [3]float64 {
+	r, c := g.Orientation[:3], g.Orientation[3:]
+	n := [3]float64{
+		r[1]*c[2] - r[2]*c[1],
+		r[2]*c[0] - r[0]*c[2],
+		r[0]*c[1] - r[1]*c[0],
+	}
+	if l := math.Sqrt(n[0]*n[0] + n[1]*n[1] + n[2]*n[2]); l > 0 {
+		n = [3]float64{n[0] / l, n[1] / l, n[2] / l}
+	}
+	return n
+}
+
+// VoxelToPatient returns the patient coordinate of voxel (column, row,
+// frame), which may be fractional
+func (g Geometry) VoxelToPatient(v [3]float64) [3]float64 {
+	m := g.matrix()
+	var p [3]float64
+	for i := range p {
+		p[i] = g.Position[i] + m[i][0]*v[0] + m[i][1]*v[1] + m[i][2]*v[2]
+	}
+	return p
+}
+
+// PatientToVoxel returns the fractional (column, row, frame) of patient
+// coordinate p; round each component for the nearest voxel. Row and column
+// directions need not be orthogonal. The geometry must be valid.
+func (g Geometry) PatientToVoxel(p [3]float64) [3]float64 {
+	inv := inverse(g.matrix())
+	rel := [3]float64{p[0] - g.Position[0], p[1] - g.Position[1], p[2] - g.Position[2]}
+	var v [3]float64
+	for i := range v {
+		v[i] = inv[i][0]*rel[0] + inv[i][1]*rel[1] + inv[i][2]*rel[2]
+	}
+	return v
+}
+
+// BoundingBoxCorners returns the patient coordinates of the 8 corners of a
+// TDR bounding box. For an oblique scan they form an oblique box.
+func (g Geometry) BoundingBoxCorners(b dicos.BoundingBox) [8][3]float64 {
+	var corners [8][3]float64
+	for i := range corners {
+		var v [3]float64
+		for axis := range v {
+			v[axis] = float64(b.TopLeft[axis])
+			if i&(1<<axis) != 0 {
+				v[axis] = float64(b.BottomRight[axis])
+			}
+		}
+		corners[i] = g.VoxelToPatient(v)
+	}
+	return corners
+}
+
+// BoundingBoxToPatient returns the smallest patient-axis aligned box, as its
+// lowest and highest corners, that holds a TDR bounding box
+func (g Geometry) BoundingBoxToPatient(b dicos.BoundingBox) (lo, hi [3]float64) {
+	return extent(g.BoundingBoxCorners(b))
+}
+
+// PatientToBoundingBox returns the smallest TDR bounding box, in fractional
+// (column, row, frame), that holds the patient box with opposite corners a
+// and b
+func (g Geometry) PatientToBoundingBox(a, b [3]float64) dicos.BoundingBox {
+	var corners [8][3]float64
+	for i := range corners {
+		p := a
+		for axis := range p {
+			if i&(1<<axis) != 0 {
+				p[axis] = b[axis]
+			}
+		}
+		corners[i] = g.PatientToVoxel(p)
+	}
+	lo, hi := extent(corners)
+	var box dicos.BoundingBox
+	for axis := range lo {
+		box.TopLeft[axis], box.BottomRight[axis] = float32(lo[axis]), float32(hi[axis])
+	}
+	return box
+}
+
+// PolygonToPatient returns the patient coordinates of a TDR polygon's
+// (column, row) points on the given frame
+func (g Geometry) PolygonToPatient(poly [][2]float32, frame float64) [][3]float64 {
+	out := make([][3]float64, len(poly))
+	for i, p := range poly {
+		out[i] = g.VoxelToPatient([3]float64{float64(p[0]), float64(p[1]), frame})
+	}
+	return out
+}
+
+// PatientToPolygon returns the (column, row) TDR polygon of patient points,
+// projected along the slice normal, and the mean frame they lie on
+func (g Geometry) PatientToPolygon(points [][3]float64) (poly [][2]float32, frame float64) {
+	if len(points) == 0 {
+		return nil, 0
+	}
+	poly = make([][2]float32, len(points))
+	for i, p := range points {
+		v := g.PatientToVoxel(p)
+		poly[i] = [2]float32{float32(v[0]), float32(v[1])}
+		frame += v[2]
+	}
+	return poly, frame / float64(len(points))
+}
+
+// matrix returns the columns scaled to the voxel spacing: row direction,
+// column direction and slice normal
+func (g Geometry) matrix() [3][3]float64 {
+	n := g.Normal()
+	var m [3][3]float64
+	for i := range m {
+		m[i] = [3]float64{
+			g.Orientation[i] * g.ColSpacing,
+			g.Orientation[3+i] * g.RowSpacing,
+			n[i] * g.SliceSpacing,
+		}
+	}
+	return m
+}
+
+func det(m [3][3]float64) float64 {
+	return m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+		m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+		m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+}
+
+// inverse returns the inverse of m by its adjugate, or zeros when m is
+// singular
+func inverse(m [3][3]float64) [3][3]float64 {
+	var inv [3][3]float64
+	d := det(m)
+	if d == 0 {
+		return inv
+	}
+	for i := range 3 {
+		for j := range 3 {
+			// cofactor of m[j][i], transposed into inv[i][j]
+			a, b := (j+1)%3, (j+2)%3
+			c, e := (i+1)%3, (i+2)%3
+			inv[i][j] = (m[a][c]*m[b][e] - m[a][e]*m[b][c]) / d
+		}
+	}
+	return inv
+}
+
+// extent returns the lowest and highest coordinate on each axis
+func extent(points [8][3]float64) (lo, hi [3]float64) {
+	lo, hi = points[0], points[0]
+	for _, p := range points[1:] {
+		for i := range p {
+			lo[i], hi[i] = min(lo[i], p[i]), max(hi[i], p[i])
+		}
+	}
+	return lo, hi
+}
@@ -0,0 +1,153 @@
+package geom
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// oblique returns a geometry rotated 30 degrees about Z and tilted 20 degrees
+// about the row direction, with anisotropic spacing
+func oblique() Geometry {
+	a, b := 30*math.Pi/180, 20*math.Pi/180
+	row := [3]float64{math.Cos(a), math.Sin(a), 0}
+	col := [3]float64{-math.Sin(a) * math.Cos(b), math.Cos(a) * math.Cos(b), math.Sin(b)}
+	return Geometry{
+		Position:     [3]float64{-120, 80.5, 300},
+		Orientation:  [6]float64{row[0], row[1], row[2], col[0], col[1], col[2]},
+		RowSpacing:   0.8,
+		ColSpacing:   0.6,
+		SliceSpacing: 1.25,
+	}
+}
+
+func TestGeometry_VoxelToPatient(t *testing.T) {
+	g := Geometry{
+		Position:     [3]float64{-10, 20, 100},
+		Orientation:  [6]float64{1, 0, 0, 0, 1, 0},
+		RowSpacing:   0.5,
+		ColSpacing:   0.25,
+		SliceSpacing: 2,
+	}
+	assert.Equal(t, [3]float64{-9, 21, 106}, g.VoxelToPatient([3]float64{4, 2, 3}))
+	assert.Equal(t, [3]float64{4, 2, 3}, g.PatientToVoxel([3]float64{-9, 21, 106}))
+
+	// a coronal plane stacks its frames towards the back of the patient
+	g.Orientation = [6]float64{1, 0, 0, 0, 0, -1}
+	assert.InDeltaSlice(t, []float64{0, 1, 0}, sl(g.Normal()), 1e-12)
+	assert.InDeltaSlice(t, []float64{-10, 22, 99}, sl(g.VoxelToPatient([3]float64{0, 2, 1})), 1e-9)
+}
+
+func TestGeometry_ObliqueRoundTrip(t *testing.T) {
+	for name, g := range map[string]Geometry{
+		"oblique": oblique(),
+		// row and column directions 80 degrees apart
+		"skewed": {
+			Position:     [3]float64{5, -5, 12},
+			Orientation:  [6]float64{1, 0, 0, math.Cos(80 * math.Pi / 180), math.Sin(80 * math.Pi / 180), 0},
+			RowSpacing:   1,
+			ColSpacing:   1,
+			SliceSpacing: 3,
+		},
+	} {
+		require.NoError(t, g.Validate(), name)
+		for _, v := range [][3]float64{{0, 0, 0}, {511, 511, 0}, {12.5, 300.25, 47.75}, {-3, 7, -2}} {
+			p := g.VoxelToPatient(v)
+			assert.InDeltaSlice(t, sl(v), sl(g.PatientToVoxel(p)), 1e-9, name)
+		}
+	}
+
+	// one step along each index moves by its spacing
+	g := oblique()
+	origin := g.VoxelToPatient([3]float64{})
+	assert.InDelta(t, g.ColSpacing, dist(origin, g.VoxelToPatient([3]float64{1, 0, 0})), 1e-9)
+	assert.InDelta(t, g.RowSpacing, dist(origin, g.VoxelToPatient([3]float64{0, 1, 0})), 1e-9)
+	assert.InDelta(t, g.SliceSpacing, dist(origin, g.VoxelToPatient([3]float64{0, 0, 1})), 1e-9)
+}
+
+func TestGeometry_BoundingBox(t *testing.T) {
+	g := oblique()
+	box := dicos.BoundingBox{TopLeft: [3]float32{10, 20, 3}, BottomRight: [3]float32{40, 60, 9}}
+
+	// every corner maps back to a corner of the box
+	for _, c := range g.BoundingBoxCorners(box) {
+		v := g.PatientToVoxel(c)
+		for axis := range v {
+			assert.True(t, math.Abs(v[axis]-float64(box.TopLeft[axis])) < 1e-6 ||
+				math.Abs(v[axis]-float64(box.BottomRight[axis])) < 1e-6, "corner %v", v)
+		}
+	}
+
+	// the patient box holds the oblique box, so its voxel box holds the original
+	lo, hi := g.BoundingBoxToPatient(box)
+	back := g.PatientToBoundingBox(lo, hi)
+	for axis := range 3 {
+		assert.LessOrEqual(t, back.TopLeft[axis], box.TopLeft[axis]+1e-4)
+		assert.GreaterOrEqual(t, back.BottomRight[axis], box.BottomRight[axis]-1e-4)
+	}
+
+	// axis aligned boxes convert exactly, whatever the corner order
+	g.Orientation = [6]float64{1, 0, 0, 0, 1, 0}
+	lo, hi = g.BoundingBoxToPatient(box)
+	back = g.PatientToBoundingBox(hi, lo)
+	assert.InDeltaSlice(t, sl32(box.TopLeft), sl32(back.TopLeft), 1e-4)
+	assert.InDeltaSlice(t, sl32(box.BottomRight), sl32(back.BottomRight), 1e-4)
+}
+
+func TestGeometry_Polygon(t *testing.T) {
+	g := oblique()
+	poly := [][2]float32{{10, 10}, {50, 12.5}, {30, 40}}
+	points := g.PolygonToPatient(poly, 7)
+	require.Len(t, points, 3)
+
+	back, frame := g.PatientToPolygon(points)
+	assert.InDelta(t, 7, frame, 1e-9)
+	for i := range poly {
+		assert.InDeltaSlice(t, []float32{poly[i][0], poly[i][1]}, []float32{back[i][0], back[i][1]}, 1e-4)
+	}
+
+	back, frame = g.PatientToPolygon(nil)
+	assert.Nil(t, back)
+	assert.Zero(t, frame)
+}
+
+func TestFromDataset(t *testing.T) {
+	ds, err := dicos.NewDataset(
+		dicos.WithElement(tag.ImagePositionPatient, "-120\\80.5\\300"),
+		dicos.WithElement(tag.ImageOrientationPatient, "0.8\\0.6\\0\\-0.6\\0.8\\0"),
+		dicos.WithElement(tag.PixelSpacing, "0.8\\0.6"),
+		dicos.WithElement(tag.SliceThickness, "1.25"),
+	)
+	require.NoError(t, err)
+	g, err := FromDataset(ds)
+	require.NoError(t, err)
+	assert.Equal(t, [3]float64{-120, 80.5, 300}, g.Position)
+	assert.Equal(t, [6]float64{0.8, 0.6, 0, -0.6, 0.8, 0}, g.Orientation)
+	assert.Equal(t, [3]float64{0.6, 0.8, 1.25}, [3]float64{g.ColSpacing, g.RowSpacing, g.SliceSpacing})
+
+	// the geometry of the decoded volume agrees with the dataset
+	v := dicos.NewVolume(2, 2, 2)
+	v.OriginX, v.OriginY, v.OriginZ = -120, 80.5, 300
+	v.Orientation = g.Orientation
+	v.SpacingX, v.SpacingY, v.SpacingZ = 0.6, 0.8, 1.25
+	want := v.VoxelToWorld(3, 4, 5, dicos.LPS)
+	assert.InDeltaSlice(t, sl(want), sl(FromVolume(v).VoxelToPatient([3]float64{3, 4, 5})), 1e-9)
+	assert.InDeltaSlice(t, sl(want), sl(g.VoxelToPatient([3]float64{3, 4, 5})), 1e-9)
+
+	ds, err = dicos.NewDataset(dicos.WithElement(tag.ImageOrientationPatient, "1\\0\\0\\1\\0\\0"))
+	require.NoError(t, err)
+	_, err = FromDataset(ds)
+	assert.ErrorContains(t, err, "no slice normal")
+}
+
+func sl(v [3]float64) []float64 { return v[:] }
+
+func sl32(v [3]float32) []float32 { return v[:] }
+
+func dist(a, b [3]float64) float64 {
+	return math.Sqrt((a[0]-b[0])*(a[0]-b[0]) + (a[1]-b[1])*(a[1]-b[1]) + (a[2]-b[2])*(a[2]-b[2]))
+}
@@ -0,0 +1,99 @@
+package dicos
+
+import (
+	"strings"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// Image Type (0008,0008) values 1 and 2 (PS3.3 C.7.6.1.1.2)
+const (
+	ImageTypeOriginal  = "ORIGINAL"  // pixel values based on the original data
+	ImageTypeDerived   = "DERIVED"   // pixel values derived from other images
+	ImageTypePrimary   = "PRIMARY"   // created as a direct result of the examination
+	ImageTypeSecondary = "SECONDARY" // created after the examination
+)
+
+// ImageType is the multi-valued Image Type (0008,0008) split into its
+// components, e.g. ORIGINAL\PRIMARY\AXIAL
+type ImageType struct {
+	PixelData   string   // value 1: ORIGINAL or DERIVED
+	Examination string   // value 2: PRIMARY or SECONDARY
+	Values      []string // values 3 and on: modality or implementation specific, e.g. AXIAL
+}
+
+// ParseImageType splits a backslash separated Image Type value
+func ParseImageType(s string) ImageType {
+	var it ImageType
+	parts := strings.Split(s, "\\")
+	for i, p := range parts {
+		p = strings.TrimSpace(p)
+		switch i {
+		case 0:
+			it.PixelData = p
+		case 1:
+			it.Examination = p
+		default:
+			it.Values = append(it.Values, p)
+		}
+	}
+	return it
+}
+
+// GetImageType returns the Image Type of ds, and false if it has none
+func GetImageType(ds *Dataset) (ImageType, bool) {
+	strs := attrStrings(ds, tag.ImageType)
+	if len(strs) == 0 {
+		return ImageType{}, false
+	}
+	return ParseImageType(strings.Join(strs, "\\")), true
+}
+
+// String returns the Image Type as stored, with backslash separated values.
+// Empty trailing values are left out.
+func (it ImageType) String() string {
+	parts := append([]string{it.PixelData, it.Examination}, it.Values...)
+	for len(parts) > 0 && parts[len(parts)-1] == "" {
+		parts = parts[:len(parts)-1]
+	}
+	return strings.Join(parts, "\\")
+}
+
+// IsOriginal returns true when the pixel values are based on original data
+func (it ImageType) IsOriginal() bool {
+	return it.PixelData == ImageTypeOriginal
+}
+
+// IsDerived returns true when the pixel values were derived from other images
+func (it ImageType) IsDerived() bool {
+	return it.PixelData == ImageTypeDerived
+}
+
+// IsPrimary returns true when the image was created by the examination
+func (it ImageType) IsPrimary() bool {
+	return it.Examination == ImageTypePrimary
+}
+
+// Derived returns the Image Type of an image derived from one of type it:
+// value 1 becomes DERIVED and the other values are kept. An empty value 2
+// becomes SECONDARY.
+func (it ImageType) Derived() ImageType {
+	out := ImageType{PixelData: ImageTypeDerived, Examination: it.Examination}
+	if out.Examination == "" {
+		out.Examination = ImageTypeSecondary
+	}
+	out.Values = append([]string(nil), it.Values...)
+	return out
+}
+
+// WithImageType sets the Image Type
+func WithImageType(it ImageType) Option {
+	return withVR(tag.ImageType, "CS", it.String())
+}
+
+// withDerivedImageType marks ds as derived from source, keeping the modality
+// specific values of the source's Image Type
+func withDerivedImageType(source *Dataset) Option {
+	it, _ := GetImageType(source)
+	return WithImageType(it.Derived())
+}
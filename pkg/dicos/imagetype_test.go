@@ -0,0 +1,65 @@
+package dicos
+
+import (
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageType_ParseAndFormat(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ImageType
+	}{
+		{"ORIGINAL\\PRIMARY\\AXIAL", ImageType{PixelData: "ORIGINAL", Examination: "PRIMARY", Values: []string{"AXIAL"}}},
+		{"DERIVED\\SECONDARY", ImageType{PixelData: "DERIVED", Examination: "SECONDARY"}},
+		{"ORIGINAL\\PRIMARY\\\\HIGH", ImageType{PixelData: "ORIGINAL", Examination: "PRIMARY", Values: []string{"", "HIGH"}}},
+		{"DERIVED", ImageType{PixelData: "DERIVED"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			it := ParseImageType(tt.in)
+			assert.Equal(t, tt.want, it)
+			assert.Equal(t, tt.in, it.String())
+		})
+	}
+}
+
+func TestImageType_Derived(t *testing.T) {
+	orig := ParseImageType("ORIGINAL\\PRIMARY\\AXIAL")
+	assert.True(t, orig.IsOriginal())
+	assert.True(t, orig.IsPrimary())
+
+	derived := orig.Derived()
+	assert.True(t, derived.IsDerived())
+	assert.Equal(t, "DERIVED\\PRIMARY\\AXIAL", derived.String())
+	assert.Equal(t, "ORIGINAL\\PRIMARY\\AXIAL", orig.String(), "the source is not modified")
+
+	assert.Equal(t, "DERIVED\\SECONDARY", ImageType{}.Derived().String())
+}
+
+func TestGetImageType(t *testing.T) {
+	for name, value := range map[string]any{
+		"string":       "ORIGINAL\\PRIMARY\\VOLUME ",
+		"string slice": []string{"ORIGINAL", "PRIMARY", "VOLUME"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ds, err := NewDataset(WithElement(tag.ImageType, value))
+			require.NoError(t, err)
+			it, ok := GetImageType(ds)
+			require.True(t, ok)
+			assert.Equal(t, []string{"VOLUME"}, it.Values)
+
+			out := rewrite(t, ds)
+			it, _ = GetImageType(out)
+			assert.Equal(t, "ORIGINAL\\PRIMARY\\VOLUME", it.String())
+		})
+	}
+
+	empty, err := NewDataset()
+	require.NoError(t, err)
+	_, ok := GetImageType(empty)
+	assert.False(t, ok)
+}
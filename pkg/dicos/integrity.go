@@ -0,0 +1,159 @@
+package dicos
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// ErrIntegrity is returned when an attested attribute no longer matches its hash
+var ErrIntegrity = errors.New("integrity check failed")
+
+// integrityCreator is the private creator reserving the integrity elements
+const integrityCreator = "DICOS.GO INTEGRITY"
+
+// integrityAlgorithm names the hash stored in IntegrityAlgorithm
+const integrityAlgorithm = "SHA256"
+
+// ThreatIntegrityTags are the attributes of a TDR that carry its assessment,
+// the default subset for AttestTags
+var ThreatIntegrityTags = []Tag{
+	tag.SOPInstanceUID,
+	tag.ReferencedSeriesSequence,
+	tag.ATDAbility,
+	tag.AlarmDecision,
+	tag.AbortReason,
+	tag.NumberOfAlarmObjects,
+	tag.PTOSequence,
+	tag.ThreatROISequence,
+	tag.ATDAssessmentSequence,
+	tag.OperatorAssessmentSequence,
+}
+
+// HashTags returns the SHA-256 of the given attributes of ds, in tag order.
+// Each attribute contributes its tag, value length and encoded value, so
+// changing, removing or adding any of them changes the hash; attributes not
+// in tags do not affect it.
+func HashTags(ds *Dataset, tags []Tag) ([]byte, error) {
+	sorted := slices.Clone(tags)
+	slices.SortFunc(sorted, func(a, b Tag) int {
+		if a == b {
+			return 0
+		}
+		if a.Less(b) {
+			return -1
+		}
+		return 1
+	})
+	sorted = slices.Compact(sorted)
+
+	h := sha256.New()
+	var b []byte
+	for _, t := range sorted {
+		b = appendTag(b[:0], t)
+		elem, ok := ds.Elements[t]
+		if !ok {
+			b = binary.LittleEndian.AppendUint32(b, 0xFFFFFFFF) // absent
+			h.Write(b)
+			continue
+		}
+		if _, ok := elem.Value.(*BulkData); ok {
+			return nil, fmt.Errorf("cannot hash bulk data element (%04X,%04X)", t.Group, t.Element)
+		}
+		val, _, err := encodeValue(elem.Value, elem.VR)
+		if err != nil {
+			return nil, fmt.Errorf("hashing (%04X,%04X): %w", t.Group, t.Element, err)
+		}
+		b = binary.LittleEndian.AppendUint32(b, uint32(len(val)))
+		h.Write(b)
+		h.Write(val)
+	}
+	return h.Sum(nil), nil
+}
+
+// AttestTags hashes the given attributes of ds, ThreatIntegrityTags when none
+// are given, and stores the hash with the list of covered attributes in the
+// private integrity elements (0011,10xx). This is tamper evidence for
+// deployments without PKI, not a signature: anyone able to edit the file can
+// also recompute the hash. Call it after the last change to the covered
+// attributes and verify with VerifyAttestation.
+//
+// Example:
+//
+//	tdr, _ := dicos.NewDataset(...)
+//	if err := dicos.AttestTags(tdr); err != nil {
+//		return err
+//	}
+//	dicos.WriteFile("tdr.dcs", tdr)
+func AttestTags(ds *Dataset, tags ...Tag) error {
+	if len(tags) == 0 {
+		tags = ThreatIntegrityTags
+	}
+	if i := slices.IndexFunc(tags, func(t Tag) bool { return t.Group == tag.IntegrityCreator.Group }); i >= 0 {
+		return fmt.Errorf("cannot attest integrity element (%04X,%04X)", tags[i].Group, tags[i].Element)
+	}
+	if creator, ok := ds.Elements[tag.IntegrityCreator]; ok {
+		if s, _ := creator.GetString(); s != integrityCreator {
+			return fmt.Errorf("private block (0011,10xx) is reserved by %q", s)
+		}
+	}
+	digest, err := HashTags(ds, tags)
+	if err != nil {
+		return err
+	}
+	for _, opt := range []Option{
+		withVR(tag.IntegrityCreator, "LO", integrityCreator),
+		withVR(tag.IntegrityAlgorithm, "CS", integrityAlgorithm),
+		withVR(tag.IntegrityTags, "AT", slices.Clone(tags)),
+		withVR(tag.IntegrityDigest, "OB", digest),
+	} {
+		if err := opt(ds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifyAttestation recomputes the hash stored by AttestTags. It returns an
+// error wrapping ErrIntegrity when any covered attribute has changed, and a
+// plain error when ds carries no attestation.
+func VerifyAttestation(ds *Dataset) error {
+	creator, ok := ds.Elements[tag.IntegrityCreator]
+	if !ok {
+		return fmt.Errorf("no integrity attestation found")
+	}
+	if s, _ := creator.GetString(); s != integrityCreator {
+		return fmt.Errorf("private block (0011,10xx) is reserved by %q, not an integrity attestation", s)
+	}
+	if elem, ok := ds.Elements[tag.IntegrityAlgorithm]; ok {
+		if s, _ := elem.GetString(); s != integrityAlgorithm {
+			return fmt.Errorf("unsupported integrity algorithm %q", s)
+		}
+	}
+	tagsElem, ok := ds.Elements[tag.IntegrityTags]
+	if !ok {
+		return fmt.Errorf("%w: attested attribute list is missing", ErrIntegrity)
+	}
+	tags, ok := tagsElem.GetTags()
+	if !ok || len(tags) == 0 {
+		return fmt.Errorf("%w: attested attribute list is unreadable", ErrIntegrity)
+	}
+	digestElem, ok := ds.Elements[tag.IntegrityDigest]
+	if !ok {
+		return fmt.Errorf("%w: digest is missing", ErrIntegrity)
+	}
+	want, _ := digestElem.Value.([]byte)
+	got, err := HashTags(ds, tags)
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(want, got) != 1 {
+		return fmt.Errorf("%w: attested attributes have changed", ErrIntegrity)
+	}
+	return nil
+}
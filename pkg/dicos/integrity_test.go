@@ -0,0 +1,71 @@
+package dicos
+
+import (
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// attestedTDR returns a written and re-read TDR attested with the default tags
+func attestedTDR(t *testing.T) *Dataset {
+	t.Helper()
+	tdr := NewThreatDetectionReport()
+	tdr.AlarmDecision = "ALARM"
+	tdr.Patient.PatientID = "BAG-0001"
+	tdr.PTOs = append(tdr.PTOs, PotentialThreatObject{ID: 1, Label: "KNIFE", Probability: 0.9})
+	ds, err := tdr.GetDataset()
+	require.NoError(t, err)
+	require.NoError(t, AttestTags(ds))
+	return rewrite(t, ds)
+}
+
+func TestAttestTags_RoundTrip(t *testing.T) {
+	ds := attestedTDR(t)
+	require.NoError(t, VerifyAttestation(ds))
+
+	ds.Elements[tag.PatientID].Value = "BAG-0002"
+	assert.NoError(t, VerifyAttestation(ds), "attributes outside the subset may change")
+}
+
+func TestAttestTags_Tampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(ds *Dataset)
+	}{
+		{"changed decision", func(ds *Dataset) {
+			ds.Elements[tag.AlarmDecision].Value = "NO_ALARM"
+		}},
+		{"removed decision", func(ds *Dataset) {
+			delete(ds.Elements, tag.AlarmDecision)
+		}},
+		{"added abort reason", func(ds *Dataset) {
+			ds.Elements[tag.AbortReason] = &Element{Tag: tag.AbortReason, VR: "CS", Value: "OVERSIZE"}
+		}},
+		{"changed PTO", func(ds *Dataset) {
+			items := ds.Elements[tag.PTOSequence].Value.([]*Dataset)
+			items[0].Elements[tag.ThreatCategoryDescription].Value = "UMBRELLA"
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := attestedTDR(t)
+			tt.tamper(ds)
+			assert.ErrorIs(t, VerifyAttestation(ds), ErrIntegrity)
+		})
+	}
+}
+
+func TestAttestTags_Subset(t *testing.T) {
+	ds := attestedTDR(t)
+	require.NoError(t, AttestTags(ds, tag.PatientID, tag.AlarmDecision))
+	ds = rewrite(t, ds)
+	require.NoError(t, VerifyAttestation(ds))
+
+	ds.Elements[tag.PatientID].Value = "BAG-0002"
+	assert.ErrorIs(t, VerifyAttestation(ds), ErrIntegrity)
+
+	assert.ErrorContains(t, AttestTags(ds, tag.IntegrityDigest), "cannot attest")
+	assert.ErrorContains(t, VerifyAttestation(&Dataset{Elements: map[Tag]*Element{}}), "no integrity attestation")
+}
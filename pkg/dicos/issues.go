@@ -0,0 +1,137 @@
+package dicos
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// ErrDuplicateTag marks an element that occurs more than once in a dataset.
+// Issues caused by duplicates wrap it, so they can be counted with errors.Is.
+var ErrDuplicateTag = errors.New("duplicate element")
+
+// ErrSkippedElement marks an element, or the rest of the input, that a
+// best-effort parse could not read and left out of the dataset
+var ErrSkippedElement = errors.New("skipped element")
+
+// DuplicatePolicy decides which value of a repeated element is kept
+type DuplicatePolicy int
+
+const (
+	DuplicateLastWins  DuplicatePolicy = iota // keep the last value, reported as an issue
+	DuplicateFirstWins                        // keep the first value, reported as an issue
+	DuplicateError                            // fail the parse with ErrDuplicateTag
+)
+
+// ParseIssue is a non-fatal encoding violation found while reading, such as an
+// odd value length or incorrect padding. In strict mode the first issue is
+// returned as the parse error.
+type ParseIssue struct {
+	Offset  int64 // byte offset in the source where the issue was detected
+	Tag     Tag
+	Message string
+	Err     error // kind of the issue, such as ErrDuplicateTag, or nil
+}
+
+func (i ParseIssue) Error() string {
+	return fmt.Sprintf("%v at offset %d: %s", i.Tag, i.Offset, i.Message)
+}
+
+// Unwrap returns the kind of the issue
+func (i ParseIssue) Unwrap() error {
+	return i.Err
+}
+
+// issue records a violation, or returns it as an error in strict mode
+func (r *Reader) issue(t Tag, format string, args ...any) error {
+	return r.record(ParseIssue{Offset: r.cr.n, Tag: t, Message: fmt.Sprintf(format, args...)})
+}
+
+// record keeps pi, or returns it as an error in strict mode
+func (r *Reader) record(pi ParseIssue) error {
+	t := pi.Tag
+	if r.opts.Strict {
+		return pi
+	}
+	r.log().DebugContext(r.ctx, "Parse issue",
+		slog.String("tag", t.String()),
+		slog.Int64("offset", pi.Offset),
+		slog.String("issue", pi.Message))
+	r.issues = append(r.issues, pi)
+	return nil
+}
+
+// bestEffort returns true if unparseable elements are skipped
+func (r *Reader) bestEffort() bool {
+	return r.opts.BestEffort && !r.opts.Strict
+}
+
+// recoverable returns true if a best-effort parse can go on after err.
+// Cancellation and resource limits always end the parse.
+func (r *Reader) recoverable(err error) bool {
+	return r.ctx.Err() == nil && !errors.Is(err, ErrResourceLimit)
+}
+
+// truncated reports that the rest of the input after tag could not be read
+func (r *Reader) truncated(t Tag, err error) error {
+	return r.record(ParseIssue{Offset: r.cr.n, Tag: t, Message: fmt.Sprintf("input ends in a damaged element, keeping the elements before it: %v", err), Err: ErrSkippedElement})
+}
+
+// put stores elem in ds following the duplicate policy. A repeated tag is
+// reported as an issue, or fails the parse with DuplicateError.
+func (r *Reader) put(ds *Dataset, elem *Element) error {
+	if _, dup := ds.Elements[elem.Tag]; dup {
+		pi := ParseIssue{Offset: r.cr.n, Tag: elem.Tag, Err: ErrDuplicateTag}
+		switch r.opts.Duplicates {
+		case DuplicateError:
+			pi.Message = "duplicate element"
+			return pi
+		case DuplicateFirstWins:
+			pi.Message = "duplicate element, keeping the first value"
+			return r.record(pi)
+		default:
+			pi.Message = "duplicate element, keeping the last value"
+			if err := r.record(pi); err != nil {
+				return err
+			}
+		}
+	}
+	ds.Elements[elem.Tag] = elem
+	return nil
+}
+
+// Issues returns the violations collected while reading in permissive mode
+func (r *Reader) Issues() []ParseIssue {
+	return r.issues
+}
+
+// knownVR returns true for the value representations defined by the standard
+func knownVR(vr string) bool {
+	switch vr {
+	case "AE", "AS", "AT", "CS", "DA", "DS", "DT", "FL", "FD", "IS", "LO", "LT",
+		"OB", "OD", "OF", "OL", "OV", "OW", "PN", "SH", "SL", "SQ", "SS", "ST",
+		"SV", "TM", "UC", "UI", "UL", "UN", "UR", "US", "UT", "UV":
+		return true
+	}
+	return false
+}
+
+// checkPadding reports string values padded with the wrong character:
+// UI values are padded with NUL and other strings with a space
+func (r *Reader) checkPadding(t Tag, vr string, data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	last := data[len(data)-1]
+	switch vr {
+	case "UI":
+		if last == ' ' {
+			return r.issue(t, "UI value padded with space instead of NUL")
+		}
+	case "AE", "AS", "CS", "DA", "DS", "DT", "IS", "LO", "LT", "PN", "SH", "ST", "TM", "UC", "UR", "UT":
+		if last == 0 {
+			return r.issue(t, "%s value padded with NUL instead of space", vr)
+		}
+	}
+	return nil
+}
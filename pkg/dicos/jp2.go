@@ -0,0 +1,224 @@
+package dicos
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"io"
+
+	"github.com/jpfielding/jpegs/pkg/compress/jpeg2k"
+)
+
+// jp2Signature is the JP2 signature box (ISO/IEC 15444-1 I.5.1)
+var jp2Signature = []byte{0x00, 0x00, 0x00, 0x0C, 'j', 'P', ' ', ' ', 0x0D, 0x0A, 0x87, 0x0A}
+
+// JP2 enumerated colour spaces of the colr box (ISO/IEC 15444-1 I.5.3.3)
+const (
+	JP2ColourSRGB      = 16
+	JP2ColourGreyscale = 17
+)
+
+// JP2Header describes the image of a JP2 file, from its ihdr and colr boxes
+type JP2Header struct {
+	Width, Height int
+	Components    int
+	BitDepth      int  // bits per component
+	Signed        bool // two's complement components
+	ColourSpace   int  // JP2ColourGreyscale or JP2ColourSRGB
+}
+
+// IsJP2 reports whether data starts with the JP2 signature box rather than
+// a raw codestream
+func IsJP2(data []byte) bool {
+	return bytes.HasPrefix(data, jp2Signature)
+}
+
+// WriteJP2 wraps a raw JPEG 2000 codestream in the JP2 file format: the
+// signature and ftyp boxes, a jp2h header whose ihdr and colr boxes describe
+// the SIZ marker of the codestream, and the codestream itself in a jp2c box.
+//
+// Example:
+//
+//	pd, _ := ds.GetPixelData() // JPEG 2000 transfer syntax
+//	f, _ := os.Create("frame.jp2")
+//	err := dicos.WriteJP2(f, pd.Frames[0].CompressedData)
+func WriteJP2(w io.Writer, codestream []byte) error {
+	h, err := codestreamHeader(codestream)
+	if err != nil {
+		return err
+	}
+	bpc := byte(h.BitDepth - 1)
+	if h.Signed {
+		bpc |= 0x80
+	}
+	ihdr := binary.BigEndian.AppendUint32(nil, uint32(h.Height))
+	ihdr = binary.BigEndian.AppendUint32(ihdr, uint32(h.Width))
+	ihdr = binary.BigEndian.AppendUint16(ihdr, uint16(h.Components))
+	ihdr = append(ihdr, bpc, 7, 0, 0) // BPC, wavelet compression, known colour space, no IPR
+	colr := binary.BigEndian.AppendUint32([]byte{1, 0, 0}, uint32(h.ColourSpace))
+
+	var buf bytes.Buffer
+	buf.Write(jp2Signature)
+	buf.Write(jp2Box("ftyp", []byte("jp2 \x00\x00\x00\x00jp2 ")))
+	buf.Write(jp2Box("jp2h", append(jp2Box("ihdr", ihdr), jp2Box("colr", colr)...)))
+	buf.Write(jp2Box("jp2c", trimCodestream(codestream)))
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// ReadJP2 returns the codestream of a JP2 file with the header its boxes
+// declare. The header must agree with the SIZ marker of the codestream. A
+// raw codestream is returned as is, with the header read from SIZ.
+func ReadJP2(data []byte) ([]byte, JP2Header, error) {
+	if !IsJP2(data) {
+		h, err := codestreamHeader(data)
+		return data, h, err
+	}
+	var h JP2Header
+	var codestream []byte
+	var brand, header bool
+	err := jp2Boxes(data[len(jp2Signature):], func(typ string, body []byte) error {
+		switch typ {
+		case "ftyp":
+			if len(body) < 8 {
+				return fmt.Errorf("jp2: short ftyp box")
+			}
+			for i := 8; i+4 <= len(body); i += 4 {
+				brand = brand || string(body[i:i+4]) == "jp2 "
+			}
+			brand = brand || string(body[:4]) == "jp2 "
+		case "jp2h":
+			header = true
+			return jp2Boxes(body, func(typ string, body []byte) error {
+				switch typ {
+				case "ihdr":
+					if len(body) != 14 {
+						return fmt.Errorf("jp2: ihdr box of %d bytes, want 14", len(body))
+					}
+					h.Height = int(binary.BigEndian.Uint32(body))
+					h.Width = int(binary.BigEndian.Uint32(body[4:]))
+					h.Components = int(binary.BigEndian.Uint16(body[8:]))
+					if bpc := body[10]; bpc != 0xFF {
+						h.BitDepth, h.Signed = int(bpc&0x7F)+1, bpc&0x80 != 0
+					}
+				case "colr":
+					if len(body) >= 7 && body[0] == 1 {
+						h.ColourSpace = int(binary.BigEndian.Uint32(body[3:]))
+					}
+				}
+				return nil
+			})
+		case "jp2c":
+			if codestream == nil {
+				codestream = body
+			}
+		}
+		return nil
+	})
+	switch {
+	case err != nil:
+		return nil, JP2Header{}, err
+	case !brand:
+		return nil, JP2Header{}, fmt.Errorf("jp2: file type is not jp2")
+	case !header || h.Width == 0:
+		return nil, JP2Header{}, fmt.Errorf("jp2: no image header box")
+	case codestream == nil:
+		return nil, JP2Header{}, fmt.Errorf("jp2: no codestream box")
+	}
+	siz, err := codestreamHeader(codestream)
+	if err != nil {
+		return nil, JP2Header{}, err
+	}
+	if h.Width != siz.Width || h.Height != siz.Height || h.Components != siz.Components ||
+		(h.BitDepth != 0 && (h.BitDepth != siz.BitDepth || h.Signed != siz.Signed)) {
+		return nil, JP2Header{}, fmt.Errorf("jp2: image header %+v disagrees with codestream %+v", h, siz)
+	}
+	if h.BitDepth == 0 {
+		h.BitDepth, h.Signed = siz.BitDepth, siz.Signed
+	}
+	return codestream, h, nil
+}
+
+// DecodeJP2 decodes a JP2 file or raw JPEG 2000 codestream, with signed
+// components returned as by CodecJPEG2000.Decode
+func DecodeJP2(r io.Reader) (image.Image, JP2Header, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, JP2Header{}, err
+	}
+	codestream, h, err := ReadJP2(data)
+	if err != nil {
+		return nil, JP2Header{}, err
+	}
+	img, err := CodecJPEG2000.Decode(codestream, h.Width, h.Height)
+	return img, h, err
+}
+
+// codestreamHeader describes a raw codestream from its SIZ marker
+func codestreamHeader(codestream []byte) (JP2Header, error) {
+	siz, _, _, err := jpeg2k.ParseCodestreamHeader(codestream)
+	if err != nil {
+		return JP2Header{}, fmt.Errorf("jp2: %w", err)
+	}
+	if len(siz.Components) == 0 || siz.XOsiz >= siz.XSiz || siz.YOsiz >= siz.YSiz {
+		return JP2Header{}, fmt.Errorf("jp2: empty codestream image")
+	}
+	c := siz.Components[0]
+	h := JP2Header{
+		Width:       int(siz.XSiz - siz.XOsiz),
+		Height:      int(siz.YSiz - siz.YOsiz),
+		Components:  len(siz.Components),
+		BitDepth:    c.Precision,
+		Signed:      c.Signed,
+		ColourSpace: JP2ColourGreyscale,
+	}
+	if h.Components >= 3 {
+		h.ColourSpace = JP2ColourSRGB
+	}
+	return h, nil
+}
+
+// trimCodestream drops the fragment padding after the EOC marker
+func trimCodestream(codestream []byte) []byte {
+	if n := len(codestream); n >= 3 && codestream[n-1] == 0 && codestream[n-3] == 0xFF && codestream[n-2] == 0xD9 {
+		return codestream[:n-1]
+	}
+	return codestream
+}
+
+// jp2Box encodes one box
+func jp2Box(typ string, body []byte) []byte {
+	b := binary.BigEndian.AppendUint32(nil, uint32(8+len(body)))
+	return append(append(b, typ...), body...)
+}
+
+// jp2Boxes calls visit with the type and body of each box in data
+func jp2Boxes(data []byte, visit func(typ string, body []byte) error) error {
+	for len(data) > 0 {
+		if len(data) == 1 && data[0] == 0 {
+			return nil // padding of an odd-length pixel data fragment
+		}
+		if len(data) < 8 {
+			return fmt.Errorf("jp2: truncated box header")
+		}
+		size, typ, hdr := uint64(binary.BigEndian.Uint32(data)), string(data[4:8]), uint64(8)
+		switch size {
+		case 0: // to the end of the file
+			size = uint64(len(data))
+		case 1: // 64-bit extended length
+			if len(data) < 16 {
+				return fmt.Errorf("jp2: truncated %q box header", typ)
+			}
+			size, hdr = binary.BigEndian.Uint64(data[8:]), 16
+		}
+		if size < hdr || size > uint64(len(data)) {
+			return fmt.Errorf("jp2: %q box of %d bytes overruns its container", typ, size)
+		}
+		if err := visit(typ, data[hdr:size]); err != nil {
+			return err
+		}
+		data = data[size:]
+	}
+	return nil
+}
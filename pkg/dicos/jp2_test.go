@@ -0,0 +1,110 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJP2_RoundTrip(t *testing.T) {
+	cs := testJPEG2000Frame(t)
+	var buf bytes.Buffer
+	require.NoError(t, WriteJP2(&buf, padFragment(cs, PadEven)))
+	jp2 := buf.Bytes()
+	require.True(t, IsJP2(jp2))
+	assert.False(t, IsJP2(cs))
+
+	got, h, err := ReadJP2(jp2)
+	require.NoError(t, err)
+	assert.Equal(t, cs, got, "fragment padding is not part of the codestream")
+	assert.Equal(t, JP2Header{Width: 8, Height: 8, Components: 1, BitDepth: 12, ColourSpace: JP2ColourGreyscale}, h)
+
+	img, h2, err := DecodeJP2(bytes.NewReader(jp2))
+	require.NoError(t, err)
+	assert.Equal(t, h, h2)
+	want, err := CodecJPEG2000.Decode(cs, 8, 8)
+	require.NoError(t, err)
+	assert.Equal(t, want, img)
+
+	// A raw codestream reads as itself
+	raw, h3, err := ReadJP2(cs)
+	require.NoError(t, err)
+	assert.Equal(t, cs, raw)
+	assert.Equal(t, h, h3)
+}
+
+func TestJP2_Rejects(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteJP2(&buf, testJPEG2000Frame(t)))
+	valid := buf.Bytes()
+	// signature (12), ftyp (8+12), jp2h (8) then ihdr (8+14)
+	ihdr := 12 + 20 + 8 + 8
+
+	tests := []struct {
+		name    string
+		corrupt func(b []byte) []byte
+		err     string
+	}{
+		{"brand", func(b []byte) []byte { copy(b[20:], "jpx "); copy(b[28:], "jpx "); return b }, "not jp2"},
+		{"width", func(b []byte) []byte { binary.BigEndian.PutUint32(b[ihdr+4:], 9); return b }, "disagrees"},
+		{"bit depth", func(b []byte) []byte { b[ihdr+10] = 15; return b }, "disagrees"},
+		{"no codestream", func(b []byte) []byte { return b[:bytes.Index(b, []byte("jp2c"))-4] }, "no codestream"},
+		{"overrun", func(b []byte) []byte { binary.BigEndian.PutUint32(b[12:], 1000); return b }, "overruns"},
+		{"truncated", func(b []byte) []byte { return b[:15] }, "truncated"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ReadJP2(tt.corrupt(bytes.Clone(valid)))
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestJP2_EncapsulatedFrames(t *testing.T) {
+	// Frames stored as JP2 files decode like codestreams, even when the
+	// transfer syntax does not say JPEG 2000
+	data := make([]uint16, 8*8)
+	for i := range data {
+		data[i] = uint16(i * 61 % 4096)
+	}
+	var buf bytes.Buffer
+	require.NoError(t, WriteJP2(&buf, testJPEG2000Frame(t)))
+	ds, err := NewDataset(
+		WithElement(tag.Rows, uint16(8)),
+		WithElement(tag.Columns, uint16(8)),
+		WithElement(tag.BitsAllocated, uint16(16)),
+		WithRawPixelData(&PixelData{IsEncapsulated: true, Frames: []Frame{{CompressedData: padFragment(buf.Bytes(), PadEven)}}}),
+	)
+	require.NoError(t, err)
+	vol, err := DecodeVolume(ds)
+	require.NoError(t, err)
+	assert.Equal(t, data, vol.Data)
+}
+
+func TestExportFrame_JP2(t *testing.T) {
+	data := []uint16{0, 2047, 0xF800, 0xFFFF, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+	ds := exportDataset(t, 16, 12, 1, data)
+	var buf bytes.Buffer
+	format, err := ExportFrame(context.Background(), ds, 0, &buf, ExportOptions{Format: ExportJP2})
+	require.NoError(t, err)
+	assert.Equal(t, ".jp2", format.Ext())
+
+	img, h, err := DecodeJP2(&buf)
+	require.NoError(t, err)
+	assert.True(t, h.Signed)
+	assert.Equal(t, 12, h.BitDepth)
+	require.IsType(t, &image.Gray16{}, img)
+	for i, v := range data {
+		assert.Equal(t, v, img.(*image.Gray16).Gray16At(i%4, i/4).Y, "sample %d", i)
+	}
+
+	f, err := ExportFormatByName("jp2")
+	require.NoError(t, err)
+	assert.Equal(t, ExportJP2, f)
+}
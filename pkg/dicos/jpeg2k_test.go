@@ -0,0 +1,437 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/jpfielding/jpegs/pkg/compress/jpeg2k"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJPEG2000_SignedCT(t *testing.T) {
+	const rows, cols = 8, 8
+	data := make([]uint16, rows*cols)
+	for i := range data {
+		data[i] = uint16(int16(i*37 - 1024)) // air through soft tissue, in HU
+	}
+
+	ct := NewCTImage()
+	ct.Codec = CodecJPEG2000
+	ct.PixelRepresent = 1
+	ct.Rows, ct.Columns = rows, cols
+	ct.SetPixelData(rows, cols, data)
+	var buf bytes.Buffer
+	_, err := ct.WriteTo(&buf)
+	require.NoError(t, err)
+
+	ds, err := ReadBufferContext(context.Background(), buf.Bytes())
+	require.NoError(t, err)
+	pd, err := ds.GetPixelData()
+	require.NoError(t, err)
+	require.Len(t, pd.Frames, 1)
+
+	siz, _, _, err := jpeg2k.ParseCodestreamHeader(pd.Frames[0].CompressedData)
+	require.NoError(t, err)
+	require.Len(t, siz.Components, 1)
+	assert.True(t, siz.Components[0].Signed)
+	assert.Equal(t, 16, siz.Components[0].Precision)
+
+	got, err := DecodeFrameData(pd, 0, rows, cols, ds.TransferSyntax())
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+}
+
+func TestJPEG2000_SampleFormats(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    SampleFormat
+		data      []uint16
+		want      []uint16 // decoded samples, defaults to data
+		precision int
+		err       bool
+	}{
+		{name: "unsigned 12 bit", format: SampleFormat{16, 12, false}, data: []uint16{0, 1, 2048, 4095}, precision: 12},
+		{name: "signed 12 bit sign-extended", format: SampleFormat{16, 12, true}, data: []uint16{0, 2047, 0xF800, 0xFFFF}, precision: 12},
+		{name: "signed 12 bit masked", format: SampleFormat{16, 12, true}, data: []uint16{0, 2047, 0x0800, 0x0FFF}, want: []uint16{0, 2047, 0xF800, 0xFFFF}, precision: 12},
+		{name: "unsigned value above bits stored", format: SampleFormat{16, 12, false}, data: []uint16{0, 4096, 0, 0}, err: true},
+		{name: "signed value above bits stored", format: SampleFormat{16, 12, true}, data: []uint16{0, 0x1800, 0, 0}, err: true},
+		{name: "12 bits allocated", format: SampleFormat{12, 12, false}, data: []uint16{0, 1, 2, 3}, err: true},
+		{name: "8 bits stored in 16", format: SampleFormat{16, 8, false}, data: []uint16{0, 1, 2, 3}, err: true},
+		{name: "stored above allocated", format: SampleFormat{8, 12, false}, data: []uint16{0, 1, 2, 3}, err: true},
+	}
+	enc := CodecJPEG2000.(SampleEncoder)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := enc.EncodeSamples(&buf, tt.data, 2, 2, tt.format)
+			if tt.err {
+				assert.ErrorIs(t, err, ErrUnsupportedPixelFormat)
+				return
+			}
+			require.NoError(t, err)
+
+			siz, _, _, err := jpeg2k.ParseCodestreamHeader(buf.Bytes())
+			require.NoError(t, err)
+			assert.Equal(t, tt.precision, siz.Components[0].Precision)
+			assert.Equal(t, tt.format.Signed, siz.Components[0].Signed)
+
+			want := tt.want
+			if want == nil {
+				want = tt.data
+			}
+			pd := &PixelData{IsEncapsulated: true, Frames: []Frame{{CompressedData: buf.Bytes()}}}
+			got, err := DecodeFrameData(pd, 0, 2, 2, TransferSyntax(CodecJPEG2000.TransferSyntaxUID()))
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestWithPixelData_JPEG2000Rejects(t *testing.T) {
+	_, err := NewDataset(
+		WithElement(tag.BitsStored, 12),
+		WithPixelData(2, 2, 16, []uint16{0, 1, 2, 5000}, CodecJPEG2000),
+	)
+	require.ErrorIs(t, err, ErrUnsupportedPixelFormat)
+	assert.Contains(t, err.Error(), "exceeds 12 bits stored")
+}
+
+// testJPEG2000Frame returns an 8x8 12-bit codestream
+func testJPEG2000Frame(t testing.TB) []byte {
+	data := make([]uint16, 8*8)
+	for i := range data {
+		data[i] = uint16(i * 61 % 4096)
+	}
+	var buf bytes.Buffer
+	require.NoError(t, CodecJPEG2000.(SampleEncoder).EncodeSamples(&buf, data, 8, 8, SampleFormat{16, 12, false}))
+	return buf.Bytes()
+}
+
+func TestJPEG2000_MalformedHeaders(t *testing.T) {
+	valid := testJPEG2000Frame(t)
+	// Offsets into the main header: SOC, SIZ marker and length, Rsiz, then
+	// XSiz at 8, YSiz at 12, XOsiz at 16, XTsiz at 24, YTsiz at 28, Csiz at 40
+	// and Ssiz at 42
+	require.Equal(t, []byte{0xFF, 0x52}, valid[45:47], "COD")
+	sod := bytes.Index(valid, []byte{0xFF, 0xD3})
+	require.Positive(t, sod)
+
+	tests := []struct {
+		name    string
+		corrupt func(b []byte) []byte
+		err     string
+	}{
+		{"truncated", func(b []byte) []byte { return b[:20] }, ""},
+		{"zero width", func(b []byte) []byte { binary.BigEndian.PutUint32(b[8:], 0); return b }, "empty image area"},
+		{"offset past width", func(b []byte) []byte { binary.BigEndian.PutUint32(b[16:], 9); return b }, "empty image area"},
+		{"wrong frame size", func(b []byte) []byte { binary.BigEndian.PutUint32(b[12:], 4096); return b }, "frame is 8x8"},
+		{"precision 38", func(b []byte) []byte { b[42] = 37; return b }, "precision 38"},
+		{"zero tile width", func(b []byte) []byte { binary.BigEndian.PutUint32(b[24:], 0); return b }, "empty tile size"},
+		{"tiles missing", func(b []byte) []byte {
+			binary.BigEndian.PutUint32(b[24:], 4)
+			binary.BigEndian.PutUint32(b[28:], 4)
+			return b
+		}, "tile 1 of 4 is missing"},
+		{"tile grid past image", func(b []byte) []byte { binary.BigEndian.PutUint32(b[32:], 1); return b }, "does not cover"},
+		// COD follows the 41 byte SIZ: SPcod holds the code-block width
+		// at 55, the code-block style at 57 and the transform at 58
+		{"code-block size", func(b []byte) []byte { b[55] = 9; return b }, "code-block size 2048x64"},
+		{"bypass coding", func(b []byte) []byte { b[57] = 0x01; return b }, "code-block style"},
+		{"irreversible wavelet", func(b []byte) []byte { b[58] = 0; return b }, "irreversible"},
+		{"packet header past tile", func(b []byte) []byte {
+			for i := sod + 2; i < len(b)-2; i++ {
+				b[i] = 0xFF
+			}
+			return b
+		}, "tile 0: code-block length"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := tt.corrupt(bytes.Clone(valid))
+			var err error
+			require.NotPanics(t, func() { _, err = CodecJPEG2000.Decode(data, 8, 8) })
+			require.Error(t, err)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+// tiledJPEG2000 writes a 16-bit grayscale codestream of w x h samples cut
+// into tiles of tw x th, each split into parts tile-parts. The tile-parts are
+// written round-robin across the tiles, as T.800 allows. The tiles hold the
+// coefficient payload of earlier versions of this library.
+func tiledJPEG2000(t testing.TB, data []uint16, w, h, tw, th, parts int) []byte {
+	t.Helper()
+	const levels = 3
+	var buf bytes.Buffer
+	cw := jpeg2k.NewCodestreamWriter(&buf)
+	require.NoError(t, cw.WriteSOC())
+	siz := jpeg2k.BuildSIZ(w, h, []jpeg2k.ComponentInfo{{Precision: 16, XRsiz: 1, YRsiz: 1}}, tw, th)
+	require.NoError(t, cw.WriteSIZ(siz))
+	require.NoError(t, cw.WriteCOD(jpeg2k.BuildDefaultCOD(levels, 1, jpeg2k.ProgressionLRCP, false)))
+	require.NoError(t, cw.WriteQCD(jpeg2k.BuildDefaultQCD(levels, 2)))
+
+	var bodies [][]byte
+	for ty := 0; ty < h; ty += th {
+		for tx := 0; tx < w; tx += tw {
+			cols, rows := min(tw, w-tx), min(th, h-ty)
+			samples := make([]int, 0, cols*rows)
+			for y := range rows {
+				for x := range cols {
+					samples = append(samples, int(data[(ty+y)*w+tx+x]))
+				}
+			}
+			body, err := jpeg2k.NewTileEncoder(cols, rows, levels, 64, 64).EncodeTile(samples)
+			require.NoError(t, err)
+			bodies = append(bodies, body)
+		}
+	}
+	for part := range parts {
+		for i, body := range bodies {
+			n := (len(body) + parts - 1) / parts
+			chunk := body[min(part*n, len(body)):min((part+1)*n, len(body))]
+			require.NoError(t, cw.WriteSOT(&jpeg2k.SOTMarker{
+				TileIndex:    uint16(i),
+				TilePartLen:  uint32(12 + 2 + len(chunk)),
+				TilePartIdx:  uint8(part),
+				NumTileParts: uint8(parts),
+			}))
+			require.NoError(t, cw.WriteSOD())
+			require.NoError(t, cw.WriteBytes(chunk))
+		}
+	}
+	require.NoError(t, cw.WriteEOC())
+	require.NoError(t, cw.Flush())
+	return buf.Bytes()
+}
+
+func TestJPEG2000_Tiled(t *testing.T) {
+	const w, h = 20, 13 // partial tiles on the right and bottom
+	data := make([]uint16, w*h)
+	for i := range data {
+		data[i] = uint16(i * 977 % 65536)
+	}
+	for _, tc := range []struct {
+		name          string
+		tw, th, parts int
+	}{
+		{"single tile", w, h, 1},
+		{"grid", 8, 8, 1},
+		{"tile-parts", 8, 8, 3},
+		{"strips", w, 4, 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cs := tiledJPEG2000(t, data, w, h, tc.tw, tc.th, tc.parts)
+			img, err := CodecJPEG2000.Decode(cs, w, h)
+			require.NoError(t, err)
+			g, ok := img.(*image.Gray16)
+			require.True(t, ok, "%T", img)
+			for i, v := range data {
+				require.Equal(t, v, g.Gray16At(i%w, i/w).Y, "sample %d,%d", i%w, i/w)
+			}
+		})
+	}
+
+	cs := tiledJPEG2000(t, data, w, h, 8, 8, 1)
+	ctx := &countdownContext{Context: context.Background(), checks: 1}
+	_, err := CodecJPEG2000.(ContextDecoder).DecodeContext(ctx, cs, w, h)
+	assert.ErrorIs(t, err, context.Canceled, "cancelled between tiles")
+
+	// a tile-part out of order is refused rather than spliced
+	cs = tiledJPEG2000(t, data, w, h, 8, 8, 2)
+	sot := bytes.Index(cs, []byte{0xFF, 0x90})
+	require.Positive(t, sot)
+	cs[sot+10] = 1
+	_, err = CodecJPEG2000.Decode(cs, w, h)
+	assert.ErrorContains(t, err, "tile 0 part 1 is out of order, expected part 0")
+}
+
+func FuzzJPEG2000Decode(f *testing.F) {
+	valid := testJPEG2000Frame(f)
+	f.Add(valid)
+	for _, off := range []int{8, 12, 40, 42, 50} {
+		b := bytes.Clone(valid)
+		b[off] ^= 0xFF
+		f.Add(b)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		img, err := CodecJPEG2000.Decode(data, 8, 8)
+		if err == nil {
+			assert.Equal(t, image.Rect(0, 0, 8, 8), img.Bounds())
+		}
+	})
+}
+
+func TestJPEG2000_MQCoder(t *testing.T) {
+	// the MQ coder test sequence of ITU-T T.88 H.2, coded in one context
+	// starting from state 0
+	in := []byte{
+		0x00, 0x02, 0x00, 0x51, 0x00, 0x00, 0x00, 0xC0, 0x03, 0x52, 0x87, 0x2A, 0xAA, 0xAA, 0xAA, 0xAA,
+		0x82, 0xC0, 0x20, 0x00, 0xFC, 0xD7, 0x9E, 0xF6, 0xBF, 0x7F, 0xED, 0x90, 0x4F, 0x46, 0xA3, 0xBF,
+	}
+	want := []byte{
+		0x84, 0xC7, 0x3B, 0xFC, 0xE1, 0xA1, 0x43, 0x04, 0x02, 0x20, 0x00, 0x00, 0x41, 0x0D,
+		0xBB, 0x86, 0xF4, 0x31, 0x7F, 0xFF, 0x88, 0xFF, 0x37, 0x47, 0x1A, 0xDB, 0x6A, 0xDF,
+	}
+	e := newJ2KMQEncoder()
+	e.ctx[0] = j2kContext{}
+	for _, b := range in {
+		for i := 7; i >= 0; i-- {
+			e.encode(0, int(b>>i&1))
+		}
+	}
+	got := e.flush()
+	assert.Equal(t, want, got)
+
+	d := newJ2KMQDecoder(got)
+	d.ctx[0] = j2kContext{}
+	for i, b := range in {
+		var v byte
+		for range 8 {
+			v = v<<1 | byte(d.decode(0))
+		}
+		require.Equal(t, b, v, "byte %d", i)
+	}
+}
+
+func TestJPEG2000_Codestream(t *testing.T) {
+	const w, h = 40, 24
+	data := make([]uint16, w*h)
+	for i := range data {
+		data[i] = uint16(i * 97 % 4096)
+	}
+	var buf bytes.Buffer
+	require.NoError(t, CodecJPEG2000.(SampleEncoder).EncodeSamples(&buf, data, w, h, SampleFormat{16, 12, false}))
+	cs := buf.Bytes()
+
+	siz, cod, qcd, err := jpeg2k.ParseCodestreamHeader(cs)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(w), siz.XTsiz)
+	assert.Equal(t, jpeg2k.TransformReversible53, cod.Transform)
+	assert.Equal(t, jpeg2k.ProgressionLRCP, cod.Progression)
+	assert.Equal(t, uint16(1), cod.NumLayers)
+	assert.Equal(t, byte(4), cod.DecompLevels, "24 rows allow four levels")
+	assert.Equal(t, byte(2), qcd.GuardBits)
+	// one exponent per sub-band: LL, then HL, LH and HH per level
+	assert.Equal(t, []int16{12, 13, 13, 14, 13, 13, 14, 13, 13, 14, 13, 13, 14}, qcd.StepSizes)
+
+	// Psot covers the SOT marker segment, SOD and the packets up to EOC
+	sot := bytes.Index(cs, []byte{0xFF, 0x90})
+	require.Positive(t, sot)
+	assert.Equal(t, len(cs)-2-sot, int(binary.BigEndian.Uint32(cs[sot+6:])))
+	assert.Equal(t, []byte{0xFF, 0xD3}, cs[sot+12:sot+14])
+	assert.Equal(t, []byte{0xFF, 0xD9}, cs[len(cs)-2:])
+	assert.Less(t, len(cs), 2*len(data), "packets, not coefficients")
+}
+
+func TestJPEG2000_RoundTrip(t *testing.T) {
+	noise := func(n, bits int) []int32 {
+		out := make([]int32, n)
+		x := uint32(2463534242)
+		for i := range out {
+			x ^= x << 13
+			x ^= x >> 17
+			x ^= x << 5
+			out[i] = int32(x % (1 << bits))
+		}
+		return out
+	}
+	ramp := func(w, h, bits int) []int32 {
+		out := make([]int32, w*h)
+		for i := range out {
+			out[i] = int32((i%w*5 + i/w*3) % (1 << bits))
+		}
+		return out
+	}
+	for _, tc := range []struct {
+		name      string
+		w, h      int
+		tw, th    int
+		precision int
+		signed    bool
+		comps     func(w, h int) [][]int32
+	}{
+		{name: "1x1", w: 1, h: 1, precision: 8},
+		{name: "row", w: 7, h: 1, precision: 8},
+		{name: "column", w: 1, h: 9, precision: 12},
+		{name: "odd", w: 37, h: 19, precision: 12},
+		{name: "flat", w: 16, h: 16, precision: 16, comps: func(w, h int) [][]int32 { return [][]int32{make([]int32, w*h)} }},
+		{name: "noise 16 bit", w: 70, h: 66, precision: 16, comps: func(w, h int) [][]int32 { return [][]int32{noise(w*h, 16)} }},
+		{name: "extremes", w: 9, h: 9, precision: 16, comps: func(w, h int) [][]int32 {
+			c := make([]int32, w*h)
+			for i := range c {
+				c[i] = int32(i % 2 * 0xFFFF)
+			}
+			return [][]int32{c}
+		}},
+		{name: "signed", w: 33, h: 17, precision: 16, signed: true, comps: func(w, h int) [][]int32 {
+			c := noise(w*h, 16)
+			for i := range c {
+				c[i] -= 1 << 15
+			}
+			return [][]int32{c}
+		}},
+		{name: "tiles at odd offsets", w: 45, h: 29, tw: 13, th: 7, precision: 12},
+		{name: "rgb", w: 21, h: 14, precision: 8, comps: func(w, h int) [][]int32 {
+			return [][]int32{noise(w*h, 8), ramp(w, h, 8), noise(w*h, 3)}
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			img := &jpeg2000Image{width: tc.w, height: tc.h, precision: tc.precision, signed: tc.signed}
+			if tc.comps != nil {
+				img.comps = tc.comps(tc.w, tc.h)
+			} else {
+				img.comps = [][]int32{ramp(tc.w, tc.h, tc.precision)}
+			}
+			var buf bytes.Buffer
+			require.NoError(t, encodeJPEG2000(&buf, img, tc.tw, tc.th))
+
+			siz, cod, qcd, err := jpeg2k.ParseCodestreamHeader(buf.Bytes())
+			require.NoError(t, err)
+			require.NoError(t, checkJPEG2000Header(siz, cod, tc.w, tc.h))
+			got, err := decodeJPEG2000Tiles(context.Background(), buf.Bytes(), siz, cod, qcd)
+			require.NoError(t, err)
+			for c, want := range img.comps {
+				for i, v := range want {
+					x, y := i%tc.w, i/tc.w
+					var s int32
+					switch g := got.(type) {
+					case *image.Gray:
+						s = int32(g.GrayAt(x, y).Y)
+					case *image.Gray16:
+						s = int32(g.Gray16At(x, y).Y)
+					case *image.RGBA:
+						s = int32(g.RGBAAt(x, y).R)
+						if c > 0 {
+							s = int32([]uint8{0, g.RGBAAt(x, y).G, g.RGBAAt(x, y).B}[c])
+						}
+					}
+					if tc.signed {
+						s -= 1 << (tc.precision - 1)
+					}
+					require.Equal(t, v, s, "component %d sample %d,%d", c, x, y)
+				}
+			}
+		})
+	}
+}
+
+func TestJPEG2000_Progressions(t *testing.T) {
+	// with one layer, one component and one precinct per resolution every
+	// progression order codes the packets of testJPEG2000Frame in turn
+	valid := testJPEG2000Frame(t)
+	want, err := CodecJPEG2000.Decode(valid, 8, 8)
+	require.NoError(t, err)
+	for order := jpeg2k.ProgressionLRCP; order <= jpeg2k.ProgressionCPRL; order++ {
+		cs := bytes.Clone(valid)
+		cs[50] = byte(order)
+		got, err := CodecJPEG2000.Decode(cs, 8, 8)
+		require.NoError(t, err, order.String())
+		assert.Equal(t, want, got, order.String())
+	}
+}
@@ -0,0 +1,639 @@
+package dicos
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// MQ coder probability estimation (T.800 Table C.2)
+var (
+	j2kQe = [47]uint32{
+		0x5601, 0x3401, 0x1801, 0x0AC1, 0x0521, 0x0221, 0x5601, 0x5401,
+		0x4801, 0x3801, 0x3001, 0x2401, 0x1C01, 0x1601, 0x5601, 0x5401,
+		0x5101, 0x4801, 0x3801, 0x3401, 0x3001, 0x2801, 0x2401, 0x2201,
+		0x1C01, 0x1801, 0x1601, 0x1401, 0x1201, 0x1101, 0x0AC1, 0x09C1,
+		0x08A1, 0x0521, 0x0441, 0x02A1, 0x0221, 0x0141, 0x0111, 0x0085,
+		0x0049, 0x0025, 0x0015, 0x0009, 0x0005, 0x0001, 0x5601,
+	}
+	j2kNMPS = [47]uint8{
+		1, 2, 3, 4, 5, 38, 7, 8, 9, 10, 11, 12, 13, 29, 15, 16,
+		17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32,
+		33, 34, 35, 36, 37, 38, 39, 40, 41, 42, 43, 44, 45, 45, 46,
+	}
+	j2kNLPS = [47]uint8{
+		1, 6, 9, 12, 29, 33, 6, 14, 14, 14, 17, 18, 20, 21, 14, 14,
+		15, 16, 17, 18, 19, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29,
+		30, 31, 32, 33, 34, 35, 36, 37, 38, 39, 40, 41, 42, 43, 46,
+	}
+	j2kSwitch = [47]bool{0: true, 6: true, 14: true}
+)
+
+// Tier-1 contexts (T.800 D.3): nine zero coding, five sign coding, three
+// magnitude refinement, then run-length and uniform
+const (
+	j2kCtxSC  = 9
+	j2kCtxMR  = 14
+	j2kCtxRL  = 17
+	j2kCtxUNI = 18
+	j2kCtxs   = 19
+)
+
+// j2kContext is the state index and more probable symbol of an MQ context
+type j2kContext struct {
+	i, mps uint8
+}
+
+// j2kResetContexts sets the initial states of T.800 Table D.7
+func j2kResetContexts(ctx *[j2kCtxs]j2kContext) {
+	*ctx = [j2kCtxs]j2kContext{}
+	ctx[0].i = 4
+	ctx[j2kCtxRL].i = 3
+	ctx[j2kCtxUNI].i = 46
+}
+
+// j2kMQEncoder is the MQ arithmetic encoder of T.800 C.2
+type j2kMQEncoder struct {
+	a, c uint32
+	ct   int
+	out  []byte // out[0] stands in for the byte before the codeword
+	ctx  [j2kCtxs]j2kContext
+}
+
+func newJ2KMQEncoder() *j2kMQEncoder {
+	e := &j2kMQEncoder{a: 0x8000, ct: 12, out: []byte{0}}
+	j2kResetContexts(&e.ctx)
+	return e
+}
+
+func (e *j2kMQEncoder) encode(cx int, d int) {
+	st := &e.ctx[cx]
+	qe := j2kQe[st.i]
+	e.a -= qe
+	if d == int(st.mps) {
+		if e.a&0x8000 != 0 {
+			e.c += qe
+			return
+		}
+		if e.a < qe {
+			e.a = qe
+		} else {
+			e.c += qe
+		}
+		st.i = j2kNMPS[st.i]
+	} else {
+		if e.a < qe {
+			e.c += qe
+		} else {
+			e.a = qe
+		}
+		if j2kSwitch[st.i] {
+			st.mps ^= 1
+		}
+		st.i = j2kNLPS[st.i]
+	}
+	for {
+		e.a <<= 1
+		e.c <<= 1
+		e.ct--
+		if e.ct == 0 {
+			e.byteOut()
+		}
+		if e.a&0x8000 != 0 {
+			return
+		}
+	}
+}
+
+func (e *j2kMQEncoder) byteOut() {
+	b := &e.out[len(e.out)-1]
+	if *b != 0xFF {
+		if e.c < 0x8000000 {
+			e.out = append(e.out, byte(e.c>>19))
+			e.c &= 0x7FFFF
+			e.ct = 8
+			return
+		}
+		*b++
+		if *b != 0xFF {
+			e.c &= 0x7FFFFFF
+			e.out = append(e.out, byte(e.c>>19))
+			e.c &= 0x7FFFF
+			e.ct = 8
+			return
+		}
+		e.c &= 0x7FFFFFF
+	}
+	e.out = append(e.out, byte(e.c>>20))
+	e.c &= 0xFFFFF
+	e.ct = 7
+}
+
+// flush terminates the codeword (T.800 C.2.9) and returns it
+func (e *j2kMQEncoder) flush() []byte {
+	t := e.c + e.a
+	e.c |= 0xFFFF
+	if e.c >= t {
+		e.c -= 0x8000
+	}
+	e.c <<= e.ct
+	e.byteOut()
+	e.c <<= e.ct
+	e.byteOut()
+	out := e.out[1:]
+	if n := len(out); n > 0 && out[n-1] == 0xFF {
+		out = out[:n-1]
+	}
+	return out
+}
+
+// j2kMQDecoder is the MQ arithmetic decoder of T.800 C.3. Reading past the
+// codeword feeds 0xFF bytes, as a marker would.
+type j2kMQDecoder struct {
+	data []byte
+	pos  int
+	a, c uint32
+	ct   int
+	ctx  [j2kCtxs]j2kContext
+}
+
+func newJ2KMQDecoder(data []byte) *j2kMQDecoder {
+	d := &j2kMQDecoder{data: data}
+	j2kResetContexts(&d.ctx)
+	d.c = uint32(d.at(0)) << 16
+	d.byteIn()
+	d.c <<= 7
+	d.ct -= 7
+	d.a = 0x8000
+	return d
+}
+
+func (d *j2kMQDecoder) at(i int) byte {
+	if i < len(d.data) {
+		return d.data[i]
+	}
+	return 0xFF
+}
+
+func (d *j2kMQDecoder) byteIn() {
+	if d.at(d.pos) == 0xFF {
+		if d.at(d.pos+1) > 0x8F {
+			d.c += 0xFF00
+			d.ct = 8
+			return
+		}
+		d.pos++
+		d.c += uint32(d.at(d.pos)) << 9
+		d.ct = 7
+		return
+	}
+	d.pos++
+	d.c += uint32(d.at(d.pos)) << 8
+	d.ct = 8
+}
+
+func (d *j2kMQDecoder) decode(cx int) int {
+	st := &d.ctx[cx]
+	qe := j2kQe[st.i]
+	d.a -= qe
+	var bit int
+	if d.c>>16 < qe {
+		if d.a < qe {
+			bit = int(st.mps)
+			st.i = j2kNMPS[st.i]
+		} else {
+			bit = int(st.mps ^ 1)
+			if j2kSwitch[st.i] {
+				st.mps ^= 1
+			}
+			st.i = j2kNLPS[st.i]
+		}
+		d.a = qe
+	} else {
+		d.c -= qe << 16
+		if d.a&0x8000 != 0 {
+			return int(st.mps)
+		}
+		if d.a < qe {
+			bit = int(st.mps ^ 1)
+			if j2kSwitch[st.i] {
+				st.mps ^= 1
+			}
+			st.i = j2kNLPS[st.i]
+		} else {
+			bit = int(st.mps)
+			st.i = j2kNMPS[st.i]
+		}
+	}
+	for {
+		if d.ct == 0 {
+			d.byteIn()
+		}
+		d.a <<= 1
+		d.c <<= 1
+		d.ct--
+		if d.a&0x8000 != 0 {
+			return bit
+		}
+	}
+}
+
+// Code-block style flags of SPcod (T.800 Table A.19)
+const (
+	j2kStyleBypass   = 0x01
+	j2kStyleReset    = 0x02
+	j2kStyleTermAll  = 0x04
+	j2kStyleVCausal  = 0x08
+	j2kStyleSegSymbs = 0x20
+)
+
+// Sub-band orientations, in the order T.800 codes them
+const (
+	j2kLL = iota
+	j2kHL
+	j2kLH
+	j2kHH
+)
+
+// Tier-1 coefficient state. The low byte holds the significance of the
+// eight neighbours; four bits hold the signs of the N, S, W and E ones.
+const (
+	j2kSigNW = 1 << iota
+	j2kSigN
+	j2kSigNE
+	j2kSigW
+	j2kSigE
+	j2kSigSW
+	j2kSigS
+	j2kSigSE
+	j2kNegN
+	j2kNegS
+	j2kNegW
+	j2kNegE
+	j2kSig     // significant
+	j2kVisited // coded by the significance pass of this bit-plane
+	j2kRefined // refined at least once
+	j2kNeg     // negative
+
+	j2kNeighbours = 0xFF
+	// neighbours of the next stripe, ignored with vertically causal contexts
+	j2kBelow = j2kSigSW | j2kSigS | j2kSigSE | j2kNegS
+)
+
+// j2kZC maps neighbour significance to zero coding contexts per
+// orientation (T.800 Table D.1)
+var j2kZC = func() (t [4][256]uint8) {
+	for o := range t {
+		for f := range 256 {
+			h := f>>3&1 + f>>4&1
+			v := f>>1&1 + f>>6&1
+			d := f&1 + f>>2&1 + f>>5&1 + f>>7&1
+			var n int
+			switch o {
+			case j2kHH:
+				hv := h + v
+				switch {
+				case d >= 3:
+					n = 8
+				case d == 2:
+					n = 6 + min(hv, 1)
+				case d == 1:
+					n = 3 + min(hv, 2)
+				default:
+					n = min(hv, 2)
+				}
+			default:
+				if o == j2kHL {
+					h, v = v, h
+				}
+				switch {
+				case h == 2:
+					n = 8
+				case h == 1 && v >= 1:
+					n = 7
+				case h == 1:
+					n = 5 + min(d, 1)
+				case v == 2:
+					n = 4
+				case v == 1:
+					n = 3
+				default:
+					n = min(d, 2)
+				}
+			}
+			t[o][f] = uint8(n)
+		}
+	}
+	return t
+}()
+
+// j2kSignContext returns the sign coding context and the bit it is
+// XORed with (T.800 Table D.3)
+func j2kSignContext(f uint32) (int, int) {
+	contrib := func(sig, neg uint32) int {
+		switch {
+		case f&sig == 0:
+			return 0
+		case f&neg != 0:
+			return -1
+		}
+		return 1
+	}
+	h := max(-1, min(1, contrib(j2kSigW, j2kNegW)+contrib(j2kSigE, j2kNegE)))
+	v := max(-1, min(1, contrib(j2kSigN, j2kNegN)+contrib(j2kSigS, j2kNegS)))
+	xor := 0
+	if h < 0 || (h == 0 && v < 0) {
+		h, v, xor = -h, -v, 1
+	}
+	if h == 0 {
+		return j2kCtxSC + min(v, 1), xor
+	}
+	return j2kCtxSC + 3 + v, xor
+}
+
+// j2kRefineContext returns the magnitude refinement context (T.800 Table D.4)
+func j2kRefineContext(f uint32) int {
+	switch {
+	case f&j2kRefined != 0:
+		return j2kCtxMR + 2
+	case f&j2kNeighbours != 0:
+		return j2kCtxMR + 1
+	}
+	return j2kCtxMR
+}
+
+// j2kBlockCoder holds the Tier-1 state of one code-block: the coefficient
+// flags with a one coefficient border, stripes of four rows scanned column
+// by column (T.800 D.1)
+type j2kBlockCoder struct {
+	w, h   int
+	orient int
+	style  byte
+	flags  []uint32
+}
+
+func newJ2KBlockCoder(w, h, orient int, style byte) *j2kBlockCoder {
+	return &j2kBlockCoder{w: w, h: h, orient: orient, style: style, flags: make([]uint32, (w+2)*(h+2))}
+}
+
+// flag returns the state of the coefficient at x, y, without the row below
+// a stripe when contexts are vertically causal
+func (t *j2kBlockCoder) flag(x, y int) uint32 {
+	f := t.flags[(y+1)*(t.w+2)+x+1]
+	if t.style&j2kStyleVCausal != 0 && y%4 == 3 {
+		f &^= j2kBelow
+	}
+	return f
+}
+
+func (t *j2kBlockCoder) mark(x, y int, bit uint32) {
+	t.flags[(y+1)*(t.w+2)+x+1] |= bit
+}
+
+// significant marks the coefficient at x, y significant for its neighbours
+func (t *j2kBlockCoder) significant(x, y int, neg bool) {
+	s := t.w + 2
+	i := (y+1)*s + x + 1
+	t.flags[i] |= j2kSig
+	t.flags[i-s-1] |= j2kSigSE
+	t.flags[i-s+1] |= j2kSigSW
+	t.flags[i+s-1] |= j2kSigNE
+	t.flags[i+s+1] |= j2kSigNW
+	t.flags[i-s] |= j2kSigS
+	t.flags[i+s] |= j2kSigN
+	t.flags[i-1] |= j2kSigE
+	t.flags[i+1] |= j2kSigW
+	if neg {
+		t.flags[i] |= j2kNeg
+		t.flags[i-s] |= j2kNegS
+		t.flags[i+s] |= j2kNegN
+		t.flags[i-1] |= j2kNegE
+		t.flags[i+1] |= j2kNegW
+	}
+}
+
+func (t *j2kBlockCoder) clearVisited() {
+	for i := range t.flags {
+		t.flags[i] &^= j2kVisited
+	}
+}
+
+// runLength returns true if column x of the stripe at y0 is coded in run
+// mode: four rows, none significant or visited, all with zero context
+func (t *j2kBlockCoder) runLength(x, y0 int) bool {
+	if y0+4 > t.h {
+		return false
+	}
+	for y := y0; y < y0+4; y++ {
+		if t.flag(x, y)&(j2kSig|j2kVisited|j2kNeighbours) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// encodeJ2KBlock codes the coefficients of a w by h code-block, row major,
+// with every coding pass in one codeword. It returns the codeword, the
+// number of passes and the number of magnitude bit-planes.
+func encodeJ2KBlock(coef []int32, w, h, orient int) ([]byte, int, int) {
+	var top uint32
+	for _, v := range coef {
+		top |= j2kAbs(v)
+	}
+	planes := bits.Len32(top)
+	if planes == 0 {
+		return nil, 0, 0
+	}
+	t := newJ2KBlockCoder(w, h, orient, 0)
+	mq := newJ2KMQEncoder()
+	bit := func(x, y, p int) int {
+		return int(j2kAbs(coef[y*w+x]) >> p & 1)
+	}
+	sign := func(x, y int) {
+		cx, xor := j2kSignContext(t.flag(x, y))
+		neg := coef[y*w+x] < 0
+		mq.encode(cx, j2kBool(neg)^xor)
+		t.significant(x, y, neg)
+	}
+	for p := planes - 1; p >= 0; p-- {
+		if p < planes-1 {
+			// significance propagation
+			for y0 := 0; y0 < h; y0 += 4 {
+				for x := range w {
+					for y := y0; y < min(y0+4, h); y++ {
+						f := t.flag(x, y)
+						if f&j2kSig != 0 || f&j2kNeighbours == 0 {
+							continue
+						}
+						b := bit(x, y, p)
+						mq.encode(int(j2kZC[orient][f&j2kNeighbours]), b)
+						t.mark(x, y, j2kVisited)
+						if b != 0 {
+							sign(x, y)
+						}
+					}
+				}
+			}
+			// magnitude refinement
+			for y0 := 0; y0 < h; y0 += 4 {
+				for x := range w {
+					for y := y0; y < min(y0+4, h); y++ {
+						f := t.flag(x, y)
+						if f&(j2kSig|j2kVisited) != j2kSig {
+							continue
+						}
+						mq.encode(j2kRefineContext(f), bit(x, y, p))
+						t.mark(x, y, j2kRefined)
+					}
+				}
+			}
+		}
+		// cleanup
+		for y0 := 0; y0 < h; y0 += 4 {
+			for x := range w {
+				y := y0
+				if t.runLength(x, y0) {
+					for y < y0+4 && bit(x, y, p) == 0 {
+						y++
+					}
+					if y == y0+4 {
+						mq.encode(j2kCtxRL, 0)
+						continue
+					}
+					mq.encode(j2kCtxRL, 1)
+					mq.encode(j2kCtxUNI, (y-y0)>>1)
+					mq.encode(j2kCtxUNI, (y-y0)&1)
+					sign(x, y)
+					y++
+				}
+				for ; y < min(y0+4, h); y++ {
+					f := t.flag(x, y)
+					if f&(j2kSig|j2kVisited) != 0 {
+						continue
+					}
+					b := bit(x, y, p)
+					mq.encode(int(j2kZC[orient][f&j2kNeighbours]), b)
+					if b != 0 {
+						sign(x, y)
+					}
+				}
+			}
+		}
+		t.clearVisited()
+	}
+	return mq.flush(), 3*planes - 2, planes
+}
+
+// decodeJ2KBlock decodes passes coding passes of a w by h code-block with
+// planes magnitude bit-planes into row major coefficients. Coefficients
+// left unrefined by a truncated codeword are reconstructed at the middle
+// of their interval.
+func decodeJ2KBlock(data []byte, w, h, orient int, style byte, passes, planes int) ([]int32, error) {
+	if style&(j2kStyleBypass|j2kStyleTermAll) != 0 {
+		return nil, fmt.Errorf("%w: code-block style %#02x", ErrUnsupportedPixelFormat, style)
+	}
+	if passes > 3*planes-2 {
+		return nil, fmt.Errorf("code-block has %d coding passes for %d bit-planes", passes, planes)
+	}
+	// magnitudes in half steps, so the middle of the lowest interval is exact
+	mag := make([]uint32, w*h)
+	t := newJ2KBlockCoder(w, h, orient, style)
+	mq := newJ2KMQDecoder(data)
+	sign := func(x, y, p int) {
+		cx, xor := j2kSignContext(t.flag(x, y))
+		t.significant(x, y, mq.decode(cx)^xor != 0)
+		mag[y*w+x] = 3 << p
+	}
+	for pass := range passes {
+		p := planes - 1 - (pass+2)/3
+		switch pass % 3 {
+		case 1: // significance propagation
+			for y0 := 0; y0 < h; y0 += 4 {
+				for x := range w {
+					for y := y0; y < min(y0+4, h); y++ {
+						f := t.flag(x, y)
+						if f&j2kSig != 0 || f&j2kNeighbours == 0 {
+							continue
+						}
+						t.mark(x, y, j2kVisited)
+						if mq.decode(int(j2kZC[orient][f&j2kNeighbours])) != 0 {
+							sign(x, y, p)
+						}
+					}
+				}
+			}
+		case 2: // magnitude refinement
+			for y0 := 0; y0 < h; y0 += 4 {
+				for x := range w {
+					for y := y0; y < min(y0+4, h); y++ {
+						f := t.flag(x, y)
+						if f&(j2kSig|j2kVisited) != j2kSig {
+							continue
+						}
+						if mq.decode(j2kRefineContext(f)) != 0 {
+							mag[y*w+x] += 1 << p
+						} else {
+							mag[y*w+x] -= 1 << p
+						}
+						t.mark(x, y, j2kRefined)
+					}
+				}
+			}
+		case 0: // cleanup
+			for y0 := 0; y0 < h; y0 += 4 {
+				for x := range w {
+					y := y0
+					if t.runLength(x, y0) {
+						if mq.decode(j2kCtxRL) == 0 {
+							continue
+						}
+						y += mq.decode(j2kCtxUNI)<<1 | mq.decode(j2kCtxUNI)
+						sign(x, y, p)
+						y++
+					}
+					for ; y < min(y0+4, h); y++ {
+						f := t.flag(x, y)
+						if f&(j2kSig|j2kVisited) != 0 {
+							continue
+						}
+						if mq.decode(int(j2kZC[orient][f&j2kNeighbours])) != 0 {
+							sign(x, y, p)
+						}
+					}
+				}
+			}
+			t.clearVisited()
+			if style&j2kStyleSegSymbs != 0 {
+				for range 4 {
+					mq.decode(j2kCtxUNI)
+				}
+			}
+		}
+		if style&j2kStyleReset != 0 {
+			j2kResetContexts(&mq.ctx)
+		}
+	}
+
+	coef := make([]int32, w*h)
+	for i, m := range mag {
+		v := int32(m >> 1)
+		if t.flags[(i/w+1)*(w+2)+i%w+1]&j2kNeg != 0 {
+			v = -v
+		}
+		coef[i] = v
+	}
+	return coef, nil
+}
+
+func j2kAbs(v int32) uint32 {
+	if v < 0 {
+		return uint32(-v)
+	}
+	return uint32(v)
+}
+
+func j2kBool(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
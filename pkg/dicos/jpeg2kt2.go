@@ -0,0 +1,592 @@
+package dicos
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"math"
+	"math/bits"
+	"slices"
+
+	"github.com/jpfielding/jpegs/pkg/compress/jpeg2k"
+)
+
+// j2kTagTree codes a grid of values through a quad-tree of their minimums
+// (T.800 B.10.2)
+type j2kTagTree struct {
+	nodes []j2kTagNode
+}
+
+type j2kTagNode struct {
+	parent int
+	value  int
+	low    int
+	known  bool
+}
+
+// newJ2KTagTree returns a tree over w x h leaves, which are its first nodes
+// in raster order, with every value unknown
+func newJ2KTagTree(w, h int) *j2kTagTree {
+	t := &j2kTagTree{}
+	if w == 0 || h == 0 {
+		return t
+	}
+	base := 0
+	for {
+		pw, ph := (w+1)/2, (h+1)/2
+		root := w == 1 && h == 1
+		for y := range h {
+			for x := range w {
+				parent := -1
+				if !root {
+					parent = base + w*h + y/2*pw + x/2
+				}
+				t.nodes = append(t.nodes, j2kTagNode{parent: parent, value: math.MaxInt32})
+			}
+		}
+		if root {
+			return t
+		}
+		base += w * h
+		w, h = pw, ph
+	}
+}
+
+// set gives leaf its value; the tree is built by setting every leaf once
+func (t *j2kTagTree) set(leaf, v int) {
+	for n := leaf; n >= 0 && t.nodes[n].value > v; n = t.nodes[n].parent {
+		t.nodes[n].value = v
+	}
+}
+
+// path returns the nodes from the root down to leaf
+func (t *j2kTagTree) path(leaf int) []int {
+	var p []int
+	for n := leaf; n >= 0; n = t.nodes[n].parent {
+		p = append(p, n)
+	}
+	slices.Reverse(p)
+	return p
+}
+
+// encode writes what a decoder needs to tell whether leaf is below threshold
+func (t *j2kTagTree) encode(bw *j2kBitWriter, leaf, threshold int) {
+	low := 0
+	for _, n := range t.path(leaf) {
+		node := &t.nodes[n]
+		low = max(low, node.low)
+		for low < threshold {
+			if low >= node.value {
+				if !node.known {
+					bw.put(1)
+					node.known = true
+				}
+				break
+			}
+			bw.put(0)
+			low++
+		}
+		node.low = low
+	}
+}
+
+// decode reads the bits encode wrote and returns true if leaf is below
+// threshold
+func (t *j2kTagTree) decode(br *j2kBitReader, leaf, threshold int) bool {
+	low := 0
+	for _, n := range t.path(leaf) {
+		node := &t.nodes[n]
+		low = max(low, node.low)
+		for low < threshold && low < node.value {
+			if br.bit() != 0 {
+				node.value = low
+			} else {
+				low++
+			}
+		}
+		node.low = low
+	}
+	return t.nodes[leaf].value < threshold
+}
+
+// j2kBitWriter writes packet headers, stuffing a zero bit after every 0xFF
+// byte (T.800 B.10.1)
+type j2kBitWriter struct {
+	out []byte
+	buf uint32
+	ct  int
+}
+
+func newJ2KBitWriter() *j2kBitWriter {
+	return &j2kBitWriter{ct: 8}
+}
+
+func (w *j2kBitWriter) byteOut() {
+	w.buf = w.buf << 8 & 0xFFFF
+	w.ct = 8
+	if w.buf == 0xFF00 {
+		w.ct = 7
+	}
+	w.out = append(w.out, byte(w.buf>>8))
+}
+
+func (w *j2kBitWriter) put(b int) {
+	if w.ct == 0 {
+		w.byteOut()
+	}
+	w.ct--
+	w.buf |= uint32(b) << w.ct
+}
+
+func (w *j2kBitWriter) putBits(v, n int) {
+	for i := n - 1; i >= 0; i-- {
+		w.put(v >> i & 1)
+	}
+}
+
+// flush pads the header to a byte boundary, adding a byte after 0xFF
+func (w *j2kBitWriter) flush() []byte {
+	w.byteOut()
+	if w.ct == 7 {
+		w.byteOut()
+	}
+	return w.out
+}
+
+// j2kBitReader reads packet headers written by j2kBitWriter
+type j2kBitReader struct {
+	data []byte
+	pos  int
+	buf  uint32
+	ct   int
+	over bool // read past the data
+}
+
+func (r *j2kBitReader) byteIn() {
+	r.buf = r.buf << 8 & 0xFFFF
+	r.ct = 8
+	if r.buf == 0xFF00 {
+		r.ct = 7
+	}
+	if r.pos < len(r.data) {
+		r.buf |= uint32(r.data[r.pos])
+		r.pos++
+	} else {
+		r.over = true
+	}
+}
+
+func (r *j2kBitReader) bit() int {
+	if r.ct == 0 {
+		r.byteIn()
+	}
+	r.ct--
+	return int(r.buf >> r.ct & 1)
+}
+
+func (r *j2kBitReader) bits(n int) int {
+	v := 0
+	for range n {
+		v = v<<1 | r.bit()
+	}
+	return v
+}
+
+// align skips to the end of the header, past a stuffed byte after 0xFF
+func (r *j2kBitReader) align() {
+	if r.buf&0xFF == 0xFF {
+		r.byteIn()
+	}
+	r.ct = 0
+}
+
+// j2kBlock is a code-block: its bounds in sub-band coordinates and its
+// codeword
+type j2kBlock struct {
+	x0, y0, x1, y1 int
+	data           []byte
+	passes         int
+	planes         int // magnitude bit-planes, Mb less the zero bit-planes
+	included       bool
+	lblock         int
+	// contribution of the packet being decoded
+	newPasses, newLen int
+}
+
+// j2kBand is a sub-band of a tile-component
+type j2kBand struct {
+	orient         int
+	x0, y0, x1, y1 int // sub-band coordinates
+	ox, oy         int // offset in the tile-component coefficients
+	exp            int // exponent of the quantization step
+	planes         int // Mb, the magnitude bit-planes (T.800 E-2)
+}
+
+// j2kPrecinctBand holds the code-blocks of a sub-band inside one precinct
+type j2kPrecinctBand struct {
+	band       *j2kBand
+	blocks     []*j2kBlock
+	incl, zero *j2kTagTree
+}
+
+// j2kPrecinct is the unit of a packet: the code-blocks of the sub-bands of
+// a resolution inside one precinct
+type j2kPrecinct struct {
+	bands []*j2kPrecinctBand
+	x, y  int // reference grid position the position progressions visit it at
+}
+
+type j2kResolution struct {
+	x0, y0, x1, y1 int
+	bands          []*j2kBand
+	precincts      []*j2kPrecinct
+}
+
+// j2kComponent is a tile-component partitioned into resolutions,
+// sub-bands, precincts and code-blocks (T.800 B.5 to B.7)
+type j2kComponent struct {
+	x0, y0, x1, y1 int
+	res            []*j2kResolution
+	coef           []int32 // wavelet coefficients, LL at the top left
+}
+
+func j2kCeilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
+
+// newJ2KComponent lays out the tile-component at x0,y0-x1,y1 with the
+// coding style of cod and the step exponents of its 3*levels+1 sub-bands
+func newJ2KComponent(x0, y0, x1, y1 int, cod *jpeg2k.CODMarker, exps []int) (*j2kComponent, error) {
+	levels := int(cod.DecompLevels)
+	xcb, ycb := int(cod.CodeBlockWidthExp)+2, int(cod.CodeBlockHeightExp)+2
+	if xcb > 10 || ycb > 10 || xcb+ycb > 12 {
+		return nil, fmt.Errorf("code-block size %dx%d", 1<<xcb, 1<<ycb)
+	}
+	if len(exps) < 3*levels+1 {
+		return nil, fmt.Errorf("%d quantization exponents for %d decomposition levels", len(exps), levels)
+	}
+	if cod.Scod&jpeg2k.CodingStylePrecinctsUser != 0 && len(cod.PrecinctSizes) < levels+1 {
+		return nil, fmt.Errorf("%d precinct sizes for %d resolutions", len(cod.PrecinctSizes), levels+1)
+	}
+
+	c := &j2kComponent{x0: x0, y0: y0, x1: x1, y1: y1, coef: make([]int32, (x1-x0)*(y1-y0))}
+	for r := 0; r <= levels; r++ {
+		s := 1 << (levels - r)
+		res := &j2kResolution{
+			x0: j2kCeilDiv(x0, s), y0: j2kCeilDiv(y0, s),
+			x1: j2kCeilDiv(x1, s), y1: j2kCeilDiv(y1, s),
+		}
+		c.res = append(c.res, res)
+
+		if r == 0 {
+			res.bands = []*j2kBand{{orient: j2kLL, x0: res.x0, y0: res.y0, x1: res.x1, y1: res.y1, exp: exps[0]}}
+		} else {
+			low := c.res[r-1]
+			lw, lh := low.x1-low.x0, low.y1-low.y0
+			nb := levels - r + 1
+			for o := j2kHL; o <= j2kHH; o++ {
+				xo, yo := o&1, o>>1
+				band := &j2kBand{
+					orient: o,
+					x0:     j2kCeilDiv(x0-xo<<(nb-1), 1<<nb),
+					y0:     j2kCeilDiv(y0-yo<<(nb-1), 1<<nb),
+					x1:     j2kCeilDiv(x1-xo<<(nb-1), 1<<nb),
+					y1:     j2kCeilDiv(y1-yo<<(nb-1), 1<<nb),
+					ox:     xo * lw,
+					oy:     yo * lh,
+					exp:    exps[3*(r-1)+o],
+				}
+				res.bands = append(res.bands, band)
+			}
+		}
+
+		ppx, ppy := 15, 15
+		if cod.Scod&jpeg2k.CodingStylePrecinctsUser != 0 {
+			ppx, ppy = int(cod.PrecinctSizes[r]&0x0F), int(cod.PrecinctSizes[r]>>4)
+			if r > 0 && (ppx == 0 || ppy == 0) {
+				return nil, fmt.Errorf("resolution %d precinct size %dx%d", r, 1<<ppx, 1<<ppy)
+			}
+		}
+		if res.x1 <= res.x0 || res.y1 <= res.y0 {
+			continue // no precincts, no packets
+		}
+		px0, py0 := res.x0>>ppx, res.y0>>ppy
+		npx, npy := j2kCeilDiv(res.x1, 1<<ppx)-px0, j2kCeilDiv(res.y1, 1<<ppy)-py0
+		// band precincts and code-blocks are half the size above resolution 0
+		bpx, bpy := ppx, ppy
+		if r > 0 {
+			bpx, bpy = ppx-1, ppy-1
+		}
+		cbx, cby := min(xcb, bpx), min(ycb, bpy)
+		for k := range npx * npy {
+			rx, ry := (px0+k%npx)<<ppx, (py0+k/npx)<<ppy
+			p := &j2kPrecinct{x: max(x0, rx<<(levels-r)), y: max(y0, ry<<(levels-r))}
+			if r > 0 {
+				rx, ry = rx>>1, ry>>1
+			}
+			for _, band := range res.bands {
+				pb := &j2kPrecinctBand{band: band}
+				bx0, by0 := max(band.x0, rx), max(band.y0, ry)
+				bx1, by1 := min(band.x1, rx+1<<bpx), min(band.y1, ry+1<<bpy)
+				var cw, ch int
+				if bx0 < bx1 && by0 < by1 {
+					cx0, cy0 := bx0>>cbx, by0>>cby
+					cw, ch = j2kCeilDiv(bx1, 1<<cbx)-cx0, j2kCeilDiv(by1, 1<<cby)-cy0
+					for j := range cw * ch {
+						bx, by := (cx0+j%cw)<<cbx, (cy0+j/cw)<<cby
+						pb.blocks = append(pb.blocks, &j2kBlock{
+							x0: max(bx0, bx), y0: max(by0, by),
+							x1: min(bx1, bx+1<<cbx), y1: min(by1, by+1<<cby),
+							lblock: 3,
+						})
+					}
+				}
+				pb.incl, pb.zero = newJ2KTagTree(cw, ch), newJ2KTagTree(cw, ch)
+				p.bands = append(p.bands, pb)
+			}
+			res.precincts = append(res.precincts, p)
+		}
+	}
+	return c, nil
+}
+
+// setPlanes sets Mb of every sub-band from the guard bits (T.800 E-2)
+func (c *j2kComponent) setPlanes(guard int) {
+	for _, res := range c.res {
+		for _, band := range res.bands {
+			band.planes = guard + band.exp - 1
+		}
+	}
+}
+
+// blockCoef returns the coefficients of a code-block, row major
+func (c *j2kComponent) blockCoef(band *j2kBand, b *j2kBlock) []int32 {
+	w := b.x1 - b.x0
+	out := make([]int32, 0, w*(b.y1-b.y0))
+	for y := b.y0; y < b.y1; y++ {
+		i := (band.oy+y-band.y0)*(c.x1-c.x0) + band.ox + b.x0 - band.x0
+		out = append(out, c.coef[i:i+w]...)
+	}
+	return out
+}
+
+// setBlockCoef stores the decoded coefficients of a code-block
+func (c *j2kComponent) setBlockCoef(band *j2kBand, b *j2kBlock, coef []int32) {
+	w := b.x1 - b.x0
+	for y := b.y0; y < b.y1; y++ {
+		i := (band.oy+y-band.y0)*(c.x1-c.x0) + band.ox + b.x0 - band.x0
+		copy(c.coef[i:i+w], coef[(y-b.y0)*w:])
+	}
+}
+
+// encodePacket writes the only packet of p in a single layer codestream:
+// every code-block with a codeword, with all its coding passes (T.800 B.10)
+func (p *j2kPrecinct) encodePacket(out *bytes.Buffer) {
+	bw := newJ2KBitWriter()
+	var body []byte
+	for _, pb := range p.bands {
+		for i, b := range pb.blocks {
+			if b.passes > 0 {
+				pb.incl.set(i, 0)
+				body = append(body, b.data...)
+			} else {
+				pb.incl.set(i, 1)
+			}
+			pb.zero.set(i, pb.band.planes-b.planes)
+		}
+	}
+	if len(body) == 0 {
+		bw.put(0) // empty packet
+		out.Write(bw.flush())
+		return
+	}
+	bw.put(1)
+	for _, pb := range p.bands {
+		for i, b := range pb.blocks {
+			pb.incl.encode(bw, i, 1)
+			if b.passes == 0 {
+				continue
+			}
+			pb.zero.encode(bw, i, math.MaxInt32)
+			j2kPutPasses(bw, b.passes)
+			n := b.lblock + bits.Len(uint(b.passes)) - 1
+			for len(b.data) >= 1<<n {
+				bw.put(1)
+				b.lblock++
+				n++
+			}
+			bw.put(0)
+			bw.putBits(len(b.data), n)
+		}
+	}
+	out.Write(bw.flush())
+	out.Write(body)
+}
+
+// j2kPutPasses codes the number of coding passes (T.800 Table B.4)
+func j2kPutPasses(bw *j2kBitWriter, n int) {
+	switch {
+	case n == 1:
+		bw.put(0)
+	case n == 2:
+		bw.putBits(0b10, 2)
+	case n <= 5:
+		bw.putBits(0b11, 2)
+		bw.putBits(n-3, 2)
+	case n <= 36:
+		bw.putBits(0b1111, 4)
+		bw.putBits(n-6, 5)
+	default:
+		bw.putBits(0b1_1111_1111, 9)
+		bw.putBits(n-37, 7)
+	}
+}
+
+func j2kGetPasses(br *j2kBitReader) int {
+	if br.bit() == 0 {
+		return 1
+	}
+	if br.bit() == 0 {
+		return 2
+	}
+	if v := br.bits(2); v != 3 {
+		return 3 + v
+	}
+	if v := br.bits(5); v != 31 {
+		return 6 + v
+	}
+	return 37 + br.bits(7)
+}
+
+// decodePacket reads the packet of p for layer from data and appends the
+// code-block contributions to their codewords. It returns the packet length.
+func (p *j2kPrecinct) decodePacket(data []byte, layer int, sop, eph bool) (int, error) {
+	pos := 0
+	if sop && len(data) >= 6 && data[0] == 0xFF && data[1] == 0x91 {
+		pos = 6
+	}
+	br := &j2kBitReader{data: data[pos:]}
+	if br.bit() == 0 {
+		br.align()
+		return p.packetEnd(data, pos+br.pos, eph, br.over)
+	}
+	for _, pb := range p.bands {
+		for i, b := range pb.blocks {
+			b.newPasses, b.newLen = 0, 0
+			var in bool
+			if b.included {
+				in = br.bit() != 0
+			} else {
+				in = pb.incl.decode(br, i, layer+1)
+			}
+			if !in {
+				continue
+			}
+			if !b.included {
+				zero := 1
+				for !pb.zero.decode(br, i, zero) {
+					if zero++; zero > pb.band.planes+1 || br.over {
+						return 0, fmt.Errorf("code-block has more zero bit-planes than the %d of its sub-band", pb.band.planes)
+					}
+				}
+				b.included = true
+				b.planes = pb.band.planes - (zero - 1)
+			}
+			b.newPasses = j2kGetPasses(br)
+			for br.bit() != 0 {
+				if b.lblock++; b.lblock > 32 || br.over {
+					return 0, fmt.Errorf("code-block length prefix overflows")
+				}
+			}
+			b.newLen = br.bits(b.lblock + bits.Len(uint(b.newPasses)) - 1)
+		}
+	}
+	br.align()
+	n, err := p.packetEnd(data, pos+br.pos, eph, br.over)
+	if err != nil {
+		return 0, err
+	}
+	for _, pb := range p.bands {
+		for _, b := range pb.blocks {
+			if b.newLen > len(data)-n {
+				return 0, fmt.Errorf("code-block of %d bytes past the end of the tile", b.newLen)
+			}
+			b.data = append(b.data, data[n:n+b.newLen]...)
+			b.passes += b.newPasses
+			n += b.newLen
+		}
+	}
+	return n, nil
+}
+
+// packetEnd returns the end of a packet header at pos, past its EPH marker
+func (p *j2kPrecinct) packetEnd(data []byte, pos int, eph, over bool) (int, error) {
+	if over {
+		return 0, fmt.Errorf("packet header past the end of the tile")
+	}
+	if eph && pos+2 <= len(data) && data[pos] == 0xFF && data[pos+1] == 0x92 {
+		pos += 2
+	}
+	return pos, nil
+}
+
+// j2kPacket is one packet of a tile: a layer of a precinct
+type j2kPacket struct {
+	layer, r, c int
+	p           *j2kPrecinct
+}
+
+// j2kPackets returns the packets of a tile in the progression order of cod
+// (T.800 B.12)
+func j2kPackets(comps []*j2kComponent, cod *jpeg2k.CODMarker) ([]j2kPacket, error) {
+	var base []j2kPacket
+	for c, comp := range comps {
+		for r, res := range comp.res {
+			for _, p := range res.precincts {
+				base = append(base, j2kPacket{r: r, c: c, p: p})
+			}
+		}
+	}
+	byRes := func(a, b j2kPacket) int { return cmp.Compare(a.r, b.r) }
+	byPos := func(a, b j2kPacket) int {
+		return cmp.Or(cmp.Compare(a.p.y, b.p.y), cmp.Compare(a.p.x, b.p.x))
+	}
+	byComp := func(a, b j2kPacket) int { return cmp.Compare(a.c, b.c) }
+	layers := int(cod.NumLayers)
+	var out []j2kPacket
+	switch cod.Progression {
+	case jpeg2k.ProgressionLRCP, jpeg2k.ProgressionRLCP:
+		slices.SortStableFunc(base, byRes)
+		for l := range layers {
+			for _, pk := range base {
+				pk.layer = l
+				out = append(out, pk)
+			}
+		}
+		if cod.Progression == jpeg2k.ProgressionRLCP {
+			slices.SortStableFunc(out, byRes)
+		}
+		return out, nil
+	case jpeg2k.ProgressionRPCL:
+		slices.SortStableFunc(base, func(a, b j2kPacket) int {
+			return cmp.Or(byRes(a, b), byPos(a, b), byComp(a, b))
+		})
+	case jpeg2k.ProgressionPCRL:
+		slices.SortStableFunc(base, func(a, b j2kPacket) int {
+			return cmp.Or(byPos(a, b), byComp(a, b), byRes(a, b))
+		})
+	case jpeg2k.ProgressionCPRL:
+		slices.SortStableFunc(base, func(a, b j2kPacket) int {
+			return cmp.Or(byComp(a, b), byPos(a, b), byRes(a, b))
+		})
+	default:
+		return nil, fmt.Errorf("%w: progression order %d", ErrUnsupportedPixelFormat, cod.Progression)
+	}
+	for _, pk := range base {
+		for l := range layers {
+			pk.layer = l
+			out = append(out, pk)
+		}
+	}
+	return out, nil
+}
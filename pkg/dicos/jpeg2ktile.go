@@ -0,0 +1,572 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+
+	"github.com/jpfielding/jpegs/pkg/compress/jpeg2k"
+)
+
+// maxJPEG2000Tiles is the most tiles a codestream can index: Isot is 16 bits
+const maxJPEG2000Tiles = 65535
+
+// decodeJPEG2000Tiles decodes every tile of a codestream whose header passed
+// checkJPEG2000Header and composes them into one image. Tile-parts are joined
+// in TPsot order before their tile is decoded, and ctx is checked per tile.
+// Tiles holding the coefficient payload of earlier versions of this library
+// are decoded as such.
+func decodeJPEG2000Tiles(ctx context.Context, data []byte, siz *jpeg2k.SIZMarker, cod *jpeg2k.CODMarker, qcd *jpeg2k.QCDMarker) (image.Image, error) {
+	tiles, err := jpeg2000TileData(data, siz.NumTiles())
+	if err != nil {
+		return nil, err
+	}
+
+	x0, y0 := int(siz.XOsiz), int(siz.YOsiz)
+	w, h := int(siz.XSiz)-x0, int(siz.YSiz)-y0
+	comps := make([][]int, len(siz.Components))
+	for c := range comps {
+		comps[c] = make([]int, w*h)
+	}
+	nx := siz.NumXTiles()
+	for t, body := range tiles {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		// tile bounds on the reference grid, clipped to the image area (T.800 B.3)
+		p, q := t%nx, t/nx
+		tx0 := max(int(siz.XTOsiz)+p*int(siz.XTsiz), x0)
+		tx1 := min(int(siz.XTOsiz)+(p+1)*int(siz.XTsiz), int(siz.XSiz))
+		ty0 := max(int(siz.YTOsiz)+q*int(siz.YTsiz), y0)
+		ty1 := min(int(siz.YTOsiz)+(q+1)*int(siz.YTsiz), int(siz.YSiz))
+		tw, th := tx1-tx0, ty1-ty0
+
+		var samples [][]int
+		if jpeg2000Legacy(body, len(comps), tw, th) {
+			samples, err = decodeJPEG2000Legacy(body, tw, th, len(comps), cod)
+		} else {
+			samples, err = decodeJ2KTile(body, tx0, ty0, tx1, ty1, siz, cod, qcd)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("jpeg-2000: tile %d: %w", t, err)
+		}
+		for c := range comps {
+			for y := range th {
+				copy(comps[c][(ty0-y0+y)*w+tx0-x0:], samples[c][y*tw:(y+1)*tw])
+			}
+		}
+	}
+
+	rect := image.Rect(0, 0, w, h)
+	switch {
+	case len(comps) == 3:
+		img := image.NewRGBA(rect)
+		for i := range comps[0] {
+			img.SetRGBA(i%w, i/w, color.RGBA{
+				R: uint8(min(max(comps[0][i], 0), 0xFF)),
+				G: uint8(min(max(comps[1][i], 0), 0xFF)),
+				B: uint8(min(max(comps[2][i], 0), 0xFF)),
+				A: 0xFF,
+			})
+		}
+		return img, nil
+	case siz.Components[0].Precision <= 8:
+		img := image.NewGray(rect)
+		for i, v := range comps[0] {
+			img.Pix[i] = uint8(min(max(v, 0), 0xFF))
+		}
+		return img, nil
+	}
+	img := image.NewGray16(rect)
+	for i, v := range comps[0] {
+		img.SetGray16(i%w, i/w, color.Gray16{Y: uint16(min(max(v, 0), 0xFFFF))})
+	}
+	return img, nil
+}
+
+// jpeg2000Legacy returns true if a tile body holds the payload earlier
+// versions of this library wrote instead of packets: per component, the tile
+// size and the wavelet coefficients as 32-bit integers
+func jpeg2000Legacy(body []byte, comps, tw, th int) bool {
+	n := 4 + 4*tw*th
+	return len(body) == comps*n &&
+		int(binary.BigEndian.Uint16(body)) == tw && int(binary.BigEndian.Uint16(body[2:])) == th
+}
+
+// decodeJPEG2000Legacy decodes a legacy tile payload
+func decodeJPEG2000Legacy(body []byte, tw, th, comps int, cod *jpeg2k.CODMarker) ([][]int, error) {
+	n := 4 + 4*tw*th
+	samples := make([][]int, comps)
+	for c := range samples {
+		s, err := jpeg2k.NewTileDecoder(tw, th, int(cod.DecompLevels), 64, 64).DecodeTile(body[c*n : (c+1)*n])
+		if err != nil {
+			return nil, err
+		}
+		samples[c] = s
+	}
+	if cod.MCT != 0 && comps >= 3 {
+		jpeg2k.ApplyInverseRCT(samples)
+	}
+	return samples, nil
+}
+
+// maxJPEG2000Packets bounds the packets of a tile, layers times precincts
+const maxJPEG2000Packets = 1 << 22
+
+// decodeJ2KTile decodes the packets of the tile at x0,y0-x1,y1 (T.800
+// Annexes B to G) and returns its samples per component, offset by half
+// their range as unsigned samples are
+func decodeJ2KTile(body []byte, x0, y0, x1, y1 int, siz *jpeg2k.SIZMarker, cod *jpeg2k.CODMarker, qcd *jpeg2k.QCDMarker) ([][]int, error) {
+	if cod.Transform != jpeg2k.TransformReversible53 {
+		return nil, fmt.Errorf("%w: irreversible 9/7 wavelet", ErrUnsupportedPixelFormat)
+	}
+	if qcd == nil || qcd.Sqcd&0x1F != 0 {
+		return nil, fmt.Errorf("%w: quantized sub-bands", ErrUnsupportedPixelFormat)
+	}
+	exps := make([]int, len(qcd.StepSizes))
+	for i, e := range qcd.StepSizes {
+		exps[i] = int(e)
+	}
+	comps := make([]*j2kComponent, len(siz.Components))
+	for c, info := range siz.Components {
+		if info.XRsiz != 1 || info.YRsiz != 1 {
+			return nil, fmt.Errorf("%w: component %d subsampled %dx%d", ErrUnsupportedPixelFormat, c, info.XRsiz, info.YRsiz)
+		}
+		comp, err := newJ2KComponent(x0, y0, x1, y1, cod, exps)
+		if err != nil {
+			return nil, err
+		}
+		comp.setPlanes(int(qcd.GuardBits))
+		for _, res := range comp.res {
+			for _, band := range res.bands {
+				if band.planes > 30 {
+					return nil, fmt.Errorf("%w: %d magnitude bit-planes", ErrUnsupportedPixelFormat, band.planes)
+				}
+			}
+		}
+		comps[c] = comp
+	}
+
+	if n := len(comps) * (int(cod.DecompLevels) + 1) * int(cod.NumLayers); n > maxJPEG2000Packets {
+		return nil, fmt.Errorf("jpeg-2000: %d layers of %d resolutions", cod.NumLayers, cod.DecompLevels+1)
+	}
+	packets, err := j2kPackets(comps, cod)
+	if err != nil {
+		return nil, err
+	}
+	if len(packets) > maxJPEG2000Packets {
+		return nil, fmt.Errorf("jpeg-2000: %d packets in a tile, at most %d", len(packets), maxJPEG2000Packets)
+	}
+	sop, eph := cod.Scod&jpeg2k.CodingStyleSOPMarker != 0, cod.Scod&jpeg2k.CodingStyleEPHMarker != 0
+	pos := 0
+	for _, pk := range packets {
+		if pos >= len(body) {
+			break // the remaining packets were truncated away
+		}
+		n, err := pk.p.decodePacket(body[pos:], pk.layer, sop, eph)
+		if err != nil {
+			return nil, err
+		}
+		pos += n
+	}
+
+	samples := make([][]int, len(comps))
+	for c, comp := range comps {
+		for _, res := range comp.res {
+			for _, p := range res.precincts {
+				for _, pb := range p.bands {
+					for _, b := range pb.blocks {
+						if b.passes == 0 {
+							continue
+						}
+						coef, err := decodeJ2KBlock(b.data, b.x1-b.x0, b.y1-b.y0, pb.band.orient, cod.CodeBlockStyle, b.passes, b.planes)
+						if err != nil {
+							return nil, err
+						}
+						comp.setBlockCoef(pb.band, b, coef)
+					}
+				}
+			}
+		}
+		comp.inverseDWT()
+		samples[c] = make([]int, len(comp.coef))
+		for i, v := range comp.coef {
+			samples[c][i] = int(v)
+		}
+	}
+	if cod.MCT != 0 && len(samples) >= 3 {
+		// inverse reversible component transform (T.800 G.2)
+		for i := range samples[0] {
+			y, u, v := samples[0][i], samples[1][i], samples[2][i]
+			g := y - (u+v)>>2
+			samples[0][i], samples[1][i], samples[2][i] = v+g, g, u+g
+		}
+	}
+	for c, s := range samples {
+		half := 1 << (siz.Components[c].Precision - 1)
+		for i := range s {
+			s[i] += half
+		}
+	}
+	return samples, nil
+}
+
+// jpeg2000Image holds the samples of the components of an image to encode,
+// row major, unsigned or two's complement per signed
+type jpeg2000Image struct {
+	width, height int
+	precision     int
+	signed        bool
+	comps         [][]int32
+}
+
+// encodeJPEG2000 writes img as a lossless T.800 codestream: the 5/3 wavelet,
+// the reversible component transform for three components, one quality
+// layer in LRCP order and 64x64 code-blocks, in tiles of tw x th or one
+// tile if they are 0
+func encodeJPEG2000(w io.Writer, img *jpeg2000Image, tw, th int) error {
+	if img.width < 1 || img.height < 1 || img.width > 0xFFFF || img.height > 0xFFFF {
+		return fmt.Errorf("jpeg-2000: image size %dx%d", img.width, img.height)
+	}
+	if tw <= 0 || th <= 0 {
+		tw, th = img.width, img.height
+	}
+	levels := 5
+	for levels > 0 && min(tw, th)>>levels == 0 {
+		levels--
+	}
+	mct := len(img.comps) == 3
+	cod := jpeg2k.BuildDefaultCOD(levels, 1, jpeg2k.ProgressionLRCP, mct)
+	exps := make([]int, 3*levels+1)
+	for i := range exps {
+		exps[i] = img.precision // LL
+		if i > 0 {
+			exps[i] += []int{1, 1, 2}[(i-1)%3] // HL, LH and HH gain
+		}
+	}
+
+	// code every tile first: the guard bits must cover the largest sub-band
+	guard := 2
+	var tiles [][]*j2kComponent
+	for ty := 0; ty < img.height; ty += th {
+		for tx := 0; tx < img.width; tx += tw {
+			x1, y1 := min(tx+tw, img.width), min(ty+th, img.height)
+			comps := make([]*j2kComponent, len(img.comps))
+			for c, samples := range img.comps {
+				comp, err := newJ2KComponent(tx, ty, x1, y1, cod, exps)
+				if err != nil {
+					return err
+				}
+				for y := ty; y < y1; y++ {
+					copy(comp.coef[(y-ty)*(x1-tx):], samples[y*img.width+tx:y*img.width+x1])
+				}
+				if !img.signed {
+					for i := range comp.coef {
+						comp.coef[i] -= 1 << (img.precision - 1)
+					}
+				}
+				comps[c] = comp
+			}
+			if mct {
+				// forward reversible component transform (T.800 G.2)
+				r, g, b := comps[0].coef, comps[1].coef, comps[2].coef
+				for i := range r {
+					r[i], g[i], b[i] = (r[i]+2*g[i]+b[i])>>2, b[i]-g[i], r[i]-g[i]
+				}
+			}
+			for _, comp := range comps {
+				comp.forwardDWT()
+				for _, res := range comp.res {
+					for _, p := range res.precincts {
+						for _, pb := range p.bands {
+							for _, b := range pb.blocks {
+								b.data, b.passes, b.planes = encodeJ2KBlock(comp.blockCoef(pb.band, b), b.x1-b.x0, b.y1-b.y0, pb.band.orient)
+								guard = max(guard, b.planes-pb.band.exp+1)
+							}
+						}
+					}
+				}
+			}
+			tiles = append(tiles, comps)
+		}
+	}
+	if guard > 7 {
+		return fmt.Errorf("jpeg-2000: coefficients need %d guard bits, at most 7", guard)
+	}
+
+	var buf bytes.Buffer
+	cw := jpeg2k.NewCodestreamWriter(&buf)
+	if err := cw.WriteSOC(); err != nil {
+		return err
+	}
+	info := make([]jpeg2k.ComponentInfo, len(img.comps))
+	for c := range info {
+		info[c] = jpeg2k.ComponentInfo{Precision: img.precision, Signed: img.signed, XRsiz: 1, YRsiz: 1}
+	}
+	if err := cw.WriteSIZ(jpeg2k.BuildSIZ(img.width, img.height, info, tw, th)); err != nil {
+		return err
+	}
+	if err := cw.WriteCOD(cod); err != nil {
+		return err
+	}
+	qcd := &jpeg2k.QCDMarker{GuardBits: byte(guard), StepSizes: make([]int16, len(exps))}
+	for i, e := range exps {
+		qcd.StepSizes[i] = int16(e)
+	}
+	if err := cw.WriteQCD(qcd); err != nil {
+		return err
+	}
+	for t, comps := range tiles {
+		var body bytes.Buffer
+		for _, comp := range comps {
+			comp.setPlanes(guard)
+		}
+		packets, err := j2kPackets(comps, cod)
+		if err != nil {
+			return err
+		}
+		for _, pk := range packets {
+			pk.p.encodePacket(&body)
+		}
+		// Psot runs from the SOT marker to the end of the tile-part data
+		sot := &jpeg2k.SOTMarker{TileIndex: uint16(t), TilePartLen: uint32(12 + 2 + body.Len()), NumTileParts: 1}
+		if err := cw.WriteSOT(sot); err != nil {
+			return err
+		}
+		if err := cw.WriteSOD(); err != nil {
+			return err
+		}
+		if err := cw.WriteBytes(body.Bytes()); err != nil {
+			return err
+		}
+	}
+	if err := cw.WriteEOC(); err != nil {
+		return err
+	}
+	if err := cw.Flush(); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// forwardDWT decomposes the tile-component with the reversible 5/3 wavelet,
+// columns then rows at each level (T.800 F.4)
+func (c *j2kComponent) forwardDWT() {
+	stride := c.x1 - c.x0
+	line := make([]int32, max(stride, c.y1-c.y0))
+	tmp := make([]int32, len(line))
+	for r := len(c.res) - 1; r > 0; r-- {
+		res := c.res[r]
+		w, h := res.x1-res.x0, res.y1-res.y0
+		for x := range w {
+			col := line[:h]
+			for y := range col {
+				col[y] = c.coef[y*stride+x]
+			}
+			j2kFDWT53(col, tmp, res.y0)
+			for y, v := range col {
+				c.coef[y*stride+x] = v
+			}
+		}
+		for y := range h {
+			j2kFDWT53(c.coef[y*stride:y*stride+w], tmp, res.x0)
+		}
+	}
+}
+
+// inverseDWT reconstructs the tile-component samples, rows then columns at
+// each level (T.800 F.3)
+func (c *j2kComponent) inverseDWT() {
+	stride := c.x1 - c.x0
+	line := make([]int32, max(stride, c.y1-c.y0))
+	tmp := make([]int32, len(line))
+	for r := 1; r < len(c.res); r++ {
+		res := c.res[r]
+		w, h := res.x1-res.x0, res.y1-res.y0
+		for y := range h {
+			j2kIDWT53(c.coef[y*stride:y*stride+w], tmp, res.x0)
+		}
+		for x := range w {
+			col := line[:h]
+			for y := range col {
+				col[y] = c.coef[y*stride+x]
+			}
+			j2kIDWT53(col, tmp, res.y0)
+			for y, v := range col {
+				c.coef[y*stride+x] = v
+			}
+		}
+	}
+}
+
+// j2kFDWT53 lifts the signal x, whose first sample has index i0, into
+// low-pass then high-pass coefficients (T.800 F.4.8)
+func j2kFDWT53(x, tmp []int32, i0 int) {
+	n := len(x)
+	if n == 1 {
+		if i0&1 != 0 {
+			x[0] *= 2
+		}
+		return
+	}
+	p := i0 & 1 // x[p] is the first even sample
+	at := j2kExtend(x)
+	for j := 1 - p; j < n; j += 2 {
+		x[j] -= (at(j-1) + at(j+1)) >> 1
+	}
+	for j := p; j < n; j += 2 {
+		x[j] += (at(j-1) + at(j+1) + 2) >> 2
+	}
+	copy(tmp, x)
+	k := 0
+	for j := p; j < n; j += 2 {
+		x[k] = tmp[j]
+		k++
+	}
+	for j := 1 - p; j < n; j += 2 {
+		x[k] = tmp[j]
+		k++
+	}
+}
+
+// j2kIDWT53 is the inverse of j2kFDWT53 (T.800 F.3.8)
+func j2kIDWT53(x, tmp []int32, i0 int) {
+	n := len(x)
+	if n == 1 {
+		if i0&1 != 0 {
+			x[0] /= 2
+		}
+		return
+	}
+	p := i0 & 1
+	copy(tmp, x)
+	k := 0
+	for j := p; j < n; j += 2 {
+		x[j] = tmp[k]
+		k++
+	}
+	for j := 1 - p; j < n; j += 2 {
+		x[j] = tmp[k]
+		k++
+	}
+	at := j2kExtend(x)
+	for j := p; j < n; j += 2 {
+		x[j] -= (at(j-1) + at(j+1) + 2) >> 2
+	}
+	for j := 1 - p; j < n; j += 2 {
+		x[j] += (at(j-1) + at(j+1)) >> 1
+	}
+}
+
+// j2kExtend returns x indexed with symmetric extension past its ends
+// (T.800 F.3.7)
+func j2kExtend(x []int32) func(int) int32 {
+	n := len(x)
+	return func(j int) int32 {
+		if j < 0 {
+			j = -j
+		} else if j >= n {
+			j = 2*(n-1) - j
+		}
+		return x[j]
+	}
+}
+
+// jpeg2000TileData returns the bodies of the tiles of a codestream, indexed
+// by Isot, each the concatenation of its tile-parts in TPsot order
+func jpeg2000TileData(data []byte, numTiles int) ([][]byte, error) {
+	// skip the main header marker segments to the first SOT
+	pos := 2
+	for {
+		if pos+4 > len(data) {
+			return nil, fmt.Errorf("jpeg-2000: no tile-part in codestream")
+		}
+		if binary.BigEndian.Uint16(data[pos:]) == jpeg2k.MarkerSOT {
+			break
+		}
+		pos += 2 + int(binary.BigEndian.Uint16(data[pos+2:]))
+	}
+
+	tiles := make([][]byte, numTiles)
+	parts := make([]int, numTiles)
+	for pos+2 <= len(data) && binary.BigEndian.Uint16(data[pos:]) != jpeg2k.MarkerEOC {
+		if binary.BigEndian.Uint16(data[pos:]) != jpeg2k.MarkerSOT {
+			return nil, fmt.Errorf("jpeg-2000: expected SOT at offset %d", pos)
+		}
+		if pos+12 > len(data) || binary.BigEndian.Uint16(data[pos+2:]) != 10 {
+			return nil, fmt.Errorf("jpeg-2000: truncated SOT at offset %d", pos)
+		}
+		isot := int(binary.BigEndian.Uint16(data[pos+4:]))
+		psot := int(binary.BigEndian.Uint32(data[pos+6:]))
+		tpsot := int(data[pos+10])
+		if isot >= numTiles {
+			return nil, fmt.Errorf("jpeg-2000: tile index %d, the tile grid has %d", isot, numTiles)
+		}
+		if tpsot != parts[isot] {
+			return nil, fmt.Errorf("jpeg-2000: tile %d part %d is out of order, expected part %d", isot, tpsot, parts[isot])
+		}
+		parts[isot]++
+
+		// tile-part header markers up to SOD
+		body := pos + 12
+		for {
+			if body+2 > len(data) {
+				return nil, fmt.Errorf("jpeg-2000: tile %d part %d has no SOD", isot, tpsot)
+			}
+			if binary.BigEndian.Uint16(data[body:]) == jpeg2k.MarkerSOD {
+				body += 2
+				break
+			}
+			if body+4 > len(data) {
+				return nil, fmt.Errorf("jpeg-2000: tile %d part %d has no SOD", isot, tpsot)
+			}
+			body += 2 + int(binary.BigEndian.Uint16(data[body+2:]))
+		}
+
+		end := len(data) // Psot 0: the last tile-part runs to EOC
+		if n := len(data); n >= 2 && binary.BigEndian.Uint16(data[n-2:]) == jpeg2k.MarkerEOC {
+			end = n - 2
+		}
+		if psot != 0 {
+			end = pos + psot
+			// jpeg2k.Encode leaves the SOD marker out of Psot, so its
+			// tile-parts end two bytes after their declared length
+			if !jpeg2000PartEnds(data, end) && jpeg2000PartEnds(data, end+2) {
+				end += 2
+			}
+		}
+		if end < body || end > len(data) {
+			return nil, fmt.Errorf("jpeg-2000: tile %d part %d length %d is outside the codestream", isot, tpsot, psot)
+		}
+		if tiles[isot] == nil {
+			tiles[isot] = data[body:end:end]
+		} else {
+			tiles[isot] = append(tiles[isot], data[body:end]...)
+		}
+		pos = end
+	}
+	for t, n := range parts {
+		if n == 0 {
+			return nil, fmt.Errorf("jpeg-2000: tile %d of %d is missing", t, numTiles)
+		}
+	}
+	return tiles, nil
+}
+
+// jpeg2000PartEnds returns true if a tile-part can end at offset i: at the
+// end of the codestream or before the next SOT or EOC
+func jpeg2000PartEnds(data []byte, i int) bool {
+	if i == len(data) {
+		return true
+	}
+	if i < 0 || i+2 > len(data) {
+		return false
+	}
+	m := binary.BigEndian.Uint16(data[i:])
+	return m == jpeg2k.MarkerSOT || m == jpeg2k.MarkerEOC
+}
@@ -0,0 +1,208 @@
+package dicos
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/jpfielding/dicos.go/pkg/dicos/transfer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// thumbnail is an 8-bit grayscale image of frames smooth gradients
+func thumbnail(t *testing.T, rows, cols, frames int, codec Codec) (*Dataset, []uint16) {
+	t.Helper()
+	data := make([]uint16, rows*cols*frames)
+	for i := range data {
+		x, y := i%cols, i/cols%rows
+		data[i] = uint16(4*x + 2*y + 16*(i/(rows*cols)))
+	}
+	ds := newTestDataset(t, DXImageStorageUID, codec,
+		WithElement(tag.Rows, rows),
+		WithElement(tag.Columns, cols),
+		WithElement(tag.SamplesPerPixel, 1),
+		WithElement(tag.PhotometricInterpretation, "MONOCHROME2"),
+		WithElement(tag.BitsAllocated, 8),
+		WithElement(tag.BitsStored, 8),
+		WithElement(tag.HighBit, 7),
+		WithElement(tag.PixelRepresentation, 0),
+		WithElement(tag.NumberOfFrames, frames),
+		WithPixelData(rows, cols, 8, data, codec),
+	)
+	return ds, data
+}
+
+func TestJPEGBaseline_RoundTrip(t *testing.T) {
+	const rows, cols = 16, 24
+	ds, want := thumbnail(t, rows, cols, 2, CodecJPEGBaseline)
+	assert.Equal(t, "01", attrString(ds, tag.LossyImageCompression))
+	assert.Equal(t, "ISO_10918_1", attrString(ds, tag.LossyImageCompressionMethod))
+	ratio := attrFloat(ds, tag.LossyImageCompressionRatio)
+	assert.Greater(t, ratio, 0.0)
+
+	ds = rewrite(t, ds)
+	assert.Equal(t, transfer.JPEGBaseline, ds.TransferSyntax())
+	assert.InDelta(t, ratio, attrFloat(ds, tag.LossyImageCompressionRatio), 0.005)
+	pd, err := ds.GetPixelData()
+	require.NoError(t, err)
+	require.Len(t, pd.Frames, 2)
+	assert.Equal(t, CodecJPEGBaseline, sniffCodec(pd.Frames[0].CompressedData))
+
+	vol, err := DecodeVolume(ds)
+	require.NoError(t, err)
+	require.Len(t, vol.Data, len(want))
+	for i, v := range vol.Data {
+		require.InDelta(t, want[i], v, 4, "sample %d", i)
+	}
+
+	// Extended frames of 8 bits decode the same way
+	ds.Elements[tag.TransferSyntaxUID].Value = string(transfer.JPEGExtended)
+	vol, err = DecodeVolume(ds)
+	require.NoError(t, err)
+	assert.Len(t, vol.Data, len(want))
+}
+
+func TestJPEGBaseline_Unsupported(t *testing.T) {
+	enc := CodecJPEGBaseline.(SampleEncoder)
+	var buf bytes.Buffer
+	for _, f := range []SampleFormat{
+		{BitsAllocated: 16, BitsStored: 12},
+		{BitsAllocated: 16, BitsStored: 8},
+		{BitsAllocated: 8, BitsStored: 8, Signed: true},
+	} {
+		err := enc.EncodeSamples(&buf, make([]uint16, 4), 2, 2, f)
+		assert.ErrorIs(t, err, ErrUnsupportedPixelFormat, "%+v", f)
+	}
+	err := CodecJPEGBaseline.Encode(&buf, image.NewGray16(image.Rect(0, 0, 2, 2)))
+	assert.ErrorIs(t, err, ErrUnsupportedPixelFormat)
+	assert.Zero(t, buf.Len())
+
+	// a 12-bit color SOF1 header is refused rather than misread
+	require.NoError(t, jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 2)), nil))
+	cs := buf.Bytes()
+	sof := bytes.Index(cs, []byte{0xFF, 0xC0})
+	require.Positive(t, sof)
+	cs[sof+1], cs[sof+4] = 0xC1, 12
+	_, err = CodecJPEGBaseline.Decode(cs, 2, 2)
+	assert.ErrorIs(t, err, ErrUnsupportedPixelFormat)
+}
+
+// ct12 is a 12-bit CT volume of frames with a smooth background and a sharp
+// full-range square
+func ct12(t *testing.T, rows, cols, frames int, codec Codec) (*Dataset, []uint16) {
+	t.Helper()
+	data := make([]uint16, rows*cols*frames)
+	for i := range data {
+		x, y := i%cols, i/cols%rows
+		data[i] = uint16(1000 + 20*x + 10*y + 40*(i/(rows*cols)))
+		if x >= 4 && x < 12 && y >= 4 && y < 12 {
+			data[i] = 4095
+		}
+	}
+	ds := newTestDataset(t, DICOSCTImageStorageUID, codec,
+		WithElement(tag.Rows, rows),
+		WithElement(tag.Columns, cols),
+		WithElement(tag.SamplesPerPixel, 1),
+		WithElement(tag.PhotometricInterpretation, "MONOCHROME2"),
+		WithElement(tag.BitsAllocated, 16),
+		WithElement(tag.BitsStored, 12),
+		WithElement(tag.HighBit, 11),
+		WithElement(tag.PixelRepresentation, 0),
+		WithElement(tag.NumberOfFrames, frames),
+		WithPixelData(rows, cols, 16, data, codec),
+	)
+	return ds, data
+}
+
+func TestJPEGExtended_RoundTrip(t *testing.T) {
+	const rows, cols = 20, 30 // partial blocks on both edges
+	ds, want := ct12(t, rows, cols, 2, NewJPEGExtendedCodec(95))
+	assert.Equal(t, "01", attrString(ds, tag.LossyImageCompression))
+	assert.Equal(t, "ISO_10918_1", attrString(ds, tag.LossyImageCompressionMethod))
+
+	ds = rewrite(t, ds)
+	assert.Equal(t, transfer.JPEGExtended, ds.TransferSyntax())
+	pd, err := ds.GetPixelData()
+	require.NoError(t, err)
+	require.Len(t, pd.Frames, 2)
+	cs := pd.Frames[0].CompressedData
+	assert.Equal(t, CodecJPEGExtended, sniffCodec(cs))
+	marker, precision, err := jpegFrameHeader(cs)
+	require.NoError(t, err)
+	assert.Equal(t, byte(0xC1), marker)
+	assert.Equal(t, 12, precision)
+
+	vol, err := DecodeVolume(ds)
+	require.NoError(t, err)
+	require.Len(t, vol.Data, len(want))
+	for i, v := range vol.Data {
+		require.InDelta(t, want[i], v, 24, "sample %d", i)
+	}
+
+	// 8-bit frames are written as Baseline
+	ds, _ = thumbnail(t, 8, 8, 1, CodecJPEGExtended)
+	pd, err = ds.GetPixelData()
+	require.NoError(t, err)
+	assert.Equal(t, CodecJPEGBaseline, sniffCodec(pd.Frames[0].CompressedData))
+}
+
+func TestJPEGExtended_Samples(t *testing.T) {
+	// full-range noise at quality 100 exercises every magnitude category and
+	// long Huffman codes, and comes back within rounding of the DCT
+	const w, h = 37, 19
+	data := make([]uint16, w*h)
+	seed := uint32(1)
+	for i := range data {
+		seed = seed*1664525 + 1013904223
+		data[i] = uint16(seed >> 20)
+	}
+	enc := NewJPEGExtendedCodec(100).(SampleEncoder)
+	var buf bytes.Buffer
+	require.NoError(t, enc.EncodeSamples(&buf, data, w, h, SampleFormat{BitsAllocated: 16, BitsStored: 12}))
+	img, err := CodecJPEGExtended.Decode(buf.Bytes(), w, h)
+	require.NoError(t, err)
+	g, ok := img.(*image.Gray16)
+	require.True(t, ok, "%T", img)
+	for i, v := range data {
+		require.InDelta(t, v, g.Gray16At(i%w, i/w).Y, 2, "sample %d", i)
+	}
+
+	for _, f := range []SampleFormat{
+		{BitsAllocated: 16, BitsStored: 16},
+		{BitsAllocated: 16, BitsStored: 12, Signed: true},
+	} {
+		err := enc.EncodeSamples(&buf, data, w, h, f)
+		assert.ErrorIs(t, err, ErrUnsupportedPixelFormat, "%+v", f)
+	}
+	data[3] = 0x1000
+	err = enc.EncodeSamples(&buf, data, w, h, SampleFormat{BitsAllocated: 16, BitsStored: 12})
+	assert.ErrorIs(t, err, ErrUnsupportedPixelFormat, "sample over 12 bits")
+
+	// a truncated scan fails rather than decoding as zeros
+	cs := buf.Bytes()
+	_, err = CodecJPEGExtended.Decode(cs[:len(cs)/2], w, h)
+	assert.Error(t, err)
+}
+
+func TestTranscode_JPEGBaseline(t *testing.T) {
+	src, _ := thumbnail(t, 8, 8, 1, nil)
+	out, err := Transcode(src, transfer.JPEGBaseline)
+	require.NoError(t, err)
+	assert.Equal(t, "01", attrString(out, tag.LossyImageCompression))
+	first := attrString(out, tag.LossyImageCompressionRatio)
+
+	// a second lossy step is appended to the history
+	out, err = Transcode(out, transfer.JPEGBaseline)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ISO_10918_1", "ISO_10918_1"}, attrStrings(out, tag.LossyImageCompressionMethod))
+	ratios := attrStrings(out, tag.LossyImageCompressionRatio)
+	require.Len(t, ratios, 2)
+	assert.Equal(t, first, ratios[0])
+
+	out, err = Transcode(out, transfer.ExplicitVRLittleEndian)
+	require.NoError(t, err)
+	assert.Equal(t, "01", attrString(out, tag.LossyImageCompression), "lossy history is kept")
+}
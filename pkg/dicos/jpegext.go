@@ -0,0 +1,764 @@
+package dicos
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"math"
+)
+
+// JPEG Extended (ITU-T T.81 Process 4) for single-component frames: the
+// sequential DCT with Huffman coding at 12 bits of precision, which
+// image/jpeg does not implement. Frames are encoded with a 16-bit
+// quantization table scaled from the T.81 Annex K luminance table and with
+// Huffman tables optimized for the frame.
+
+// jpegUnzig maps zig-zag positions to natural (row-major) block positions
+var jpegUnzig = [64]int{
+	0, 1, 8, 16, 9, 2, 3, 10,
+	17, 24, 32, 25, 18, 11, 4, 5,
+	12, 19, 26, 33, 40, 48, 41, 34,
+	27, 20, 13, 6, 7, 14, 21, 28,
+	35, 42, 49, 56, 57, 50, 43, 36,
+	29, 22, 15, 23, 30, 37, 44, 51,
+	58, 59, 52, 45, 38, 31, 39, 46,
+	53, 60, 61, 54, 47, 55, 62, 63,
+}
+
+// jpegLuminanceQuant is the T.81 Table K.1 luminance table in natural order
+var jpegLuminanceQuant = [64]int{
+	16, 11, 10, 16, 24, 40, 51, 61,
+	12, 12, 14, 19, 26, 58, 60, 55,
+	14, 13, 16, 24, 40, 57, 69, 56,
+	14, 17, 22, 29, 51, 87, 80, 62,
+	18, 22, 37, 56, 68, 109, 103, 77,
+	24, 35, 55, 64, 81, 104, 113, 92,
+	49, 64, 78, 87, 103, 121, 120, 101,
+	72, 92, 95, 98, 112, 100, 103, 99,
+}
+
+// jpegCos holds C(u)/2 * cos((2x+1)uπ/16), indexed [x][u], so that both the
+// forward and inverse 8x8 DCT are two passes of one matrix product
+var jpegCos = func() (c [8][8]float64) {
+	for x := range 8 {
+		for u := range 8 {
+			cu := 1.0
+			if u == 0 {
+				cu = 1 / math.Sqrt2
+			}
+			c[x][u] = cu / 2 * math.Cos(float64(2*x+1)*float64(u)*math.Pi/16)
+		}
+	}
+	return c
+}()
+
+// jpegMaxCoefficient bounds quantized coefficients so DC differences stay in
+// the 15 magnitude categories T.81 allows at 12 bits
+const jpegMaxCoefficient = 1<<14 - 1
+
+// encodeJPEGExtended writes one frame of 12-bit unsigned samples as a JPEG
+// Extended (SOF1) codestream at quality, from 1 to 100
+func encodeJPEGExtended(w io.Writer, data []uint16, width, height, quality int) error {
+	if width <= 0 || height <= 0 || width > 0xFFFF || height > 0xFFFF {
+		return fmt.Errorf("jpeg-extended: %w: %dx%d frame", ErrUnsupportedPixelFormat, width, height)
+	}
+	if len(data) < width*height {
+		return fmt.Errorf("jpeg-extended: frame has %d samples, want %d", len(data), width*height)
+	}
+
+	// quantization table in zig-zag order
+	scale := 200 - 2*quality
+	if quality < 50 {
+		scale = 5000 / quality
+	}
+	var quant [64]int32
+	for k := range quant {
+		quant[k] = int32(min(max((jpegLuminanceQuant[jpegUnzig[k]]*scale+50)/100, 1), 0x7FFF))
+	}
+
+	// transform and quantize every block, in raster order
+	bw, bh := (width+7)/8, (height+7)/8
+	blocks := make([][64]int32, bw*bh)
+	var px, tmp [64]float64
+	for by := range bh {
+		for bx := range bw {
+			for y := range 8 {
+				sy := min(by*8+y, height-1) // replicate the right and bottom edges
+				for x := range 8 {
+					sx := min(bx*8+x, width-1)
+					px[y*8+x] = float64(data[sy*width+sx]) - 2048
+				}
+			}
+			jpegFDCT(&px, &tmp)
+			blk := &blocks[by*bw+bx]
+			for k := range blk {
+				q := int32(math.Round(tmp[jpegUnzig[k]] / float64(quant[k])))
+				blk[k] = min(max(q, -jpegMaxCoefficient), jpegMaxCoefficient)
+			}
+		}
+	}
+
+	// optimal Huffman tables from the symbol frequencies
+	var dcFreq, acFreq [257]int
+	pred := int32(0)
+	for i := range blocks {
+		jpegCountBlock(&blocks[i], pred, &dcFreq, &acFreq)
+		pred = blocks[i][0]
+	}
+	dcBits, dcVals := jpegOptimalTable(dcFreq)
+	acBits, acVals := jpegOptimalTable(acFreq)
+	dc := newJPEGEncodeTable(dcBits, dcVals)
+	ac := newJPEGEncodeTable(acBits, acVals)
+
+	bwr := bufio.NewWriter(w)
+	hdr := []byte{0xFF, 0xD8} // SOI
+
+	// DQT with 16-bit entries (Pq=1), table 0
+	hdr = append(hdr, 0xFF, 0xDB, 0, 2+1+128, 0x10)
+	for _, q := range quant {
+		hdr = binary.BigEndian.AppendUint16(hdr, uint16(q))
+	}
+
+	// SOF1: 12-bit precision, one component sampled 1x1 using table 0
+	hdr = append(hdr, 0xFF, 0xC1, 0, 11, 12)
+	hdr = binary.BigEndian.AppendUint16(hdr, uint16(height))
+	hdr = binary.BigEndian.AppendUint16(hdr, uint16(width))
+	hdr = append(hdr, 1, 1, 0x11, 0)
+
+	// DHT: DC table 0 and AC table 0
+	for _, t := range []struct {
+		class byte
+		bits  [16]byte
+		vals  []byte
+	}{{0x00, dcBits, dcVals}, {0x10, acBits, acVals}} {
+		hdr = binary.BigEndian.AppendUint16(append(hdr, 0xFF, 0xC4), uint16(2+1+16+len(t.vals)))
+		hdr = append(hdr, t.class)
+		hdr = append(hdr, t.bits[:]...)
+		hdr = append(hdr, t.vals...)
+	}
+
+	// SOS: the one component with DC and AC table 0, full spectral range
+	hdr = append(hdr, 0xFF, 0xDA, 0, 8, 1, 1, 0x00, 0, 63, 0)
+	if _, err := bwr.Write(hdr); err != nil {
+		return err
+	}
+
+	bits := jpegBitWriter{w: bwr}
+	pred = 0
+	for i := range blocks {
+		blk := &blocks[i]
+		diff := blk[0] - pred
+		pred = blk[0]
+		size := jpegCategory(diff)
+		bits.emit(dc.code[size], dc.size[size])
+		bits.emitValue(diff, size)
+
+		run := 0
+		for k := 1; k < 64; k++ {
+			v := blk[k]
+			if v == 0 {
+				run++
+				continue
+			}
+			for ; run > 15; run -= 16 {
+				bits.emit(ac.code[0xF0], ac.size[0xF0]) // ZRL
+			}
+			size := jpegCategory(v)
+			sym := run<<4 | int(size)
+			bits.emit(ac.code[sym], ac.size[sym])
+			bits.emitValue(v, size)
+			run = 0
+		}
+		if run > 0 {
+			bits.emit(ac.code[0x00], ac.size[0x00]) // EOB
+		}
+	}
+	bits.flush()
+	if bits.err != nil {
+		return bits.err
+	}
+	if _, err := bwr.Write([]byte{0xFF, 0xD9}); err != nil { // EOI
+		return err
+	}
+	return bwr.Flush()
+}
+
+// jpegFDCT computes the forward DCT of an 8x8 block in natural order
+func jpegFDCT(in, out *[64]float64) {
+	var rows [64]float64
+	for y := range 8 {
+		for u := range 8 {
+			var s float64
+			for x := range 8 {
+				s += jpegCos[x][u] * in[y*8+x]
+			}
+			rows[y*8+u] = s
+		}
+	}
+	for u := range 8 {
+		for v := range 8 {
+			var s float64
+			for y := range 8 {
+				s += jpegCos[y][v] * rows[y*8+u]
+			}
+			out[v*8+u] = s
+		}
+	}
+}
+
+// jpegIDCT computes the inverse DCT of an 8x8 block in natural order
+func jpegIDCT(in, out *[64]float64) {
+	var cols [64]float64
+	for u := range 8 {
+		for y := range 8 {
+			var s float64
+			for v := range 8 {
+				s += jpegCos[y][v] * in[v*8+u]
+			}
+			cols[y*8+u] = s
+		}
+	}
+	for y := range 8 {
+		for x := range 8 {
+			var s float64
+			for u := range 8 {
+				s += jpegCos[x][u] * cols[y*8+u]
+			}
+			out[y*8+x] = s
+		}
+	}
+}
+
+// jpegCategory returns the magnitude category (SSSS) of v: its bit length
+func jpegCategory(v int32) uint8 {
+	if v < 0 {
+		v = -v
+	}
+	var n uint8
+	for ; v != 0; v >>= 1 {
+		n++
+	}
+	return n
+}
+
+// jpegCountBlock adds the Huffman symbols of one block to the frequencies
+func jpegCountBlock(blk *[64]int32, pred int32, dcFreq, acFreq *[257]int) {
+	dcFreq[jpegCategory(blk[0]-pred)]++
+	run := 0
+	for k := 1; k < 64; k++ {
+		if blk[k] == 0 {
+			run++
+			continue
+		}
+		for ; run > 15; run -= 16 {
+			acFreq[0xF0]++
+		}
+		acFreq[run<<4|int(jpegCategory(blk[k]))]++
+		run = 0
+	}
+	if run > 0 {
+		acFreq[0x00]++
+	}
+}
+
+// jpegOptimalTable builds Huffman code lengths of at most 16 bits from symbol
+// frequencies, following T.81 Annex K.2. Symbol 256 is reserved so that no
+// code is all ones.
+func jpegOptimalTable(freq [257]int) (bits [16]byte, vals []byte) {
+	freq[256] = 1
+	var codesize [257]int
+	var others [257]int
+	for i := range others {
+		others[i] = -1
+	}
+	for {
+		// the two least frequent symbols, the larger index winning ties
+		c1, c2 := -1, -1
+		for i, f := range freq {
+			if f > 0 && (c1 < 0 || f <= freq[c1]) {
+				c1 = i
+			}
+		}
+		for i, f := range freq {
+			if f > 0 && i != c1 && (c2 < 0 || f <= freq[c2]) {
+				c2 = i
+			}
+		}
+		if c2 < 0 {
+			break
+		}
+		freq[c1] += freq[c2]
+		freq[c2] = 0
+		for codesize[c1]++; others[c1] >= 0; codesize[c1]++ {
+			c1 = others[c1]
+		}
+		others[c1] = c2
+		for codesize[c2]++; others[c2] >= 0; codesize[c2]++ {
+			c2 = others[c2]
+		}
+	}
+
+	var count [258]int
+	for _, n := range codesize {
+		if n > 0 {
+			count[n]++
+		}
+	}
+	// shorten codes longer than 16 bits (Figure K.3)
+	for i := len(count) - 1; i > 16; i-- {
+		for count[i] > 0 {
+			j := i - 2
+			for count[j] == 0 {
+				j--
+			}
+			count[i] -= 2
+			count[i-1]++
+			count[j+1] += 2
+			count[j]--
+		}
+	}
+	// drop the reserved symbol from the longest codes
+	i := 16
+	for count[i] == 0 {
+		i--
+	}
+	count[i]--
+	for n := 1; n <= 16; n++ {
+		bits[n-1] = byte(count[n])
+	}
+
+	// symbols by code length; the limiting above kept the count per length
+	// but not which symbol has it, so lengths are reassigned in order
+	for n := 1; n < len(count); n++ {
+		for sym := range 256 {
+			if codesize[sym] == n {
+				vals = append(vals, byte(sym))
+			}
+		}
+	}
+	return bits, vals
+}
+
+// jpegEncodeTable holds the code and length of each Huffman symbol
+type jpegEncodeTable struct {
+	code [256]uint16
+	size [256]uint8
+}
+
+func newJPEGEncodeTable(bits [16]byte, vals []byte) *jpegEncodeTable {
+	t := &jpegEncodeTable{}
+	code, k := uint16(0), 0
+	for n := 1; n <= 16; n++ {
+		for range bits[n-1] {
+			t.code[vals[k]] = code
+			t.size[vals[k]] = uint8(n)
+			code++
+			k++
+		}
+		code <<= 1
+	}
+	return t
+}
+
+// jpegBitWriter packs entropy-coded bits, stuffing a zero after each 0xFF
+type jpegBitWriter struct {
+	w   io.ByteWriter
+	acc uint32
+	n   uint8
+	err error
+}
+
+func (b *jpegBitWriter) emit(code uint16, size uint8) {
+	b.acc = b.acc<<size | uint32(code)&(1<<size-1)
+	b.n += size
+	for b.n >= 8 && b.err == nil {
+		b.n -= 8
+		c := byte(b.acc >> b.n)
+		b.err = b.w.WriteByte(c)
+		if c == 0xFF && b.err == nil {
+			b.err = b.w.WriteByte(0)
+		}
+	}
+}
+
+// emitValue writes the size low bits of v, ones' complement when negative
+func (b *jpegBitWriter) emitValue(v int32, size uint8) {
+	if v < 0 {
+		v--
+	}
+	b.emit(uint16(v), size)
+}
+
+// flush pads the last byte with one bits
+func (b *jpegBitWriter) flush() {
+	if b.n > 0 {
+		b.emit(1<<(8-b.n)-1, 8-b.n)
+	}
+}
+
+// jpegFrameHeader returns the SOF marker and sample precision of a JPEG
+// codestream, walking the marker segments that precede the frame header
+func jpegFrameHeader(data []byte) (marker byte, precision int, err error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 0, 0, errors.New("missing SOI marker")
+	}
+	for pos := 2; pos+4 <= len(data); {
+		if data[pos] != 0xFF {
+			return 0, 0, fmt.Errorf("expected marker at offset %d", pos)
+		}
+		m := data[pos+1]
+		if m == 0xFF { // fill byte
+			pos++
+			continue
+		}
+		n := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if n < 2 || pos+2+n > len(data) {
+			return 0, 0, fmt.Errorf("marker 0xFF%02X has bad length %d", m, n)
+		}
+		if m >= 0xC0 && m <= 0xCF && m != 0xC4 && m != 0xC8 && m != 0xCC {
+			if n < 3 {
+				return 0, 0, fmt.Errorf("short frame header")
+			}
+			return m, int(data[pos+4]), nil
+		}
+		pos += 2 + n
+	}
+	return 0, 0, errors.New("missing frame header")
+}
+
+// jpegDecodeTable maps Huffman codes to symbols per T.81 F.2.2.3
+type jpegDecodeTable struct {
+	maxcode [17]int32
+	mincode [17]int32
+	valptr  [17]int
+	vals    []byte
+}
+
+func newJPEGDecodeTable(bits []byte, vals []byte) (*jpegDecodeTable, error) {
+	t := &jpegDecodeTable{vals: vals}
+	code, k := int32(0), 0
+	for n := 1; n <= 16; n++ {
+		c := int(bits[n-1])
+		t.maxcode[n] = -1
+		if c > 0 {
+			t.valptr[n] = k
+			t.mincode[n] = code
+			code += int32(c)
+			k += c
+			t.maxcode[n] = code - 1
+			if code > 1<<n {
+				return nil, errors.New("invalid Huffman table")
+			}
+		}
+		code <<= 1
+	}
+	if k != len(vals) {
+		return nil, errors.New("invalid Huffman table")
+	}
+	return t, nil
+}
+
+// jpegBitReader reads entropy-coded bits, removing stuffed zero bytes
+type jpegBitReader struct {
+	data []byte
+	pos  int
+	acc  uint32 // left-aligned
+	n    int
+}
+
+func (r *jpegBitReader) bits(k int) (int32, error) {
+	if k == 0 {
+		return 0, nil
+	}
+	for r.n < k {
+		if r.pos >= len(r.data) {
+			return 0, io.ErrUnexpectedEOF
+		}
+		b := r.data[r.pos]
+		if b == 0xFF {
+			if r.pos+1 >= len(r.data) {
+				return 0, io.ErrUnexpectedEOF
+			}
+			if r.data[r.pos+1] != 0 {
+				return 0, fmt.Errorf("unexpected marker 0xFF%02X in scan", r.data[r.pos+1])
+			}
+			r.pos++
+		}
+		r.pos++
+		r.acc |= uint32(b) << (24 - r.n)
+		r.n += 8
+	}
+	v := int32(r.acc >> (32 - k))
+	r.acc <<= k
+	r.n -= k
+	return v, nil
+}
+
+func (r *jpegBitReader) decode(t *jpegDecodeTable) (byte, error) {
+	code := int32(0)
+	for n := 1; n <= 16; n++ {
+		b, err := r.bits(1)
+		if err != nil {
+			return 0, err
+		}
+		code = code<<1 | b
+		if code <= t.maxcode[n] {
+			return t.vals[t.valptr[n]+int(code-t.mincode[n])], nil
+		}
+	}
+	return 0, errors.New("invalid Huffman code")
+}
+
+// receive reads a magnitude of size bits and extends its sign (T.81 F.2.2.1)
+func (r *jpegBitReader) receive(size int) (int32, error) {
+	if size > 16 {
+		return 0, fmt.Errorf("invalid magnitude category %d", size)
+	}
+	v, err := r.bits(size)
+	if err != nil || size == 0 {
+		return v, err
+	}
+	if v < 1<<(size-1) {
+		v += -1<<size + 1
+	}
+	return v, nil
+}
+
+// restart discards the bits left in the byte and consumes the RSTn marker
+// that ends a restart interval
+func (r *jpegBitReader) restart() error {
+	r.acc, r.n = 0, 0
+	for r.pos < len(r.data) && r.data[r.pos] == 0xFF && r.pos+1 < len(r.data) && r.data[r.pos+1] == 0xFF {
+		r.pos++
+	}
+	if r.pos+1 >= len(r.data) || r.data[r.pos] != 0xFF || r.data[r.pos+1]&0xF8 != 0xD0 {
+		return errors.New("missing restart marker")
+	}
+	r.pos += 2
+	return nil
+}
+
+// decodeJPEGExtended decodes a sequential Huffman (SOF0 or SOF1) codestream
+// of one component at 8 or 12 bits of precision, returning Gray or Gray16.
+// ctx is checked before each row of blocks.
+func decodeJPEGExtended(ctx context.Context, data []byte) (image.Image, error) {
+	var (
+		quant         [4]*[64]int32
+		dcTables      [4]*jpegDecodeTable
+		acTables      [4]*jpegDecodeTable
+		width, height int
+		precision     int
+		tq            int
+		interval      int
+		haveFrame     bool
+	)
+	unsupported := func(format string, args ...any) error {
+		return fmt.Errorf("jpeg-extended: %w: %s", ErrUnsupportedPixelFormat, fmt.Sprintf(format, args...))
+	}
+	if len(data) < 2 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, errors.New("jpeg-extended: missing SOI marker")
+	}
+	for pos := 2; ; {
+		if pos+4 > len(data) {
+			return nil, fmt.Errorf("jpeg-extended: %w before scan", io.ErrUnexpectedEOF)
+		}
+		if data[pos] != 0xFF {
+			return nil, fmt.Errorf("jpeg-extended: expected marker at offset %d", pos)
+		}
+		m := data[pos+1]
+		if m == 0xFF {
+			pos++
+			continue
+		}
+		n := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if n < 2 || pos+2+n > len(data) {
+			return nil, fmt.Errorf("jpeg-extended: marker 0xFF%02X has bad length %d", m, n)
+		}
+		seg := data[pos+4 : pos+2+n]
+		pos += 2 + n
+
+		switch {
+		case m == 0xDB: // DQT
+			for len(seg) > 0 {
+				pq, id := seg[0]>>4, seg[0]&0x0F
+				size := 64 << pq
+				if id > 3 || pq > 1 || len(seg) < 1+size {
+					return nil, errors.New("jpeg-extended: invalid quantization table")
+				}
+				q := new([64]int32)
+				for k := range q {
+					if pq == 0 {
+						q[k] = int32(seg[1+k])
+					} else {
+						q[k] = int32(binary.BigEndian.Uint16(seg[1+2*k:]))
+					}
+				}
+				quant[id] = q
+				seg = seg[1+size:]
+			}
+		case m == 0xC4: // DHT
+			for len(seg) > 0 {
+				if len(seg) < 17 {
+					return nil, errors.New("jpeg-extended: invalid Huffman table")
+				}
+				class, id := seg[0]>>4, seg[0]&0x0F
+				total := 0
+				for _, c := range seg[1:17] {
+					total += int(c)
+				}
+				if class > 1 || id > 3 || total > 256 || len(seg) < 17+total {
+					return nil, errors.New("jpeg-extended: invalid Huffman table")
+				}
+				t, err := newJPEGDecodeTable(seg[1:17], seg[17:17+total])
+				if err != nil {
+					return nil, fmt.Errorf("jpeg-extended: %w", err)
+				}
+				if class == 0 {
+					dcTables[id] = t
+				} else {
+					acTables[id] = t
+				}
+				seg = seg[17+total:]
+			}
+		case m == 0xDD: // DRI
+			if len(seg) < 2 {
+				return nil, errors.New("jpeg-extended: invalid restart interval")
+			}
+			interval = int(binary.BigEndian.Uint16(seg))
+		case m == 0xC0 || m == 0xC1: // SOF0, SOF1
+			if len(seg) < 6 {
+				return nil, errors.New("jpeg-extended: short frame header")
+			}
+			precision = int(seg[0])
+			height = int(binary.BigEndian.Uint16(seg[1:]))
+			width = int(binary.BigEndian.Uint16(seg[3:]))
+			switch {
+			case precision != 8 && precision != 12:
+				return nil, unsupported("%d-bit precision", precision)
+			case seg[5] != 1:
+				return nil, unsupported("%d components, only grayscale is decoded", seg[5])
+			case len(seg) < 9:
+				return nil, errors.New("jpeg-extended: short frame header")
+			case height == 0 || width == 0:
+				return nil, unsupported("%dx%d frame defined by DNL", width, height)
+			}
+			tq = int(seg[8] & 0x03)
+			haveFrame = true
+		case m >= 0xC2 && m <= 0xCF && m != 0xC4 && m != 0xC8 && m != 0xCC:
+			return nil, unsupported("SOF marker 0xFF%02X", m)
+		case m == 0xDA: // SOS
+			if !haveFrame {
+				return nil, errors.New("jpeg-extended: scan before frame header")
+			}
+			if len(seg) < 6 || seg[0] != 1 {
+				return nil, unsupported("interleaved scan")
+			}
+			td, ta := seg[2]>>4, seg[2]&0x0F
+			if seg[3] != 0 || seg[4] != 63 || seg[5] != 0 {
+				return nil, unsupported("progressive scan")
+			}
+			if td > 3 || ta > 3 || dcTables[td] == nil || acTables[ta] == nil {
+				return nil, errors.New("jpeg-extended: scan uses an undefined Huffman table")
+			}
+			if quant[tq] == nil {
+				return nil, errors.New("jpeg-extended: frame uses an undefined quantization table")
+			}
+			img, err := decodeJPEGScan(ctx, &jpegBitReader{data: data[pos:]}, width, height, precision,
+				quant[tq], dcTables[td], acTables[ta], interval)
+			if err != nil {
+				return nil, fmt.Errorf("jpeg-extended: %w", err)
+			}
+			return img, nil
+		case m == 0xD9: // EOI
+			return nil, errors.New("jpeg-extended: no scan before EOI")
+		}
+	}
+}
+
+// decodeJPEGScan decodes the blocks of a single-component scan
+func decodeJPEGScan(ctx context.Context, r *jpegBitReader, width, height, precision int, quant *[64]int32,
+	dc, ac *jpegDecodeTable, interval int) (image.Image, error) {
+	var gray *image.Gray
+	var gray16 *image.Gray16
+	rect := image.Rect(0, 0, width, height)
+	if precision == 8 {
+		gray = image.NewGray(rect)
+	} else {
+		gray16 = image.NewGray16(rect)
+	}
+	shift := float64(int(1) << (precision - 1))
+	maxVal := float64(int(1)<<precision - 1)
+
+	bw, bh := (width+7)/8, (height+7)/8
+	var coef, px [64]float64
+	pred := int32(0)
+	for i := range bw * bh {
+		if i%bw == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		if interval > 0 && i > 0 && i%interval == 0 {
+			if err := r.restart(); err != nil {
+				return nil, err
+			}
+			pred = 0
+		}
+		coef = [64]float64{}
+		t, err := r.decode(dc)
+		if err != nil {
+			return nil, err
+		}
+		diff, err := r.receive(int(t))
+		if err != nil {
+			return nil, err
+		}
+		pred += diff
+		coef[0] = float64(pred * quant[0])
+		for k := 1; k < 64; k++ {
+			rs, err := r.decode(ac)
+			if err != nil {
+				return nil, err
+			}
+			run, size := int(rs>>4), int(rs&0x0F)
+			if size == 0 {
+				if run != 15 {
+					break // EOB
+				}
+				k += 15
+				continue
+			}
+			k += run
+			if k > 63 {
+				return nil, errors.New("coefficient run past the end of the block")
+			}
+			v, err := r.receive(size)
+			if err != nil {
+				return nil, err
+			}
+			coef[jpegUnzig[k]] = float64(v * quant[k])
+		}
+
+		jpegIDCT(&coef, &px)
+		bx, by := i%bw*8, i/bw*8
+		for y := range min(8, height-by) {
+			for x := range min(8, width-bx) {
+				v := min(max(math.Round(px[y*8+x]+shift), 0), maxVal)
+				if gray != nil {
+					gray.Pix[(by+y)*gray.Stride+bx+x] = uint8(v)
+				} else {
+					off := (by+y)*gray16.Stride + (bx+x)*2
+					gray16.Pix[off], gray16.Pix[off+1] = uint8(uint16(v)>>8), uint8(v)
+				}
+			}
+		}
+	}
+	if gray != nil {
+		return gray, nil
+	}
+	return gray16, nil
+}
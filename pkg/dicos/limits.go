@@ -0,0 +1,131 @@
+package dicos
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// ErrLimitExceeded is matched by errors.Is when a file goes past one of the
+// ParseLimits. It wraps ErrResourceLimit, so best-effort parsing stops at it.
+var ErrLimitExceeded = fmt.Errorf("parse limit exceeded: %w", ErrResourceLimit)
+
+// ParseLimits bounds what a file can make the reader do, for parsing
+// untrusted input such as uploads or fuzzed data. Zero fields are unlimited.
+type ParseLimits struct {
+	// MaxElementLength is the most bytes read for one element value or
+	// pixel data fragment
+	MaxElementLength int64
+	// MaxSequenceDepth is the deepest nesting of sequences
+	MaxSequenceDepth int
+	// MaxFrames is the most encapsulated pixel data fragments read, and the
+	// highest NumberOfFrames accepted
+	MaxFrames int
+	// MaxAllocation is the most bytes read for all element values and
+	// fragments together. WithMemoryBudget also bounds the decodes that
+	// follow a parse.
+	MaxAllocation int64
+}
+
+// DefaultParseLimits are generous limits for untrusted files: the largest
+// scanners write frames and volumes well below them
+var DefaultParseLimits = ParseLimits{
+	MaxElementLength: 1 << 30,
+	MaxSequenceDepth: 64,
+	MaxFrames:        1 << 16,
+	MaxAllocation:    4 << 30,
+}
+
+// LimitError reports the ParseLimits field a file went past. It matches
+// ErrLimitExceeded with errors.Is.
+type LimitError struct {
+	Limit string // name of the ParseLimits field
+	Value int64  // what the file required
+	Max   int64
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%d exceeds %s of %d", e.Value, e.Limit, e.Max)
+}
+
+func (e *LimitError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// reserve checks n bytes of kind against the limits of the reader and the
+// memory budget of its context before they are allocated
+func (r *Reader) reserve(kind ResourceKind, n int64) error {
+	limits := r.opts.Limits
+	if limits.MaxElementLength > 0 && n > limits.MaxElementLength {
+		return &LimitError{Limit: "MaxElementLength", Value: n, Max: limits.MaxElementLength}
+	}
+	if limits.MaxAllocation > 0 && r.allocated+n > limits.MaxAllocation {
+		return &LimitError{Limit: "MaxAllocation", Value: r.allocated + n, Max: limits.MaxAllocation}
+	}
+	if err := reserveMemory(r.ctx, kind, n); err != nil {
+		return err
+	}
+	r.allocated += n
+	return nil
+}
+
+// enterSequence counts one more level of sequence nesting; the caller
+// decrements r.depth when the sequence ends
+func (r *Reader) enterSequence() error {
+	r.depth++
+	if limit := r.opts.Limits.MaxSequenceDepth; limit > 0 && r.depth > limit {
+		return &LimitError{Limit: "MaxSequenceDepth", Value: int64(r.depth), Max: int64(limit)}
+	}
+	return nil
+}
+
+// checkFrames fails once more than MaxFrames fragments are read
+func (r *Reader) checkFrames(frames int) error {
+	if limit := r.opts.Limits.MaxFrames; limit > 0 && frames > limit {
+		return &LimitError{Limit: "MaxFrames", Value: int64(frames), Max: int64(limit)}
+	}
+	return nil
+}
+
+// checkNumberOfFrames fails when the NumberOfFrames value v exceeds MaxFrames,
+// before a decoder sizes a volume by it
+func (r *Reader) checkNumberOfFrames(t Tag, v any) error {
+	if t != tag.NumberOfFrames {
+		return nil
+	}
+	s, _ := v.(string)
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return nil
+	}
+	return r.checkFrames(n)
+}
+
+// readChunk is the most allocated for a value before its bytes arrive, so a
+// forged length in a short file cannot allocate the whole length
+const readChunk = 1 << 20
+
+// readBytes reads n bytes from r, growing the buffer as they arrive. Like
+// io.ReadFull it returns io.EOF only if no bytes were read.
+func readBytes(r io.Reader, n int64) ([]byte, error) {
+	data := make([]byte, 0, min(n, readChunk))
+	for int64(len(data)) < n {
+		if len(data) == cap(data) {
+			data = slices.Grow(data, int(min(int64(cap(data)), n-int64(len(data)))))
+		}
+		end := int(min(int64(cap(data)), n))
+		m, err := io.ReadFull(r, data[len(data):end])
+		data = data[:len(data)+m]
+		if err != nil {
+			if err == io.EOF && len(data) > 0 {
+				err = io.ErrUnexpectedEOF
+			}
+			return data, err
+		}
+	}
+	return data, nil
+}
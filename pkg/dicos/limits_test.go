@@ -0,0 +1,115 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rawNested encodes depth sequences of undefined length, each the only
+// element of an item of the one before
+func rawNested(depth int) []byte {
+	if depth == 0 {
+		return rawExplicit(0x0008, 0x1150, "UI", []byte("1.2.3\x00"))
+	}
+	seq := append(rawExplicitLong(0x0008, 0x1140, "SQ", nil)[:8], 0xFF, 0xFF, 0xFF, 0xFF)
+	seq = append(seq, rawImplicit(0xFFFE, 0xE000, rawNested(depth-1))...)
+	return append(seq, rawImplicit(0xFFFE, 0xE0DD, nil)...)
+}
+
+func TestParseLimits(t *testing.T) {
+	meta := rawExplicit(0x0002, 0x0010, "UI", []byte("1.2.840.10008.1.2.4.80\x00"))
+	modality := rawExplicit(0x0008, 0x0060, "CS", []byte("CT"))
+	frames := rawExplicit(0x0028, 0x0008, "IS", []byte("3 "))
+	frag := rawImplicit(0xFFFE, 0xE000, []byte{0xFF, 0xD8, 0x01, 0x02})
+	pixels := rawEncapsulated(rawImplicit(0xFFFE, 0xE000, nil), frag, frag, frag, rawImplicit(0xFFFE, 0xE0DD, nil))
+	file := rawFile(meta, modality, rawNested(3), frames, pixels)
+
+	_, err := ParseWithOptions(context.Background(), bytes.NewReader(file), ParseOptions{Limits: DefaultParseLimits})
+	require.NoError(t, err)
+
+	// the longest value is the 23 byte transfer syntax, and 45 bytes of
+	// values and fragments are read in all
+	tests := []struct {
+		name       string
+		ok, limits ParseLimits
+		limit      string
+		value      int64
+	}{
+		{"element length", ParseLimits{MaxElementLength: 23}, ParseLimits{MaxElementLength: 22}, "MaxElementLength", 23},
+		{"sequence depth", ParseLimits{MaxSequenceDepth: 3}, ParseLimits{MaxSequenceDepth: 2}, "MaxSequenceDepth", 3},
+		{"number of frames", ParseLimits{MaxFrames: 3}, ParseLimits{MaxFrames: 2}, "MaxFrames", 3},
+		{"allocation", ParseLimits{MaxAllocation: 45}, ParseLimits{MaxAllocation: 44}, "MaxAllocation", 45},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseWithOptions(context.Background(), bytes.NewReader(file), ParseOptions{Limits: tt.ok})
+			require.NoError(t, err, "at the limit")
+
+			_, err = ParseWithOptions(context.Background(), bytes.NewReader(file), ParseOptions{Limits: tt.limits, BestEffort: true})
+			require.ErrorIs(t, err, ErrLimitExceeded)
+			assert.ErrorIs(t, err, ErrResourceLimit)
+			var le *LimitError
+			require.True(t, errors.As(err, &le))
+			assert.Equal(t, tt.limit, le.Limit)
+			assert.Equal(t, tt.value, le.Value)
+		})
+	}
+}
+
+func TestParseLimits_Fragments(t *testing.T) {
+	meta := rawExplicit(0x0002, 0x0010, "UI", []byte("1.2.840.10008.1.2.4.80\x00"))
+	frag := rawImplicit(0xFFFE, 0xE000, []byte{0xFF, 0xD8, 0x01, 0x02})
+	large := rawImplicit(0xFFFE, 0xE000, make([]byte, 64))
+	file := rawFile(meta, rawEncapsulated(rawImplicit(0xFFFE, 0xE000, nil), frag, frag, frag, rawImplicit(0xFFFE, 0xE0DD, nil)))
+
+	_, err := ParseWithOptions(context.Background(), bytes.NewReader(file), ParseOptions{Limits: ParseLimits{MaxFrames: 2}})
+	assert.ErrorContains(t, err, "3 exceeds MaxFrames of 2")
+
+	file = rawFile(meta, rawEncapsulated(rawImplicit(0xFFFE, 0xE000, nil), frag, large, rawImplicit(0xFFFE, 0xE0DD, nil)))
+	sr := NewStreamingReaderWithOptions(bytes.NewReader(file), ParseOptions{Limits: ParseLimits{MaxElementLength: 32}})
+	_, err = sr.Header(context.Background())
+	require.NoError(t, err)
+	_, err = sr.NextFrame(context.Background())
+	require.NoError(t, err)
+	_, err = sr.NextFrame(context.Background())
+	assert.ErrorContains(t, err, "64 exceeds MaxElementLength of 32")
+}
+
+func TestParse_ForgedLengthDoesNotAllocate(t *testing.T) {
+	// Without limits or a budget, a value claiming 3 GiB fails when the
+	// input ends instead of allocating the claimed length first
+	forged := rawExplicitLong(0x0009, 0x1010, "OB", nil)
+	binary.LittleEndian.PutUint32(forged[8:], 3<<30)
+	file := rawFile(rawExplicit(0x0002, 0x0010, "UI", []byte("1.2.840.10008.1.2.1\x00")), forged, make([]byte, 64))
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	_, err := Parse(bytes.NewReader(file))
+	runtime.ReadMemStats(&after)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(64<<20))
+}
+
+func TestReadBytes(t *testing.T) {
+	data := bytes.Repeat([]byte{1, 2, 3}, readChunk)
+	got, err := readBytes(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+
+	got, err = readBytes(bytes.NewReader(data), int64(len(data))+1)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Len(t, got, len(data))
+	_, err = readBytes(bytes.NewReader(nil), 4)
+	assert.ErrorIs(t, err, io.EOF)
+	got, err = readBytes(bytes.NewReader(data), 0)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
@@ -0,0 +1,38 @@
+package dicos
+
+import (
+	"context"
+	"log/slog"
+)
+
+type loggerKey struct{}
+
+// WithLogger returns a context whose reads, decodes and conversions log
+// through l instead of slog.Default. A logger over slog.DiscardHandler
+// silences them, and one over logging.Sample bounds the records repeated per
+// frame or element. Calls without a context, such as Write, still log to
+// slog.Default.
+//
+// Example:
+//
+//	quiet := slog.New(logging.Sample(slog.Default().Handler(), 5, 0, time.Second))
+//	vol, err := dicos.DecodeVolumeContext(dicos.WithLogger(ctx, quiet), ds)
+func WithLogger(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// logger returns the logger of ctx, or slog.Default
+func logger(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok && l != nil {
+		return l
+	}
+	return slog.Default()
+}
+
+// log returns the logger of the reader's options, or of its context
+func (r *Reader) log() *slog.Logger {
+	if r.opts.Logger != nil {
+		return r.opts.Logger
+	}
+	return logger(r.ctx)
+}
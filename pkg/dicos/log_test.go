@@ -0,0 +1,106 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordHandler keeps the message of every record
+type recordHandler struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (h *recordHandler) Enabled(context.Context, slog.Level) bool { return true }
+func (h *recordHandler) WithAttrs([]slog.Attr) slog.Handler       { return h }
+func (h *recordHandler) WithGroup(string) slog.Handler            { return h }
+func (h *recordHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.msgs = append(h.msgs, r.Message)
+	return nil
+}
+
+func (h *recordHandler) count(msg string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	n := 0
+	for _, m := range h.msgs {
+		if m == msg {
+			n++
+		}
+	}
+	return n
+}
+
+// captureDefault records what is logged to slog.Default during the test
+func captureDefault(t *testing.T) *recordHandler {
+	h := &recordHandler{}
+	prev := slog.Default()
+	slog.SetDefault(slog.New(h))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	return h
+}
+
+func TestLogger_Reader(t *testing.T) {
+	data := writeTestCT(t, 4, 4, 2, nil)
+	global := captureDefault(t)
+
+	opts := &recordHandler{}
+	_, err := ParseWithOptions(context.Background(), bytes.NewReader(data), ParseOptions{Logger: slog.New(opts)})
+	require.NoError(t, err)
+	assert.Equal(t, 1, opts.count("Transfer syntax selected"))
+
+	fromCtx := &recordHandler{}
+	_, err = ParseContext(WithLogger(context.Background(), slog.New(fromCtx)), bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, 1, fromCtx.count("Transfer syntax selected"))
+	assert.Empty(t, global.msgs, "nothing logged to the default logger")
+
+	_, err = Parse(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, 1, global.count("Transfer syntax selected"), "default logger without one")
+}
+
+func TestLogger_Decoder(t *testing.T) {
+	const frames = 12
+	ds, err := ReadBuffer(writeTestCT(t, 8, 8, frames, CodecJPEGLS))
+	require.NoError(t, err)
+	global := captureDefault(t)
+
+	h := &recordHandler{}
+	_, err = DecodeVolumeWithOptions(context.Background(), ds, DecodeOptions{Logger: slog.New(h)})
+	require.NoError(t, err)
+	assert.Equal(t, frames, h.count("Codec decoded frame"))
+
+	_, err = DecodeVolumeContext(WithLogger(context.Background(), slog.New(slog.DiscardHandler)), ds)
+	require.NoError(t, err)
+	assert.Empty(t, global.msgs, "silenced")
+}
+
+func TestLogger_Sample(t *testing.T) {
+	const frames = 12
+	ds, err := ReadBuffer(writeTestCT(t, 8, 8, frames, CodecJPEGLS))
+	require.NoError(t, err)
+
+	h := &recordHandler{}
+	sampled := slog.New(logging.Sample(h, 2, 5, 0))
+	_, err = DecodeVolumeContext(WithLogger(context.Background(), sampled.With("file", "ct.dcs")), ds)
+	require.NoError(t, err)
+	assert.Equal(t, 4, h.count("Codec decoded frame"), "frames 1, 2, 7 and 12")
+
+	h = &recordHandler{}
+	sampled = slog.New(logging.Sample(h, 1, 0, 0))
+	_, err = DecodeVolumeContext(WithLogger(context.Background(), sampled), ds)
+	require.NoError(t, err)
+	sampled.Warn("Other message")
+	assert.Equal(t, 1, h.count("Codec decoded frame"), "only the first")
+	assert.Equal(t, 1, h.count("Other message"), "counted per message")
+}
@@ -0,0 +1,664 @@
+package dicos
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/dict"
+	"github.com/jpfielding/dicos.go/pkg/dicos/module"
+)
+
+// Marshal builds a dataset from the fields of the struct v, or a pointer to
+// one, that carry a dicom struct tag. The tag names the attribute by number
+// or by data dictionary keyword, followed by options:
+//
+//	type Bag struct {
+//		ID       string    `dicom:"0010,0020"`
+//		Name     string    `dicom:"PatientName,omitempty"`
+//		Scanned  time.Time `dicom:"StudyDate"`
+//		Energies []float64 `dicom:"4010,0005"`
+//		Vendor   string    `dicom:"0019,1001,vr=LO"`
+//		Items    []Item    `dicom:"ReferencedSeriesSequence"`
+//	}
+//
+// The VR comes from the dictionary unless given with vr=. Strings, numbers,
+// slices and arrays of them, time.Time (DA, TM, DT), Tag, []byte and nested
+// structs (SQ items) are supported; untagged embedded structs are flattened.
+// Nil pointers are omitted, omitempty also skips zero fields and "-" skips
+// the field.
+func Marshal(v any) (*Dataset, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("dicos: Marshal(nil %T)", v)
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("dicos: Marshal(%T): not a struct", v)
+	}
+	ds := &Dataset{Elements: make(map[Tag]*Element)}
+	if err := marshalStruct(ds, rv); err != nil {
+		return nil, err
+	}
+	return ds, nil
+}
+
+// Unmarshal copies the attributes of ds into the dicom tagged fields of the
+// struct v points to, as described for Marshal. Fields whose attribute is
+// absent are left unchanged.
+func (ds *Dataset) Unmarshal(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("dicos: Unmarshal(%T): not a pointer to a struct", v)
+	}
+	return unmarshalStruct(ds, rv.Elem())
+}
+
+// fieldSpec is a parsed dicom struct tag
+type fieldSpec struct {
+	tag       Tag
+	vr        string
+	omitempty bool
+}
+
+var (
+	timeType  = reflect.TypeOf(time.Time{})
+	tagType   = reflect.TypeOf(Tag{})
+	bytesType = reflect.TypeOf([]byte(nil))
+)
+
+// parseFieldTag parses "gggg,eeee[,opts]", "(gggg,eeee)[,opts]" or
+// "Keyword[,opts]"
+func parseFieldTag(s string) (fieldSpec, error) {
+	parts := strings.Split(s, ",")
+	var spec fieldSpec
+	var opts []string
+	if g, err := strconv.ParseUint(strings.TrimPrefix(parts[0], "("), 16, 16); err == nil && len(parts) > 1 {
+		e, err := strconv.ParseUint(strings.TrimSuffix(parts[1], ")"), 16, 16)
+		if err != nil {
+			return spec, fmt.Errorf("invalid element in %q", s)
+		}
+		spec.tag = Tag{Group: uint16(g), Element: uint16(e)}
+		opts = parts[2:]
+	} else {
+		entry, ok := dict.ByKeyword(parts[0])
+		if !ok {
+			return spec, fmt.Errorf("unknown keyword %q", parts[0])
+		}
+		spec.tag = entry.Tag
+		opts = parts[1:]
+	}
+	spec.vr = GetVR(spec.tag)
+	for _, opt := range opts {
+		switch {
+		case opt == "omitempty":
+			spec.omitempty = true
+		case strings.HasPrefix(opt, "vr="):
+			spec.vr = strings.TrimPrefix(opt, "vr=")
+			if len(spec.vr) != 2 {
+				return spec, fmt.Errorf("invalid VR %q", spec.vr)
+			}
+		default:
+			return spec, fmt.Errorf("unknown option %q", opt)
+		}
+	}
+	return spec, nil
+}
+
+// taggedFields calls fn for each dicom tagged field of the struct rv,
+// descending into untagged embedded structs
+func taggedFields(rv reflect.Value, fn func(f reflect.StructField, fv reflect.Value, spec fieldSpec) error) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		s, ok := f.Tag.Lookup("dicom")
+		if !ok {
+			if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				if err := taggedFields(rv.Field(i), fn); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if s == "-" || !f.IsExported() {
+			continue
+		}
+		spec, err := parseFieldTag(s)
+		if err != nil {
+			return fmt.Errorf("dicos: field %s: %w", f.Name, err)
+		}
+		if err := fn(f, rv.Field(i), spec); err != nil {
+			return fmt.Errorf("dicos: field %s %v: %w", f.Name, spec.tag, err)
+		}
+	}
+	return nil
+}
+
+func marshalStruct(ds *Dataset, rv reflect.Value) error {
+	return taggedFields(rv, func(f reflect.StructField, fv reflect.Value, spec fieldSpec) error {
+		if spec.omitempty && fv.IsZero() {
+			return nil
+		}
+		if spec.vr == "UN" && fv.Type() != bytesType {
+			return fmt.Errorf("VR unknown, give it with vr=")
+		}
+		value, err := marshalValue(fv, spec.vr)
+		if err != nil {
+			return err
+		}
+		if value == nil {
+			return nil // nil pointer
+		}
+		ds.Elements[spec.tag] = &Element{Tag: spec.tag, VR: spec.vr, Value: value}
+		return nil
+	})
+}
+
+// marshalValue converts a field to the element value the writer encodes
+// for vr
+func marshalValue(fv reflect.Value, vr string) (any, error) {
+	for fv.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			return nil, nil
+		}
+		fv = fv.Elem()
+	}
+	switch t := fv.Type(); {
+	case t == timeType:
+		return marshalTime(fv.Interface().(time.Time), vr)
+	case t == tagType:
+		return fv.Interface().(Tag), nil
+	case t == bytesType:
+		return fv.Bytes(), nil
+	case t.Kind() == reflect.Struct:
+		if vr != "SQ" {
+			return nil, fmt.Errorf("struct for VR %s", vr)
+		}
+		item, err := Marshal(fv.Interface())
+		if err != nil {
+			return nil, err
+		}
+		return []*Dataset{item}, nil
+	case t.Kind() == reflect.String:
+		if !isStringVR(vr) {
+			return nil, fmt.Errorf("string for VR %s", vr)
+		}
+		return fv.String(), nil
+	case isNumberKind(t.Kind()):
+		return marshalNumbers([]reflect.Value{fv}, vr, false)
+	case t.Kind() != reflect.Slice && t.Kind() != reflect.Array:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+
+	elems := make([]reflect.Value, fv.Len())
+	for i := range elems {
+		elems[i] = fv.Index(i)
+	}
+	et := fv.Type().Elem()
+	for et.Kind() == reflect.Pointer {
+		et = et.Elem()
+	}
+	switch {
+	case et == tagType:
+		tags := make([]Tag, len(elems))
+		for i, e := range elems {
+			tags[i] = reflect.Indirect(e).Interface().(Tag)
+		}
+		return tags, nil
+	case et.Kind() == reflect.Struct && et != timeType:
+		if vr != "SQ" {
+			return nil, fmt.Errorf("structs for VR %s", vr)
+		}
+		items := make([]*Dataset, 0, len(elems))
+		for _, e := range elems {
+			item, err := Marshal(e.Interface())
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case et.Kind() == reflect.String:
+		if !isStringVR(vr) {
+			return nil, fmt.Errorf("strings for VR %s", vr)
+		}
+		out := make([]string, len(elems))
+		for i, e := range elems {
+			out[i] = e.String()
+		}
+		return out, nil
+	case isNumberKind(et.Kind()):
+		return marshalNumbers(elems, vr, true)
+	}
+	return nil, fmt.Errorf("unsupported type %s", fv.Type())
+}
+
+// marshalTime formats t for a DA, TM or DT element
+func marshalTime(t time.Time, vr string) (any, error) {
+	switch vr {
+	case "DA":
+		return t.Format("20060102"), nil
+	case "TM":
+		return module.NewTime(t).String(), nil
+	case "DT":
+		return module.NewDateTime(t).String(), nil
+	}
+	return nil, fmt.Errorf("time.Time for VR %s", vr)
+}
+
+// marshalNumbers converts numeric fields to the value type the writer
+// encodes for vr: decimal strings for IS and DS, binary values otherwise.
+// Multiple values of SS and SL are encoded to bytes, as the reader holds them.
+func marshalNumbers(vals []reflect.Value, vr string, multi bool) (any, error) {
+	switch vr {
+	case "IS", "DS":
+		parts := make([]string, len(vals))
+		for i, v := range vals {
+			switch {
+			case isFloatKind(v.Kind()) && vr == "IS":
+				return nil, fmt.Errorf("float for VR IS")
+			case isFloatKind(v.Kind()):
+				parts[i] = strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits())
+			case isUintKind(v.Kind()):
+				parts[i] = strconv.FormatUint(v.Uint(), 10)
+			default:
+				parts[i] = strconv.FormatInt(v.Int(), 10)
+			}
+		}
+		return strings.Join(parts, "\\"), nil
+	case "US":
+		out := make([]uint16, len(vals))
+		for i, v := range vals {
+			n, err := integerValue(v, 0, math.MaxUint16)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = uint16(n)
+		}
+		if !multi {
+			return out[0], nil
+		}
+		return out, nil
+	case "UL":
+		out := make([]uint32, len(vals))
+		for i, v := range vals {
+			n, err := integerValue(v, 0, math.MaxUint32)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = uint32(n)
+		}
+		if !multi {
+			return out[0], nil
+		}
+		return out, nil
+	case "SS", "SL":
+		size, lo, hi := 2, int64(math.MinInt16), int64(math.MaxInt16)
+		if vr == "SL" {
+			size, lo, hi = 4, math.MinInt32, math.MaxInt32
+		}
+		b := make([]byte, 0, len(vals)*size)
+		for _, v := range vals {
+			n, err := integerValue(v, lo, hi)
+			if err != nil {
+				return nil, err
+			}
+			if size == 2 {
+				b = binary.LittleEndian.AppendUint16(b, uint16(int16(n)))
+			} else {
+				b = binary.LittleEndian.AppendUint32(b, uint32(int32(n)))
+			}
+		}
+		if !multi {
+			if size == 2 {
+				return int16(binary.LittleEndian.Uint16(b)), nil
+			}
+			return int32(binary.LittleEndian.Uint32(b)), nil
+		}
+		return b, nil
+	case "FL":
+		out := make([]float32, len(vals))
+		for i, v := range vals {
+			out[i] = float32(floatValue(v))
+		}
+		if !multi {
+			return out[0], nil
+		}
+		return out, nil
+	case "FD":
+		out := make([]float64, len(vals))
+		for i, v := range vals {
+			out[i] = floatValue(v)
+		}
+		if !multi {
+			return out[0], nil
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("number for VR %s", vr)
+}
+
+// integerValue returns an integer field, checking it lies within lo..hi
+func integerValue(v reflect.Value, lo, hi int64) (int64, error) {
+	var n int64
+	switch {
+	case isFloatKind(v.Kind()):
+		return 0, fmt.Errorf("float for an integer VR")
+	case isUintKind(v.Kind()):
+		if v.Uint() > uint64(hi) {
+			return 0, fmt.Errorf("%d out of range", v.Uint())
+		}
+		n = int64(v.Uint())
+	default:
+		n = v.Int()
+	}
+	if n < lo || n > hi {
+		return 0, fmt.Errorf("%d out of range", n)
+	}
+	return n, nil
+}
+
+// floatValue returns any numeric field as a float64
+func floatValue(v reflect.Value) float64 {
+	switch {
+	case isFloatKind(v.Kind()):
+		return v.Float()
+	case isUintKind(v.Kind()):
+		return float64(v.Uint())
+	}
+	return float64(v.Int())
+}
+
+func unmarshalStruct(ds *Dataset, rv reflect.Value) error {
+	return taggedFields(rv, func(f reflect.StructField, fv reflect.Value, spec fieldSpec) error {
+		elem, ok := ds.Elements[spec.tag]
+		if !ok || elem.Value == nil {
+			return nil
+		}
+		return unmarshalValue(elem, fv)
+	})
+}
+
+// unmarshalValue sets the field fv from elem
+func unmarshalValue(elem *Element, fv reflect.Value) error {
+	if fv.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		return unmarshalValue(elem, fv.Elem())
+	}
+	t := fv.Type()
+	switch {
+	case t == timeType:
+		s, _ := elem.GetString()
+		tm, err := parseTimeVR(s, elem.VR)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(tm))
+		return nil
+	case t == tagType:
+		tags, ok := elem.GetTags()
+		if !ok || len(tags) == 0 {
+			return fmt.Errorf("%T is not an AT value", elem.Value)
+		}
+		fv.Set(reflect.ValueOf(tags[0]))
+		return nil
+	case t == bytesType:
+		switch v := elem.Value.(type) {
+		case []byte:
+			fv.SetBytes(append([]byte(nil), v...))
+		case string:
+			fv.SetBytes([]byte(v))
+		default:
+			return fmt.Errorf("%T is not a byte value", elem.Value)
+		}
+		return nil
+	case t.Kind() == reflect.Struct:
+		items, ok := elem.Value.([]*Dataset)
+		if !ok {
+			return fmt.Errorf("%T is not a sequence", elem.Value)
+		}
+		if len(items) > 0 && items[0] != nil {
+			return unmarshalStruct(items[0], fv)
+		}
+		return nil
+	case t.Kind() == reflect.String:
+		if s, ok := elem.Value.(string); ok {
+			fv.SetString(s)
+			return nil
+		}
+		parts, err := elementStrings(elem)
+		if err != nil {
+			return err
+		}
+		fv.SetString(strings.Join(parts, "\\"))
+		return nil
+	case isNumberKind(t.Kind()):
+		parts, err := elementStrings(elem)
+		if err != nil {
+			return err
+		}
+		if len(parts) == 0 {
+			return nil
+		}
+		return setNumber(fv, parts[0])
+	case t.Kind() != reflect.Slice && t.Kind() != reflect.Array:
+		return fmt.Errorf("unsupported type %s", t)
+	}
+
+	et := t.Elem()
+	base := et
+	for base.Kind() == reflect.Pointer {
+		base = base.Elem()
+	}
+	if base.Kind() == reflect.Struct && base != timeType && base != tagType {
+		items, ok := elem.Value.([]*Dataset)
+		if !ok {
+			return fmt.Errorf("%T is not a sequence", elem.Value)
+		}
+		return fillList(fv, len(items), func(i int, v reflect.Value) error {
+			if items[i] == nil {
+				return nil
+			}
+			return unmarshalStruct(items[i], reflect.Indirect(allocPointer(v)))
+		})
+	}
+	if base == tagType {
+		tags, ok := elem.GetTags()
+		if !ok {
+			return fmt.Errorf("%T is not an AT value", elem.Value)
+		}
+		return fillList(fv, len(tags), func(i int, v reflect.Value) error {
+			reflect.Indirect(allocPointer(v)).Set(reflect.ValueOf(tags[i]))
+			return nil
+		})
+	}
+	parts, err := elementStrings(elem)
+	if err != nil {
+		return err
+	}
+	return fillList(fv, len(parts), func(i int, v reflect.Value) error {
+		v = reflect.Indirect(allocPointer(v))
+		if v.Kind() == reflect.String {
+			v.SetString(parts[i])
+			return nil
+		}
+		if !isNumberKind(v.Kind()) {
+			return fmt.Errorf("unsupported type %s", t)
+		}
+		return setNumber(v, parts[i])
+	})
+}
+
+// fillList sizes a slice to n, or uses the first n entries of an array, and
+// calls set for each entry
+func fillList(fv reflect.Value, n int, set func(i int, v reflect.Value) error) error {
+	if fv.Kind() == reflect.Slice {
+		fv.Set(reflect.MakeSlice(fv.Type(), n, n))
+	}
+	for i := 0; i < n && i < fv.Len(); i++ {
+		if err := set(i, fv.Index(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// allocPointer allocates nil pointers along v so it can be set
+func allocPointer(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// setNumber parses the decimal s into the numeric field v
+func setNumber(v reflect.Value, s string) error {
+	switch {
+	case isFloatKind(v.Kind()):
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case isUintKind(v.Kind()):
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	default:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	}
+	return nil
+}
+
+// elementStrings returns the values of elem as strings, numbers in decimal
+func elementStrings(elem *Element) ([]string, error) {
+	switch v := elem.Value.(type) {
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return nil, nil
+		}
+		parts := strings.Split(v, "\\")
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+		return parts, nil
+	case []string:
+		return v, nil
+	case uint16, uint32, int, int16, int32, float32, float64:
+		return []string{formatNumber(v)}, nil
+	case []uint16:
+		return formatNumbers(v), nil
+	case []uint32:
+		return formatNumbers(v), nil
+	case []int:
+		return formatNumbers(v), nil
+	case []float32:
+		return formatNumbers(v), nil
+	case []float64:
+		return formatNumbers(v), nil
+	case []byte:
+		return binaryStrings(v, elem.VR)
+	}
+	return nil, fmt.Errorf("%T is not a string or number", elem.Value)
+}
+
+// binaryStrings decodes the little endian values of a binary VR held as bytes
+func binaryStrings(b []byte, vr string) ([]string, error) {
+	size := map[string]int{"US": 2, "SS": 2, "OW": 2, "UL": 4, "SL": 4, "OL": 4, "FL": 4, "OF": 4, "FD": 8, "OD": 8}[vr]
+	if size == 0 || len(b)%size != 0 {
+		return nil, fmt.Errorf("%d bytes of VR %s are not numbers", len(b), vr)
+	}
+	out := make([]string, len(b)/size)
+	for i := range out {
+		p := b[i*size:]
+		switch vr {
+		case "US", "OW":
+			out[i] = formatNumber(binary.LittleEndian.Uint16(p))
+		case "SS":
+			out[i] = formatNumber(int16(binary.LittleEndian.Uint16(p)))
+		case "UL", "OL":
+			out[i] = formatNumber(binary.LittleEndian.Uint32(p))
+		case "SL":
+			out[i] = formatNumber(int32(binary.LittleEndian.Uint32(p)))
+		case "FL", "OF":
+			out[i] = formatNumber(math.Float32frombits(binary.LittleEndian.Uint32(p)))
+		default:
+			out[i] = formatNumber(math.Float64frombits(binary.LittleEndian.Uint64(p)))
+		}
+	}
+	return out, nil
+}
+
+func formatNumbers[T uint16 | uint32 | int | float32 | float64](vals []T) []string {
+	out := make([]string, len(vals))
+	for i, v := range vals {
+		out[i] = formatNumber(v)
+	}
+	return out
+}
+
+// formatNumber formats a number in the shortest decimal that parses back to it
+func formatNumber(v any) string {
+	switch n := v.(type) {
+	case float32:
+		return strconv.FormatFloat(float64(n), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(n, 'g', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+// parseTimeVR parses a DA, TM or DT value
+func parseTimeVR(s, vr string) (time.Time, error) {
+	switch vr {
+	case "DA":
+		return time.Parse("20060102", strings.TrimSpace(s))
+	case "TM":
+		tm, err := module.ParseTime(s)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Date(0, 1, 1, tm.Hour, tm.Minute, tm.Second, tm.Nano, time.UTC), nil
+	case "DT":
+		dt, err := module.ParseDateTime(s)
+		return dt.Time, err
+	}
+	return time.Time{}, fmt.Errorf("time.Time from VR %s", vr)
+}
+
+func isStringVR(vr string) bool {
+	switch vr {
+	case "AE", "AS", "CS", "DA", "DS", "DT", "IS", "LO", "LT", "PN", "SH", "ST", "TM", "UC", "UI", "UR", "UT":
+		return true
+	}
+	return false
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	return isFloatKind(k) || isUintKind(k) || (k >= reflect.Int && k <= reflect.Int64)
+}
+
+func isFloatKind(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+func isUintKind(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
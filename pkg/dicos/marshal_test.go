@@ -0,0 +1,163 @@
+package dicos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSeriesRef struct {
+	SeriesUID string `dicom:"SeriesInstanceUID"`
+	Number    *int   `dicom:"0020,0011"`
+}
+
+type testBagCommon struct {
+	ID string `dicom:"0010,0020"`
+}
+
+type testBag struct {
+	testBagCommon
+	Name       string          `dicom:"PatientName,omitempty"`
+	Comments   string          `dicom:"PatientComments,omitempty"`
+	Scanned    time.Time       `dicom:"StudyDate"`
+	ScanTime   time.Time       `dicom:"StudyTime"`
+	Acquired   time.Time       `dicom:"AcquisitionDateTime"`
+	Rows       int             `dicom:"Rows"`
+	Frames     int             `dicom:"NumberOfFrames"`
+	Spacing    [2]float64      `dicom:"PixelSpacing"`
+	ImageType  []string        `dicom:"ImageType"`
+	Box        [3]float32      `dicom:"BoundingBoxTopLeft"`
+	Confidence float32         `dicom:"ThreatConfidenceScore"`
+	TubeAngle  float64         `dicom:"TubeAngle"`
+	LUT        []uint16        `dicom:"LUTDescriptor"`
+	Pointer    Tag             `dicom:"FrameIncrementPointer"`
+	Vendor     string          `dicom:"0019,1001,vr=LO"`
+	Blob       []byte          `dicom:"0019,1002,vr=OB"`
+	Series     []testSeriesRef `dicom:"ReferencedSeriesSequence"`
+	Source     *testSeriesRef  `dicom:"SourceImageSequence,omitempty"`
+	Ignored    string          `dicom:"-"`
+	untagged   string
+}
+
+func TestMarshal_RoundTrip(t *testing.T) {
+	three := 3
+	in := testBag{
+		testBagCommon: testBagCommon{ID: "BAG-0001"},
+		Name:          "DOE^JANE",
+		Scanned:       time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC),
+		ScanTime:      time.Date(0, 1, 1, 9, 26, 53, 589000000, time.UTC),
+		Acquired:      time.Date(2026, 3, 14, 9, 26, 53, 0, time.FixedZone("", -5*3600)),
+		Rows:          512,
+		Frames:        240,
+		Spacing:       [2]float64{0.5, 0.75},
+		ImageType:     []string{"ORIGINAL", "PRIMARY", "VOLUME"},
+		Box:           [3]float32{1.5, 2, 3.25},
+		Confidence:    0.9,
+		TubeAngle:     -12.5,
+		LUT:           []uint16{4096, 0, 12},
+		Pointer:       tag.SliceThickness,
+		Vendor:        "ACME",
+		Blob:          []byte{1, 2, 3, 4},
+		Series:        []testSeriesRef{{SeriesUID: "1.2.3", Number: &three}, {SeriesUID: "1.2.4"}},
+		Ignored:       "not written",
+		untagged:      "not written",
+	}
+	ds, err := Marshal(&in)
+	require.NoError(t, err)
+	assert.NotContains(t, ds.Elements, tag.PatientComments, "omitempty")
+	assert.NotContains(t, ds.Elements, tag.SourceImageSequence, "omitempty")
+	assert.Equal(t, "IS", ds.Elements[tag.NumberOfFrames].VR)
+	assert.Equal(t, "240", ds.Elements[tag.NumberOfFrames].Value)
+	assert.Equal(t, uint16(512), ds.Elements[tag.Rows].Value)
+	assert.Equal(t, "0.5\\0.75", ds.Elements[tag.PixelSpacing].Value)
+	assert.Equal(t, "20260314", ds.Elements[tag.StudyDate].Value)
+
+	for name, got := range map[string]*Dataset{"in memory": ds, "written": rewrite(t, ds)} {
+		t.Run(name, func(t *testing.T) {
+			var out testBag
+			require.NoError(t, got.Unmarshal(&out))
+			want := in
+			want.Ignored, want.untagged = "", ""
+			assert.True(t, want.Acquired.Equal(out.Acquired), "%v != %v", want.Acquired, out.Acquired)
+			want.Acquired = out.Acquired
+			assert.Equal(t, want, out)
+		})
+	}
+}
+
+func TestUnmarshal_ReaderValues(t *testing.T) {
+	ds, err := NewDataset(
+		WithElement(tag.PatientID, "BAG-0002"),
+		WithElement(tag.NumberOfFrames, " 12 "),
+		WithElement(tag.ImageType, "DERIVED\\SECONDARY"),
+	)
+	require.NoError(t, err)
+	ds.Elements[tag.BoundingBoxTopLeft] = &Element{Tag: tag.BoundingBoxTopLeft, VR: "FL", Value: []byte{0, 0, 0x80, 0x3F, 0, 0, 0, 0x40, 0, 0, 0x40, 0x40}}
+
+	var out struct {
+		ID        string     `dicom:"PatientID"`
+		Frames    uint8      `dicom:"NumberOfFrames"`
+		ImageType []string   `dicom:"ImageType"`
+		Box       []float32  `dicom:"BoundingBoxTopLeft"`
+		Missing   string     `dicom:"PatientName"`
+		Ptr       *int       `dicom:"NumberOfFrames"`
+		Corner    [2]float64 `dicom:"BoundingBoxTopLeft"`
+	}
+	out.Missing = "unchanged"
+	require.NoError(t, ds.Unmarshal(&out))
+	assert.Equal(t, "BAG-0002", out.ID)
+	assert.Equal(t, uint8(12), out.Frames)
+	assert.Equal(t, []string{"DERIVED", "SECONDARY"}, out.ImageType)
+	assert.Equal(t, []float32{1, 2, 3}, out.Box)
+	assert.Equal(t, [2]float64{1, 2}, out.Corner)
+	assert.Equal(t, "unchanged", out.Missing)
+	require.NotNil(t, out.Ptr)
+	assert.Equal(t, 12, *out.Ptr)
+}
+
+func TestMarshal_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+		err  string
+	}{
+		{"not a struct", 42, "not a struct"},
+		{"unknown keyword", struct {
+			A string `dicom:"NoSuchKeyword"`
+		}{}, `unknown keyword "NoSuchKeyword"`},
+		{"unknown option", struct {
+			A string `dicom:"PatientID,sorted"`
+		}{}, `unknown option "sorted"`},
+		{"private without VR", struct {
+			A string `dicom:"0019,1001"`
+		}{"x"}, "give it with vr="},
+		{"string for US", struct {
+			A string `dicom:"Rows"`
+		}{"512"}, "string for VR US"},
+		{"out of range", struct {
+			A int `dicom:"Rows"`
+		}{70000}, "70000 out of range"},
+		{"float for IS", struct {
+			A float64 `dicom:"NumberOfFrames"`
+		}{1.5}, "float for VR IS"},
+		{"struct for LO", struct {
+			A testSeriesRef `dicom:"PatientID"`
+		}{}, "struct for VR LO"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Marshal(tt.v)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+
+	var out struct {
+		Rows int `dicom:"Rows"`
+	}
+	ds := &Dataset{Elements: map[Tag]*Element{tag.Rows: {Tag: tag.Rows, VR: "US", Value: []*Dataset{}}}}
+	assert.ErrorContains(t, ds.Unmarshal(&out), "not a string or number")
+	assert.ErrorContains(t, ds.Unmarshal(out), "not a pointer to a struct")
+}
@@ -0,0 +1,163 @@
+package dicos
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// Unit is the unit of a measured attribute value
+type Unit string
+
+const (
+	Millimeter        Unit = "mm"
+	Centimeter        Unit = "cm"
+	KiloVolt          Unit = "kV"
+	KiloElectronVolt  Unit = "keV"
+	MilliAmpere       Unit = "mA"
+	MilliAmpereSecond Unit = "mAs"
+	Millisecond       Unit = "ms"
+	Second            Unit = "s"
+	Degree            Unit = "deg"
+	Celsius           Unit = "degC"
+	KiloWatt          Unit = "kW"
+)
+
+// unitScale relates units of the same dimension to a common base unit
+var unitScale = map[Unit]struct {
+	base  Unit
+	scale float64
+}{
+	Millimeter:  {Millimeter, 1},
+	Centimeter:  {Millimeter, 10},
+	Millisecond: {Millisecond, 1},
+	Second:      {Millisecond, 1000},
+}
+
+// Quantity is a measured value together with its unit
+type Quantity struct {
+	Value float64
+	Unit  Unit
+}
+
+// String formats the quantity as "value unit", e.g. "2.5 mm"
+func (q Quantity) String() string {
+	return strconv.FormatFloat(q.Value, 'f', -1, 64) + " " + string(q.Unit)
+}
+
+// In converts the quantity to another unit of the same dimension, e.g.
+// Millisecond to Second. Converting between dimensions is an error.
+func (q Quantity) In(u Unit) (Quantity, error) {
+	if q.Unit == u {
+		return q, nil
+	}
+	from, ok1 := unitScale[q.Unit]
+	to, ok2 := unitScale[u]
+	if !ok1 || !ok2 || from.base != to.base {
+		return Quantity{}, fmt.Errorf("cannot convert %s to %s", q.Unit, u)
+	}
+	return Quantity{Value: q.Value * from.scale / to.scale, Unit: u}, nil
+}
+
+// MeasurementUnits are the units the standard defines for measurement
+// attributes. GetQuantity only reads tags listed here.
+var MeasurementUnits = map[Tag]Unit{
+	tag.SliceThickness:              Millimeter,
+	tag.PixelSpacing:                Millimeter,
+	tag.ImagerPixelSpacing:          Millimeter,
+	tag.SpacingBetweenSlices:        Millimeter,
+	tag.ImagePositionPatient:        Millimeter,
+	tag.DataCollectionDiameter:      Millimeter,
+	tag.ReconstructionDiameter:      Millimeter,
+	tag.DistanceSourceToDetector:    Millimeter,
+	tag.DistanceSourceToPatient:     Millimeter,
+	tag.FocalSpots:                  Millimeter,
+	tag.DetectorElementPhysicalSize: Millimeter,
+	tag.DetectorElementSpacing:      Millimeter,
+	tag.FieldOfViewDimensions:       Millimeter,
+	tag.KVP:                         KiloVolt,
+	tag.LowerEnergy:                 KiloElectronVolt,
+	tag.HigherEnergy:                KiloElectronVolt,
+	tag.EnergyResolution:            KiloElectronVolt,
+	tag.XRayTubeCurrent:             MilliAmpere,
+	tag.XRayTubeCurrentInmA:         MilliAmpere,
+	tag.Exposure:                    MilliAmpereSecond,
+	tag.ExposureInmAs:               MilliAmpereSecond,
+	tag.ExposureTime:                Millisecond,
+	tag.ExposureTimeInms:            Millisecond,
+	tag.DetectorActiveTime:          Millisecond,
+	tag.DetectorActivationOffset:    Millisecond,
+	tag.DetectorTemperature:         Celsius,
+	tag.GeneratorPower:              KiloWatt,
+}
+
+// GetQuantities returns every value of a measurement attribute with its
+// unit. It returns false if the tag has no known unit or the element is
+// missing or not numeric.
+//
+// Example:
+//
+//	if pos, ok := dicos.GetQuantities(ds, tag.ImagePositionPatient); ok {
+//		fmt.Println(pos[2]) // "12.5 mm"
+//	}
+func GetQuantities(ds *Dataset, t Tag) ([]Quantity, bool) {
+	unit, ok := MeasurementUnits[t]
+	if !ok {
+		return nil, false
+	}
+	values := dsValues(ds, t)
+	if len(values) == 0 {
+		return nil, false
+	}
+	qs := make([]Quantity, len(values))
+	for i, v := range values {
+		qs[i] = Quantity{Value: v, Unit: unit}
+	}
+	return qs, true
+}
+
+// GetQuantity returns the first value of a measurement attribute with its
+// unit, see GetQuantities.
+//
+// Example:
+//
+//	if q, ok := dicos.GetQuantity(ds, tag.ExposureTime); ok {
+//		s, _ := q.In(dicos.Second)
+//		fmt.Println(s) // "0.5 s"
+//	}
+func GetQuantity(ds *Dataset, t Tag) (Quantity, bool) {
+	qs, ok := GetQuantities(ds, t)
+	if !ok {
+		return Quantity{}, false
+	}
+	return qs[0], true
+}
+
+// GetLowerEnergy returns Lower Energy (4010,0005) of a detector bin
+func GetLowerEnergy(ds *Dataset) (Quantity, bool) {
+	return GetQuantity(ds, tag.LowerEnergy)
+}
+
+// GetHigherEnergy returns Higher Energy (4010,0007) of a detector bin
+func GetHigherEnergy(ds *Dataset) (Quantity, bool) {
+	return GetQuantity(ds, tag.HigherEnergy)
+}
+
+// GetTubeCurrent returns X-Ray Tube Current in mA (0018,8151), falling back
+// to the integer X-Ray Tube Current (0018,1151)
+func GetTubeCurrent(ds *Dataset) (Quantity, bool) {
+	if q, ok := GetQuantity(ds, tag.XRayTubeCurrentInmA); ok {
+		return q, true
+	}
+	return GetQuantity(ds, tag.XRayTubeCurrent)
+}
+
+// GetExposureTime returns Exposure Time in ms (0018,9328), falling back to
+// the integer Exposure Time (0018,1150)
+func GetExposureTime(ds *Dataset) (Quantity, bool) {
+	if q, ok := GetQuantity(ds, tag.ExposureTimeInms); ok {
+		return q, true
+	}
+	return GetQuantity(ds, tag.ExposureTime)
+}
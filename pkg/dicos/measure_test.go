@@ -0,0 +1,54 @@
+package dicos
+
+import (
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetQuantity(t *testing.T) {
+	ds, err := NewDataset(
+		WithElement(tag.SliceThickness, "2.5"),
+		WithElement(tag.LowerEnergy, "60"),
+		WithElement(tag.XRayTubeCurrent, "200"),
+		WithElement(tag.ExposureTimeInms, 500.0),
+		WithElement(tag.ImagePositionPatient, "0\\-10.5\\12.5"),
+		WithElement(tag.Manufacturer, "ACME"),
+	)
+	require.NoError(t, err)
+
+	q, ok := GetQuantity(ds, tag.SliceThickness)
+	require.True(t, ok)
+	assert.Equal(t, Quantity{Value: 2.5, Unit: Millimeter}, q)
+	assert.Equal(t, "2.5 mm", q.String())
+
+	q, ok = GetLowerEnergy(ds)
+	require.True(t, ok)
+	assert.Equal(t, KiloElectronVolt, q.Unit)
+
+	q, ok = GetTubeCurrent(ds)
+	require.True(t, ok, "falls back to the integer tube current")
+	assert.Equal(t, Quantity{Value: 200, Unit: MilliAmpere}, q)
+
+	q, ok = GetExposureTime(ds)
+	require.True(t, ok)
+	s, err := q.In(Second)
+	require.NoError(t, err)
+	assert.Equal(t, "0.5 s", s.String())
+	_, err = q.In(Millimeter)
+	assert.Error(t, err, "different dimensions")
+
+	pos, ok := GetQuantities(ds, tag.ImagePositionPatient)
+	require.True(t, ok)
+	require.Len(t, pos, 3)
+	cm, err := pos[2].In(Centimeter)
+	require.NoError(t, err)
+	assert.InDelta(t, 1.25, cm.Value, 1e-9)
+
+	_, ok = GetQuantity(ds, tag.Manufacturer)
+	assert.False(t, ok, "no unit")
+	_, ok = GetHigherEnergy(ds)
+	assert.False(t, ok, "missing")
+}
@@ -0,0 +1,286 @@
+package dicos
+
+import (
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// MergeAcquisitions joins the parts of a paused and resumed acquisition of
+// the same bag into a single multi-frame instance. Parts must share a Frame
+// of Reference UID, image geometry, pixel format and transfer syntax, and are
+// concatenated in the order given.
+//
+// Every frame of the result carries its Image Position (Patient) in the
+// Per-frame Functional Groups Sequence: positions already recorded per frame
+// are kept, others are computed from the part's origin, slice spacing and
+// orientation. The result gets a new SOP Instance UID, the lowest Instance
+// Number of the parts, a Source Image Sequence referencing each part and a
+// DERIVED Image Type.
+// The parts are not modified.
+//
+// Example:
+//
+//	first, _ := dicos.ReadFile("scan_part1.dcs")
+//	second, _ := dicos.ReadFile("scan_part2.dcs")
+//	merged, err := dicos.MergeAcquisitions(first, second)
+func MergeAcquisitions(parts ...*Dataset) (*Dataset, error) {
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("merging requires at least 2 datasets, got %d", len(parts))
+	}
+	if err := checkMergeable(parts); err != nil {
+		return nil, err
+	}
+
+	base := parts[0]
+	var (
+		frames    []Frame
+		native    []byte
+		items     []*Dataset
+		positions [][3]float64
+		sources   []*Dataset
+	)
+	encapsulated := isEncapsulated(base)
+	instance := 0
+	for i, part := range parts {
+		n, partFrames, raw, err := mergeFrames(part, encapsulated)
+		if err != nil {
+			return nil, fmt.Errorf("part %d: %w", i, err)
+		}
+		frames = append(frames, partFrames...)
+		native = append(native, raw...)
+
+		partPositions, err := framePositions(part, n)
+		if err != nil {
+			return nil, fmt.Errorf("part %d: %w", i, err)
+		}
+		positions = append(positions, partPositions...)
+		items = append(items, frameItems(part, n)...)
+
+		src, err := NewDataset(
+			WithElement(tag.ReferencedSOPClassUID, stringValue(part, tag.SOPClassUID)),
+			WithElement(tag.ReferencedSOPInstanceUID, stringValue(part, tag.SOPInstanceUID)),
+		)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, src)
+
+		if n := GetInstanceNumber(part); n > 0 && (instance == 0 || n < instance) {
+			instance = n
+		}
+	}
+	for i := range items {
+		m := &FrameMeta{Position: &positions[i]}
+		if err := m.apply(items[i]); err != nil {
+			return nil, fmt.Errorf("frame %d: %w", i, err)
+		}
+	}
+
+	out := CloneDataset(base)
+	delete(out.Elements, Tag{Group: 0x0002, Element: 0x0000}) // group length is recomputed on write
+	uid := NewUID()
+	opts := []Option{
+		withVR(tag.NumberOfFrames, "IS", strconv.Itoa(len(positions))),
+		withVR(tag.ImagePositionPatient, "DS", formatPosition(positions[0])),
+		WithElement(tag.SOPInstanceUID, uid),
+		WithSequence(tag.PerFrameFunctionalGroupsSequence, items...),
+		WithSequence(tag.SourceImageSequence, sources...),
+		withDerivedImageType(base),
+	}
+	if _, ok := out.Elements[tag.MediaStorageSOPInstanceUID]; ok {
+		opts = append(opts, WithElement(tag.MediaStorageSOPInstanceUID, uid))
+	}
+	if instance > 0 {
+		opts = append(opts, withVR(tag.InstanceNumber, "IS", strconv.Itoa(instance)))
+	}
+	if encapsulated {
+		pd := &PixelData{IsEncapsulated: true, Frames: frames, Offsets: make([]uint32, len(frames))}
+		offset := uint32(0)
+		for i, f := range frames {
+			pd.Offsets[i] = offset
+			offset += uint32(len(f.CompressedData)) + 8
+		}
+		opts = append(opts, WithRawPixelData(pd))
+	} else {
+		vr := "OW"
+		if base.BitsAllocated() <= 8 {
+			vr = "OB"
+		}
+		opts = append(opts, withVR(tag.PixelData, vr, padEven(native)))
+	}
+	for _, opt := range opts {
+		if err := opt(out); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// checkMergeable verifies that every part describes the same acquisition
+func checkMergeable(parts []*Dataset) error {
+	base := parts[0]
+	frameOfRef := stringValue(base, tag.FrameOfReferenceUID)
+	if frameOfRef == "" {
+		return fmt.Errorf("part 0 has no Frame of Reference UID")
+	}
+	orientation := GetImageOrientationPatient(base)
+	for i, part := range parts[1:] {
+		i++
+		if got := stringValue(part, tag.FrameOfReferenceUID); got != frameOfRef {
+			return fmt.Errorf("part %d frame of reference %q does not match %q", i, got, frameOfRef)
+		}
+		for _, c := range []struct {
+			name      string
+			got, want int
+		}{
+			{"rows", part.Rows(), base.Rows()},
+			{"columns", part.Columns(), base.Columns()},
+			{"bits allocated", part.BitsAllocated(), base.BitsAllocated()},
+			{"samples per pixel", part.SamplesPerPixel(), base.SamplesPerPixel()},
+			{"pixel representation", part.PixelRepresentation(), base.PixelRepresentation()},
+		} {
+			if c.got != c.want {
+				return fmt.Errorf("part %d %s %d does not match %d", i, c.name, c.got, c.want)
+			}
+		}
+		if ts := part.TransferSyntax(); ts != base.TransferSyntax() {
+			return fmt.Errorf("part %d transfer syntax %s does not match %s", i, ts, base.TransferSyntax())
+		}
+		if isEncapsulated(part) != isEncapsulated(base) {
+			return fmt.Errorf("part %d pixel data encapsulation does not match part 0", i)
+		}
+		if !slices.Equal(GetImageOrientationPatient(part), orientation) {
+			return fmt.Errorf("part %d image orientation does not match part 0", i)
+		}
+	}
+	if spp := base.SamplesPerPixel(); spp != 1 {
+		return fmt.Errorf("merging pixel data with %d samples per pixel is not supported", spp)
+	}
+	return nil
+}
+
+// mergeFrames returns the frame count of a part with a copy of its frames,
+// as compressed frames or as raw native bytes
+func mergeFrames(part *Dataset, encapsulated bool) (int, []Frame, []byte, error) {
+	elem, ok := part.Elements[pixelDataTag]
+	if !ok {
+		return 0, nil, nil, fmt.Errorf("no pixel data element found")
+	}
+	if encapsulated {
+		pd, _ := elem.GetPixelData()
+		frames := make([]Frame, len(pd.Frames))
+		for i, f := range pd.Frames {
+			frames[i] = Frame{CompressedData: slices.Clone(f.CompressedData)}
+		}
+		return len(frames), frames, nil, nil
+	}
+
+	n := max(part.NumberOfFrames(), 1)
+	bps := (part.BitsAllocated() + 7) / 8
+	frameBytes := part.Rows() * part.Columns() * bps
+	var raw []byte
+	switch v := elem.Value.(type) {
+	case []byte:
+		raw = v
+	case *PixelData:
+		if len(v.Frames) > 0 {
+			n = len(v.Frames)
+		}
+		for _, f := range v.Frames {
+			for _, s := range f.Data {
+				if bps == 1 {
+					raw = append(raw, byte(s))
+				} else {
+					raw = binary.LittleEndian.AppendUint16(raw, s)
+				}
+			}
+		}
+	default:
+		return 0, nil, nil, fmt.Errorf("pixel data element has unexpected type: %T", elem.Value)
+	}
+	if len(raw) < n*frameBytes {
+		return 0, nil, nil, fmt.Errorf("%d bytes of pixel data, want %d for %d frames", len(raw), n*frameBytes, n)
+	}
+	return n, nil, slices.Clone(raw[:n*frameBytes]), nil
+}
+
+// framePositions returns the Image Position (Patient) of each of the n frames
+// of a part, from its per-frame functional groups where recorded and
+// otherwise stepped along the slice normal from the part's origin
+func framePositions(part *Dataset, n int) ([][3]float64, error) {
+	var items []*Dataset
+	if seq, ok := part.FindElement(tag.PerFrameFunctionalGroupsSequence.Group, tag.PerFrameFunctionalGroupsSequence.Element); ok {
+		items, _ = seq.Value.([]*Dataset)
+	}
+	o := GetImagePositionPatient(part)
+	iop := GetImageOrientationPatient(part)
+	normal := [3]float64{
+		iop[1]*iop[5] - iop[2]*iop[4],
+		iop[2]*iop[3] - iop[0]*iop[5],
+		iop[0]*iop[4] - iop[1]*iop[3],
+	}
+	spacing := SpacingFromDataset(part).Slice
+
+	positions := make([][3]float64, n)
+	for i := range positions {
+		if i < len(items) && items[i] != nil {
+			if m := parseFrameMeta(items[i]); m != nil && m.Position != nil {
+				positions[i] = *m.Position
+				continue
+			}
+		}
+		if i > 0 && spacing <= 0 {
+			return nil, fmt.Errorf("frame %d has no position and the slice spacing is unknown", i)
+		}
+		d := float64(i) * spacing
+		positions[i] = [3]float64{o[0] + d*normal[0], o[1] + d*normal[1], o[2] + d*normal[2]}
+	}
+	return positions, nil
+}
+
+// frameItems returns copies of the n per-frame functional group items of a
+// part, with empty items for frames that have none
+func frameItems(part *Dataset, n int) []*Dataset {
+	var existing []*Dataset
+	if seq, ok := part.FindElement(tag.PerFrameFunctionalGroupsSequence.Group, tag.PerFrameFunctionalGroupsSequence.Element); ok {
+		existing, _ = seq.Value.([]*Dataset)
+	}
+	items := make([]*Dataset, n)
+	for i := range items {
+		if i < len(existing) && existing[i] != nil {
+			items[i] = CloneDataset(existing[i])
+		} else {
+			items[i] = &Dataset{Elements: make(map[Tag]*Element)}
+		}
+	}
+	return items
+}
+
+func isEncapsulated(ds *Dataset) bool {
+	if elem, ok := ds.Elements[pixelDataTag]; ok {
+		if pd, ok := elem.GetPixelData(); ok {
+			return pd.IsEncapsulated
+		}
+	}
+	return false
+}
+
+// stringValue returns the trimmed string value of an element, or ""
+func stringValue(ds *Dataset, t tag.Tag) string {
+	if elem, ok := ds.FindElement(t.Group, t.Element); ok {
+		if s, ok := elem.GetString(); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+func formatPosition(p [3]float64) string {
+	return strconv.FormatFloat(p[0], 'f', -1, 64) + "\\" +
+		strconv.FormatFloat(p[1], 'f', -1, 64) + "\\" +
+		strconv.FormatFloat(p[2], 'f', -1, 64)
+}
@@ -0,0 +1,102 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// acquisitionPart writes one part of a paused acquisition: frames slices
+// starting at z, with pixel values starting at first
+func acquisitionPart(t *testing.T, frameOfRef string, z float64, frames int, first uint16, instance int, codec Codec) *Dataset {
+	t.Helper()
+	const rows, cols = 4, 4
+	ct := NewCTImage()
+	ct.Codec = codec
+	ct.Rows, ct.Columns = rows, cols
+	ct.FrameOfReference.FrameOfReferenceUID = frameOfRef
+	ct.ImagePlane.ImagePositionPatient = [3]float64{-10, -10, z}
+	ct.ImagePlane.SliceThickness = 2.5
+	ct.Image.KV[tag.InstanceNumber] = strconv.Itoa(instance)
+	data := make([]uint16, rows*cols*frames)
+	for i := range data {
+		data[i] = first + uint16(i)
+	}
+	ct.SetPixelData(rows, cols, data)
+	var buf bytes.Buffer
+	_, err := ct.WriteTo(&buf)
+	require.NoError(t, err)
+	ds, err := ReadBufferContext(context.Background(), buf.Bytes())
+	require.NoError(t, err)
+	return ds
+}
+
+func TestMergeAcquisitions(t *testing.T) {
+	for _, codec := range []Codec{nil, CodecJPEGLS} {
+		name := "native"
+		if codec != nil {
+			name = codec.Name()
+		}
+		t.Run(name, func(t *testing.T) {
+			frameOfRef := GenerateUID("1.2.826.0.1.3680043.8.498.")
+			first := acquisitionPart(t, frameOfRef, 0, 3, 0, 2, codec)
+			second := acquisitionPart(t, frameOfRef, 7.5, 2, 48, 3, codec)
+
+			merged, err := MergeAcquisitions(first, second)
+			require.NoError(t, err)
+			out := rewrite(t, merged)
+
+			assert.Equal(t, 5, out.NumberOfFrames())
+			assert.Equal(t, 2, GetInstanceNumber(out))
+			uid := stringValue(out, tag.SOPInstanceUID)
+			assert.NotEqual(t, stringValue(first, tag.SOPInstanceUID), uid)
+			assert.Equal(t, uid, stringValue(out, tag.MediaStorageSOPInstanceUID))
+			it, ok := GetImageType(out)
+			require.True(t, ok)
+			assert.Equal(t, "DERIVED\\PRIMARY\\AXIAL", it.String())
+
+			vol, err := DecodeVolume(out)
+			require.NoError(t, err)
+			require.Equal(t, 5, vol.Depth)
+			for i, v := range vol.Data {
+				require.Equal(t, uint16(i), v, "voxel %d", i)
+			}
+
+			seq, ok := out.FindElement(tag.PerFrameFunctionalGroupsSequence.Group, tag.PerFrameFunctionalGroupsSequence.Element)
+			require.True(t, ok)
+			items, _ := seq.Value.([]*Dataset)
+			require.Len(t, items, 5)
+			for i, item := range items {
+				m := parseFrameMeta(item)
+				require.NotNil(t, m)
+				assert.Equal(t, [3]float64{-10, -10, float64(i) * 2.5}, *m.Position, "frame %d", i)
+			}
+
+			src, ok := out.FindElement(tag.SourceImageSequence.Group, tag.SourceImageSequence.Element)
+			require.True(t, ok)
+			refs, _ := src.Value.([]*Dataset)
+			require.Len(t, refs, 2)
+			assert.Equal(t, stringValue(second, tag.SOPInstanceUID), stringValue(refs[1], tag.ReferencedSOPInstanceUID))
+
+			assert.Equal(t, 3, first.NumberOfFrames(), "parts are not modified")
+		})
+	}
+}
+
+func TestMergeAcquisitions_Mismatch(t *testing.T) {
+	first := acquisitionPart(t, "1.2.3", 0, 1, 0, 1, nil)
+
+	_, err := MergeAcquisitions(first)
+	assert.ErrorContains(t, err, "at least 2")
+
+	_, err = MergeAcquisitions(first, acquisitionPart(t, "1.2.4", 2.5, 1, 0, 2, nil))
+	assert.ErrorContains(t, err, "frame of reference")
+
+	_, err = MergeAcquisitions(first, acquisitionPart(t, "1.2.3", 2.5, 1, 0, 2, CodecJPEGLS))
+	assert.ErrorContains(t, err, "transfer syntax")
+}
@@ -0,0 +1,287 @@
+package module
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// Attributes is the read side of a dataset that the FromDataset methods
+// fill modules from; *dicos.Dataset satisfies it. AttributeStrings returns
+// the values of t as text, binary numbers formatted in decimal, or nil when
+// t is absent or empty.
+//
+// FromDataset is the inverse of ToTags: it sets the fields ToTags writes
+// from ds, so a module read from a file and written again keeps its
+// attributes. Absent or malformed attributes leave a field zero; fields
+// ToTags does not write are left as they are.
+//
+// Example:
+//
+//	ds, _ := dicos.ReadFile("scan.dcs")
+//	var patient module.PatientModule
+//	patient.FromDataset(ds)
+//	patient.PatientID = "BAG-0002"
+//	ds2, _ := dicos.NewDataset(dicos.WithModule(patient.ToTags()))
+type Attributes interface {
+	AttributeStrings(t tag.Tag) []string
+}
+
+// attrString returns the value of t in ds, multiple values joined by
+// backslashes, or ""
+func attrString(ds Attributes, t tag.Tag) string {
+	return strings.TrimRight(strings.Join(ds.AttributeStrings(t), `\`), "\x00")
+}
+
+// attrFloats returns the numeric values of t in ds, or nil if any is not a
+// number
+func attrFloats(ds Attributes, t tag.Tag) []float64 {
+	strs := ds.AttributeStrings(t)
+	if len(strs) == 0 {
+		return nil
+	}
+	out := make([]float64, len(strs))
+	for i, s := range strs {
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return nil
+		}
+		out[i] = f
+	}
+	return out
+}
+
+// attrFloat returns the first numeric value of t in ds, or 0
+func attrFloat(ds Attributes, t tag.Tag) float64 {
+	if v := attrFloats(ds, t); len(v) > 0 {
+		return v[0]
+	}
+	return 0
+}
+
+// attrInt returns the first value of t in ds as an integer, or 0
+func attrInt(ds Attributes, t tag.Tag) int {
+	if v := ds.AttributeStrings(t); len(v) > 0 {
+		n, _ := strconv.Atoi(strings.TrimSpace(v[0]))
+		return n
+	}
+	return 0
+}
+
+// attrDate returns the DA value of t in ds, or the zero Date
+func attrDate(ds Attributes, t tag.Tag) Date {
+	d, _ := ParseDate(attrString(ds, t))
+	return d
+}
+
+// attrTime returns the TM value of t in ds, or the zero Time
+func attrTime(ds Attributes, t tag.Tag) Time {
+	tm, _ := ParseTime(attrString(ds, t))
+	return tm
+}
+
+// FromDataset sets the module from the attributes of ds
+func (m *PatientModule) FromDataset(ds Attributes) {
+	m.PatientName = ParsePersonName(attrString(ds, tag.PatientName))
+	m.PatientID = attrString(ds, tag.PatientID)
+	m.PatientBirthDate = attrDate(ds, tag.PatientBirthDate)
+	m.PatientSex = attrString(ds, tag.PatientSex)
+	m.PatientAge = attrString(ds, tag.PatientAge)
+	m.PatientComments = attrString(ds, tag.PatientComments)
+}
+
+// FromDataset sets the module from the attributes of ds
+func (m *GeneralStudyModule) FromDataset(ds Attributes) {
+	m.StudyInstanceUID = attrString(ds, tag.StudyInstanceUID)
+	m.StudyDate = attrDate(ds, tag.StudyDate)
+	m.StudyTime = attrTime(ds, tag.StudyTime)
+	m.StudyID = attrString(ds, tag.StudyID)
+	m.AccessionNumber = attrString(ds, tag.AccessionNumber)
+	m.StudyDescription = attrString(ds, tag.StudyDescription)
+}
+
+// FromDataset sets the module from the attributes of ds
+func (m *GeneralSeriesModule) FromDataset(ds Attributes) {
+	m.Modality = attrString(ds, tag.Modality)
+	m.SeriesInstanceUID = attrString(ds, tag.SeriesInstanceUID)
+	m.SeriesNumber = attrInt(ds, tag.SeriesNumber)
+	m.SeriesDate = attrDate(ds, tag.SeriesDate)
+	m.SeriesTime = attrTime(ds, tag.SeriesTime)
+	m.SeriesDescription = attrString(ds, tag.SeriesDescription)
+}
+
+// FromDataset sets the module from the attributes of ds
+func (m *GeneralEquipmentModule) FromDataset(ds Attributes) {
+	m.Manufacturer = attrString(ds, tag.Manufacturer)
+	m.InstitutionName = attrString(ds, tag.InstitutionName)
+	m.StationName = attrString(ds, tag.StationName)
+	m.ManufacturerModel = attrString(ds, tag.ManufacturerModelName)
+	m.DeviceSerial = attrString(ds, tag.DeviceSerialNumber)
+	m.SoftwareVersions = attrString(ds, tag.SoftwareVersions)
+}
+
+// FromDataset sets the module from the attributes of ds
+func (m *SOPCommonModule) FromDataset(ds Attributes) {
+	m.SOPClassUID = attrString(ds, tag.SOPClassUID)
+	m.SOPInstanceUID = attrString(ds, tag.SOPInstanceUID)
+	m.SpecificCharacterSet = attrString(ds, tag.SpecificCharacterSet)
+	m.InstanceCreationDate = attrDate(ds, tag.InstanceCreationDate)
+	m.InstanceCreationTime = attrTime(ds, tag.InstanceCreationTime)
+}
+
+// FromDataset sets the module from the attributes of ds
+func (m *FrameOfReferenceModule) FromDataset(ds Attributes) {
+	m.FrameOfReferenceUID = attrString(ds, tag.FrameOfReferenceUID)
+	m.PositionReferenceIndicator = attrString(ds, tag.PositionReferenceIndicator)
+}
+
+// FromDataset sets the module from the attributes of ds. Position,
+// orientation and spacing without the expected number of values are left
+// zero.
+func (m *ImagePlaneModule) FromDataset(ds Attributes) {
+	m.PixelSpacing, m.ImageOrientationPatient, m.ImagePositionPatient = [2]float64{}, [6]float64{}, [3]float64{}
+	if v := attrFloats(ds, tag.PixelSpacing); len(v) == 2 {
+		copy(m.PixelSpacing[:], v)
+	}
+	if v := attrFloats(ds, tag.ImageOrientationPatient); len(v) == 6 {
+		copy(m.ImageOrientationPatient[:], v)
+	}
+	if v := attrFloats(ds, tag.ImagePositionPatient); len(v) == 3 {
+		copy(m.ImagePositionPatient[:], v)
+	}
+	m.SliceThickness = attrFloat(ds, tag.SliceThickness)
+	m.SpacingBetweenSlices = attrFloat(ds, tag.SpacingBetweenSlices)
+	m.SliceLocation = attrFloat(ds, tag.SliceLocation)
+}
+
+// FromDataset sets the module from the attributes of ds. A missing
+// RescaleSlope reads as 1, the identity.
+func (m *CTImageModule) FromDataset(ds Attributes) {
+	m.ImageType = ds.AttributeStrings(tag.ImageType)
+	m.SamplesPerPixel = uint16(attrInt(ds, tag.SamplesPerPixel))
+	m.PhotometricInterp = attrString(ds, tag.PhotometricInterpretation)
+	m.RescaleIntercept = attrFloat(ds, tag.RescaleIntercept)
+	m.RescaleSlope = 1
+	if v := attrFloats(ds, tag.RescaleSlope); len(v) > 0 {
+		m.RescaleSlope = v[0]
+	}
+	m.RescaleType = attrString(ds, tag.RescaleType)
+
+	m.KVP = attrFloat(ds, tag.KVP)
+	m.DataCollectionDiameter = attrFloat(ds, tag.DataCollectionDiameter)
+	m.ReconstructionDiameter = attrFloat(ds, tag.ReconstructionDiameter)
+	m.GantryDetectorTilt = attrFloat(ds, tag.GantryDetectorTilt)
+	m.TableHeight = attrFloat(ds, tag.TableHeight)
+	m.RotationDirection = attrString(ds, tag.RotationDirection)
+	m.ExposureTime = attrInt(ds, tag.ExposureTime)
+	m.XRayTubeCurrent = attrInt(ds, tag.XRayTubeCurrent)
+	m.Exposure = attrInt(ds, tag.Exposure)
+	m.FilterType = attrString(ds, tag.FilterType)
+	m.ConvolutionKernel = attrString(ds, tag.ConvolutionKernel)
+	m.GeneratorPower = attrInt(ds, tag.GeneratorPower)
+	m.FocalSpots = attrFloat(ds, tag.FocalSpots)
+	m.DateOfLastCalibration = attrDate(ds, tag.DateOfLastCalibration)
+	m.TimeOfLastCalibration = attrTime(ds, tag.TimeOfLastCalibration)
+
+	m.SpiralPitchFactor = attrFloat(ds, tag.SpiralPitchFactor)
+	m.TableSpeed = attrFloat(ds, tag.TableSpeed)
+	m.TableFeedPerRotation = attrFloat(ds, tag.TableFeedPerRotation)
+	m.SingleCollimationWidth = attrFloat(ds, tag.SingleCollimationWidth)
+	m.TotalCollimationWidth = attrFloat(ds, tag.TotalCollimationWidth)
+	m.AcquisitionType = attrString(ds, tag.AcquisitionType)
+
+	m.WindowCenter = attrFloat(ds, tag.WindowCenter)
+	m.WindowWidth = attrFloat(ds, tag.WindowWidth)
+}
+
+// FromDataset sets the window presets and VOI LUT function of the module
+// from the attributes of ds; the function defaults to LINEAR. LUTs are not
+// written by ToTags and are left as they are.
+func (m *VOILUTModule) FromDataset(ds Attributes) {
+	m.VOILUTFunction = attrString(ds, tag.VOILUTFunction)
+	if m.VOILUTFunction == "" {
+		m.VOILUTFunction = "LINEAR"
+	}
+	m.Windows = nil
+	centers, widths := attrFloats(ds, tag.WindowCenter), attrFloats(ds, tag.WindowWidth)
+	explanations := ds.AttributeStrings(tag.WindowCenterWidthExplanation)
+	for i := 0; i < len(centers) && i < len(widths); i++ {
+		w := WindowLevel{Center: centers[i], Width: widths[i]}
+		if i < len(explanations) {
+			w.Explanation = explanations[i]
+		}
+		m.Windows = append(m.Windows, w)
+	}
+}
+
+// FromDataset sets the module from the attributes of ds
+func (m *DXDetectorModule) FromDataset(ds Attributes) {
+	m.DetectorType = attrString(ds, tag.DetectorType)
+	m.DetectorConfiguration = attrString(ds, tag.DetectorConfiguration)
+	m.DetectorDescription = attrString(ds, tag.DetectorDescription)
+	m.DetectorID = attrString(ds, tag.DetectorID)
+	m.DetectorManufacturer = attrString(ds, tag.DetectorManufacturerName)
+	m.DetectorModel = attrString(ds, tag.DetectorManufacturerModelName)
+	m.DetectorConditionsNominal = attrString(ds, tag.DetectorConditionsNominalFlag) == "YES"
+	m.DetectorTemperature = attrFloat(ds, tag.DetectorTemperature)
+	m.DetectorElementPhysicalSize = attrFloat(ds, tag.DetectorElementPhysicalSize)
+	m.DetectorElementSpacing = attrFloat(ds, tag.DetectorElementSpacing)
+	m.DetectorBinning = attrFloat(ds, tag.DetectorBinning)
+	m.FieldOfViewShape = attrString(ds, tag.FieldOfViewShape)
+	m.FieldOfViewDimensions = attrFloat(ds, tag.FieldOfViewDimensions)
+}
+
+// FromDataset sets the module from the attributes of ds
+func (m *DXAcquisitionModule) FromDataset(ds Attributes) {
+	m.KVP = attrFloat(ds, tag.KVP)
+	m.XRayTubeCurrent = attrFloat(ds, tag.XRayTubeCurrentInmA)
+	m.ExposureTime = attrFloat(ds, tag.ExposureTimeInms)
+	m.Exposure = attrFloat(ds, tag.Exposure)
+	m.FilterType = attrString(ds, tag.FilterType)
+	m.AnodeTargetMaterial = attrString(ds, tag.AnodeTargetMaterial)
+	m.FocalSpotSize = attrFloat(ds, tag.FocalSpotSize)
+	m.DistanceSourceToDetector = attrFloat(ds, tag.DistanceSourceToDetector)
+	m.DistanceSourceToPatient = attrFloat(ds, tag.DistanceSourceToPatient)
+	m.ExposureControlMode = attrString(ds, tag.ExposureControlMode)
+	m.ExposureStatus = attrString(ds, tag.ExposureStatus)
+	m.SensitivityValue = attrFloat(ds, tag.SensitivityValue)
+	m.Grid = attrString(ds, tag.Grid)
+	m.ImageAndFluoroscopyAreaDoseProduct = attrFloat(ds, tag.ImageAndFluoroscopyAreaDoseProduct)
+	m.BodyPartThickness = attrFloat(ds, tag.BodyPartThickness)
+	m.CompressionForce = attrFloat(ds, tag.CompressionForce)
+}
+
+// FromDataset sets the module from the attributes of ds
+func (m *QRAcquisitionModule) FromDataset(ds Attributes) {
+	m.ResonantNucleus = attrString(ds, tag.ResonantNucleus)
+	m.TransmitterFrequency = attrFloats(ds, tag.TransmitterFrequency)
+	m.SpectralWidth = attrFloat(ds, tag.SpectralWidth)
+	m.AcquisitionDuration = attrFloat(ds, tag.AcquisitionDuration)
+	m.NumberOfAverages = attrInt(ds, tag.NumberOfAverages)
+}
+
+// FromDataset sets the module from the attributes of ds
+func (m *OOIOwnerModule) FromDataset(ds Attributes) {
+	m.OwnerID = attrString(ds, tag.OOIOwnerID)
+	m.OwnerName = attrString(ds, tag.OOIOwnerName)
+	m.OwnerIDType = attrString(ds, tag.OOIOwnerIDType)
+	m.OwnerCategory = attrString(ds, tag.OOIOwnerCategory)
+}
+
+// FromDataset sets the module from the attributes of ds
+func (m *OOIModule) FromDataset(ds Attributes) {
+	m.OOIID = attrString(ds, tag.OOIID)
+	m.OOIType = attrString(ds, tag.OOITypeAttr)
+	m.OOISize = attrString(ds, tag.OOISizeAttr)
+	m.OOILabel = attrString(ds, tag.OOILabel)
+}
+
+// FromDataset sets the module from the attributes of ds
+func (m *ItineraryModule) FromDataset(ds Attributes) {
+	m.FlightNumber = attrString(ds, tag.FlightNumber)
+	m.DepartureAirport = attrString(ds, tag.DepartureAirport)
+	m.ArrivalAirport = attrString(ds, tag.ArrivalAirport)
+	m.CarrierName = attrString(ds, tag.CarrierName)
+	m.CarrierCode = attrString(ds, tag.CarrierCode)
+}
@@ -2,6 +2,8 @@ package module
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
@@ -19,7 +21,12 @@ type Date struct {
 	Day   int
 }
 
+// String formats the date as YYYYMMDD, or empty for the zero Date so a
+// Type 2 date without a value is written empty rather than as 00000000
 func (d Date) String() string {
+	if d == (Date{}) {
+		return ""
+	}
 	return fmt.Sprintf("%04d%02d%02d", d.Year, d.Month, d.Day)
 }
 
@@ -31,6 +38,17 @@ func NewDate(t time.Time) Date {
 	}
 }
 
+// ParseDate parses a DA value, YYYYMMDD. The ACR-NEMA form YYYY.MM.DD is
+// also accepted.
+func ParseDate(s string) (Date, error) {
+	s = strings.ReplaceAll(strings.TrimRight(strings.TrimSpace(s), "\x00"), ".", "")
+	t, err := time.Parse("20060102", s)
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid DA %q", s)
+	}
+	return NewDate(t), nil
+}
+
 // Time represents a DICOS Time (TM VR)
 type Time struct {
 	Hour   int
@@ -53,6 +71,126 @@ func NewTime(t time.Time) Time {
 	}
 }
 
+// ParseTime parses a TM value: HH, HHMM, HHMMSS or HHMMSS.FFFFFF with one
+// to six fractional digits. The ACR-NEMA form HH:MM:SS is also accepted.
+func ParseTime(s string) (Time, error) {
+	s = strings.TrimRight(strings.TrimSpace(s), "\x00")
+	digits, frac, hasFrac := strings.Cut(strings.ReplaceAll(s, ":", ""), ".")
+	var t Time
+	if len(digits) < 2 || len(digits) > 6 || len(digits)%2 != 0 || (hasFrac && len(digits) != 6) {
+		return t, fmt.Errorf("invalid TM %q", s)
+	}
+	fields := []*int{&t.Hour, &t.Minute, &t.Second}
+	for i := 0; i < len(digits); i += 2 {
+		v, err := strconv.Atoi(digits[i : i+2])
+		if err != nil || v < 0 {
+			return Time{}, fmt.Errorf("invalid TM %q", s)
+		}
+		*fields[i/2] = v
+	}
+	if t.Hour > 23 || t.Minute > 59 || t.Second > 60 { // 60 for leap seconds
+		return Time{}, fmt.Errorf("invalid TM %q: out of range", s)
+	}
+	if hasFrac {
+		if len(frac) == 0 || len(frac) > 6 {
+			return Time{}, fmt.Errorf("invalid TM %q: fraction must have 1 to 6 digits", s)
+		}
+		v, err := strconv.Atoi(frac + strings.Repeat("0", 9-len(frac)))
+		if err != nil || v < 0 {
+			return Time{}, fmt.Errorf("invalid TM %q", s)
+		}
+		t.Nano = v
+	}
+	return t, nil
+}
+
+// Duration returns the time since midnight, for comparing acquisition times
+// at sub-second precision
+func (t Time) Duration() time.Duration {
+	return time.Duration(t.Hour)*time.Hour + time.Duration(t.Minute)*time.Minute +
+		time.Duration(t.Second)*time.Second + time.Duration(t.Nano)
+}
+
+// DateTime represents a DICOS Date Time (DT VR), YYYYMMDDHHMMSS.FFFFFF&ZZXX,
+// as used for per-frame acquisition timestamps. HasOffset controls whether
+// the UTC offset suffix is written; values parsed without one are in UTC.
+type DateTime struct {
+	time.Time
+	HasOffset bool
+}
+
+// NewDateTime returns t as a DT value carrying its UTC offset
+func NewDateTime(t time.Time) DateTime {
+	return DateTime{Time: t, HasOffset: true}
+}
+
+func (d DateTime) String() string {
+	s := d.Format("20060102150405.000000")
+	if d.HasOffset {
+		s += d.Format("-0700")
+	}
+	return s
+}
+
+// ParseDateTime parses a DT value. Trailing components may be omitted
+// (YYYY, YYYYMM, ... YYYYMMDDHHMMSS.F), the fraction has one to six digits
+// and the UTC offset suffix &ZZXX is optional.
+func ParseDateTime(s string) (DateTime, error) {
+	s = strings.TrimRight(strings.TrimSpace(s), "\x00")
+	loc := time.UTC
+	d := DateTime{}
+	if i := strings.LastIndexAny(s, "+-"); i >= 4 {
+		offset := s[i:]
+		s = s[:i]
+		if len(offset) != 5 {
+			return d, fmt.Errorf("invalid DT offset %q", offset)
+		}
+		hh, err1 := strconv.Atoi(offset[1:3])
+		mm, err2 := strconv.Atoi(offset[3:5])
+		if err1 != nil || err2 != nil || hh > 14 || mm > 59 {
+			return d, fmt.Errorf("invalid DT offset %q", offset)
+		}
+		secs := hh*3600 + mm*60
+		if offset[0] == '-' {
+			secs = -secs
+		}
+		loc = time.FixedZone("", secs)
+		d.HasOffset = true
+	}
+
+	datePart, timePart := s, ""
+	if len(s) > 8 {
+		datePart, timePart = s[:8], s[8:]
+	}
+	if len(datePart) < 4 || len(datePart)%2 != 0 {
+		return DateTime{}, fmt.Errorf("invalid DT %q", s)
+	}
+	date := []int{0, 1, 1}
+	for i, n := range []int{4, 2, 2} {
+		start := []int{0, 4, 6}[i]
+		if start >= len(datePart) {
+			break
+		}
+		v, err := strconv.Atoi(datePart[start : start+n])
+		if err != nil || v < 0 {
+			return DateTime{}, fmt.Errorf("invalid DT %q", s)
+		}
+		date[i] = v
+	}
+	var tm Time
+	if timePart != "" {
+		var err error
+		if tm, err = ParseTime(timePart); err != nil {
+			return DateTime{}, fmt.Errorf("invalid DT %q: %w", s, err)
+		}
+	}
+	d.Time = time.Date(date[0], time.Month(date[1]), date[2], tm.Hour, tm.Minute, tm.Second, tm.Nano, loc)
+	if d.Month() != time.Month(date[1]) || d.Day() != date[2] {
+		return DateTime{}, fmt.Errorf("invalid DT %q: out of range", s)
+	}
+	return d, nil
+}
+
 // PersonName represents a DICOS Person Name (PN VR)
 type PersonName struct {
 	FamilyName string
@@ -67,6 +205,17 @@ func (p PersonName) String() string {
 	return fmt.Sprintf("%s^%s^%s^%s^%s", p.FamilyName, p.GivenName, p.MiddleName, p.Prefix, p.Suffix)
 }
 
+// ParsePersonName parses the alphabetic group of a PN value,
+// Family^Given^Middle^Prefix^Suffix; missing trailing components are empty
+func ParsePersonName(s string) PersonName {
+	s, _, _ = strings.Cut(strings.TrimRight(strings.TrimSpace(s), "\x00"), "=")
+	parts := strings.SplitN(s, "^", 5)
+	for len(parts) < 5 {
+		parts = append(parts, "")
+	}
+	return PersonName{FamilyName: parts[0], GivenName: parts[1], MiddleName: parts[2], Prefix: parts[3], Suffix: parts[4]}
+}
+
 // IODModule defines the interface for DICOM Information Object Definition (IOD) modules.
 //
 // An IOD module is a collection of related DICOM attributes that describe a specific
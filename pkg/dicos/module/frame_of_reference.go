@@ -14,10 +14,11 @@ type FrameOfReferenceModule struct {
 	PositionReferenceIndicator string // Anatomical reference point (e.g., "VERTEX", "NA")
 }
 
-// NewFrameOfReferenceModule creates a new FrameOfReferenceModule with generated UID
-func NewFrameOfReferenceModule(uidPrefix string) *FrameOfReferenceModule {
+// NewFrameOfReferenceModule creates a new FrameOfReferenceModule for uid,
+// e.g. dicos.NewUID()
+func NewFrameOfReferenceModule(uid string) *FrameOfReferenceModule {
 	return &FrameOfReferenceModule{
-		FrameOfReferenceUID:        generateUID(uidPrefix),
+		FrameOfReferenceUID:        uid,
 		PositionReferenceIndicator: "",
 	}
 }
@@ -90,9 +91,3 @@ func formatDS6(v [6]float64) string {
 	return formatDS(v[0]) + "\\" + formatDS(v[1]) + "\\" + formatDS(v[2]) + "\\" +
 		formatDS(v[3]) + "\\" + formatDS(v[4]) + "\\" + formatDS(v[5])
 }
-
-// Simple UID generation placeholder - actual impl in dicos package
-func generateUID(prefix string) string {
-	// Placeholder - caller should use dicos.GenerateUID
-	return prefix + "1.2.3.4.5"
-}
@@ -0,0 +1,42 @@
+package module
+
+import (
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// QRAcquisitionModule describes a DICOS Quadrupole Resonance measurement.
+// QR excites nuclei such as 14N with radio frequency pulses and listens for
+// the resonances characteristic of explosives and narcotics; its acquisition
+// attributes are those of MR spectroscopy.
+type QRAcquisitionModule struct {
+	ResonantNucleus      string    // e.g. "14N"
+	TransmitterFrequency []float64 // MHz, one per excited line (VM 1-2)
+	SpectralWidth        float64   // Hz
+	AcquisitionDuration  float64   // s
+	NumberOfAverages     int
+}
+
+// NewQRAcquisitionModule returns a module for a 14N measurement
+func NewQRAcquisitionModule() *QRAcquisitionModule {
+	return &QRAcquisitionModule{ResonantNucleus: "14N"}
+}
+
+// ToTags converts the module to DICOM tag elements
+func (m *QRAcquisitionModule) ToTags() []IODElement {
+	elements := []IODElement{
+		{Tag: tag.ResonantNucleus, Value: m.ResonantNucleus},
+	}
+	if len(m.TransmitterFrequency) > 0 {
+		elements = append(elements, IODElement{Tag: tag.TransmitterFrequency, Value: m.TransmitterFrequency})
+	}
+	if m.SpectralWidth != 0 {
+		elements = append(elements, IODElement{Tag: tag.SpectralWidth, Value: m.SpectralWidth})
+	}
+	if m.AcquisitionDuration != 0 {
+		elements = append(elements, IODElement{Tag: tag.AcquisitionDuration, Value: m.AcquisitionDuration})
+	}
+	if m.NumberOfAverages != 0 {
+		elements = append(elements, IODElement{Tag: tag.NumberOfAverages, Value: formatIS(m.NumberOfAverages)})
+	}
+	return elements
+}
@@ -0,0 +1,133 @@
+package dicos
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/module"
+)
+
+// The read*Module functions fill the common modules from a dataset for the
+// IOD parsers (ParseCT, ParseTDR) with the modules' FromDataset. Absent or
+// malformed attributes leave the field zero; the optional OOI modules are nil
+// when none of their attributes are present.
+
+func readPatientModule(ds *Dataset) module.PatientModule {
+	var m module.PatientModule
+	m.FromDataset(ds)
+	return m
+}
+
+func readStudyModule(ds *Dataset) module.GeneralStudyModule {
+	var m module.GeneralStudyModule
+	m.FromDataset(ds)
+	return m
+}
+
+func readSeriesModule(ds *Dataset) module.GeneralSeriesModule {
+	var m module.GeneralSeriesModule
+	m.FromDataset(ds)
+	return m
+}
+
+func readEquipmentModule(ds *Dataset) module.GeneralEquipmentModule {
+	var m module.GeneralEquipmentModule
+	m.FromDataset(ds)
+	return m
+}
+
+func readSOPCommonModule(ds *Dataset) module.SOPCommonModule {
+	var m module.SOPCommonModule
+	m.FromDataset(ds)
+	return m
+}
+
+// readOOIOwnerModule returns the OOI Owner Module, or nil without one
+func readOOIOwnerModule(ds *Dataset) *module.OOIOwnerModule {
+	var m module.OOIOwnerModule
+	if m.FromDataset(ds); m == (module.OOIOwnerModule{}) {
+		return nil
+	}
+	return &m
+}
+
+// readOOIModule returns the OOI Module, or nil without one
+func readOOIModule(ds *Dataset) *module.OOIModule {
+	var m module.OOIModule
+	if m.FromDataset(ds); m == (module.OOIModule{}) {
+		return nil
+	}
+	return &m
+}
+
+// readItineraryModule returns the Itinerary Module, or nil without one
+func readItineraryModule(ds *Dataset) *module.ItineraryModule {
+	var m module.ItineraryModule
+	m.FromDataset(ds)
+	if m.FlightNumber == "" && m.DepartureAirport == "" && m.ArrivalAirport == "" && m.CarrierName == "" && m.CarrierCode == "" {
+		return nil
+	}
+	return &m
+}
+
+// AttributeStrings returns the values of t as text, binary numbers
+// formatted in decimal, or nil when t is absent or empty. It lets the
+// FromDataset methods of the module package read ds.
+func (ds *Dataset) AttributeStrings(t Tag) []string {
+	return attrStrings(ds, t)
+}
+
+// attrString returns the trimmed string value of t in ds, or ""
+func attrString(ds *Dataset, t Tag) string {
+	elem, ok := ds.Elements[t]
+	if !ok {
+		return ""
+	}
+	s, _ := elem.GetString()
+	return strings.TrimRight(strings.TrimSpace(s), "\x00")
+}
+
+// attrStrings returns the backslash separated values of t in ds
+func attrStrings(ds *Dataset, t Tag) []string {
+	elem, ok := ds.Elements[t]
+	if !ok {
+		return nil
+	}
+	strs, _ := elementStrings(elem)
+	return strs
+}
+
+// attrFloats returns the numeric values of t in ds, binary or decimal, or
+// nil if any is not a number
+func attrFloats(ds *Dataset, t Tag) []float64 {
+	strs := attrStrings(ds, t)
+	if len(strs) == 0 {
+		return nil
+	}
+	out := make([]float64, 0, len(strs))
+	for _, s := range strs {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// attrFloat returns the first numeric value of t in ds, or 0
+func attrFloat(ds *Dataset, t Tag) float64 {
+	if v := attrFloats(ds, t); len(v) > 0 {
+		return v[0]
+	}
+	return 0
+}
+
+// attrInt returns the first value of t in ds as an integer, or 0
+func attrInt(ds *Dataset, t Tag) int {
+	if v := attrStrings(ds, t); len(v) > 0 {
+		n, _ := strconv.Atoi(v[0])
+		return n
+	}
+	return 0
+}
@@ -0,0 +1,77 @@
+package dicos
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/module"
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fromDataset is a module that can be read back
+type fromDataset interface {
+	module.IODModule
+	FromDataset(module.Attributes)
+}
+
+func TestModuleFromDataset_RoundTrip(t *testing.T) {
+	patient := &module.PatientModule{PatientID: "BAG-0001", PatientSex: "O", PatientAge: "000Y", PatientComments: "transfer"}
+	patient.SetPatientName("JANE", "DOE", "Q", "", "")
+	patient.PatientBirthDate = module.Date{Year: 1980, Month: 1, Day: 2}
+	ctImage := module.NewCTImageModule()
+	ctImage.KVP, ctImage.ExposureTime, ctImage.RescaleIntercept = 140, 500, -1024
+	ctImage.DateOfLastCalibration = module.Date{Year: 2026, Month: 1, Day: 5}
+	ctImage.TimeOfLastCalibration = module.Time{Hour: 7, Minute: 30}
+	ctImage.SpiralPitchFactor, ctImage.AcquisitionType = 0.75, "SPIRAL"
+	ctImage.WindowCenter, ctImage.WindowWidth = 40, 400
+	detector := module.NewDXDetectorModule()
+	detector.DetectorID, detector.DetectorBinning, detector.FieldOfViewDimensions = "D-7", 2, 430
+	acquisition := module.NewDXAcquisitionModule()
+	acquisition.XRayTubeCurrent, acquisition.ExposureTime, acquisition.Exposure = 2.5, 12.5, 40
+	acquisition.Grid = "NONE"
+
+	for _, in := range []fromDataset{
+		patient,
+		&module.GeneralStudyModule{StudyInstanceUID: "1.2.3", StudyDate: module.Date{Year: 2026, Month: 3, Day: 14}, StudyTime: module.Time{Hour: 9, Minute: 26, Second: 53, Nano: 589000}, StudyID: "7", AccessionNumber: "A1", StudyDescription: "Checked"},
+		&module.GeneralSeriesModule{Modality: "CT", SeriesInstanceUID: "1.2.3.4", SeriesNumber: 2, SeriesDescription: "High"},
+		&module.GeneralEquipmentModule{Manufacturer: "ACME", InstitutionName: "IAD", StationName: "L3", ManufacturerModel: "X1", DeviceSerial: "42", SoftwareVersions: "1.0"},
+		&module.SOPCommonModule{SOPClassUID: CTImageStorageUID, SOPInstanceUID: "1.2.3.4.5", SpecificCharacterSet: "ISO_IR 100"},
+		&module.FrameOfReferenceModule{FrameOfReferenceUID: "1.2.3.9", PositionReferenceIndicator: "NA"},
+		&module.ImagePlaneModule{PixelSpacing: [2]float64{0.5, 0.75}, ImageOrientationPatient: [6]float64{1, 0, 0, 0, 1, 0}, ImagePositionPatient: [3]float64{-10, 20, 30.5}, SliceThickness: 1.25},
+		ctImage,
+		module.NewVOILUTModuleForCT(),
+		detector,
+		acquisition,
+		&module.QRAcquisitionModule{ResonantNucleus: "14N", TransmitterFrequency: []float64{3.41, 3.6}, SpectralWidth: 2000, NumberOfAverages: 64},
+		&module.OOIModule{OOIID: "BAG-0001", OOIType: "BAG", OOISize: "CABIN", OOILabel: "0016"},
+		&module.OOIOwnerModule{OwnerID: "P1", OwnerName: "DOE^JANE", OwnerIDType: "PASSPORT", OwnerCategory: "PASSENGER"},
+		&module.ItineraryModule{FlightNumber: "UA1", DepartureAirport: "IAD", ArrivalAirport: "SFO", CarrierName: "United", CarrierCode: "UA"},
+	} {
+		name := reflect.TypeOf(in).Elem().Name()
+		t.Run(name, func(t *testing.T) {
+			ds, err := NewDataset(WithModule(in.ToTags()))
+			require.NoError(t, err)
+			got := reflect.New(reflect.TypeOf(in).Elem()).Interface().(fromDataset)
+			got.FromDataset(rewrite(t, ds))
+			assert.Equal(t, in, got)
+		})
+	}
+}
+
+func TestModuleFromDataset_KeepsUnwrittenFields(t *testing.T) {
+	ds, err := NewDataset(WithElement(tag.PatientID, "BAG-0002"))
+	require.NoError(t, err)
+	m := module.PatientModule{PatientID: "BAG-0001", PatientSex: "F", Magistrate: "kept"}
+	m.FromDataset(ds)
+	assert.Equal(t, module.PatientModule{PatientID: "BAG-0002", Magistrate: "kept"}, m)
+
+	voi := module.VOILUTModule{LUTs: []module.VOILUT{{Explanation: "kept"}}}
+	voi.FromDataset(ds)
+	assert.Equal(t, "LINEAR", voi.VOILUTFunction)
+	assert.Empty(t, voi.Windows)
+	assert.Len(t, voi.LUTs, 1)
+	assert.Nil(t, ds.AttributeStrings(tag.PatientName))
+	assert.Equal(t, []string{"BAG-0002"}, ds.AttributeStrings(tag.PatientID))
+}
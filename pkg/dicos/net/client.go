@@ -0,0 +1,305 @@
+package net
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	stdnet "net"
+	"sync"
+	"time"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+)
+
+// ClientOptions configures an association requested by Dial
+type ClientOptions struct {
+	CallingAE        string        // our AE title, "DICOS_SCU" when empty
+	CalledAE         string        // the peer's AE title, "ANY-SCP" when empty
+	SOPClasses       []string      // storage SOP classes to propose, DefaultSOPClasses when nil
+	TransferSyntaxes []string      // transfer syntaxes to propose, DefaultTransferSyntaxes when nil
+	QuerySOPClasses  []string      // query/retrieve SOP classes to propose, DefaultQuerySOPClasses when nil
+	MaxPDULength     uint32        // largest PDU we accept, DefaultMaxPDULength when zero
+	Timeout          time.Duration // limit per PDU, zero waits forever
+	TLSConfig        *tls.Config   // connects with TLS when set, see TLSOptions
+}
+
+// Association is an established association with an SCP. Its methods may be
+// called from several goroutines; requests are sent one at a time.
+type Association struct {
+	c        *conn
+	contexts []PresentationContext
+
+	mu        sync.Mutex
+	messageID uint16
+}
+
+// Dial connects to addr and requests an association proposing Verification
+// and every SOP class with each transfer syntax, one presentation context
+// per pair, so datasets can be sent in their own transfer syntax, and the
+// query/retrieve SOP classes.
+//
+// Example:
+//
+//	assoc, err := net.Dial(ctx, "scp:11112", net.ClientOptions{CalledAE: "DICOS_SCP"})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer assoc.Release()
+//	err = assoc.Store(ctx, ds)
+func Dial(ctx context.Context, addr string, opts ClientOptions) (*Association, error) {
+	var nc stdnet.Conn
+	var err error
+	if opts.TLSConfig != nil {
+		d := tls.Dialer{Config: opts.TLSConfig}
+		if nc, err = d.DialContext(ctx, "tcp", addr); err != nil {
+			return nil, err
+		}
+		logTLS(slog.With(slog.String("remote", addr)), nc.(*tls.Conn).ConnectionState())
+	} else {
+		var d stdnet.Dialer
+		if nc, err = d.DialContext(ctx, "tcp", addr); err != nil {
+			return nil, err
+		}
+	}
+	a, err := associateOn(ctx, nc, opts)
+	if err != nil {
+		nc.Close()
+		return nil, err
+	}
+	return a, nil
+}
+
+// associateOn requests an association over an open connection
+func associateOn(ctx context.Context, nc stdnet.Conn, opts ClientOptions) (*Association, error) {
+	if opts.CallingAE == "" {
+		opts.CallingAE = "DICOS_SCU"
+	}
+	if opts.CalledAE == "" {
+		opts.CalledAE = "ANY-SCP"
+	}
+	if opts.SOPClasses == nil {
+		opts.SOPClasses = DefaultSOPClasses
+	}
+	if opts.TransferSyntaxes == nil {
+		opts.TransferSyntaxes = DefaultTransferSyntaxes
+	}
+	if opts.QuerySOPClasses == nil {
+		opts.QuerySOPClasses = DefaultQuerySOPClasses
+	}
+	if opts.MaxPDULength == 0 {
+		opts.MaxPDULength = DefaultMaxPDULength
+	}
+
+	rq := &associate{
+		CalledAE:               opts.CalledAE,
+		CallingAE:              opts.CallingAE,
+		MaxPDULength:           opts.MaxPDULength,
+		ImplementationClassUID: ImplementationClassUID,
+		ImplementationVersion:  ImplementationVersionName,
+	}
+	propose := func(abstract string, syntaxes []string) error {
+		if len(rq.Contexts) == 128 {
+			return fmt.Errorf("dicos/net: more than 128 presentation contexts")
+		}
+		id := byte(2*len(rq.Contexts) + 1) // context IDs are odd
+		rq.Contexts = append(rq.Contexts, PresentationContext{ID: id, AbstractSyntax: abstract, TransferSyntaxes: syntaxes})
+		return nil
+	}
+	if err := propose(VerificationSOPClass, []string{string(dicos.ImplicitVRLittleEndian)}); err != nil {
+		return nil, err
+	}
+	for _, class := range opts.SOPClasses {
+		for _, ts := range opts.TransferSyntaxes {
+			if err := propose(class, []string{ts}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	// Identifiers are written in Explicit VR Little Endian only
+	for _, class := range opts.QuerySOPClasses {
+		if err := propose(class, []string{string(dicos.ExplicitVRLittleEndian)}); err != nil {
+			return nil, err
+		}
+	}
+
+	c := newConn(nc, opts.Timeout)
+	if deadline, ok := ctx.Deadline(); ok {
+		nc.SetDeadline(deadline)
+		defer nc.SetDeadline(time.Time{})
+	}
+	if err := c.writePDU(pduAssociateRQ, rq.encode(false)); err != nil {
+		return nil, err
+	}
+	typ, body, err := c.readPDU()
+	if err != nil {
+		return nil, err
+	}
+	switch typ {
+	case pduAssociateAC:
+	case pduAssociateRJ:
+		if len(body) < 4 {
+			return nil, &RejectError{}
+		}
+		return nil, &RejectError{Result: body[1], Source: body[2], Reason: body[3]}
+	case pduAbort:
+		if len(body) < 4 {
+			return nil, &AbortError{}
+		}
+		return nil, &AbortError{Source: body[2], Reason: body[3]}
+	default:
+		c.abort(2)
+		return nil, fmt.Errorf("dicos/net: unexpected PDU type %d in answer to A-ASSOCIATE-RQ", typ)
+	}
+	ac, err := decodeAssociate(body)
+	if err != nil {
+		c.abort(6)
+		return nil, err
+	}
+	// The answer names contexts by ID only
+	for i, pc := range ac.Contexts {
+		for _, p := range rq.Contexts {
+			if p.ID == pc.ID {
+				ac.Contexts[i].AbstractSyntax = p.AbstractSyntax
+			}
+		}
+	}
+	c.maxPDU = ac.MaxPDULength
+	return &Association{c: c, contexts: ac.Contexts}, nil
+}
+
+// TLS returns the state of the TLS connection, ok is false for an
+// association over plain TCP
+func (a *Association) TLS() (state tls.ConnectionState, ok bool) {
+	if tc, ok := a.c.c.(*tls.Conn); ok {
+		return tc.ConnectionState(), true
+	}
+	return tls.ConnectionState{}, false
+}
+
+// Contexts returns the presentation contexts the SCP answered
+func (a *Association) Contexts() []PresentationContext {
+	return append([]PresentationContext(nil), a.contexts...)
+}
+
+// Echo sends a C-ECHO to verify the association end to end
+func (a *Association) Echo(ctx context.Context) error {
+	pc, ok := acceptedContext(a.contexts, VerificationSOPClass, string(dicos.ImplicitVRLittleEndian))
+	if !ok {
+		return fmt.Errorf("dicos/net: Verification was not accepted")
+	}
+	_, err := a.request(ctx, pc.ID, &Command{Field: CEchoRQ, AffectedSOPClassUID: VerificationSOPClass}, nil)
+	return err
+}
+
+// Store sends ds with C-STORE on a presentation context for its SOP class and
+// transfer syntax. A failure status is returned as a *StatusError.
+func (a *Association) Store(ctx context.Context, ds *dicos.Dataset) error {
+	sopClass, sopInstance, ts, body, err := encodeDataset(ds)
+	if err != nil {
+		return err
+	}
+	pc, ok := acceptedContext(a.contexts, sopClass, ts)
+	if !ok {
+		return fmt.Errorf("dicos/net: no accepted presentation context for %s in %s", sopClass, ts)
+	}
+	_, err = a.request(ctx, pc.ID, &Command{
+		Field:                  CStoreRQ,
+		AffectedSOPClassUID:    sopClass,
+		AffectedSOPInstanceUID: sopInstance,
+	}, body)
+	return err
+}
+
+// request sends one command and waits for its response
+func (a *Association) request(ctx context.Context, contextID byte, cmd *Command, data []byte) (*Command, error) {
+	return a.exchange(ctx, contextID, cmd, data, nil)
+}
+
+// exchange sends one command and reads its responses, passing each pending
+// one to onPending, until the final response
+func (a *Association) exchange(ctx context.Context, contextID byte, cmd *Command, data []byte, onPending func(*message) error) (*Command, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	// Cancelling ctx interrupts a blocked read or write
+	stop := context.AfterFunc(ctx, func() { a.c.c.SetDeadline(time.Now()) })
+	defer func() {
+		if stop() {
+			return
+		}
+		a.c.c.SetDeadline(time.Time{})
+	}()
+
+	a.messageID++
+	cmd.MessageID = a.messageID
+	if err := a.c.writeMessage(contextID, cmd, data); err != nil {
+		return nil, contextError(ctx, err)
+	}
+	for {
+		m, err := a.c.readMessage()
+		if err != nil {
+			return nil, contextError(ctx, err)
+		}
+		rsp := m.command
+		if rsp.MessageIDBeingRespondedTo != cmd.MessageID || rsp.Field != cmd.Field|0x8000 {
+			a.c.abort(6)
+			return nil, fmt.Errorf("dicos/net: response 0x%04X to message %d, want 0x%04X to %d",
+				rsp.Field, rsp.MessageIDBeingRespondedTo, cmd.Field|0x8000, cmd.MessageID)
+		}
+		if onPending != nil && pending(rsp.Status) {
+			if err := onPending(m); err != nil {
+				a.c.abort(0)
+				return nil, err
+			}
+			continue
+		}
+		if !succeeded(rsp.Status) {
+			return rsp, &StatusError{Field: rsp.Field, Status: rsp.Status}
+		}
+		return rsp, nil
+	}
+}
+
+// contextError prefers the context error over the deadline it caused
+func contextError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
+// Release asks the SCP to end the association and closes the connection
+func (a *Association) Release() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	defer a.c.Close()
+	if err := a.c.writePDU(pduReleaseRQ, make([]byte, 4)); err != nil {
+		return err
+	}
+	for {
+		typ, _, err := a.c.readPDU()
+		if err != nil {
+			return err
+		}
+		switch typ {
+		case pduReleaseRP:
+			return nil
+		case pduAbort:
+			return &AbortError{}
+		case pduDataTF:
+			continue // a late response
+		default:
+			return fmt.Errorf("dicos/net: unexpected PDU type %d in answer to A-RELEASE-RQ", typ)
+		}
+	}
+}
+
+// Abort ends the association without waiting for the SCP
+func (a *Association) Abort() error {
+	err := a.c.writePDU(pduAbort, []byte{0, 0, 0, 0})
+	return errors.Join(err, a.c.Close())
+}
@@ -0,0 +1,115 @@
+package net
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// DefaultSOPClasses are the storage SOP classes of the DICOS IODs and of the
+// reports stored with them, accepted by a Server and proposed by Dial unless
+// configured otherwise
+var DefaultSOPClasses = []string{
+	dicos.DICOSCTImageStorageUID,
+	dicos.DICOSDXImageStorageUID,
+	dicos.DICOSDXForPresentationUID,
+	dicos.DICOSTDRStorageUID,
+	dicos.DICOSAIT2DImageStorageUID,
+	dicos.DICOSAIT3DImageStorageUID,
+	dicos.DICOSQRStorageUID,
+	dicos.EncapsulatedPDFStorageUID,
+}
+
+// DefaultTransferSyntaxes are the transfer syntaxes the reader can decode, in
+// order of preference
+var DefaultTransferSyntaxes = []string{
+	string(dicos.ExplicitVRLittleEndian),
+	string(dicos.ImplicitVRLittleEndian),
+	string(dicos.JPEGLSLossless),
+	string(dicos.JPEGLosslessFirstOrder),
+	"1.2.840.10008.1.2.4.90", // JPEG 2000 Lossless
+	"1.2.840.10008.1.2.5",    // RLE Lossless
+}
+
+// parseDataset reads a dataset received as P-DATA. The network carries no
+// File Meta Information, so it is rebuilt from the command and the transfer
+// syntax of the presentation context; the result can be written to a file
+// as is.
+func parseDataset(ctx context.Context, cmd *Command, ts string, body []byte) (*dicos.Dataset, error) {
+	meta, err := dicos.NewDataset(dicos.WithFileMeta(cmd.AffectedSOPClassUID, cmd.AffectedSOPInstanceUID, ts))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if _, err := dicos.Write(&buf, meta); err != nil {
+		return nil, err
+	}
+	buf.Write(body)
+	return dicos.ReadBufferContext(ctx, buf.Bytes())
+}
+
+// encodeDataset returns ds as sent in P-DATA, without the preamble and File
+// Meta Information, with its SOP class and instance and transfer syntax.
+// The writer uses Explicit VR Little Endian unless the dataset is Implicit VR
+// Little Endian, so other native transfer syntaxes are sent as explicit VR.
+func encodeDataset(ds *dicos.Dataset) (sopClass, sopInstance, ts string, body []byte, err error) {
+	sopClass, sopInstance = uid(ds, tag.SOPClassUID), uid(ds, tag.SOPInstanceUID)
+	if sopClass == "" || sopInstance == "" {
+		return "", "", "", nil, fmt.Errorf("dicos/net: dataset has no SOP Class or SOP Instance UID")
+	}
+	ts = string(dicos.ExplicitVRLittleEndian)
+	if syntax := ds.TransferSyntax(); syntax.IsEncapsulated() || syntax == dicos.ImplicitVRLittleEndian {
+		ts = string(syntax)
+	}
+
+	var buf bytes.Buffer
+	if _, err := dicos.Write(&buf, ds); err != nil {
+		return "", "", "", nil, err
+	}
+	body, err = stripFileMeta(buf.Bytes())
+	return sopClass, sopInstance, ts, body, err
+}
+
+// uid returns a string element of ds
+func uid(ds *dicos.Dataset, t tag.Tag) string {
+	if elem, ok := ds.FindElement(t.Group, t.Element); ok {
+		if s, ok := elem.GetString(); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+// stripFileMeta skips the preamble and the group 0002 elements of a Part 10
+// stream, which are always Explicit VR Little Endian
+func stripFileMeta(b []byte) ([]byte, error) {
+	if len(b) < 132 || string(b[128:132]) != "DICM" {
+		return nil, fmt.Errorf("dicos/net: missing DICM prefix")
+	}
+	b = b[132:]
+	for len(b) >= 8 && binary.LittleEndian.Uint16(b) == 0x0002 {
+		vr := string(b[4:6])
+		switch vr {
+		case "OB", "OD", "OF", "OL", "OV", "OW", "SQ", "SV", "UC", "UN", "UR", "UT", "UV":
+			if len(b) < 12 {
+				return nil, fmt.Errorf("dicos/net: truncated file meta")
+			}
+			n := int(binary.LittleEndian.Uint32(b[8:]))
+			if len(b) < 12+n {
+				return nil, fmt.Errorf("dicos/net: truncated file meta")
+			}
+			b = b[12+n:]
+		default:
+			n := int(binary.LittleEndian.Uint16(b[6:]))
+			if len(b) < 8+n {
+				return nil, fmt.Errorf("dicos/net: truncated file meta")
+			}
+			b = b[8+n:]
+		}
+	}
+	return b, nil
+}
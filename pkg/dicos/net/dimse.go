@@ -0,0 +1,366 @@
+package net
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"fmt"
+	stdnet "net"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Command fields (PS3.7 E.1)
+const (
+	CStoreRQ  uint16 = 0x0001
+	CStoreRSP uint16 = 0x8001
+	CFindRQ   uint16 = 0x0020
+	CFindRSP  uint16 = 0x8020
+	CMoveRQ   uint16 = 0x0021
+	CMoveRSP  uint16 = 0x8021
+	CEchoRQ   uint16 = 0x0030
+	CEchoRSP  uint16 = 0x8030
+)
+
+// Status codes (PS3.7 C)
+const (
+	StatusSuccess              uint16 = 0x0000
+	StatusProcessingFailure    uint16 = 0x0110
+	StatusSOPClassNotSupported uint16 = 0x0122
+	StatusOutOfResources       uint16 = 0xA700
+	StatusCannotUnderstand     uint16 = 0xC000
+	StatusCancel               uint16 = 0xFE00
+	StatusPending              uint16 = 0xFF00
+	StatusPendingWarning       uint16 = 0xFF01 // pending, some optional keys not supported
+)
+
+// VerificationSOPClass is the abstract syntax of C-ECHO
+const VerificationSOPClass = "1.2.840.10008.1.1"
+
+// noDataset is the CommandDataSetType of a command without a dataset
+const noDataset = 0x0101
+
+// Command is a DIMSE command set. Only the elements the supported services
+// use are kept.
+type Command struct {
+	Field                     uint16 // CommandField (0000,0100)
+	MessageID                 uint16
+	MessageIDBeingRespondedTo uint16
+	AffectedSOPClassUID       string
+	AffectedSOPInstanceUID    string
+	Priority                  uint16
+	MoveDestination           string // C-MOVE: AE title that receives the instances
+	HasDataset                bool
+	Status                    uint16
+	// Sub-operation counts of a C-MOVE response
+	Remaining, Completed, Failed, Warning uint16
+}
+
+// StatusError reports a DIMSE response with a failure status
+type StatusError struct {
+	Field  uint16 // response command field, e.g. CStoreRSP
+	Status uint16
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("dicos/net: command 0x%04X failed with status 0x%04X", e.Field, e.Status)
+}
+
+// succeeded returns true for success and warning statuses
+func succeeded(status uint16) bool {
+	return status == StatusSuccess || status&0xF000 == 0xB000
+}
+
+// pending returns true for the statuses of a response that more follow
+func pending(status uint16) bool {
+	return status == StatusPending || status == StatusPendingWarning
+}
+
+// encode returns the command set, always Implicit VR Little Endian
+func (c *Command) encode() []byte {
+	elems := map[uint16][]byte{
+		0x0100: binary.LittleEndian.AppendUint16(nil, c.Field),
+		0x0800: binary.LittleEndian.AppendUint16(nil, noDataset),
+	}
+	if c.HasDataset {
+		elems[0x0800] = binary.LittleEndian.AppendUint16(nil, 0x0000)
+	}
+	if c.AffectedSOPClassUID != "" {
+		elems[0x0002] = uidValue(c.AffectedSOPClassUID)
+	}
+	if c.Field&0x8000 == 0 {
+		elems[0x0110] = binary.LittleEndian.AppendUint16(nil, c.MessageID)
+	} else {
+		elems[0x0120] = binary.LittleEndian.AppendUint16(nil, c.MessageIDBeingRespondedTo)
+		elems[0x0900] = binary.LittleEndian.AppendUint16(nil, c.Status)
+	}
+	switch c.Field {
+	case CStoreRQ, CFindRQ, CMoveRQ:
+		elems[0x0700] = binary.LittleEndian.AppendUint16(nil, c.Priority)
+	case CMoveRSP:
+		if pending(c.Status) {
+			elems[0x1020] = binary.LittleEndian.AppendUint16(nil, c.Remaining)
+		}
+		elems[0x1021] = binary.LittleEndian.AppendUint16(nil, c.Completed)
+		elems[0x1022] = binary.LittleEndian.AppendUint16(nil, c.Failed)
+		elems[0x1023] = binary.LittleEndian.AppendUint16(nil, c.Warning)
+	}
+	if c.MoveDestination != "" {
+		b := []byte(c.MoveDestination)
+		if len(b)%2 != 0 {
+			b = append(b, ' ')
+		}
+		elems[0x0600] = b
+	}
+	if c.AffectedSOPInstanceUID != "" {
+		elems[0x1000] = uidValue(c.AffectedSOPInstanceUID)
+	}
+
+	keys := make([]int, 0, len(elems))
+	for k := range elems {
+		keys = append(keys, int(k))
+	}
+	sort.Ints(keys)
+	var body []byte
+	for _, k := range keys {
+		body = appendImplicit(body, uint16(k), elems[uint16(k)])
+	}
+	group := appendImplicit(nil, 0x0000, binary.LittleEndian.AppendUint32(nil, uint32(len(body))))
+	return append(group, body...)
+}
+
+// appendImplicit appends one group 0000 element in Implicit VR Little Endian
+func appendImplicit(b []byte, element uint16, value []byte) []byte {
+	b = binary.LittleEndian.AppendUint16(b, 0x0000)
+	b = binary.LittleEndian.AppendUint16(b, element)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(value)))
+	return append(b, value...)
+}
+
+// uidValue pads a UI value to an even length with NUL
+func uidValue(s string) []byte {
+	b := []byte(s)
+	if len(b)%2 != 0 {
+		b = append(b, 0)
+	}
+	return b
+}
+
+// decodeCommand parses a command set
+func decodeCommand(b []byte) (*Command, error) {
+	c := &Command{}
+	for len(b) > 0 {
+		if len(b) < 8 {
+			return nil, fmt.Errorf("dicos/net: truncated command element")
+		}
+		group, element := binary.LittleEndian.Uint16(b), binary.LittleEndian.Uint16(b[2:])
+		n := int(binary.LittleEndian.Uint32(b[4:]))
+		if len(b) < 8+n {
+			return nil, fmt.Errorf("dicos/net: command element (%04X,%04X) of %d bytes is truncated", group, element, n)
+		}
+		v := b[8 : 8+n]
+		b = b[8+n:]
+		if group != 0x0000 {
+			return nil, fmt.Errorf("dicos/net: element (%04X,%04X) in a command set", group, element)
+		}
+		u16 := func() uint16 {
+			if len(v) < 2 {
+				return 0
+			}
+			return binary.LittleEndian.Uint16(v)
+		}
+		switch element {
+		case 0x0002:
+			c.AffectedSOPClassUID = trimUID(v)
+		case 0x0100:
+			c.Field = u16()
+		case 0x0110:
+			c.MessageID = u16()
+		case 0x0120:
+			c.MessageIDBeingRespondedTo = u16()
+		case 0x0600:
+			c.MoveDestination = strings.TrimSpace(string(v))
+		case 0x0700:
+			c.Priority = u16()
+		case 0x0800:
+			c.HasDataset = u16() != noDataset
+		case 0x0900:
+			c.Status = u16()
+		case 0x1000:
+			c.AffectedSOPInstanceUID = trimUID(v)
+		case 0x1020:
+			c.Remaining = u16()
+		case 0x1021:
+			c.Completed = u16()
+		case 0x1022:
+			c.Failed = u16()
+		case 0x1023:
+			c.Warning = u16()
+		}
+	}
+	return c, nil
+}
+
+// message is a command with its dataset, if any, in the transfer syntax of
+// its presentation context
+type message struct {
+	contextID byte
+	command   *Command
+	data      []byte
+}
+
+// conn carries DIMSE messages over an established association
+type conn struct {
+	c       stdnet.Conn
+	r       *bufio.Reader
+	timeout time.Duration // per PDU read and write, zero waits forever
+	maxPDU  uint32        // largest PDU the peer accepts, zero for no limit
+}
+
+func newConn(c stdnet.Conn, timeout time.Duration) *conn {
+	return &conn{c: c, r: bufio.NewReader(c), timeout: timeout}
+}
+
+// peerCertificates returns the verified certificate chain of a TLS peer
+func (c *conn) peerCertificates() []*x509.Certificate {
+	if tc, ok := c.c.(*tls.Conn); ok {
+		if chains := tc.ConnectionState().VerifiedChains; len(chains) > 0 {
+			return chains[0]
+		}
+	}
+	return nil
+}
+
+// readPDU reads the next PDU within the timeout
+func (c *conn) readPDU() (byte, []byte, error) {
+	if c.timeout > 0 {
+		c.c.SetReadDeadline(time.Now().Add(c.timeout))
+	}
+	return readPDU(c.r)
+}
+
+// writePDU writes one PDU within the timeout
+func (c *conn) writePDU(typ byte, body []byte) error {
+	if c.timeout > 0 {
+		c.c.SetWriteDeadline(time.Now().Add(c.timeout))
+	}
+	return writePDU(c.c, typ, body)
+}
+
+// abort sends an A-ABORT from the service provider
+func (c *conn) abort(reason byte) error {
+	return c.writePDU(pduAbort, []byte{0, 0, 2, reason})
+}
+
+// readMessage reads the next command and its dataset. It returns errReleased
+// after answering an A-RELEASE-RQ and an *AbortError on an A-ABORT.
+func (c *conn) readMessage() (*message, error) {
+	var m *message
+	var cmd, data []byte
+	for {
+		typ, body, err := c.readPDU()
+		if err != nil {
+			return nil, err
+		}
+		switch typ {
+		case pduDataTF:
+		case pduReleaseRQ:
+			if err := c.writePDU(pduReleaseRP, make([]byte, 4)); err != nil {
+				return nil, err
+			}
+			return nil, errReleased
+		case pduAbort:
+			if len(body) < 4 {
+				return nil, &AbortError{}
+			}
+			return nil, &AbortError{Source: body[2], Reason: body[3]}
+		default:
+			c.abort(2) // unexpected PDU
+			return nil, fmt.Errorf("dicos/net: unexpected PDU type %d", typ)
+		}
+
+		pdvs, err := decodePDVs(body)
+		if err != nil {
+			c.abort(6) // invalid PDU parameter
+			return nil, err
+		}
+		for _, p := range pdvs {
+			switch {
+			case m == nil && p.command:
+				cmd = append(cmd, p.data...)
+				if !p.last {
+					continue
+				}
+				command, err := decodeCommand(cmd)
+				if err != nil {
+					c.abort(6)
+					return nil, err
+				}
+				m = &message{contextID: p.contextID, command: command}
+				if !command.HasDataset {
+					return m, nil
+				}
+			case m != nil && !p.command && p.contextID == m.contextID:
+				data = append(data, p.data...)
+				if p.last {
+					m.data = data
+					return m, nil
+				}
+			default:
+				c.abort(6)
+				return nil, fmt.Errorf("dicos/net: unexpected PDV on presentation context %d", p.contextID)
+			}
+		}
+	}
+}
+
+// writeMessage sends a command and its dataset, split into PDUs the peer accepts
+func (c *conn) writeMessage(contextID byte, cmd *Command, data []byte) error {
+	cmd.HasDataset = data != nil
+	if err := c.writeFragments(contextID, true, cmd.encode()); err != nil {
+		return err
+	}
+	if data == nil {
+		return nil
+	}
+	return c.writeFragments(contextID, false, data)
+}
+
+// writeFragments sends b as PDVs of at most the peer's maximum PDU length
+func (c *conn) writeFragments(contextID byte, command bool, b []byte) error {
+	size := DefaultMaxPDULength
+	if c.maxPDU > 6 {
+		size = int(c.maxPDU)
+	}
+	size -= 6 // PDV length, context ID and message control header
+	for {
+		n := min(len(b), size)
+		last := n == len(b)
+		if err := c.writePDU(pduDataTF, encodePDV(pdv{contextID: contextID, command: command, last: last, data: b[:n]})); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+		b = b[n:]
+	}
+}
+
+// Close closes the network connection
+func (c *conn) Close() error {
+	return c.c.Close()
+}
+
+// acceptedContext returns the accepted presentation context with the given
+// abstract syntax and transfer syntax
+func acceptedContext(contexts []PresentationContext, abstract, ts string) (PresentationContext, bool) {
+	for _, pc := range contexts {
+		if pc.Result == ResultAcceptance && pc.AbstractSyntax == abstract &&
+			len(pc.TransferSyntaxes) > 0 && pc.TransferSyntaxes[0] == ts {
+			return pc, true
+		}
+	}
+	return PresentationContext{}, false
+}
@@ -0,0 +1,66 @@
+package net_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	stdnet "net"
+	"time"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+	"github.com/jpfielding/dicos.go/pkg/dicos/net"
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// Send a scan to a C-STORE SCP, here one running in the same process
+func Example_store() {
+	received := make(chan *net.StoreRequest, 1)
+	srv := &net.Server{AETitle: "DICOS_SCP", Handler: func(ctx context.Context, req *net.StoreRequest) error {
+		received <- req
+		return nil
+	}}
+	l, err := stdnet.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		log.Fatal(err)
+	}
+	go srv.Serve(l)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(ctx)
+	}()
+
+	ct := dicos.NewCTImage()
+	ct.SOPCommon.SOPClassUID = dicos.DICOSCTImageStorageUID
+	ct.Patient.PatientID = "BAG-0001"
+	ct.Rows, ct.Columns = 8, 8
+	ct.SetPixelData(8, 8, make([]uint16, 64))
+	ct.Codec = dicos.CodecJPEGLS
+	ds, err := ct.GetDataset()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx := context.Background()
+	a, err := net.Dial(ctx, l.Addr().String(), net.ClientOptions{CalledAE: "DICOS_SCP"})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer a.Release()
+	if err := a.Echo(ctx); err != nil {
+		log.Fatal(err)
+	}
+	if err := a.Store(ctx, ds); err != nil {
+		log.Fatal(err)
+	}
+
+	req := <-received
+	patientID, _ := req.Dataset.Elements[tag.PatientID].GetString()
+	fmt.Println("from:", req.CallingAE)
+	fmt.Println("patient:", patientID)
+	fmt.Println("transfer syntax:", req.Dataset.TransferSyntax().Name())
+	// Output:
+	// from: DICOS_SCU
+	// patient: BAG-0001
+	// transfer syntax: JPEG-LS Lossless
+}
@@ -0,0 +1,174 @@
+package net
+
+import (
+	"context"
+	"errors"
+	stdnet "net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startServer serves srv on a loopback port until the test ends
+func startServer(t *testing.T, srv *Server) string {
+	t.Helper()
+	l, err := stdnet.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(l) }()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		assert.NoError(t, srv.Shutdown(ctx))
+		assert.ErrorIs(t, <-served, ErrServerClosed)
+	})
+	return l.Addr().String()
+}
+
+func testCT(t *testing.T, codec dicos.Codec) *dicos.Dataset {
+	t.Helper()
+	ct := dicos.NewCTImage()
+	ct.SOPCommon.SOPClassUID = dicos.DICOSCTImageStorageUID
+	ct.Patient.PatientID = "BAG-0001"
+	ct.Codec = codec
+	rows, cols, frames := 32, 32, 4
+	data := make([]uint16, rows*cols*frames)
+	for i := range data {
+		data[i] = uint16(i)
+	}
+	ct.Rows, ct.Columns = rows, cols
+	ct.SetPixelData(rows, cols, data)
+	ds, err := ct.GetDataset()
+	require.NoError(t, err)
+	return ds
+}
+
+func TestServer_Store(t *testing.T) {
+	var mu sync.Mutex
+	var got []*StoreRequest
+	addr := startServer(t, &Server{
+		AETitle:      "DICOS_SCP",
+		MaxPDULength: 4096, // datasets span several PDUs
+		Handler: func(ctx context.Context, req *StoreRequest) error {
+			mu.Lock()
+			defer mu.Unlock()
+			got = append(got, req)
+			return nil
+		},
+	})
+
+	ctx := context.Background()
+	assoc, err := Dial(ctx, addr, ClientOptions{CallingAE: "SCANNER", CalledAE: "DICOS_SCP"})
+	require.NoError(t, err)
+	require.NoError(t, assoc.Echo(ctx))
+
+	sent := []*dicos.Dataset{testCT(t, nil), testCT(t, dicos.CodecJPEGLS)}
+	for _, ds := range sent {
+		require.NoError(t, assoc.Store(ctx, ds))
+	}
+	require.NoError(t, assoc.Release())
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, got, len(sent))
+	for i, req := range got {
+		assert.Equal(t, "SCANNER", req.CallingAE)
+		assert.Equal(t, "DICOS_SCP", req.CalledAE)
+		assert.Equal(t, dicos.DICOSCTImageStorageUID, req.SOPClassUID)
+		assert.Equal(t, uid(sent[i], tag.SOPInstanceUID), req.SOPInstanceUID)
+		assert.Equal(t, sent[i].TransferSyntax(), req.Dataset.TransferSyntax())
+		assert.Equal(t, req.SOPInstanceUID, uid(req.Dataset, tag.MediaStorageSOPInstanceUID))
+		assert.Equal(t, "BAG-0001", uid(req.Dataset, tag.PatientID))
+
+		want, err := dicos.DecodeVolume(sent[i])
+		require.NoError(t, err)
+		vol, err := dicos.DecodeVolume(req.Dataset)
+		require.NoError(t, err)
+		assert.Equal(t, want.Data, vol.Data)
+	}
+}
+
+func TestServer_HandlerStatus(t *testing.T) {
+	addr := startServer(t, &Server{
+		Handler: func(ctx context.Context, req *StoreRequest) error {
+			if uid(req.Dataset, tag.PatientID) == "FULL" {
+				return &StatusError{Status: StatusOutOfResources}
+			}
+			return errors.New("disk on fire")
+		},
+	})
+
+	ctx := context.Background()
+	assoc, err := Dial(ctx, addr, ClientOptions{})
+	require.NoError(t, err)
+	defer assoc.Release()
+
+	var se *StatusError
+	require.ErrorAs(t, assoc.Store(ctx, testCT(t, nil)), &se)
+	assert.Equal(t, StatusProcessingFailure, se.Status)
+	assert.Equal(t, CStoreRSP, se.Field)
+
+	ds := testCT(t, nil)
+	ds.Elements[tag.PatientID].Value = "FULL"
+	require.ErrorAs(t, assoc.Store(ctx, ds), &se)
+	assert.Equal(t, StatusOutOfResources, se.Status)
+
+	// The association survives failed stores
+	assert.NoError(t, assoc.Echo(ctx))
+}
+
+func TestServer_Negotiation(t *testing.T) {
+	addr := startServer(t, &Server{
+		AETitle:          "DICOS_SCP",
+		SOPClasses:       []string{dicos.DICOSTDRStorageUID},
+		TransferSyntaxes: []string{string(dicos.ExplicitVRLittleEndian), string(dicos.ImplicitVRLittleEndian)},
+		Handler:          func(ctx context.Context, req *StoreRequest) error { return nil },
+	})
+	ctx := context.Background()
+
+	_, err := Dial(ctx, addr, ClientOptions{CalledAE: "SOMEONE_ELSE"})
+	var rj *RejectError
+	require.ErrorAs(t, err, &rj)
+	assert.Equal(t, byte(7), rj.Reason)
+
+	assoc, err := Dial(ctx, addr, ClientOptions{CalledAE: "DICOS_SCP"})
+	require.NoError(t, err)
+	defer assoc.Release()
+	for _, pc := range assoc.Contexts() {
+		switch {
+		case pc.AbstractSyntax == VerificationSOPClass:
+			assert.Equal(t, byte(ResultAcceptance), pc.Result)
+		case pc.AbstractSyntax != dicos.DICOSTDRStorageUID:
+			assert.Equal(t, byte(ResultAbstractSyntaxNotSupported), pc.Result, pc.AbstractSyntax)
+		case pc.TransferSyntaxes[0] == string(dicos.JPEGLSLossless):
+			assert.Equal(t, byte(ResultTransferSyntaxNotSupported), pc.Result)
+		}
+	}
+
+	// A CT is refused by the client, as no context carries it
+	assert.ErrorContains(t, assoc.Store(ctx, testCT(t, nil)), "no accepted presentation context")
+}
+
+func TestDial_Cancel(t *testing.T) {
+	block := make(chan struct{})
+	addr := startServer(t, &Server{
+		Handler: func(ctx context.Context, req *StoreRequest) error {
+			<-block
+			return nil
+		},
+	})
+	defer close(block)
+
+	assoc, err := Dial(context.Background(), addr, ClientOptions{})
+	require.NoError(t, err)
+	defer assoc.Abort()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, assoc.Store(ctx, testCT(t, nil)), context.DeadlineExceeded)
+}
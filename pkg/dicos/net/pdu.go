@@ -0,0 +1,298 @@
+// Package net implements the DICOM Upper Layer protocol (PS3.8) and the DIMSE
+// services (PS3.7) that DICOS systems use to exchange datasets: a C-STORE SCP
+// (Server) that receives datasets and an SCU (Dial) that sends them, queries
+// archives with C-FIND and retrieves from them with C-MOVE.
+package net
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PDU types (PS3.8 9.3)
+const (
+	pduAssociateRQ = 0x01
+	pduAssociateAC = 0x02
+	pduAssociateRJ = 0x03
+	pduDataTF      = 0x04
+	pduReleaseRQ   = 0x05
+	pduReleaseRP   = 0x06
+	pduAbort       = 0x07
+)
+
+// Variable item types of the association PDUs
+const (
+	itemApplicationContext     = 0x10
+	itemPresentationContextRQ  = 0x20
+	itemPresentationContextAC  = 0x21
+	itemAbstractSyntax         = 0x30
+	itemTransferSyntax         = 0x40
+	itemUserInformation        = 0x50
+	itemMaxLength              = 0x51
+	itemImplementationClassUID = 0x52
+	itemImplementationVersion  = 0x55
+)
+
+const (
+	// ApplicationContextName is the only application context DICOM defines
+	ApplicationContextName = "1.2.840.10008.3.1.1.1"
+	// ImplementationClassUID identifies this implementation to peers
+	ImplementationClassUID = "1.2.826.0.1.3680043.8.498.1"
+	// ImplementationVersionName accompanies ImplementationClassUID
+	ImplementationVersionName = "GO_DICOS"
+	// DefaultMaxPDULength is the largest P-DATA PDU we accept unless configured
+	DefaultMaxPDULength = 64 * 1024
+
+	// maxPDUSize bounds any PDU read from a peer, whatever it claims
+	maxPDUSize = 16 * 1024 * 1024
+)
+
+// Presentation context results (PS3.8 9.3.3.2)
+const (
+	ResultAcceptance                 = 0
+	ResultUserRejection              = 1
+	ResultNoReason                   = 2
+	ResultAbstractSyntaxNotSupported = 3
+	ResultTransferSyntaxNotSupported = 4
+)
+
+// errReleased is returned by readMessage when the peer releases the association
+var errReleased = errors.New("association released")
+
+// AbortError reports an A-ABORT received from the peer
+type AbortError struct {
+	Source, Reason byte
+}
+
+func (e *AbortError) Error() string {
+	return fmt.Sprintf("dicos/net: association aborted (source %d, reason %d)", e.Source, e.Reason)
+}
+
+// RejectError reports an A-ASSOCIATE-RJ received from the peer
+type RejectError struct {
+	Result, Source, Reason byte
+}
+
+func (e *RejectError) Error() string {
+	return fmt.Sprintf("dicos/net: association rejected (result %d, source %d, reason %d)", e.Result, e.Source, e.Reason)
+}
+
+// PresentationContext is an abstract syntax negotiated on an association. A
+// proposal lists the acceptable transfer syntaxes; an answer holds the one
+// accepted, or none with a Result other than ResultAcceptance.
+type PresentationContext struct {
+	ID               byte
+	AbstractSyntax   string
+	TransferSyntaxes []string
+	Result           byte
+}
+
+// associate is the body of an A-ASSOCIATE-RQ or -AC PDU
+type associate struct {
+	CalledAE, CallingAE    string
+	Contexts               []PresentationContext
+	MaxPDULength           uint32
+	ImplementationClassUID string
+	ImplementationVersion  string
+}
+
+// readPDU reads one PDU and returns its type and body
+func readPDU(r io.Reader) (byte, []byte, error) {
+	var hdr [6]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return 0, nil, err
+	}
+	length := binary.BigEndian.Uint32(hdr[2:])
+	if length > maxPDUSize {
+		return 0, nil, fmt.Errorf("dicos/net: PDU type %d of %d bytes exceeds %d", hdr[0], length, maxPDUSize)
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, fmt.Errorf("dicos/net: truncated PDU type %d: %w", hdr[0], err)
+	}
+	return hdr[0], body, nil
+}
+
+// writePDU writes one PDU
+func writePDU(w io.Writer, typ byte, body []byte) error {
+	b := make([]byte, 6, 6+len(body))
+	b[0] = typ
+	binary.BigEndian.PutUint32(b[2:], uint32(len(body)))
+	_, err := w.Write(append(b, body...))
+	return err
+}
+
+// appendItem appends a variable item: type, reserved byte and 16-bit length
+func appendItem(b []byte, typ byte, value []byte) []byte {
+	b = append(b, typ, 0)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(value)))
+	return append(b, value...)
+}
+
+// aeTitle pads an AE title to its fixed 16 bytes
+func aeTitle(s string) []byte {
+	b := bytes.Repeat([]byte{' '}, 16)
+	copy(b, s)
+	return b
+}
+
+// trimUID removes the NUL or space padding of a UID item
+func trimUID(b []byte) string {
+	return strings.TrimRight(string(b), "\x00 ")
+}
+
+// encode returns the body of an A-ASSOCIATE-RQ, or of an -AC when ac is set
+func (a *associate) encode(ac bool) []byte {
+	b := []byte{0, 1, 0, 0} // protocol version 1, reserved
+	b = append(b, aeTitle(a.CalledAE)...)
+	b = append(b, aeTitle(a.CallingAE)...)
+	b = append(b, make([]byte, 32)...)
+	b = appendItem(b, itemApplicationContext, []byte(ApplicationContextName))
+	for _, pc := range a.Contexts {
+		var v []byte
+		if ac {
+			v = append(v, pc.ID, 0, pc.Result, 0)
+			ts := ""
+			if len(pc.TransferSyntaxes) > 0 {
+				ts = pc.TransferSyntaxes[0]
+			}
+			v = appendItem(v, itemTransferSyntax, []byte(ts))
+			b = appendItem(b, itemPresentationContextAC, v)
+			continue
+		}
+		v = append(v, pc.ID, 0, 0, 0)
+		v = appendItem(v, itemAbstractSyntax, []byte(pc.AbstractSyntax))
+		for _, ts := range pc.TransferSyntaxes {
+			v = appendItem(v, itemTransferSyntax, []byte(ts))
+		}
+		b = appendItem(b, itemPresentationContextRQ, v)
+	}
+	var ui []byte
+	ui = appendItem(ui, itemMaxLength, binary.BigEndian.AppendUint32(nil, a.MaxPDULength))
+	ui = appendItem(ui, itemImplementationClassUID, []byte(a.ImplementationClassUID))
+	if a.ImplementationVersion != "" {
+		ui = appendItem(ui, itemImplementationVersion, []byte(a.ImplementationVersion))
+	}
+	return appendItem(b, itemUserInformation, ui)
+}
+
+// items splits a run of variable items
+func items(b []byte, visit func(typ byte, value []byte) error) error {
+	for len(b) > 0 {
+		if len(b) < 4 {
+			return fmt.Errorf("dicos/net: truncated item header")
+		}
+		typ, n := b[0], int(binary.BigEndian.Uint16(b[2:]))
+		if len(b) < 4+n {
+			return fmt.Errorf("dicos/net: item type 0x%02X of %d bytes is truncated", typ, n)
+		}
+		if err := visit(typ, b[4:4+n]); err != nil {
+			return err
+		}
+		b = b[4+n:]
+	}
+	return nil
+}
+
+// decodeAssociate parses the body of an A-ASSOCIATE-RQ or -AC
+func decodeAssociate(b []byte) (*associate, error) {
+	if len(b) < 68 {
+		return nil, fmt.Errorf("dicos/net: association PDU of %d bytes is too short", len(b))
+	}
+	if binary.BigEndian.Uint16(b)&1 == 0 {
+		return nil, fmt.Errorf("dicos/net: unsupported protocol version 0x%04X", binary.BigEndian.Uint16(b))
+	}
+	a := &associate{
+		CalledAE:  strings.TrimSpace(string(b[4:20])),
+		CallingAE: strings.TrimSpace(string(b[20:36])),
+	}
+	err := items(b[68:], func(typ byte, v []byte) error {
+		switch typ {
+		case itemApplicationContext:
+			if name := trimUID(v); name != ApplicationContextName {
+				return fmt.Errorf("dicos/net: unsupported application context %q", name)
+			}
+		case itemPresentationContextRQ, itemPresentationContextAC:
+			if len(v) < 4 {
+				return fmt.Errorf("dicos/net: truncated presentation context")
+			}
+			pc := PresentationContext{ID: v[0], Result: v[2]}
+			if err := items(v[4:], func(typ byte, v []byte) error {
+				switch typ {
+				case itemAbstractSyntax:
+					pc.AbstractSyntax = trimUID(v)
+				case itemTransferSyntax:
+					pc.TransferSyntaxes = append(pc.TransferSyntaxes, trimUID(v))
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			a.Contexts = append(a.Contexts, pc)
+		case itemUserInformation:
+			return items(v, func(typ byte, v []byte) error {
+				switch typ {
+				case itemMaxLength:
+					if len(v) == 4 {
+						a.MaxPDULength = binary.BigEndian.Uint32(v)
+					}
+				case itemImplementationClassUID:
+					a.ImplementationClassUID = trimUID(v)
+				case itemImplementationVersion:
+					a.ImplementationVersion = strings.TrimSpace(string(v))
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+	return a, err
+}
+
+// pdv is one Presentation Data Value of a P-DATA-TF PDU
+type pdv struct {
+	contextID byte
+	command   bool // command set, otherwise dataset
+	last      bool // last fragment of the command set or dataset
+	data      []byte
+}
+
+// decodePDVs parses the body of a P-DATA-TF PDU
+func decodePDVs(b []byte) ([]pdv, error) {
+	var out []pdv
+	for len(b) > 0 {
+		if len(b) < 6 {
+			return nil, fmt.Errorf("dicos/net: truncated PDV header")
+		}
+		n := int(binary.BigEndian.Uint32(b))
+		if n < 2 || len(b) < 4+n {
+			return nil, fmt.Errorf("dicos/net: PDV of %d bytes is truncated", n)
+		}
+		out = append(out, pdv{
+			contextID: b[4],
+			command:   b[5]&0x01 != 0,
+			last:      b[5]&0x02 != 0,
+			data:      b[6 : 4+n],
+		})
+		b = b[4+n:]
+	}
+	return out, nil
+}
+
+// encodePDV returns the body of a P-DATA-TF PDU holding one PDV
+func encodePDV(p pdv) []byte {
+	b := binary.BigEndian.AppendUint32(nil, uint32(len(p.data)+2))
+	var mch byte
+	if p.command {
+		mch |= 0x01
+	}
+	if p.last {
+		mch |= 0x02
+	}
+	b = append(b, p.contextID, mch)
+	return append(b, p.data...)
+}
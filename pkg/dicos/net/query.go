@@ -0,0 +1,156 @@
+package net
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// Query/Retrieve information models (PS3.4 C.6)
+const (
+	PatientRootFind = "1.2.840.10008.5.1.4.1.2.1.1"
+	PatientRootMove = "1.2.840.10008.5.1.4.1.2.1.2"
+	StudyRootFind   = "1.2.840.10008.5.1.4.1.2.2.1"
+	StudyRootMove   = "1.2.840.10008.5.1.4.1.2.2.2"
+)
+
+// DefaultQuerySOPClasses are the query/retrieve SOP classes proposed by Dial
+// unless configured otherwise
+var DefaultQuerySOPClasses = []string{StudyRootFind, StudyRootMove, PatientRootFind, PatientRootMove}
+
+// QueryLevel is the Query/Retrieve Level of an identifier
+type QueryLevel string
+
+const (
+	PatientLevel QueryLevel = "PATIENT"
+	StudyLevel   QueryLevel = "STUDY"
+	SeriesLevel  QueryLevel = "SERIES"
+	ImageLevel   QueryLevel = "IMAGE"
+)
+
+// MoveResult counts the C-STORE sub-operations of a C-MOVE
+type MoveResult struct {
+	Completed, Failed, Warning int
+}
+
+// NewIdentifier builds a C-FIND or C-MOVE identifier at level from keys. A
+// key with an empty or nil value is a return key: every match carries it.
+// Matching follows PS3.4 C.2.2, e.g. "BAG*" as a wildcard or
+// "20240101-20240131" as a date range.
+//
+// Example:
+//
+//	id, err := net.NewIdentifier(net.StudyLevel, map[tag.Tag]any{
+//		tag.StudyDate:        "20240101-20240131",
+//		tag.StudyInstanceUID: "",
+//		tag.PatientID:        "",
+//	})
+func NewIdentifier(level QueryLevel, keys map[tag.Tag]any) (*dicos.Dataset, error) {
+	opts := []dicos.Option{dicos.WithElement(tag.QueryRetrieveLevel, string(level))}
+	for t, v := range keys {
+		if v == nil {
+			v = ""
+		}
+		opts = append(opts, dicos.WithElement(t, v))
+	}
+	return dicos.NewDataset(opts...)
+}
+
+// Find sends a C-FIND with identifier and returns the matches. Queries at
+// PATIENT level use the Patient Root model, others prefer Study Root.
+func (a *Association) Find(ctx context.Context, identifier *dicos.Dataset) ([]*dicos.Dataset, error) {
+	pc, sopClass, err := a.queryContext(identifier, StudyRootFind, PatientRootFind)
+	if err != nil {
+		return nil, err
+	}
+	body, err := encodeIdentifier(identifier)
+	if err != nil {
+		return nil, err
+	}
+	var matches []*dicos.Dataset
+	_, err = a.exchange(ctx, pc.ID, &Command{Field: CFindRQ, AffectedSOPClassUID: sopClass}, body, func(m *message) error {
+		if !m.command.HasDataset {
+			return nil
+		}
+		match, err := parseIdentifier(ctx, pc.TransferSyntaxes[0], m.data)
+		if err != nil {
+			return fmt.Errorf("dicos/net: C-FIND match %d: %w", len(matches)+1, err)
+		}
+		matches = append(matches, match)
+		return nil
+	})
+	return matches, err
+}
+
+// Move sends a C-MOVE asking the SCP to store the instances matching
+// identifier on the AE title destination, and waits until it is done. The
+// destination is usually a Server of the caller, known to the SCP by that
+// AE title. A failure status is returned as a *StatusError along with the
+// counts received.
+func (a *Association) Move(ctx context.Context, destination string, identifier *dicos.Dataset) (MoveResult, error) {
+	pc, sopClass, err := a.queryContext(identifier, StudyRootMove, PatientRootMove)
+	if err != nil {
+		return MoveResult{}, err
+	}
+	body, err := encodeIdentifier(identifier)
+	if err != nil {
+		return MoveResult{}, err
+	}
+	rsp, err := a.exchange(ctx, pc.ID, &Command{
+		Field:               CMoveRQ,
+		AffectedSOPClassUID: sopClass,
+		MoveDestination:     destination,
+	}, body, func(m *message) error { return nil })
+	if rsp == nil {
+		return MoveResult{}, err
+	}
+	return MoveResult{Completed: int(rsp.Completed), Failed: int(rsp.Failed), Warning: int(rsp.Warning)}, err
+}
+
+// queryContext returns the accepted presentation context for identifier:
+// Patient Root for PATIENT level queries, otherwise Study Root and then
+// Patient Root
+func (a *Association) queryContext(identifier *dicos.Dataset, studyRoot, patientRoot string) (PresentationContext, string, error) {
+	level := QueryLevel(uid(identifier, tag.QueryRetrieveLevel))
+	if level == "" {
+		return PresentationContext{}, "", fmt.Errorf("dicos/net: identifier has no Query/Retrieve Level")
+	}
+	models := []string{studyRoot, patientRoot}
+	if level == PatientLevel {
+		models = models[1:]
+	}
+	ts := string(dicos.ExplicitVRLittleEndian)
+	for _, sopClass := range models {
+		if pc, ok := acceptedContext(a.contexts, sopClass, ts); ok {
+			return pc, sopClass, nil
+		}
+	}
+	return PresentationContext{}, "", fmt.Errorf("dicos/net: no accepted presentation context for %s in %s", models[0], ts)
+}
+
+// encodeIdentifier returns identifier as sent in P-DATA
+func encodeIdentifier(identifier *dicos.Dataset) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := dicos.Write(&buf, identifier); err != nil {
+		return nil, err
+	}
+	return stripFileMeta(buf.Bytes())
+}
+
+// parseIdentifier reads an identifier received as P-DATA. It has no SOP
+// instance, so the File Meta Information rebuilt to read it is dropped.
+func parseIdentifier(ctx context.Context, ts string, body []byte) (*dicos.Dataset, error) {
+	ds, err := parseDataset(ctx, &Command{}, ts, body)
+	if err != nil {
+		return nil, err
+	}
+	for t := range ds.Elements {
+		if t.Group == 0x0002 {
+			delete(ds.Elements, t)
+		}
+	}
+	return ds, nil
+}
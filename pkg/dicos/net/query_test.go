@@ -0,0 +1,128 @@
+package net
+
+import (
+	"context"
+	stdnet "net"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startArchive serves one association that accepts every proposed context
+// and answers each request with respond
+func startArchive(t *testing.T, respond func(c *conn, m *message)) string {
+	t.Helper()
+	l, err := stdnet.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		nc, err := l.Accept()
+		if err != nil {
+			return
+		}
+		c := newConn(nc, 0)
+		defer c.Close()
+		_, body, err := c.readPDU()
+		if err != nil {
+			return
+		}
+		rq, err := decodeAssociate(body)
+		if err != nil {
+			return
+		}
+		ac := &associate{CalledAE: rq.CalledAE, CallingAE: rq.CallingAE, MaxPDULength: DefaultMaxPDULength, ImplementationClassUID: ImplementationClassUID}
+		for _, pc := range rq.Contexts {
+			ac.Contexts = append(ac.Contexts, PresentationContext{ID: pc.ID, TransferSyntaxes: pc.TransferSyntaxes[:1]})
+		}
+		if c.writePDU(pduAssociateAC, ac.encode(true)) != nil {
+			return
+		}
+		for {
+			m, err := c.readMessage()
+			if err != nil {
+				return
+			}
+			respond(c, m)
+		}
+	}()
+	return l.Addr().String()
+}
+
+func TestAssociation_Find(t *testing.T) {
+	var query *dicos.Dataset
+	addr := startArchive(t, func(c *conn, m *message) {
+		var err error
+		query, err = parseIdentifier(context.Background(), string(dicos.ExplicitVRLittleEndian), m.data)
+		assert.NoError(t, err)
+		rsp := &Command{Field: CFindRSP, MessageIDBeingRespondedTo: m.command.MessageID, AffectedSOPClassUID: m.command.AffectedSOPClassUID, Status: StatusPending}
+		for _, id := range []string{"BAG-0001", "BAG-0002"} {
+			match, err := NewIdentifier(StudyLevel, map[tag.Tag]any{
+				tag.PatientID:        id,
+				tag.StudyInstanceUID: "1.2.3." + id[4:],
+			})
+			assert.NoError(t, err)
+			body, err := encodeIdentifier(match)
+			assert.NoError(t, err)
+			assert.NoError(t, c.writeMessage(m.contextID, rsp, body))
+		}
+		rsp.Status = StatusSuccess
+		assert.NoError(t, c.writeMessage(m.contextID, rsp, nil))
+	})
+
+	ctx := context.Background()
+	assoc, err := Dial(ctx, addr, ClientOptions{})
+	require.NoError(t, err)
+	defer assoc.Release()
+
+	id, err := NewIdentifier(StudyLevel, map[tag.Tag]any{
+		tag.PatientID:        "BAG*",
+		tag.StudyInstanceUID: nil,
+	})
+	require.NoError(t, err)
+	matches, err := assoc.Find(ctx, id)
+	require.NoError(t, err)
+
+	require.NotNil(t, query)
+	assert.Equal(t, "STUDY", uid(query, tag.QueryRetrieveLevel))
+	assert.Equal(t, "BAG*", uid(query, tag.PatientID))
+	assert.True(t, dicos.HasElement(query, tag.StudyInstanceUID), "return keys are sent")
+
+	require.Len(t, matches, 2)
+	assert.Equal(t, "BAG-0002", uid(matches[1], tag.PatientID))
+	assert.Equal(t, "1.2.3.0002", uid(matches[1], tag.StudyInstanceUID))
+	assert.False(t, dicos.HasElement(matches[1], tag.TransferSyntaxUID))
+
+	empty, err := dicos.NewDataset()
+	require.NoError(t, err)
+	_, err = assoc.Find(ctx, empty)
+	assert.ErrorContains(t, err, "no Query/Retrieve Level")
+}
+
+func TestAssociation_Move(t *testing.T) {
+	var dest string
+	addr := startArchive(t, func(c *conn, m *message) {
+		dest = m.command.MoveDestination
+		rsp := &Command{Field: CMoveRSP, MessageIDBeingRespondedTo: m.command.MessageID, Status: StatusPending, Remaining: 2}
+		for i := range 2 {
+			rsp.Remaining, rsp.Completed = uint16(2-i), uint16(i)
+			assert.NoError(t, c.writeMessage(m.contextID, rsp, nil))
+		}
+		rsp.Status, rsp.Completed, rsp.Failed = 0xB000, 2, 1 // warning: sub-operations failed
+		assert.NoError(t, c.writeMessage(m.contextID, rsp, nil))
+	})
+
+	ctx := context.Background()
+	assoc, err := Dial(ctx, addr, ClientOptions{})
+	require.NoError(t, err)
+	defer assoc.Release()
+
+	id, err := NewIdentifier(StudyLevel, map[tag.Tag]any{tag.StudyInstanceUID: "1.2.3.4"})
+	require.NoError(t, err)
+	res, err := assoc.Move(ctx, "WORKSTATION", id)
+	require.NoError(t, err)
+	assert.Equal(t, "WORKSTATION", dest)
+	assert.Equal(t, MoveResult{Completed: 2, Failed: 1}, res)
+}
@@ -0,0 +1,352 @@
+package net
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	stdnet "net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+)
+
+// ErrServerClosed is returned by Serve and ListenAndServe after Shutdown
+var ErrServerClosed = errors.New("dicos/net: server closed")
+
+// StoreRequest is one dataset received by C-STORE
+type StoreRequest struct {
+	CallingAE      string // AE title of the sender
+	CalledAE       string // AE title the sender addressed
+	RemoteAddr     string
+	SOPClassUID    string // Affected SOP Class UID of the command
+	SOPInstanceUID string // Affected SOP Instance UID of the command
+	// PeerCertificates is the verified client certificate chain of a
+	// mutually authenticated TLS association, leaf first, or nil
+	PeerCertificates []*x509.Certificate
+	// Dataset includes File Meta Information rebuilt from the association,
+	// so it can be written with dicos.WriteFile unchanged
+	Dataset *dicos.Dataset
+}
+
+// StoreHandler is called with each received dataset. A non-nil error is
+// answered with StatusProcessingFailure, or with the status of a
+// *StatusError, and the association stays open.
+type StoreHandler func(ctx context.Context, req *StoreRequest) error
+
+// Server is a C-STORE SCP: it accepts associations for the DICOS storage SOP
+// classes and Verification, and passes every received dataset to Handler.
+// The zero value, with a Handler, accepts any called AE title.
+//
+// Example:
+//
+//	srv := &net.Server{AETitle: "DICOS_SCP", Handler: func(ctx context.Context, req *net.StoreRequest) error {
+//		_, err := dicos.WriteFile(req.SOPInstanceUID+".dcs", req.Dataset)
+//		return err
+//	}}
+//	log.Fatal(srv.ListenAndServe(":11112"))
+type Server struct {
+	AETitle          string        // called AE title to accept, empty accepts any
+	Handler          StoreHandler  // required
+	SOPClasses       []string      // storage SOP classes to accept, DefaultSOPClasses when nil
+	TransferSyntaxes []string      // in order of preference, DefaultTransferSyntaxes when nil
+	MaxPDULength     uint32        // largest PDU we accept, DefaultMaxPDULength when zero
+	Timeout          time.Duration // idle limit per PDU and for the TLS handshake, zero waits forever
+	TLSConfig        *tls.Config   // secures every association with TLS when set, see TLSOptions
+
+	mu        sync.Mutex
+	listeners map[stdnet.Listener]struct{}
+	conns     map[*conn]struct{}
+	active    sync.WaitGroup
+	closed    bool
+	done      chan struct{}
+}
+
+// ListenAndServe listens on the TCP address addr and serves associations
+func (s *Server) ListenAndServe(addr string) error {
+	l, err := stdnet.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// Serve accepts associations on l until Shutdown, always returning a non-nil
+// error. Each association is served on its own goroutine.
+func (s *Server) Serve(l stdnet.Listener) error {
+	if s.Handler == nil {
+		return fmt.Errorf("dicos/net: Server has no Handler")
+	}
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		l.Close()
+		return ErrServerClosed
+	}
+	if s.TLSConfig != nil {
+		l = tls.NewListener(l, s.TLSConfig)
+	}
+	if s.listeners == nil {
+		s.listeners = make(map[stdnet.Listener]struct{})
+		s.conns = make(map[*conn]struct{})
+		s.done = make(chan struct{})
+	}
+	s.listeners[l] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.listeners, l)
+		s.mu.Unlock()
+		l.Close()
+	}()
+
+	slog.Info("DICOS SCP listening", slog.String("addr", l.Addr().String()), slog.String("ae", s.AETitle), slog.Bool("tls", s.TLSConfig != nil))
+	for {
+		nc, err := l.Accept()
+		if err != nil {
+			select {
+			case <-s.done:
+				return ErrServerClosed
+			default:
+			}
+			return err
+		}
+		c := newConn(nc, s.Timeout)
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			nc.Close()
+			return ErrServerClosed
+		}
+		s.conns[c] = struct{}{}
+		s.active.Add(1)
+		s.mu.Unlock()
+		go func() {
+			defer func() {
+				c.Close()
+				s.mu.Lock()
+				delete(s.conns, c)
+				s.mu.Unlock()
+				s.active.Done()
+			}()
+			s.serveConn(context.Background(), c)
+		}()
+	}
+}
+
+// Shutdown stops accepting associations and waits for the open ones to be
+// released. When ctx ends first the remaining connections are closed and
+// ctx.Err() is returned.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		if s.done != nil {
+			close(s.done)
+		}
+		for l := range s.listeners {
+			l.Close()
+		}
+	}
+	s.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		s.active.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		for c := range s.conns {
+			c.Close()
+		}
+		s.mu.Unlock()
+		<-drained
+		return ctx.Err()
+	}
+}
+
+// serveConn negotiates an association and serves its messages until release
+func (s *Server) serveConn(ctx context.Context, c *conn) {
+	remote := c.c.RemoteAddr().String()
+	log := slog.With(slog.String("remote", remote))
+
+	if tc, ok := c.c.(*tls.Conn); ok {
+		hctx := ctx
+		if s.Timeout > 0 {
+			var cancel context.CancelFunc
+			hctx, cancel = context.WithTimeout(ctx, s.Timeout)
+			defer cancel()
+		}
+		if err := tc.HandshakeContext(hctx); err != nil {
+			log.Warn("TLS handshake failed", slog.Any("error", err))
+			return
+		}
+		logTLS(log, tc.ConnectionState())
+	}
+
+	typ, body, err := c.readPDU()
+	if err != nil {
+		log.Debug("Association not started", slog.Any("error", err))
+		return
+	}
+	if typ != pduAssociateRQ {
+		c.abort(2)
+		log.Warn("Expected A-ASSOCIATE-RQ", slog.Int("pdu", int(typ)))
+		return
+	}
+	rq, err := decodeAssociate(body)
+	if err != nil {
+		// result 1 permanent, source 1 service user, reason 2 application context
+		c.writePDU(pduAssociateRJ, []byte{0, 1, 1, 2})
+		log.Warn("Rejected association", slog.Any("error", err))
+		return
+	}
+	log = log.With(slog.String("calling", rq.CallingAE), slog.String("called", rq.CalledAE))
+	if s.AETitle != "" && rq.CalledAE != s.AETitle {
+		c.writePDU(pduAssociateRJ, []byte{0, 1, 1, 7}) // called AE title not recognized
+		log.Warn("Rejected association for unknown AE title")
+		return
+	}
+
+	ac := &associate{
+		CalledAE:               rq.CalledAE,
+		CallingAE:              rq.CallingAE,
+		MaxPDULength:           s.maxPDULength(),
+		ImplementationClassUID: ImplementationClassUID,
+		ImplementationVersion:  ImplementationVersionName,
+	}
+	for _, pc := range rq.Contexts {
+		ac.Contexts = append(ac.Contexts, s.negotiate(pc))
+	}
+	if err := c.writePDU(pduAssociateAC, ac.encode(true)); err != nil {
+		log.Warn("Failed to accept association", slog.Any("error", err))
+		return
+	}
+	for i := range ac.Contexts {
+		ac.Contexts[i].AbstractSyntax = rq.Contexts[i].AbstractSyntax
+	}
+	c.maxPDU = rq.MaxPDULength
+	log.Info("Association accepted", slog.Int("contexts", len(ac.Contexts)))
+
+	for {
+		m, err := c.readMessage()
+		switch {
+		case errors.Is(err, errReleased):
+			log.Info("Association released")
+			return
+		case err != nil:
+			log.Warn("Association ended", slog.Any("error", err))
+			return
+		}
+		if err := s.serveMessage(ctx, c, ac, remote, m, log); err != nil {
+			log.Warn("Association ended", slog.Any("error", err))
+			return
+		}
+	}
+}
+
+// negotiate answers one proposed presentation context
+func (s *Server) negotiate(pc PresentationContext) PresentationContext {
+	out := PresentationContext{ID: pc.ID, Result: ResultAbstractSyntaxNotSupported}
+	classes := s.SOPClasses
+	if classes == nil {
+		classes = DefaultSOPClasses
+	}
+	if pc.AbstractSyntax != VerificationSOPClass && !slices.Contains(classes, pc.AbstractSyntax) {
+		return out
+	}
+	syntaxes := s.TransferSyntaxes
+	if syntaxes == nil {
+		syntaxes = DefaultTransferSyntaxes
+	}
+	out.Result = ResultTransferSyntaxNotSupported
+	for _, ts := range syntaxes {
+		if slices.Contains(pc.TransferSyntaxes, ts) {
+			out.Result = ResultAcceptance
+			out.TransferSyntaxes = []string{ts}
+			break
+		}
+	}
+	return out
+}
+
+func (s *Server) maxPDULength() uint32 {
+	if s.MaxPDULength == 0 {
+		return DefaultMaxPDULength
+	}
+	return s.MaxPDULength
+}
+
+// serveMessage answers one DIMSE request
+func (s *Server) serveMessage(ctx context.Context, c *conn, ac *associate, remote string, m *message, log *slog.Logger) error {
+	var pc *PresentationContext
+	for i := range ac.Contexts {
+		if ac.Contexts[i].ID == m.contextID && ac.Contexts[i].Result == ResultAcceptance {
+			pc = &ac.Contexts[i]
+		}
+	}
+	if pc == nil {
+		c.abort(6)
+		return fmt.Errorf("dicos/net: message on unaccepted presentation context %d", m.contextID)
+	}
+
+	rsp := &Command{
+		MessageIDBeingRespondedTo: m.command.MessageID,
+		AffectedSOPClassUID:       m.command.AffectedSOPClassUID,
+		AffectedSOPInstanceUID:    m.command.AffectedSOPInstanceUID,
+	}
+	switch m.command.Field {
+	case CEchoRQ:
+		rsp.Field, rsp.Status = CEchoRSP, StatusSuccess
+	case CStoreRQ:
+		rsp.Field = CStoreRSP
+		rsp.Status = s.store(ctx, c, ac, remote, pc.TransferSyntaxes[0], m, log)
+	default:
+		c.abort(6)
+		return fmt.Errorf("dicos/net: unsupported command 0x%04X", m.command.Field)
+	}
+	return c.writeMessage(m.contextID, rsp, nil)
+}
+
+// store parses a C-STORE dataset, runs the handler and returns the status
+func (s *Server) store(ctx context.Context, c *conn, ac *associate, remote, ts string, m *message, log *slog.Logger) uint16 {
+	log = log.With(slog.String("sop_instance", m.command.AffectedSOPInstanceUID))
+	if !m.command.HasDataset {
+		log.Warn("C-STORE without a dataset")
+		return StatusCannotUnderstand
+	}
+	ds, err := parseDataset(ctx, m.command, ts, m.data)
+	if err != nil {
+		log.Warn("C-STORE dataset not readable", slog.Any("error", err))
+		return StatusCannotUnderstand
+	}
+	err = s.Handler(ctx, &StoreRequest{
+		CallingAE:        ac.CallingAE,
+		CalledAE:         ac.CalledAE,
+		RemoteAddr:       remote,
+		SOPClassUID:      m.command.AffectedSOPClassUID,
+		SOPInstanceUID:   m.command.AffectedSOPInstanceUID,
+		PeerCertificates: c.peerCertificates(),
+		Dataset:          ds,
+	})
+	var se *StatusError
+	switch {
+	case err == nil:
+		log.Info("C-STORE received", slog.Int("bytes", len(m.data)))
+		return StatusSuccess
+	case errors.As(err, &se):
+		log.Warn("C-STORE handler failed", slog.Any("error", err))
+		return se.Status
+	default:
+		log.Warn("C-STORE handler failed", slog.Any("error", err))
+		return StatusProcessingFailure
+	}
+}
@@ -0,0 +1,155 @@
+package net
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/jpfielding/dicos.go/pkg/util"
+)
+
+// TLSProfile selects the TLS versions and cipher suites of an association,
+// after the BCP 195 secure transport connection profiles of PS3.15 Annex B
+type TLSProfile string
+
+const (
+	// TLSProfileBCP195 allows TLS 1.2 and later with every suite BCP 195
+	// permits, the Go defaults (PS3.15 B.9)
+	TLSProfileBCP195 TLSProfile = "bcp195"
+	// TLSProfileNonDowngradingBCP195 allows TLS 1.2 and later with only the
+	// forward secret AEAD suites BCP 195 recommends (PS3.15 B.10)
+	TLSProfileNonDowngradingBCP195 TLSProfile = "bcp195-nd"
+	// TLSProfileExtendedBCP195 allows TLS 1.3 only (PS3.15 B.11)
+	TLSProfileExtendedBCP195 TLSProfile = "bcp195-ext"
+)
+
+// TLSProfiles lists the profiles in order of strictness
+var TLSProfiles = []TLSProfile{TLSProfileBCP195, TLSProfileNonDowngradingBCP195, TLSProfileExtendedBCP195}
+
+// bcp195Suites are the recommended TLS 1.2 suites of BCP 195 that Go
+// implements: ECDHE key exchange with AES-GCM or ChaCha20-Poly1305
+var bcp195Suites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+}
+
+// Apply sets the minimum version and cipher suites of the profile on cfg.
+// The empty profile is TLSProfileBCP195.
+func (p TLSProfile) Apply(cfg *tls.Config) error {
+	switch p {
+	case "", TLSProfileBCP195:
+		cfg.MinVersion, cfg.CipherSuites = tls.VersionTLS12, nil
+	case TLSProfileNonDowngradingBCP195:
+		cfg.MinVersion, cfg.CipherSuites = tls.VersionTLS12, bcp195Suites
+	case TLSProfileExtendedBCP195:
+		cfg.MinVersion, cfg.CipherSuites = tls.VersionTLS13, nil
+	default:
+		return fmt.Errorf("dicos/net: unknown TLS profile %q, want one of %v", p, TLSProfiles)
+	}
+	return nil
+}
+
+// TLSOptions builds the TLS configuration of an SCP or SCU from PEM files
+//
+// Example:
+//
+//	cfg, err := net.TLSOptions{CertFile: "scp.pem", KeyFile: "scp.key", CAFile: "ca.pem", VerifyClient: true}.ServerConfig()
+//	srv := &net.Server{AETitle: "DICOS_SCP", Handler: h, TLSConfig: cfg}
+//	log.Fatal(srv.ListenAndServe(":2762"))
+type TLSOptions struct {
+	CertFile     string     // our certificate chain, required for an SCP and for mutual authentication
+	KeyFile      string     // private key of CertFile
+	CAFile       string     // CAs trusted to sign the peer's certificate, the system pool when empty
+	Profile      TLSProfile // TLSProfileBCP195 when empty
+	ServerName   string     // SCU: name the SCP certificate must hold, the dialed host when empty
+	VerifyClient bool       // SCP: require a client certificate signed by CAFile (mutual authentication)
+}
+
+// ServerConfig returns the configuration of an SCP presenting CertFile
+func (o TLSOptions) ServerConfig() (*tls.Config, error) {
+	if o.CertFile == "" || o.KeyFile == "" {
+		return nil, fmt.Errorf("dicos/net: a TLS server needs a certificate and key")
+	}
+	cfg, err := o.config()
+	if err != nil {
+		return nil, err
+	}
+	if o.VerifyClient {
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+		cfg.ClientCAs, cfg.RootCAs = cfg.RootCAs, nil
+		if cfg.ClientCAs == nil {
+			if cfg.ClientCAs, err = x509.SystemCertPool(); err != nil {
+				return nil, fmt.Errorf("dicos/net: loading system CAs: %w", err)
+			}
+		}
+	}
+	return cfg, nil
+}
+
+// ClientConfig returns the configuration of an SCU, presenting CertFile when
+// the SCP asks for a client certificate
+func (o TLSOptions) ClientConfig() (*tls.Config, error) {
+	cfg, err := o.config()
+	if err != nil {
+		return nil, err
+	}
+	cfg.ServerName = o.ServerName
+	return cfg, nil
+}
+
+// config loads the certificate, CA pool and profile shared by both sides
+func (o TLSOptions) config() (*tls.Config, error) {
+	cfg := &tls.Config{}
+	if err := o.Profile.Apply(cfg); err != nil {
+		return nil, err
+	}
+	if o.CertFile != "" || o.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("dicos/net: loading TLS certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	if o.CAFile != "" {
+		pem, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("dicos/net: reading CAs: %w", err)
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("dicos/net: no certificates in %s", o.CAFile)
+		}
+	}
+	return cfg, nil
+}
+
+// logTLS logs the negotiated version and suite and the peer's subject, and
+// at debug level each certificate of the peer's chain
+func logTLS(log *slog.Logger, state tls.ConnectionState) {
+	attrs := []any{
+		slog.String("tls_version", tls.VersionName(state.Version)),
+		slog.String("cipher_suite", tls.CipherSuiteName(state.CipherSuite)),
+	}
+	if len(state.PeerCertificates) > 0 {
+		leaf := state.PeerCertificates[0]
+		attrs = append(attrs, slog.String("peer", leaf.Subject.String()), slog.String("peer_issuer", leaf.Issuer.String()))
+	}
+	log.Info("TLS established", attrs...)
+	if !log.Enabled(context.Background(), slog.LevelDebug) {
+		return
+	}
+	for i, cert := range state.PeerCertificates {
+		text, err := util.PrettyPrintCert(cert)
+		if err != nil {
+			text = err.Error()
+		}
+		log.Debug("TLS peer certificate", slog.Int("depth", i), slog.String("certificate", text))
+	}
+}
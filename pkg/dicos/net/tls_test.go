@@ -0,0 +1,177 @@
+package net
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	stdnet "net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPKI writes a CA and a server and client certificate it signed to a
+// temporary directory, returning the paths by name: ca, scp, scp.key, scu
+// and scu.key
+func testPKI(t *testing.T) map[string]string {
+	t.Helper()
+	dir := t.TempDir()
+	paths := make(map[string]string)
+	write := func(name, typ string, der []byte) {
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(p, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600))
+		paths[name] = p
+	}
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, ca, ca, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	write("ca", "CERTIFICATE", der)
+
+	for i, name := range []string{"scp", "scu"} {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		tmpl := &x509.Certificate{
+			SerialNumber: big.NewInt(int64(i + 2)),
+			Subject:      pkix.Name{CommonName: name},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+			IPAddresses:  []stdnet.IP{stdnet.IPv4(127, 0, 0, 1)},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+		require.NoError(t, err)
+		write(name, "CERTIFICATE", der)
+		keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+		require.NoError(t, err)
+		write(name+".key", "PRIVATE KEY", keyDER)
+	}
+	return paths
+}
+
+func TestServer_TLS(t *testing.T) {
+	pki := testPKI(t)
+	serverCfg, err := TLSOptions{
+		CertFile: pki["scp"], KeyFile: pki["scp.key"], CAFile: pki["ca"],
+		Profile: TLSProfileNonDowngradingBCP195, VerifyClient: true,
+	}.ServerConfig()
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var got []*StoreRequest
+	addr := startServer(t, &Server{
+		TLSConfig: serverCfg,
+		Handler: func(ctx context.Context, req *StoreRequest) error {
+			mu.Lock()
+			defer mu.Unlock()
+			got = append(got, req)
+			return nil
+		},
+	})
+
+	clientCfg, err := TLSOptions{
+		CertFile: pki["scu"], KeyFile: pki["scu.key"], CAFile: pki["ca"],
+		Profile: TLSProfileNonDowngradingBCP195,
+	}.ClientConfig()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	assoc, err := Dial(ctx, addr, ClientOptions{TLSConfig: clientCfg})
+	require.NoError(t, err)
+	state, ok := assoc.TLS()
+	require.True(t, ok)
+	assert.GreaterOrEqual(t, state.Version, uint16(tls.VersionTLS12))
+	assert.Equal(t, "scp", state.PeerCertificates[0].Subject.CommonName)
+
+	require.NoError(t, assoc.Echo(ctx))
+	require.NoError(t, assoc.Store(ctx, testCT(t, nil)))
+	require.NoError(t, assoc.Release())
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, got, 1)
+	require.Len(t, got[0].PeerCertificates, 2, "client leaf and CA")
+	assert.Equal(t, "scu", got[0].PeerCertificates[0].Subject.CommonName)
+	assert.Equal(t, "Test CA", got[0].PeerCertificates[1].Subject.CommonName)
+}
+
+func TestServer_TLSRejected(t *testing.T) {
+	pki := testPKI(t)
+	serverCfg, err := TLSOptions{
+		CertFile: pki["scp"], KeyFile: pki["scp.key"], CAFile: pki["ca"],
+		Profile: TLSProfileExtendedBCP195, VerifyClient: true,
+	}.ServerConfig()
+	require.NoError(t, err)
+	addr := startServer(t, &Server{
+		TLSConfig: serverCfg,
+		Timeout:   5 * time.Second,
+		Handler:   func(ctx context.Context, req *StoreRequest) error { return nil },
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// no client certificate
+	clientCfg, err := TLSOptions{CAFile: pki["ca"]}.ClientConfig()
+	require.NoError(t, err)
+	_, err = Dial(ctx, addr, ClientOptions{TLSConfig: clientCfg})
+	assert.Error(t, err)
+
+	// below the TLS 1.3 minimum of the extended profile
+	clientCfg, err = TLSOptions{CertFile: pki["scu"], KeyFile: pki["scu.key"], CAFile: pki["ca"]}.ClientConfig()
+	require.NoError(t, err)
+	clientCfg.MaxVersion = tls.VersionTLS12
+	_, err = Dial(ctx, addr, ClientOptions{TLSConfig: clientCfg})
+	assert.Error(t, err)
+
+	// an untrusted server
+	clientCfg, err = TLSOptions{CertFile: pki["scu"], KeyFile: pki["scu.key"]}.ClientConfig()
+	require.NoError(t, err)
+	_, err = Dial(ctx, addr, ClientOptions{TLSConfig: clientCfg})
+	assert.Error(t, err)
+
+	// the same client within the profile is accepted
+	clientCfg.RootCAs = serverCfg.ClientCAs
+	assoc, err := Dial(ctx, addr, ClientOptions{TLSConfig: clientCfg})
+	require.NoError(t, err)
+	require.NoError(t, assoc.Echo(ctx))
+	require.NoError(t, assoc.Release())
+}
+
+func TestTLSProfile_Apply(t *testing.T) {
+	for _, p := range TLSProfiles {
+		cfg := &tls.Config{}
+		require.NoError(t, p.Apply(cfg), p)
+		assert.GreaterOrEqual(t, cfg.MinVersion, uint16(tls.VersionTLS12), p)
+	}
+	cfg := &tls.Config{}
+	require.NoError(t, TLSProfileNonDowngradingBCP195.Apply(cfg))
+	assert.Equal(t, bcp195Suites, cfg.CipherSuites)
+	assert.ErrorContains(t, TLSProfile("ssl3").Apply(cfg), "unknown TLS profile")
+
+	_, err := TLSOptions{}.ServerConfig()
+	assert.Error(t, err)
+	_, err = TLSOptions{CAFile: filepath.Join(t.TempDir(), "missing.pem")}.ClientConfig()
+	assert.Error(t, err)
+}
@@ -0,0 +1,114 @@
+package dicos
+
+import (
+	"math"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// VoxelSpacing is the physical size of one pixel/voxel step in mm
+type VoxelSpacing struct {
+	Row   float64 // spacing between rows (Y)
+	Col   float64 // spacing between columns (X)
+	Slice float64 // spacing between frames (Z)
+}
+
+// SpacingFromDataset returns the voxel spacing of an image dataset.
+// In-plane spacing is calibrated per GetCalibratedSpacing; slice spacing prefers
+// Spacing Between Slices (0018,0088) over Slice Thickness (0018,0050).
+func SpacingFromDataset(ds *Dataset) VoxelSpacing {
+	sp := GetCalibratedSpacing(ds)
+	slice := GetSliceThickness(ds)
+	if v := dsValues(ds, tag.SpacingBetweenSlices); len(v) > 0 && v[0] > 0 {
+		slice = v[0]
+	}
+	return VoxelSpacing{Row: sp.Row, Col: sp.Col, Slice: slice}
+}
+
+// OOIDimensions is the physical extent of an object of interest
+type OOIDimensions struct {
+	Width  float64 // X extent (mm)
+	Height float64 // Y extent (mm)
+	Depth  float64 // Z extent (mm), 0 for projection images
+	Volume float64 // mm³, 0 for projection images
+}
+
+// Dimensions converts a bounding box in pixel/voxel coordinates (column, row, frame)
+// to physical dimensions. Corners are treated as continuous coordinates, so a box
+// from 0 to 10 spans ten voxels.
+func (b *BoundingBox) Dimensions(sp VoxelSpacing) OOIDimensions {
+	dx := math.Abs(float64(b.BottomRight[0]-b.TopLeft[0])) * sp.Col
+	dy := math.Abs(float64(b.BottomRight[1]-b.TopLeft[1])) * sp.Row
+	dz := math.Abs(float64(b.BottomRight[2]-b.TopLeft[2])) * sp.Slice
+	return OOIDimensions{Width: dx, Height: dy, Depth: dz, Volume: dx * dy * dz}
+}
+
+// PolygonArea returns the area in mm² of a closed in-plane polygon given in
+// pixel coordinates (column, row), using the shoelace formula.
+func PolygonArea(points [][2]float32, sp VoxelSpacing) float64 {
+	if len(points) < 3 {
+		return 0
+	}
+	var sum float64
+	for i := range points {
+		j := (i + 1) % len(points)
+		xi, yi := float64(points[i][0])*sp.Col, float64(points[i][1])*sp.Row
+		xj, yj := float64(points[j][0])*sp.Col, float64(points[j][1])*sp.Row
+		sum += xi*yj - xj*yi
+	}
+	return math.Abs(sum) / 2
+}
+
+// EstimateOOIDimensions computes the physical size of a PTO.
+// A polygon is extruded across the bounding box frame range (or one slice if there
+// is no box), giving a tighter volume than the box alone. Returns false if the
+// PTO has no geometry.
+func EstimateOOIDimensions(pto *PotentialThreatObject, sp VoxelSpacing) (OOIDimensions, bool) {
+	var dims OOIDimensions
+	hasBox := pto.BoundingBox != nil
+	if hasBox {
+		dims = pto.BoundingBox.Dimensions(sp)
+	}
+	if len(pto.Polygon) < 3 {
+		return dims, hasBox
+	}
+
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range pto.Polygon {
+		minX, maxX = math.Min(minX, float64(p[0])), math.Max(maxX, float64(p[0]))
+		minY, maxY = math.Min(minY, float64(p[1])), math.Max(maxY, float64(p[1]))
+	}
+	dims.Width = (maxX - minX) * sp.Col
+	dims.Height = (maxY - minY) * sp.Row
+	if !hasBox {
+		dims.Depth = sp.Slice
+	}
+	dims.Volume = PolygonArea(pto.Polygon, sp) * dims.Depth
+	return dims, true
+}
+
+// EstimateOOISizes fills Size and Volume on every PTO that has geometry and no
+// caller-supplied values, using the given spacing of the referenced image.
+func (tdr *ThreatDetectionReport) EstimateOOISizes(sp VoxelSpacing) {
+	for i := range tdr.PTOs {
+		tdr.PTOs[i].Size, tdr.PTOs[i].Volume = ooiSize(&tdr.PTOs[i], sp)
+	}
+}
+
+// ooiSize returns the Size and Volume of pto, estimating those left zero from
+// its geometry
+func ooiSize(pto *PotentialThreatObject, sp VoxelSpacing) ([3]float32, float32) {
+	size, volume := pto.Size, pto.Volume
+	dims, ok := EstimateOOIDimensions(pto, sp)
+	if !ok {
+		return size, volume
+	}
+	if size == ([3]float32{}) {
+		size = [3]float32{float32(dims.Width), float32(dims.Height), float32(dims.Depth)}
+	}
+	if volume == 0 {
+		volume = float32(dims.Volume)
+	}
+	return size, volume
+}
@@ -0,0 +1,84 @@
+package dicos
+
+import (
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoundingBox_Dimensions(t *testing.T) {
+	// 10x20x5 voxel cuboid phantom at 0.5mm in-plane and 2mm slices
+	box := &BoundingBox{TopLeft: [3]float32{10, 10, 3}, BottomRight: [3]float32{20, 30, 8}}
+	dims := box.Dimensions(VoxelSpacing{Row: 0.5, Col: 0.5, Slice: 2})
+
+	assert.InDelta(t, 5.0, dims.Width, 1e-6)
+	assert.InDelta(t, 10.0, dims.Height, 1e-6)
+	assert.InDelta(t, 10.0, dims.Depth, 1e-6)
+	assert.InDelta(t, 500.0, dims.Volume, 1e-6)
+}
+
+func TestEstimateOOIDimensions_Polygon(t *testing.T) {
+	sp := VoxelSpacing{Row: 1, Col: 1, Slice: 2}
+
+	// Right triangle with legs of 10 pixels extruded across 4 frames: 50mm² * 8mm
+	pto := &PotentialThreatObject{
+		BoundingBox: &BoundingBox{TopLeft: [3]float32{0, 0, 0}, BottomRight: [3]float32{10, 10, 4}},
+		Polygon:     [][2]float32{{0, 0}, {10, 0}, {0, 10}},
+	}
+	dims, ok := EstimateOOIDimensions(pto, sp)
+	require.True(t, ok)
+	assert.InDelta(t, 10.0, dims.Width, 1e-6)
+	assert.InDelta(t, 8.0, dims.Depth, 1e-6)
+	assert.InDelta(t, 400.0, dims.Volume, 1e-6)
+
+	// No geometry
+	_, ok = EstimateOOIDimensions(&PotentialThreatObject{}, sp)
+	assert.False(t, ok)
+}
+
+func TestSpacingFromDataset(t *testing.T) {
+	ds, err := NewDataset(
+		WithElement(tag.PixelSpacing, "0.7\\0.8"),
+		WithElement(tag.SliceThickness, "3"),
+		WithElement(tag.SpacingBetweenSlices, "1.5"),
+	)
+	require.NoError(t, err)
+
+	sp := SpacingFromDataset(ds)
+	assert.Equal(t, VoxelSpacing{Row: 0.7, Col: 0.8, Slice: 1.5}, sp)
+}
+
+func TestTDR_PopulatesOOISize(t *testing.T) {
+	tdr := NewThreatDetectionReport()
+	tdr.Spacing = &VoxelSpacing{Row: 0.5, Col: 0.5, Slice: 2}
+	tdr.PTOs = []PotentialThreatObject{
+		{ID: 1, BoundingBox: &BoundingBox{TopLeft: [3]float32{10, 10, 3}, BottomRight: [3]float32{20, 30, 8}}},
+		{ID: 2, Size: [3]float32{1, 2, 3}, Mass: 250, BoundingBox: &BoundingBox{BottomRight: [3]float32{4, 4, 4}}},
+	}
+
+	ds, err := tdr.GetDataset()
+	require.NoError(t, err)
+	assert.Zero(t, tdr.PTOs[0].Size, "GetDataset leaves the PTOs unchanged")
+	assert.Zero(t, tdr.PTOs[0].Volume)
+
+	items := GetSequenceItems(ds, tag.PTOSequence)
+	require.Len(t, items, 2)
+	assert.Equal(t, []float32{5, 10, 10}, items[0].Elements[tag.OOISize].Value)
+	assert.Equal(t, float32(500), items[0].Elements[tag.OOIVolume].Value)
+	assert.Equal(t, []float32{1, 2, 3}, items[1].Elements[tag.OOISize].Value, "caller-supplied size is kept")
+	assert.Equal(t, float32(250), items[1].Elements[tag.OOIMass].Value)
+
+	tdr.EstimateOOISizes(*tdr.Spacing)
+	assert.Equal(t, [3]float32{5, 10, 10}, tdr.PTOs[0].Size)
+	assert.InDelta(t, 500.0, tdr.PTOs[0].Volume, 1e-3)
+	assert.Equal(t, [3]float32{1, 2, 3}, tdr.PTOs[1].Size)
+
+	parsed, err := ParseTDR(ds)
+	require.NoError(t, err)
+	assert.Equal(t, [3]float32{5, 10, 10}, parsed.PTOs[0].Size)
+	assert.Equal(t, float32(500), parsed.PTOs[0].Volume)
+	assert.Equal(t, float32(250), parsed.PTOs[1].Mass)
+	assert.NotEqual(t, tag.OOISize, tag.BoundingBoxBottomRight)
+}
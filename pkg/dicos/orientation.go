@@ -0,0 +1,190 @@
+package dicos
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Convention is the handedness of a world coordinate system. DICOM and DICOS
+// use LPS: +X towards the patient's (object's) left, +Y posterior and +Z
+// superior. NIfTI and most neuroimaging and ML tools use RAS, which negates X
+// and Y.
+type Convention int
+
+const (
+	LPS Convention = iota // DICOM/DICOS patient coordinates, also ITK
+	RAS                   // NIfTI, 3D Slicer, nibabel
+)
+
+// String returns the convention name
+func (c Convention) String() string {
+	switch c {
+	case LPS:
+		return "LPS"
+	case RAS:
+		return "RAS"
+	}
+	return fmt.Sprintf("Convention(%d)", int(c))
+}
+
+// ConvertPoint converts a world coordinate between conventions. The
+// conversion negates X and Y, so it is its own inverse.
+func ConvertPoint(p [3]float64, from, to Convention) [3]float64 {
+	if from == to {
+		return p
+	}
+	return [3]float64{-p[0], -p[1], p[2]}
+}
+
+// ConvertAffine converts a voxel-to-world matrix between conventions
+func ConvertAffine(m [4][4]float64, from, to Convention) [4][4]float64 {
+	if from == to {
+		return m
+	}
+	for r := 0; r < 2; r++ {
+		for c := range m[r] {
+			m[r][c] = -m[r][c]
+		}
+	}
+	return m
+}
+
+// Direction returns the LPS direction matrix of the volume: column 0 is the
+// direction of increasing X (along a row), column 1 of increasing Y (down the
+// columns) and column 2 of increasing Z, the slice normal. This is the layout
+// of an ITK/SimpleITK image direction.
+func (v *Volume) Direction() [3][3]float64 {
+	row, col, normal := v.axes()
+	var d [3][3]float64
+	for i := 0; i < 3; i++ {
+		d[i][0], d[i][1], d[i][2] = row[i], col[i], normal[i]
+	}
+	return d
+}
+
+// Affine returns the 4x4 matrix mapping a voxel index (x, y, z, 1) to world
+// coordinates in mm in the given convention. Affine(RAS) is the NIfTI
+// sform/qform of the volume when the data is written X fastest.
+func (v *Volume) Affine(conv Convention) [4][4]float64 {
+	d := v.Direction()
+	spacing := [3]float64{v.SpacingX, v.SpacingY, v.SpacingZ}
+	origin := [3]float64{v.OriginX, v.OriginY, v.OriginZ}
+	var m [4][4]float64
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			m[r][c] = d[r][c] * spacing[c]
+		}
+		m[r][3] = origin[r]
+	}
+	m[3][3] = 1
+	return ConvertAffine(m, LPS, conv)
+}
+
+// VoxelToWorld returns the world coordinate of voxel (x, y, z) in the given
+// convention
+func (v *Volume) VoxelToWorld(x, y, z float64, conv Convention) [3]float64 {
+	m := v.Affine(conv)
+	var p [3]float64
+	for r := range p {
+		p[r] = m[r][0]*x + m[r][1]*y + m[r][2]*z + m[r][3]
+	}
+	return p
+}
+
+// WorldToVoxel returns the voxel position, possibly fractional or outside
+// the volume, of world coordinate p in the given convention. It inverts
+// VoxelToWorld for orthogonal row and column directions.
+func (v *Volume) WorldToVoxel(p [3]float64, conv Convention) [3]float64 {
+	p = ConvertPoint(p, conv, LPS)
+	d := v.Direction()
+	rel := [3]float64{p[0] - v.OriginX, p[1] - v.OriginY, p[2] - v.OriginZ}
+	spacing := [3]float64{v.SpacingX, v.SpacingY, v.SpacingZ}
+	var idx [3]float64
+	for c := range idx {
+		dot := d[0][c]*rel[0] + d[1][c]*rel[1] + d[2][c]*rel[2]
+		if spacing[c] != 0 {
+			idx[c] = dot / spacing[c]
+		}
+	}
+	return idx
+}
+
+// AxisCodes returns the anatomical direction each voxel axis points towards,
+// one letter per axis, e.g. "LPS" for an axial volume with rows running to
+// the left, columns to the back and slices towards the head. The letters name
+// directions, not coordinate signs, so they are the same in LPS and RAS; an
+// NIfTI reader reports the same codes for Affine(RAS). Oblique axes are
+// labeled by their dominant component.
+func (v *Volume) AxisCodes() string {
+	var b strings.Builder
+	for _, label := range v.AxisLabels() {
+		b.WriteByte(label[0])
+	}
+	return b.String()
+}
+
+// AxisLabels returns the anatomical direction of each voxel axis with every
+// significant component, largest first, e.g. "LP" for a row direction between
+// left and posterior. This is the DICOM patient orientation labeling.
+func (v *Volume) AxisLabels() [3]string {
+	row, col, normal := v.axes()
+	return [3]string{axisLabel(row), axisLabel(col), axisLabel(normal)}
+}
+
+// axes returns the unit row, column and slice normal directions from the
+// Orientation cosines, falling back to the identity when they are unset
+func (v *Volume) axes() (row, col, normal [3]float64) {
+	copy(row[:], v.Orientation[:3])
+	copy(col[:], v.Orientation[3:])
+	row, okRow := unit(row)
+	col, okCol := unit(col)
+	if !okRow || !okCol {
+		row, col = [3]float64{1, 0, 0}, [3]float64{0, 1, 0}
+	}
+	normal = [3]float64{
+		row[1]*col[2] - row[2]*col[1],
+		row[2]*col[0] - row[0]*col[2],
+		row[0]*col[1] - row[1]*col[0],
+	}
+	normal, _ = unit(normal)
+	return row, col, normal
+}
+
+// unit normalizes d, reporting false for a zero vector
+func unit(d [3]float64) ([3]float64, bool) {
+	n := math.Sqrt(d[0]*d[0] + d[1]*d[1] + d[2]*d[2])
+	if n < 1e-9 {
+		return d, false
+	}
+	return [3]float64{d[0] / n, d[1] / n, d[2] / n}, true
+}
+
+// axisLabel names the directions the LPS vector d points towards, largest
+// component first, ignoring components under 0.0001
+func axisLabel(d [3]float64) string {
+	pos, neg := [3]byte{'L', 'P', 'S'}, [3]byte{'R', 'A', 'I'}
+	var b strings.Builder
+	used := [3]bool{}
+	for range d {
+		best := -1
+		for i, c := range d {
+			if !used[i] && math.Abs(c) > 0.0001 && (best < 0 || math.Abs(c) > math.Abs(d[best])) {
+				best = i
+			}
+		}
+		if best < 0 {
+			break
+		}
+		used[best] = true
+		if d[best] > 0 {
+			b.WriteByte(pos[best])
+		} else {
+			b.WriteByte(neg[best])
+		}
+	}
+	if b.Len() == 0 {
+		return "?"
+	}
+	return b.String()
+}
@@ -0,0 +1,76 @@
+package dicos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVolume_AffineConventions(t *testing.T) {
+	v := NewVolume(4, 3, 2)
+	v.SpacingX, v.SpacingY, v.SpacingZ = 0.5, 0.75, 2
+	v.OriginX, v.OriginY, v.OriginZ = 10, 20, 30
+
+	assert.Equal(t, "LPS", v.AxisCodes())
+	lps := v.Affine(LPS)
+	assert.Equal(t, [4][4]float64{
+		{0.5, 0, 0, 10},
+		{0, 0.75, 0, 20},
+		{0, 0, 2, 30},
+		{0, 0, 0, 1},
+	}, lps)
+	assert.Equal(t, [4][4]float64{
+		{-0.5, 0, 0, -10},
+		{0, -0.75, 0, -20},
+		{0, 0, 2, 30},
+		{0, 0, 0, 1},
+	}, v.Affine(RAS))
+
+	p := v.VoxelToWorld(1, 2, 1, LPS)
+	assert.Equal(t, [3]float64{10.5, 21.5, 32}, p)
+	assert.Equal(t, ConvertPoint(p, LPS, RAS), v.VoxelToWorld(1, 2, 1, RAS))
+	assert.Equal(t, p, ConvertPoint(ConvertPoint(p, LPS, RAS), RAS, LPS))
+}
+
+func TestVolume_AxisLabels(t *testing.T) {
+	tests := []struct {
+		name        string
+		orientation [6]float64
+		codes       string
+		labels      [3]string
+	}{
+		{"axial", [6]float64{1, 0, 0, 0, 1, 0}, "LPS", [3]string{"L", "P", "S"}},
+		{"coronal", [6]float64{1, 0, 0, 0, 0, -1}, "LIP", [3]string{"L", "I", "P"}},
+		{"sagittal", [6]float64{0, 1, 0, 0, 0, -1}, "PIR", [3]string{"P", "I", "R"}},
+		{"flipped", [6]float64{-1, 0, 0, 0, -1, 0}, "RAS", [3]string{"R", "A", "S"}},
+		{"oblique", [6]float64{0.8, 0.6, 0, -0.6, 0.8, 0}, "LPS", [3]string{"LP", "PR", "S"}},
+		{"unset", [6]float64{}, "LPS", [3]string{"L", "P", "S"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVolume(2, 2, 2)
+			v.Orientation = tt.orientation
+			assert.Equal(t, tt.codes, v.AxisCodes())
+			assert.Equal(t, tt.labels, v.AxisLabels())
+
+			d := v.Direction()
+			for c := 0; c < 3; c++ {
+				n := d[0][c]*d[0][c] + d[1][c]*d[1][c] + d[2][c]*d[2][c]
+				assert.InDelta(t, 1, n, 1e-9, "column %d is not a unit vector", c)
+			}
+		})
+	}
+}
+
+func TestVolume_TransformKeepsWorldCoordinates(t *testing.T) {
+	v := NewVolume(3, 2, 1)
+	v.SpacingX, v.SpacingY = 0.5, 2
+	v.OriginX, v.OriginY = 5, -5
+	want := v.VoxelToWorld(2, 0, 0, RAS)
+
+	out := v.Transform(Rotate90)
+	// Rotate90 moves column c of row r to column rows-1-r of row c
+	got := out.VoxelToWorld(1, 2, 0, RAS)
+	assert.InDeltaSlice(t, want[:], got[:], 1e-9)
+	assert.Equal(t, "ALS", out.AxisCodes())
+}
@@ -0,0 +1,221 @@
+package dicos
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// OverlayOptions controls how RenderTDR draws threats over a frame
+type OverlayOptions struct {
+	Window     *Window     // display window for RenderTDRFrame; nil uses WindowFromDataset
+	Color      color.Color // box, outline and label color; nil is red
+	LineWidth  int         // 0 scales with the image width
+	HideLabels bool        // skip the category and probability labels
+}
+
+// Frames returns the first and last frame a PTO spans, from the Z extent of
+// its bounding box. ok is false for a PTO without a bounding box.
+func (p PotentialThreatObject) Frames() (first, last int, ok bool) {
+	if p.BoundingBox == nil {
+		return 0, 0, false
+	}
+	lo := min(p.BoundingBox.TopLeft[2], p.BoundingBox.BottomRight[2])
+	hi := max(p.BoundingBox.TopLeft[2], p.BoundingBox.BottomRight[2])
+	return int(math.Floor(float64(lo))), int(math.Floor(float64(hi))), true
+}
+
+// OnFrame reports whether a PTO is drawn on frame: its bounding box reaches
+// the frame, or it has only an in-plane polygon, which is drawn on every frame
+func (p PotentialThreatObject) OnFrame(frame int) bool {
+	first, last, ok := p.Frames()
+	return !ok || frame >= first && frame <= last
+}
+
+// Frames returns the range of frames holding any PTO bounding box, for
+// rendering only the slices of a scan with threats on them. ok is false when
+// no PTO has a bounding box.
+func (t *ThreatDetectionReport) Frames() (first, last int, ok bool) {
+	for _, pto := range t.PTOs {
+		f, l, has := pto.Frames()
+		if !has {
+			continue
+		}
+		if !ok {
+			first, last, ok = f, l, true
+			continue
+		}
+		first, last = min(first, f), max(last, l)
+	}
+	return first, last, ok
+}
+
+// Caption returns the text drawn next to a PTO: its ID, threat category and
+// probability, e.g. "PTO 2 KNIFE 87%"
+func (p PotentialThreatObject) Caption() string {
+	text := fmt.Sprintf("PTO %d", p.ID)
+	category := p.Label
+	if category == "" {
+		category = p.OOIType
+	}
+	if category == "" && len(p.Assessments) > 0 {
+		category = p.Assessments[0].Category
+	}
+	if category != "" {
+		text += " " + category
+	}
+	if p.Probability > 0 {
+		text += fmt.Sprintf(" %.0f%%", p.Probability*100)
+	}
+	return text
+}
+
+// RenderTDR returns a color copy of a decoded, windowed frame with the
+// bounding boxes, polygons and labels of the PTOs on frame drawn over it,
+// for operator display or reports. Coordinates are (column, row) pixels of
+// the frame.
+//
+// Example:
+//
+//	img, _ := dicos.WindowFromDataset(ct).Render(data, rows, cols)
+//	out := dicos.RenderTDR(img, tdr, 12, dicos.OverlayOptions{})
+//	png.Encode(f, out)
+func RenderTDR(frame image.Image, tdr *ThreatDetectionReport, index int, opts OverlayOptions) *image.RGBA {
+	b := frame.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(out, out.Bounds(), frame, b.Min, draw.Src)
+	DrawTDR(out, tdr, index, opts)
+	return out
+}
+
+// DrawTDR draws the PTOs of tdr on frame index over img in place
+func DrawTDR(img draw.Image, tdr *ThreatDetectionReport, index int, opts OverlayOptions) {
+	fg := opts.Color
+	if fg == nil {
+		fg = color.RGBA{R: 0xFF, A: 0xFF}
+	}
+	scale := max(1, img.Bounds().Dx()/256)
+	width := opts.LineWidth
+	if width <= 0 {
+		width = scale
+	}
+
+	for _, pto := range tdr.PTOs {
+		if !pto.OnFrame(index) {
+			continue
+		}
+		var labelAt image.Point
+		if box := pto.BoundingBox; box != nil {
+			x0, x1 := pixel(min(box.TopLeft[0], box.BottomRight[0])), pixel(max(box.TopLeft[0], box.BottomRight[0]))
+			y0, y1 := pixel(min(box.TopLeft[1], box.BottomRight[1])), pixel(max(box.TopLeft[1], box.BottomRight[1]))
+			drawLine(img, x0, y0, x1, y0, width, fg)
+			drawLine(img, x1, y0, x1, y1, width, fg)
+			drawLine(img, x1, y1, x0, y1, width, fg)
+			drawLine(img, x0, y1, x0, y0, width, fg)
+			labelAt = image.Pt(x0, y0)
+		}
+		for i, p := range pto.Polygon {
+			q := pto.Polygon[(i+1)%len(pto.Polygon)]
+			drawLine(img, pixel(p[0]), pixel(p[1]), pixel(q[0]), pixel(q[1]), width, fg)
+		}
+		if pto.BoundingBox == nil && len(pto.Polygon) > 0 {
+			labelAt = image.Pt(pixel(pto.Polygon[0][0]), pixel(pto.Polygon[0][1]))
+		}
+		if opts.HideLabels || pto.BoundingBox == nil && len(pto.Polygon) == 0 {
+			continue
+		}
+		// above the outline, or inside it at the top of the image
+		y := labelAt.Y - 7*scale
+		if y < img.Bounds().Min.Y+scale {
+			y = labelAt.Y + width + scale
+		}
+		drawText(img, labelAt.X+scale, y, scale, pto.Caption(), fg, color.Black)
+	}
+}
+
+// RenderTDRFrame decodes and windows frame of a grayscale scan and draws the
+// PTOs of tdr on it, for rendering the slices of Frames one by one
+//
+// Example:
+//
+//	first, last, ok := tdr.Frames()
+//	for i := first; ok && i <= last; i++ {
+//		img, err := dicos.RenderTDRFrame(ctx, ct, tdr, i, dicos.OverlayOptions{})
+//	}
+func RenderTDRFrame(ctx context.Context, ds *Dataset, tdr *ThreatDetectionReport, frame int, opts OverlayOptions) (*image.RGBA, error) {
+	if spp := ds.SamplesPerPixel(); spp != 1 {
+		return nil, fmt.Errorf("rendering %d samples per pixel is not supported", spp)
+	}
+	rows, cols := ds.Rows(), ds.Columns()
+	if rows == 0 || cols == 0 {
+		return nil, fmt.Errorf("invalid image dimensions: %dx%d", cols, rows)
+	}
+	pd, err := ds.GetPixelDataContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	data, err := DecodeFrameDataContext(ctx, pd, frame, rows, cols, ds.TransferSyntax())
+	if err != nil {
+		return nil, err
+	}
+	win := WindowFromDataset(ds)
+	if opts.Window != nil {
+		win = *opts.Window
+	}
+	img, err := win.Render(data, rows, cols)
+	if err != nil {
+		return nil, err
+	}
+	return RenderTDR(img, tdr, frame, opts), nil
+}
+
+// pixel rounds a TDR coordinate to the nearest pixel
+func pixel(c float32) int {
+	return int(math.Round(float64(c)))
+}
+
+// drawLine draws a line from (x0, y0) to (x1, y1) with a square pen of
+// width pixels, clipped to img
+func drawLine(img draw.Image, x0, y0, x1, y1, width int, c color.Color) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	bounds := img.Bounds()
+	off := (width - 1) / 2
+	for e := dx + dy; ; {
+		pen := image.Rect(x0-off, y0-off, x0-off+width, y0-off+width).Intersect(bounds)
+		for y := pen.Min.Y; y < pen.Max.Y; y++ {
+			for x := pen.Min.X; x < pen.Max.X; x++ {
+				img.Set(x, y, c)
+			}
+		}
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		// Bresenham step
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,113 @@
+package dicos
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func overlayTDR() *ThreatDetectionReport {
+	tdr := NewThreatDetectionReport()
+	tdr.PTOs = []PotentialThreatObject{
+		{
+			ID: 1, Label: "Knife", Probability: 0.87,
+			BoundingBox: &BoundingBox{TopLeft: [3]float32{40, 30, 2}, BottomRight: [3]float32{20, 50, 4}},
+		},
+		{
+			ID: 2, OOIType: "EXPLOSIVE",
+			BoundingBox: &BoundingBox{TopLeft: [3]float32{5, 5, 7}, BottomRight: [3]float32{10, 10, 9.5}},
+		},
+		{ID: 3, Polygon: [][2]float32{{60, 60}, {90, 60}, {75, 90}}},
+	}
+	return tdr
+}
+
+func TestTDR_Frames(t *testing.T) {
+	tdr := overlayTDR()
+	first, last, ok := tdr.PTOs[0].Frames()
+	assert.True(t, ok)
+	assert.Equal(t, [2]int{2, 4}, [2]int{first, last})
+
+	_, _, ok = tdr.PTOs[2].Frames()
+	assert.False(t, ok)
+	assert.True(t, tdr.PTOs[2].OnFrame(100), "a polygon without a box is on every frame")
+	assert.True(t, tdr.PTOs[1].OnFrame(9))
+	assert.False(t, tdr.PTOs[1].OnFrame(10))
+
+	first, last, ok = tdr.Frames()
+	assert.True(t, ok)
+	assert.Equal(t, [2]int{2, 9}, [2]int{first, last})
+
+	_, _, ok = NewThreatDetectionReport().Frames()
+	assert.False(t, ok)
+}
+
+func TestPTO_Caption(t *testing.T) {
+	tdr := overlayTDR()
+	assert.Equal(t, "PTO 1 Knife 87%", tdr.PTOs[0].Caption())
+	assert.Equal(t, "PTO 2 EXPLOSIVE", tdr.PTOs[1].Caption())
+	assert.Equal(t, "PTO 3", tdr.PTOs[2].Caption())
+	pto := PotentialThreatObject{ID: 4, Assessments: []ATDAssessment{{Category: "GUN"}}}
+	assert.Equal(t, "PTO 4 GUN", pto.Caption())
+}
+
+func TestRenderTDR(t *testing.T) {
+	frame := image.NewGray(image.Rect(0, 0, 100, 100))
+	for i := range frame.Pix {
+		frame.Pix[i] = 0x40
+	}
+	red := color.RGBA{R: 0xFF, A: 0xFF}
+	gray := color.RGBA{R: 0x40, G: 0x40, B: 0x40, A: 0xFF}
+
+	out := RenderTDR(frame, overlayTDR(), 3, OverlayOptions{})
+	require.Equal(t, frame.Bounds(), out.Bounds())
+	// corners and edges of the first box, whatever its corner order
+	for _, p := range []image.Point{{20, 30}, {40, 50}, {30, 30}, {40, 40}, {20, 45}} {
+		assert.Equal(t, red, out.RGBAAt(p.X, p.Y), "box pixel %v", p)
+	}
+	assert.Equal(t, gray, out.RGBAAt(30, 40), "inside the box")
+	// the second box is on frames 7-9 only
+	assert.Equal(t, gray, out.RGBAAt(5, 5))
+	// the polygon outline, including its diagonal edges
+	for _, p := range []image.Point{{60, 60}, {75, 60}, {90, 60}, {75, 90}} {
+		assert.Equal(t, red, out.RGBAAt(p.X, p.Y), "polygon pixel %v", p)
+	}
+	assert.True(t, hasColor(out, image.Rect(20, 22, 60, 29), red), "label above the box")
+	// the source frame is untouched
+	assert.Equal(t, uint8(0x40), frame.GrayAt(20, 30).Y)
+
+	blue := color.RGBA{B: 0xFF, A: 0xFF}
+	out = RenderTDR(frame, overlayTDR(), 8, OverlayOptions{Color: blue, LineWidth: 3, HideLabels: true})
+	assert.Equal(t, gray, out.RGBAAt(20, 30), "first box is off frame 8")
+	assert.Equal(t, blue, out.RGBAAt(4, 7), "thick pen")
+	assert.Equal(t, blue, out.RGBAAt(5, 5))
+	assert.False(t, hasColor(out, image.Rect(60, 48, 100, 58), blue), "no labels")
+}
+
+func TestRenderTDRFrame(t *testing.T) {
+	ds := exportDataset(t, 16, 12, 0, ramp(16, 100))
+	tdr := NewThreatDetectionReport()
+	tdr.PTOs = []PotentialThreatObject{{ID: 1, BoundingBox: &BoundingBox{BottomRight: [3]float32{3, 0, 0}}}}
+
+	out, err := RenderTDRFrame(context.Background(), ds, tdr, 0, OverlayOptions{Window: &Window{Center: 750, Width: 1500}, HideLabels: true})
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 4, 4), out.Bounds())
+	assert.Equal(t, color.RGBA{R: 0xFF, A: 0xFF}, out.RGBAAt(3, 0))
+	// stored 400 is 400/1500 of the way through the window
+	assert.Equal(t, color.RGBA{R: 68, G: 68, B: 68, A: 0xFF}, out.RGBAAt(0, 1))
+}
+
+func hasColor(img *image.RGBA, r image.Rectangle, c color.RGBA) bool {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if img.RGBAAt(x, y) == c {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package dicos
+
+import (
+	"fmt"
+	"io"
+)
+
+// FragmentPadding is the policy for padding the fragments of encapsulated
+// pixel data. PS3.5 A.4 requires every fragment to have an even length, so by
+// default an odd codestream gets one trailing 0x00 byte. Some decoders reject
+// anything after the end-of-image marker and need the codestream as encoded.
+//
+// Native pixel data is always written word aligned, padded with 0x00 to an
+// even length. The reader reports odd fragments as a ParseIssue and keeps them
+// unpadded (PadNone) so a rewrite reproduces the source.
+type FragmentPadding int
+
+const (
+	PadEven FragmentPadding = iota // append 0x00 to odd-length fragments
+	PadNone                        // write fragments exactly as encoded
+)
+
+// String returns the policy name
+func (p FragmentPadding) String() string {
+	switch p {
+	case PadEven:
+		return "even"
+	case PadNone:
+		return "none"
+	}
+	return fmt.Sprintf("FragmentPadding(%d)", int(p))
+}
+
+// FragmentPadder is implemented by codecs whose fragments need a padding
+// policy other than PadEven. The built-in codecs use PadEven.
+type FragmentPadder interface {
+	FragmentPadding() FragmentPadding
+}
+
+// CodecWithPadding returns c with the fragment padding policy p, for
+// receivers that require a policy the codec does not use by default.
+//
+// Example:
+//
+//	ct.Codec = dicos.CodecWithPadding(dicos.CodecJPEGLS, dicos.PadNone)
+func CodecWithPadding(c Codec, p FragmentPadding) Codec {
+	pc := paddedCodec{Codec: c, padding: p}
+	if se, ok := c.(SampleEncoder); ok {
+		return paddedSampleCodec{paddedCodec: pc, se: se}
+	}
+	return pc
+}
+
+// paddedCodec overrides the padding policy of a codec
+type paddedCodec struct {
+	Codec
+	padding FragmentPadding
+}
+
+func (c paddedCodec) FragmentPadding() FragmentPadding {
+	return c.padding
+}
+
+// paddedSampleCodec keeps the SampleEncoder of the codec it wraps
+type paddedSampleCodec struct {
+	paddedCodec
+	se SampleEncoder
+}
+
+func (c paddedSampleCodec) EncodeSamples(w io.Writer, data []uint16, width, height int, f SampleFormat) error {
+	return c.se.EncodeSamples(w, data, width, height, f)
+}
+
+// paddingOf returns the padding policy of codec c
+func paddingOf(c Codec) FragmentPadding {
+	if fp, ok := c.(FragmentPadder); ok {
+		return fp.FragmentPadding()
+	}
+	return PadEven
+}
+
+// padFragment applies policy p to one encapsulated fragment
+func padFragment(b []byte, p FragmentPadding) []byte {
+	if p == PadNone {
+		return b
+	}
+	return padEven(b)
+}
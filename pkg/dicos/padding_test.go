@@ -0,0 +1,119 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// oddCodec encodes every frame as three bytes, like a codestream that ends on
+// an odd boundary
+type oddCodec struct{}
+
+func (oddCodec) Encode(w io.Writer, img image.Image) error {
+	_, err := w.Write([]byte{0xFF, 0xD9, 0x01})
+	return err
+}
+
+func (oddCodec) Decode(data []byte, width, height int) (image.Image, error) {
+	return image.NewGray16(image.Rect(0, 0, width, height)), nil
+}
+
+func (oddCodec) Name() string              { return "odd" }
+func (oddCodec) TransferSyntaxUID() string { return string(JPEGLSLossless) }
+
+func writeOddFrames(t *testing.T, codec Codec) []byte {
+	t.Helper()
+	ds, err := NewDataset(
+		WithFileMeta(DICOSCTImageStorageUID, "1.2.3.4", codec.TransferSyntaxUID()),
+		WithPixelData(2, 2, 16, make([]uint16, 8), codec),
+	)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	_, err = Write(&buf, ds)
+	require.NoError(t, err)
+	return buf.Bytes()
+}
+
+func TestFragmentPadding_Even(t *testing.T) {
+	data := writeOddFrames(t, oddCodec{})
+	ds, issues, err := ParseWithIssues(context.Background(), bytes.NewReader(data), ParseOptions{Strict: true})
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+
+	pd, err := ds.GetPixelData()
+	require.NoError(t, err)
+	require.Len(t, pd.Frames, 2)
+	assert.Equal(t, []byte{0xFF, 0xD9, 0x01, 0x00}, pd.Frames[0].CompressedData)
+	assert.Equal(t, []uint32{0, 12}, pd.Offsets)
+	assert.Equal(t, PadEven, pd.Padding)
+}
+
+func TestFragmentPadding_None(t *testing.T) {
+	codec := CodecWithPadding(oddCodec{}, PadNone)
+	data := writeOddFrames(t, codec)
+
+	// Odd fragments are reported, and an error in strict mode
+	_, _, err := ParseWithIssues(context.Background(), bytes.NewReader(data), ParseOptions{Strict: true})
+	var issue ParseIssue
+	require.True(t, errors.As(err, &issue))
+	assert.Contains(t, issue.Message, "odd length 3 of fragment 0")
+
+	ds, issues, err := ParseWithIssues(context.Background(), bytes.NewReader(data), ParseOptions{})
+	require.NoError(t, err)
+	assert.Len(t, issues, 2)
+	pd, err := ds.GetPixelData()
+	require.NoError(t, err)
+	assert.Equal(t, PadNone, pd.Padding)
+	assert.Equal(t, []byte{0xFF, 0xD9, 0x01}, pd.Frames[1].CompressedData)
+	assert.Equal(t, []uint32{0, 11}, pd.Offsets)
+
+	// A rewrite keeps the source padding until the policy is changed
+	pd = mustPixelData(t, rewrite(t, ds))
+	assert.Len(t, pd.Frames[0].CompressedData, 3)
+
+	ds.Elements[pixelDataTag].Value.(*PixelData).Padding = PadEven
+	pd = mustPixelData(t, rewrite(t, ds))
+	assert.Len(t, pd.Frames[0].CompressedData, 4)
+	assert.Equal(t, []uint32{0, 12}, pd.Offsets, "offsets follow the padded fragments")
+}
+
+func TestCodecWithPadding(t *testing.T) {
+	for _, c := range []Codec{CodecJPEGLS, CodecJPEGLi, CodecRLE, CodecJPEG2000} {
+		assert.Equal(t, PadEven, paddingOf(c), c.Name())
+		padded := CodecWithPadding(c, PadNone)
+		assert.Equal(t, PadNone, paddingOf(padded), c.Name())
+		assert.Equal(t, c.Name(), padded.Name())
+		_, wasSample := c.(SampleEncoder)
+		_, isSample := padded.(SampleEncoder)
+		assert.Equal(t, wasSample, isSample, c.Name())
+	}
+}
+
+func TestNativePixelData_WordAligned(t *testing.T) {
+	ds, err := NewDataset(
+		WithFileMeta(DICOSCTImageStorageUID, "1.2.3.4", string(ExplicitVRLittleEndian)),
+		withVR(pixelDataTag, "OB", []byte{1, 2, 3}),
+	)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	_, err = Write(&buf, ds)
+	require.NoError(t, err)
+
+	_, issues, err := ParseWithIssues(context.Background(), bytes.NewReader(buf.Bytes()), ParseOptions{Strict: true})
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+}
+
+func mustPixelData(t *testing.T, ds *Dataset) *PixelData {
+	t.Helper()
+	pd, err := ds.GetPixelData()
+	require.NoError(t, err)
+	return pd
+}
@@ -0,0 +1,232 @@
+package dicos
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/module"
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/jpfielding/dicos.go/pkg/dicos/transfer"
+)
+
+// PDFMIMEType is the MIME Type of Encapsulated Document of an Encapsulated
+// PDF
+const PDFMIMEType = "application/pdf"
+
+// EncapsulatedPDF represents an Encapsulated PDF IOD: a document such as a
+// scan-level inspection report, stored and transmitted in the same study as
+// the images and TDRs it describes.
+type EncapsulatedPDF struct {
+	// Modules
+	Patient   module.PatientModule
+	Study     module.GeneralStudyModule
+	Series    module.GeneralSeriesModule
+	Equipment module.GeneralEquipmentModule
+	SOPCommon module.SOPCommonModule
+
+	// SC Equipment and Encapsulated Document attributes
+	ConversionType      string // WSD (workstation) by default
+	InstanceNumber      int
+	ContentDate         module.Date
+	ContentTime         module.Time
+	AcquisitionDateTime string // DT; empty when the document was not acquired
+	DocumentTitle       string
+
+	// BurnedInAnnotation is set when the document identifies the subject,
+	// such as a report naming the passenger, so anonymizers can tell
+	BurnedInAnnotation bool
+
+	// SourceInstances are the instances the document was made from
+	SourceInstances []SourceImage
+
+	// Document is the PDF file
+	Document []byte
+
+	// Additional Tags (Generic support for tags not explicitly defined)
+	AdditionalTags map[tag.Tag]interface{}
+}
+
+// NewEncapsulatedPDF creates a new Encapsulated PDF with default values
+func NewEncapsulatedPDF() *EncapsulatedPDF {
+	t := time.Now()
+	return &EncapsulatedPDF{
+		ConversionType: "WSD",
+		ContentDate:    module.NewDate(t),
+		ContentTime:    module.NewTime(t),
+		Series:         module.GeneralSeriesModule{Modality: "DOC"},
+		Study:          module.NewGeneralStudyModule(),
+		SOPCommon:      module.NewSOPCommonModule(),
+		AdditionalTags: make(map[tag.Tag]interface{}),
+	}
+}
+
+// AttachTo places the document in the study of ds: the patient and study are
+// copied from it, and it is referenced as a source instance.
+//
+// Example:
+//
+//	doc := dicos.NewEncapsulatedPDF()
+//	doc.Document, _ = os.ReadFile("report.pdf")
+//	doc.DocumentTitle = "Inspection Report"
+//	if err := doc.AttachTo(ct); err != nil {
+//		return err
+//	}
+//	doc.Write("report.dcs")
+func (doc *EncapsulatedPDF) AttachTo(ds *Dataset) error {
+	src, err := sourceInstance(ds)
+	if err != nil {
+		return err
+	}
+	doc.Patient = readPatientModule(ds)
+	doc.Study = readStudyModule(ds)
+	doc.SourceInstances = append(doc.SourceInstances, src)
+	return nil
+}
+
+// GetDataset builds and returns the DICOS Dataset
+func (doc *EncapsulatedPDF) GetDataset() (*Dataset, error) {
+	if !bytes.HasPrefix(doc.Document, []byte("%PDF-")) {
+		return nil, fmt.Errorf("document is not a PDF")
+	}
+	opts := make([]Option, 0, 32)
+
+	// 1. File Meta Information
+	if doc.SOPCommon.SOPInstanceUID == "" {
+		doc.SOPCommon.SOPInstanceUID = NewUID()
+	}
+	doc.SOPCommon.SOPClassUID = EncapsulatedPDFStorageUID
+	if doc.Study.StudyInstanceUID == "" {
+		doc.Study.StudyInstanceUID = NewUID()
+	}
+	if doc.Series.SeriesInstanceUID == "" {
+		doc.Series.SeriesInstanceUID = NewUID()
+	}
+	opts = append(opts, WithFileMeta(EncapsulatedPDFStorageUID, doc.SOPCommon.SOPInstanceUID, string(transfer.ExplicitVRLittleEndian)))
+
+	// 2. Modules
+	burnedIn := "NO"
+	if doc.BurnedInAnnotation {
+		burnedIn = "YES"
+	}
+	opts = append(opts,
+		WithModule(doc.Patient.ToTags()),
+		WithModule(doc.Study.ToTags()),
+		WithModule(doc.Series.ToTags()),
+		WithModule(doc.Equipment.ToTags()),
+		WithModule(doc.SOPCommon.ToTags()),
+		WithElement(tag.ConversionType, doc.ConversionType),
+		WithElement(tag.InstanceNumber, strconv.Itoa(doc.InstanceNumber)),
+		WithElement(tag.ContentDate, doc.ContentDate.String()),
+		WithElement(tag.ContentTime, doc.ContentTime.String()),
+		WithElement(tag.AcquisitionDateTime, doc.AcquisitionDateTime),
+		WithElement(tag.BurnedInAnnotation, burnedIn),
+		WithElement(tag.DocumentTitle, doc.DocumentTitle),
+		WithSequence(tag.ConceptNameCodeSequence),
+	)
+	if len(doc.SourceInstances) > 0 {
+		items := make([]*Dataset, len(doc.SourceInstances))
+		for i, src := range doc.SourceInstances {
+			item, err := NewDataset(
+				WithElement(tag.ReferencedSOPClassUID, src.SOPClassUID),
+				WithElement(tag.ReferencedSOPInstanceUID, src.SOPInstanceUID),
+			)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		opts = append(opts, WithSequence(tag.SourceInstanceSequence, items...))
+	}
+
+	// Additional Tags
+	for t, v := range doc.AdditionalTags {
+		opts = append(opts, WithElement(t, v))
+	}
+
+	// 3. Document
+	opts = append(opts,
+		WithElement(tag.MIMETypeOfEncapsulatedDocument, PDFMIMEType),
+		WithElement(tag.EncapsulatedDocumentLength, uint32(len(doc.Document))),
+		withVR(tag.EncapsulatedDocument, "OB", padEven(doc.Document)),
+	)
+
+	return NewDataset(opts...)
+}
+
+// WriteTo writes the Encapsulated PDF to any io.Writer
+func (doc *EncapsulatedPDF) WriteTo(w io.Writer) (int64, error) {
+	dataset, err := doc.GetDataset()
+	if err != nil {
+		return 0, err
+	}
+	return Write(w, dataset)
+}
+
+// Write saves the Encapsulated PDF to a DICOS file (convenience wrapper)
+func (doc *EncapsulatedPDF) Write(path string) (int64, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return doc.WriteTo(f)
+}
+
+// EncapsulatedPDFFromDataset reads an Encapsulated PDF back from a dataset.
+// The document loses the padding added to make it even length: its length is
+// taken from Encapsulated Document Length when present.
+//
+// Example:
+//
+//	ds, _ := dicos.ReadFile("report.dcs")
+//	doc, err := dicos.EncapsulatedPDFFromDataset(ds)
+//	os.WriteFile("report.pdf", doc.Document, 0o644)
+func EncapsulatedPDFFromDataset(ds *Dataset) (*EncapsulatedPDF, error) {
+	if uid := attrString(ds, tag.SOPClassUID); uid != EncapsulatedPDFStorageUID {
+		return nil, fmt.Errorf("not an encapsulated PDF: SOP Class UID %q", uid)
+	}
+	elem, ok := ds.Elements[tag.EncapsulatedDocument]
+	if !ok {
+		return nil, fmt.Errorf("no encapsulated document element found")
+	}
+	data, ok := elem.Value.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("encapsulated document has unexpected type: %T", elem.Value)
+	}
+	if l, ok := ds.Elements[tag.EncapsulatedDocumentLength]; ok {
+		if n, ok := l.GetInt(); ok && n >= 0 && n <= len(data) {
+			data = data[:n]
+		}
+	} else if len(data) > 0 && data[len(data)-1] == 0 {
+		data = data[:len(data)-1]
+	}
+
+	doc := &EncapsulatedPDF{
+		Patient:             readPatientModule(ds),
+		Study:               readStudyModule(ds),
+		Series:              readSeriesModule(ds),
+		Equipment:           readEquipmentModule(ds),
+		ConversionType:      attrString(ds, tag.ConversionType),
+		InstanceNumber:      attrInt(ds, tag.InstanceNumber),
+		AcquisitionDateTime: attrString(ds, tag.AcquisitionDateTime),
+		DocumentTitle:       attrString(ds, tag.DocumentTitle),
+		BurnedInAnnotation:  attrString(ds, tag.BurnedInAnnotation) == "YES",
+		Document:            data,
+		AdditionalTags:      make(map[tag.Tag]interface{}),
+	}
+	doc.SOPCommon.SOPClassUID = EncapsulatedPDFStorageUID
+	doc.SOPCommon.SOPInstanceUID = attrString(ds, tag.SOPInstanceUID)
+	doc.ContentDate, _ = module.ParseDate(attrString(ds, tag.ContentDate))
+	doc.ContentTime, _ = module.ParseTime(attrString(ds, tag.ContentTime))
+	for _, item := range GetSequenceItems(ds, tag.SourceInstanceSequence) {
+		doc.SourceInstances = append(doc.SourceInstances, SourceImage{
+			SOPClassUID:    attrString(item, tag.ReferencedSOPClassUID),
+			SOPInstanceUID: attrString(item, tag.ReferencedSOPInstanceUID),
+		})
+	}
+	return doc, nil
+}
@@ -0,0 +1,65 @@
+package dicos
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPDF is a minimal PDF of odd length, so it is padded when stored
+var testPDF = []byte("%PDF-1.4\n1 0 obj << /Type /Catalog >> endobj\ntrailer << /Root 1 0 R >>\n%%EOF\n")
+
+func TestEncapsulatedPDF_RoundTrip(t *testing.T) {
+	require.Equal(t, 1, len(testPDF)%2)
+	ct, err := ReadBuffer(writeTestCT(t, 4, 4, 1, nil))
+	require.NoError(t, err)
+
+	doc := NewEncapsulatedPDF()
+	doc.Document = testPDF
+	doc.DocumentTitle = "Inspection Report"
+	doc.BurnedInAnnotation = true
+	require.NoError(t, doc.AttachTo(ct))
+
+	var buf bytes.Buffer
+	_, err = doc.WriteTo(&buf)
+	require.NoError(t, err)
+	ds, err := ReadBuffer(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, EncapsulatedPDFStorageUID, stringValue(ds, tag.MediaStorageSOPClassUID))
+	assert.Equal(t, "DOC", stringValue(ds, tag.Modality))
+	assert.Equal(t, PDFMIMEType, stringValue(ds, tag.MIMETypeOfEncapsulatedDocument))
+	assert.Contains(t, ds.Elements, tag.ConceptNameCodeSequence)
+	assert.Equal(t, stringValue(ct, tag.StudyInstanceUID), stringValue(ds, tag.StudyInstanceUID), "same study")
+	assert.NotEqual(t, stringValue(ct, tag.SeriesInstanceUID), stringValue(ds, tag.SeriesInstanceUID), "own series")
+
+	got, err := EncapsulatedPDFFromDataset(ds)
+	require.NoError(t, err)
+	assert.Equal(t, testPDF, got.Document, "padding removed")
+	assert.Equal(t, "Inspection Report", got.DocumentTitle)
+	assert.True(t, got.BurnedInAnnotation)
+	assert.Equal(t, "READER-001", got.Patient.PatientID)
+	assert.Equal(t, doc.SOPCommon.SOPInstanceUID, got.SOPCommon.SOPInstanceUID)
+	assert.Equal(t, []SourceImage{{SOPClassUID: stringValue(ct, tag.SOPClassUID), SOPInstanceUID: stringValue(ct, tag.SOPInstanceUID)}}, got.SourceInstances)
+
+	// without a length the padding byte is dropped
+	delete(ds.Elements, tag.EncapsulatedDocumentLength)
+	got, err = EncapsulatedPDFFromDataset(ds)
+	require.NoError(t, err)
+	assert.Equal(t, testPDF, got.Document)
+}
+
+func TestEncapsulatedPDF_Errors(t *testing.T) {
+	doc := NewEncapsulatedPDF()
+	doc.Document = []byte("not a pdf")
+	_, err := doc.GetDataset()
+	assert.ErrorContains(t, err, "not a PDF")
+
+	ct, err := ReadBuffer(writeTestCT(t, 4, 4, 1, nil))
+	require.NoError(t, err)
+	_, err = EncapsulatedPDFFromDataset(ct)
+	assert.ErrorContains(t, err, "not an encapsulated PDF")
+	assert.Error(t, doc.AttachTo(&Dataset{Elements: map[Tag]*Element{}}))
+}
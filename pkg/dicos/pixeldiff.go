@@ -0,0 +1,108 @@
+package dicos
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// FrameDiff summarizes the per-pixel differences between two frames
+type FrameDiff struct {
+	Pixels    int     // pixels compared
+	MaxAbs    int     // largest absolute difference
+	MeanAbs   float64 // mean absolute difference over all pixels
+	Differing int     // pixels whose absolute difference exceeds the tolerance
+}
+
+// Lossless returns true if every pixel is identical
+func (d FrameDiff) Lossless() bool {
+	return d.MaxAbs == 0
+}
+
+func (d FrameDiff) String() string {
+	return fmt.Sprintf("max=%d mean=%.4f differing=%d/%d", d.MaxAbs, d.MeanAbs, d.Differing, d.Pixels)
+}
+
+// CompareFrames compares two frames pixel by pixel. Pixels whose absolute
+// difference is greater than tolerance are counted as differing; a tolerance of
+// 0 checks that a transcode is truly lossless.
+//
+// Example:
+//
+//	diff, err := dicos.CompareFrames(original, transcoded, 0)
+//	if err == nil && !diff.Lossless() {
+//		fmt.Println("transcode changed pixels:", diff)
+//	}
+func CompareFrames(a, b []uint16, tolerance int) (FrameDiff, error) {
+	if len(a) != len(b) {
+		return FrameDiff{}, fmt.Errorf("frame sizes differ: %d vs %d pixels", len(a), len(b))
+	}
+	d := FrameDiff{Pixels: len(a)}
+	var sum int64
+	for i := range a {
+		diff := absDiff(a[i], b[i])
+		sum += int64(diff)
+		d.MaxAbs = max(d.MaxAbs, diff)
+		if diff > tolerance {
+			d.Differing++
+		}
+	}
+	if d.Pixels > 0 {
+		d.MeanAbs = float64(sum) / float64(d.Pixels)
+	}
+	return d, nil
+}
+
+// DiffHeatmap renders the absolute difference of two rows x cols frames.
+// Identical pixels are black; differences ramp from blue through green and
+// yellow to red at the largest difference in the frame.
+func DiffHeatmap(a, b []uint16, rows, cols int) (*image.RGBA, error) {
+	if len(a) != len(b) {
+		return nil, fmt.Errorf("frame sizes differ: %d vs %d pixels", len(a), len(b))
+	}
+	if rows*cols > len(a) {
+		return nil, fmt.Errorf("frame has %d pixels, need %dx%d", len(a), rows, cols)
+	}
+
+	maxDiff := 0
+	for i := range rows * cols {
+		maxDiff = max(maxDiff, absDiff(a[i], b[i]))
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, cols, rows))
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols; x++ {
+			i := y*cols + x
+			diff := absDiff(a[i], b[i])
+			if diff == 0 {
+				img.SetRGBA(x, y, color.RGBA{A: 0xFF})
+				continue
+			}
+			img.SetRGBA(x, y, heatColor(float64(diff)/float64(maxDiff)))
+		}
+	}
+	return img, nil
+}
+
+// heatColor maps t in (0,1] onto a blue-green-yellow-red ramp
+func heatColor(t float64) color.RGBA {
+	lerp := func(a, b uint8, f float64) uint8 { return uint8(float64(a) + (float64(b)-float64(a))*f) }
+	switch {
+	case t < 1.0/3:
+		f := t * 3
+		return color.RGBA{R: 0, G: lerp(0, 0xFF, f), B: lerp(0xFF, 0, f), A: 0xFF}
+	case t < 2.0/3:
+		f := (t - 1.0/3) * 3
+		return color.RGBA{R: lerp(0, 0xFF, f), G: 0xFF, B: 0, A: 0xFF}
+	default:
+		f := (t - 2.0/3) * 3
+		return color.RGBA{R: 0xFF, G: lerp(0xFF, 0, f), B: 0, A: 0xFF}
+	}
+}
+
+func absDiff(a, b uint16) int {
+	if a > b {
+		return int(a - b)
+	}
+	return int(b - a)
+}
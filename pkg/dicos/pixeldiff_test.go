@@ -0,0 +1,44 @@
+package dicos
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareFrames(t *testing.T) {
+	a := []uint16{10, 20, 30, 40}
+	b := []uint16{10, 22, 25, 40}
+
+	d, err := CompareFrames(a, b, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 4, d.Pixels)
+	assert.Equal(t, 5, d.MaxAbs)
+	assert.InDelta(t, 7.0/4, d.MeanAbs, 1e-9)
+	assert.Equal(t, 1, d.Differing, "only the 5 difference exceeds the tolerance")
+	assert.False(t, d.Lossless())
+
+	d, err = CompareFrames(a, a, 0)
+	require.NoError(t, err)
+	assert.True(t, d.Lossless())
+	assert.Zero(t, d.Differing)
+
+	_, err = CompareFrames(a, b[:3], 0)
+	assert.Error(t, err)
+}
+
+func TestDiffHeatmap(t *testing.T) {
+	a := []uint16{0, 0, 0, 0}
+	b := []uint16{0, 10, 0, 5}
+
+	img, err := DiffHeatmap(a, b, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, color.RGBA{A: 0xFF}, img.RGBAAt(0, 0), "identical pixels are black")
+	assert.Equal(t, color.RGBA{R: 0xFF, A: 0xFF}, img.RGBAAt(1, 0), "largest difference is red")
+	assert.NotEqual(t, img.RGBAAt(1, 0), img.RGBAAt(1, 1))
+
+	_, err = DiffHeatmap(a, b, 3, 3)
+	assert.Error(t, err)
+}
@@ -0,0 +1,90 @@
+package dicos
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// MIP returns the maximum intensity projection of the volume along the
+// normal of a slice plane: SliceAxial projects along Z onto an XY image,
+// SliceCoronal along Y onto an XZ image and SliceSagittal along X onto a YZ
+// image, laid out like Slice. Pixels hold the maximum stored sample; a
+// signed volume's samples are offset by 32768 so they keep their order.
+// Multi-sample volumes project their first sample.
+//
+// Example:
+//
+//	vol, _ := dicos.DecodeVolume(ds)
+//	mip, err := vol.MIP(dicos.SliceCoronal)
+func (v *Volume) MIP(plane int) (*image.Gray16, error) {
+	data, rows, cols, err := v.project(plane)
+	if err != nil {
+		return nil, err
+	}
+	img := image.NewGray16(image.Rect(0, 0, cols, rows))
+	for i, val := range data {
+		if v.Signed {
+			val += 0x8000
+		}
+		img.SetGray16(i%cols, i/cols, color.Gray16{Y: val})
+	}
+	return img, nil
+}
+
+// ProjectedView returns the maximum intensity projection of the volume onto
+// a slice plane, windowed for display. The window rescales samples to
+// modality values (e.g. HU) first; use WindowFromDataset for the rescale of
+// the dataset the volume was decoded from.
+//
+// Example:
+//
+//	win := dicos.WindowFromDataset(ds)
+//	win.Center, win.Width = 300, 1500
+//	img, err := vol.ProjectedView(dicos.SliceAxial, win)
+func (v *Volume) ProjectedView(plane int, w Window) (*image.Gray16, error) {
+	data, rows, cols, err := v.project(plane)
+	if err != nil {
+		return nil, err
+	}
+	// volume samples are already sign extended to 16 bits
+	w.Signed, w.BitsStored = v.Signed, 16
+	return w.Render16(data, rows, cols)
+}
+
+// project returns the maximum first sample along the normal of plane, with
+// the size of the projected image
+func (v *Volume) project(plane int) (data []uint16, rows, cols int, err error) {
+	var depth int
+	var at func(col, row, d int) (x, y, z int)
+	switch plane {
+	case SliceAxial:
+		rows, cols, depth = v.Height, v.Width, v.Depth
+		at = func(col, row, d int) (int, int, int) { return col, row, d }
+	case SliceCoronal:
+		rows, cols, depth = v.Depth, v.Width, v.Height
+		at = func(col, row, d int) (int, int, int) { return col, d, row }
+	case SliceSagittal:
+		rows, cols, depth = v.Depth, v.Height, v.Width
+		at = func(col, row, d int) (int, int, int) { return d, col, row }
+	default:
+		return nil, 0, 0, fmt.Errorf("unknown slice plane %d", plane)
+	}
+	if rows == 0 || cols == 0 || depth == 0 {
+		return nil, 0, 0, fmt.Errorf("cannot project an empty %dx%dx%d volume", v.Width, v.Height, v.Depth)
+	}
+
+	data = make([]uint16, rows*cols)
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			best := v.Get(at(col, row, 0))
+			for d := 1; d < depth; d++ {
+				if val := v.Get(at(col, row, d)); v.toFloat(val) > v.toFloat(best) {
+					best = val
+				}
+			}
+			data[row*cols+col] = best
+		}
+	}
+	return data, rows, cols, nil
+}
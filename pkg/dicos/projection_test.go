@@ -0,0 +1,61 @@
+package dicos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVolume_MIP(t *testing.T) {
+	v := NewVolume(3, 2, 4)
+	for i := range v.Data {
+		v.Data[i] = uint16(i)
+	}
+	v.Set(1, 0, 1, 500)
+
+	tests := []struct {
+		plane      int
+		rows, cols int
+		at         [2]int // pixel (col, row) holding the bright voxel
+	}{
+		{SliceAxial, 2, 3, [2]int{1, 0}},
+		{SliceCoronal, 4, 3, [2]int{1, 1}},
+		{SliceSagittal, 4, 2, [2]int{0, 1}},
+	}
+	for _, tt := range tests {
+		img, err := v.MIP(tt.plane)
+		require.NoError(t, err)
+		assert.Equal(t, tt.cols, img.Bounds().Dx())
+		assert.Equal(t, tt.rows, img.Bounds().Dy())
+		assert.Equal(t, uint16(500), img.Gray16At(tt.at[0], tt.at[1]).Y, "plane %d", tt.plane)
+	}
+
+	// along Z the last slice holds the largest ramp values
+	img, err := v.MIP(SliceAxial)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(v.Get(2, 1, 3)), img.Gray16At(2, 1).Y)
+
+	_, err = v.MIP(5)
+	assert.Error(t, err)
+}
+
+func TestVolume_MIPSigned(t *testing.T) {
+	v := NewVolume(1, 1, 3)
+	v.Signed = true
+	copy(v.Data, Uint16Samples([]int16{-1000, -200, -500}))
+
+	img, err := v.MIP(SliceAxial)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(0x8000-200), img.Gray16At(0, 0).Y)
+}
+
+func TestVolume_ProjectedView(t *testing.T) {
+	// stored 0..3000 with a -1024 intercept, so HU -1024..1976
+	v := NewVolume(2, 1, 2)
+	copy(v.Data, []uint16{0, 1024, 3000, 524})
+	img, err := v.ProjectedView(SliceAxial, Window{Center: 0, Width: 1000, Slope: 1, Intercept: -1024})
+	require.NoError(t, err)
+	assert.Equal(t, uint16(65535), img.Gray16At(0, 0).Y) // 3000 -> 1976 HU, above the window
+	assert.Equal(t, uint16(32767), img.Gray16At(1, 0).Y) // 1024 -> 0 HU, the center
+}
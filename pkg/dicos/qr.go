@@ -0,0 +1,188 @@
+package dicos
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/module"
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/jpfielding/dicos.go/pkg/dicos/transfer"
+)
+
+// QRMeasurement represents a DICOS Quadrupole Resonance (QR) Storage IOD, the
+// result of a QR scan of a bag. It holds no image: the measured spectrum is
+// kept in SpectroscopyData and the substances found as ATD assessments.
+// SOP Class UID: 1.2.840.10008.5.1.4.1.1.501.6
+type QRMeasurement struct {
+	// Modules
+	Patient     module.PatientModule
+	Study       module.GeneralStudyModule
+	Series      module.GeneralSeriesModule
+	Equipment   module.GeneralEquipmentModule
+	SOPCommon   module.SOPCommonModule
+	Acquisition *module.QRAcquisitionModule
+
+	ContentDate   module.Date
+	ContentTime   module.Time
+	AlarmDecision string // "ALARM", "NO_ALARM", "UNKNOWN"
+
+	// Substances detected, written as the ATD Assessment Sequence
+	Results []ATDAssessment
+
+	// Measured spectrum, optional
+	Spectrum []float32
+}
+
+// NewQRMeasurement creates a new QR measurement with defaults
+func NewQRMeasurement() *QRMeasurement {
+	t := time.Now()
+	return &QRMeasurement{
+		Study:       module.NewGeneralStudyModule(),
+		Series:      module.GeneralSeriesModule{Modality: "QR"},
+		SOPCommon:   module.NewSOPCommonModule(),
+		Acquisition: module.NewQRAcquisitionModule(),
+		ContentDate: module.NewDate(t),
+		ContentTime: module.NewTime(t),
+	}
+}
+
+// GetDataset builds and returns the DICOS Dataset
+func (qr *QRMeasurement) GetDataset() (*Dataset, error) {
+	opts := make([]Option, 0, 32)
+
+	sopInstanceUID := qr.SOPCommon.SOPInstanceUID
+	if sopInstanceUID == "" {
+		sopInstanceUID = NewUID()
+		qr.SOPCommon.SOPInstanceUID = sopInstanceUID
+	}
+	qr.SOPCommon.SOPClassUID = DICOSQRStorageUID
+	if qr.Study.StudyInstanceUID == "" {
+		qr.Study.StudyInstanceUID = NewUID()
+	}
+	if qr.Series.SeriesInstanceUID == "" {
+		qr.Series.SeriesInstanceUID = NewUID()
+	}
+
+	// File Meta, no pixel data so never compressed
+	opts = append(opts, WithFileMeta(DICOSQRStorageUID, sopInstanceUID, string(transfer.ExplicitVRLittleEndian)))
+
+	// Modules
+	opts = append(opts,
+		WithModule(qr.Patient.ToTags()),
+		WithModule(qr.Study.ToTags()),
+		WithModule(qr.Series.ToTags()),
+		WithModule(qr.Equipment.ToTags()),
+		WithModule(qr.SOPCommon.ToTags()),
+	)
+	if qr.Acquisition != nil {
+		opts = append(opts, WithModule(qr.Acquisition.ToTags()))
+	}
+
+	// Content Date/Time
+	opts = append(opts,
+		WithElement(tag.ContentDate, qr.ContentDate.String()),
+		WithElement(tag.ContentTime, qr.ContentTime.String()),
+	)
+
+	if qr.AlarmDecision != "" {
+		opts = append(opts, WithElement(tag.AlarmDecision, qr.AlarmDecision))
+	}
+
+	// ATD Assessment Sequence, one item per substance
+	if len(qr.Results) > 0 {
+		items := make([]*Dataset, 0, len(qr.Results))
+		for _, r := range qr.Results {
+			rOpts := make([]Option, 0, 4)
+			if r.Category != "" {
+				rOpts = append(rOpts, WithElement(tag.ThreatCategoryDescription, r.Category))
+			}
+			if r.Ability != "" {
+				rOpts = append(rOpts, WithElement(tag.ATDAbility, r.Ability))
+			}
+			rOpts = append(rOpts,
+				WithElement(tag.ATDAssessmentProbability, r.Probability),
+				WithElement(tag.ThreatConfidenceScore, r.Confidence),
+			)
+			item, err := NewDataset(rOpts...)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		opts = append(opts, WithSequence(tag.ATDAssessmentSequence, items...))
+	}
+
+	if len(qr.Spectrum) > 0 {
+		opts = append(opts, WithElement(tag.SpectroscopyData, qr.Spectrum))
+	}
+
+	return NewDataset(opts...)
+}
+
+// WriteTo writes the QR measurement to any io.Writer
+func (qr *QRMeasurement) WriteTo(w io.Writer) (int64, error) {
+	dataset, err := qr.GetDataset()
+	if err != nil {
+		return 0, err
+	}
+	return Write(w, dataset)
+}
+
+// Write saves the QR measurement to a DICOS file (convenience wrapper)
+func (qr *QRMeasurement) Write(path string) (int64, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return qr.WriteTo(f)
+}
+
+// ParseQR maps a QR dataset, e.g. one read with ReadFile, back to a
+// QRMeasurement: the patient, study, series, equipment and SOP common
+// modules, the acquisition, the alarm decision, each ATD assessment and the
+// spectrum. Acquisition is nil when the dataset has no resonant nucleus.
+func ParseQR(ds *Dataset) (*QRMeasurement, error) {
+	if ds == nil {
+		return nil, fmt.Errorf("dicos: ParseQR: nil dataset")
+	}
+	if !IsQR(ds) {
+		return nil, fmt.Errorf("dicos: ParseQR: SOP class %q is not a QR", attrString(ds, tag.SOPClassUID))
+	}
+
+	qr := &QRMeasurement{
+		Patient:       readPatientModule(ds),
+		Study:         readStudyModule(ds),
+		Series:        readSeriesModule(ds),
+		Equipment:     readEquipmentModule(ds),
+		SOPCommon:     readSOPCommonModule(ds),
+		AlarmDecision: attrString(ds, tag.AlarmDecision),
+	}
+	qr.ContentDate, _ = module.ParseDate(attrString(ds, tag.ContentDate))
+	qr.ContentTime, _ = module.ParseTime(attrString(ds, tag.ContentTime))
+
+	if attrString(ds, tag.ResonantNucleus) != "" {
+		qr.Acquisition = &module.QRAcquisitionModule{}
+		qr.Acquisition.FromDataset(ds)
+	}
+
+	for _, a := range GetSequenceItems(ds, tag.ATDAssessmentSequence) {
+		qr.Results = append(qr.Results, ATDAssessment{
+			Category:    attrString(a, tag.ThreatCategoryDescription),
+			Ability:     attrString(a, tag.ATDAbility),
+			Probability: tdrFloat(a, tag.ATDAssessmentProbability),
+			Confidence:  tdrFloat(a, tag.ThreatConfidenceScore),
+		})
+	}
+
+	if elem := ds.Elements[tag.SpectroscopyData]; elem != nil {
+		spectrum, err := float32Values(elem)
+		if err != nil {
+			return nil, fmt.Errorf("dicos: ParseQR: spectroscopy data: %w", err)
+		}
+		qr.Spectrum = spectrum
+	}
+	return qr, nil
+}
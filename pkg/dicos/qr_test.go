@@ -0,0 +1,95 @@
+package dicos
+
+import (
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQRMeasurement_RoundTrip(t *testing.T) {
+	qr := NewQRMeasurement()
+	qr.Patient.PatientID = "BAG-0001"
+	qr.Acquisition.TransmitterFrequency = []float64{3.41, 5.19}
+	qr.Acquisition.SpectralWidth = 20000
+	qr.Acquisition.AcquisitionDuration = 2.5
+	qr.Acquisition.NumberOfAverages = 64
+	qr.AlarmDecision = "ALARM"
+	qr.Results = []ATDAssessment{{Category: "RDX", Ability: "AUTOMATIC", Probability: 0.875, Confidence: 0.5}}
+	qr.Spectrum = []float32{0, 0.25, 1.5, -0.75}
+
+	ds, err := qr.GetDataset()
+	require.NoError(t, err)
+	ds = rewrite(t, ds)
+
+	assert.True(t, IsQR(ds))
+	assert.False(t, IsCT(ds) || IsTDR(ds))
+	assert.Equal(t, "QR", GetModality(ds))
+	result := ValidateQR(ds)
+	assert.True(t, result.IsValid(), result.String())
+
+	var got struct {
+		Nucleus   string    `dicom:"ResonantNucleus"`
+		Frequency []float64 `dicom:"TransmitterFrequency"`
+		Width     float64   `dicom:"SpectralWidth"`
+		Duration  float64   `dicom:"AcquisitionDuration"`
+		Averages  int       `dicom:"NumberOfAverages"`
+		Spectrum  []float32 `dicom:"SpectroscopyData"`
+		Results   []struct {
+			Category    string  `dicom:"ThreatCategoryDescription"`
+			Probability float32 `dicom:"ATDAssessmentProbability"`
+		} `dicom:"ATDAssessmentSequence"`
+	}
+	require.NoError(t, ds.Unmarshal(&got))
+	assert.Equal(t, "14N", got.Nucleus)
+	assert.Equal(t, []float64{3.41, 5.19}, got.Frequency)
+	assert.Equal(t, 20000.0, got.Width)
+	assert.Equal(t, 2.5, got.Duration)
+	assert.Equal(t, 64, got.Averages)
+	assert.Equal(t, qr.Spectrum, got.Spectrum)
+	require.Len(t, got.Results, 1)
+	assert.Equal(t, "RDX", got.Results[0].Category)
+	assert.Equal(t, float32(0.875), got.Results[0].Probability)
+}
+
+func TestParseQR(t *testing.T) {
+	qr := NewQRMeasurement()
+	qr.Patient.PatientID = "BAG-0001"
+	qr.Acquisition.TransmitterFrequency = []float64{3.41, 5.19}
+	qr.Acquisition.SpectralWidth = 20000
+	qr.Acquisition.NumberOfAverages = 64
+	qr.AlarmDecision = "ALARM"
+	qr.Results = []ATDAssessment{{Category: "RDX", Ability: "AUTOMATIC", Probability: 0.875, Confidence: 0.5}}
+	qr.Spectrum = []float32{0, 0.25, 1.5, -0.75}
+	ds, err := qr.GetDataset()
+	require.NoError(t, err)
+
+	got, err := ParseQR(rewrite(t, ds))
+	require.NoError(t, err)
+	assert.Equal(t, qr.Patient.PatientID, got.Patient.PatientID)
+	assert.Equal(t, qr.Study.StudyInstanceUID, got.Study.StudyInstanceUID)
+	assert.Equal(t, qr.SOPCommon.SOPInstanceUID, got.SOPCommon.SOPInstanceUID)
+	assert.Equal(t, "QR", got.Series.Modality)
+	assert.Equal(t, qr.ContentDate, got.ContentDate)
+	assert.Equal(t, qr.Acquisition, got.Acquisition)
+	assert.Equal(t, "ALARM", got.AlarmDecision)
+	assert.Equal(t, qr.Results, got.Results)
+	assert.Equal(t, qr.Spectrum, got.Spectrum)
+
+	ct, err := NewCTImage().GetDataset()
+	require.NoError(t, err)
+	_, err = ParseQR(ct)
+	assert.ErrorContains(t, err, "is not a QR")
+}
+
+func TestValidateQR_MissingFrequency(t *testing.T) {
+	ds, err := NewQRMeasurement().GetDataset()
+	require.NoError(t, err)
+	result := ValidateQR(ds)
+	require.False(t, result.IsValid())
+	assert.Equal(t, tag.TransmitterFrequency, result.Errors[0].Tag)
+
+	_, err = BuildFromTemplate([]byte("iod: QR\nmodules:\n  acquisition:\n    TransmitterFrequency: [3.41]\n"), nil)
+	assert.NoError(t, err)
+}
@@ -2,24 +2,98 @@ package dicos
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"math"
+	"slices"
 )
 
 // Reader reads DICOS/DICOM files
 type Reader struct {
 	r              io.Reader
+	src            io.Reader
+	cr             *countingReader
+	ctx            context.Context
+	opts           ParseOptions
+	issues         []ParseIssue
 	transferSyntax string
 	explicitVR     bool
 	littleEndian   bool
+	allocated      int64 // bytes reserved for values and fragments
+	depth          int   // nesting of the sequence being read
+}
+
+// ParseOptions controls how a dataset is read
+type ParseOptions struct {
+	// BulkDataThreshold reads private OB/OW/UN values of at least this many
+	// bytes as *BulkData instead of loading them. Zero loads everything.
+	BulkDataThreshold int64
+	// ExcludeBulkData drops bulk payloads instead of referencing them, so
+	// they are not carried into written output
+	ExcludeBulkData bool
+	// Strict fails on the first encoding violation instead of collecting it
+	// as a ParseIssue, for conformance testing
+	Strict bool
+	// SkipPixelData stops reading at the Pixel Data (7FE0,0010) element, so
+	// metadata-only consumers never load or decode it
+	SkipPixelData bool
+	// OnlyTags keeps only these top-level elements, plus the file meta
+	// group. Other values are skipped without being parsed, and reading
+	// stops once past the highest listed tag. Empty keeps everything.
+	OnlyTags []Tag
+	// Duplicates selects which value of a repeated element is kept. Every
+	// repeat is reported as a ParseIssue wrapping ErrDuplicateTag.
+	Duplicates DuplicatePolicy
+	// Decryption restores the encrypted attributes addressed to this
+	// recipient with DecryptAttributes. Files without any for it are read
+	// as they are.
+	Decryption *DecryptionKey
+	// Logger receives the reader's diagnostics instead of the logger of the
+	// context (see WithLogger)
+	Logger *slog.Logger
+	// BestEffort skips elements whose values cannot be parsed, resuming after
+	// their value length, and keeps the elements read before the input ends.
+	// Each is reported as a ParseIssue wrapping ErrSkippedElement. It has no
+	// effect with Strict.
+	BestEffort bool
+	// Limits bounds the lengths, nesting, frames and allocations a file can
+	// demand, failing with ErrLimitExceeded; see DefaultParseLimits
+	Limits ParseLimits
+}
+
+// keep returns true if the top-level element t should be read
+func (o ParseOptions) keep(t Tag) bool {
+	return len(o.OnlyTags) == 0 || slices.Contains(o.OnlyTags, t)
+}
+
+// done returns true if no element at or after t is wanted
+func (o ParseOptions) done(t Tag) bool {
+	if o.SkipPixelData && t == pixelDataTag {
+		return true
+	}
+	if len(o.OnlyTags) == 0 {
+		return false
+	}
+	for _, want := range o.OnlyTags {
+		if !want.Less(t) {
+			return false
+		}
+	}
+	return true
 }
 
 // NewReader creates a new DICOS reader
 func NewReader(r io.Reader) *Reader {
+	cr := &countingReader{r: r}
 	return &Reader{
-		r:            r,
+		r:            cr,
+		src:          r,
+		cr:           cr,
+		ctx:          context.Background(),
 		explicitVR:   true,
 		littleEndian: true,
 	}
@@ -27,8 +101,38 @@ func NewReader(r io.Reader) *Reader {
 
 // Parse reads a complete DICOS file
 func Parse(r io.Reader) (*Dataset, error) {
+	return ParseContext(context.Background(), r)
+}
+
+// ParseContext reads a complete DICOS file, honoring ctx for cancellation.
+// Log records are emitted with ctx so handlers such as logging.ContextHandler
+// can attach per-request fields.
+func ParseContext(ctx context.Context, r io.Reader) (*Dataset, error) {
+	return ParseWithOptions(ctx, r, ParseOptions{})
+}
+
+// ParseWithOptions reads a complete DICOS file using opts.
+// To keep bulk payloads accessible, r must be an io.ReaderAt (such as an
+// *os.File) that stays open while the dataset is in use.
+func ParseWithOptions(ctx context.Context, r io.Reader, opts ParseOptions) (*Dataset, error) {
+	ds, _, err := ParseWithIssues(ctx, r, opts)
+	return ds, err
+}
+
+// ParseWithIssues reads a complete DICOS file using opts and returns the
+// non-fatal violations encountered. With opts.Strict the first violation is
+// returned as a ParseIssue error instead.
+func ParseWithIssues(ctx context.Context, r io.Reader, opts ParseOptions) (*Dataset, []ParseIssue, error) {
 	reader := NewReader(r)
-	return reader.ReadDataset()
+	reader.ctx = ctx
+	reader.opts = opts
+	ds, err := reader.ReadDataset()
+	if err == nil && opts.Decryption != nil {
+		if _, derr := DecryptAttributes(ds, *opts.Decryption); derr != nil && !errors.Is(derr, ErrNotRecipient) {
+			return nil, reader.Issues(), derr
+		}
+	}
+	return ds, reader.Issues(), err
 }
 
 // ReadDataset reads the complete dataset
@@ -37,72 +141,344 @@ func (r *Reader) ReadDataset() (*Dataset, error) {
 		Elements: make(map[Tag]*Element),
 	}
 
+	tag, err := r.readPreamble(ds)
+	if err == io.EOF {
+		return ds, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Read dataset elements. Once the pixel data has been read, the bytes of
+	// each following element are recorded so that appended content which
+	// does not parse can be kept as ds.Trailing.
+	afterPixelData := false
+	var prev Tag
+	for {
+		if err := r.ctx.Err(); err != nil {
+			return nil, err
+		}
+		if reason := trailingReason(prev, tag, afterPixelData); reason != "" {
+			return ds, r.readTrailing(ds, tag, afterPixelData, reason)
+		}
+		if r.opts.done(tag) {
+			return ds, nil
+		}
+
+		if r.opts.keep(tag) {
+			elem, err := r.readElement(tag)
+			if err != nil {
+				if afterPixelData && !errors.Is(err, ErrResourceLimit) {
+					return ds, r.readTrailing(ds, tag, true, err.Error())
+				}
+				if r.bestEffort() && r.recoverable(err) {
+					if err := r.truncated(tag, err); err != nil {
+						return nil, err
+					}
+					break
+				}
+				return nil, fmt.Errorf("failed to read element %v: %w", tag, err)
+			}
+			if elem != nil {
+				if err := r.put(ds, elem); err != nil {
+					return nil, err
+				}
+			}
+		} else if err := r.skipElement(tag); err != nil {
+			if r.bestEffort() && r.recoverable(err) {
+				if err := r.truncated(tag, err); err != nil {
+					return nil, err
+				}
+				break
+			}
+			return nil, fmt.Errorf("failed to skip element %v: %w", tag, err)
+		}
+		afterPixelData = afterPixelData || tag == pixelDataTag
+
+		prev = tag
+		if afterPixelData {
+			r.cr.record()
+		}
+		tag, err = r.readTag()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if afterPixelData {
+				return ds, r.readTrailing(ds, prev, true, err.Error())
+			}
+			if r.bestEffort() {
+				if err := r.truncated(prev, err); err != nil {
+					return nil, err
+				}
+				break
+			}
+			return nil, fmt.Errorf("failed to read tag: %w", err)
+		}
+	}
+	r.cr.recording = false
+
+	if elem, ok := ds.Elements[pixelDataTag]; ok {
+		if !r.explicitVR && ds.BitsAllocated() <= 8 {
+			elem.VR = "OB" // the dictionary VR of implicit pixel data is OW
+		}
+		if pd, ok := elem.GetPixelData(); ok {
+			applyFrameMeta(ds, pd)
+		}
+	}
+	return ds, nil
+}
+
+// readPreamble reads the preamble, DICM magic and file meta into ds, selects
+// the transfer syntax and returns the first tag of the dataset (or io.EOF)
+func (r *Reader) readPreamble(ds *Dataset) (Tag, error) {
 	// Read preamble (128 bytes) and DICM magic
 	preamble := make([]byte, 128)
 	if _, err := io.ReadFull(r.r, preamble); err != nil {
-		return nil, fmt.Errorf("failed to read preamble: %w", err)
+		return Tag{}, fmt.Errorf("failed to read preamble: %w", err)
 	}
 
 	magic := make([]byte, 4)
 	if _, err := io.ReadFull(r.r, magic); err != nil {
-		return nil, fmt.Errorf("failed to read DICM magic: %w", err)
+		return Tag{}, fmt.Errorf("failed to read DICM magic: %w", err)
 	}
 	if string(magic) != "DICM" {
-		return nil, errors.New("invalid DICOM file: missing DICM magic")
+		return Tag{}, errors.New("invalid DICOM file: missing DICM magic")
 	}
 
 	// Group 0002 (File Meta Information) is ALWAYS Explicit VR Little Endian
 	r.explicitVR = true
 	r.littleEndian = true
 
-	// Read dataset elements
+	tag, err := r.readFileMeta(ds)
+	if err != nil {
+		return Tag{}, err
+	}
+	if err := r.selectTransferSyntax(ds, tag); err != nil {
+		return Tag{}, err
+	}
+	return tag, nil
+}
+
+// trailingReason returns why tag cannot start a top-level element, or "" if
+// it can: delimiters and reserved groups never appear at the top level, and
+// elements after the pixel data must keep ascending.
+func trailingReason(prev, tag Tag, afterPixelData bool) string {
+	switch {
+	case tag.Group == 0xFFFE:
+		return "item or delimiter tag at top level"
+	case tag.Group == 0x0000 || tag.Group == 0xFFFF || (tag.Group%2 == 1 && tag.Group <= 0x0007):
+		return fmt.Sprintf("illegal group %04X", tag.Group)
+	case afterPixelData && !prev.Less(tag):
+		return fmt.Sprintf("tag %v does not follow %v", tag, prev)
+	}
+	return ""
+}
+
+// readTrailing stops parsing and keeps everything from the start of tag to
+// the end of the input as ds.Trailing. When recorded is false the tag was
+// just read and its 4 bytes are rebuilt instead.
+func (r *Reader) readTrailing(ds *Dataset, tag Tag, recorded bool, reason string) error {
+	var head []byte
+	if recorded {
+		head = append(head, r.cr.rec...)
+	} else {
+		head = binary.LittleEndian.AppendUint16(head, tag.Group)
+		head = binary.LittleEndian.AppendUint16(head, tag.Element)
+	}
+	r.cr.recording = false
+	rest, err := io.ReadAll(r.r)
+	ds.Trailing = append(head, rest...)
+	r.log().DebugContext(r.ctx, "Trailing data after dataset",
+		slog.String("tag", tag.String()),
+		slog.Int("bytes", len(ds.Trailing)),
+		slog.String("reason", reason))
+	if err != nil {
+		return fmt.Errorf("reading trailing data: %w", err)
+	}
+	return r.issue(tag, "%d bytes of trailing data after the dataset: %s", len(ds.Trailing), reason)
+}
+
+// readFileMeta reads the File Meta Information group, which is always Explicit
+// VR Little Endian, and returns the first tag that follows it (or io.EOF).
+//
+// When (0002,0000) is present its value bounds the group, so elements of other
+// groups interleaved inside it are still read as meta, and 0002 elements that
+// stray past it are accepted. Both are reported as parse issues.
+func (r *Reader) readFileMeta(ds *Dataset) (Tag, error) {
+	metaEnd := int64(-1)
 	for {
+		if err := r.ctx.Err(); err != nil {
+			return Tag{}, err
+		}
+
+		start := r.cr.n
 		tag, err := r.readTag()
 		if err == io.EOF {
-			break
+			return Tag{}, err
 		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to read tag: %w", err)
+			return Tag{}, fmt.Errorf("failed to read tag: %w", err)
 		}
 
-		// Transition from File Meta to Dataset?
-		if tag.Group != 0x0002 && r.transferSyntax == "" {
-			// If we hit a non-meta tag but haven't seen TransferSyntaxUID yet,
-			// or if we just finished meta group, we need to update settings.
-			// Default to Implicit VR if no File Meta was found
-			r.transferSyntax = "1.2.840.10008.1.2" // Implicit VR Little Endian
-			r.updateTransferSyntax()
+		inGroup := metaEnd >= 0 && start < metaEnd
+		switch {
+		case tag.Group == 0x0002 && metaEnd >= 0 && !inGroup:
+			if err := r.issue(tag, "file meta element beyond group length"); err != nil {
+				return Tag{}, err
+			}
+		case tag.Group != 0x0002 && inGroup:
+			if err := r.issue(tag, "element inside file meta group length"); err != nil {
+				return Tag{}, err
+			}
+		case tag.Group != 0x0002:
+			return tag, nil
 		}
 
-		elem, err := r.readElementWithTag(tag)
+		elem, err := r.readElement(tag)
 		if err != nil {
-			return nil, fmt.Errorf("failed to read element %v: %w", tag, err)
+			return Tag{}, fmt.Errorf("failed to read element %v: %w", tag, err)
+		}
+		if elem == nil {
+			continue
+		}
+		if err := r.put(ds, elem); err != nil {
+			return Tag{}, err
 		}
 
-		ds.Elements[elem.Tag] = elem
-
-		// If this was TransferSyntaxUID, update settings for the REST of the file
-		if tag.Group == 0x0002 && tag.Element == 0x0010 {
-			if tsStr, ok := elem.Value.(string); ok {
-				r.transferSyntax = tsStr
-				r.updateTransferSyntax()
+		if tag.Group == 0x0002 && tag.Element == 0x0000 {
+			if length, ok := elem.GetUint32(); ok {
+				metaEnd = r.cr.n + int64(length)
 			}
 		}
 	}
+}
 
-	return ds, nil
+// selectTransferSyntax switches the reader to the transfer syntax named in
+// the file meta. Without one, the encoding of the first dataset element (next)
+// is sniffed: a valid VR after its tag means Explicit VR Little Endian,
+// otherwise Implicit VR Little Endian is assumed.
+func (r *Reader) selectTransferSyntax(ds *Dataset, next Tag) error {
+	if elem, ok := ds.Elements[Tag{Group: 0x0002, Element: 0x0010}]; ok {
+		if ts, ok := elem.GetString(); ok && ts != "" {
+			r.transferSyntax = ts
+			r.updateTransferSyntax()
+			r.log().DebugContext(r.ctx, "Transfer syntax selected",
+				slog.String("uid", ts),
+				slog.Bool("explicitVR", r.explicitVR))
+			return nil
+		}
+	}
+
+	r.transferSyntax = "1.2.840.10008.1.2" // Implicit VR Little Endian
+	if vr, err := r.cr.peek(2); err == nil && knownVR(string(vr)) {
+		r.transferSyntax = "1.2.840.10008.1.2.1" // Explicit VR Little Endian
+	}
+	r.updateTransferSyntax()
+	r.log().DebugContext(r.ctx, "No transfer syntax in file meta, detected from first element",
+		slog.String("tag", next.String()),
+		slog.Bool("explicitVR", r.explicitVR))
+	return r.issue(next, "no transfer syntax in file meta")
 }
 
 // readElementWithTag reads a DICOM element after the tag has been read
 func (r *Reader) readElementWithTag(tag Tag) (*Element, error) {
-	var vr string
-	var vl uint32
+	vr, vl, err := r.readElementHeader(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read value
+	value, err := r.readValue(tag, vr, vl)
+	if err != nil {
+		return nil, err
+	}
 
+	return &Element{
+		Tag:   tag,
+		VR:    vr,
+		Value: value,
+	}, nil
+}
+
+// readElement reads the element of tag. In best-effort mode an element whose
+// value cannot be parsed is reported and skipped to the end of its value
+// length, or to the next sequence delimiter when its length is undefined, and
+// a nil element is returned.
+func (r *Reader) readElement(tag Tag) (*Element, error) {
+	if !r.bestEffort() {
+		return r.readElementWithTag(tag)
+	}
+	vr, vl, err := r.readElementHeader(tag)
+	if err != nil {
+		return nil, err
+	}
+	start := r.cr.n
+	value, err := r.readValue(tag, vr, vl)
+	if err == nil {
+		return &Element{Tag: tag, VR: vr, Value: value}, nil
+	}
+	if !r.recoverable(err) {
+		return nil, err
+	}
+	if vl == undefinedLength {
+		if rerr := r.resyncToSequenceDelimiter(tag); rerr != nil {
+			return nil, err
+		}
+	} else if end := start + int64(vl); r.cr.n > end || r.cr.skip(end-r.cr.n) != nil {
+		return nil, err
+	}
+	return nil, r.record(ParseIssue{Offset: start, Tag: tag, Message: fmt.Sprintf("skipped %s value: %v", vr, err), Err: ErrSkippedElement})
+}
+
+// skipElement passes over the value of an element that is not wanted.
+// Defined-length values are skipped unread; sequences of undefined length
+// have to be parsed to find their end.
+func (r *Reader) skipElement(tag Tag) error {
+	vr, vl, err := r.readElementHeader(tag)
+	if err != nil {
+		return err
+	}
+	if vl != undefinedLength {
+		return r.cr.skip(int64(vl))
+	}
+	if tag == pixelDataTag {
+		return r.skipEncapsulated()
+	}
+	_, err = r.readValue(tag, vr, vl)
+	return err
+}
+
+// skipEncapsulated passes over the items of encapsulated pixel data
+func (r *Reader) skipEncapsulated() error {
+	for {
+		tag, err := r.readTag()
+		if err != nil {
+			return err
+		}
+		var length uint32
+		if err := binary.Read(r.r, binary.LittleEndian, &length); err != nil {
+			return err
+		}
+		if tag == seqDelimTag {
+			return nil
+		}
+		if tag != itemTag || length == undefinedLength {
+			return r.resyncToSequenceDelimiter(pixelDataTag)
+		}
+		if err := r.cr.skip(int64(length)); err != nil {
+			return err
+		}
+	}
+}
+
+// readElementHeader reads the VR and value length that follow a tag
+func (r *Reader) readElementHeader(tag Tag) (vr string, vl uint32, err error) {
 	if r.explicitVR {
 		// Read VR (2 bytes)
 		vrBytes := make([]byte, 2)
 		if _, err := io.ReadFull(r.r, vrBytes); err != nil {
-			return nil, err
+			return "", 0, err
 		}
 		vr = string(vrBytes)
 
@@ -111,39 +487,39 @@ func (r *Reader) readElementWithTag(tag Tag) (*Element, error) {
 			// Reserved 2 bytes
 			reserved := make([]byte, 2)
 			if _, err := io.ReadFull(r.r, reserved); err != nil {
-				return nil, err
+				return "", 0, err
 			}
 			// VL is 4 bytes
 			if err := binary.Read(r.r, binary.LittleEndian, &vl); err != nil {
-				return nil, err
+				return "", 0, err
 			}
 		} else {
 			// VL is 2 bytes
 			var vl16 uint16
 			if err := binary.Read(r.r, binary.LittleEndian, &vl16); err != nil {
-				return nil, err
+				return "", 0, err
 			}
 			vl = uint32(vl16)
 		}
 	} else {
 		// Implicit VR: VL is always 4 bytes, VR is determined by tag
 		if err := binary.Read(r.r, binary.LittleEndian, &vl); err != nil {
-			return nil, err
+			return "", 0, err
 		}
 		vr = getImplicitVR(tag)
 	}
 
-	// Read value
-	value, err := r.readValue(tag, vr, vl)
-	if err != nil {
-		return nil, err
+	if r.explicitVR && !knownVR(vr) {
+		if err := r.issue(tag, "unknown VR %q", vr); err != nil {
+			return "", 0, err
+		}
 	}
-
-	return &Element{
-		Tag:   tag,
-		VR:    vr,
-		Value: value,
-	}, nil
+	if vl != 0xFFFFFFFF && vl%2 != 0 {
+		if err := r.issue(tag, "odd value length %d", vl); err != nil {
+			return "", 0, err
+		}
+	}
+	return vr, vl, nil
 }
 
 // readTag reads a DICOM tag
@@ -161,18 +537,49 @@ func (r *Reader) readTag() (Tag, error) {
 // readValue reads the value based on VR and VL
 func (r *Reader) readValue(tag Tag, vr string, vl uint32) (interface{}, error) {
 	// Handle undefined length
-	if vl == 0xFFFFFFFF {
+	if vl == undefinedLength {
 		return r.readUndefinedLengthValue(tag, vr)
 	}
 
+	if vr == "SQ" {
+		return r.readSequence(vl)
+	}
+
+	if r.isBulkCandidate(tag, vr, vl) {
+		return r.readBulkData(vl)
+	}
+
 	// Read fixed-length value
-	data := make([]byte, vl)
-	if _, err := io.ReadFull(r.r, data); err != nil {
+	kind := ResourceElement
+	if tag == pixelDataTag {
+		kind = ResourceFrame
+	}
+	if err := r.reserve(kind, int64(vl)); err != nil {
+		return nil, err
+	}
+	data, err := readBytes(r.r, int64(vl))
+	if err != nil {
+		return nil, err
+	}
+	if err := r.checkPadding(tag, vr, data); err != nil {
 		return nil, err
 	}
 
 	// Parse based on VR
-	return parseValue(vr, data)
+	v, err := parseValue(vr, data)
+	if s, ok := v.(string); ok && err == nil && vr == "UI" {
+		var invalid []error
+		v, invalid = normalizeUIDs(s)
+		for _, e := range invalid {
+			if err := r.record(ParseIssue{Offset: r.cr.n, Tag: tag, Message: e.Error(), Err: ErrInvalidUID}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err == nil {
+		err = r.checkNumberOfFrames(tag, v)
+	}
+	return v, err
 }
 
 // readUndefinedLengthValue handles pixel data and sequences with undefined length
@@ -182,158 +589,221 @@ func (r *Reader) readUndefinedLengthValue(tag Tag, _ string) (interface{}, error
 		return r.readEncapsulatedPixelData()
 	}
 
-	// Handle sequences with undefined length (VR = SQ)
-	// Skip the entire sequence by reading until Sequence Delimitation Item
-	return r.skipUndefinedLengthSequence()
+	// Anything else with undefined length is a sequence
+	return r.readSequence(undefinedLength)
 }
 
-// skipUndefinedLengthSequence skips over a sequence with undefined length
-// Reads until Sequence Delimitation Item (FFFE,E0DD)
-func (r *Reader) skipUndefinedLengthSequence() (interface{}, error) {
-	for {
-		itemTag, err := r.readTag()
+// undefinedLength marks a value, sequence or item terminated by a delimiter
+const undefinedLength = 0xFFFFFFFF
+
+// readSequence reads the items of a sequence, either up to the Sequence
+// Delimitation Item (FFFE,E0DD) or for exactly length bytes
+func (r *Reader) readSequence(length uint32) ([]*Dataset, error) {
+	defer func() { r.depth-- }()
+	if err := r.enterSequence(); err != nil {
+		return nil, err
+	}
+	items := []*Dataset{}
+	end := r.cr.n + int64(length)
+	for length == undefinedLength || r.cr.n < end {
+		t, err := r.readTag()
 		if err != nil {
-			if err == io.EOF {
-				return nil, nil // End of file is OK
+			if err == io.EOF && length == undefinedLength {
+				return items, nil // End of file is OK
 			}
 			return nil, fmt.Errorf("reading sequence item tag: %w", err)
 		}
+		// Delimiters have a 4-byte length and no VR
+		var itemLen uint32
+		if err := binary.Read(r.r, binary.LittleEndian, &itemLen); err != nil {
+			return nil, fmt.Errorf("reading item length: %w", err)
+		}
 
-		// Check for sequence/item delimiters (these have 4-byte zero length, no VR)
-		if itemTag.Group == 0xFFFE {
+		switch t {
+		case seqDelimTag:
+			return items, nil
+		case itemDelimTag:
+			continue
+		case itemTag:
+			item, err := r.readItem(itemLen)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		default:
+			return nil, fmt.Errorf("unexpected tag %v in sequence", t)
+		}
+	}
+	return items, nil
+}
+
+// readItem reads the elements of one sequence item, either up to the Item
+// Delimitation Item (FFFE,E00D) or for exactly length bytes
+func (r *Reader) readItem(length uint32) (*Dataset, error) {
+	item := &Dataset{Elements: make(map[Tag]*Element)}
+	end := r.cr.n + int64(length)
+	for length == undefinedLength || r.cr.n < end {
+		t, err := r.readTag()
+		if err != nil {
+			return nil, fmt.Errorf("reading item element tag: %w", err)
+		}
+		if t == itemDelimTag {
 			var delimLen uint32
 			if err := binary.Read(r.r, binary.LittleEndian, &delimLen); err != nil {
 				return nil, fmt.Errorf("reading delimiter length: %w", err)
 			}
-
-			switch itemTag.Element {
-			case 0xE0DD: // Sequence Delimitation
-				return nil, nil
-			case 0xE00D: // Item Delimitation
-				// Continue reading
-				continue
-			case 0xE000: // Item Start
-				if delimLen != 0xFFFFFFFF && delimLen > 0 {
-					// Fixed length item - skip entire content
-					if _, err := io.CopyN(io.Discard, r.r, int64(delimLen)); err != nil {
-						return nil, fmt.Errorf("skipping item data: %w", err)
-					}
-				}
-				// Undefined length items - continue reading nested elements
-				continue
-			}
+			return item, nil
 		}
-
-		// Regular element - parse VR and skip
-		var vl uint32
-		if r.explicitVR {
-			var vrBytes [2]byte
-			if _, err := io.ReadFull(r.r, vrBytes[:]); err != nil {
-				return nil, fmt.Errorf("reading VR: %w", err)
-			}
-			vr := string(vrBytes[:])
-
-			if isLongVR(vr) {
-				// Skip 2 reserved bytes, then 4-byte length
-				var reserved uint16
-				binary.Read(r.r, binary.LittleEndian, &reserved)
-				binary.Read(r.r, binary.LittleEndian, &vl)
-			} else {
-				// 2-byte length
-				var vl16 uint16
-				binary.Read(r.r, binary.LittleEndian, &vl16)
-				vl = uint32(vl16)
-			}
-		} else {
-			// Implicit VR - 4 byte length
-			binary.Read(r.r, binary.LittleEndian, &vl)
+		elem, err := r.readElement(t)
+		if err != nil {
+			return nil, fmt.Errorf("reading item element %v: %w", t, err)
 		}
-
-		// Skip element value
-		if vl != 0xFFFFFFFF && vl > 0 {
-			if _, err := io.CopyN(io.Discard, r.r, int64(vl)); err != nil {
-				return nil, fmt.Errorf("skipping element value: %w", err)
-			}
-		} else if vl == 0xFFFFFFFF {
-			// Nested undefined length - recursively skip
-			if _, err := r.skipUndefinedLengthSequence(); err != nil {
-				return nil, err
-			}
+		if elem == nil {
+			continue
+		}
+		if err := r.put(item, elem); err != nil {
+			return nil, err
 		}
 	}
+	return item, nil
 }
 
-// readEncapsulatedPixelData reads encapsulated (compressed) pixel data
+// Delimiter tags used inside encapsulated pixel data
+var (
+	itemTag         = Tag{Group: 0xFFFE, Element: 0xE000}
+	itemDelimTag    = Tag{Group: 0xFFFE, Element: 0xE00D}
+	seqDelimTag     = Tag{Group: 0xFFFE, Element: 0xE0DD}
+	pixelDataTag    = Tag{Group: 0x7FE0, Element: 0x0010}
+	seqDelimPattern = []byte{0xFE, 0xFF, 0xDD, 0xE0}
+)
+
+// readEncapsulatedPixelData reads encapsulated (compressed) pixel data.
+//
+// Damaged encodings are reported as parse issues and the frames read so far
+// are kept: a missing offset table, zero-length fragments and stray item
+// delimiters are skipped; unexpected tags or undefined-length items resync to
+// the sequence delimiter; a truncated stream ends the pixel data.
 func (r *Reader) readEncapsulatedPixelData() (*PixelData, error) {
 	pd := &PixelData{
 		IsEncapsulated: true,
 		Frames:         []Frame{},
 	}
 
-	// Read Basic Offset Table (Item Tag FFFE,E000)
-	botTag, err := r.readTag()
-	if err != nil {
-		return nil, err
-	}
-	if botTag.Group != 0xFFFE || botTag.Element != 0xE000 {
-		return nil, fmt.Errorf("expected BOT item tag, got %v", botTag)
-	}
-
-	var botLength uint32
-	if err := binary.Read(r.r, binary.LittleEndian, &botLength); err != nil {
-		return nil, err
-	}
+	first := true
+	for {
+		tag, err := r.readTag()
+		if err != nil {
+			return pd, r.issue(pixelDataTag, "truncated encapsulated pixel data after %d frames: %v", len(pd.Frames), err)
+		}
+		var length uint32
+		if err := binary.Read(r.r, binary.LittleEndian, &length); err != nil {
+			return pd, r.issue(pixelDataTag, "truncated encapsulated pixel data after %d frames: %v", len(pd.Frames), err)
+		}
 
-	// Read BOT offsets
-	if botLength > 0 {
-		numOffsets := botLength / 4
-		pd.Offsets = make([]uint32, numOffsets)
-		for i := range pd.Offsets {
-			if err := binary.Read(r.r, binary.LittleEndian, &pd.Offsets[i]); err != nil {
+		switch {
+		case tag == seqDelimTag:
+			if length != 0 {
+				if err := r.issue(pixelDataTag, "sequence delimiter with length %d", length); err != nil {
+					return nil, err
+				}
+			}
+			return pd, nil
+		case tag == itemDelimTag:
+			if err := r.issue(pixelDataTag, "unexpected item delimiter in encapsulated pixel data"); err != nil {
+				return nil, err
+			}
+			continue
+		case tag != itemTag:
+			if err := r.issue(pixelDataTag, "unexpected tag %v in encapsulated pixel data", tag); err != nil {
+				return nil, err
+			}
+			return pd, r.resyncToSequenceDelimiter(pixelDataTag)
+		case length == 0xFFFFFFFF:
+			if err := r.issue(pixelDataTag, "undefined length item in encapsulated pixel data"); err != nil {
 				return nil, err
 			}
+			return pd, r.resyncToSequenceDelimiter(pixelDataTag)
 		}
-	}
 
-	// Read frames until Sequence Delimitation Item
-	for {
-		itemTag, err := r.readTag()
-		if err != nil {
+		if err := r.reserve(ResourceFrame, int64(length)); err != nil {
 			return nil, err
 		}
+		data, err := readBytes(r.r, int64(length))
+		if err != nil {
+			return pd, r.issue(pixelDataTag, "truncated fragment %d: %v", len(pd.Frames), err)
+		}
 
-		// Check for Sequence Delimitation Item (FFFE,E0DD)
-		if itemTag.Group == 0xFFFE && itemTag.Element == 0xE0DD {
-			// Read and discard length (should be 0)
-			var delimLength uint32
-			if err := binary.Read(r.r, binary.LittleEndian, &delimLength); err != nil {
+		// The first item is the Basic Offset Table
+		if first {
+			first = false
+			if offsets, ok := parseOffsetTable(data); ok {
+				pd.Offsets = offsets
+				continue
+			}
+			if err := r.issue(pixelDataTag, "basic offset table missing, first item is a fragment"); err != nil {
 				return nil, err
 			}
-			break
 		}
 
-		// Regular Item (FFFE,E000)
-		if itemTag.Group != 0xFFFE || itemTag.Element != 0xE000 {
-			return nil, fmt.Errorf("expected item tag, got %v", itemTag)
+		if length == 0 {
+			if err := r.issue(pixelDataTag, "zero-length fragment after frame %d", len(pd.Frames)); err != nil {
+				return nil, err
+			}
+			continue
 		}
-
-		var itemLength uint32
-		if err := binary.Read(r.r, binary.LittleEndian, &itemLength); err != nil {
-			return nil, err
+		if length%2 != 0 {
+			if err := r.issue(pixelDataTag, "odd length %d of fragment %d", length, len(pd.Frames)); err != nil {
+				return nil, err
+			}
+			pd.Padding = PadNone
 		}
-
-		// Read frame data
-		frameData := make([]byte, itemLength)
-		if _, err := io.ReadFull(r.r, frameData); err != nil {
+		if err := r.checkFrames(len(pd.Frames) + 1); err != nil {
 			return nil, err
 		}
-
 		pd.Frames = append(pd.Frames, Frame{
-			CompressedData: frameData,
+			CompressedData: data,
 		})
 	}
+}
 
-	return pd, nil
+// parseOffsetTable decodes a Basic Offset Table item. Items that cannot be an
+// offset table (odd sizes, offsets not starting at 0 or not ascending) are
+// fragments from a writer that omitted the table.
+func parseOffsetTable(data []byte) ([]uint32, bool) {
+	if len(data)%4 != 0 {
+		return nil, false
+	}
+	offsets := make([]uint32, len(data)/4)
+	for i := range offsets {
+		offsets[i] = binary.LittleEndian.Uint32(data[i*4:])
+		if (i == 0 && offsets[i] != 0) || (i > 0 && offsets[i] <= offsets[i-1]) {
+			return nil, false
+		}
+	}
+	return offsets, true
+}
+
+// resyncToSequenceDelimiter discards bytes up to and including the next
+// sequence delimiter item, so parsing can continue after damaged pixel data
+func (r *Reader) resyncToSequenceDelimiter(t Tag) error {
+	window := make([]byte, 0, len(seqDelimPattern))
+	b := make([]byte, 1)
+	for {
+		if _, err := io.ReadFull(r.r, b); err != nil {
+			return r.issue(t, "no sequence delimiter after the damaged value")
+		}
+		window = append(window, b[0])
+		if len(window) > len(seqDelimPattern) {
+			window = window[1:]
+		}
+		if bytes.Equal(window, seqDelimPattern) {
+			var length uint32
+			if err := binary.Read(r.r, binary.LittleEndian, &length); err != nil {
+				return r.issue(t, "truncated sequence delimiter")
+			}
+			return nil
+		}
+	}
 }
 
 // updateTransferSyntax updates reader settings based on transfer syntax
@@ -357,6 +827,9 @@ func (r *Reader) updateTransferSyntax() {
 	case "1.2.840.10008.1.2.4.90", "1.2.840.10008.1.2.4.91": // JPEG 2000
 		r.explicitVR = true
 		r.littleEndian = true
+	case "1.2.840.10008.1.2.4.50", "1.2.840.10008.1.2.4.51": // JPEG Baseline, Extended
+		r.explicitVR = true
+		r.littleEndian = true
 	}
 }
 
@@ -371,40 +844,16 @@ func isLongVR(vr string) bool {
 	return false
 }
 
-// getImplicitVR returns VR for a tag when using Implicit VR transfer syntax
+// getImplicitVR returns VR for a tag when using Implicit VR transfer syntax,
+// from the data dictionary
 func getImplicitVR(tag Tag) string {
-	// For now, return a default - in production, use a tag dictionary
-	switch {
-	case tag.Group == 0x0002: // File Meta Information
-		return "UL"
-	case tag.Group == 0x7FE0 && tag.Element == 0x0010:
-		return "OW" // Pixel Data
-	case tag.Group == 0x0028: // Image Pixel Module
-		switch tag.Element {
-		case 0x0010, 0x0011, 0x0100, 0x0101, 0x0102, 0x0103, 0x0002:
-			return "US"
-		case 0x0008:
-			return "IS" // Number of Frames
-		case 0x0030, 0x1050, 0x1051, 0x1052, 0x1053, 0x1054:
-			return "DS" // Spacing, Windowing, Rescale
-		case 0x0004:
-			return "CS" // Photometric Interpretation
-		}
-	case tag.Group == 0x0008: // General Information
-		switch tag.Element {
-		case 0x0016, 0x0018:
-			return "UI"
-		case 0x0060, 0x0008, 0x0080:
-			return "CS"
-		}
-	}
-	return "UN" // Unknown
+	return GetVR(tag)
 }
 
 // parseValue converts raw bytes to typed value based on VR
 func parseValue(vr string, data []byte) (interface{}, error) {
 	switch vr {
-	case "UI", "SH", "LO", "ST", "LT", "UT", "PN", "CS", "DA", "TM", "DT", "AS", "IS", "DS":
+	case "UI", "SH", "LO", "ST", "LT", "UT", "PN", "CS", "DA", "TM", "DT", "AS", "IS", "DS", "AE", "UC", "UR":
 		// String types - trim null padding
 		s := string(data)
 		for len(s) > 0 && (s[len(s)-1] == 0 || s[len(s)-1] == ' ') {
@@ -450,6 +899,36 @@ func parseValue(vr string, data []byte) (interface{}, error) {
 			binary.Read(bytes.NewReader(data), binary.LittleEndian, &f)
 			return f, nil
 		}
+	case "AT": // Attribute Tag, group and element pairs
+		if len(data)%4 != 0 {
+			break
+		}
+		tags := make([]Tag, len(data)/4)
+		for i := range tags {
+			tags[i] = Tag{Group: binary.LittleEndian.Uint16(data[i*4:]), Element: binary.LittleEndian.Uint16(data[i*4+2:])}
+		}
+		if len(tags) == 1 {
+			return tags[0], nil
+		}
+		return tags, nil
+	case "OL": // Other Long
+		if len(data)%4 != 0 {
+			break
+		}
+		values := make([]uint32, len(data)/4)
+		for i := range values {
+			values[i] = binary.LittleEndian.Uint32(data[i*4:])
+		}
+		return values, nil
+	case "OD": // Other Double
+		if len(data)%8 != 0 {
+			break
+		}
+		values := make([]float64, len(data)/8)
+		for i := range values {
+			values[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[i*8:]))
+		}
+		return values, nil
 	case "OB", "OW", "UN":
 		// Binary data
 		return data, nil
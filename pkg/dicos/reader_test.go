@@ -0,0 +1,560 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/jpfielding/dicos.go/pkg/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestCT writes a CT of frames rows x cols frames, whose pixel i holds i,
+// uncompressed or with codec
+func writeTestCT(t testing.TB, rows, cols, frames int, codec Codec) []byte {
+	t.Helper()
+	ct := NewCTImage()
+	ct.Codec = codec
+	ct.Patient.PatientID = "READER-001"
+	data := make([]uint16, rows*cols*frames)
+	for i := range data {
+		data[i] = uint16(i)
+	}
+	ct.Rows = rows
+	ct.Columns = cols
+	ct.SetPixelData(rows, cols, data)
+
+	var buf bytes.Buffer
+	_, err := ct.WriteTo(&buf)
+	require.NoError(t, err)
+	return buf.Bytes()
+}
+
+// newTestDataset builds a dataset of sopClass from opts, with the file meta
+// transfer syntax of codec, or Explicit VR Little Endian when codec is nil
+func newTestDataset(t testing.TB, sopClass string, codec Codec, opts ...Option) *Dataset {
+	t.Helper()
+	ts := string(ExplicitVRLittleEndian)
+	if codec != nil {
+		ts = codec.TransferSyntaxUID()
+	}
+	ds, err := NewDataset(append([]Option{WithFileMeta(sopClass, "1.2.3.4", ts)}, opts...)...)
+	require.NoError(t, err)
+	return ds
+}
+
+// rewrite writes ds and reads it back
+func rewrite(t testing.TB, ds *Dataset) *Dataset {
+	t.Helper()
+	var buf bytes.Buffer
+	_, err := Write(&buf, ds)
+	require.NoError(t, err)
+	out, err := ReadBufferContext(context.Background(), buf.Bytes())
+	require.NoError(t, err)
+	return out
+}
+
+func TestParseContext_RoundTrip(t *testing.T) {
+	data := writeTestCT(t, 8, 8, 1, nil)
+
+	ds, err := ParseContext(context.Background(), bytes.NewReader(data))
+	require.NoError(t, err)
+
+	vol, err := DecodeVolumeContext(context.Background(), ds)
+	require.NoError(t, err)
+	assert.Equal(t, 8, vol.Width)
+	assert.Equal(t, 8, vol.Height)
+}
+
+func TestParseContext_Canceled(t *testing.T) {
+	data := writeTestCT(t, 8, 8, 1, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ParseContext(ctx, bytes.NewReader(data))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestParseContext_LogsCarryRequestFields(t *testing.T) {
+	files := [][]byte{writeTestCT(t, 8, 8, 1, nil), writeTestCT(t, 8, 8, 1, CodecJPEGLS)}
+
+	var logs bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(logging.Logger(&logs, false, slog.LevelDebug))
+	defer slog.SetDefault(prev)
+
+	ctx := logging.AppendCtx(context.Background(), slog.String("request_id", "req-42"))
+
+	for _, data := range files {
+		ds, err := ParseContext(ctx, bytes.NewReader(data))
+		require.NoError(t, err)
+		_, err = DecodeVolumeContext(ctx, ds)
+		require.NoError(t, err)
+	}
+
+	msgs := map[string]bool{}
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		assert.Contains(t, line, `"request_id":"req-42"`)
+		for _, m := range []string{"Transfer syntax selected", "Converting uncompressed pixel data", "Codec decoded frame"} {
+			if strings.Contains(line, m) {
+				msgs[m] = true
+			}
+		}
+	}
+	assert.Len(t, msgs, 3, "expected reader, native and codec records: %v", msgs)
+}
+
+func TestParseWithIssues_CleanFile(t *testing.T) {
+	data := writeTestCT(t, 4, 4, 1, nil)
+
+	ds, issues, err := ParseWithIssues(context.Background(), bytes.NewReader(data), ParseOptions{Strict: true})
+	require.NoError(t, err)
+	assert.NotNil(t, ds)
+	assert.Empty(t, issues)
+}
+
+func TestParseWithIssues_StrictVsPermissive(t *testing.T) {
+	odd := Tag{Group: 0x0009, Element: 0x1001}
+	ds, err := NewDataset(
+		WithFileMeta(DICOSCTImageStorageUID, "1.2.3.4", string(ExplicitVRLittleEndian)),
+		WithElement(odd, []byte{1, 2, 3}),
+	)
+	require.NoError(t, err)
+	ds.Elements[odd].VR = "OB"
+	var buf bytes.Buffer
+	_, err = Write(&buf, ds)
+	require.NoError(t, err)
+
+	// Permissive: the dataset is read and the violation is reported
+	parsed, issues, err := ParseWithIssues(context.Background(), bytes.NewReader(buf.Bytes()), ParseOptions{})
+	require.NoError(t, err)
+	assert.True(t, HasElement(parsed, odd))
+	require.Len(t, issues, 1)
+	assert.Equal(t, odd, issues[0].Tag)
+	assert.Contains(t, issues[0].Message, "odd value length 3")
+
+	// Strict: the violation fails the parse
+	_, _, err = ParseWithIssues(context.Background(), bytes.NewReader(buf.Bytes()), ParseOptions{Strict: true})
+	var issue ParseIssue
+	require.True(t, errors.As(err, &issue))
+	assert.Equal(t, odd, issue.Tag)
+}
+
+// rawExplicit encodes a short-VR Explicit VR Little Endian element
+func rawExplicit(group, element uint16, vr string, value []byte) []byte {
+	b := binary.LittleEndian.AppendUint16(nil, group)
+	b = binary.LittleEndian.AppendUint16(b, element)
+	b = append(b, vr...)
+	b = binary.LittleEndian.AppendUint16(b, uint16(len(value)))
+	return append(b, value...)
+}
+
+// rawImplicit encodes an Implicit VR Little Endian element
+func rawImplicit(group, element uint16, value []byte) []byte {
+	b := binary.LittleEndian.AppendUint16(nil, group)
+	b = binary.LittleEndian.AppendUint16(b, element)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(value)))
+	return append(b, value...)
+}
+
+// rawExplicitLong encodes an Explicit VR element whose VR has a 4-byte length
+func rawExplicitLong(group, element uint16, vr string, value []byte) []byte {
+	b := binary.LittleEndian.AppendUint16(nil, group)
+	b = binary.LittleEndian.AppendUint16(b, element)
+	b = append(b, vr...)
+	b = append(b, 0, 0)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(value)))
+	return append(b, value...)
+}
+
+func rawFile(parts ...[]byte) []byte {
+	out := append(make([]byte, 128), "DICM"...)
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
+
+func rawGroupLength(parts ...[]byte) []byte {
+	n := 0
+	for _, p := range parts {
+		n += len(p)
+	}
+	return rawExplicit(0x0002, 0x0000, "UL", binary.LittleEndian.AppendUint32(nil, uint32(n)))
+}
+
+func TestReadDataset_AdversarialFileMeta(t *testing.T) {
+	sopClass := rawExplicit(0x0002, 0x0002, "UI", []byte("1.2.3\x00"))
+	explicitTS := rawExplicit(0x0002, 0x0010, "UI", []byte("1.2.840.10008.1.2.1\x00"))
+	implicitTS := rawExplicit(0x0002, 0x0010, "UI", []byte("1.2.840.10008.1.2\x00"))
+	rows := binary.LittleEndian.AppendUint16(nil, 4)
+	explicitBody := [][]byte{
+		rawExplicit(0x0008, 0x0060, "CS", []byte("CT")),
+		rawExplicit(0x0028, 0x0010, "US", rows),
+	}
+	implicitBody := [][]byte{
+		rawImplicit(0x0008, 0x0060, []byte("CT")),
+		rawImplicit(0x0028, 0x0010, rows),
+	}
+
+	charset := rawExplicit(0x0008, 0x0005, "CS", []byte("ISO_IR 100"))
+
+	tests := []struct {
+		name   string
+		file   []byte
+		issues int
+	}{
+		{
+			name: "transfer syntax after other meta elements",
+			file: rawFile(append([][]byte{rawGroupLength(sopClass, explicitTS), sopClass, explicitTS}, explicitBody...)...),
+		},
+		{
+			name:   "dataset element interleaved before a late transfer syntax",
+			file:   rawFile(append([][]byte{rawGroupLength(sopClass, charset, implicitTS), sopClass, charset, implicitTS}, implicitBody...)...),
+			issues: 1,
+		},
+		{
+			name:   "meta element beyond group length",
+			file:   rawFile(append([][]byte{rawGroupLength(sopClass), sopClass, implicitTS}, implicitBody...)...),
+			issues: 1,
+		},
+		{
+			name:   "missing transfer syntax with explicit body",
+			file:   rawFile(append([][]byte{sopClass}, explicitBody...)...),
+			issues: 1,
+		},
+		{
+			name:   "missing transfer syntax with implicit body",
+			file:   rawFile(append([][]byte{sopClass}, implicitBody...)...),
+			issues: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds, issues, err := ParseWithIssues(context.Background(), bytes.NewReader(tt.file), ParseOptions{})
+			require.NoError(t, err)
+			assert.Len(t, issues, tt.issues)
+			assert.Equal(t, "CT", ds.Modality())
+			assert.Equal(t, 4, ds.Rows())
+		})
+	}
+}
+
+func rawEncapsulated(items ...[]byte) []byte {
+	b := binary.LittleEndian.AppendUint16(nil, 0x7FE0)
+	b = binary.LittleEndian.AppendUint16(b, 0x0010)
+	b = append(b, "OB\x00\x00"...)
+	b = binary.LittleEndian.AppendUint32(b, 0xFFFFFFFF)
+	for _, item := range items {
+		b = append(b, item...)
+	}
+	return b
+}
+
+func TestReadEncapsulatedPixelData_Damaged(t *testing.T) {
+	meta := rawExplicit(0x0002, 0x0010, "UI", []byte("1.2.840.10008.1.2.4.80"))
+	rows := rawExplicit(0x0028, 0x0010, "US", binary.LittleEndian.AppendUint16(nil, 4))
+	bot := rawImplicit(0xFFFE, 0xE000, nil)
+	frag1 := rawImplicit(0xFFFE, 0xE000, []byte{0xFF, 0xD8, 0x01, 0x02})
+	frag2 := rawImplicit(0xFFFE, 0xE000, []byte{0xFF, 0xD8, 0x03, 0x04})
+	delim := rawImplicit(0xFFFE, 0xE0DD, nil)
+
+	tests := []struct {
+		name   string
+		file   []byte
+		frames int
+		issues int
+	}{
+		{"intact", rawFile(meta, rows, rawEncapsulated(bot, frag1, frag2, delim)), 2, 0},
+		{"missing offset table", rawFile(meta, rows, rawEncapsulated(frag1, frag2, delim)), 2, 1},
+		{"zero-length fragment", rawFile(meta, rows, rawEncapsulated(bot, frag1, bot, frag2, delim)), 2, 1},
+		{"stray item delimiter", rawFile(meta, rows, rawEncapsulated(bot, frag1, rawImplicit(0xFFFE, 0xE00D, nil), frag2, delim)), 2, 1},
+		{"unexpected tag", rawFile(meta, rows, rawEncapsulated(bot, frag1, rawImplicit(0x0008, 0x0010, []byte("junk")), frag2, delim)), 1, 1},
+		{"trailing garbage", rawFile(meta, rows, rawEncapsulated(bot, frag1, frag2, delim), []byte{0x01, 0x00, 0x02}), 2, 1},
+		{"truncated", rawFile(meta, rows, rawEncapsulated(bot, frag1, frag2[:10])), 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds, issues, err := ParseWithIssues(context.Background(), bytes.NewReader(tt.file), ParseOptions{})
+			require.NoError(t, err)
+			assert.Len(t, issues, tt.issues, "%v", issues)
+			assert.Equal(t, 4, ds.Rows())
+			pd, err := ds.GetPixelData()
+			require.NoError(t, err)
+			assert.Len(t, pd.Frames, tt.frames)
+
+			if tt.issues > 0 {
+				_, _, err := ParseWithIssues(context.Background(), bytes.NewReader(tt.file), ParseOptions{Strict: true})
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestReadSequence_FixedLength(t *testing.T) {
+	meta := rawExplicit(0x0002, 0x0010, "UI", []byte("1.2.840.10008.1.2.1\x00"))
+	inner := rawExplicit(0x0008, 0x1150, "UI", []byte("1.2.3\x00"))
+	item := binary.LittleEndian.AppendUint16(nil, 0xFFFE)
+	item = binary.LittleEndian.AppendUint16(item, 0xE000)
+	item = binary.LittleEndian.AppendUint32(item, uint32(len(inner)))
+	item = append(item, inner...)
+	seq := binary.LittleEndian.AppendUint16(nil, 0x0008)
+	seq = binary.LittleEndian.AppendUint16(seq, 0x1140)
+	seq = append(seq, "SQ\x00\x00"...)
+	seq = binary.LittleEndian.AppendUint32(seq, uint32(2*len(item)))
+	seq = append(seq, item...)
+	seq = append(seq, item...)
+	after := rawExplicit(0x0008, 0x1155, "UI", []byte("1.2.4\x00"))
+
+	ds, err := ReadBufferContext(context.Background(), rawFile(meta, seq, after))
+	require.NoError(t, err)
+	elem, ok := ds.Elements[Tag{Group: 0x0008, Element: 0x1140}]
+	require.True(t, ok)
+	items, ok := elem.Value.([]*Dataset)
+	require.True(t, ok)
+	require.Len(t, items, 2)
+	ref, ok := items[1].FindElement(0x0008, 0x1150)
+	require.True(t, ok)
+	s, _ := ref.GetString()
+	assert.Equal(t, "1.2.3", s)
+	_, ok = ds.FindElement(0x0008, 0x1155)
+	assert.True(t, ok, "elements after the sequence are read")
+}
+
+func TestReadDataset_TrailingContent(t *testing.T) {
+	meta := rawExplicit(0x0002, 0x0010, "UI", []byte("1.2.840.10008.1.2.1\x00"))
+	rows := rawExplicit(0x0028, 0x0010, "US", binary.LittleEndian.AppendUint16(nil, 1))
+	cols := rawExplicit(0x0028, 0x0011, "US", binary.LittleEndian.AppendUint16(nil, 2))
+	pixels := rawExplicitLong(0x7FE0, 0x0010, "OW", []byte{1, 0, 2, 0})
+	padding := rawExplicitLong(0xFFFC, 0xFFFC, "OB", []byte{0, 0, 0, 0})
+	blob := []byte("VENDOR-BLOB\x00\x01\x02")
+
+	tests := []struct {
+		name     string
+		file     []byte
+		trailing []byte
+		issues   int
+	}{
+		{"clean", rawFile(meta, rows, cols, pixels), nil, 0},
+		{"trailing padding element", rawFile(meta, rows, cols, pixels, padding), nil, 0},
+		{"appended blob", rawFile(meta, rows, cols, pixels, blob), blob, 1},
+		{"partial tag", rawFile(meta, rows, cols, pixels, []byte{0x01, 0x00, 0x02}), []byte{0x01, 0x00, 0x02}, 1},
+		{"delimiter at top level", rawFile(meta, rows, cols, rawImplicit(0xFFFE, 0xE0DD, nil)), rawImplicit(0xFFFE, 0xE0DD, nil), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds, issues, err := ParseWithIssues(context.Background(), bytes.NewReader(tt.file), ParseOptions{})
+			require.NoError(t, err)
+			assert.Len(t, issues, tt.issues, "%v", issues)
+			assert.Equal(t, tt.trailing, ds.Trailing)
+			assert.Equal(t, 2, ds.Columns())
+		})
+	}
+}
+
+func TestParseWithOptions_SkipPixelDataAndOnlyTags(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		codec Codec
+	}{
+		{"native", nil},
+		{"jpeg-ls", CodecJPEGLS},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			data := writeTestCT(t, 16, 16, 4, tt.codec)
+			full, err := ReadBufferContext(ctx, data)
+			require.NoError(t, err)
+
+			src := bytes.NewReader(data)
+			ds, err := ParseWithOptions(ctx, src, ParseOptions{SkipPixelData: true})
+			require.NoError(t, err)
+			assert.NotContains(t, ds.Elements, pixelDataTag)
+			assert.Equal(t, len(full.Elements)-1, len(ds.Elements))
+			assert.Greater(t, src.Len(), 16*16*4/4, "pixel data should be left unread")
+
+			path := filepath.Join(t.TempDir(), "ct.dcs")
+			require.NoError(t, os.WriteFile(path, data, 0o644))
+			only := []Tag{tag.StudyInstanceUID, tag.Rows, tag.PixelData}
+			ds, err = ReadFileWithOptions(ctx, path, ParseOptions{SkipPixelData: true, OnlyTags: only})
+			require.NoError(t, err)
+			for tg := range ds.Elements {
+				if tg.Group != 0x0002 {
+					assert.Contains(t, only[:2], tg)
+				}
+			}
+			assert.Equal(t, full.Elements[tag.StudyInstanceUID].Value, ds.Elements[tag.StudyInstanceUID].Value)
+			assert.Equal(t, 16, GetRows(ds))
+
+			// Without SkipPixelData the listed pixel data is still read
+			ds, err = ParseWithOptions(ctx, bytes.NewReader(data), ParseOptions{OnlyTags: []Tag{tag.PixelData}})
+			require.NoError(t, err)
+			assert.Contains(t, ds.Elements, pixelDataTag)
+			assert.NotContains(t, ds.Elements, tag.Rows)
+		})
+	}
+}
+
+func TestReadDataset_DuplicateTags(t *testing.T) {
+	meta := rawExplicit(0x0002, 0x0010, "UI", []byte("1.2.840.10008.1.2.1\x00"))
+	first := rawExplicit(0x0010, 0x0020, "LO", []byte("FIRST "))
+	last := rawExplicit(0x0010, 0x0020, "LO", []byte("LAST"))
+	rows := rawExplicit(0x0028, 0x0010, "US", binary.LittleEndian.AppendUint16(nil, 4))
+	file := rawFile(meta, first, last, rows)
+
+	tests := []struct {
+		name   string
+		policy DuplicatePolicy
+		want   string
+	}{
+		{"last wins", DuplicateLastWins, "LAST"},
+		{"first wins", DuplicateFirstWins, "FIRST"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds, issues, err := ParseWithIssues(context.Background(), bytes.NewReader(file), ParseOptions{Duplicates: tt.policy})
+			require.NoError(t, err)
+			require.Len(t, issues, 1)
+			assert.ErrorIs(t, issues[0], ErrDuplicateTag)
+			assert.Equal(t, tag.PatientID, issues[0].Tag)
+			id, _ := ds.Elements[tag.PatientID].GetString()
+			assert.Equal(t, tt.want, id)
+			assert.Equal(t, 4, ds.Rows(), "reading continues after the duplicate")
+
+			_, _, err = ParseWithIssues(context.Background(), bytes.NewReader(file), ParseOptions{Duplicates: tt.policy, Strict: true})
+			assert.ErrorIs(t, err, ErrDuplicateTag)
+		})
+	}
+
+	_, _, err := ParseWithIssues(context.Background(), bytes.NewReader(file), ParseOptions{Duplicates: DuplicateError})
+	assert.ErrorIs(t, err, ErrDuplicateTag)
+
+	_, err = NewStreamingReaderWithOptions(bytes.NewReader(file), ParseOptions{Duplicates: DuplicateError}).Header(context.Background())
+	assert.ErrorIs(t, err, ErrDuplicateTag)
+}
+
+func TestReadSequence_DuplicateTagsInItem(t *testing.T) {
+	meta := rawExplicit(0x0002, 0x0010, "UI", []byte("1.2.840.10008.1.2.1\x00"))
+	inner := append(rawExplicit(0x0008, 0x1150, "UI", []byte("1.2.3\x00")), rawExplicit(0x0008, 0x1150, "UI", []byte("1.2.4\x00"))...)
+	item := binary.LittleEndian.AppendUint16(nil, 0xFFFE)
+	item = binary.LittleEndian.AppendUint16(item, 0xE000)
+	item = binary.LittleEndian.AppendUint32(item, uint32(len(inner)))
+	item = append(item, inner...)
+	seq := binary.LittleEndian.AppendUint16(nil, 0x0008)
+	seq = binary.LittleEndian.AppendUint16(seq, 0x1140)
+	seq = append(seq, "SQ\x00\x00"...)
+	seq = binary.LittleEndian.AppendUint32(seq, uint32(len(item)))
+	seq = append(seq, item...)
+
+	ds, issues, err := ParseWithIssues(context.Background(), bytes.NewReader(rawFile(meta, seq)), ParseOptions{Duplicates: DuplicateFirstWins})
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.ErrorIs(t, issues[0], ErrDuplicateTag)
+	items, ok := ds.Elements[Tag{Group: 0x0008, Element: 0x1140}].Value.([]*Dataset)
+	require.True(t, ok)
+	ref, ok := items[0].FindElement(0x0008, 0x1150)
+	require.True(t, ok)
+	s, _ := ref.GetString()
+	assert.Equal(t, "1.2.3", s)
+}
+
+func TestParseWithOptions_BestEffort(t *testing.T) {
+	meta := rawExplicit(0x0002, 0x0010, "UI", []byte("1.2.840.10008.1.2.1\x00"))
+	modality := rawExplicit(0x0008, 0x0060, "CS", []byte("CT"))
+	rows := rawExplicit(0x0028, 0x0010, "US", binary.LittleEndian.AppendUint16(nil, 4))
+	ref := rawExplicit(0x0008, 0x1150, "UI", []byte("1.2.3\x00"))
+	junk := rawImplicit(0x0008, 0x0010, []byte("junk"))
+	// a sequence whose only item is replaced by a data element
+	damaged := rawExplicitLong(0x0008, 0x1115, "SQ", junk)
+	undefined := append(rawExplicitLong(0x0008, 0x1140, "SQ", nil)[:8], 0xFF, 0xFF, 0xFF, 0xFF)
+	undefined = append(append(undefined, junk...), rawImplicit(0xFFFE, 0xE0DD, nil)...)
+
+	tests := []struct {
+		name    string
+		file    []byte
+		skipped []Tag
+		ref     bool // the item of (0008,1140) is kept
+	}{
+		{
+			name:    "damaged sequence",
+			file:    rawFile(meta, modality, damaged, rows),
+			skipped: []Tag{{Group: 0x0008, Element: 0x1115}},
+		},
+		{
+			name:    "damaged sequence inside an item",
+			file:    rawFile(meta, modality, rawExplicitLong(0x0008, 0x1140, "SQ", rawImplicit(0xFFFE, 0xE000, append(slices.Clone(damaged), ref...))), rows),
+			skipped: []Tag{{Group: 0x0008, Element: 0x1115}},
+			ref:     true,
+		},
+		{
+			name:    "undefined length sequence resynced to its delimiter",
+			file:    rawFile(meta, modality, undefined, rows),
+			skipped: []Tag{{Group: 0x0008, Element: 0x1140}},
+		},
+		{
+			name:    "truncated value",
+			file:    rawFile(meta, modality, rows, rawExplicit(0x0010, 0x0010, "PN", []byte("DOE^JANE"))[:10]),
+			skipped: []Tag{{Group: 0x0010, Element: 0x0010}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseWithOptions(context.Background(), bytes.NewReader(tt.file), ParseOptions{})
+			require.Error(t, err, "fails without BestEffort")
+
+			ds, issues, err := ParseWithIssues(context.Background(), bytes.NewReader(tt.file), ParseOptions{BestEffort: true})
+			require.NoError(t, err)
+			var skipped []Tag
+			for _, issue := range issues {
+				if errors.Is(issue, ErrSkippedElement) {
+					skipped = append(skipped, issue.Tag)
+				}
+			}
+			assert.Equal(t, tt.skipped, skipped, "%v", issues)
+			assert.Equal(t, "CT", ds.Modality())
+			assert.Equal(t, 4, ds.Rows(), "elements after the damage are read")
+			for _, tag := range tt.skipped {
+				assert.NotContains(t, ds.Elements, tag)
+			}
+			if tt.ref {
+				items := GetSequenceItems(ds, Tag{Group: 0x0008, Element: 0x1140})
+				require.Len(t, items, 1)
+				assert.Equal(t, "1.2.3", attrString(items[0], Tag{Group: 0x0008, Element: 0x1150}))
+			}
+
+			_, _, err = ParseWithIssues(context.Background(), bytes.NewReader(tt.file), ParseOptions{BestEffort: true, Strict: true})
+			assert.Error(t, err, "Strict takes precedence")
+		})
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	f.Add(writeTestCT(f, 4, 4, 1, nil))
+	f.Add(writeTestCT(f, 4, 4, 1, CodecJPEGLS))
+	f.Add(writeTestCT(f, 4, 4, 1, CodecRLE))
+	meta := rawExplicit(0x0002, 0x0010, "UI", []byte("1.2.840.10008.1.2.1\x00"))
+	item := rawImplicit(0xFFFE, 0xE000, rawExplicit(0x0008, 0x1150, "UI", []byte("1.2.3\x00")))
+	f.Add(rawFile(meta, rawExplicitLong(0x0008, 0x1140, "SQ", item), rawExplicit(0x0028, 0x0008, "IS", []byte("2 "))))
+	f.Add(rawFile(meta, rawEncapsulated(rawImplicit(0xFFFE, 0xE000, nil), rawImplicit(0xFFFE, 0xE000, []byte{0xFF, 0xD8}))))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ctx := WithMemoryBudget(context.Background(), &MemoryBudget{Limit: 64 << 20})
+		opts := ParseOptions{Limits: DefaultParseLimits}
+		_, _ = ParseWithOptions(ctx, bytes.NewReader(data), opts)
+		opts.BestEffort = true
+		ds, _, err := ParseWithIssues(ctx, bytes.NewReader(data), opts)
+		if err != nil {
+			return
+		}
+		_, _ = DecodeVolumeContext(ctx, ds)
+	})
+}
@@ -0,0 +1,95 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func assertRedacted(t *testing.T, ds *Dataset, frames, rows, cols, redacted int, r image.Rectangle, zero bool) {
+	t.Helper()
+	vol, err := DecodeVolume(ds)
+	require.NoError(t, err)
+	require.Equal(t, frames, vol.Depth)
+	for z := 0; z < frames; z++ {
+		for y := 0; y < rows; y++ {
+			for x := 0; x < cols; x++ {
+				v := vol.Data[(z*rows+y)*cols+x]
+				orig := uint16((z*rows+y)*cols + x)
+				switch {
+				case z == redacted && image.Pt(x, y).In(r) && zero:
+					require.Zero(t, v, "frame %d (%d,%d)", z, x, y)
+				case z == redacted && image.Pt(x, y).In(r):
+				default:
+					require.Equal(t, orig, v, "frame %d (%d,%d)", z, x, y)
+				}
+			}
+		}
+	}
+	annot, ok := ds.FindElement(tag.BurnedInAnnotation.Group, tag.BurnedInAnnotation.Element)
+	require.True(t, ok)
+	s, _ := annot.GetString()
+	assert.Equal(t, "NO", s)
+}
+
+func TestRedactRegion_Native(t *testing.T) {
+	ds, err := ReadBufferContext(context.Background(), writeTestCT(t, 8, 8, 2, nil))
+	require.NoError(t, err)
+
+	r := image.Rect(2, 3, 5, 20) // clipped to the image
+	require.NoError(t, RedactRegion(ds, 1, r))
+	assertRedacted(t, rewrite(t, ds), 2, 8, 8, 1, r, true)
+}
+
+func TestRedactRegion_Encapsulated(t *testing.T) {
+	ds, err := ReadBufferContext(context.Background(), writeTestCT(t, 8, 8, 3, CodecJPEGLS))
+	require.NoError(t, err)
+	pd, err := ds.GetPixelData()
+	require.NoError(t, err)
+	untouched := bytes.Clone(pd.Frames[0].CompressedData)
+
+	r := image.Rect(0, 0, 4, 4)
+	require.NoError(t, RedactRegionContext(context.Background(), ds, 1, r, RedactOptions{Fill: RedactNoise, Seed: 7}))
+	assert.Equal(t, untouched, pd.Frames[0].CompressedData, "other frames are not re-encoded")
+
+	out := rewrite(t, ds)
+	assertRedacted(t, out, 3, 8, 8, 1, r, false)
+	outPD, err := out.GetPixelData()
+	require.NoError(t, err)
+	assert.Equal(t, pd.Offsets, outPD.Offsets)
+}
+
+func TestRedactRegion_RGB(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for i := range img.Pix {
+		img.Pix[i] = 200
+	}
+	sc := NewSecondaryCaptureImage()
+	sc.Image = img
+	var buf bytes.Buffer
+	_, err := sc.WriteTo(&buf)
+	require.NoError(t, err)
+	ds, err := ReadBufferContext(context.Background(), buf.Bytes())
+	require.NoError(t, err)
+
+	require.NoError(t, RedactRegion(ds, 0, image.Rect(1, 1, 3, 3)))
+	got, err := SecondaryCaptureFromDataset(context.Background(), rewrite(t, ds))
+	require.NoError(t, err)
+	assert.Equal(t, color.RGBA{0, 0, 0, 255}, got.Image.At(1, 2))
+	assert.Equal(t, color.RGBA{200, 200, 200, 255}, got.Image.At(0, 0))
+}
+
+func TestRedactRegion_Errors(t *testing.T) {
+	ds, err := ReadBufferContext(context.Background(), writeTestCT(t, 8, 8, 1, nil))
+	require.NoError(t, err)
+	assert.ErrorContains(t, RedactRegion(ds, 0, image.Rect(10, 10, 20, 20)), "outside")
+	assert.ErrorContains(t, RedactRegion(ds, 1, image.Rect(0, 0, 2, 2)), "out of range")
+	_, ok := ds.FindElement(tag.BurnedInAnnotation.Group, tag.BurnedInAnnotation.Element)
+	assert.False(t, ok, "failed redactions are not recorded")
+}
@@ -0,0 +1,250 @@
+package dicos
+
+import (
+	"fmt"
+	"math"
+)
+
+// Resample returns the volume interpolated trilinearly onto voxels of the
+// given spacing in mm. The first voxel keeps its position and the extent is
+// kept up to the last whole voxel that fits, so world coordinates are
+// unchanged. Signed volumes are interpolated as signed values.
+//
+// Example:
+//
+//	vol, _ := dicos.DecodeVolume(ds)
+//	fine, err := vol.Resample(0.5, 0.5, 0.5)
+func (v *Volume) Resample(spacingX, spacingY, spacingZ float64) (*Volume, error) {
+	if spacingX <= 0 || spacingY <= 0 || spacingZ <= 0 {
+		return nil, fmt.Errorf("resample spacing must be positive, got %gx%gx%g", spacingX, spacingY, spacingZ)
+	}
+	if v.SpacingX <= 0 || v.SpacingY <= 0 || v.SpacingZ <= 0 {
+		return nil, fmt.Errorf("volume spacing must be positive, got %gx%gx%g", v.SpacingX, v.SpacingY, v.SpacingZ)
+	}
+	if v.Width == 0 || v.Height == 0 || v.Depth == 0 {
+		return nil, fmt.Errorf("cannot resample an empty %dx%dx%d volume", v.Width, v.Height, v.Depth)
+	}
+	size := func(n int, from, to float64) int {
+		return int(math.Floor(float64(n-1)*from/to+1e-9)) + 1
+	}
+	n := v.samples()
+	out := v.withGeometry(size(v.Width, v.SpacingX, spacingX), size(v.Height, v.SpacingY, spacingY), size(v.Depth, v.SpacingZ, spacingZ))
+	out.SpacingX, out.SpacingY, out.SpacingZ = spacingX, spacingY, spacingZ
+
+	rx, ry, rz := spacingX/v.SpacingX, spacingY/v.SpacingY, spacingZ/v.SpacingZ
+	for z := 0; z < out.Depth; z++ {
+		for y := 0; y < out.Height; y++ {
+			for x := 0; x < out.Width; x++ {
+				idx := out.index(x, y, z)
+				for s := range n {
+					val := v.interpolate(float64(x)*rx, float64(y)*ry, float64(z)*rz, s)
+					out.Data[idx+s] = v.fromFloat(val)
+				}
+			}
+		}
+	}
+	return out, nil
+}
+
+// ResampleIsotropic resamples the volume to cubic voxels of spacing mm, or
+// of its finest spacing when spacing is 0, so distances and reslices are the
+// same along every axis
+func (v *Volume) ResampleIsotropic(spacing float64) (*Volume, error) {
+	if spacing == 0 {
+		spacing = min(v.SpacingX, v.SpacingY, v.SpacingZ)
+	}
+	return v.Resample(spacing, spacing, spacing)
+}
+
+// interpolate returns sample s at the fractional voxel position (x, y, z),
+// clamped to the volume
+func (v *Volume) interpolate(x, y, z float64, s int) float64 {
+	x0, fx := splitCoord(x, v.Width)
+	y0, fy := splitCoord(y, v.Height)
+	z0, fz := splitCoord(z, v.Depth)
+	x1, y1, z1 := min(x0+1, v.Width-1), min(y0+1, v.Height-1), min(z0+1, v.Depth-1)
+
+	at := func(x, y, z int) float64 {
+		return v.toFloat(v.Data[v.index(x, y, z)+s])
+	}
+	lerp := func(a, b, f float64) float64 { return a + (b-a)*f }
+	c00 := lerp(at(x0, y0, z0), at(x1, y0, z0), fx)
+	c10 := lerp(at(x0, y1, z0), at(x1, y1, z0), fx)
+	c01 := lerp(at(x0, y0, z1), at(x1, y0, z1), fx)
+	c11 := lerp(at(x0, y1, z1), at(x1, y1, z1), fx)
+	return lerp(lerp(c00, c10, fy), lerp(c01, c11, fy), fz)
+}
+
+// splitCoord clamps c to [0, n-1] and splits it into a voxel index and the
+// fraction towards the next voxel
+func splitCoord(c float64, n int) (int, float64) {
+	c = min(max(c, 0), float64(n-1))
+	i := int(c)
+	return i, c - float64(i)
+}
+
+// toFloat returns a stored sample as its value
+func (v *Volume) toFloat(val uint16) float64 {
+	if v.Signed {
+		return float64(int16(val))
+	}
+	return float64(val)
+}
+
+// fromFloat rounds a value to a stored sample
+func (v *Volume) fromFloat(f float64) uint16 {
+	f = math.Round(f)
+	if v.Signed {
+		return uint16(int16(min(max(f, math.MinInt16), math.MaxInt16)))
+	}
+	return uint16(min(max(f, 0), math.MaxUint16))
+}
+
+// withGeometry returns an empty volume of the given size with the spacing,
+// origin, orientation, samples and signedness of v
+func (v *Volume) withGeometry(width, height, depth int) *Volume {
+	out := NewVolumeSamples(width, height, depth, v.samples())
+	out.SpacingX, out.SpacingY, out.SpacingZ = v.SpacingX, v.SpacingY, v.SpacingZ
+	out.OriginX, out.OriginY, out.OriginZ = v.OriginX, v.OriginY, v.OriginZ
+	out.Orientation = v.Orientation
+	out.Signed = v.Signed
+	return out
+}
+
+// Reslice returns the volume reordered so the given plane (SliceAxial,
+// SliceCoronal or SliceSagittal) is its slice plane: slice z of a coronal
+// reslice is the XZ plane of the source, with its rows running from the last
+// source slice to the first. Spacing, origin and orientation are updated so
+// voxels keep their world coordinates.
+//
+// Example:
+//
+//	coronal, err := vol.Reslice(dicos.SliceCoronal)
+//	mid := coronal.Slice(dicos.SliceAxial, coronal.Depth/2)
+func (v *Volume) Reslice(plane int) (*Volume, error) {
+	row, col, normal := v.axes()
+	neg := func(d [3]float64) [3]float64 { return [3]float64{-d[0], -d[1], -d[2]} }
+
+	// source returns the source voxel of output voxel (x, y, z)
+	var source func(x, y, z int) (int, int, int)
+	var out *Volume
+	switch plane {
+	case SliceAxial:
+		out = v.withGeometry(v.Width, v.Height, v.Depth)
+		copy(out.Data, v.Data)
+		return out, nil
+	case SliceCoronal:
+		// rows along X, columns against the slice normal, slices along Y
+		out = v.withGeometry(v.Width, v.Depth, v.Height)
+		out.SpacingX, out.SpacingY, out.SpacingZ = v.SpacingX, v.SpacingZ, v.SpacingY
+		source = func(x, y, z int) (int, int, int) { return x, z, v.Depth - 1 - y }
+		col = neg(normal)
+	case SliceSagittal:
+		// rows along Y, columns against the slice normal, slices against X
+		out = v.withGeometry(v.Height, v.Depth, v.Width)
+		out.SpacingX, out.SpacingY, out.SpacingZ = v.SpacingY, v.SpacingZ, v.SpacingX
+		source = func(x, y, z int) (int, int, int) { return v.Width - 1 - z, x, v.Depth - 1 - y }
+		row, col = col, neg(normal)
+	default:
+		return nil, fmt.Errorf("unknown slice plane %d", plane)
+	}
+	copy(out.Orientation[:3], row[:])
+	copy(out.Orientation[3:], col[:])
+	sx, sy, sz := source(0, 0, 0)
+	origin := v.VoxelToWorld(float64(sx), float64(sy), float64(sz), LPS)
+	out.OriginX, out.OriginY, out.OriginZ = origin[0], origin[1], origin[2]
+
+	n := v.samples()
+	for z := 0; z < out.Depth; z++ {
+		for y := 0; y < out.Height; y++ {
+			for x := 0; x < out.Width; x++ {
+				sx, sy, sz := source(x, y, z)
+				src := v.index(sx, sy, sz)
+				copy(out.Data[out.index(x, y, z):], v.Data[src:src+n])
+			}
+		}
+	}
+	return out, nil
+}
+
+// Crop returns the voxels from (x0, y0, z0) up to but excluding (x1, y1, z1),
+// clipped to the volume, with the origin moved to the first kept voxel
+func (v *Volume) Crop(x0, y0, z0, x1, y1, z1 int) (*Volume, error) {
+	x0, y0, z0 = max(x0, 0), max(y0, 0), max(z0, 0)
+	x1, y1, z1 = min(x1, v.Width), min(y1, v.Height), min(z1, v.Depth)
+	if x0 >= x1 || y0 >= y1 || z0 >= z1 {
+		return nil, fmt.Errorf("crop box is outside the %dx%dx%d volume", v.Width, v.Height, v.Depth)
+	}
+	out := v.withGeometry(x1-x0, y1-y0, z1-z0)
+	origin := v.VoxelToWorld(float64(x0), float64(y0), float64(z0), LPS)
+	out.OriginX, out.OriginY, out.OriginZ = origin[0], origin[1], origin[2]
+
+	n := v.samples()
+	rowLen := out.Width * n
+	for z := 0; z < out.Depth; z++ {
+		for y := 0; y < out.Height; y++ {
+			src := v.index(x0, y0+y, z0+z)
+			copy(out.Data[out.index(0, y, z):], v.Data[src:src+rowLen])
+		}
+	}
+	return out, nil
+}
+
+// CropWorld returns the voxels inside the box with opposite corners a and b,
+// in LPS world coordinates in mm, clipped to the volume. Every voxel whose
+// extent the box reaches is kept, so an oblique box is cut to the
+// voxel-aligned box around it.
+//
+// Example:
+//
+//	sub, err := vol.CropWorld([3]float64{-40, -20, 100}, [3]float64{40, 20, 180})
+func (v *Volume) CropWorld(a, b [3]float64) (*Volume, error) {
+	lo := [3]float64{math.Inf(1), math.Inf(1), math.Inf(1)}
+	hi := [3]float64{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	for corner := range 8 {
+		p := a
+		for i := range p {
+			if corner&(1<<i) != 0 {
+				p[i] = b[i]
+			}
+		}
+		idx := v.WorldToVoxel(p, LPS)
+		for i := range idx {
+			// voxel k covers positions k-0.5 to k+0.5 around its center
+			lo[i], hi[i] = min(lo[i], idx[i]+0.5), max(hi[i], idx[i]+0.5)
+		}
+	}
+	return v.cropRange(lo, hi, 0)
+}
+
+// CropBoundingBox returns the voxels of a TDR bounding box, given in
+// (column, row, frame) as the library reads and writes it, widened by margin
+// voxels on every side and clipped to the volume. It cuts the sub-volume
+// around a PTO for secondary analysis.
+//
+// Example:
+//
+//	for _, pto := range tdr.PTOs {
+//		if pto.BoundingBox != nil {
+//			sub, err := vol.CropBoundingBox(*pto.BoundingBox, 4)
+//		}
+//	}
+func (v *Volume) CropBoundingBox(b BoundingBox, margin int) (*Volume, error) {
+	var lo, hi [3]float64
+	for i := range lo {
+		lo[i] = float64(min(b.TopLeft[i], b.BottomRight[i]))
+		hi[i] = float64(max(b.TopLeft[i], b.BottomRight[i]))
+	}
+	return v.cropRange(lo, hi, margin)
+}
+
+// cropRange crops to the voxels from floor(lo) to floor(hi), widened by
+// margin voxels
+func (v *Volume) cropRange(lo, hi [3]float64, margin int) (*Volume, error) {
+	var from, to [3]int
+	for i := range lo {
+		from[i] = int(math.Floor(lo[i]+1e-9)) - margin
+		to[i] = int(math.Floor(hi[i]+1e-9)) + 1 + margin
+	}
+	return v.Crop(from[0], from[1], from[2], to[0], to[1], to[2])
+}
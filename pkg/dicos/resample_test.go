@@ -0,0 +1,121 @@
+package dicos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// geometryVolume returns a 4x3x5 volume of distinct voxels with anisotropic
+// spacing and an oblique orientation
+func geometryVolume() *Volume {
+	v := NewVolume(4, 3, 5)
+	v.SpacingX, v.SpacingY, v.SpacingZ = 0.5, 0.75, 2
+	v.OriginX, v.OriginY, v.OriginZ = -10, 20, 100
+	v.Orientation = [6]float64{0.8, 0.6, 0, -0.6, 0.8, 0}
+	for i := range v.Data {
+		v.Data[i] = uint16(i)
+	}
+	return v
+}
+
+func TestVolume_WorldToVoxel(t *testing.T) {
+	v := geometryVolume()
+	for _, conv := range []Convention{LPS, RAS} {
+		p := v.VoxelToWorld(1.5, 2, 3.25, conv)
+		got := v.WorldToVoxel(p, conv)
+		assert.InDeltaSlice(t, []float64{1.5, 2, 3.25}, got[:], 1e-9, conv.String())
+	}
+}
+
+func TestVolume_Resample(t *testing.T) {
+	v := NewVolume(3, 1, 1)
+	v.SpacingX = 2
+	copy(v.Data, []uint16{0, 100, 300})
+
+	out, err := v.Resample(1, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 5, out.Width)
+	assert.Equal(t, []uint16{0, 50, 100, 200, 300}, out.Data)
+
+	// the first voxel keeps its position
+	v = geometryVolume()
+	iso, err := v.ResampleIsotropic(0)
+	require.NoError(t, err)
+	assert.Equal(t, [3]float64{0.5, 0.5, 0.5}, [3]float64{iso.SpacingX, iso.SpacingY, iso.SpacingZ})
+	assert.Equal(t, [3]int{4, 4, 17}, [3]int{iso.Width, iso.Height, iso.Depth})
+	want, got := v.VoxelToWorld(3, 2, 4, LPS), iso.VoxelToWorld(3, 3, 16, LPS)
+	assert.InDeltaSlice(t, want[:], got[:], 1e-9)
+	assert.Equal(t, v.Get(3, 0, 4), iso.Get(3, 0, 16))
+
+	_, err = v.Resample(0, 1, 1)
+	assert.ErrorContains(t, err, "must be positive")
+}
+
+func TestVolume_ResampleSigned(t *testing.T) {
+	v := NewVolume(2, 1, 1)
+	v.Signed = true
+	copy(v.Data, Uint16Samples([]int16{-1000, 1000}))
+
+	out, err := v.Resample(0.5, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []int16{-1000, 0, 1000}, out.Int16Data())
+	assert.True(t, out.Signed)
+}
+
+func TestVolume_ResliceKeepsWorldCoordinates(t *testing.T) {
+	v := geometryVolume()
+	for _, plane := range []int{SliceAxial, SliceCoronal, SliceSagittal} {
+		out, err := v.Reslice(plane)
+		require.NoError(t, err)
+		require.Len(t, out.Data, len(v.Data))
+		for z := 0; z < out.Depth; z++ {
+			for y := 0; y < out.Height; y++ {
+				for x := 0; x < out.Width; x++ {
+					// find the source voxel by its world coordinate
+					p := out.VoxelToWorld(float64(x), float64(y), float64(z), LPS)
+					src := v.WorldToVoxel(p, LPS)
+					sx, sy, sz := int(src[0]+0.5), int(src[1]+0.5), int(src[2]+0.5)
+					require.InDeltaSlice(t, []float64{float64(sx), float64(sy), float64(sz)}, src[:], 1e-9, "plane %d", plane)
+					require.Equal(t, v.Get(sx, sy, sz), out.Get(x, y, z), "plane %d voxel (%d,%d,%d)", plane, x, y, z)
+				}
+			}
+		}
+	}
+
+	coronal, err := v.Reslice(SliceCoronal)
+	require.NoError(t, err)
+	assert.Equal(t, [3]int{4, 5, 3}, [3]int{coronal.Width, coronal.Height, coronal.Depth})
+
+	_, err = v.Reslice(3)
+	assert.Error(t, err)
+}
+
+func TestVolume_Crop(t *testing.T) {
+	v := geometryVolume()
+	out, err := v.Crop(1, 1, 2, 3, 10, 4)
+	require.NoError(t, err)
+	assert.Equal(t, [3]int{2, 2, 2}, [3]int{out.Width, out.Height, out.Depth})
+	assert.Equal(t, v.Get(1, 1, 2), out.Get(0, 0, 0))
+	assert.Equal(t, v.Get(2, 2, 3), out.Get(1, 1, 1))
+	want, got := v.VoxelToWorld(1, 1, 2, LPS), out.VoxelToWorld(0, 0, 0, LPS)
+	assert.InDeltaSlice(t, want[:], got[:], 1e-9)
+
+	_, err = v.Crop(5, 0, 0, 8, 1, 1)
+	assert.ErrorContains(t, err, "outside")
+}
+
+func TestVolume_CropWorldAndBoundingBox(t *testing.T) {
+	v := geometryVolume()
+	out, err := v.CropWorld(v.VoxelToWorld(1, 0, 1, LPS), v.VoxelToWorld(2, 1, 3, LPS))
+	require.NoError(t, err)
+	assert.Equal(t, [3]int{2, 2, 3}, [3]int{out.Width, out.Height, out.Depth})
+	assert.Equal(t, v.Get(1, 0, 1), out.Get(0, 0, 0))
+
+	box := BoundingBox{TopLeft: [3]float32{2, 1, 3}, BottomRight: [3]float32{1, 1, 3}}
+	out, err = v.CropBoundingBox(box, 1)
+	require.NoError(t, err)
+	assert.Equal(t, [3]int{4, 3, 3}, [3]int{out.Width, out.Height, out.Depth}, "margin is clipped to the volume")
+	assert.Equal(t, v.Get(0, 0, 2), out.Get(0, 0, 0))
+}
@@ -0,0 +1,328 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/draw"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/module"
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/jpfielding/dicos.go/pkg/dicos/transfer"
+)
+
+// SecondaryCaptureImage represents a Secondary Capture Image IOD: an 8-bit
+// RGB rendering such as an annotated review screen or a report page, archived
+// alongside the DICOS instances it was made from.
+type SecondaryCaptureImage struct {
+	// Modules
+	Patient   module.PatientModule
+	Study     module.GeneralStudyModule
+	Series    module.GeneralSeriesModule
+	Equipment module.GeneralEquipmentModule
+	SOPCommon module.SOPCommonModule
+
+	// SC Equipment and Image Attributes
+	ConversionType        string // WSD (workstation) by default
+	DerivationDescription string // e.g. "Operator review screen"
+	InstanceNumber        int
+	ContentDate           module.Date
+	ContentTime           module.Time
+
+	// SourceImages are the DICOS instances shown in the capture
+	SourceImages []SourceImage
+
+	// ICCProfile is the color profile of the pixel data; nil means sRGB.
+	// The pixels are stored as rendered, without color management.
+	ICCProfile []byte
+
+	// Image is the rendered capture, stored as 8-bit RGB
+	Image image.Image
+	Codec Codec // nil = uncompressed; must support RGB, see CodecJPEG2000
+
+	// Additional Tags (Generic support for tags not explicitly defined)
+	AdditionalTags map[tag.Tag]interface{}
+}
+
+// SourceImage references an instance a derived image was made from
+type SourceImage struct {
+	SOPClassUID    string
+	SOPInstanceUID string
+}
+
+// rgbCodecs are the codecs that encode and decode RGB images
+var rgbCodecs = map[string]bool{
+	"jpeg-2000": true,
+}
+
+// NewSecondaryCaptureImage creates a new Secondary Capture Image with default values
+func NewSecondaryCaptureImage() *SecondaryCaptureImage {
+	t := time.Now()
+	return &SecondaryCaptureImage{
+		ConversionType: "WSD",
+		ContentDate:    module.NewDate(t),
+		ContentTime:    module.NewTime(t),
+		Series:         module.GeneralSeriesModule{Modality: "OT"},
+		Study:          module.NewGeneralStudyModule(),
+		SOPCommon:      module.NewSOPCommonModule(),
+		AdditionalTags: make(map[tag.Tag]interface{}),
+	}
+}
+
+// AddSource references a DICOS instance shown in the capture, taking its
+// SOP Class and Instance UIDs from the dataset
+func (sc *SecondaryCaptureImage) AddSource(ds *Dataset) error {
+	src, err := sourceInstance(ds)
+	if err != nil {
+		return err
+	}
+	sc.SourceImages = append(sc.SourceImages, src)
+	return nil
+}
+
+// sourceInstance returns the reference to ds as a source instance
+func sourceInstance(ds *Dataset) (SourceImage, error) {
+	var src SourceImage
+	if elem, ok := ds.FindElement(tag.SOPClassUID.Group, tag.SOPClassUID.Element); ok {
+		src.SOPClassUID, _ = elem.GetString()
+	}
+	if elem, ok := ds.FindElement(tag.SOPInstanceUID.Group, tag.SOPInstanceUID.Element); ok {
+		src.SOPInstanceUID, _ = elem.GetString()
+	}
+	if src.SOPClassUID == "" || src.SOPInstanceUID == "" {
+		return src, fmt.Errorf("source dataset has no SOP Class/Instance UID")
+	}
+	return src, nil
+}
+
+// GetDataset builds and returns the DICOS Dataset
+func (sc *SecondaryCaptureImage) GetDataset() (*Dataset, error) {
+	if sc.Image == nil {
+		return nil, fmt.Errorf("secondary capture has no image")
+	}
+	if sc.Codec != nil && !rgbCodecs[sc.Codec.Name()] {
+		return nil, fmt.Errorf("codec %s does not support RGB", sc.Codec.Name())
+	}
+	opts := make([]Option, 0, 32)
+
+	// 1. File Meta Information
+	tsUID := string(transfer.ExplicitVRLittleEndian)
+	if sc.Codec != nil {
+		tsUID = sc.Codec.TransferSyntaxUID()
+	}
+	if sc.SOPCommon.SOPInstanceUID == "" {
+		sc.SOPCommon.SOPInstanceUID = NewUID()
+	}
+	sc.SOPCommon.SOPClassUID = SecondaryCaptureImageStorageUID
+	if sc.Study.StudyInstanceUID == "" {
+		sc.Study.StudyInstanceUID = NewUID()
+	}
+	if sc.Series.SeriesInstanceUID == "" {
+		sc.Series.SeriesInstanceUID = NewUID()
+	}
+	opts = append(opts, WithFileMeta(SecondaryCaptureImageStorageUID, sc.SOPCommon.SOPInstanceUID, tsUID))
+
+	// 2. Modules
+	opts = append(opts,
+		WithModule(sc.Patient.ToTags()),
+		WithModule(sc.Study.ToTags()),
+		WithModule(sc.Series.ToTags()),
+		WithModule(sc.Equipment.ToTags()),
+		WithModule(sc.SOPCommon.ToTags()),
+		WithElement(tag.ConversionType, sc.ConversionType),
+		WithElement(tag.ContentDate, sc.ContentDate.String()),
+		WithElement(tag.ContentTime, sc.ContentTime.String()),
+		WithElement(tag.InstanceNumber, strconv.Itoa(sc.InstanceNumber)),
+		WithImageType(ImageType{PixelData: ImageTypeDerived, Examination: ImageTypeSecondary}),
+	)
+	if sc.DerivationDescription != "" {
+		opts = append(opts, WithElement(tag.DerivationDescription, sc.DerivationDescription))
+	}
+	if len(sc.SourceImages) > 0 {
+		items := make([]*Dataset, len(sc.SourceImages))
+		for i, src := range sc.SourceImages {
+			item, err := NewDataset(
+				WithElement(tag.ReferencedSOPClassUID, src.SOPClassUID),
+				WithElement(tag.ReferencedSOPInstanceUID, src.SOPInstanceUID),
+			)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		opts = append(opts, WithSequence(tag.SourceImageSequence, items...))
+	}
+	if sc.ICCProfile != nil {
+		opts = append(opts, WithElement(tag.ICCProfile, padEven(sc.ICCProfile)))
+	}
+
+	// 3. Image Pixel Module
+	b := sc.Image.Bounds()
+	opts = append(opts,
+		WithElement(tag.Rows, b.Dy()),
+		WithElement(tag.Columns, b.Dx()),
+		WithElement(tag.SamplesPerPixel, 3),
+		WithElement(tag.PhotometricInterpretation, "RGB"),
+		WithElement(tag.PlanarConfiguration, 0),
+		WithElement(tag.BitsAllocated, 8),
+		WithElement(tag.BitsStored, 8),
+		WithElement(tag.HighBit, 7),
+		WithElement(tag.PixelRepresentation, 0),
+	)
+
+	// Additional Tags
+	for t, v := range sc.AdditionalTags {
+		opts = append(opts, WithElement(t, v))
+	}
+
+	// 4. Pixel Data
+	if sc.Codec != nil {
+		var buf bytes.Buffer
+		if err := sc.Codec.Encode(&buf, sc.Image); err != nil {
+			return nil, fmt.Errorf("%s encode error: %w", sc.Codec.Name(), err)
+		}
+		pd := &PixelData{
+			IsEncapsulated: true,
+			Frames:         []Frame{{CompressedData: padFragment(buf.Bytes(), paddingOf(sc.Codec))}},
+			Offsets:        []uint32{0},
+			Padding:        paddingOf(sc.Codec),
+		}
+		opts = append(opts, WithRawPixelData(pd))
+	} else {
+		opts = append(opts, withVR(tag.PixelData, "OB", padEven(interleavedRGB(sc.Image))))
+	}
+
+	return NewDataset(opts...)
+}
+
+// WriteTo writes the Secondary Capture Image to any io.Writer
+func (sc *SecondaryCaptureImage) WriteTo(w io.Writer) (int64, error) {
+	dataset, err := sc.GetDataset()
+	if err != nil {
+		return 0, err
+	}
+	return Write(w, dataset)
+}
+
+// Write saves the Secondary Capture Image to a DICOS file (convenience wrapper)
+func (sc *SecondaryCaptureImage) Write(path string) (int64, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return sc.WriteTo(f)
+}
+
+// SecondaryCaptureFromDataset reads a Secondary Capture Image back from a
+// dataset, decoding its pixel data to an *image.RGBA. Patient, equipment and
+// descriptive module fields are not restored; the UIDs, source references,
+// ICC profile and image are.
+//
+// Example:
+//
+//	ds, _ := dicos.ReadFile("review.dcs")
+//	sc, err := dicos.SecondaryCaptureFromDataset(ctx, ds)
+//	png.Encode(out, sc.Image)
+func SecondaryCaptureFromDataset(ctx context.Context, ds *Dataset) (*SecondaryCaptureImage, error) {
+	str := func(t tag.Tag) string {
+		if elem, ok := ds.FindElement(t.Group, t.Element); ok {
+			s, _ := elem.GetString()
+			return s
+		}
+		return ""
+	}
+	if uid := str(tag.SOPClassUID); uid != SecondaryCaptureImageStorageUID {
+		return nil, fmt.Errorf("not a secondary capture image: SOP Class UID %q", uid)
+	}
+	if ds.SamplesPerPixel() != 3 || ds.BitsAllocated() != 8 {
+		return nil, fmt.Errorf("secondary capture must be 8-bit RGB, got %d samples of %d bits", ds.SamplesPerPixel(), ds.BitsAllocated())
+	}
+
+	sc := &SecondaryCaptureImage{
+		ConversionType:        str(tag.ConversionType),
+		DerivationDescription: str(tag.DerivationDescription),
+		AdditionalTags:        make(map[tag.Tag]interface{}),
+	}
+	sc.SOPCommon.SOPClassUID = SecondaryCaptureImageStorageUID
+	sc.SOPCommon.SOPInstanceUID = str(tag.SOPInstanceUID)
+	sc.Study.StudyInstanceUID = str(tag.StudyInstanceUID)
+	sc.Series.SeriesInstanceUID = str(tag.SeriesInstanceUID)
+	sc.Series.Modality = str(tag.Modality)
+	sc.InstanceNumber, _ = strconv.Atoi(str(tag.InstanceNumber))
+	for _, item := range GetSequenceItems(ds, tag.SourceImageSequence) {
+		src := SourceImage{}
+		if elem, ok := item.FindElement(tag.ReferencedSOPClassUID.Group, tag.ReferencedSOPClassUID.Element); ok {
+			src.SOPClassUID, _ = elem.GetString()
+		}
+		if elem, ok := item.FindElement(tag.ReferencedSOPInstanceUID.Group, tag.ReferencedSOPInstanceUID.Element); ok {
+			src.SOPInstanceUID, _ = elem.GetString()
+		}
+		sc.SourceImages = append(sc.SourceImages, src)
+	}
+	if elem, ok := ds.FindElement(tag.ICCProfile.Group, tag.ICCProfile.Element); ok {
+		sc.ICCProfile, _ = elem.Value.([]byte)
+	}
+
+	rows, cols := ds.Rows(), ds.Columns()
+	elem, ok := ds.FindElement(tag.PixelData.Group, tag.PixelData.Element)
+	if !ok {
+		return nil, fmt.Errorf("no pixel data element found")
+	}
+	switch v := elem.Value.(type) {
+	case []byte:
+		if len(v) < rows*cols*3 {
+			return nil, fmt.Errorf("pixel data truncated: expected %d bytes, got %d", rows*cols*3, len(v))
+		}
+		img := image.NewRGBA(image.Rect(0, 0, cols, rows))
+		for i := 0; i < rows*cols; i++ {
+			copy(img.Pix[i*4:], v[i*3:i*3+3])
+			img.Pix[i*4+3] = 0xFF
+		}
+		sc.Image = img
+	case *PixelData:
+		if !v.IsEncapsulated || len(v.Frames) == 0 {
+			return nil, fmt.Errorf("unexpected native pixel data for RGB image")
+		}
+		ts := string(ds.TransferSyntax())
+		if sc.Codec = CodecByTransferSyntax(ts); sc.Codec == nil {
+			return nil, fmt.Errorf("no codec for transfer syntax %s", ts)
+		}
+		decoded, err := decodeWithCodec(ctx, sc.Codec, v.Frames[0].CompressedData, cols, rows)
+		if err != nil {
+			return nil, err
+		}
+		img := image.NewRGBA(decoded.Bounds())
+		draw.Draw(img, img.Bounds(), decoded, decoded.Bounds().Min, draw.Src)
+		sc.Image = img
+	default:
+		return nil, fmt.Errorf("pixel data element has unexpected type: %T", elem.Value)
+	}
+	return sc, nil
+}
+
+// interleavedRGB flattens img into R, G, B bytes per pixel in row-major order
+func interleavedRGB(img image.Image) []byte {
+	b := img.Bounds()
+	out := make([]byte, 0, b.Dx()*b.Dy()*3)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, g, bl, _ := img.At(x, y).RGBA()
+			out = append(out, uint8(r>>8), uint8(g>>8), uint8(bl>>8))
+		}
+	}
+	return out
+}
+
+// padEven appends a NUL byte to odd-length binary values
+func padEven(b []byte) []byte {
+	if len(b)%2 != 0 {
+		return append(b[:len(b):len(b)], 0)
+	}
+	return b
+}
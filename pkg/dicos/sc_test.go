@@ -0,0 +1,67 @@
+package dicos_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecondaryCapture_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	src := dicos.NewCTImage()
+	src.SetPixelData(4, 4, make([]uint16, 16))
+	srcDS, err := src.GetDataset()
+	require.NoError(t, err)
+
+	screen := image.NewRGBA(image.Rect(0, 0, 7, 5)) // odd width and pixel count
+	for y := 0; y < 5; y++ {
+		for x := 0; x < 7; x++ {
+			screen.Set(x, y, color.RGBA{uint8(x * 30), uint8(y * 50), 200, 255})
+		}
+	}
+
+	for _, codec := range []dicos.Codec{nil, dicos.CodecJPEG2000} {
+		name := "native"
+		if codec != nil {
+			name = codec.Name()
+		}
+		t.Run(name, func(t *testing.T) {
+			sc := dicos.NewSecondaryCaptureImage()
+			sc.DerivationDescription = "Operator review screen"
+			sc.ICCProfile = []byte{1, 2, 3}
+			sc.Image = screen
+			sc.Codec = codec
+			require.NoError(t, sc.AddSource(srcDS))
+
+			var buf bytes.Buffer
+			_, err := sc.WriteTo(&buf)
+			require.NoError(t, err)
+			ds, err := dicos.ReadBufferContext(ctx, buf.Bytes())
+			require.NoError(t, err)
+
+			got, err := dicos.SecondaryCaptureFromDataset(ctx, ds)
+			require.NoError(t, err)
+			assert.Equal(t, sc.SOPCommon.SOPInstanceUID, got.SOPCommon.SOPInstanceUID)
+			assert.Equal(t, "WSD", got.ConversionType)
+			assert.Equal(t, "Operator review screen", got.DerivationDescription)
+			assert.Equal(t, []dicos.SourceImage{{SOPClassUID: src.SOPCommon.SOPClassUID, SOPInstanceUID: src.SOPCommon.SOPInstanceUID}}, got.SourceImages)
+			assert.Equal(t, []byte{1, 2, 3, 0}, got.ICCProfile)
+			require.Equal(t, screen.Bounds(), got.Image.Bounds())
+			assert.Equal(t, screen.Pix, got.Image.(*image.RGBA).Pix)
+		})
+	}
+
+	sc := dicos.NewSecondaryCaptureImage()
+	sc.Image = screen
+	sc.Codec = dicos.CodecJPEGLS
+	_, err = sc.GetDataset()
+	assert.Error(t, err, "grayscale-only codec")
+	_, err = dicos.SecondaryCaptureFromDataset(ctx, srcDS)
+	assert.Error(t, err, "not a secondary capture")
+}
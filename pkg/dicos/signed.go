@@ -0,0 +1,79 @@
+package dicos
+
+import "github.com/jpfielding/dicos.go/pkg/dicos/tag"
+
+// Signed samples (PixelRepresentation 1) travel through the pixel pipeline
+// as the two's complement bits of their value in []uint16, as they are
+// stored. Decoding sign extends them from BitsStored to 16 bits, so that
+// int16(v) is the stored value whatever the stored precision or codec; the
+// helpers here convert to and from []int16 at the edges.
+
+// Int16Samples returns samples as signed values, sign extended from
+// bitsStored (1-16) bits. Samples already sign extended are unchanged.
+//
+// Example:
+//
+//	pd, _ := ds.GetPixelData()
+//	hu := dicos.Int16Samples(pd.Frames[0].Data, ds.BitsStored())
+func Int16Samples(data []uint16, bitsStored int) []int16 {
+	shift := signShift(bitsStored)
+	out := make([]int16, len(data))
+	for i, v := range data {
+		out[i] = int16(v<<shift) >> shift
+	}
+	return out
+}
+
+// Uint16Samples returns signed values as the two's complement bits stored
+// in pixel data, for WithPixelData and the SetPixelData methods
+func Uint16Samples(data []int16) []uint16 {
+	out := make([]uint16, len(data))
+	for i, v := range data {
+		out[i] = uint16(v)
+	}
+	return out
+}
+
+// Int16Data returns the native samples of the frame as signed values, sign
+// extended from bitsStored bits
+func (f Frame) Int16Data(bitsStored int) []int16 {
+	return Int16Samples(f.Data, bitsStored)
+}
+
+// WithSignedPixelData is WithPixelData for signed samples, such as CT in
+// Hounsfield units. It sets PixelRepresentation to 1 before encoding, so
+// codecs describe the samples as signed; set BitsStored first when fewer
+// than 16 bits are significant.
+//
+// Example:
+//
+//	ds, _ := dicos.NewDataset(
+//		dicos.WithElement(tag.BitsAllocated, uint16(16)),
+//		dicos.WithElement(tag.BitsStored, uint16(12)),
+//		dicos.WithSignedPixelData(512, 512, hu, dicos.CodecJPEGLS),
+//	)
+func WithSignedPixelData(rows, cols int, data []int16, codec Codec) Option {
+	return func(ds *Dataset) error {
+		if err := WithElement(tag.PixelRepresentation, uint16(1))(ds); err != nil {
+			return err
+		}
+		return WithPixelData(rows, cols, 16, Uint16Samples(data), codec)(ds)
+	}
+}
+
+// signExtend sign extends samples in place from bitsStored to 16 bits
+func signExtend(data []uint16, bitsStored int) {
+	shift := signShift(bitsStored)
+	if shift == 0 {
+		return
+	}
+	for i, v := range data {
+		data[i] = uint16(int16(v<<shift) >> shift)
+	}
+}
+
+// signShift returns the shift that moves the sign bit of a bitsStored
+// sample to bit 15
+func signShift(bitsStored int) uint {
+	return uint(16 - min(max(bitsStored, 1), 16))
+}
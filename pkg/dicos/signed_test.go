@@ -0,0 +1,106 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInt16Samples(t *testing.T) {
+	assert.Equal(t, []int16{-1, -2048, 2047, 0}, Int16Samples([]uint16{0x0FFF, 0x0800, 0x07FF, 0}, 12))
+	assert.Equal(t, []int16{-1, -2048, 2047}, Int16Samples([]uint16{0xFFFF, 0xF800, 0x07FF}, 12), "already extended")
+	assert.Equal(t, []int16{-1, 32767}, Int16Samples([]uint16{0xFFFF, 0x7FFF}, 16))
+	assert.Equal(t, []uint16{0xFFFF, 0xFC18, 1000}, Uint16Samples([]int16{-1, -1000, 1000}))
+}
+
+// TestSignedPixelData_RoundTrip checks that Hounsfield values written as
+// signed samples decode to the same values with every codec
+func TestSignedPixelData_RoundTrip(t *testing.T) {
+	const rows, cols, frames = 8, 8, 2
+	for _, bits := range []int{12, 16} {
+		lo := -(1 << (bits - 1))
+		hu := make([]int16, frames*rows*cols)
+		for i := range hu {
+			hu[i] = int16(lo + i*97%(1<<bits))
+		}
+		hu[0], hu[1] = int16(lo), -1000 // air
+
+		for _, codec := range []Codec{nil, CodecJPEGLS, CodecJPEGLi, CodecRLE, CodecJPEG2000} {
+			name := "native"
+			if codec != nil {
+				name = codec.Name()
+			}
+			t.Run(fmt.Sprintf("%s/%d", name, bits), func(t *testing.T) {
+				ct := NewCTImage()
+				ct.BitsStored = uint16(bits)
+				ct.Rows, ct.Columns = rows, cols
+				ct.SetSignedPixelData(rows, cols, hu)
+				ct.Codec = codec
+				var buf bytes.Buffer
+				_, err := ct.WriteTo(&buf)
+				require.NoError(t, err)
+				ds, err := ReadBuffer(buf.Bytes())
+				require.NoError(t, err)
+				assert.Equal(t, 1, ds.PixelRepresentation())
+
+				vol, err := DecodeVolume(ds)
+				require.NoError(t, err)
+				assert.True(t, vol.Signed)
+				assert.Equal(t, hu, vol.Int16Data())
+				assert.Equal(t, hu[1], vol.GetInt16(1, 0, 0))
+				mn, _ := vol.SampleRange()
+				assert.Equal(t, lo, mn)
+
+				var streamed []int16
+				err = DecodeSlices(context.Background(), ds, func(z int, slice []uint16) error {
+					streamed = append(streamed, Int16Samples(slice, 16)...)
+					return nil
+				})
+				require.NoError(t, err)
+				assert.Equal(t, hu, streamed)
+			})
+		}
+	}
+}
+
+// TestDecodeVolume_MaskedSigned reads signed samples stored without their
+// high bits, as some scanners write them
+func TestDecodeVolume_MaskedSigned(t *testing.T) {
+	hu := []int16{-2048, -1000, -1, 0, 1, 2047}
+	masked := make([]uint16, len(hu))
+	for i, v := range hu {
+		masked[i] = uint16(v) & 0x0FFF
+	}
+	ds, err := NewDataset(
+		WithElement(tag.Rows, uint16(2)),
+		WithElement(tag.Columns, uint16(3)),
+		WithElement(tag.BitsAllocated, uint16(16)),
+		WithElement(tag.BitsStored, uint16(12)),
+		WithElement(tag.PixelRepresentation, uint16(1)),
+		WithPixelData(2, 3, 16, masked, nil),
+	)
+	require.NoError(t, err)
+	vol, err := DecodeVolume(ds)
+	require.NoError(t, err)
+	assert.Equal(t, hu, vol.Int16Data())
+
+	pd, err := ds.GetPixelData()
+	require.NoError(t, err)
+	assert.Equal(t, hu, pd.Frames[0].Int16Data(12))
+
+	vol, err = VolumeFromDataset(ds)
+	require.NoError(t, err)
+	assert.Equal(t, hu, vol.Int16Data())
+
+	// unsigned data is left alone
+	ds.Elements[tag.PixelRepresentation].Value = uint16(0)
+	vol, err = DecodeVolume(ds)
+	require.NoError(t, err)
+	assert.False(t, vol.Signed)
+	assert.Equal(t, masked, vol.Data)
+}
@@ -0,0 +1,284 @@
+package dicos
+
+import (
+	"fmt"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/jpfielding/dicos.go/pkg/dicos/transfer"
+)
+
+// EstimateEncodedSize returns the number of bytes Write would produce for ds.
+// Pixel data and bulk payloads are measured rather than encoded, so this is
+// cheap even for large volumes.
+func EstimateEncodedSize(ds *Dataset) (int64, error) {
+	n, err := datasetBodySize(ds, ds.TransferSyntax() == transfer.ImplicitVRLittleEndian)
+	if err != nil {
+		return 0, err
+	}
+	// Write adds the File Meta group length and version when missing
+	if hasGroup(ds, 0x0002) {
+		if _, ok := ds.Elements[tag.FileMetaInformationGroupLength]; !ok {
+			n += 12
+		}
+		if _, ok := ds.Elements[tag.FileMetaInformationVersion]; !ok {
+			n += 14
+		}
+	}
+	return 128 + 4 + n, nil // preamble and DICM magic
+}
+
+// datasetBodySize mirrors writeDataSetBody
+func datasetBodySize(ds *Dataset, implicit bool) (int64, error) {
+	var total int64
+	for _, elem := range ds.Elements {
+		if bd, ok := elem.Value.(*BulkData); ok && bd.Excluded() {
+			continue
+		}
+		n, err := elementSize(elem, implicit && elem.Tag.Group != 0x0002)
+		if err != nil {
+			return 0, fmt.Errorf("failed to size element %v: %w", elem.Tag, err)
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// elementSize mirrors writeElement: tag, VR, length field and value
+func elementSize(elem *Element, implicit bool) (int64, error) {
+	vr := elem.VR
+	if len(vr) != 2 {
+		vr = "UN"
+	}
+	header := int64(8)
+	if !implicit && isLongVR(vr) {
+		header = 12
+	}
+	n, err := valueSize(elem.Value, vr, implicit)
+	if err != nil {
+		return 0, err
+	}
+	return header + n, nil
+}
+
+func valueSize(v interface{}, vr string, implicit bool) (int64, error) {
+	switch val := v.(type) {
+	case *BulkData:
+		return val.Length + val.Length%2, nil
+	case *PixelData:
+		var n int64
+		if val.IsEncapsulated {
+			n = 8 + 4*int64(len(val.Offsets)) + 8 // BOT item and sequence delimiter
+			for _, f := range val.Frames {
+				n += 8 + int64(len(f.CompressedData))
+			}
+			return n, nil
+		}
+		// 16-bit words, or single bytes padded to even length when OB
+		per := int64(2)
+		if vr == "OB" {
+			per = 1
+		}
+		for _, f := range val.Frames {
+			n += per * int64(len(f.Data))
+		}
+		return n + n%2, nil
+	case []*Dataset:
+		if vr != "SQ" {
+			break
+		}
+		n := int64(8) // sequence delimiter
+		for _, item := range val {
+			body, err := datasetBodySize(item, implicit)
+			if err != nil {
+				return 0, err
+			}
+			n += 8 + body
+		}
+		return n, nil
+	}
+	b, _, err := encodeValue(v, vr)
+	return int64(len(b)), err
+}
+
+// sizeSampleFrames is the most frames compressed when estimating a codec's ratio
+const sizeSampleFrames = 3
+
+// SizeEstimate predicts the encoded size of a dataset under one transfer syntax
+type SizeEstimate struct {
+	Codec          Codec  // nil for uncompressed
+	TransferSyntax string // transfer syntax the dataset would be written with
+	Frames         int
+	FixedBytes     int64   // bytes that do not scale with the frame count
+	FrameBytes     int64   // estimated bytes per frame, including item and offset table overhead
+	TotalBytes     int64   // FixedBytes + Frames*FrameBytes
+	Ratio          float64 // uncompressed pixel bytes / estimated pixel bytes
+}
+
+// Fits returns true if the whole dataset fits in limit bytes
+func (e SizeEstimate) Fits(limit int64) bool {
+	return e.TotalBytes <= limit
+}
+
+// FramesPerFile returns the most frames a single file of at most limit bytes
+// can carry, or 0 if not even one frame fits
+func (e SizeEstimate) FramesPerFile(limit int64) int {
+	if e.FrameBytes <= 0 {
+		if e.FixedBytes <= limit {
+			return e.Frames
+		}
+		return 0
+	}
+	n := (limit - e.FixedBytes) / e.FrameBytes
+	if n < 0 {
+		return 0
+	}
+	return int(min(n, int64(e.Frames)))
+}
+
+// Files returns how many files of at most limit bytes are needed to split the
+// frames, or 0 if a single frame does not fit
+func (e SizeEstimate) Files(limit int64) int {
+	per := e.FramesPerFile(limit)
+	if per == 0 {
+		return 0
+	}
+	if e.Frames == 0 {
+		return 1
+	}
+	return (e.Frames + per - 1) / per
+}
+
+func (e SizeEstimate) String() string {
+	name := "uncompressed"
+	if e.Codec != nil {
+		name = e.Codec.Name()
+	}
+	return fmt.Sprintf("%s: %d bytes (%d frames, %.2fx)", name, e.TotalBytes, e.Frames, e.Ratio)
+}
+
+// PlanEncodedSize predicts the encoded size of ds with each codec (nil for
+// uncompressed). Compressed sizes are extrapolated from a few sample frames,
+// so acquisition software can pick a codec or split the frames across files
+// before committing to a full encode.
+//
+// Example:
+//
+//	estimates, _ := dicos.PlanEncodedSize(ds, nil, dicos.CodecRLE, dicos.CodecJPEGLS)
+//	if best, ok := dicos.SmallestEstimate(estimates); ok && !best.Fits(limit) {
+//		fmt.Printf("split into %d files\n", best.Files(limit))
+//	}
+func PlanEncodedSize(ds *Dataset, codecs ...Codec) ([]SizeEstimate, error) {
+	pd, pixErr := ds.GetPixelData()
+
+	// Everything but the pixel data element
+	fixed, err := EstimateEncodedSize(ds)
+	if err != nil {
+		return nil, err
+	}
+	if pixErr == nil {
+		pixelElem, _ := ds.FindElement(0x7FE0, 0x0010)
+		n, err := elementSize(pixelElem, ds.TransferSyntax() == transfer.ImplicitVRLittleEndian)
+		if err != nil {
+			return nil, err
+		}
+		fixed -= n
+	}
+
+	var samples [][]uint16
+	rows, cols := ds.Rows(), ds.Columns()
+	frames := 0
+	if pixErr == nil {
+		frames = len(pd.Frames)
+		ts := GetTransferSyntax(ds)
+		for _, i := range sampleFrameIndices(frames) {
+			data, err := DecodeFrameData(pd, i, rows, cols, ts)
+			if err != nil {
+				return nil, fmt.Errorf("failed to sample frame %d: %w", i, err)
+			}
+			samples = append(samples, data)
+		}
+	}
+	// native samples are written as bytes when 8 bits are allocated
+	bytesPerSample := int64(2)
+	if ds.BitsAllocated() <= 8 {
+		bytesPerSample = 1
+	}
+	nativeFrame := int64(rows*cols*max(ds.SamplesPerPixel(), 1)) * bytesPerSample
+
+	estimates := make([]SizeEstimate, 0, len(codecs))
+	for _, codec := range codecs {
+		e := SizeEstimate{
+			Codec:          codec,
+			TransferSyntax: string(transfer.ExplicitVRLittleEndian),
+			Frames:         frames,
+			FixedBytes:     fixed,
+			Ratio:          1,
+		}
+		if codec != nil {
+			e.TransferSyntax = codec.TransferSyntaxUID()
+		}
+		// The transfer syntax UID in the file meta changes length with the codec
+		if elem, ok := ds.FindElement(0x0002, 0x0010); ok {
+			if ts, ok := elem.GetString(); ok {
+				e.FixedBytes += evenLen(e.TransferSyntax) - evenLen(ts)
+			}
+		}
+
+		if frames > 0 {
+			if codec == nil {
+				e.FixedBytes += 12 + int64(frames)*nativeFrame%2 // odd length pad
+				e.FrameBytes = nativeFrame
+			} else {
+				// encoded with the sample format the writer would use
+				var sampled int64
+				for _, data := range samples {
+					frag, err := encodeGrayFrame(codec, data, rows, cols, sampleFormat(ds, ds.BitsAllocated()))
+					if err != nil {
+						return nil, err
+					}
+					sampled += int64(len(frag) + len(frag)%2)
+				}
+				avg := (sampled + int64(len(samples)) - 1) / int64(len(samples))
+				e.FixedBytes += 12 + 8 + 8 // element header, offset table item, delimiter
+				e.FrameBytes = avg + 8 + 4 // item header and offset table entry
+				if avg > 0 {
+					e.Ratio = float64(nativeFrame) / float64(avg)
+				}
+			}
+		}
+		e.TotalBytes = e.FixedBytes + int64(e.Frames)*e.FrameBytes
+		estimates = append(estimates, e)
+	}
+	return estimates, nil
+}
+
+// SmallestEstimate returns the estimate with the fewest total bytes
+func SmallestEstimate(estimates []SizeEstimate) (SizeEstimate, bool) {
+	if len(estimates) == 0 {
+		return SizeEstimate{}, false
+	}
+	best := estimates[0]
+	for _, e := range estimates[1:] {
+		if e.TotalBytes < best.TotalBytes {
+			best = e
+		}
+	}
+	return best, true
+}
+
+// sampleFrameIndices picks the first, middle and last frames
+func sampleFrameIndices(frames int) []int {
+	if frames <= sizeSampleFrames {
+		idx := make([]int, frames)
+		for i := range idx {
+			idx[i] = i
+		}
+		return idx
+	}
+	return []int{0, frames / 2, frames - 1}
+}
+
+func evenLen(s string) int64 {
+	n := int64(len(s))
+	return n + n%2
+}
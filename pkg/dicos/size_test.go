@@ -0,0 +1,106 @@
+package dicos
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sizeTestDataset(t *testing.T, codec Codec) *Dataset {
+	t.Helper()
+	rows, cols, frames := 32, 32, 5
+	data := make([]uint16, rows*cols*frames)
+	for i := range data {
+		data[i] = uint16((i % cols) * 40)
+	}
+	return newTestDataset(t, DICOSCTImageStorageUID, codec,
+		WithElement(tag.Rows, uint16(rows)),
+		WithElement(tag.Columns, uint16(cols)),
+		WithPixelData(rows, cols, 16, data, codec),
+	)
+}
+
+func encodedLen(t *testing.T, ds *Dataset) int64 {
+	t.Helper()
+	var buf bytes.Buffer
+	n, err := Write(&buf, ds)
+	require.NoError(t, err)
+	return n + 132
+}
+
+func TestEstimateEncodedSize_MatchesWrite(t *testing.T) {
+	for _, codec := range []Codec{nil, CodecRLE} {
+		ds := sizeTestDataset(t, codec)
+		size, err := EstimateEncodedSize(ds)
+		require.NoError(t, err)
+		assert.Equal(t, encodedLen(t, ds), size)
+	}
+
+	implicit := sizeTestDataset(t, nil)
+	implicit.Elements[Tag{Group: 0x0002, Element: 0x0010}].Value = string(ImplicitVRLittleEndian)
+	size, err := EstimateEncodedSize(implicit)
+	require.NoError(t, err)
+	assert.Equal(t, encodedLen(t, implicit), size, "implicit VR")
+
+	tdr := NewThreatDetectionReport()
+	tdr.PTOs = []PotentialThreatObject{{Label: "KNIFE", BoundingBox: &BoundingBox{BottomRight: [3]float32{1, 2, 3}}}}
+	ds, err := tdr.GetDataset()
+	require.NoError(t, err)
+	size, err = EstimateEncodedSize(ds)
+	require.NoError(t, err)
+	assert.Equal(t, encodedLen(t, ds), size)
+}
+
+func TestPlanEncodedSize(t *testing.T) {
+	ds := sizeTestDataset(t, nil)
+
+	estimates, err := PlanEncodedSize(ds, nil, CodecRLE)
+	require.NoError(t, err)
+	require.Len(t, estimates, 2)
+
+	native := estimates[0]
+	assert.Equal(t, encodedLen(t, ds), native.TotalBytes)
+	assert.Equal(t, 5, native.Frames)
+
+	// Frames are identical, so the sampled estimate is exact
+	rle := estimates[1]
+	assert.Equal(t, encodedLen(t, sizeTestDataset(t, CodecRLE)), rle.TotalBytes)
+	assert.Greater(t, rle.Ratio, 1.0)
+
+	best, ok := SmallestEstimate(estimates)
+	require.True(t, ok)
+	assert.Equal(t, CodecRLE, best.Codec)
+}
+
+func TestPlanEncodedSize_8Bit(t *testing.T) {
+	// 3 frames of 5x3 bytes: 45 bytes of OB padded to 46
+	ds, _ := thumbnail(t, 5, 3, 3, nil)
+	require.Equal(t, "OB", ds.Elements[tag.PixelData].VR)
+	size, err := EstimateEncodedSize(ds)
+	require.NoError(t, err)
+	assert.Equal(t, encodedLen(t, ds), size)
+
+	estimates, err := PlanEncodedSize(ds, nil, CodecRLE)
+	require.NoError(t, err)
+	native := estimates[0]
+	assert.Equal(t, int64(15), native.FrameBytes)
+	assert.Equal(t, encodedLen(t, ds), native.TotalBytes)
+
+	rle := estimates[1]
+	rleDS, _ := thumbnail(t, 5, 3, 3, CodecRLE)
+	assert.Equal(t, encodedLen(t, rleDS), rle.TotalBytes)
+}
+
+func TestSizeEstimate_Split(t *testing.T) {
+	e := SizeEstimate{Frames: 10, FixedBytes: 1000, FrameBytes: 500}
+	e.TotalBytes = e.FixedBytes + int64(e.Frames)*e.FrameBytes
+
+	assert.True(t, e.Fits(6000))
+	assert.False(t, e.Fits(5999))
+	assert.Equal(t, 4, e.FramesPerFile(3000))
+	assert.Equal(t, 3, e.Files(3000))
+	assert.Equal(t, 0, e.Files(1200))
+}
@@ -0,0 +1,91 @@
+package dicos
+
+import (
+	"strings"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// SpacingSource identifies which attributes produced a calibrated spacing
+type SpacingSource string
+
+const (
+	// SpacingFromPixelSpacing uses Pixel Spacing (0028,0030) of a cross-sectional image,
+	// or of a projection image whose calibration type is not recorded
+	SpacingFromPixelSpacing SpacingSource = "PixelSpacing"
+	// SpacingFromCalibration uses Pixel Spacing of a projection image calibrated per
+	// Pixel Spacing Calibration Type (0028,0A02)
+	SpacingFromCalibration SpacingSource = "PixelSpacing/PixelSpacingCalibrationType"
+	// SpacingFromMagnification uses Imager Pixel Spacing (0018,1164) divided by
+	// Estimated Radiographic Magnification Factor (0018,1114)
+	SpacingFromMagnification SpacingSource = "ImagerPixelSpacing/EstimatedRadiographicMagnificationFactor"
+	// SpacingFromGeometry uses Imager Pixel Spacing scaled by SOD/SID (0018,1111)/(0018,1110)
+	SpacingFromGeometry SpacingSource = "ImagerPixelSpacing/DistanceSourceToDetector"
+	// SpacingFromImager uses Imager Pixel Spacing uncorrected (detector plane)
+	SpacingFromImager SpacingSource = "ImagerPixelSpacing"
+	// SpacingDefault means no spacing attributes were present; 1mm is assumed
+	SpacingDefault SpacingSource = "Default"
+)
+
+// CalibratedSpacing is the in-plane pixel spacing in mm together with its provenance
+type CalibratedSpacing struct {
+	Row    float64 // spacing between rows (mm)
+	Col    float64 // spacing between columns (mm)
+	Source SpacingSource
+	// Calibrated is true when the spacing is measured at the object plane
+	// rather than the detector plane or assumed
+	Calibrated bool
+	// CalibrationType is Pixel Spacing Calibration Type (0028,0A02), GEOMETRY or FIDUCIAL, if present
+	CalibrationType string
+}
+
+// GetCalibratedSpacing returns the in-plane pixel spacing to use for measurements.
+//
+// Cross-sectional (CT) images carry only Pixel Spacing (0028,0030), which is
+// used as is. Projection (DX) images are recognised by Imager Pixel Spacing
+// (0018,1164), and their attributes are evaluated in this order per
+// DICOM PS3.3 10.7.1.3:
+//  1. Pixel Spacing with Pixel Spacing Calibration Type (0028,0A02) - calibrated at the object
+//  2. Imager Pixel Spacing / Estimated Radiographic Magnification Factor (0018,1114)
+//  3. Imager Pixel Spacing * Distance Source to Patient / Distance Source to Detector
+//  4. Pixel Spacing without a calibration type - meaning unspecified, not calibrated
+//  5. Imager Pixel Spacing - detector plane, not calibrated
+//  6. 1mm default
+func GetCalibratedSpacing(ds *Dataset) CalibratedSpacing {
+	ps := dsValues(ds, tag.PixelSpacing)
+	hasPS := len(ps) >= 2 && ps[0] > 0 && ps[1] > 0
+	ips := dsValues(ds, tag.ImagerPixelSpacing)
+	hasIPS := len(ips) >= 2 && ips[0] > 0 && ips[1] > 0
+
+	var calType string
+	if elem, ok := ds.FindElement(tag.PixelSpacingCalibrationType.Group, tag.PixelSpacingCalibrationType.Element); ok {
+		if s, ok := elem.GetString(); ok {
+			calType = strings.TrimSpace(s)
+		}
+	}
+
+	switch {
+	case hasPS && !hasIPS:
+		return CalibratedSpacing{Row: ps[0], Col: ps[1], Source: SpacingFromPixelSpacing, Calibrated: true, CalibrationType: calType}
+	case hasPS && calType != "":
+		return CalibratedSpacing{Row: ps[0], Col: ps[1], Source: SpacingFromCalibration, Calibrated: true, CalibrationType: calType}
+	case !hasIPS:
+		return CalibratedSpacing{Row: 1.0, Col: 1.0, Source: SpacingDefault}
+	}
+
+	if mag := dsValues(ds, tag.EstimatedRadiographicMagnificationFactor); len(mag) > 0 && mag[0] > 0 {
+		return CalibratedSpacing{Row: ips[0] / mag[0], Col: ips[1] / mag[0], Source: SpacingFromMagnification, Calibrated: true}
+	}
+
+	sid := dsValues(ds, tag.DistanceSourceToDetector)
+	sod := dsValues(ds, tag.DistanceSourceToPatient)
+	if len(sid) > 0 && len(sod) > 0 && sid[0] > 0 && sod[0] > 0 {
+		scale := sod[0] / sid[0]
+		return CalibratedSpacing{Row: ips[0] * scale, Col: ips[1] * scale, Source: SpacingFromGeometry, Calibrated: true}
+	}
+
+	if hasPS {
+		return CalibratedSpacing{Row: ps[0], Col: ps[1], Source: SpacingFromPixelSpacing}
+	}
+	return CalibratedSpacing{Row: ips[0], Col: ips[1], Source: SpacingFromImager}
+}
@@ -0,0 +1,143 @@
+package dicos
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCalibratedSpacing_Precedence(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []Option
+		row    float64
+		col    float64
+		source SpacingSource
+		calib  bool
+	}{
+		{
+			name:   "default",
+			row:    1.0,
+			col:    1.0,
+			source: SpacingDefault,
+		},
+		{
+			name: "cross-sectional pixel spacing",
+			opts: []Option{
+				WithElement(tag.PixelSpacing, "0.5\\0.6"),
+			},
+			row: 0.5, col: 0.6, source: SpacingFromPixelSpacing, calib: true,
+		},
+		{
+			name: "calibrated projection pixel spacing wins",
+			opts: []Option{
+				WithElement(tag.PixelSpacing, "0.5\\0.6"),
+				WithElement(tag.PixelSpacingCalibrationType, "FIDUCIAL"),
+				WithElement(tag.ImagerPixelSpacing, "0.2\\0.2"),
+				WithElement(tag.EstimatedRadiographicMagnificationFactor, "2"),
+			},
+			row: 0.5, col: 0.6, source: SpacingFromCalibration, calib: true,
+		},
+		{
+			name: "uncalibrated projection pixel spacing loses to magnification",
+			opts: []Option{
+				WithElement(tag.PixelSpacing, "0.2\\0.2"),
+				WithElement(tag.ImagerPixelSpacing, "0.2\\0.2"),
+				WithElement(tag.EstimatedRadiographicMagnificationFactor, "2"),
+			},
+			row: 0.1, col: 0.1, source: SpacingFromMagnification, calib: true,
+		},
+		{
+			name: "uncalibrated projection pixel spacing",
+			opts: []Option{
+				WithElement(tag.PixelSpacing, "0.25\\0.25"),
+				WithElement(tag.ImagerPixelSpacing, "0.2\\0.2"),
+			},
+			row: 0.25, col: 0.25, source: SpacingFromPixelSpacing,
+		},
+		{
+			name: "magnification corrected",
+			opts: []Option{
+				WithElement(tag.ImagerPixelSpacing, "0.4\\0.2"),
+				WithElement(tag.EstimatedRadiographicMagnificationFactor, "2"),
+				WithElement(tag.DistanceSourceToDetector, "1000"),
+				WithElement(tag.DistanceSourceToPatient, "250"),
+			},
+			row: 0.2, col: 0.1, source: SpacingFromMagnification, calib: true,
+		},
+		{
+			name: "geometry corrected",
+			opts: []Option{
+				WithElement(tag.ImagerPixelSpacing, "0.4\\0.4"),
+				WithElement(tag.DistanceSourceToDetector, "1000"),
+				WithElement(tag.DistanceSourceToPatient, "500"),
+			},
+			row: 0.2, col: 0.2, source: SpacingFromGeometry, calib: true,
+		},
+		{
+			name: "imager only",
+			opts: []Option{
+				WithElement(tag.ImagerPixelSpacing, "0.3\\0.3"),
+			},
+			row: 0.3, col: 0.3, source: SpacingFromImager,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds, err := NewDataset(tt.opts...)
+			require.NoError(t, err)
+
+			sp := GetCalibratedSpacing(ds)
+			assert.InDelta(t, tt.row, sp.Row, 1e-9)
+			assert.InDelta(t, tt.col, sp.Col, 1e-9)
+			assert.Equal(t, tt.source, sp.Source)
+			assert.Equal(t, tt.calib, sp.Calibrated)
+		})
+	}
+}
+
+func TestGetPixelSpacing_ProjectionMagnification(t *testing.T) {
+	ds, err := NewDataset(
+		WithElement(tag.Rows, uint16(2)),
+		WithElement(tag.Columns, uint16(2)),
+		WithElement(tag.ImagerPixelSpacing, "0.3\\0.3"),
+		WithElement(tag.EstimatedRadiographicMagnificationFactor, "1.5"),
+		WithRawPixelData(&PixelData{Frames: []Frame{{Data: []uint16{1, 2, 3, 4}}}}),
+	)
+	require.NoError(t, err)
+
+	row, col := GetPixelSpacing(ds)
+	assert.InDelta(t, 0.2, row, 1e-9)
+	assert.InDelta(t, 0.2, col, 1e-9)
+
+	// A 10 pixel wide object on the detector measures 2mm at the object
+	vol, err := DecodeVolume(ds)
+	require.NoError(t, err)
+	assert.InDelta(t, 2.0, 10*vol.SpacingX, 1e-9)
+	assert.InDelta(t, 2.0, 10*vol.SpacingY, 1e-9)
+}
+
+func TestGetCalibratedSpacing_RoundTrip(t *testing.T) {
+	ds, err := NewDataset(
+		WithFileMeta(DICOSDXImageStorageUID, "1.2.3.4", string(ExplicitVRLittleEndian)),
+		WithElement(tag.ImagerPixelSpacing, "0.15\\0.15"),
+		WithElement(tag.DistanceSourceToDetector, "1200"),
+		WithElement(tag.DistanceSourceToPatient, "800"),
+	)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	_, err = Write(&buf, ds)
+	require.NoError(t, err)
+
+	read, err := ReadBuffer(buf.Bytes())
+	require.NoError(t, err)
+
+	sp := GetCalibratedSpacing(read)
+	assert.Equal(t, SpacingFromGeometry, sp.Source)
+	assert.InDelta(t, 0.1, sp.Row, 1e-9)
+}
@@ -0,0 +1,269 @@
+package dicos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FrameChunk is one piece of an encapsulated frame in transit
+type FrameChunk struct {
+	InstanceUID string // SOP Instance UID the frame belongs to
+	Frame       int    // frame index
+	Offset      int64  // byte offset of Data within the frame
+	Data        []byte
+	Last        bool // Data ends the frame
+}
+
+// ResumeToken marks a position in a frame stream: everything before byte
+// Offset of frame Frame has been received. Receivers acknowledge with tokens,
+// and a sender restarted with a token continues from that position instead
+// of from the start of the instance.
+type ResumeToken struct {
+	InstanceUID string
+	Frame       int
+	Offset      int64
+}
+
+// String encodes the token as "uid:frame:offset"
+func (t ResumeToken) String() string {
+	return fmt.Sprintf("%s:%d:%d", t.InstanceUID, t.Frame, t.Offset)
+}
+
+// before returns true if t is an earlier position than other
+func (t ResumeToken) before(other ResumeToken) bool {
+	if t.Frame != other.Frame {
+		return t.Frame < other.Frame
+	}
+	return t.Offset < other.Offset
+}
+
+// ParseResumeToken parses a token produced by ResumeToken.String
+func ParseResumeToken(s string) (ResumeToken, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return ResumeToken{}, fmt.Errorf("invalid resume token %q", s)
+	}
+	frame, err := strconv.Atoi(parts[1])
+	if err != nil || frame < 0 {
+		return ResumeToken{}, fmt.Errorf("invalid resume token %q: bad frame", s)
+	}
+	offset, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || offset < 0 {
+		return ResumeToken{}, fmt.Errorf("invalid resume token %q: bad offset", s)
+	}
+	return ResumeToken{InstanceUID: parts[0], Frame: frame, Offset: offset}, nil
+}
+
+// ChunkTransport carries frame chunks to a receiver. Acks delivers the
+// receiver's cumulative acknowledgements; a nil channel disables backpressure.
+// DIMSE, gRPC or plain TCP layers plug in by implementing this interface.
+type ChunkTransport interface {
+	Send(ctx context.Context, chunk FrameChunk) error
+	Acks() <-chan ResumeToken
+}
+
+// StreamOptions controls StreamFrames
+type StreamOptions struct {
+	ChunkSize  int           // bytes per chunk; 0 means 64 KiB
+	Window     int           // unacknowledged chunks allowed in flight; 0 means 16
+	AckTimeout time.Duration // how long to wait for an acknowledgement; 0 waits for ctx
+	Resume     *ResumeToken  // continue from this position instead of the start
+}
+
+// ErrAckTimeout is returned when the receiver stops acknowledging chunks
+var ErrAckTimeout = errors.New("timed out waiting for acknowledgement")
+
+// StreamFrames sends the encapsulated frames of pd as chunks over t. At most
+// opts.Window chunks are sent ahead of the receiver's acknowledgements, so a
+// slow link throttles the sender instead of buffering the whole instance.
+//
+// It returns the last acknowledged position. On failure, pass that token as
+// opts.Resume to a new StreamFrames call to continue mid-instance rather than
+// restarting.
+//
+// Example:
+//
+//	token, err := dicos.StreamFrames(ctx, pd, uid, conn, dicos.StreamOptions{})
+//	for err != nil && retries < 3 {
+//		conn = reconnect()
+//		token, err = dicos.StreamFrames(ctx, pd, uid, conn, dicos.StreamOptions{Resume: &token})
+//		retries++
+//	}
+func StreamFrames(ctx context.Context, pd *PixelData, instanceUID string, t ChunkTransport, opts StreamOptions) (ResumeToken, error) {
+	if !pd.IsEncapsulated {
+		return ResumeToken{}, fmt.Errorf("streaming requires encapsulated pixel data")
+	}
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = 64 << 10
+	}
+	window := opts.Window
+	if window <= 0 {
+		window = 16
+	}
+
+	acked := ResumeToken{InstanceUID: instanceUID}
+	if opts.Resume != nil {
+		if opts.Resume.InstanceUID != instanceUID {
+			return acked, fmt.Errorf("resume token is for instance %s, not %s", opts.Resume.InstanceUID, instanceUID)
+		}
+		if opts.Resume.Frame > len(pd.Frames) {
+			return acked, fmt.Errorf("resume token frame %d beyond %d frames", opts.Resume.Frame, len(pd.Frames))
+		}
+		acked = *opts.Resume
+		logger(ctx).DebugContext(ctx, "Resuming frame stream", slog.String("token", acked.String()))
+	}
+
+	acks := t.Acks()
+	var inflight []ResumeToken // end positions of unacknowledged chunks
+	waitAck := func() error {
+		var timeout <-chan time.Time
+		if opts.AckTimeout > 0 {
+			timer := time.NewTimer(opts.AckTimeout)
+			defer timer.Stop()
+			timeout = timer.C
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timeout:
+			return ErrAckTimeout
+		case tok, ok := <-acks:
+			if !ok {
+				return fmt.Errorf("acknowledgement channel closed")
+			}
+			if acked.before(tok) {
+				acked = tok
+			}
+			for len(inflight) > 0 && !acked.before(inflight[0]) {
+				inflight = inflight[1:]
+			}
+			return nil
+		}
+	}
+
+	// drainAcks takes acknowledgements already delivered, so a failed stream
+	// reports the latest position the receiver confirmed
+	drainAcks := func() {
+		for {
+			select {
+			case tok, ok := <-acks:
+				if !ok {
+					return
+				}
+				if acked.before(tok) {
+					acked = tok
+				}
+			default:
+				return
+			}
+		}
+	}
+
+	start := acked
+	for f := start.Frame; f < len(pd.Frames); f++ {
+		data := pd.Frames[f].CompressedData
+		offset := int64(0)
+		if f == start.Frame {
+			offset = min(start.Offset, int64(len(data)))
+		}
+		for {
+			end := min(offset+int64(chunkSize), int64(len(data)))
+			chunk := FrameChunk{
+				InstanceUID: instanceUID,
+				Frame:       f,
+				Offset:      offset,
+				Data:        data[offset:end],
+				Last:        end == int64(len(data)),
+			}
+			if acks != nil {
+				for len(inflight) >= window {
+					if err := waitAck(); err != nil {
+						return acked, err
+					}
+				}
+			}
+			if err := t.Send(ctx, chunk); err != nil {
+				drainAcks()
+				return acked, err
+			}
+			next := ResumeToken{InstanceUID: instanceUID, Frame: f, Offset: end}
+			if chunk.Last {
+				next = ResumeToken{InstanceUID: instanceUID, Frame: f + 1}
+			}
+			if acks == nil {
+				acked = next
+			} else {
+				inflight = append(inflight, next)
+			}
+			if chunk.Last {
+				break
+			}
+			offset = end
+		}
+	}
+
+	for len(inflight) > 0 {
+		if err := waitAck(); err != nil {
+			return acked, err
+		}
+	}
+	return acked, nil
+}
+
+// FrameAssembler rebuilds encapsulated frames from chunks on the receiving
+// side and reports the resume position to acknowledge. Chunks already
+// received, such as those resent after a resume, are ignored.
+type FrameAssembler struct {
+	instanceUID string
+	frames      []Frame
+	pos         ResumeToken
+	current     []byte
+}
+
+// NewFrameAssembler returns an assembler for the frames of one instance
+func NewFrameAssembler(instanceUID string) *FrameAssembler {
+	return &FrameAssembler{instanceUID: instanceUID, pos: ResumeToken{InstanceUID: instanceUID}}
+}
+
+// Add appends a chunk and returns the token to acknowledge. A chunk that
+// skips ahead of the received data is an error.
+func (a *FrameAssembler) Add(c FrameChunk) (ResumeToken, error) {
+	if c.InstanceUID != a.instanceUID {
+		return a.pos, fmt.Errorf("chunk for instance %s, assembling %s", c.InstanceUID, a.instanceUID)
+	}
+	end := ResumeToken{InstanceUID: c.InstanceUID, Frame: c.Frame, Offset: c.Offset + int64(len(c.Data))}
+	if c.Last {
+		end = ResumeToken{InstanceUID: c.InstanceUID, Frame: c.Frame + 1}
+	}
+	if !a.pos.before(end) {
+		return a.pos, nil // duplicate
+	}
+	if c.Frame != a.pos.Frame || c.Offset > a.pos.Offset {
+		return a.pos, fmt.Errorf("chunk at frame %d offset %d, expected frame %d offset %d", c.Frame, c.Offset, a.pos.Frame, a.pos.Offset)
+	}
+
+	// Keep only the part of an overlapping chunk not yet received
+	a.current = append(a.current, c.Data[a.pos.Offset-c.Offset:]...)
+	if c.Last {
+		a.frames = append(a.frames, Frame{CompressedData: a.current})
+		a.current = nil
+	}
+	a.pos = end
+	return a.pos, nil
+}
+
+// Token returns the position up to which chunks have been received
+func (a *FrameAssembler) Token() ResumeToken {
+	return a.pos
+}
+
+// PixelData returns the frames completed so far as encapsulated pixel data
+func (a *FrameAssembler) PixelData() *PixelData {
+	return &PixelData{IsEncapsulated: true, Frames: a.frames}
+}
@@ -0,0 +1,115 @@
+package dicos
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loopback delivers chunks straight to an assembler, optionally failing
+// after a number of sends or withholding acknowledgements
+type loopback struct {
+	asm       *FrameAssembler
+	acks      chan ResumeToken
+	sent      int
+	failAfter int
+	silent    bool
+}
+
+func newLoopback(asm *FrameAssembler) *loopback {
+	return &loopback{asm: asm, acks: make(chan ResumeToken, 1024), failAfter: -1}
+}
+
+func (l *loopback) Send(_ context.Context, c FrameChunk) error {
+	if l.sent == l.failAfter {
+		return errors.New("link down")
+	}
+	l.sent++
+	tok, err := l.asm.Add(c)
+	if err != nil {
+		return err
+	}
+	if !l.silent {
+		l.acks <- tok
+	}
+	return nil
+}
+
+func (l *loopback) Acks() <-chan ResumeToken { return l.acks }
+
+func streamTestPixelData() *PixelData {
+	pd := &PixelData{IsEncapsulated: true}
+	for f := 0; f < 3; f++ {
+		data := make([]byte, 100+f*37)
+		for i := range data {
+			data[i] = byte(i*7 + f)
+		}
+		pd.Frames = append(pd.Frames, Frame{CompressedData: data})
+	}
+	return pd
+}
+
+func TestStreamFrames_ResumeMidInstance(t *testing.T) {
+	ctx := context.Background()
+	pd := streamTestPixelData()
+	asm := NewFrameAssembler("1.2.3")
+
+	link := newLoopback(asm)
+	link.failAfter = 5
+	token, err := StreamFrames(ctx, pd, "1.2.3", link, StreamOptions{ChunkSize: 32, Window: 2})
+	require.Error(t, err)
+	assert.Equal(t, 1, token.Frame, "frame 0 takes four chunks, the fifth is inside frame 1")
+	assert.Equal(t, int64(32), token.Offset)
+
+	parsed, err := ParseResumeToken(token.String())
+	require.NoError(t, err)
+	assert.Equal(t, token, parsed)
+
+	token, err = StreamFrames(ctx, pd, "1.2.3", newLoopback(asm), StreamOptions{ChunkSize: 32, Window: 2, Resume: &parsed})
+	require.NoError(t, err)
+	assert.Equal(t, ResumeToken{InstanceUID: "1.2.3", Frame: 3}, token)
+
+	got := asm.PixelData()
+	require.Len(t, got.Frames, 3)
+	for i := range pd.Frames {
+		assert.Equal(t, pd.Frames[i].CompressedData, got.Frames[i].CompressedData, "frame %d", i)
+	}
+}
+
+func TestStreamFrames_Backpressure(t *testing.T) {
+	pd := streamTestPixelData()
+	link := newLoopback(NewFrameAssembler("1.2.3"))
+	link.silent = true
+
+	token, err := StreamFrames(context.Background(), pd, "1.2.3", link, StreamOptions{ChunkSize: 10, Window: 4, AckTimeout: 10 * time.Millisecond})
+	assert.ErrorIs(t, err, ErrAckTimeout)
+	assert.Equal(t, 4, link.sent, "sender stops when the window is full")
+	assert.Equal(t, ResumeToken{InstanceUID: "1.2.3"}, token)
+}
+
+func TestFrameAssembler_Ordering(t *testing.T) {
+	asm := NewFrameAssembler("1.2.3")
+	tok, err := asm.Add(FrameChunk{InstanceUID: "1.2.3", Data: []byte{1, 2, 3, 4}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), tok.Offset)
+
+	_, err = asm.Add(FrameChunk{InstanceUID: "1.2.3", Offset: 2, Data: []byte{3, 4, 5, 6}, Last: true})
+	require.NoError(t, err, "overlapping resend")
+	assert.Equal(t, []byte{1, 2, 3, 4, 5, 6}, asm.PixelData().Frames[0].CompressedData)
+
+	_, err = asm.Add(FrameChunk{InstanceUID: "1.2.3", Frame: 0, Data: []byte{9}})
+	assert.NoError(t, err, "duplicate is ignored")
+	_, err = asm.Add(FrameChunk{InstanceUID: "1.2.3", Frame: 1, Offset: 8, Data: []byte{9}})
+	assert.Error(t, err, "gap")
+	_, err = asm.Add(FrameChunk{InstanceUID: "9.9", Data: []byte{9}})
+	assert.Error(t, err, "other instance")
+
+	_, err = StreamFrames(context.Background(), &PixelData{}, "1.2.3", newLoopback(asm), StreamOptions{})
+	assert.Error(t, err, "native pixel data")
+	_, err = ParseResumeToken("1.2.3:x:0")
+	assert.Error(t, err)
+}
@@ -0,0 +1,321 @@
+package dicos
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// StreamingReader reads a DICOS file without holding its pixel data in memory.
+// Header reads the elements up to the Pixel Data (7FE0,0010); the frames are
+// then read from the source one at a time with NextFrame or Frames, so a
+// multi-gigabyte CT volume costs one frame of memory instead of the whole file.
+//
+// Native frames are split by Rows x Columns x SamplesPerPixel x BitsAllocated;
+// encapsulated frames are read one fragment per frame, as Parse does. Elements
+// following the pixel data are added to the header once the last frame has
+// been read.
+//
+// Example:
+//
+//	f, _ := os.Open("scan.dcs")
+//	defer f.Close()
+//	sr := dicos.NewStreamingReader(bufio.NewReader(f))
+//	hdr, err := sr.Header(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	err = sr.Frames(ctx, func(i int, frame dicos.Frame) error {
+//		return process(hdr, i, frame)
+//	})
+type StreamingReader struct {
+	r      *Reader
+	header *Dataset
+	state  streamState
+
+	frame     int   // index of the next frame
+	remaining int64 // native bytes left in the pixel data value
+	frameSize int64 // native bytes per frame
+	bytesPP   int   // native bytes per sample
+	firstItem bool  // the next encapsulated item may be the offset table
+	metas     []*Dataset
+}
+
+type streamState int
+
+const (
+	streamStart  streamState = iota
+	streamFrames             // positioned inside the pixel data
+	streamDone               // pixel data and trailing elements read
+)
+
+// NewStreamingReader creates a StreamingReader over r
+func NewStreamingReader(r io.Reader) *StreamingReader {
+	return NewStreamingReaderWithOptions(r, ParseOptions{})
+}
+
+// NewStreamingReaderWithOptions creates a StreamingReader over r using opts
+// for the header elements
+func NewStreamingReaderWithOptions(r io.Reader, opts ParseOptions) *StreamingReader {
+	reader := NewReader(r)
+	reader.opts = opts
+	return &StreamingReader{r: reader}
+}
+
+// Issues returns the non-fatal violations encountered so far
+func (s *StreamingReader) Issues() []ParseIssue {
+	return s.r.Issues()
+}
+
+// Header reads the file up to the pixel data and returns those elements.
+// The returned dataset has no Pixel Data element; it is safe to call Header
+// again, which returns the same dataset.
+func (s *StreamingReader) Header(ctx context.Context) (*Dataset, error) {
+	if s.header != nil {
+		return s.header, nil
+	}
+	s.r.ctx = ctx
+	ds := &Dataset{Elements: make(map[Tag]*Element)}
+	t, err := s.r.readPreamble(ds)
+	for err == nil {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+		if t == pixelDataTag {
+			if err := s.startPixelData(ds); err != nil {
+				return nil, err
+			}
+			s.header = ds
+			return ds, nil
+		}
+		var elem *Element
+		if elem, err = s.r.readElement(t); err != nil {
+			return nil, fmt.Errorf("failed to read element %v: %w", t, err)
+		}
+		if elem != nil {
+			if err := s.r.put(ds, elem); err != nil {
+				return nil, err
+			}
+		}
+		t, err = s.r.readTag()
+	}
+	if err != io.EOF {
+		return nil, err
+	}
+	s.header, s.state = ds, streamDone
+	return ds, nil
+}
+
+// startPixelData reads the pixel data element header and prepares frame reads
+func (s *StreamingReader) startPixelData(ds *Dataset) error {
+	_, vl, err := s.r.readElementHeader(pixelDataTag)
+	if err != nil {
+		return fmt.Errorf("failed to read element %v: %w", pixelDataTag, err)
+	}
+	s.state = streamFrames
+	if seq, ok := ds.Elements[tag.PerFrameFunctionalGroupsSequence]; ok {
+		s.metas, _ = seq.Value.([]*Dataset)
+	}
+	if vl == undefinedLength {
+		s.firstItem = true
+		return nil
+	}
+
+	rows, cols := GetRows(ds), GetColumns(ds)
+	if rows == 0 || cols == 0 {
+		return fmt.Errorf("invalid dimensions for pixel data: %dx%d", rows, cols)
+	}
+	s.bytesPP = (GetBitsAllocated(ds) + 7) / 8
+	if s.bytesPP == 0 {
+		s.bytesPP = 2
+	}
+	spp := 1
+	if elem, ok := ds.Elements[tag.SamplesPerPixel]; ok {
+		if v, ok := elem.GetInt(); ok && v > 0 {
+			spp = v
+		}
+	}
+	s.frameSize = int64(rows * cols * spp * s.bytesPP)
+	s.remaining = int64(vl)
+	s.r.log().DebugContext(s.r.ctx, "Streaming native pixel data",
+		slog.Int64("frameSize", s.frameSize),
+		slog.Int64("length", s.remaining))
+	return nil
+}
+
+// NextFrame reads the next frame, returning io.EOF after the last one.
+// Native frames carry Data, encapsulated frames CompressedData; Meta is
+// filled from the header's Per-frame Functional Groups Sequence.
+func (s *StreamingReader) NextFrame(ctx context.Context) (Frame, error) {
+	if _, err := s.Header(ctx); err != nil {
+		return Frame{}, err
+	}
+	if s.state != streamFrames {
+		return Frame{}, io.EOF
+	}
+	if err := ctx.Err(); err != nil {
+		return Frame{}, err
+	}
+	s.r.ctx = ctx
+
+	var frame Frame
+	var err error
+	if s.frameSize > 0 {
+		frame, err = s.nextNativeFrame()
+	} else {
+		frame, err = s.nextEncapsulatedFrame()
+	}
+	if err == io.EOF {
+		s.state = streamDone
+		if err := s.readRemaining(); err != nil {
+			return Frame{}, err
+		}
+		return Frame{}, io.EOF
+	}
+	if err != nil {
+		return Frame{}, err
+	}
+	if s.frame < len(s.metas) && s.metas[s.frame] != nil {
+		frame.Meta = parseFrameMeta(s.metas[s.frame])
+	}
+	s.frame++
+	return frame, nil
+}
+
+// Frames calls fn with each remaining frame and its index, stopping at the
+// first error fn returns
+func (s *StreamingReader) Frames(ctx context.Context, fn func(int, Frame) error) error {
+	for {
+		i := s.frame
+		frame, err := s.NextFrame(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(i, frame); err != nil {
+			return err
+		}
+	}
+}
+
+// nextNativeFrame reads one frame of native pixel data
+func (s *StreamingReader) nextNativeFrame() (Frame, error) {
+	if s.remaining < s.frameSize {
+		if s.remaining > 0 {
+			if err := s.r.cr.skip(s.remaining); err != nil {
+				return Frame{}, err
+			}
+			if err := s.r.issue(pixelDataTag, "%d bytes of pixel data after frame %d do not fill a frame", s.remaining, s.frame); err != nil {
+				return Frame{}, err
+			}
+			s.remaining = 0
+		}
+		return Frame{}, io.EOF
+	}
+	if err := s.r.reserve(ResourceFrame, s.frameSize); err != nil {
+		return Frame{}, err
+	}
+	buf, err := readBytes(s.r.r, s.frameSize)
+	if err != nil {
+		return Frame{}, fmt.Errorf("reading frame %d: %w", s.frame, err)
+	}
+	s.remaining -= s.frameSize
+
+	data := make([]uint16, int(s.frameSize)/s.bytesPP)
+	for i := range data {
+		if s.bytesPP == 2 {
+			data[i] = binary.LittleEndian.Uint16(buf[i*2:])
+		} else {
+			data[i] = uint16(buf[i])
+		}
+	}
+	return Frame{Data: data}, nil
+}
+
+// nextEncapsulatedFrame reads the next fragment of encapsulated pixel data,
+// skipping the Basic Offset Table and empty fragments
+func (s *StreamingReader) nextEncapsulatedFrame() (Frame, error) {
+	for {
+		t, err := s.r.readTag()
+		if err != nil {
+			return Frame{}, s.truncated(err)
+		}
+		var length uint32
+		if err := binary.Read(s.r.r, binary.LittleEndian, &length); err != nil {
+			return Frame{}, s.truncated(err)
+		}
+		switch {
+		case t == seqDelimTag:
+			return Frame{}, io.EOF
+		case t != itemTag || length == undefinedLength:
+			if err := s.r.issue(pixelDataTag, "unexpected item %v in encapsulated pixel data", t); err != nil {
+				return Frame{}, err
+			}
+			if err := s.r.resyncToSequenceDelimiter(pixelDataTag); err != nil {
+				return Frame{}, err
+			}
+			return Frame{}, io.EOF
+		}
+
+		if err := s.r.reserve(ResourceFrame, int64(length)); err != nil {
+			return Frame{}, err
+		}
+		data, err := readBytes(s.r.r, int64(length))
+		if err != nil {
+			return Frame{}, s.truncated(err)
+		}
+		if s.firstItem {
+			s.firstItem = false
+			if _, ok := parseOffsetTable(data); ok {
+				continue
+			}
+		}
+		if length == 0 {
+			continue
+		}
+		if length%2 != 0 {
+			if err := s.r.issue(pixelDataTag, "odd length %d of fragment %d", length, s.frame); err != nil {
+				return Frame{}, err
+			}
+		}
+		return Frame{CompressedData: data}, nil
+	}
+}
+
+// truncated reports a stream that ends inside the pixel data
+func (s *StreamingReader) truncated(err error) error {
+	if err := s.r.issue(pixelDataTag, "truncated encapsulated pixel data after %d frames: %v", s.frame, err); err != nil {
+		return err
+	}
+	return io.EOF
+}
+
+// readRemaining adds the elements following the pixel data to the header.
+// Content that does not parse is reported as an issue and ignored.
+func (s *StreamingReader) readRemaining() error {
+	for {
+		t, err := s.r.readTag()
+		if err == io.EOF {
+			return nil
+		}
+		if err == nil {
+			var elem *Element
+			if elem, err = s.r.readElementWithTag(t); err == nil {
+				if err = s.r.put(s.header, elem); err == nil {
+					continue
+				}
+			}
+		}
+		if errors.Is(err, ErrResourceLimit) || errors.Is(err, ErrDuplicateTag) {
+			return err
+		}
+		return s.r.issue(t, "unreadable data after the pixel data: %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// onlyReader hides the io.ReaderAt and io.Seeker of its source, as a pipe would
+type onlyReader struct{ r io.Reader }
+
+func (o onlyReader) Read(p []byte) (int, error) { return o.r.Read(p) }
+
+func TestStreamingReader_MatchesParse(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		codec Codec
+	}{
+		{"native", nil},
+		{"jpeg-ls", CodecJPEGLS},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			data := writeTestCT(t, 8, 8, 3, tt.codec)
+			ds, err := ReadBufferContext(ctx, data)
+			require.NoError(t, err)
+			want, err := ds.GetPixelData()
+			require.NoError(t, err)
+
+			sr := NewStreamingReader(onlyReader{bytes.NewReader(data)})
+			hdr, err := sr.Header(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, GetRows(ds), GetRows(hdr))
+			assert.NotContains(t, hdr.Elements, pixelDataTag)
+			assert.Equal(t, ds.Elements[tag.SOPInstanceUID].Value, hdr.Elements[tag.SOPInstanceUID].Value)
+
+			var got []Frame
+			require.NoError(t, sr.Frames(ctx, func(i int, f Frame) error {
+				assert.Equal(t, len(got), i)
+				got = append(got, f)
+				return nil
+			}))
+			require.Len(t, got, len(want.Frames))
+			for i := range got {
+				assert.Equal(t, want.Frames[i].Data, got[i].Data, "frame %d", i)
+				assert.Equal(t, want.Frames[i].CompressedData, got[i].CompressedData, "frame %d", i)
+			}
+
+			_, err = sr.NextFrame(ctx)
+			assert.ErrorIs(t, err, io.EOF)
+		})
+	}
+}
+
+func TestStreamingReader_StopAndCancel(t *testing.T) {
+	data := writeTestCT(t, 4, 4, 4, nil)
+	sr := NewStreamingReader(bytes.NewReader(data))
+
+	stop := errors.New("stop")
+	n := 0
+	err := sr.Frames(context.Background(), func(i int, f Frame) error {
+		n++
+		if i == 1 {
+			return stop
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 2, n)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = sr.NextFrame(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = NewStreamingReader(bytes.NewReader([]byte("not a dicos file"))).Header(context.Background())
+	assert.Error(t, err)
+}
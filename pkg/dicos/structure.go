@@ -0,0 +1,110 @@
+package dicos
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MaxSequenceDepth is the deepest sequence nesting Write accepts. Real IODs
+// nest a handful of levels; anything deeper is almost certainly a cycle or a
+// construction bug.
+var MaxSequenceDepth = 16
+
+// Structural violations reported by CheckStructure, wrapped in a StructureError
+var (
+	ErrNestedPixelData = errors.New("pixel data is not allowed inside this sequence")
+	ErrOddItemLength   = errors.New("odd value length makes the sequence item odd")
+	ErrSequenceDepth   = errors.New("sequences nested too deeply")
+	ErrNilItem         = errors.New("sequence item is nil")
+	ErrSequenceValue   = errors.New("SQ element does not hold sequence items")
+)
+
+// pixelDataSequences are the sequences whose items may carry Pixel Data
+var pixelDataSequences = map[Tag]bool{
+	{Group: 0x0088, Element: 0x0200}: true, // Icon Image Sequence
+}
+
+// StructureError locates a structural violation within nested sequences
+type StructureError struct {
+	Path string // e.g. "(5200,9230)[3].(0020,9111)[0]"
+	Tag  Tag    // offending element
+	Err  error  // one of the Err* violations
+}
+
+func (e *StructureError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("%v: %v", e.Tag, e.Err)
+	}
+	return fmt.Sprintf("%s.%v: %v", e.Path, e.Tag, e.Err)
+}
+
+func (e *StructureError) Unwrap() error {
+	return e.Err
+}
+
+// CheckStructure walks ds and its sequences to any depth and returns a
+// *StructureError for the first violation of the encoding rules Write
+// enforces: Pixel Data only in sequences that allow it (Icon Image Sequence),
+// even-length values inside items, no nil items, and at most
+// MaxSequenceDepth levels of nesting.
+//
+// Example:
+//
+//	var se *dicos.StructureError
+//	if err := dicos.CheckStructure(ds); errors.As(err, &se) {
+//		log.Printf("illegal structure at %s: %v", se.Path, se.Err)
+//	}
+func CheckStructure(ds *Dataset) error {
+	return checkStructure(ds, nil, Tag{}, 0)
+}
+
+func checkStructure(ds *Dataset, path []string, seq Tag, depth int) error {
+	for _, t := range sortedTags(ds) {
+		elem := ds.Elements[t]
+		fail := func(err error) error {
+			return &StructureError{Path: strings.Join(path, "."), Tag: t, Err: err}
+		}
+		if depth > 0 {
+			if t == pixelDataTag && !pixelDataSequences[seq] {
+				return fail(ErrNestedPixelData)
+			}
+			if oddValueLength(elem.Value) {
+				return fail(ErrOddItemLength)
+			}
+		}
+
+		items, isSeq := elem.Value.([]*Dataset)
+		if elem.VR == "SQ" && !isSeq && elem.Value != nil {
+			return fail(ErrSequenceValue)
+		}
+		if !isSeq {
+			continue
+		}
+		if depth+1 > MaxSequenceDepth {
+			return fail(ErrSequenceDepth)
+		}
+		for i, item := range items {
+			if item == nil {
+				return fail(ErrNilItem)
+			}
+			itemPath := append(path[:len(path):len(path)], fmt.Sprintf("%v[%d]", t, i))
+			if err := checkStructure(item, itemPath, t, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// oddValueLength returns true for values the writer emits without padding
+// that have an odd length
+func oddValueLength(v interface{}) bool {
+	switch val := v.(type) {
+	case []byte:
+		return len(val)%2 != 0
+	case *BulkData:
+		return val.Length%2 != 0
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nest wraps item in depth levels of Referenced Image Sequence
+func nest(t *testing.T, item *Dataset, depth int) *Dataset {
+	for range depth {
+		var err error
+		item, err = NewDataset(WithSequence(tag.ReferencedImageSequence, item))
+		require.NoError(t, err)
+	}
+	return item
+}
+
+func TestCheckStructure(t *testing.T) {
+	leaf, err := NewDataset(WithElement(tag.ReferencedSOPInstanceUID, "1.2.3"))
+	require.NoError(t, err)
+	pixels, err := NewDataset(WithPixelData(2, 2, 16, make([]uint16, 4), nil))
+	require.NoError(t, err)
+	odd, err := NewDataset(WithElement(tag.New(0x0009, 0x1010), []byte{1, 2, 3}))
+	require.NoError(t, err)
+	icon, err := NewDataset(WithSequence(tag.New(0x0088, 0x0200), pixels))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		ds   *Dataset
+		want error
+	}{
+		{"deep but legal", nest(t, leaf, MaxSequenceDepth), nil},
+		{"too deep", nest(t, leaf, MaxSequenceDepth+1), ErrSequenceDepth},
+		{"pixel data in item", nest(t, pixels, 2), ErrNestedPixelData},
+		{"icon image", icon, nil},
+		{"odd item", nest(t, odd, 1), ErrOddItemLength},
+		{"nil item", nest(t, nil, 2), ErrNilItem},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckStructure(tt.ds)
+			if tt.want == nil {
+				require.NoError(t, err)
+				var buf bytes.Buffer
+				_, err = Write(&buf, tt.ds)
+				require.NoError(t, err)
+				_, err = ReadBufferContext(context.Background(), buf.Bytes())
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.want)
+			var se *StructureError
+			require.True(t, errors.As(err, &se))
+			assert.Contains(t, se.Path, tag.ReferencedImageSequence.String()+"[0]")
+
+			var buf bytes.Buffer
+			_, err = Write(&buf, tt.ds)
+			assert.ErrorIs(t, err, tt.want)
+			assert.Zero(t, buf.Len(), "nothing is written")
+		})
+	}
+}
+
+func TestCheckStructure_Path(t *testing.T) {
+	pixels, err := NewDataset(WithPixelData(2, 2, 16, make([]uint16, 4), nil))
+	require.NoError(t, err)
+	ok, err := NewDataset(WithElement(tag.ReferencedSOPInstanceUID, "1.2.3"))
+	require.NoError(t, err)
+	ds, err := NewDataset(WithSequence(tag.ReferencedImageSequence, ok, pixels))
+	require.NoError(t, err)
+
+	err = CheckStructure(ds)
+	var se *StructureError
+	require.True(t, errors.As(err, &se))
+	assert.Equal(t, "(0008,1140)[1]", se.Path)
+	assert.Equal(t, tag.PixelData, se.Tag)
+	assert.Equal(t, "(0008,1140)[1].(7FE0,0010): pixel data is not allowed inside this sequence", se.Error())
+}
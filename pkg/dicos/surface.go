@@ -0,0 +1,320 @@
+package dicos
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// Surface is a surface of an AIT 3D image in the Surface Mesh Module
+// (PS3.3 C.27.1): vertices in patient coordinates (mm), optionally a normal
+// per vertex, and triangles joining them. A surface without triangles is a
+// point cloud.
+type Surface struct {
+	Number    int          // SurfaceNumber, its position from 1 when 0
+	Comments  string       // SurfaceComments
+	Points    [][3]float32 // vertices, x, y, z
+	Normals   [][3]float32 // none, or one unit vector per point
+	Triangles [][3]uint32  // 0-based indices into Points, counter-clockwise seen from outside
+
+	Opacity          float32 // RecommendedPresentationOpacity, 1 when 0
+	PresentationType string  // SURFACE, WIREFRAME or POINTS; SURFACE for a mesh, POINTS for a point cloud when empty
+	FiniteVolume     string  // YES, NO or UNKNOWN (the default): the surface encloses a volume
+	Manifold         string  // YES, NO or UNKNOWN (the default)
+}
+
+// Validate checks that the normals match the points and that every triangle
+// indexes existing points
+func (s *Surface) Validate() error {
+	if len(s.Points) == 0 {
+		return fmt.Errorf("surface %d has no points", s.Number)
+	}
+	if len(s.Normals) != 0 && len(s.Normals) != len(s.Points) {
+		return fmt.Errorf("surface %d has %d normals for %d points", s.Number, len(s.Normals), len(s.Points))
+	}
+	for i, tri := range s.Triangles {
+		for _, p := range tri {
+			if int(p) >= len(s.Points) {
+				return fmt.Errorf("surface %d triangle %d indexes point %d of %d", s.Number, i, p, len(s.Points))
+			}
+		}
+	}
+	return nil
+}
+
+// Bounds returns the corners of the axis-aligned box holding every point
+func (s *Surface) Bounds() (lo, hi [3]float32) {
+	if len(s.Points) == 0 {
+		return lo, hi
+	}
+	lo, hi = s.Points[0], s.Points[0]
+	for _, p := range s.Points[1:] {
+		for i := range 3 {
+			lo[i] = min(lo[i], p[i])
+			hi[i] = max(hi[i], p[i])
+		}
+	}
+	return lo, hi
+}
+
+// WithSurfaces writes the Surface Mesh Module: NumberOfSurfaces and a
+// SurfaceSequence item per surface
+//
+// Example:
+//
+//	ds, err := dicos.NewDataset(
+//		dicos.WithFileMeta(dicos.DICOSAIT3DImageStorageUID, uid, ts),
+//		dicos.WithSurfaces(dicos.Surface{Points: pts, Triangles: tris}),
+//	)
+func WithSurfaces(surfaces ...Surface) Option {
+	return func(ds *Dataset) error {
+		items := make([]*Dataset, len(surfaces))
+		for i := range surfaces {
+			s := surfaces[i]
+			if s.Number == 0 {
+				s.Number = i + 1
+			}
+			item, err := surfaceItem(&s)
+			if err != nil {
+				return err
+			}
+			items[i] = item
+		}
+		ds.Elements[tag.NumberOfSurfaces] = &Element{Tag: tag.NumberOfSurfaces, VR: "UL", Value: uint32(len(items))}
+		ds.Elements[tag.SurfaceSequence] = &Element{Tag: tag.SurfaceSequence, VR: "SQ", Value: items}
+		return nil
+	}
+}
+
+// surfaceItem encodes one SurfaceSequence item
+func surfaceItem(s *Surface) (*Dataset, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	opacity := s.Opacity
+	if opacity == 0 {
+		opacity = 1
+	}
+	presentation := s.PresentationType
+	if presentation == "" {
+		presentation = "SURFACE"
+		if len(s.Triangles) == 0 {
+			presentation = "POINTS"
+		}
+	}
+
+	lo, hi := s.Bounds()
+	points := &Dataset{Elements: make(map[Tag]*Element)}
+	setElements(points,
+		&Element{Tag: tag.NumberOfSurfacePoints, VR: "UL", Value: uint32(len(s.Points))},
+		&Element{Tag: tag.PointCoordinatesData, VR: "OF", Value: flattenVec3(s.Points)},
+		&Element{Tag: tag.PointsBoundingBoxCoordinates, VR: "FL", Value: []float32{lo[0], lo[1], lo[2], hi[0], hi[1], hi[2]}},
+	)
+
+	// every point is a vertex of the primitives, so a point cloud lists them all
+	primitives := &Dataset{Elements: make(map[Tag]*Element)}
+	if len(s.Triangles) > 0 {
+		indices := make([]uint32, 0, 3*len(s.Triangles))
+		for _, tri := range s.Triangles {
+			indices = append(indices, tri[0]+1, tri[1]+1, tri[2]+1)
+		}
+		setElements(primitives, &Element{Tag: tag.LongTrianglePointIndexList, VR: "OL", Value: indices})
+	} else {
+		indices := make([]uint32, len(s.Points))
+		for i := range indices {
+			indices[i] = uint32(i + 1)
+		}
+		setElements(primitives, &Element{Tag: tag.LongVertexPointIndexList, VR: "OL", Value: indices})
+	}
+
+	item := &Dataset{Elements: make(map[Tag]*Element)}
+	setElements(item,
+		&Element{Tag: tag.SurfaceNumber, VR: "UL", Value: uint32(s.Number)},
+		&Element{Tag: tag.SurfaceProcessing, VR: "CS", Value: "NO"},
+		&Element{Tag: tag.RecommendedPresentationOpacity, VR: "FL", Value: opacity},
+		&Element{Tag: tag.RecommendedPresentationType, VR: "CS", Value: presentation},
+		&Element{Tag: tag.FiniteVolume, VR: "CS", Value: orUnknown(s.FiniteVolume)},
+		&Element{Tag: tag.Manifold, VR: "CS", Value: orUnknown(s.Manifold)},
+		&Element{Tag: tag.SurfacePointsSequence, VR: "SQ", Value: []*Dataset{points}},
+		&Element{Tag: tag.SurfaceMeshPrimitivesSequence, VR: "SQ", Value: []*Dataset{primitives}},
+	)
+	if s.Comments != "" {
+		setElements(item, &Element{Tag: tag.SurfaceComments, VR: "LT", Value: s.Comments})
+	}
+	if len(s.Normals) > 0 {
+		normals := &Dataset{Elements: make(map[Tag]*Element)}
+		setElements(normals,
+			&Element{Tag: tag.NumberOfVectors, VR: "UL", Value: uint32(len(s.Normals))},
+			&Element{Tag: tag.VectorDimensionality, VR: "US", Value: uint16(3)},
+			&Element{Tag: tag.VectorCoordinateData, VR: "OF", Value: flattenVec3(s.Normals)},
+		)
+		setElements(item, &Element{Tag: tag.SurfacePointsNormalsSequence, VR: "SQ", Value: []*Dataset{normals}})
+	}
+	return item, nil
+}
+
+// ParseSurfaces reads the Surface Mesh Module of ds, nil when it has none
+func ParseSurfaces(ds *Dataset) ([]Surface, error) {
+	elem, ok := ds.Elements[tag.SurfaceSequence]
+	if !ok {
+		return nil, nil
+	}
+	items, _ := elem.Value.([]*Dataset)
+	surfaces := make([]Surface, len(items))
+	for i, item := range items {
+		s, err := parseSurface(item)
+		if err != nil {
+			return nil, fmt.Errorf("surface item %d: %w", i, err)
+		}
+		if s.Number == 0 {
+			s.Number = i + 1
+		}
+		surfaces[i] = s
+	}
+	return surfaces, nil
+}
+
+// parseSurface decodes one SurfaceSequence item
+func parseSurface(item *Dataset) (Surface, error) {
+	s := Surface{
+		Number:           attrInt(item, tag.SurfaceNumber),
+		Comments:         attrString(item, tag.SurfaceComments),
+		Opacity:          float32(attrFloat(item, tag.RecommendedPresentationOpacity)),
+		PresentationType: attrString(item, tag.RecommendedPresentationType),
+		FiniteVolume:     attrString(item, tag.FiniteVolume),
+		Manifold:         attrString(item, tag.Manifold),
+	}
+
+	points := firstItem(item, tag.SurfacePointsSequence)
+	if points == nil {
+		return s, fmt.Errorf("no surface points")
+	}
+	coords, err := float32Values(points.Elements[tag.PointCoordinatesData])
+	if err != nil {
+		return s, fmt.Errorf("point coordinates: %w", err)
+	}
+	if s.Points, err = vec3s(coords); err != nil {
+		return s, fmt.Errorf("point coordinates: %w", err)
+	}
+	if n := attrInt(points, tag.NumberOfSurfacePoints); n != 0 && n != len(s.Points) {
+		return s, fmt.Errorf("%d point coordinates, NumberOfSurfacePoints is %d", len(s.Points), n)
+	}
+
+	if normals := firstItem(item, tag.SurfacePointsNormalsSequence); normals != nil {
+		if dim := attrInt(normals, tag.VectorDimensionality); dim != 0 && dim != 3 {
+			return s, fmt.Errorf("normals have %d dimensions, want 3", dim)
+		}
+		data, err := float32Values(normals.Elements[tag.VectorCoordinateData])
+		if err != nil {
+			return s, fmt.Errorf("normals: %w", err)
+		}
+		if s.Normals, err = vec3s(data); err != nil {
+			return s, fmt.Errorf("normals: %w", err)
+		}
+	}
+
+	if primitives := firstItem(item, tag.SurfaceMeshPrimitivesSequence); primitives != nil {
+		if elem, ok := primitives.Elements[tag.LongTrianglePointIndexList]; ok {
+			indices, err := uint32Values(elem)
+			if err != nil {
+				return s, fmt.Errorf("triangles: %w", err)
+			}
+			if len(indices)%3 != 0 {
+				return s, fmt.Errorf("%d triangle point indices are not whole triangles", len(indices))
+			}
+			s.Triangles = make([][3]uint32, len(indices)/3)
+			for i := range s.Triangles {
+				for j := range 3 {
+					p := indices[3*i+j]
+					if p == 0 {
+						return s, fmt.Errorf("triangle %d has point index 0, indices start at 1", i)
+					}
+					s.Triangles[i][j] = p - 1
+				}
+			}
+		}
+	}
+	return s, s.Validate()
+}
+
+// setElements puts each element into ds under its tag
+func setElements(ds *Dataset, elems ...*Element) {
+	for _, e := range elems {
+		ds.Elements[e.Tag] = e
+	}
+}
+
+// orUnknown returns v, or UNKNOWN when it is empty
+func orUnknown(v string) string {
+	if v == "" {
+		return "UNKNOWN"
+	}
+	return v
+}
+
+// flattenVec3 lays vectors out as consecutive x, y, z components
+func flattenVec3(v [][3]float32) []float32 {
+	out := make([]float32, 0, 3*len(v))
+	for _, p := range v {
+		out = append(out, p[0], p[1], p[2])
+	}
+	return out
+}
+
+// vec3s groups consecutive components into vectors
+func vec3s(f []float32) ([][3]float32, error) {
+	if len(f)%3 != 0 {
+		return nil, fmt.Errorf("%d components are not whole 3D vectors", len(f))
+	}
+	out := make([][3]float32, len(f)/3)
+	for i := range out {
+		out[i] = [3]float32{f[3*i], f[3*i+1], f[3*i+2]}
+	}
+	return out, nil
+}
+
+// float32Values returns an OF or FL value, as built or as read (little
+// endian bytes)
+func float32Values(elem *Element) ([]float32, error) {
+	if elem == nil {
+		return nil, fmt.Errorf("missing")
+	}
+	switch v := elem.Value.(type) {
+	case []float32:
+		return v, nil
+	case float32:
+		return []float32{v}, nil
+	case []byte:
+		if len(v)%4 != 0 {
+			return nil, fmt.Errorf("%d bytes are not 32-bit floats", len(v))
+		}
+		out := make([]float32, len(v)/4)
+		for i := range out {
+			out[i] = math.Float32frombits(binary.LittleEndian.Uint32(v[4*i:]))
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("%T is not 32-bit floats", elem.Value)
+}
+
+// uint32Values returns an OL or UL value, as built or as read
+func uint32Values(elem *Element) ([]uint32, error) {
+	switch v := elem.Value.(type) {
+	case []uint32:
+		return v, nil
+	case uint32:
+		return []uint32{v}, nil
+	case []byte:
+		if len(v)%4 != 0 {
+			return nil, fmt.Errorf("%d bytes are not 32-bit integers", len(v))
+		}
+		out := make([]uint32, len(v)/4)
+		for i := range out {
+			out[i] = binary.LittleEndian.Uint32(v[4*i:])
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("%T is not 32-bit integers", elem.Value)
+}
@@ -0,0 +1,111 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tetrahedron is a closed mesh with a normal per vertex
+func tetrahedron() Surface {
+	return Surface{
+		Comments:     "torso",
+		Points:       [][3]float32{{0, 0, 0}, {100, 0, 0}, {0, 100, 0}, {0, 0, 100.5}},
+		Normals:      [][3]float32{{-0.577, -0.577, -0.577}, {1, 0, 0}, {0, 1, 0}, {0, 0, 1}},
+		Triangles:    [][3]uint32{{0, 2, 1}, {0, 1, 3}, {0, 3, 2}, {1, 2, 3}},
+		Opacity:      0.5,
+		FiniteVolume: "YES",
+		Manifold:     "YES",
+	}
+}
+
+func TestSurfaces_RoundTrip(t *testing.T) {
+	cloud := Surface{Points: [][3]float32{{-1, 2, 3}, {4, -5, 6}}}
+	ait := NewAIT3DImage()
+	ait.SetPixelData(4, 4, 2, make([]uint16, 32))
+	ait.Surfaces = []Surface{tetrahedron(), cloud}
+
+	for _, ts := range []string{string(ExplicitVRLittleEndian), string(ImplicitVRLittleEndian)} {
+		t.Run(ts, func(t *testing.T) {
+			ds, err := ait.GetDataset()
+			require.NoError(t, err)
+			ds.Elements[tag.TransferSyntaxUID].Value = ts
+
+			var buf bytes.Buffer
+			_, err = Write(&buf, ds)
+			require.NoError(t, err)
+			got, err := ParseWithOptions(context.Background(), bytes.NewReader(buf.Bytes()), ParseOptions{})
+			require.NoError(t, err)
+
+			surfaces, err := ParseSurfaces(got)
+			require.NoError(t, err)
+			require.Len(t, surfaces, 2)
+			assert.Equal(t, 2, attrInt(got, tag.NumberOfSurfaces))
+
+			want := tetrahedron()
+			mesh := surfaces[0]
+			assert.Equal(t, 1, mesh.Number)
+			assert.Equal(t, "torso", mesh.Comments)
+			assert.Equal(t, want.Points, mesh.Points)
+			assert.Equal(t, want.Normals, mesh.Normals)
+			assert.Equal(t, want.Triangles, mesh.Triangles)
+			assert.Equal(t, float32(0.5), mesh.Opacity)
+			assert.Equal(t, "SURFACE", mesh.PresentationType)
+			assert.Equal(t, "YES", mesh.Manifold)
+
+			pc := surfaces[1]
+			assert.Equal(t, 2, pc.Number)
+			assert.Equal(t, cloud.Points, pc.Points)
+			assert.Empty(t, pc.Triangles)
+			assert.Empty(t, pc.Normals)
+			assert.Equal(t, "POINTS", pc.PresentationType)
+			assert.Equal(t, "UNKNOWN", pc.FiniteVolume)
+			assert.Equal(t, float32(1), pc.Opacity)
+		})
+	}
+}
+
+func TestSurfaces_Encoding(t *testing.T) {
+	ds, err := NewDataset(WithSurfaces(tetrahedron()))
+	require.NoError(t, err)
+	item := firstItem(ds, tag.SurfaceSequence)
+	require.NotNil(t, item)
+	points := firstItem(item, tag.SurfacePointsSequence)
+	require.NotNil(t, points)
+	assert.Equal(t, []float32{0, 0, 0, 100, 100, 100.5}, points.Elements[tag.PointsBoundingBoxCoordinates].Value)
+	primitives := firstItem(item, tag.SurfaceMeshPrimitivesSequence)
+	require.NotNil(t, primitives)
+	indices := primitives.Elements[tag.LongTrianglePointIndexList].Value.([]uint32)
+	assert.Equal(t, []uint32{1, 3, 2}, indices[:3], "point indices are 1-based")
+}
+
+func TestSurfaces_Invalid(t *testing.T) {
+	bad := tetrahedron()
+	bad.Triangles = append(bad.Triangles, [3]uint32{1, 2, 4})
+	_, err := NewDataset(WithSurfaces(bad))
+	assert.ErrorContains(t, err, "triangle 4 indexes point 4 of 4")
+
+	bad = tetrahedron()
+	bad.Normals = bad.Normals[:2]
+	_, err = NewDataset(WithSurfaces(bad))
+	assert.ErrorContains(t, err, "2 normals for 4 points")
+
+	_, err = NewDataset(WithSurfaces(Surface{}))
+	assert.ErrorContains(t, err, "no points")
+
+	// a zero index is not a point
+	ds, err := NewDataset(WithSurfaces(tetrahedron()))
+	require.NoError(t, err)
+	primitives := firstItem(firstItem(ds, tag.SurfaceSequence), tag.SurfaceMeshPrimitivesSequence)
+	primitives.Elements[tag.LongTrianglePointIndexList].Value = []uint32{0, 1, 2}
+	_, err = ParseSurfaces(ds)
+	assert.ErrorContains(t, err, "indices start at 1")
+
+	surfaces, err := ParseSurfaces(&Dataset{Elements: make(map[Tag]*Element)})
+	assert.NoError(t, err)
+	assert.Nil(t, surfaces)
+}
@@ -19,6 +19,14 @@ func (t Tag) Equals(other Tag) bool {
 	return t.Group == other.Group && t.Element == other.Element
 }
 
+// Less returns true if t sorts before other in dataset order
+func (t Tag) Less(other Tag) bool {
+	if t.Group != other.Group {
+		return t.Group < other.Group
+	}
+	return t.Element < other.Element
+}
+
 // IsPrivate returns true if this is a private tag (odd group number)
 func (t Tag) IsPrivate() bool {
 	return t.Group%2 == 1
@@ -31,118 +39,142 @@ func (t Tag) IsGroup0002() bool {
 
 // Standard DICOM Tags - File Meta Information (Group 0002)
 var (
-	FileMetaInformationGroupLength = Tag{0x0002, 0x0000}
-	FileMetaInformationVersion     = Tag{0x0002, 0x0001}
-	MediaStorageSOPClassUID        = Tag{0x0002, 0x0002}
-	MediaStorageSOPInstanceUID     = Tag{0x0002, 0x0003}
-	TransferSyntaxUID              = Tag{0x0002, 0x0010}
-	ImplementationClassUID         = Tag{0x0002, 0x0012}
-	ImplementationVersionName      = Tag{0x0002, 0x0013}
-	SpecificCharacterSet           = Tag{0x0008, 0x0005}
+	FileMetaInformationGroupLength = Tag{0x0002, 0x0000} // UL - Length of the remaining meta elements
+	FileMetaInformationVersion     = Tag{0x0002, 0x0001} // OB - Meta information version 00\01
+	MediaStorageSOPClassUID        = Tag{0x0002, 0x0002} // UI - SOP Class of the stored instance
+	MediaStorageSOPInstanceUID     = Tag{0x0002, 0x0003} // UI - SOP Instance of the stored instance
+	TransferSyntaxUID              = Tag{0x0002, 0x0010} // UI - Encoding of the dataset
+	ImplementationClassUID         = Tag{0x0002, 0x0012} // UI - Writing implementation
+	ImplementationVersionName      = Tag{0x0002, 0x0013} // SH - Writing implementation version
+	SpecificCharacterSet           = Tag{0x0008, 0x0005} // CS - Character set, e.g. ISO_IR 192
+)
+
+// Basic Directory IOD, the DICOMDIR of a File-set (Group 0004)
+var (
+	FileSetID                                   = Tag{0x0004, 0x1130} // CS - File-set label
+	OffsetOfTheFirstDirectoryRecordOfTheRoot    = Tag{0x0004, 0x1200} // UL - First root record
+	OffsetOfTheLastDirectoryRecordOfTheRoot     = Tag{0x0004, 0x1202} // UL - Last root record
+	FileSetConsistencyFlag                      = Tag{0x0004, 0x1212} // US - 0 when no known inconsistencies
+	DirectoryRecordSequence                     = Tag{0x0004, 0x1220} // SQ - Directory records
+	OffsetOfTheNextDirectoryRecord              = Tag{0x0004, 0x1400} // UL - Next record of the same entity
+	OffsetOfReferencedLowerLevelDirectoryEntity = Tag{0x0004, 0x1420} // UL - First record of the child entity
+	DirectoryRecordType                         = Tag{0x0004, 0x1430} // CS - PATIENT, STUDY, SERIES, IMAGE, ...
+	ReferencedFileID                            = Tag{0x0004, 0x1500} // CS - File path components
+	ReferencedSOPClassUIDInFile                 = Tag{0x0004, 0x1510} // UI - SOP Class of the referenced file
+	ReferencedSOPInstanceUIDInFile              = Tag{0x0004, 0x1511} // UI - SOP Instance of the referenced file
+	ReferencedTransferSyntaxUIDInFile           = Tag{0x0004, 0x1512} // UI - Transfer syntax of the referenced file
 )
 
 // Patient Module (Group 0010)
 var (
-	PatientName      = Tag{0x0010, 0x0010}
-	PatientID        = Tag{0x0010, 0x0020}
-	PatientBirthDate = Tag{0x0010, 0x0030}
-	PatientSex       = Tag{0x0010, 0x0040}
-	PatientAge       = Tag{0x0010, 0x1010}
-	PatientComments  = Tag{0x0010, 0x4000}
+	PatientName       = Tag{0x0010, 0x0010} // PN - Patient (OOI owner) name
+	PatientID         = Tag{0x0010, 0x0020} // LO - Patient (OOI) identifier
+	PatientBirthDate  = Tag{0x0010, 0x0030} // DA - Birth date
+	PatientSex        = Tag{0x0010, 0x0040} // CS - M, F or O
+	PatientAge        = Tag{0x0010, 0x1010} // AS - Age, e.g. 042Y
+	PatientComments   = Tag{0x0010, 0x4000} // LT - Free text comments
+	IssuerOfPatientID = Tag{0x0010, 0x0021} // LO - Assigning authority of Patient ID
 )
 
 // General Study Module (Group 0008, 0020)
 var (
-	StudyDate        = Tag{0x0008, 0x0020}
-	StudyTime        = Tag{0x0008, 0x0030}
-	AccessionNumber  = Tag{0x0008, 0x0050}
-	StudyDescription = Tag{0x0008, 0x1030}
-	StudyInstanceUID = Tag{0x0020, 0x000D}
-	StudyID          = Tag{0x0020, 0x0010}
+	StudyDate        = Tag{0x0008, 0x0020} // DA - Study date
+	StudyTime        = Tag{0x0008, 0x0030} // TM - Study time
+	AccessionNumber  = Tag{0x0008, 0x0050} // SH - Order identifier
+	StudyDescription = Tag{0x0008, 0x1030} // LO - Study description
+	StudyInstanceUID = Tag{0x0020, 0x000D} // UI - Study identifier
+	StudyID          = Tag{0x0020, 0x0010} // SH - Site study identifier
 )
 
 // General Series Module
 var (
-	Modality               = Tag{0x0008, 0x0060}
-	SeriesInstanceUID      = Tag{0x0020, 0x000E}
-	SeriesNumber           = Tag{0x0020, 0x0011}
-	InstanceNumber         = Tag{0x0020, 0x0013}
-	SeriesDescription      = Tag{0x0008, 0x103E}
-	SeriesDate             = Tag{0x0008, 0x0021}
-	SeriesTime             = Tag{0x0008, 0x0031}
-	PresentationIntentType = Tag{0x0008, 0x0068}
+	Modality               = Tag{0x0008, 0x0060} // CS - CT, DX, TDR, ...
+	SeriesInstanceUID      = Tag{0x0020, 0x000E} // UI - Series identifier
+	SeriesNumber           = Tag{0x0020, 0x0011} // IS - Series number
+	InstanceNumber         = Tag{0x0020, 0x0013} // IS - Instance number within the series
+	SeriesDescription      = Tag{0x0008, 0x103E} // LO - Series description
+	SeriesDate             = Tag{0x0008, 0x0021} // DA - Series date
+	SeriesTime             = Tag{0x0008, 0x0031} // TM - Series time
+	PresentationIntentType = Tag{0x0008, 0x0068} // CS - FOR PRESENTATION or FOR PROCESSING
 )
 
 // General Equipment Module
 var (
-	Manufacturer          = Tag{0x0008, 0x0070}
-	InstitutionName       = Tag{0x0008, 0x0080}
-	StationName           = Tag{0x0008, 0x1010}
-	ManufacturerModelName = Tag{0x0008, 0x1090}
-	DeviceSerialNumber    = Tag{0x0018, 0x1000}
-	SoftwareVersions      = Tag{0x0018, 0x1020}
+	Manufacturer                = Tag{0x0008, 0x0070} // LO - Equipment manufacturer
+	InstitutionName             = Tag{0x0008, 0x0080} // LO - Institution name
+	StationName                 = Tag{0x0008, 0x1010} // SH - Station name
+	ManufacturerModelName       = Tag{0x0008, 0x1090} // LO - Equipment model
+	DeviceSerialNumber          = Tag{0x0018, 0x1000} // LO - Equipment serial number
+	SoftwareVersions            = Tag{0x0018, 0x1020} // LO - Equipment software versions
+	InstitutionAddress          = Tag{0x0008, 0x0081} // ST - Institution address
+	InstitutionalDepartmentName = Tag{0x0008, 0x1040} // LO - Department name
+	OperatorsName               = Tag{0x0008, 0x1070} // PN - Operator names
 )
 
 // X-Ray Acquisition Parameters
 var (
-	KVP           = Tag{0x0018, 0x0060} // Peak kilo voltage output of X-ray generator
-	ImageComments = Tag{0x0020, 0x4000} // User-defined comments about image
+	KVP           = Tag{0x0018, 0x0060} // DS - Peak kilo voltage output of X-ray generator
+	ImageComments = Tag{0x0020, 0x4000} // LT - User-defined comments about image
 )
 
 // SOP Common Module
 var (
-	SOPClassUID          = Tag{0x0008, 0x0016}
-	SOPInstanceUID       = Tag{0x0008, 0x0018}
-	InstanceCreationDate = Tag{0x0008, 0x0012}
-	InstanceCreationTime = Tag{0x0008, 0x0013}
+	SOPClassUID          = Tag{0x0008, 0x0016} // UI - SOP Class
+	SOPInstanceUID       = Tag{0x0008, 0x0018} // UI - SOP Instance
+	InstanceCreationDate = Tag{0x0008, 0x0012} // DA - Instance creation date
+	InstanceCreationTime = Tag{0x0008, 0x0013} // TM - Instance creation time
 )
 
 // Frame of Reference Module
 var (
-	FrameOfReferenceUID        = Tag{0x0020, 0x0052}
-	PositionReferenceIndicator = Tag{0x0020, 0x1040}
+	FrameOfReferenceUID        = Tag{0x0020, 0x0052} // UI - Spatial frame of reference
+	PositionReferenceIndicator = Tag{0x0020, 0x1040} // LO - Reference point of the frame of reference
 )
 
 // Image Pixel Module (Group 0028)
 var (
-	SamplesPerPixel           = Tag{0x0028, 0x0002}
-	PhotometricInterpretation = Tag{0x0028, 0x0004}
-	Rows                      = Tag{0x0028, 0x0010}
-	Columns                   = Tag{0x0028, 0x0011}
-	BitsAllocated             = Tag{0x0028, 0x0100}
-	BitsStored                = Tag{0x0028, 0x0101}
-	HighBit                   = Tag{0x0028, 0x0102}
-	PixelRepresentation       = Tag{0x0028, 0x0103}
-	PixelData                 = Tag{0x7FE0, 0x0010}
-	NumberOfFrames            = Tag{0x0028, 0x0008}
+	SamplesPerPixel           = Tag{0x0028, 0x0002} // US - 1 for grayscale, 3 for color
+	PhotometricInterpretation = Tag{0x0028, 0x0004} // CS - MONOCHROME2, RGB, ...
+	Rows                      = Tag{0x0028, 0x0010} // US - Image height
+	Columns                   = Tag{0x0028, 0x0011} // US - Image width
+	BitsAllocated             = Tag{0x0028, 0x0100} // US - Bits per sample in the pixel data
+	BitsStored                = Tag{0x0028, 0x0101} // US - Significant bits per sample
+	HighBit                   = Tag{0x0028, 0x0102} // US - Most significant bit, BitsStored - 1
+	PixelRepresentation       = Tag{0x0028, 0x0103} // US - 0=unsigned, 1=two's complement
+	PixelData                 = Tag{0x7FE0, 0x0010} // OB/OW - Pixel data
+	NumberOfFrames            = Tag{0x0028, 0x0008} // IS - Frames in the pixel data
+	FrameIncrementPointer     = Tag{0x0028, 0x0009} // AT - attribute that varies per frame
 )
 
 // CT Image Module
 var (
-	ImageType                    = Tag{0x0008, 0x0008}
-	RescaleIntercept             = Tag{0x0028, 0x1052}
-	RescaleSlope                 = Tag{0x0028, 0x1053}
-	RescaleType                  = Tag{0x0028, 0x1054}
-	WindowCenter                 = Tag{0x0028, 0x1050}
-	WindowWidth                  = Tag{0x0028, 0x1051}
+	ImageType                    = Tag{0x0008, 0x0008} // CS - ORIGINAL\PRIMARY, DERIVED\SECONDARY, ...
+	RescaleIntercept             = Tag{0x0028, 0x1052} // DS - Modality LUT intercept
+	RescaleSlope                 = Tag{0x0028, 0x1053} // DS - Modality LUT slope
+	RescaleType                  = Tag{0x0028, 0x1054} // LO - Rescaled units, e.g. HU
+	WindowCenter                 = Tag{0x0028, 0x1050} // DS - VOI window center
+	WindowWidth                  = Tag{0x0028, 0x1051} // DS - VOI window width
 	WindowCenterWidthExplanation = Tag{0x0028, 0x1055} // LO - Window explanation
 	VOILUTFunction               = Tag{0x0028, 0x1056} // CS - LINEAR, SIGMOID, LINEAR_EXACT
 )
 
 // Image Position/Orientation
 var (
-	ImagePositionPatient    = Tag{0x0020, 0x0032}
-	ImageOrientationPatient = Tag{0x0020, 0x0037}
-	SliceThickness          = Tag{0x0018, 0x0050}
-	SpacingBetweenSlices    = Tag{0x0018, 0x0088}
-	PixelSpacing            = Tag{0x0028, 0x0030}
-	SliceLocation           = Tag{0x0020, 0x1041}
+	ImagePositionPatient    = Tag{0x0020, 0x0032} // DS - Upper-left corner (x,y,z) in mm
+	ImageOrientationPatient = Tag{0x0020, 0x0037} // DS - Row and column direction cosines
+	SliceThickness          = Tag{0x0018, 0x0050} // DS - Slice thickness (mm)
+	SpacingBetweenSlices    = Tag{0x0018, 0x0088} // DS - Slice spacing (mm)
+	PixelSpacing            = Tag{0x0028, 0x0030} // DS - Row\column spacing (mm)
+	SliceLocation           = Tag{0x0020, 0x1041} // DS - Relative slice position (mm)
 )
 
 // Content Date/Time
 var (
-	ContentDate = Tag{0x0008, 0x0023}
-	ContentTime = Tag{0x0008, 0x0033}
+	ContentDate         = Tag{0x0008, 0x0023} // DA - Content date
+	ContentTime         = Tag{0x0008, 0x0033} // TM - Content time
+	AcquisitionDate     = Tag{0x0008, 0x0022} // DA - Acquisition date
+	AcquisitionTime     = Tag{0x0008, 0x0032} // TM - Acquisition time
+	AcquisitionDateTime = Tag{0x0008, 0x002A} // DT - Acquisition date and time
 )
 
 // Sequence delimiters
@@ -155,7 +187,9 @@ var (
 // DICOS-Specific Tags (Group 4010) - ATD/Threat Detection
 var (
 	OOIType                   = Tag{0x4010, 0x1012} // CS - Object of Interest type
-	OOISize                   = Tag{0x4010, 0x1024} // FL - Object size (mm)
+	OOISize                   = Tag{0x4010, 0x1013} // FL - Object extent X, Y, Z (mm)
+	OOIVolume                 = Tag{0x4010, 0x1019} // FL - Object volume (mm³)
+	OOIMass                   = Tag{0x4010, 0x101A} // FL - Object mass (g)
 	PTORepresentationSequence = Tag{0x4010, 0x1011} // SQ - PTO representations
 	ThreatROIType             = Tag{0x4010, 0x1009} // CS - ROI type
 	BoundingPolygon           = Tag{0x4010, 0x101D} // FL - Polygon coordinates
@@ -180,10 +214,11 @@ var (
 	OperatorAssessmentSequence = Tag{0x4010, 0x1029} // SQ - Operator assessment seq
 
 	// Reference Tags for TDR
-	ReferencedSOPClassUID    = Tag{0x0008, 0x1150} // UI - Referenced SOP Class
-	ReferencedSOPInstanceUID = Tag{0x0008, 0x1155} // UI - Referenced SOP Instance
-	ReferencedSeriesSequence = Tag{0x0008, 0x1115} // SQ - Referenced series
-	ReferencedImageSequence  = Tag{0x0008, 0x1140} // SQ - Referenced images
+	ReferencedSOPClassUID      = Tag{0x0008, 0x1150} // UI - Referenced SOP Class
+	ReferencedSOPInstanceUID   = Tag{0x0008, 0x1155} // UI - Referenced SOP Instance
+	ReferencedSeriesSequence   = Tag{0x0008, 0x1115} // SQ - Referenced series
+	ReferencedImageSequence    = Tag{0x0008, 0x1140} // SQ - Referenced images
+	ReferencedInstanceSequence = Tag{0x0008, 0x114A} // SQ - Referenced instances of a series
 
 	// Material Classification
 	OOIOwnerType                    = Tag{0x4010, 0x1018} // CS - Owner type
@@ -244,7 +279,7 @@ var (
 	DetectorTemperature           = Tag{0x0018, 0x7001} // DS - Temperature (deg C)
 	DetectorElementPhysicalSize   = Tag{0x0018, 0x7020} // DS - size (mm)
 	DetectorElementSpacing        = Tag{0x0018, 0x7022} // DS - spacing (mm)
-	DetectorActiveDimensions      = Tag{0x0018, 0x7026} // US - active rows/cols
+	DetectorActiveDimensions      = Tag{0x0018, 0x7026} // DS - active width/height (mm)
 	DetectorBinning               = Tag{0x0018, 0x701A} // DS - binning factor
 	FieldOfViewShape              = Tag{0x0018, 0x1147} // CS - RECTANGLE, ROUND, HEXAGONAL
 	FieldOfViewDimensions         = Tag{0x0018, 0x1149} // IS - FOV dimensions (mm)
@@ -270,28 +305,104 @@ var (
 	ImageAndFluoroscopyAreaDoseProduct = Tag{0x0018, 0x115E} // DS - DAP (dGy*cm2)
 )
 
+// Pixel Spacing Calibration Tags
+var (
+	ImagerPixelSpacing                       = Tag{0x0018, 0x1164} // DS - Detector plane spacing (mm)
+	EstimatedRadiographicMagnificationFactor = Tag{0x0018, 0x1114} // DS - Object-to-detector magnification
+	PixelSpacingCalibrationType              = Tag{0x0028, 0x0A02} // CS - GEOMETRY, FIDUCIAL
+	PixelSpacingCalibrationDescription       = Tag{0x0028, 0x0A04} // LO - Calibration description
+)
+
+// Multi-frame Functional Groups (Group 5200, 0020, 0018)
+var (
+	SharedFunctionalGroupsSequence   = Tag{0x5200, 0x9229} // SQ - Attributes shared by all frames
+	PerFrameFunctionalGroupsSequence = Tag{0x5200, 0x9230} // SQ - One item per frame
+	FrameContentSequence             = Tag{0x0020, 0x9111} // SQ - Frame Content functional group
+	PlanePositionSequence            = Tag{0x0020, 0x9113} // SQ - Plane Position (Patient) functional group
+	FrameAcquisitionDateTime         = Tag{0x0018, 0x9074} // DT - Acquisition time of the frame
+	FrameLabel                       = Tag{0x0020, 0x9453} // LO - Label of the frame, e.g. energy bin
+)
+
+// Patient De-identification (Group 0012)
+var (
+	PatientIdentityRemoved = Tag{0x0012, 0x0062} // CS - YES or NO
+	DeidentificationMethod = Tag{0x0012, 0x0063} // LO - Profile or method applied
+)
+
+// Attribute Confidentiality (Group 0400), PS3.15 Annex E
+var (
+	EncryptedAttributesSequence       = Tag{0x0400, 0x0500} // SQ - Encrypted copies of attributes
+	EncryptedContentTransferSyntaxUID = Tag{0x0400, 0x0510} // UI - Encoding of the encrypted dataset
+	EncryptedContent                  = Tag{0x0400, 0x0520} // OB - CMS EnvelopedData of the dataset
+	ModifiedAttributesSequence        = Tag{0x0400, 0x0550} // SQ - Original values of replaced attributes
+)
+
+// Surface Mesh Module (Group 0066), the surfaces of an AIT 3D image
+var (
+	NumberOfSurfaces               = Tag{0x0066, 0x0001} // UL - Items in SurfaceSequence
+	SurfaceSequence                = Tag{0x0066, 0x0002} // SQ - One item per surface
+	SurfaceNumber                  = Tag{0x0066, 0x0003} // UL - 1-based surface identifier
+	SurfaceComments                = Tag{0x0066, 0x0004} // LT - Free text
+	SurfaceProcessing              = Tag{0x0066, 0x0009} // CS - YES if reduced or smoothed
+	RecommendedPresentationOpacity = Tag{0x0066, 0x000C} // FL - 0 transparent to 1 opaque
+	RecommendedPresentationType    = Tag{0x0066, 0x000D} // CS - SURFACE, WIREFRAME or POINTS
+	FiniteVolume                   = Tag{0x0066, 0x000E} // CS - YES, NO or UNKNOWN
+	Manifold                       = Tag{0x0066, 0x0010} // CS - YES, NO or UNKNOWN
+	SurfacePointsSequence          = Tag{0x0066, 0x0011} // SQ - The vertices
+	SurfacePointsNormalsSequence   = Tag{0x0066, 0x0012} // SQ - One normal per vertex
+	SurfaceMeshPrimitivesSequence  = Tag{0x0066, 0x0013} // SQ - Triangles and vertices indexing the points
+	NumberOfSurfacePoints          = Tag{0x0066, 0x0015} // UL - Vertices in PointCoordinatesData
+	PointCoordinatesData           = Tag{0x0066, 0x0016} // OF - x, y, z per vertex in mm
+	PointsBoundingBoxCoordinates   = Tag{0x0066, 0x001A} // FL - Min x, y, z then max x, y, z
+	NumberOfVectors                = Tag{0x0066, 0x001E} // UL - Vectors in VectorCoordinateData
+	VectorDimensionality           = Tag{0x0066, 0x001F} // US - Components per vector, 3 for normals
+	VectorCoordinateData           = Tag{0x0066, 0x0021} // OF - The vector components
+	LongTrianglePointIndexList     = Tag{0x0066, 0x0041} // OL - Three 1-based point indices per triangle
+	LongVertexPointIndexList       = Tag{0x0066, 0x0043} // OL - 1-based indices of lone points
+)
+
 // DICOS General Series Energy Tags (Group 6100)
 var (
 	SeriesEnergy            = Tag{0x6100, 0x0030} // US - Energy level (1=LE, 2=HE)
 	SeriesEnergyDescription = Tag{0x6100, 0x0031} // LO - Energy description string
 )
 
+// SC Equipment and Image Modules (Group 0008, 0028)
+var (
+	ConversionType        = Tag{0x0008, 0x0064} // CS - WSD, SI, DV, ...
+	DerivationDescription = Tag{0x0008, 0x2111} // ST - How the image was derived
+	SourceImageSequence   = Tag{0x0008, 0x2112} // SQ - Instances the image was derived from
+	ICCProfile            = Tag{0x0028, 0x2000} // OB - ICC color profile of the pixel data
+)
+
+// Encapsulated Document Module (Group 0042, 0040)
+var (
+	DocumentTitle                  = Tag{0x0042, 0x0010} // ST - Title of the document
+	EncapsulatedDocument           = Tag{0x0042, 0x0011} // OB - The document, padded to even length
+	MIMETypeOfEncapsulatedDocument = Tag{0x0042, 0x0012} // LO - e.g. application/pdf
+	SourceInstanceSequence         = Tag{0x0042, 0x0013} // SQ - Instances the document was made from
+	EncapsulatedDocumentLength     = Tag{0x0042, 0x0015} // UL - Length of the document without padding
+	ConceptNameCodeSequence        = Tag{0x0040, 0xA043} // SQ - Coded document title
+)
+
 // Extended Image Pixel Module (Group 0028)
 var (
-	PlanarConfiguration        = Tag{0x0028, 0x0006} // US - 0=color-by-pixel, 1=color-by-plane
-	SmallestImagePixelValue    = Tag{0x0028, 0x0106} // US/SS - Min pixel value
-	LargestImagePixelValue     = Tag{0x0028, 0x0107} // US/SS - Max pixel value
-	PixelPaddingValue          = Tag{0x0028, 0x0120} // US/SS - Padding value
-	PixelPaddingRangeLimit     = Tag{0x0028, 0x0121} // US/SS - Padding range limit
-	LossyImageCompression      = Tag{0x0028, 0x2110} // CS - 00=lossless, 01=lossy
-	LossyImageCompressionRatio = Tag{0x0028, 0x2112} // DS - Compression ratio
-	LUTDescriptor              = Tag{0x0028, 0x3002} // US - LUT descriptor
-	LUTData                    = Tag{0x0028, 0x3006} // US/OW - LUT data
-	VOILUTSequence             = Tag{0x0028, 0x3010} // SQ - VOI LUT sequence
-	ModalityLUTSequence        = Tag{0x0028, 0x3000} // SQ - Modality LUT sequence
-	RedPaletteColorLUTData     = Tag{0x0028, 0x1201} // OW - Red palette
-	GreenPaletteColorLUTData   = Tag{0x0028, 0x1202} // OW - Green palette
-	BluePaletteColorLUTData    = Tag{0x0028, 0x1203} // OW - Blue palette
+	PlanarConfiguration         = Tag{0x0028, 0x0006} // US - 0=color-by-pixel, 1=color-by-plane
+	SmallestImagePixelValue     = Tag{0x0028, 0x0106} // US/SS - Min pixel value
+	LargestImagePixelValue      = Tag{0x0028, 0x0107} // US/SS - Max pixel value
+	PixelPaddingValue           = Tag{0x0028, 0x0120} // US/SS - Padding value
+	PixelPaddingRangeLimit      = Tag{0x0028, 0x0121} // US/SS - Padding range limit
+	BurnedInAnnotation          = Tag{0x0028, 0x0301} // CS - YES/NO identifying text in the pixels
+	LossyImageCompression       = Tag{0x0028, 0x2110} // CS - 00=lossless, 01=lossy
+	LossyImageCompressionRatio  = Tag{0x0028, 0x2112} // DS - Compression ratio
+	LossyImageCompressionMethod = Tag{0x0028, 0x2114} // CS - e.g. ISO_10918_1 for JPEG
+	LUTDescriptor               = Tag{0x0028, 0x3002} // US - LUT descriptor
+	LUTData                     = Tag{0x0028, 0x3006} // US/OW - LUT data
+	VOILUTSequence              = Tag{0x0028, 0x3010} // SQ - VOI LUT sequence
+	ModalityLUTSequence         = Tag{0x0028, 0x3000} // SQ - Modality LUT sequence
+	RedPaletteColorLUTData      = Tag{0x0028, 0x1201} // OW - Red palette
+	GreenPaletteColorLUTData    = Tag{0x0028, 0x1202} // OW - Green palette
+	BluePaletteColorLUTData     = Tag{0x0028, 0x1203} // OW - Blue palette
 )
 
 // CT Acquisition Parameters (Group 0018)
@@ -321,6 +432,41 @@ var (
 	TubeAngle              = Tag{0x0018, 0x9303} // FD - Tube angle (degrees)
 )
 
+// Quadrupole Resonance Acquisition (Group 0018, 5600), shared with MR spectroscopy
+var (
+	ResonantNucleus      = Tag{0x0018, 0x9100} // CS - Nucleus excited, e.g. 14N
+	TransmitterFrequency = Tag{0x0018, 0x9098} // FD - Excitation frequency (MHz)
+	SpectralWidth        = Tag{0x0018, 0x9052} // FD - Receiver bandwidth (Hz)
+	AcquisitionDuration  = Tag{0x0018, 0x9073} // FD - Acquisition duration (s)
+	NumberOfAverages     = Tag{0x0018, 0x0083} // DS - Acquisitions averaged
+	SpectroscopyData     = Tag{0x5600, 0x0020} // OF - Measured spectrum samples
+)
+
+// Query/Retrieve Identifier (PS3.4 C.4)
+var (
+	QueryRetrieveLevel             = Tag{0x0008, 0x0052} // CS - PATIENT, STUDY, SERIES or IMAGE
+	RetrieveAETitle                = Tag{0x0008, 0x0054} // AE - AE titles the match can be retrieved from
+	ModalitiesInStudy              = Tag{0x0008, 0x0061} // CS - Modalities of the series in a study
+	NumberOfStudyRelatedSeries     = Tag{0x0020, 0x1206} // IS - Series in a study
+	NumberOfStudyRelatedInstances  = Tag{0x0020, 0x1208} // IS - Instances in a study
+	NumberOfSeriesRelatedInstances = Tag{0x0020, 0x1209} // IS - Instances in a series
+)
+
+// Integrity Attestation Private Tags (Group 0011), reserved by IntegrityCreator
+var (
+	IntegrityCreator   = Tag{0x0011, 0x0010} // LO - Private creator "DICOS.GO INTEGRITY"
+	IntegrityAlgorithm = Tag{0x0011, 0x1010} // CS - Hash algorithm, e.g. SHA256
+	IntegrityTags      = Tag{0x0011, 0x1011} // AT - Attributes covered by the hash
+	IntegrityDigest    = Tag{0x0011, 0x1012} // OB - Hash over the covered attributes
+)
+
+// Codec Decision Private Tags (Group 0011), reserved by CodecDecisionCreator
+var (
+	CodecDecisionCreator = Tag{0x0011, 0x0011} // LO - Private creator "DICOS.GO CODEC"
+	CodecDecisionName    = Tag{0x0011, 0x1110} // LO - Name of the chosen codec, e.g. jpeg-ls
+	CodecDecisionRecord  = Tag{0x0011, 0x1111} // UT - Decision statistics and trial ratios in JSON
+)
+
 // LookupName returns a human-readable name for common tags
 func (t Tag) LookupName() string {
 	switch t {
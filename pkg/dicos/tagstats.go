@@ -0,0 +1,165 @@
+package dicos
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/dict"
+)
+
+// maxExampleLength bounds the length of an example value in a TagStat
+const maxExampleLength = 64
+
+// TagStat counts one attribute across the datasets added to a TagInventory.
+// Private elements are told apart by the creator reserving their block, so
+// the same tag written by two vendors gives two stats.
+type TagStat struct {
+	Tag         Tag      `json:"tag"`
+	Keyword     string   `json:"keyword,omitempty"` // dictionary keyword, empty when unknown
+	Creator     string   `json:"creator,omitempty"` // private creator of a private element
+	VRs         []string `json:"vrs"`               // VRs seen, in order of appearance
+	Datasets    int      `json:"datasets"`          // datasets holding it at any depth
+	Occurrences int      `json:"occurrences"`       // including every sequence item
+	Examples    []string `json:"examples,omitempty"`
+}
+
+// Known reports whether the data dictionary describes the attribute
+func (s TagStat) Known() bool {
+	return s.Keyword != ""
+}
+
+// tagStatKey tells private elements of different creators apart
+type tagStatKey struct {
+	tag     Tag
+	creator string
+}
+
+// TagInventory tallies which attributes appear across a corpus of datasets,
+// standard and private, with up to MaxExamples distinct example values each.
+// The zero value keeps no examples.
+//
+// Example:
+//
+//	inv := &dicos.TagInventory{MaxExamples: 3}
+//	for _, path := range files {
+//		ds, err := dicos.ReadFile(path)
+//		...
+//		inv.Add(ds)
+//	}
+//	for _, s := range inv.Private() {
+//		fmt.Println(s.Tag, s.Creator, s.Datasets, s.Examples)
+//	}
+type TagInventory struct {
+	MaxExamples int
+	Datasets    int // datasets added
+
+	stats map[tagStatKey]*TagStat
+}
+
+// Add counts the elements of ds, including those inside sequence items
+func (inv *TagInventory) Add(ds *Dataset) {
+	if inv.stats == nil {
+		inv.stats = make(map[tagStatKey]*TagStat)
+	}
+	inv.Datasets++
+	seen := make(map[tagStatKey]bool)
+	inv.add(ds, seen)
+}
+
+func (inv *TagInventory) add(ds *Dataset, seen map[tagStatKey]bool) {
+	for t, elem := range ds.Elements {
+		key := tagStatKey{tag: t, creator: privateCreator(ds, t)}
+		s, ok := inv.stats[key]
+		if !ok {
+			s = &TagStat{Tag: t, Creator: key.creator}
+			if e, ok := dict.Lookup(t); ok {
+				s.Keyword = e.Keyword
+			}
+			inv.stats[key] = s
+		}
+		s.Occurrences++
+		if !seen[key] {
+			seen[key] = true
+			s.Datasets++
+		}
+		if !slices.Contains(s.VRs, elem.VR) {
+			s.VRs = append(s.VRs, elem.VR)
+		}
+		if len(s.Examples) < inv.MaxExamples {
+			if v := valueString(elem); v != "" && !slices.Contains(s.Examples, v) {
+				s.Examples = append(s.Examples, v)
+			}
+		}
+		if items, ok := elem.Value.([]*Dataset); ok {
+			for _, item := range items {
+				if item != nil {
+					inv.add(item, seen)
+				}
+			}
+		}
+	}
+}
+
+// Stats returns every attribute seen, ordered by tag and then creator
+func (inv *TagInventory) Stats() []TagStat {
+	out := make([]TagStat, 0, len(inv.stats))
+	for _, s := range inv.stats {
+		out = append(out, *s)
+	}
+	slices.SortFunc(out, func(a, b TagStat) int {
+		if a.Tag != b.Tag {
+			if a.Tag.Less(b.Tag) {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Creator, b.Creator)
+	})
+	return out
+}
+
+// Private returns the private data elements seen, without their creator
+// elements, ordered by tag and then creator
+func (inv *TagInventory) Private() []TagStat {
+	out := []TagStat{}
+	for _, s := range inv.Stats() {
+		if s.Tag.IsPrivate() && s.Tag.Element > 0x00FF {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// privateCreator returns the creator reserving the block of a private data
+// element (gggg,xxyy), read from (gggg,00xx), or "" for other elements
+func privateCreator(ds *Dataset, t Tag) string {
+	if !t.IsPrivate() || t.Element <= 0x00FF {
+		return ""
+	}
+	if elem, ok := ds.Elements[Tag{Group: t.Group, Element: t.Element >> 8}]; ok {
+		if s, ok := elem.GetString(); ok {
+			return strings.TrimSpace(s)
+		}
+	}
+	return ""
+}
+
+// valueString returns a short, printable rendering of an element value
+func valueString(e *Element) string {
+	var s string
+	switch v := e.Value.(type) {
+	case nil:
+		return ""
+	case []*Dataset:
+		s = fmt.Sprintf("%d items", len(v))
+	case string:
+		s = strings.TrimRight(v, " \x00")
+	default:
+		s = formatValue(v)
+	}
+	if len(s) > maxExampleLength {
+		s = s[:maxExampleLength-3] + "..."
+	}
+	return s
+}
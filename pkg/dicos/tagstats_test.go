@@ -0,0 +1,83 @@
+package dicos
+
+import (
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagInventory(t *testing.T) {
+	vendor := func(creator, value string) *Dataset {
+		ref, err := NewDataset(WithElement(tag.ReferencedSOPInstanceUID, "1.2.3.9"))
+		require.NoError(t, err)
+		ds, err := NewDataset(
+			WithElement(tag.Modality, "CT"),
+			withVR(tag.New(0x0009, 0x0010), "LO", creator),
+			withVR(tag.New(0x0009, 0x1001), "LO", value),
+			WithSequence(tag.ReferencedImageSequence, ref, ref),
+		)
+		require.NoError(t, err)
+		return ds
+	}
+
+	inv := &TagInventory{MaxExamples: 2}
+	inv.Add(vendor("ACME 1.0", "first"))
+	inv.Add(vendor("ACME 1.0", "second"))
+	inv.Add(vendor("ACME 1.0", "third"))
+	inv.Add(vendor("OTHER", "x"))
+	assert.Equal(t, 4, inv.Datasets)
+
+	stats := inv.Stats()
+	byKey := make(map[tagStatKey]TagStat)
+	for i, s := range stats {
+		byKey[tagStatKey{tag: s.Tag, creator: s.Creator}] = s
+		if i > 0 {
+			assert.False(t, s.Tag.Less(stats[i-1].Tag), "stats out of order at %s", s.Tag)
+		}
+	}
+
+	modality := byKey[tagStatKey{tag: tag.Modality}]
+	assert.Equal(t, "Modality", modality.Keyword)
+	assert.True(t, modality.Known())
+	assert.Equal(t, 4, modality.Datasets)
+	assert.Equal(t, []string{"CT"}, modality.Examples)
+
+	// counted once per dataset but at every sequence item
+	ref := byKey[tagStatKey{tag: tag.ReferencedSOPInstanceUID}]
+	assert.Equal(t, 4, ref.Datasets)
+	assert.Equal(t, 8, ref.Occurrences)
+	assert.Equal(t, []string{"2 items"}, byKey[tagStatKey{tag: tag.ReferencedImageSequence}].Examples)
+
+	private := inv.Private()
+	require.Len(t, private, 2)
+	assert.Equal(t, "ACME 1.0", private[0].Creator)
+	assert.Equal(t, 3, private[0].Datasets)
+	assert.Equal(t, []string{"first", "second"}, private[0].Examples)
+	assert.Equal(t, []string{"LO"}, private[0].VRs)
+	assert.False(t, private[0].Known())
+	assert.Equal(t, "OTHER", private[1].Creator)
+	assert.Equal(t, 1, private[1].Datasets)
+}
+
+func TestValueString(t *testing.T) {
+	long := make([]byte, 100)
+	for i := range long {
+		long[i] = 'a'
+	}
+	for name, tc := range map[string]struct {
+		value any
+		want  string
+	}{
+		"padded": {value: "CT ", want: "CT"},
+		"binary": {value: make([]byte, 32), want: "Binary Data (32 bytes)"},
+		"long":   {value: string(long), want: string(long[:61]) + "..."},
+		"nil":    {value: nil, want: ""},
+		"number": {value: []uint16{1, 2}, want: "[1 2]"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, valueString(&Element{Value: tc.value}))
+		})
+	}
+}
@@ -1,8 +1,11 @@
 package dicos
 
 import (
+	"fmt"
 	"io"
 	"os"
+	"slices"
+	"strconv"
 	"time"
 
 	"github.com/jpfielding/dicos.go/pkg/dicos/module"
@@ -20,18 +23,38 @@ type ThreatDetectionReport struct {
 	Equipment module.GeneralEquipmentModule
 	SOPCommon module.SOPCommonModule
 
+	// Object of inspection: the bag, its owner and its routing; nil writes none
+	OOI       *module.OOIModule
+	OOIOwner  *module.OOIOwnerModule
+	Itinerary *module.ItineraryModule
+
 	// TDR Specifics
 	ContentDate   module.Date
 	ContentTime   module.Time
 	AlarmDecision string // "ALARM", "NO_ALARM", "UNKNOWN"
+	AbortReason   string // why the ATD did not complete, see SetAbort
+
+	// Review of the report by an operator, see SetOperatorDecision
+	Operator *OperatorAssessment
 
 	// Referenced Images (source CT/DX that spawned this TDR)
 	ReferencedSOPClassUID    string
 	ReferencedSOPInstanceUID string
+	ExtraReferences          []SOPReference // further referenced images, e.g. the other energy
+
+	// Series of the referenced images and their shared frame of reference,
+	// see LinkTDR
+	ReferencedSeries    []SeriesReference
+	FrameOfReferenceUID string
 
 	// PTOs
 	PTOs []PotentialThreatObject
 
+	// Spacing of the referenced image; when set, PTO Size and Volume left
+	// zero are written as estimated from their geometry (see
+	// EstimateOOISizes). The PTOs themselves are not changed.
+	Spacing *VoxelSpacing
+
 	// Configuration
 	Codec Codec // nil = uncompressed
 }
@@ -47,12 +70,47 @@ type PotentialThreatObject struct {
 	Confidence  float32 // ThreatConfidenceScore (0.0-1.0)
 
 	// Material Classification
-	OOIType string  // Object of Interest type: FIREARM, KNIFE, EXPLOSIVE, etc.
-	Mass    float32 // Estimated mass (grams)
-	Volume  float32 // Estimated volume (mm³)
+	OOIType string     // Object of Interest type: FIREARM, KNIFE, EXPLOSIVE, etc.
+	Mass    float32    // Estimated mass (grams)
+	Volume  float32    // Estimated volume (mm³)
+	Size    [3]float32 // Physical extent X, Y, Z (mm)
 
 	// Spatial
-	BoundingBox *BoundingBox // Optional 3D bounding box
+	BoundingBox *BoundingBox // Optional 3D bounding box (column, row, frame)
+	Polygon     [][2]float32 // Optional in-plane outline (column, row)
+
+	// Per-algorithm results, written as the ATD Assessment Sequence
+	Assessments []ATDAssessment
+}
+
+// ATDAssessment is one item of a PTO's ATD Assessment Sequence
+type ATDAssessment struct {
+	Category    string  // ThreatCategoryDescription
+	Ability     string  // ATDAbility
+	Probability float32 // ATDAssessmentProbability (0.0-1.0)
+	Confidence  float32 // ThreatConfidenceScore (0.0-1.0)
+}
+
+// OperatorAssessment is an operator's review of a TDR, written as the
+// Operator Assessment Sequence
+type OperatorAssessment struct {
+	Operator string      // OperatorsName
+	Decision string      // AlarmDecision: "ALARM", "NO_ALARM", "UNKNOWN"
+	Category string      // ThreatCategoryDescription of the threat found, if any
+	Date     module.Date // ContentDate of the decision
+	Time     module.Time // ContentTime of the decision
+}
+
+// SOPReference identifies a referenced instance
+type SOPReference struct {
+	SOPClassUID    string
+	SOPInstanceUID string
+}
+
+// SeriesReference identifies the referenced instances of one series
+type SeriesReference struct {
+	SeriesInstanceUID string
+	Instances         []SOPReference
 }
 
 type BoundingBox struct {
@@ -62,11 +120,111 @@ type BoundingBox struct {
 
 func NewThreatDetectionReport() *ThreatDetectionReport {
 	t := time.Now()
-	return &ThreatDetectionReport{
+	tdr := &ThreatDetectionReport{
 		ContentDate: module.NewDate(t),
 		ContentTime: module.NewTime(t),
 		PTOs:        make([]PotentialThreatObject, 0),
 	}
+	fillUIDs(&tdr.Series.SeriesInstanceUID, &tdr.SOPCommon.SOPInstanceUID)
+	return tdr
+}
+
+// alarmDecisions are the values of AlarmDecision
+var alarmDecisions = []string{"ALARM", "NO_ALARM", "UNKNOWN"}
+
+// AddAssessment appends a to the ATD Assessment Sequence of the PTO with ID
+// ptoID. A PTO with ID 0 is matched by its position, counting from 1, as it
+// is numbered when written.
+func (tdr *ThreatDetectionReport) AddAssessment(ptoID int, a ATDAssessment) error {
+	for i := range tdr.PTOs {
+		pto := &tdr.PTOs[i]
+		if pto.ID == ptoID || (pto.ID == 0 && i+1 == ptoID) {
+			pto.Assessments = append(pto.Assessments, a)
+			return nil
+		}
+	}
+	return fmt.Errorf("no PTO with ID %d", ptoID)
+}
+
+// SetOperatorDecision records the review of the report by an operator. A
+// zero Date sets the Date and Time to now.
+func (tdr *ThreatDetectionReport) SetOperatorDecision(op OperatorAssessment) error {
+	if !slices.Contains(alarmDecisions, op.Decision) {
+		return fmt.Errorf("operator decision %q is not one of %v", op.Decision, alarmDecisions)
+	}
+	if op.Date.IsZero() {
+		now := time.Now()
+		op.Date, op.Time = module.NewDate(now), module.NewTime(now)
+	}
+	tdr.Operator = &op
+	return nil
+}
+
+// SetAbort records that the ATD did not complete, e.g. with reason
+// "OVERSIZE" or "INCOMPLETE_SCAN", and sets the AlarmDecision to UNKNOWN
+func (tdr *ThreatDetectionReport) SetAbort(reason string) {
+	tdr.AbortReason = reason
+	tdr.AlarmDecision = "UNKNOWN"
+}
+
+// LinkTDR references images, the scans tdr reports on, from tdr. The first
+// image becomes ReferencedSOPClassUID and ReferencedSOPInstanceUID, the rest
+// ExtraReferences, and ReferencedSeries groups them all by series, so the
+// Referenced Image and Referenced Series Sequences agree. The references tdr
+// had are replaced. Images with a FrameOfReferenceUID must share it, and it
+// becomes the TDR's. tdr is unchanged when an error is returned.
+//
+// Example:
+//
+//	ct, _ := dicos.ReadFile("scan.dcs")
+//	tdr := dicos.NewThreatDetectionReport()
+//	if err := dicos.LinkTDR(tdr, ct); err != nil {
+//		return err
+//	}
+func LinkTDR(tdr *ThreatDetectionReport, images ...*Dataset) error {
+	if len(images) == 0 {
+		return fmt.Errorf("no images to link")
+	}
+	refs := make([]SOPReference, 0, len(images))
+	var series []SeriesReference
+	var frameOfRef string
+	for i, img := range images {
+		src, err := sourceInstance(img)
+		if err != nil {
+			return fmt.Errorf("image %d: %w", i, err)
+		}
+		ref := SOPReference{SOPClassUID: src.SOPClassUID, SOPInstanceUID: src.SOPInstanceUID}
+		if slices.Contains(refs, ref) {
+			return fmt.Errorf("image %d: instance %s is already linked", i, ref.SOPInstanceUID)
+		}
+		seriesUID := attrString(img, tag.SeriesInstanceUID)
+		if seriesUID == "" {
+			return fmt.Errorf("image %d has no Series Instance UID", i)
+		}
+		if got := attrString(img, tag.FrameOfReferenceUID); got != "" {
+			if frameOfRef != "" && got != frameOfRef {
+				return fmt.Errorf("image %d frame of reference %q does not match %q", i, got, frameOfRef)
+			}
+			frameOfRef = got
+		}
+
+		refs = append(refs, ref)
+		j := slices.IndexFunc(series, func(s SeriesReference) bool { return s.SeriesInstanceUID == seriesUID })
+		if j < 0 {
+			j = len(series)
+			series = append(series, SeriesReference{SeriesInstanceUID: seriesUID})
+		}
+		series[j].Instances = append(series[j].Instances, ref)
+	}
+
+	tdr.ReferencedSOPClassUID, tdr.ReferencedSOPInstanceUID = refs[0].SOPClassUID, refs[0].SOPInstanceUID
+	tdr.ExtraReferences = refs[1:]
+	if len(tdr.ExtraReferences) == 0 {
+		tdr.ExtraReferences = nil
+	}
+	tdr.ReferencedSeries = series
+	tdr.FrameOfReferenceUID = frameOfRef
+	return nil
 }
 
 // GetDataset builds and returns the DICOS Dataset
@@ -75,7 +233,7 @@ func (tdr *ThreatDetectionReport) GetDataset() (*Dataset, error) {
 
 	sopInstanceUID := tdr.SOPCommon.SOPInstanceUID
 	if sopInstanceUID == "" {
-		sopInstanceUID = GenerateUID("1.2.826.0.1.3680043.8.498.")
+		sopInstanceUID = NewUID()
 		tdr.SOPCommon.SOPInstanceUID = sopInstanceUID
 	}
 	tdr.SOPCommon.SOPClassUID = DICOSTDRStorageUID
@@ -96,6 +254,7 @@ func (tdr *ThreatDetectionReport) GetDataset() (*Dataset, error) {
 		WithModule(tdr.Equipment.ToTags()),
 		WithModule(tdr.SOPCommon.ToTags()),
 	)
+	opts = append(opts, ooiOptions(tdr.OOI, tdr.OOIOwner, tdr.Itinerary)...)
 
 	// Content Date/Time
 	opts = append(opts,
@@ -103,10 +262,36 @@ func (tdr *ThreatDetectionReport) GetDataset() (*Dataset, error) {
 		WithElement(tag.ContentTime, tdr.ContentTime.String()),
 	)
 
-	// Alarm Decision
+	// Alarm Decision, with the number of PTOs that raised it
 	if tdr.AlarmDecision != "" {
 		opts = append(opts, WithElement(tag.AlarmDecision, tdr.AlarmDecision))
 	}
+	if tdr.AlarmDecision != "" || len(tdr.PTOs) > 0 {
+		opts = append(opts, WithElement(tag.NumberOfAlarmObjects, uint16(len(tdr.PTOs))))
+	}
+	if tdr.AbortReason != "" {
+		opts = append(opts, WithElement(tag.AbortReason, tdr.AbortReason))
+	}
+	if op := tdr.Operator; op != nil {
+		opOpts := []Option{WithElement(tag.AlarmDecision, op.Decision)}
+		if op.Operator != "" {
+			opOpts = append(opOpts, WithElement(tag.OperatorsName, op.Operator))
+		}
+		if op.Category != "" {
+			opOpts = append(opOpts, WithElement(tag.ThreatCategoryDescription, op.Category))
+		}
+		if !op.Date.IsZero() {
+			opOpts = append(opOpts,
+				WithElement(tag.ContentDate, op.Date.String()),
+				WithElement(tag.ContentTime, op.Time.String()),
+			)
+		}
+		opDS, err := NewDataset(opOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("operator assessment: %w", err)
+		}
+		opts = append(opts, WithSequence(tag.OperatorAssessmentSequence, opDS))
+	}
 
 	// Referenced Image Sequence (link to source CT/DX)
 	if tdr.ReferencedSOPInstanceUID != "" {
@@ -115,9 +300,49 @@ func (tdr *ThreatDetectionReport) GetDataset() (*Dataset, error) {
 			refOpts = append(refOpts, WithElement(tag.ReferencedSOPClassUID, tdr.ReferencedSOPClassUID))
 		}
 		refOpts = append(refOpts, WithElement(tag.ReferencedSOPInstanceUID, tdr.ReferencedSOPInstanceUID))
+		refs := make([]*Dataset, 0, 1+len(tdr.ExtraReferences))
 		if refDS, err := NewDataset(refOpts...); err == nil {
-			opts = append(opts, WithSequence(tag.ReferencedImageSequence, refDS))
+			refs = append(refs, refDS)
+		}
+		for _, ref := range tdr.ExtraReferences {
+			if refDS, err := NewDataset(
+				WithElement(tag.ReferencedSOPClassUID, ref.SOPClassUID),
+				WithElement(tag.ReferencedSOPInstanceUID, ref.SOPInstanceUID),
+			); err == nil {
+				refs = append(refs, refDS)
+			}
+		}
+		opts = append(opts, WithSequence(tag.ReferencedImageSequence, refs...))
+	}
+
+	// Referenced Series Sequence, each series with its referenced instances
+	if len(tdr.ReferencedSeries) > 0 {
+		items := make([]*Dataset, 0, len(tdr.ReferencedSeries))
+		for _, s := range tdr.ReferencedSeries {
+			instances := make([]*Dataset, 0, len(s.Instances))
+			for _, ref := range s.Instances {
+				refDS, err := NewDataset(
+					WithElement(tag.ReferencedSOPClassUID, ref.SOPClassUID),
+					WithElement(tag.ReferencedSOPInstanceUID, ref.SOPInstanceUID),
+				)
+				if err != nil {
+					return nil, err
+				}
+				instances = append(instances, refDS)
+			}
+			item, err := NewDataset(
+				WithElement(tag.SeriesInstanceUID, s.SeriesInstanceUID),
+				WithSequence(tag.ReferencedInstanceSequence, instances...),
+			)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
 		}
+		opts = append(opts, WithSequence(tag.ReferencedSeriesSequence, items...))
+	}
+	if tdr.FrameOfReferenceUID != "" {
+		opts = append(opts, WithElement(tag.FrameOfReferenceUID, tdr.FrameOfReferenceUID))
 	}
 
 	// PTO Sequence
@@ -129,7 +354,7 @@ func (tdr *ThreatDetectionReport) GetDataset() (*Dataset, error) {
 				id = i + 1
 			}
 
-			itemOpts := []Option{WithElement(tag.PotentialThreatObjectID, id)}
+			itemOpts := []Option{WithElement(tag.PotentialThreatObjectID, strconv.Itoa(id))}
 			if pto.Label != "" {
 				itemOpts = append(itemOpts, WithElement(tag.ThreatCategoryDescription, pto.Label))
 			}
@@ -142,9 +367,43 @@ func (tdr *ThreatDetectionReport) GetDataset() (*Dataset, error) {
 			if pto.Confidence > 0 {
 				itemOpts = append(itemOpts, WithElement(tag.ThreatConfidenceScore, pto.Confidence))
 			}
+			size, volume := pto.Size, pto.Volume
+			if tdr.Spacing != nil {
+				size, volume = ooiSize(&pto, *tdr.Spacing)
+			}
+			if size != ([3]float32{}) {
+				itemOpts = append(itemOpts, WithElement(tag.OOISize, size[:]))
+			}
+			if volume > 0 {
+				itemOpts = append(itemOpts, WithElement(tag.OOIVolume, volume))
+			}
+			if pto.Mass > 0 {
+				itemOpts = append(itemOpts, WithElement(tag.OOIMass, pto.Mass))
+			}
+
+			if len(pto.Assessments) > 0 {
+				items := make([]*Dataset, 0, len(pto.Assessments))
+				for _, a := range pto.Assessments {
+					aOpts := make([]Option, 0, 4)
+					if a.Category != "" {
+						aOpts = append(aOpts, WithElement(tag.ThreatCategoryDescription, a.Category))
+					}
+					if a.Ability != "" {
+						aOpts = append(aOpts, WithElement(tag.ATDAbility, a.Ability))
+					}
+					aOpts = append(aOpts,
+						WithElement(tag.ATDAssessmentProbability, a.Probability),
+						WithElement(tag.ThreatConfidenceScore, a.Confidence),
+					)
+					if aDS, err := NewDataset(aOpts...); err == nil {
+						items = append(items, aDS)
+					}
+				}
+				itemOpts = append(itemOpts, WithSequence(tag.ATDAssessmentSequence, items...))
+			}
 
-			// PTO Representation Sequence (bounding box, mass, volume)
-			if pto.BoundingBox != nil || pto.Mass > 0 || pto.Volume > 0 {
+			// PTO Representation Sequence (bounding box, polygon)
+			if pto.BoundingBox != nil || len(pto.Polygon) > 0 {
 				repOpts := make([]Option, 0, 4)
 				if pto.BoundingBox != nil {
 					repOpts = append(repOpts,
@@ -158,8 +417,17 @@ func (tdr *ThreatDetectionReport) GetDataset() (*Dataset, error) {
 							pto.BoundingBox.BottomRight[2]}),
 					)
 				}
-				if pto.Mass > 0 {
-					repOpts = append(repOpts, WithElement(tag.OOISize, pto.Mass))
+				if len(pto.Polygon) > 0 {
+					// (column, row, frame) triplets, on the first frame of the box
+					var frame float32
+					if pto.BoundingBox != nil {
+						frame = pto.BoundingBox.TopLeft[2]
+					}
+					poly := make([]float32, 0, len(pto.Polygon)*3)
+					for _, p := range pto.Polygon {
+						poly = append(poly, p[0], p[1], frame)
+					}
+					repOpts = append(repOpts, WithElement(tag.BoundingPolygon, poly))
 				}
 				if repDS, err := NewDataset(repOpts...); err == nil {
 					itemOpts = append(itemOpts, WithSequence(tag.PTORepresentationSequence, repDS))
@@ -194,3 +462,154 @@ func (tdr *ThreatDetectionReport) Write(path string) (int64, error) {
 	defer f.Close()
 	return tdr.WriteTo(f)
 }
+
+// ParseTDR maps a TDR dataset, e.g. one read with ReadFile, back to a
+// ThreatDetectionReport: the patient, series, equipment, SOP common and OOI
+// modules, the alarm decision and abort reason, the operator assessment, the
+// referenced images, series and frame of reference and each PTO with its
+// bounding box, polygon and ATD assessments. A PTO without its own label,
+// probability or confidence takes them from its first assessment. PTO IDs
+// that are not numbers are left 0.
+func ParseTDR(ds *Dataset) (*ThreatDetectionReport, error) {
+	if ds == nil {
+		return nil, fmt.Errorf("dicos: ParseTDR: nil dataset")
+	}
+	if !IsTDR(ds) {
+		return nil, fmt.Errorf("dicos: ParseTDR: SOP class %q is not a TDR", attrString(ds, tag.SOPClassUID))
+	}
+
+	tdr := &ThreatDetectionReport{
+		Patient:       readPatientModule(ds),
+		Series:        readSeriesModule(ds),
+		Equipment:     readEquipmentModule(ds),
+		SOPCommon:     readSOPCommonModule(ds),
+		OOI:           readOOIModule(ds),
+		OOIOwner:      readOOIOwnerModule(ds),
+		Itinerary:     readItineraryModule(ds),
+		AlarmDecision: attrString(ds, tag.AlarmDecision),
+		AbortReason:   attrString(ds, tag.AbortReason),
+
+		FrameOfReferenceUID: attrString(ds, tag.FrameOfReferenceUID),
+	}
+	tdr.ContentDate, _ = module.ParseDate(attrString(ds, tag.ContentDate))
+	tdr.ContentTime, _ = module.ParseTime(attrString(ds, tag.ContentTime))
+
+	for i, ref := range GetSequenceItems(ds, tag.ReferencedImageSequence) {
+		r := SOPReference{
+			SOPClassUID:    attrString(ref, tag.ReferencedSOPClassUID),
+			SOPInstanceUID: attrString(ref, tag.ReferencedSOPInstanceUID),
+		}
+		if i == 0 {
+			tdr.ReferencedSOPClassUID, tdr.ReferencedSOPInstanceUID = r.SOPClassUID, r.SOPInstanceUID
+		} else {
+			tdr.ExtraReferences = append(tdr.ExtraReferences, r)
+		}
+	}
+
+	for _, item := range GetSequenceItems(ds, tag.ReferencedSeriesSequence) {
+		s := SeriesReference{SeriesInstanceUID: attrString(item, tag.SeriesInstanceUID)}
+		for _, ref := range GetSequenceItems(item, tag.ReferencedInstanceSequence) {
+			s.Instances = append(s.Instances, SOPReference{
+				SOPClassUID:    attrString(ref, tag.ReferencedSOPClassUID),
+				SOPInstanceUID: attrString(ref, tag.ReferencedSOPInstanceUID),
+			})
+		}
+		tdr.ReferencedSeries = append(tdr.ReferencedSeries, s)
+	}
+
+	if items := GetSequenceItems(ds, tag.OperatorAssessmentSequence); len(items) > 0 {
+		op := &OperatorAssessment{
+			Operator: attrString(items[0], tag.OperatorsName),
+			Decision: attrString(items[0], tag.AlarmDecision),
+			Category: attrString(items[0], tag.ThreatCategoryDescription),
+		}
+		op.Date, _ = module.ParseDate(attrString(items[0], tag.ContentDate))
+		op.Time, _ = module.ParseTime(attrString(items[0], tag.ContentTime))
+		tdr.Operator = op
+	}
+
+	for i, item := range GetSequenceItems(ds, tag.PTOSequence) {
+		pto, err := parsePTO(item)
+		if err != nil {
+			return nil, fmt.Errorf("dicos: ParseTDR: PTO item %d: %w", i, err)
+		}
+		tdr.PTOs = append(tdr.PTOs, pto)
+	}
+	return tdr, nil
+}
+
+// parsePTO reads one PTO Sequence item
+func parsePTO(item *Dataset) (PotentialThreatObject, error) {
+	pto := PotentialThreatObject{
+		Label:       attrString(item, tag.ThreatCategoryDescription),
+		OOIType:     attrString(item, tag.OOIType),
+		Probability: tdrFloat(item, tag.ATDAssessmentProbability),
+		Confidence:  tdrFloat(item, tag.ThreatConfidenceScore),
+	}
+	pto.ID = attrInt(item, tag.PotentialThreatObjectID)
+	if size := tdrFloats(item, tag.OOISize); len(size) == 3 {
+		copy(pto.Size[:], size)
+	}
+	pto.Volume = tdrFloat(item, tag.OOIVolume)
+	pto.Mass = tdrFloat(item, tag.OOIMass)
+
+	for _, a := range GetSequenceItems(item, tag.ATDAssessmentSequence) {
+		pto.Assessments = append(pto.Assessments, ATDAssessment{
+			Category:    attrString(a, tag.ThreatCategoryDescription),
+			Ability:     attrString(a, tag.ATDAbility),
+			Probability: tdrFloat(a, tag.ATDAssessmentProbability),
+			Confidence:  tdrFloat(a, tag.ThreatConfidenceScore),
+		})
+	}
+	if len(pto.Assessments) > 0 {
+		first := pto.Assessments[0]
+		if pto.Label == "" {
+			pto.Label = first.Category
+		}
+		if !HasElement(item, tag.ATDAssessmentProbability) {
+			pto.Probability = first.Probability
+		}
+		if !HasElement(item, tag.ThreatConfidenceScore) {
+			pto.Confidence = first.Confidence
+		}
+	}
+
+	for _, rep := range GetSequenceItems(item, tag.PTORepresentationSequence) {
+		tl, br := tdrFloats(rep, tag.BoundingBoxTopLeft), tdrFloats(rep, tag.BoundingBoxBottomRight)
+		if pto.BoundingBox == nil && (tl != nil || br != nil) {
+			if len(tl) != 3 || len(br) != 3 {
+				return pto, fmt.Errorf("bounding box has %d and %d values, want 3", len(tl), len(br))
+			}
+			pto.BoundingBox = &BoundingBox{}
+			copy(pto.BoundingBox.TopLeft[:], tl)
+			copy(pto.BoundingBox.BottomRight[:], br)
+		}
+		if poly := tdrFloats(rep, tag.BoundingPolygon); pto.Polygon == nil && poly != nil {
+			if len(poly)%3 != 0 {
+				return pto, fmt.Errorf("bounding polygon has %d values, want (column, row, frame) triplets", len(poly))
+			}
+			for j := 0; j < len(poly); j += 3 {
+				pto.Polygon = append(pto.Polygon, [2]float32{poly[j], poly[j+1]})
+			}
+		}
+	}
+	return pto, nil
+}
+
+// tdrFloats returns the numeric values of t in ds as float32s, or nil
+func tdrFloats(ds *Dataset, t Tag) []float32 {
+	v := attrFloats(ds, t)
+	if v == nil {
+		return nil
+	}
+	out := make([]float32, len(v))
+	for i, f := range v {
+		out[i] = float32(f)
+	}
+	return out
+}
+
+// tdrFloat returns the first numeric value of t in ds, or 0
+func tdrFloat(ds *Dataset, t Tag) float32 {
+	return float32(attrFloat(ds, t))
+}
@@ -0,0 +1,232 @@
+package dicos
+
+import (
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/module"
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTDR_RoundTrip(t *testing.T) {
+	in := &ThreatDetectionReport{
+		ContentDate:   module.Date{Year: 2026, Month: 3, Day: 14},
+		ContentTime:   module.Time{Hour: 9, Minute: 26, Second: 53, Nano: 589000},
+		AlarmDecision: "ALARM",
+		OOI:           &module.OOIModule{OOIID: "BAG-0001", OOIType: "BAG"},
+		OOIOwner:      &module.OOIOwnerModule{OwnerName: "DOE^JANE", OwnerCategory: "PASSENGER"},
+		Itinerary:     &module.ItineraryModule{FlightNumber: "BA286", DepartureAirport: "SFO", ArrivalAirport: "LHR", CarrierName: "British Airways", CarrierCode: "BA"},
+		Operator: &OperatorAssessment{
+			Operator: "SMITH^ALEX",
+			Decision: "NO_ALARM",
+			Category: "LIQUID",
+			Date:     module.Date{Year: 2026, Month: 3, Day: 14},
+			Time:     module.Time{Hour: 9, Minute: 31, Second: 2},
+		},
+
+		ReferencedSOPClassUID:    CTImageStorageUID,
+		ReferencedSOPInstanceUID: "1.2.3.4.1",
+		ExtraReferences:          []SOPReference{{SOPClassUID: CTImageStorageUID, SOPInstanceUID: "1.2.3.4.2"}},
+		ReferencedSeries: []SeriesReference{{SeriesInstanceUID: "1.2.3.4", Instances: []SOPReference{
+			{SOPClassUID: CTImageStorageUID, SOPInstanceUID: "1.2.3.4.1"},
+			{SOPClassUID: CTImageStorageUID, SOPInstanceUID: "1.2.3.4.2"},
+		}}},
+		FrameOfReferenceUID: "1.2.3.6",
+		PTOs: []PotentialThreatObject{
+			{
+				ID:          7,
+				Label:       "EXPLOSIVE",
+				OOIType:     "EXPLOSIVE",
+				Probability: 0.95,
+				Confidence:  0.5,
+				Size:        [3]float32{10, 20, 30.5},
+				BoundingBox: &BoundingBox{TopLeft: [3]float32{100, 110, 5}, BottomRight: [3]float32{200, 210, 15}},
+				Polygon:     [][2]float32{{100, 110}, {200, 110}, {150.5, 210}},
+				Assessments: []ATDAssessment{
+					{Category: "EXPLOSIVE", Ability: "AUTOMATIC", Probability: 0.95, Confidence: 0.5},
+					{Category: "LIQUID", Probability: 0.25, Confidence: 0.125},
+				},
+			},
+			{ID: 8, Label: "KNIFE", OOIType: "KNIFE", Probability: 0.75},
+		},
+	}
+	in.Patient.PatientID = "BAG-0001"
+	in.Patient.SetPatientName("JANE", "DOE", "", "", "")
+	in.Patient.PatientBirthDate = module.Date{Year: 1980, Month: 1, Day: 2}
+	in.Series = module.GeneralSeriesModule{Modality: "TDR", SeriesInstanceUID: "1.2.3.5", SeriesNumber: 3, SeriesDescription: "ATD"}
+	in.Equipment.Manufacturer = "ACME"
+	in.SOPCommon.SpecificCharacterSet = "ISO_IR 100"
+
+	ds, err := in.GetDataset()
+	require.NoError(t, err)
+	got, err := ParseTDR(rewrite(t, ds))
+	require.NoError(t, err)
+	assert.Equal(t, in, got)
+}
+
+func TestThreatDetectionReport_Builders(t *testing.T) {
+	tdr := NewThreatDetectionReport()
+	tdr.AlarmDecision = "ALARM"
+	tdr.PTOs = []PotentialThreatObject{{ID: 4, Label: "KNIFE"}, {Label: "FIREARM"}}
+	require.NoError(t, tdr.AddAssessment(4, ATDAssessment{Category: "KNIFE", Ability: "AUTOMATIC", Probability: 0.75, Confidence: 0.5}))
+	require.NoError(t, tdr.AddAssessment(2, ATDAssessment{Category: "FIREARM", Probability: 0.5}))
+	assert.ErrorContains(t, tdr.AddAssessment(3, ATDAssessment{}), "no PTO with ID 3")
+
+	assert.ErrorContains(t, tdr.SetOperatorDecision(OperatorAssessment{Decision: "CLEAR"}), `operator decision "CLEAR" is not one of`)
+	assert.Nil(t, tdr.Operator)
+	require.NoError(t, tdr.SetOperatorDecision(OperatorAssessment{Operator: "SMITH^ALEX", Decision: "ALARM", Category: "KNIFE"}))
+	assert.False(t, tdr.Operator.Date.IsZero(), "decision time defaults to now")
+
+	ds, err := tdr.GetDataset()
+	require.NoError(t, err)
+	ds = rewrite(t, ds)
+	assert.Equal(t, 2, attrInt(ds, tag.NumberOfAlarmObjects))
+	assert.False(t, HasElement(ds, tag.AbortReason))
+	ops := GetSequenceItems(ds, tag.OperatorAssessmentSequence)
+	require.Len(t, ops, 1)
+	assert.Equal(t, "ALARM", attrString(ops[0], tag.AlarmDecision))
+	assert.Equal(t, "SMITH^ALEX", attrString(ops[0], tag.OperatorsName))
+	ptos := GetSequenceItems(ds, tag.PTOSequence)
+	require.Len(t, ptos, 2)
+	for i, pto := range ptos {
+		items := GetSequenceItems(pto, tag.ATDAssessmentSequence)
+		require.Len(t, items, 1, "PTO %d", i)
+		assert.Equal(t, tdr.PTOs[i].Label, attrString(items[0], tag.ThreatCategoryDescription))
+	}
+	assert.Equal(t, "AUTOMATIC", attrString(GetSequenceItems(ptos[0], tag.ATDAssessmentSequence)[0], tag.ATDAbility))
+
+	got, err := ParseTDR(ds)
+	require.NoError(t, err)
+	require.NotNil(t, got.Operator)
+	assert.Equal(t, tdr.Operator.Date, got.Operator.Date)
+	assert.Equal(t, tdr.Operator.Category, got.Operator.Category)
+	assert.Equal(t, tdr.PTOs[1].Assessments, got.PTOs[1].Assessments)
+}
+
+func TestThreatDetectionReport_SetAbort(t *testing.T) {
+	tdr := NewThreatDetectionReport()
+	tdr.AlarmDecision = "NO_ALARM"
+	tdr.SetAbort("INCOMPLETE_SCAN")
+	ds, err := tdr.GetDataset()
+	require.NoError(t, err)
+	ds = rewrite(t, ds)
+	assert.Equal(t, "INCOMPLETE_SCAN", attrString(ds, tag.AbortReason))
+	assert.Equal(t, "UNKNOWN", attrString(ds, tag.AlarmDecision))
+	assert.Equal(t, 0, attrInt(ds, tag.NumberOfAlarmObjects))
+	assert.True(t, HasElement(ds, tag.NumberOfAlarmObjects))
+
+	got, err := ParseTDR(ds)
+	require.NoError(t, err)
+	assert.Equal(t, "INCOMPLETE_SCAN", got.AbortReason)
+}
+
+// linkedCT is a CT of series in frame of reference frameOfRef
+func linkedCT(t *testing.T, series, frameOfRef string) *Dataset {
+	t.Helper()
+	ct := NewCTImage()
+	ct.Rows, ct.Columns = 4, 4
+	ct.Series.SeriesInstanceUID = series
+	ct.FrameOfReference.FrameOfReferenceUID = frameOfRef
+	ds, err := ct.GetDataset()
+	require.NoError(t, err)
+	return ds
+}
+
+func TestLinkTDR(t *testing.T) {
+	high, low := linkedCT(t, "1.2.3.1", "1.2.3.9"), linkedCT(t, "1.2.3.1", "1.2.3.9")
+	other := linkedCT(t, "1.2.3.2", "1.2.3.9")
+	delete(other.Elements, tag.FrameOfReferenceUID) // e.g. a projection without one
+
+	tdr := NewThreatDetectionReport()
+	tdr.ExtraReferences = []SOPReference{{SOPClassUID: CTImageStorageUID, SOPInstanceUID: "1.9"}}
+	require.NoError(t, LinkTDR(tdr, high, low, other))
+	assert.Equal(t, attrString(high, tag.SOPInstanceUID), tdr.ReferencedSOPInstanceUID)
+	assert.Equal(t, CTImageStorageUID, tdr.ReferencedSOPClassUID)
+	assert.Equal(t, []SOPReference{
+		{SOPClassUID: CTImageStorageUID, SOPInstanceUID: attrString(low, tag.SOPInstanceUID)},
+		{SOPClassUID: CTImageStorageUID, SOPInstanceUID: attrString(other, tag.SOPInstanceUID)},
+	}, tdr.ExtraReferences, "earlier references are replaced")
+	require.Len(t, tdr.ReferencedSeries, 2)
+	assert.Equal(t, "1.2.3.1", tdr.ReferencedSeries[0].SeriesInstanceUID)
+	assert.Len(t, tdr.ReferencedSeries[0].Instances, 2)
+	assert.Equal(t, "1.2.3.2", tdr.ReferencedSeries[1].SeriesInstanceUID)
+	assert.Equal(t, "1.2.3.9", tdr.FrameOfReferenceUID)
+
+	ds, err := tdr.GetDataset()
+	require.NoError(t, err)
+	ds = rewrite(t, ds)
+	assert.Len(t, GetSequenceItems(ds, tag.ReferencedImageSequence), 3)
+	series := GetSequenceItems(ds, tag.ReferencedSeriesSequence)
+	require.Len(t, series, 2)
+	assert.Equal(t, "1.2.3.2", attrString(series[1], tag.SeriesInstanceUID))
+	instances := GetSequenceItems(series[1], tag.ReferencedInstanceSequence)
+	require.Len(t, instances, 1)
+	assert.Equal(t, attrString(other, tag.SOPInstanceUID), attrString(instances[0], tag.ReferencedSOPInstanceUID))
+	assert.Equal(t, "1.2.3.9", attrString(ds, tag.FrameOfReferenceUID))
+}
+
+func TestLinkTDR_Errors(t *testing.T) {
+	tdr := NewThreatDetectionReport()
+	assert.ErrorContains(t, LinkTDR(tdr), "no images to link")
+
+	a := linkedCT(t, "1.2.3.1", "1.2.3.9")
+	err := LinkTDR(tdr, a, linkedCT(t, "1.2.3.1", "1.2.3.8"))
+	assert.ErrorContains(t, err, `image 1 frame of reference "1.2.3.8" does not match "1.2.3.9"`)
+	assert.Empty(t, tdr.ReferencedSOPInstanceUID, "unchanged on error")
+	assert.ErrorContains(t, LinkTDR(tdr, a, a), "image 1: instance")
+
+	noSeries := linkedCT(t, "1.2.3.1", "1.2.3.9")
+	delete(noSeries.Elements, tag.SeriesInstanceUID)
+	assert.ErrorContains(t, LinkTDR(tdr, noSeries), "image 0 has no Series Instance UID")
+	delete(a.Elements, tag.SOPInstanceUID)
+	assert.ErrorContains(t, LinkTDR(tdr, a), "image 0: source dataset has no SOP Class/Instance UID")
+}
+
+func TestParseTDR_AssessmentFallback(t *testing.T) {
+	assessment, err := NewDataset(
+		WithElement(tag.ThreatCategoryDescription, "FIREARM"),
+		WithElement(tag.ATDAssessmentProbability, float32(0.875)),
+		WithElement(tag.ThreatConfidenceScore, float32(0.5)),
+	)
+	require.NoError(t, err)
+	rep, err := NewDataset(WithElement(tag.BoundingPolygon, []float32{1, 2, 0, 3, 4, 0}))
+	require.NoError(t, err)
+	pto, err := NewDataset(
+		WithElement(tag.PotentialThreatObjectID, "PTO-A"),
+		WithSequence(tag.ATDAssessmentSequence, assessment),
+		WithSequence(tag.PTORepresentationSequence, rep),
+	)
+	require.NoError(t, err)
+	ds, err := NewDataset(
+		WithElement(tag.SOPClassUID, DICOSTDRStorageUID),
+		WithSequence(tag.PTOSequence, pto),
+	)
+	require.NoError(t, err)
+
+	tdr, err := ParseTDR(rewrite(t, ds))
+	require.NoError(t, err)
+	require.Len(t, tdr.PTOs, 1)
+	got := tdr.PTOs[0]
+	assert.Equal(t, 0, got.ID, "non-numeric IDs are left 0")
+	assert.Equal(t, "FIREARM", got.Label)
+	assert.Equal(t, float32(0.875), got.Probability)
+	assert.Equal(t, float32(0.5), got.Confidence)
+	assert.Nil(t, got.BoundingBox)
+	assert.Equal(t, [][2]float32{{1, 2}, {3, 4}}, got.Polygon)
+
+	rep.Elements[tag.BoundingPolygon].Value = []float32{1, 2, 3, 4}
+	_, err = ParseTDR(ds)
+	assert.ErrorContains(t, err, "PTO item 0: bounding polygon has 4 values")
+}
+
+func TestParseTDR_NotATDR(t *testing.T) {
+	ct := NewCTImage()
+	ct.Rows, ct.Columns = 4, 4
+	ds, err := ct.GetDataset()
+	require.NoError(t, err)
+	_, err = ParseTDR(ds)
+	assert.ErrorContains(t, err, "is not a TDR")
+	_, err = ParseTDR(nil)
+	assert.Error(t, err)
+}
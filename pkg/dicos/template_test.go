@@ -0,0 +1,107 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildFromTemplate_CT(t *testing.T) {
+	tmpl := []byte(`
+iod: CT
+codec: jpeg-ls
+rows: 8
+columns: 8
+frames: 2
+modules:
+  patient:
+    PatientID: PAT-001
+    PatientName: DOE^JANE
+    PatientBirthDate: "19800131"
+  series:
+    Modality: CT
+  equipment:
+    manufacturer: ACME
+  ctimagemod:
+    KVP: 120
+tags:
+  "(0008,1090)": SCANNER-9000
+  "0028,1050": {vr: DS, value: [40, 400]}
+`)
+	var calls int
+	ds, err := BuildFromTemplate(tmpl, func(rows, cols, frames int) ([]uint16, error) {
+		calls++
+		data := make([]uint16, rows*cols*frames)
+		for i := range data {
+			data[i] = uint16(i)
+		}
+		return data, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+
+	str := func(tg tag.Tag) string {
+		elem, ok := ds.FindElement(tg.Group, tg.Element)
+		require.True(t, ok, "missing %s", tg)
+		s, _ := elem.GetString()
+		return s
+	}
+	assert.Equal(t, "PAT-001", str(tag.PatientID))
+	assert.Equal(t, "DOE^JANE^^^", str(tag.PatientName))
+	assert.Equal(t, "19800131", str(tag.PatientBirthDate))
+	assert.Equal(t, "ACME", str(tag.Manufacturer))
+	assert.Equal(t, "SCANNER-9000", str(tag.New(0x0008, 0x1090)))
+	assert.Equal(t, "40\\400", str(tag.WindowCenter))
+
+	var buf bytes.Buffer
+	_, err = Write(&buf, ds)
+	require.NoError(t, err)
+	parsed, err := ReadBufferContext(context.Background(), buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, string(CodecJPEGLS.TransferSyntaxUID()), string(GetTransferSyntax(parsed)))
+	pd, err := parsed.GetPixelDataContext(context.Background())
+	require.NoError(t, err)
+	require.Len(t, pd.Frames, 2)
+	frame, err := DecodeFrameDataContext(context.Background(), pd, 1, 8, 8, GetTransferSyntax(parsed))
+	require.NoError(t, err)
+	assert.Equal(t, uint16(64), frame[0])
+}
+
+func TestBuildFromTemplate_JSON(t *testing.T) {
+	tmpl := []byte(`{
+		"iod": "TDR",
+		"modules": {"series": {"Modality": "TDR", "SeriesInstanceUID": "1.2.3.4"}},
+		"attributes": {"AlarmDecision": "ALARM"},
+		"tags": {"(0020,000D)": "1.2.3"},
+		"ptos": [{"label": "KNIFE", "probability": 0.9}]
+	}`)
+	ds, err := BuildFromTemplate(tmpl, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, ds)
+}
+
+func TestBuildFromTemplate_Errors(t *testing.T) {
+	tests := map[string]string{
+		"unknown iod":    "iod: MRI",
+		"unknown codec":  "iod: CT\ncodec: bogus",
+		"unknown module": "iod: DX\nmodules:\n  nope:\n    X: 1",
+		"unknown field":  "iod: DX\nmodules:\n  patient:\n    Shoe: 9",
+		"bad date":       "iod: DX\nmodules:\n  patient:\n    PatientBirthDate: yesterday",
+		"bad tag":        "iod: DX\ntags:\n  \"(0008)\": x",
+		"invalid iod":    "iod: CT\ntags:\n  \"(0008,0016)\": \"\"",
+		"malformed":      "iod: [",
+	}
+	for name, tmpl := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := BuildFromTemplate([]byte(tmpl), nil)
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := BuildFromTemplate([]byte("iod: CT\nvalidate: false\ntags:\n  \"(0008,0016)\": \"\""), nil)
+	assert.NoError(t, err, "validation can be disabled")
+}
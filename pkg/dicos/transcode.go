@@ -0,0 +1,89 @@
+package dicos
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/jpfielding/dicos.go/pkg/dicos/transfer"
+)
+
+// Transcode returns a copy of ds with its pixel data re-encoded in the target
+// transfer syntax. The frames are decoded (native, JPEG-LS, JPEG Lossless,
+// JPEG 2000 or RLE) and encoded again with the codec of target, or stored
+// native for Explicit or Implicit VR Little Endian, the native syntaxes Write
+// produces.
+// TransferSyntaxUID is updated, and LossyImageCompression is set to "00"
+// unless the source or the target is lossy compressed, which is recorded as
+// "01" with the ratio and method of a lossy target appended to any already
+// recorded. ds is not modified.
+//
+// Example:
+//
+//	ds, _ := dicos.ReadFile("vendor.dcs")
+//	out, err := dicos.Transcode(ds, transfer.JPEGLSLossless)
+//	if err != nil {
+//		return err
+//	}
+//	dicos.WriteFile("normalized.dcs", out)
+func Transcode(ds *Dataset, target transfer.Syntax) (*Dataset, error) {
+	return TranscodeContext(context.Background(), ds, target)
+}
+
+// TranscodeContext is Transcode with a context that is checked between
+// frames and carried into log records emitted while decoding
+func TranscodeContext(ctx context.Context, ds *Dataset, target transfer.Syntax) (*Dataset, error) {
+	var codec Codec
+	if target != transfer.ExplicitVRLittleEndian && target != transfer.ImplicitVRLittleEndian {
+		if codec = CodecByTransferSyntax(string(target)); codec == nil {
+			return nil, fmt.Errorf("cannot transcode to %s (%s)", target.Name(), target)
+		}
+	}
+	if _, ok := ds.FindElement(tag.PixelData.Group, tag.PixelData.Element); !ok {
+		return nil, fmt.Errorf("no pixel data to transcode")
+	}
+
+	source := ds.TransferSyntax()
+	out := CloneDataset(ds)
+
+	if source.IsEncapsulated() || codec != nil {
+		old, err := ds.GetPixelDataContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		vol, err := DecodeVolumeContext(ctx, ds)
+		if err != nil {
+			return nil, fmt.Errorf("decoding %s pixel data: %w", source.Name(), err)
+		}
+		err = WithPixelData(GetRows(ds), GetColumns(ds), ds.BitsAllocated(), vol.Data, codec)(out)
+		if err != nil {
+			return nil, fmt.Errorf("encoding %s pixel data: %w", target.Name(), err)
+		}
+		if pd, ok := out.Elements[tag.PixelData].GetPixelData(); ok && len(pd.Frames) == len(old.Frames) {
+			for i := range pd.Frames {
+				pd.Frames[i].Meta = old.Frames[i].Meta
+			}
+		}
+		// A recorded codec decision described the codec being replaced
+		delete(out.Elements, tag.CodecDecisionCreator)
+		delete(out.Elements, tag.CodecDecisionName)
+		delete(out.Elements, tag.CodecDecisionRecord)
+	}
+
+	// a lossy target codec has recorded its compression in out
+	lossy := source.IsLossy() || attrString(out, tag.LossyImageCompression) == "01"
+	compression := "00"
+	if lossy {
+		compression = "01"
+	}
+	opts := []Option{
+		WithElement(tag.TransferSyntaxUID, string(target)),
+		WithElement(tag.LossyImageCompression, compression),
+	}
+	for _, opt := range opts {
+		if err := opt(out); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
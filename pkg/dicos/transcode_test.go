@@ -0,0 +1,80 @@
+package dicos
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/jpfielding/dicos.go/pkg/dicos/transfer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranscode_RoundTrip(t *testing.T) {
+	const rows, cols = 8, 8
+	hu := make([]int16, rows*cols*2)
+	for i := range hu {
+		hu[i] = int16(i*31 - 1000)
+	}
+	ct := NewCTImage()
+	ct.SetSignedPixelData(rows, cols, hu)
+	src, err := ct.GetDataset()
+	require.NoError(t, err)
+	want, err := DecodeVolume(src)
+	require.NoError(t, err)
+
+	// each step transcodes the previous one, so every syntax is decoded too
+	ds := src
+	for _, target := range []transfer.Syntax{
+		transfer.JPEGLSLossless,
+		transfer.JPEGLosslessFirstOrder,
+		transfer.RLELossless,
+		transfer.JPEG2000Lossless,
+		transfer.ExplicitVRLittleEndian,
+		transfer.ImplicitVRLittleEndian,
+		transfer.JPEGLSLossless,
+	} {
+		out, err := Transcode(ds, target)
+		require.NoError(t, err, target.Name())
+		assert.Equal(t, target, out.TransferSyntax())
+		assert.Equal(t, target.IsEncapsulated(), out.IsEncapsulated())
+		assert.Equal(t, "00", stringValue(out, tag.LossyImageCompression))
+
+		var buf bytes.Buffer
+		_, err = Write(&buf, out)
+		require.NoError(t, err, target.Name())
+		ds, err = ReadBuffer(buf.Bytes())
+		require.NoError(t, err, target.Name())
+		got, err := DecodeVolume(ds)
+		require.NoError(t, err, target.Name())
+		assert.Equal(t, want.Data, got.Data, target.Name())
+		assert.Equal(t, 2, got.Depth, target.Name())
+	}
+	assert.Equal(t, transfer.ExplicitVRLittleEndian, src.TransferSyntax(), "source unchanged")
+}
+
+func TestTranscode_Lossy(t *testing.T) {
+	ds, err := ReadBuffer(writeTestCT(t, 4, 4, 1, CodecJPEGLS))
+	require.NoError(t, err)
+	ds.Elements[tag.TransferSyntaxUID].Value = string(transfer.JPEGLSNearLossless)
+
+	out, err := Transcode(ds, transfer.ExplicitVRLittleEndian)
+	require.NoError(t, err)
+	assert.Equal(t, "01", stringValue(out, tag.LossyImageCompression), "lossy history is kept")
+	assert.False(t, out.IsEncapsulated())
+}
+
+func TestTranscode_Errors(t *testing.T) {
+	ds, err := ReadBuffer(writeTestCT(t, 4, 4, 1, nil))
+	require.NoError(t, err)
+	_, err = Transcode(ds, transfer.JPEG2000)
+	assert.ErrorContains(t, err, "cannot transcode to JPEG 2000")
+	_, err = Transcode(ds, transfer.JPEGBaseline)
+	assert.ErrorIs(t, err, ErrUnsupportedPixelFormat, "16-bit CT")
+	_, err = Transcode(ds, transfer.ExplicitVRBigEndian)
+	assert.Error(t, err)
+
+	delete(ds.Elements, tag.PixelData)
+	_, err = Transcode(ds, transfer.RLELossless)
+	assert.ErrorContains(t, err, "no pixel data")
+}
@@ -63,6 +63,17 @@ func (s Syntax) IsJPEGLossless() bool {
 	return s == JPEGLossless || s == JPEGLosslessFirstOrder
 }
 
+// IsLossy returns true if this transfer syntax may carry lossy compressed
+// pixel data
+func (s Syntax) IsLossy() bool {
+	switch s {
+	case JPEGLSNearLossless, JPEG2000, JPEGBaseline, JPEGExtended:
+		return true
+	default:
+		return false
+	}
+}
+
 // Name returns a human-readable name for the transfer syntax
 func (s Syntax) Name() string {
 	switch s {
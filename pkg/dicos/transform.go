@@ -0,0 +1,288 @@
+package dicos
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// Transform is an in-plane orientation change applied to frames
+type Transform int
+
+const (
+	Rotate90  Transform = iota // 90° clockwise
+	Rotate180                  // 180°
+	Rotate270                  // 270° clockwise (90° counter-clockwise)
+	FlipH                      // mirror left/right
+	FlipV                      // mirror top/bottom
+)
+
+// String returns the transform name
+func (t Transform) String() string {
+	switch t {
+	case Rotate90:
+		return "rotate90"
+	case Rotate180:
+		return "rotate180"
+	case Rotate270:
+		return "rotate270"
+	case FlipH:
+		return "flipH"
+	case FlipV:
+		return "flipV"
+	}
+	return fmt.Sprintf("Transform(%d)", int(t))
+}
+
+// SwapsAxes returns true if the transform exchanges rows and columns
+func (t Transform) SwapsAxes() bool {
+	return t == Rotate90 || t == Rotate270
+}
+
+// mapPoint maps a position in an image of size w x h to its position after the
+// transform. Pixel indices use w-1/h-1 as the far edge; continuous coordinates
+// (ROI corners) use w/h.
+func (t Transform) mapPoint(x, y, w, h float64) (float64, float64) {
+	switch t {
+	case Rotate90:
+		return h - y, x
+	case Rotate180:
+		return w - x, h - y
+	case Rotate270:
+		return y, w - x
+	case FlipH:
+		return w - x, y
+	case FlipV:
+		return x, h - y
+	}
+	return x, y
+}
+
+// MapPoint maps a continuous (column, row) coordinate, such as a TDR ROI
+// corner, in a rows x cols image to its position after the transform
+func (t Transform) MapPoint(col, row float32, rows, cols int) (float32, float32) {
+	x, y := t.mapPoint(float64(col), float64(row), float64(cols), float64(rows))
+	return float32(x), float32(y)
+}
+
+// TransformFrame applies t to one frame of row-major pixels and returns the
+// new pixels with their dimensions
+func TransformFrame(data []uint16, rows, cols int, t Transform) (out []uint16, newRows, newCols int) {
+	newRows, newCols = rows, cols
+	if t.SwapsAxes() {
+		newRows, newCols = cols, rows
+	}
+	out = make([]uint16, rows*cols)
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			idx := r*cols + c
+			if idx >= len(data) {
+				return out, newRows, newCols
+			}
+			nx, ny := t.mapPoint(float64(c), float64(r), float64(cols-1), float64(rows-1))
+			out[int(ny)*newCols+int(nx)] = data[idx]
+		}
+	}
+	return out, newRows, newCols
+}
+
+// PlaneGeometry places an image plane in patient coordinates
+type PlaneGeometry struct {
+	Position    [3]float64 // ImagePositionPatient of the first pixel
+	Orientation [6]float64 // ImageOrientationPatient: row then column direction cosines
+	RowSpacing  float64    // mm between rows
+	ColSpacing  float64    // mm between columns
+	Rows, Cols  int
+}
+
+// PlaneGeometryFromDataset reads the image plane attributes of ds
+func PlaneGeometryFromDataset(ds *Dataset) PlaneGeometry {
+	g := PlaneGeometry{Rows: ds.Rows(), Cols: ds.Columns()}
+	copy(g.Position[:], GetImagePositionPatient(ds))
+	copy(g.Orientation[:], GetImageOrientationPatient(ds))
+	g.RowSpacing, g.ColSpacing = GetPixelSpacing(ds)
+	return g
+}
+
+// PixelToPatient returns the patient coordinate of pixel (col, row)
+func (g PlaneGeometry) PixelToPatient(col, row float64) [3]float64 {
+	var p [3]float64
+	for i := range p {
+		p[i] = g.Position[i] + col*g.ColSpacing*g.Orientation[i] + row*g.RowSpacing*g.Orientation[3+i]
+	}
+	return p
+}
+
+// Transform returns the geometry of the plane after t is applied to its pixels,
+// so that every pixel keeps its patient coordinate
+func (g PlaneGeometry) Transform(t Transform) PlaneGeometry {
+	var rowDir, colDir [3]float64
+	copy(rowDir[:], g.Orientation[:3])
+	copy(colDir[:], g.Orientation[3:])
+	neg := func(v [3]float64) [3]float64 { return [3]float64{0 - v[0], 0 - v[1], 0 - v[2]} }
+
+	out := g
+	lastCol, lastRow := float64(g.Cols-1), float64(g.Rows-1)
+	switch t {
+	case Rotate90:
+		out.Position = g.PixelToPatient(0, lastRow)
+		rowDir, colDir = neg(colDir), rowDir
+	case Rotate180:
+		out.Position = g.PixelToPatient(lastCol, lastRow)
+		rowDir, colDir = neg(rowDir), neg(colDir)
+	case Rotate270:
+		out.Position = g.PixelToPatient(lastCol, 0)
+		rowDir, colDir = colDir, neg(rowDir)
+	case FlipH:
+		out.Position = g.PixelToPatient(lastCol, 0)
+		rowDir = neg(rowDir)
+	case FlipV:
+		out.Position = g.PixelToPatient(0, lastRow)
+		colDir = neg(colDir)
+	}
+	copy(out.Orientation[:3], rowDir[:])
+	copy(out.Orientation[3:], colDir[:])
+	if t.SwapsAxes() {
+		out.Rows, out.Cols = g.Cols, g.Rows
+		out.RowSpacing, out.ColSpacing = g.ColSpacing, g.RowSpacing
+	}
+	return out
+}
+
+// Transform returns a new volume with t applied to every slice. Spacing,
+// origin and orientation are updated so voxels keep their patient coordinates.
+func (v *Volume) Transform(t Transform) *Volume {
+	g := PlaneGeometry{
+		Position:    [3]float64{v.OriginX, v.OriginY, v.OriginZ},
+		Orientation: v.Orientation,
+		RowSpacing:  v.SpacingY,
+		ColSpacing:  v.SpacingX,
+		Rows:        v.Height,
+		Cols:        v.Width,
+	}.Transform(t)
+
+	n := v.samples()
+	out := NewVolumeSamples(g.Cols, g.Rows, v.Depth, n)
+	out.SpacingX, out.SpacingY, out.SpacingZ = g.ColSpacing, g.RowSpacing, v.SpacingZ
+	out.OriginX, out.OriginY, out.OriginZ = g.Position[0], g.Position[1], g.Position[2]
+	out.Orientation = g.Orientation
+
+	sliceSize := v.Width * v.Height
+	if n == 1 {
+		for z := 0; z < v.Depth; z++ {
+			slice, _, _ := TransformFrame(v.Data[z*sliceSize:(z+1)*sliceSize], v.Height, v.Width, t)
+			copy(out.Data[z*sliceSize:], slice)
+		}
+		return out
+	}
+	// each sample of a color volume is transformed as its own plane
+	plane := make([]uint16, sliceSize)
+	for z := 0; z < v.Depth; z++ {
+		base := z * sliceSize * n
+		for s := range n {
+			for i := range plane {
+				plane[i] = v.Data[base+i*n+s]
+			}
+			slice, _, _ := TransformFrame(plane, v.Height, v.Width, t)
+			for i, val := range slice {
+				out.Data[base+i*n+s] = val
+			}
+		}
+	}
+	return out
+}
+
+// TransformROIs maps PTO bounding boxes and polygons, given in the pixel space
+// of a rows x cols image, to the image after t is applied. Frame (Z)
+// coordinates are unchanged.
+func (tdr *ThreatDetectionReport) TransformROIs(t Transform, rows, cols int) {
+	for i := range tdr.PTOs {
+		pto := &tdr.PTOs[i]
+		if b := pto.BoundingBox; b != nil {
+			x1, y1 := t.MapPoint(b.TopLeft[0], b.TopLeft[1], rows, cols)
+			x2, y2 := t.MapPoint(b.BottomRight[0], b.BottomRight[1], rows, cols)
+			b.TopLeft[0], b.BottomRight[0] = min(x1, x2), max(x1, x2)
+			b.TopLeft[1], b.BottomRight[1] = min(y1, y2), max(y1, y2)
+		}
+		for j, p := range pto.Polygon {
+			x, y := t.MapPoint(p[0], p[1], rows, cols)
+			pto.Polygon[j] = [2]float32{x, y}
+		}
+	}
+}
+
+// TransformDataset applies t to every frame of a dataset with native pixel data
+// and rewrites Rows, Columns, PixelSpacing, ImagePositionPatient and
+// ImageOrientationPatient to match, and marks the Image Type DERIVED.
+// Encapsulated pixel data must be decoded first.
+func TransformDataset(ds *Dataset, t Transform) error {
+	if spp := ds.SamplesPerPixel(); spp != 1 {
+		return fmt.Errorf("transforming %d samples per pixel is not supported, use Volume.Transform", spp)
+	}
+	pd, err := ds.GetPixelData()
+	if err != nil {
+		return err
+	}
+	if pd.IsEncapsulated {
+		return fmt.Errorf("cannot %s encapsulated pixel data, decode it first", t)
+	}
+
+	g := PlaneGeometryFromDataset(ds)
+	rows, cols := g.Rows, g.Cols
+	out := &PixelData{Frames: make([]Frame, len(pd.Frames))}
+	for i, f := range pd.Frames {
+		out.Frames[i].Data, _, _ = TransformFrame(f.Data, rows, cols, t)
+	}
+	ng := g.Transform(t)
+
+	opts := []Option{
+		WithRawPixelData(out),
+		WithElement(tag.Rows, uint16(ng.Rows)),
+		WithElement(tag.Columns, uint16(ng.Cols)),
+		withDerivedImageType(ds),
+	}
+	if t.SwapsAxes() {
+		// the stored row\column pairs swap as they are, whatever spacing the
+		// geometry was calibrated from
+		for _, st := range []Tag{tag.PixelSpacing, tag.ImagerPixelSpacing} {
+			if v := dsValues(ds, st); len(v) >= 2 {
+				opts = append(opts, WithElement(st, formatDSValues(v[1], v[0])))
+			}
+		}
+	}
+	_, hasIPP := ds.FindElement(tag.ImagePositionPatient.Group, tag.ImagePositionPatient.Element)
+	_, hasIOP := ds.FindElement(tag.ImageOrientationPatient.Group, tag.ImageOrientationPatient.Element)
+	if hasIPP || hasIOP {
+		opts = append(opts,
+			WithElement(tag.ImagePositionPatient, formatDSValues(ng.Position[:]...)),
+			WithElement(tag.ImageOrientationPatient, formatDSValues(ng.Orientation[:]...)),
+		)
+	}
+	for _, opt := range opts {
+		if err := opt(ds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatDSValues formats values as a backslash separated DS string
+func formatDSValues(values ...float64) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = formatDS(v)
+	}
+	return strings.Join(parts, "\\")
+}
+
+// formatDS formats v in at most the 16 characters of a DS value, dropping
+// significant digits as needed
+func formatDS(v float64) string {
+	s := strconv.FormatFloat(v, 'g', -1, 64)
+	for prec := 15; len(s) > 16 && prec > 0; prec-- {
+		s = strconv.FormatFloat(v, 'g', prec, 64)
+	}
+	return s
+}
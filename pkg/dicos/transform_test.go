@@ -0,0 +1,140 @@
+package dicos
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransformFrame(t *testing.T) {
+	// 2 rows x 3 cols
+	// 1 2 3
+	// 4 5 6
+	data := []uint16{1, 2, 3, 4, 5, 6}
+
+	tests := []struct {
+		t          Transform
+		want       []uint16
+		rows, cols int
+	}{
+		{Rotate90, []uint16{4, 1, 5, 2, 6, 3}, 3, 2},
+		{Rotate180, []uint16{6, 5, 4, 3, 2, 1}, 2, 3},
+		{Rotate270, []uint16{3, 6, 2, 5, 1, 4}, 3, 2},
+		{FlipH, []uint16{3, 2, 1, 6, 5, 4}, 2, 3},
+		{FlipV, []uint16{4, 5, 6, 1, 2, 3}, 2, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.t.String(), func(t *testing.T) {
+			out, rows, cols := TransformFrame(data, 2, 3, tt.t)
+			assert.Equal(t, tt.want, out)
+			assert.Equal(t, tt.rows, rows)
+			assert.Equal(t, tt.cols, cols)
+		})
+	}
+}
+
+func TestPlaneGeometry_TransformKeepsPatientCoordinates(t *testing.T) {
+	g := PlaneGeometry{
+		Position:    [3]float64{-10, 20, 5},
+		Orientation: [6]float64{1, 0, 0, 0, 1, 0},
+		RowSpacing:  0.5,
+		ColSpacing:  0.8,
+		Rows:        4,
+		Cols:        6,
+	}
+
+	for _, tr := range []Transform{Rotate90, Rotate180, Rotate270, FlipH, FlipV} {
+		ng := g.Transform(tr)
+		for r := 0; r < g.Rows; r++ {
+			for c := 0; c < g.Cols; c++ {
+				nx, ny := tr.mapPoint(float64(c), float64(r), float64(g.Cols-1), float64(g.Rows-1))
+				want := g.PixelToPatient(float64(c), float64(r))
+				got := ng.PixelToPatient(nx, ny)
+				for i := range want {
+					assert.InDelta(t, want[i], got[i], 1e-9, "%s pixel (%d,%d)", tr, c, r)
+				}
+			}
+		}
+	}
+}
+
+func TestTDR_TransformROIs(t *testing.T) {
+	tdr := NewThreatDetectionReport()
+	tdr.PTOs = []PotentialThreatObject{{
+		BoundingBox: &BoundingBox{TopLeft: [3]float32{10, 20, 1}, BottomRight: [3]float32{30, 25, 4}},
+		Polygon:     [][2]float32{{10, 20}},
+	}}
+
+	// 100 rows x 200 cols rotated clockwise becomes 200 rows x 100 cols
+	tdr.TransformROIs(Rotate90, 100, 200)
+
+	box := tdr.PTOs[0].BoundingBox
+	assert.Equal(t, [3]float32{75, 10, 1}, box.TopLeft)
+	assert.Equal(t, [3]float32{80, 30, 4}, box.BottomRight)
+	assert.Equal(t, [2]float32{80, 10}, tdr.PTOs[0].Polygon[0])
+}
+
+func TestTransformDataset(t *testing.T) {
+	ds, err := NewDataset(
+		WithElement(tag.Rows, uint16(2)),
+		WithElement(tag.Columns, uint16(3)),
+		WithElement(tag.PixelSpacing, "0.5\\0.8"),
+		WithElement(tag.ImagePositionPatient, "0\\0\\0"),
+		WithElement(tag.ImageOrientationPatient, "1\\0\\0\\0\\1\\0"),
+		WithRawPixelData(&PixelData{Frames: []Frame{{Data: []uint16{1, 2, 3, 4, 5, 6}}}}),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, TransformDataset(ds, Rotate90))
+
+	assert.Equal(t, 3, ds.Rows())
+	assert.Equal(t, 2, ds.Columns())
+	row, col := GetPixelSpacing(ds)
+	assert.Equal(t, 0.8, row)
+	assert.Equal(t, 0.5, col)
+	assert.Equal(t, []float64{0, 0.5, 0}, GetImagePositionPatient(ds))
+	assert.Equal(t, []float64{0, -1, 0, 1, 0, 0}, GetImageOrientationPatient(ds))
+	it, _ := GetImageType(ds)
+	assert.True(t, it.IsDerived())
+
+	pd, err := ds.GetPixelData()
+	require.NoError(t, err)
+	assert.Equal(t, []uint16{4, 1, 5, 2, 6, 3}, pd.Frames[0].Data)
+}
+
+func TestTransformDataset_DSValues(t *testing.T) {
+	// a magnification factor calibrates the spacing, but PixelSpacing keeps
+	// its own values, and oblique cosines stay within the DS length
+	ds, err := NewDataset(
+		WithElement(tag.Rows, uint16(2)),
+		WithElement(tag.Columns, uint16(3)),
+		WithElement(tag.PixelSpacing, "0.3\\0.4"),
+		WithElement(tag.ImagerPixelSpacing, "0.6\\0.8"),
+		WithElement(tag.EstimatedRadiographicMagnificationFactor, "2"),
+		WithElement(tag.ImagePositionPatient, "0\\0\\0"),
+		WithElement(tag.ImageOrientationPatient, "0.7071067811865476\\0.7071067811865476\\0\\0\\0\\1"),
+		WithRawPixelData(&PixelData{Frames: []Frame{{Data: make([]uint16, 6)}}}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, TransformDataset(ds, Rotate270))
+
+	assert.Equal(t, []float64{0.4, 0.3}, dsValues(ds, tag.PixelSpacing))
+	assert.Equal(t, []float64{0.8, 0.6}, dsValues(ds, tag.ImagerPixelSpacing))
+	for _, st := range []Tag{tag.ImagePositionPatient, tag.ImageOrientationPatient} {
+		s, _ := ds.Elements[st].GetString()
+		for _, v := range strings.Split(s, "\\") {
+			assert.LessOrEqual(t, len(v), 16, "%s value %q", st, v)
+		}
+	}
+	assert.InDeltaSlice(t, []float64{0, 0, 1, -0.7071067811865476, -0.7071067811865476, 0}, GetImageOrientationPatient(ds), 1e-12)
+}
+
+func TestFormatDS(t *testing.T) {
+	assert.Equal(t, "0.5", formatDS(0.5))
+	assert.Equal(t, "-0.7071067811865", formatDS(-0.7071067811865476))
+	assert.Equal(t, "1.2345678912e-05", formatDS(1.2345678912345e-05))
+	assert.Equal(t, "-1.23456789e+100", formatDS(-1.2345678912345e+100))
+}
@@ -3,8 +3,12 @@ package dicos
 import (
 	"encoding/binary"
 	"fmt"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/jpfielding/dicos.go/pkg/dicos/module"
 	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
 	"github.com/jpfielding/dicos.go/pkg/dicos/transfer"
 )
@@ -22,6 +26,14 @@ import (
 //	elem, ok := ds.FindElement(tag.PatientID.Group, tag.PatientID.Element)
 type Dataset struct {
 	Elements map[Tag]*Element
+
+	// Trailing holds bytes found after the end of a parsed dataset that do
+	// not form valid elements, such as vendor blobs appended to the file.
+	// It is kept for forensic use and is never written.
+	Trailing []byte
+
+	mu       sync.Mutex
+	warnings []Warning // heuristics applied by helpers, see Warnings
 }
 
 // Element represents a single DICOM data element with its tag, Value Representation (VR),
@@ -67,7 +79,8 @@ type Tag = tag.Tag
 type PixelData struct {
 	IsEncapsulated bool
 	Frames         []Frame
-	Offsets        []uint32 // Basic Offset Table for encapsulated data
+	Offsets        []uint32        // Basic Offset Table for encapsulated data
+	Padding        FragmentPadding // applied to encapsulated fragments when written
 }
 
 // Frame represents a single frame (image slice) of pixel data.
@@ -88,6 +101,9 @@ type Frame struct {
 
 	// For encapsulated (compressed) data
 	CompressedData []byte
+
+	// Optional per-frame context, carried in the Per-frame Functional Groups Sequence
+	Meta *FrameMeta
 }
 
 // GetFlatData returns all frames concatenated into a single slice of uint16 pixel values.
@@ -254,10 +270,8 @@ func (ds *Dataset) NumberOfFrames() int {
 			return v
 		}
 		// Number of Frames can be a string (IS VR)
-		if s, ok := elem.GetString(); ok {
-			var n int
-			fmt.Sscanf(strings.TrimSpace(s), "%d", &n)
-			return n
+		if v, ok := elem.GetUints(); ok {
+			return int(v[0])
 		}
 	}
 	return 1
@@ -274,6 +288,28 @@ func (ds *Dataset) BitsAllocated() int {
 	return 16
 }
 
+// BitsStored returns the bits stored per sample from BitsStored (0028,0101).
+// Returns BitsAllocated as default if not specified.
+func (ds *Dataset) BitsStored() int {
+	if elem, ok := ds.FindElement(0x0028, 0x0101); ok {
+		if v, ok := elem.GetInt(); ok {
+			return v
+		}
+	}
+	return ds.BitsAllocated()
+}
+
+// SamplesPerPixel returns the samples per pixel from SamplesPerPixel (0028,0002).
+// Returns 1 (grayscale) as default if not specified.
+func (ds *Dataset) SamplesPerPixel() int {
+	if elem, ok := ds.FindElement(0x0028, 0x0002); ok {
+		if v, ok := elem.GetInt(); ok {
+			return v
+		}
+	}
+	return 1
+}
+
 // PixelRepresentation returns the pixel representation from PixelRepresentation (0028,0103).
 // Returns 0 (unsigned) as default if not specified.
 func (ds *Dataset) PixelRepresentation() int {
@@ -393,7 +429,9 @@ func (elem *Element) GetInts() ([]int, bool) {
 	return nil, false
 }
 
-// GetFloats returns a slice of float64s from an element
+// GetFloats returns the numeric values of an element as float64s: binary
+// FL, FD, OF and OD values, the decimal strings of DS and IS, and integers.
+// It fails if any value is not a number.
 func (elem *Element) GetFloats() ([]float64, bool) {
 	switch v := elem.Value.(type) {
 	case []float32:
@@ -409,6 +447,120 @@ func (elem *Element) GetFloats() ([]float64, bool) {
 	case float64:
 		return []float64{v}, true
 	}
+	strs, ok := elem.GetStrings()
+	if !ok || len(strs) == 0 {
+		return nil, false
+	}
+	res := make([]float64, len(strs))
+	for i, s := range strs {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, false
+		}
+		res[i] = f
+	}
+	return res, true
+}
+
+// GetStrings returns the values of an element as strings: a string value
+// split on backslash with each value trimmed, or numbers formatted in
+// decimal. An empty string has no values.
+func (elem *Element) GetStrings() ([]string, bool) {
+	strs, err := elementStrings(elem)
+	if err != nil {
+		return nil, false
+	}
+	out := make([]string, len(strs))
+	for i, s := range strs {
+		out[i] = strings.TrimRight(s, "\x00")
+	}
+	return out, true
+}
+
+// GetUints returns the values of an element as unsigned integers: binary
+// US, UL, OW and OL values and the decimal strings of IS. It fails if any
+// value is negative or not an integer.
+func (elem *Element) GetUints() ([]uint32, bool) {
+	switch v := elem.Value.(type) {
+	case []uint32:
+		return v, true
+	case uint32:
+		return []uint32{v}, true
+	case uint16:
+		return []uint32{uint32(v)}, true
+	case []uint16:
+		res := make([]uint32, len(v))
+		for i, val := range v {
+			res[i] = uint32(val)
+		}
+		return res, true
+	}
+	strs, ok := elem.GetStrings()
+	if !ok || len(strs) == 0 {
+		return nil, false
+	}
+	res := make([]uint32, len(strs))
+	for i, s := range strs {
+		u, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return nil, false
+		}
+		res[i] = uint32(u)
+	}
+	return res, true
+}
+
+// GetDate returns the first value of a DA element as midnight UTC of that
+// day, or of a DT element as the instant it names
+func (elem *Element) GetDate() (time.Time, bool) {
+	strs, ok := elem.GetStrings()
+	if !ok || len(strs) == 0 {
+		return time.Time{}, false
+	}
+	if elem.VR == "DT" {
+		dt, err := module.ParseDateTime(strs[0])
+		return dt.Time, err == nil
+	}
+	d, err := module.ParseDate(strs[0])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Date(d.Year, time.Month(d.Month), d.Day, 0, 0, 0, 0, time.UTC), true
+}
+
+// GetTime returns the first value of a TM element as that time of day on the
+// zero time.Time, January 1 of year 1 UTC. Add it to a GetDate for a
+// timestamp:
+//
+//	d, _ := ds.Elements[tag.StudyDate].GetDate()
+//	tm, _ := ds.Elements[tag.StudyTime].GetTime()
+//	studied := d.Add(tm.Sub(time.Time{}))
+func (elem *Element) GetTime() (time.Time, bool) {
+	strs, ok := elem.GetStrings()
+	if !ok || len(strs) == 0 {
+		return time.Time{}, false
+	}
+	t, err := module.ParseTime(strs[0])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Time{}.Add(t.Duration()), true
+}
+
+// GetSequence returns the items of an SQ element
+func (elem *Element) GetSequence() ([]*Dataset, bool) {
+	items, ok := elem.Value.([]*Dataset)
+	return items, ok
+}
+
+// GetTags returns the tags held by an AT element
+func (elem *Element) GetTags() ([]Tag, bool) {
+	switch v := elem.Value.(type) {
+	case Tag:
+		return []Tag{v}, true
+	case []Tag:
+		return v, true
+	}
 	return nil, false
 }
 
@@ -0,0 +1,73 @@
+package dicos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElement_TypedAccessors(t *testing.T) {
+	item, err := NewDataset(WithElement(tag.ReferencedSOPInstanceUID, "1.2.3"))
+	require.NoError(t, err)
+	ds, err := NewDataset(
+		WithFileMeta(CTImageStorageUID, "1.2.3.4", string(ExplicitVRLittleEndian)),
+		WithElement(tag.PixelSpacing, `0.5\ 1.25 `),
+		WithElement(tag.ImageType, `ORIGINAL\PRIMARY\AXIAL`),
+		WithElement(tag.NumberOfFrames, "12"),
+		withVR(tag.FrameIncrementPointer, "US", []uint16{1, 2}),
+		withVR(tag.BoundingPolygon, "FL", []float32{0.5, -2}),
+		WithElement(tag.StudyDate, "20240229"),
+		WithElement(tag.StudyTime, "134501.25"),
+		withVR(tag.AcquisitionDateTime, "DT", "20240229134501.5+0100"),
+		WithSequence(tag.ReferencedImageSequence, item),
+	)
+	require.NoError(t, err)
+	ds = rewrite(t, ds)
+
+	floats, ok := ds.Elements[tag.PixelSpacing].GetFloats()
+	require.True(t, ok)
+	assert.Equal(t, []float64{0.5, 1.25}, floats)
+	floats, ok = ds.Elements[tag.BoundingPolygon].GetFloats()
+	require.True(t, ok, "FL read back as bytes")
+	assert.Equal(t, []float64{0.5, -2}, floats)
+	_, ok = ds.Elements[tag.ImageType].GetFloats()
+	assert.False(t, ok)
+
+	strs, ok := ds.Elements[tag.ImageType].GetStrings()
+	require.True(t, ok)
+	assert.Equal(t, []string{"ORIGINAL", "PRIMARY", "AXIAL"}, strs)
+	strs, ok = ds.Elements[tag.FrameIncrementPointer].GetStrings()
+	require.True(t, ok)
+	assert.Equal(t, []string{"1", "2"}, strs)
+
+	uints, ok := ds.Elements[tag.FrameIncrementPointer].GetUints()
+	require.True(t, ok)
+	assert.Equal(t, []uint32{1, 2}, uints)
+	uints, ok = ds.Elements[tag.NumberOfFrames].GetUints()
+	require.True(t, ok)
+	assert.Equal(t, []uint32{12}, uints)
+	_, ok = ds.Elements[tag.BoundingPolygon].GetUints()
+	assert.False(t, ok)
+
+	date, ok := ds.Elements[tag.StudyDate].GetDate()
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), date)
+	tm, ok := ds.Elements[tag.StudyTime].GetTime()
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, 2, 29, 13, 45, 1, 250e6, time.UTC), date.Add(tm.Sub(time.Time{})))
+	dt, ok := ds.Elements[tag.AcquisitionDateTime].GetDate()
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, 2, 29, 12, 45, 1, 500e6, time.UTC), dt.UTC())
+	_, ok = ds.Elements[tag.ImageType].GetDate()
+	assert.False(t, ok)
+
+	items, ok := ds.Elements[tag.ReferencedImageSequence].GetSequence()
+	require.True(t, ok)
+	require.Len(t, items, 1)
+	assert.Equal(t, "1.2.3", attrString(items[0], tag.ReferencedSOPInstanceUID))
+	_, ok = ds.Elements[tag.StudyDate].GetSequence()
+	assert.False(t, ok)
+}
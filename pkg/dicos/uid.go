@@ -0,0 +1,67 @@
+package dicos
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxUIDLength is the longest UID PS3.5 9.1 allows
+const maxUIDLength = 64
+
+// ErrInvalidUID is matched by errors.Is when a UID breaks the syntax of
+// PS3.5 9.1
+var ErrInvalidUID = errors.New("invalid UID")
+
+// ValidateUID checks s against the UID syntax of PS3.5 9.1: at most 64
+// characters of numeric components separated by periods, none empty and none
+// with a leading zero other than the component "0". Padding is not allowed;
+// see NormalizeUID. Failures wrap ErrInvalidUID.
+//
+// Example:
+//
+//	dicos.ValidateUID("1.2.840.10008.1.2.1") // nil
+//	dicos.ValidateUID("1.2.03")              // leading zero in component 3
+func ValidateUID(s string) error {
+	if s == "" {
+		return fmt.Errorf("%w: empty", ErrInvalidUID)
+	}
+	if len(s) > maxUIDLength {
+		return fmt.Errorf("%w: %d characters, at most %d", ErrInvalidUID, len(s), maxUIDLength)
+	}
+	for i, c := range strings.Split(s, ".") {
+		switch {
+		case c == "":
+			return fmt.Errorf("%w: %q has an empty component %d", ErrInvalidUID, s, i+1)
+		case strings.Trim(c, "0123456789") != "":
+			return fmt.Errorf("%w: %q has a non-numeric component %d", ErrInvalidUID, s, i+1)
+		case len(c) > 1 && c[0] == '0':
+			return fmt.Errorf("%w: %q has a leading zero in component %d", ErrInvalidUID, s, i+1)
+		}
+	}
+	return nil
+}
+
+// NormalizeUID strips the padding vendors leave around a UID: the NUL of an
+// even length value, and spaces or NULs on either side
+func NormalizeUID(s string) string {
+	return strings.Trim(s, " \x00")
+}
+
+// normalizeUIDs normalizes every value of a UI element, which may hold
+// several separated by backslashes, and returns the normalized element value
+// with the errors of its invalid UIDs. An empty value is left alone.
+func normalizeUIDs(v string) (string, []error) {
+	if NormalizeUID(v) == "" {
+		return "", nil
+	}
+	values := strings.Split(v, `\`)
+	var errs []error
+	for i, s := range values {
+		values[i] = NormalizeUID(s)
+		if err := ValidateUID(values[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return strings.Join(values, `\`), errs
+}
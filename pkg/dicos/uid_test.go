@@ -0,0 +1,174 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateUID(t *testing.T) {
+	for _, tc := range []struct {
+		uid  string
+		want string
+	}{
+		{uid: "1.2.840.10008.1.2.1"},
+		{uid: "0.1.0"},
+		{uid: "1.2." + strings.Repeat("9", 60)},
+		{uid: GenerateUID("1.2.826.0.1.3680043.8.498.")},
+		{uid: "", want: "empty"},
+		{uid: "1.2." + strings.Repeat("9", 61), want: "65 characters"},
+		{uid: "1.2.03", want: "leading zero in component 3"},
+		{uid: "0041811861.1", want: "leading zero in component 1"},
+		{uid: "1..2", want: "empty component 2"},
+		{uid: "1.2.", want: "empty component 3"},
+		{uid: "1.2a.3", want: "non-numeric component 2"},
+		{uid: "1.2.3 ", want: "non-numeric component 3"},
+	} {
+		err := ValidateUID(tc.uid)
+		if tc.want == "" {
+			assert.NoError(t, err, tc.uid)
+			continue
+		}
+		assert.ErrorIs(t, err, ErrInvalidUID, tc.uid)
+		assert.ErrorContains(t, err, tc.want, tc.uid)
+	}
+}
+
+func TestNormalizeUID(t *testing.T) {
+	assert.Equal(t, "1.2.3", NormalizeUID("1.2.3\x00"))
+	assert.Equal(t, "1.2.3", NormalizeUID(" 1.2.3 "))
+	v, errs := normalizeUIDs(" 1.2.3\\1.2.4\x00")
+	assert.Equal(t, `1.2.3\1.2.4`, v)
+	assert.Empty(t, errs)
+	v, errs = normalizeUIDs("\x00")
+	assert.Equal(t, "", v)
+	assert.Empty(t, errs)
+	_, errs = normalizeUIDs(`1.2.3\1.02`)
+	assert.Len(t, errs, 1)
+}
+
+// TestReader_NormalizesUIDs checks that UI values are read without their
+// padding, and that invalid UIDs are reported as parse issues
+func TestReader_NormalizesUIDs(t *testing.T) {
+	write := func(instance, referenced string) []byte {
+		ds, err := NewDataset(
+			WithFileMeta(DICOSCTImageStorageUID, "1.2.3.4", string(ExplicitVRLittleEndian)),
+			WithElement(tag.SOPInstanceUID, instance),
+			WithElement(tag.FrameOfReferenceUID, referenced),
+		)
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		_, err = Write(&buf, ds)
+		require.NoError(t, err)
+		return buf.Bytes()
+	}
+
+	data := write(" 1.2.3.4 ", "1.2.5\x00")
+	ds, issues, err := ParseWithIssues(context.Background(), bytes.NewReader(data), ParseOptions{Strict: true})
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+	assert.Equal(t, "1.2.3.4", stringValue(ds, tag.SOPInstanceUID))
+	assert.Equal(t, "1.2.5", stringValue(ds, tag.FrameOfReferenceUID))
+
+	// Permissive: the invalid UID is kept and reported
+	data = write("1.2.03.4", "1.2.5")
+	ds, issues, err = ParseWithIssues(context.Background(), bytes.NewReader(data), ParseOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.03.4", stringValue(ds, tag.SOPInstanceUID))
+	require.Len(t, issues, 1)
+	assert.Equal(t, tag.SOPInstanceUID, issues[0].Tag)
+	assert.ErrorIs(t, issues[0].Err, ErrInvalidUID)
+
+	// Strict: it fails the parse
+	_, _, err = ParseWithIssues(context.Background(), bytes.NewReader(data), ParseOptions{Strict: true})
+	var issue ParseIssue
+	require.True(t, errors.As(err, &issue))
+	assert.Equal(t, tag.SOPInstanceUID, issue.Tag)
+
+	// and the validator reports it
+	result := ValidateDataset(ds, nil)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, tag.SOPInstanceUID, result.Errors[0].Tag)
+	assert.True(t, result.Errors[0].IsCritical)
+}
+
+func TestUIDFactory(t *testing.T) {
+	f, err := NewUIDFactory("1.2.840.99999.7.")
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.840.99999.7", f.Root())
+	seen := make(map[string]bool)
+	for range 1000 {
+		uid := f.New()
+		require.NoError(t, ValidateUID(uid), uid)
+		require.True(t, strings.HasPrefix(uid, "1.2.840.99999.7."), uid)
+		require.False(t, seen[uid], "duplicate %s", uid)
+		seen[uid] = true
+	}
+
+	f, err = NewUIDFactory("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultUIDRoot, f.Root())
+
+	_, err = NewUIDFactory("1.2.03")
+	assert.ErrorIs(t, err, ErrInvalidUID)
+	_, err = NewUIDFactory("1.2." + strings.Repeat("9", 42))
+	assert.ErrorContains(t, err, "at most 43")
+}
+
+func TestUIDFactory_Deterministic(t *testing.T) {
+	a, err := NewDeterministicUIDFactory("1.2.3", "golden")
+	require.NoError(t, err)
+	b, err := NewDeterministicUIDFactory("1.2.3", "golden")
+	require.NoError(t, err)
+	other, err := NewDeterministicUIDFactory("1.2.3", "other")
+	require.NoError(t, err)
+	first := a.New()
+	assert.Equal(t, first, b.New())
+	assert.Equal(t, a.New(), b.New())
+	assert.NotEqual(t, first, a.New(), "a sequence, not a constant")
+	assert.NotEqual(t, first, other.New())
+	require.NoError(t, ValidateUID(first))
+
+	// content UIDs ignore the seed and the sequence
+	content := a.FromContent([]byte("bag"), []byte("0001"))
+	assert.Equal(t, content, other.FromContent([]byte("bag"), []byte("0001")))
+	assert.NotEqual(t, content, a.FromContent([]byte("bag0"), []byte("001")))
+	require.NoError(t, ValidateUID(content))
+	assert.Len(t, content, len("1.2.3.")+38)
+}
+
+func TestSetUIDFactory(t *testing.T) {
+	f, err := NewDeterministicUIDFactory("1.2.840.99999.7", t.Name())
+	require.NoError(t, err)
+	defer SetUIDFactory(SetUIDFactory(f))
+
+	ct, dx, tdr := NewCTImage(), NewDXImage(), NewThreatDetectionReport()
+	ait2d, ait3d := NewAIT2DImage(), NewAIT3DImage()
+	for _, uid := range []string{
+		ct.Study.StudyInstanceUID, ct.Series.SeriesInstanceUID, ct.SOPCommon.SOPInstanceUID, ct.FrameOfReference.FrameOfReferenceUID,
+		dx.Study.StudyInstanceUID, dx.Series.SeriesInstanceUID, dx.SOPCommon.SOPInstanceUID,
+		tdr.Series.SeriesInstanceUID, tdr.SOPCommon.SOPInstanceUID,
+		ait2d.Study.StudyInstanceUID, ait2d.SOPCommon.SOPInstanceUID,
+		ait3d.Series.SeriesInstanceUID, ait3d.FrameOfReference.FrameOfReferenceUID,
+		GenerateUID("1.2.3."),
+	} {
+		require.NoError(t, ValidateUID(uid), uid)
+	}
+	assert.True(t, strings.HasPrefix(ct.SOPCommon.SOPInstanceUID, "1.2.840.99999.7."))
+	assert.True(t, strings.HasPrefix(GenerateUID("1.2.3."), "1.2.3."))
+
+	// the same seed replays the same UIDs
+	f, err = NewDeterministicUIDFactory("1.2.840.99999.7", t.Name())
+	require.NoError(t, err)
+	SetUIDFactory(f)
+	assert.Equal(t, ct.Study.StudyInstanceUID, NewCTImage().Study.StudyInstanceUID)
+
+	SetUIDFactory(nil)
+	assert.True(t, strings.HasPrefix(NewUID(), DefaultUIDRoot+"."))
+}
@@ -1,26 +1,195 @@
 package dicos
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
 	"fmt"
-	"math/rand"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
-// GenerateUID generates a DICOM unique identifier (UID)
-// using a prefix and unique components (time, random).
-// Format: prefix.<timestamp>.<random>
-func GenerateUID(prefix string) string {
-	now := time.Now()
-	// Simple UID generation strategy
-	// 20060102150405 + .nanoseconds + .random
-	timestamp := now.Format("20060102150405")
-	nano := now.Nanosecond()
-	rnd := rand.Intn(10000)
+// DefaultUIDRoot is the root of generated UIDs when no organization root is
+// configured. It is a public test root; products should register their own
+// (PS3.5 9.2) and pass it to NewUIDFactory.
+const DefaultUIDRoot = "1.2.826.0.1.3680043.8.498"
+
+// minUIDSuffix is the fewest decimal digits of entropy a generated UID
+// keeps after its root
+const minUIDSuffix = 20
+
+// UIDFactory generates UIDs under an organization root. The suffix is a
+// decimal digest of the host, process and time with a counter, so factories
+// on different hosts or in different processes never meet. A factory with a
+// seed derives its sequence from the seed alone, for reproducible output.
+// A UIDFactory is safe for concurrent use.
+//
+// Example:
+//
+//	f, err := dicos.NewUIDFactory("1.2.840.99999.7") // your registered root
+//	dicos.SetUIDFactory(f)                         // used by every constructor
+//	ct := dicos.NewCTImage()                       // UIDs under 1.2.840.99999.7
+type UIDFactory struct {
+	root  string
+	seed  []byte // nil for host/process entropy
+	count atomic.Uint64
+}
+
+// NewUIDFactory returns a factory generating UIDs under root, DefaultUIDRoot
+// when empty. The root must be a valid UID short enough to leave room for the
+// generated suffix.
+func NewUIDFactory(root string) (*UIDFactory, error) {
+	root = strings.TrimSuffix(root, ".")
+	if root == "" {
+		root = DefaultUIDRoot
+	}
+	if err := ValidateUID(root); err != nil {
+		return nil, fmt.Errorf("UID root: %w", err)
+	}
+	if len(root)+1+minUIDSuffix > maxUIDLength {
+		return nil, fmt.Errorf("UID root %q is %d characters, at most %d", root, len(root), maxUIDLength-1-minUIDSuffix)
+	}
+	return &UIDFactory{root: root}, nil
+}
+
+// NewDeterministicUIDFactory returns a factory whose sequence of UIDs depends
+// only on root and seed, so tests and golden files see the same UIDs on
+// every run. Its UIDs are unique only within the sequence; do not use one for
+// data that leaves the test.
+func NewDeterministicUIDFactory(root, seed string) (*UIDFactory, error) {
+	f, err := NewUIDFactory(root)
+	if err != nil {
+		return nil, err
+	}
+	f.seed = []byte(seed)
+	return f, nil
+}
+
+// Root returns the organization root of the factory
+func (f *UIDFactory) Root() string {
+	return f.root
+}
+
+// New returns the next UID
+func (f *UIDFactory) New() string {
+	return f.under(f.root)
+}
+
+// FromContent returns a UID derived only from the root and parts, so the
+// same content always gets the same UID, e.g. an instance UID computed from
+// the pixel data it holds, or a replacement UID computed from the original
+func (f *UIDFactory) FromContent(parts ...[]byte) string {
+	h := sha256.New()
+	h.Write([]byte("content"))
+	writeParts(h, parts)
+	return formatUID(f.root, h.Sum(nil))
+}
+
+// under returns the next UID below root
+func (f *UIDFactory) under(root string) string {
+	n := f.count.Add(1)
+	h := sha256.New()
+	if f.seed != nil {
+		h.Write([]byte("seed"))
+		writeParts(h, [][]byte{f.seed})
+	} else {
+		h.Write(nodeEntropy())
+		binary.Write(h, binary.BigEndian, time.Now().UnixNano())
+	}
+	binary.Write(h, binary.BigEndian, n)
+	return formatUID(root, h.Sum(nil))
+}
+
+// formatUID appends the digest to root as the longest decimal component that
+// fits in a UID
+func formatUID(root string, digest []byte) string {
+	digits := maxUIDLength - len(root) - 1
+	if digits > 38 { // a SHA-256 digest keeps its full entropy in 38 digits
+		digits = 38
+	}
+	mod := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(digits)), nil)
+	suffix := new(big.Int).Mod(new(big.Int).SetBytes(digest), mod)
+	return root + "." + suffix.String()
+}
+
+// writeParts hashes each part with its length so adjacent parts cannot run
+// together
+func writeParts(h io.Writer, parts [][]byte) {
+	for _, p := range parts {
+		binary.Write(h, binary.BigEndian, uint64(len(p)))
+		h.Write(p)
+	}
+}
+
+// nodeEntropy identifies this process on this host: the host name, the
+// process id and random bytes drawn once per process
+var nodeEntropy = sync.OnceValue(func() []byte {
+	host, _ := os.Hostname()
+	b := make([]byte, 0, len(host)+8+16)
+	b = append(b, host...)
+	b = binary.BigEndian.AppendUint64(b, uint64(os.Getpid()))
+	random := make([]byte, 16)
+	rand.Read(random)
+	return append(b, random...)
+})
+
+var (
+	uidFactoryMu sync.RWMutex
+	uidFactory   = &UIDFactory{root: DefaultUIDRoot}
+)
 
-	// Ensure prefix ends with dot
-	if len(prefix) > 0 && prefix[len(prefix)-1] != '.' {
-		prefix += "."
+// SetUIDFactory sets the factory behind NewUID, GenerateUID and every
+// constructor that fills in UIDs, and returns the previous one so a test can
+// restore it. A nil f restores the default under DefaultUIDRoot.
+//
+// Example:
+//
+//	f, _ := dicos.NewDeterministicUIDFactory("", t.Name())
+//	defer dicos.SetUIDFactory(dicos.SetUIDFactory(f))
+func SetUIDFactory(f *UIDFactory) *UIDFactory {
+	if f == nil {
+		f = &UIDFactory{root: DefaultUIDRoot}
 	}
+	uidFactoryMu.Lock()
+	defer uidFactoryMu.Unlock()
+	prev := uidFactory
+	uidFactory = f
+	return prev
+}
+
+// CurrentUIDFactory returns the factory set by SetUIDFactory
+func CurrentUIDFactory() *UIDFactory {
+	uidFactoryMu.RLock()
+	defer uidFactoryMu.RUnlock()
+	return uidFactory
+}
+
+// NewUID returns the next UID of the current factory, under its root
+func NewUID() string {
+	return CurrentUIDFactory().New()
+}
 
-	return fmt.Sprintf("%s%s.%d.%d", prefix, timestamp, nano, rnd)
+// fillUIDs sets each empty UID to the next UID of the current factory
+func fillUIDs(uids ...*string) {
+	for _, u := range uids {
+		if *u == "" {
+			*u = NewUID()
+		}
+	}
+}
+
+// GenerateUID generates a DICOM unique identifier (UID) under prefix with the
+// entropy, or the seed, of the current factory. Prefer NewUID, which uses the
+// factory's organization root.
+func GenerateUID(prefix string) string {
+	root := strings.TrimSuffix(prefix, ".")
+	if root == "" {
+		return NewUID()
+	}
+	return CurrentUIDFactory().under(root)
 }
@@ -2,6 +2,7 @@ package dicos
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
 )
@@ -35,7 +36,12 @@ func (e ValidationError) Error() string {
 }
 
 func (e ValidationError) typeName() string {
-	switch e.Type {
+	return e.Type.String()
+}
+
+// String returns the type as written in PS3.3, e.g. "Type 1C"
+func (t AttributeType) String() string {
+	switch t {
 	case Type1:
 		return "Type 1"
 	case Type1C:
@@ -171,9 +177,11 @@ type IODRequirement struct {
 	Tag       tag.Tag
 	Type      AttributeType
 	Condition func(*Dataset) bool // For Type 1C/2C, returns true if attribute is required
+	Values    []string            // Enumerated values, checked whenever the attribute has a value
 }
 
-// ValidateDataset validates a dataset against a set of requirements
+// ValidateDataset validates a dataset against a set of requirements, and
+// every UID in it against ValidateUID
 func ValidateDataset(ds *Dataset, requirements []IODRequirement) ValidationResult {
 	result := ValidationResult{}
 
@@ -240,6 +248,52 @@ func ValidateDataset(ds *Dataset, requirements []IODRequirement) ValidationResul
 		case Type3:
 			// Optional - no validation needed
 		}
+
+		if len(req.Values) > 0 && exists {
+			if err := checkEnumerated(elem, req.Values); err != nil {
+				result.Errors = append(result.Errors, ValidationError{
+					Tag:        req.Tag,
+					Type:       req.Type,
+					Message:    err.Error(),
+					IsCritical: true,
+				})
+			}
+		}
+	}
+
+	// UIDs must be well formed wherever they appear, or receivers reject them
+	types := make(map[tag.Tag]AttributeType, len(requirements))
+	for _, req := range requirements {
+		types[req.Tag] = req.Type
+	}
+	for _, t := range sortedTags(ds) {
+		elem := ds.Elements[t]
+		v, ok := elem.Value.(string)
+		if elem.VR != "UI" || !ok || v == "" {
+			continue
+		}
+		typ, ok := types[t]
+		if !ok {
+			typ = Type3
+		}
+		for _, uid := range strings.Split(v, `\`) {
+			if err := ValidateUID(uid); err != nil {
+				result.Errors = append(result.Errors, ValidationError{
+					Tag:        t,
+					Type:       typ,
+					Message:    err.Error(),
+					IsCritical: true,
+				})
+			}
+		}
+	}
+
+	// DA and TM values in their DICOM form, and pixel attributes that agree
+	for _, e := range append(checkDateTimes(ds), checkImagePixel(ds)...) {
+		if typ, ok := types[e.Tag]; ok {
+			e.Type = typ
+		}
+		result.Errors = append(result.Errors, e)
 	}
 
 	return result
@@ -289,7 +343,7 @@ var GeneralSeriesModuleRequirements = []IODRequirement{
 // ImagePixelModuleRequirements defines required attributes for Image Pixel Module
 var ImagePixelModuleRequirements = []IODRequirement{
 	{Tag: tag.SamplesPerPixel, Type: Type1},
-	{Tag: tag.PhotometricInterpretation, Type: Type1},
+	{Tag: tag.PhotometricInterpretation, Type: Type1, Values: PhotometricInterpretations},
 	{Tag: tag.Rows, Type: Type1},
 	{Tag: tag.Columns, Type: Type1},
 	{Tag: tag.BitsAllocated, Type: Type1},
@@ -326,11 +380,103 @@ var DXImageRequirements = append(append(append(append(
 	SOPCommonModuleRequirements...)
 
 // TDRRequirements combines all requirements for TDR IOD
-var TDRRequirements = append(append(append(
+var TDRRequirements = append(append(append(append(
 	PatientModuleRequirements,
 	GeneralStudyModuleRequirements...),
 	GeneralSeriesModuleRequirements...),
-	SOPCommonModuleRequirements...)
+	SOPCommonModuleRequirements...),
+	IODRequirement{Tag: tag.AlarmDecision, Type: Type3, Values: AlarmDecisions},
+)
+
+// QRAcquisitionModuleRequirements defines required attributes for the QR
+// Acquisition Module
+var QRAcquisitionModuleRequirements = []IODRequirement{
+	{Tag: tag.ResonantNucleus, Type: Type1},
+	{Tag: tag.TransmitterFrequency, Type: Type1},
+	{Tag: tag.AlarmDecision, Type: Type2, Values: AlarmDecisions},
+}
+
+// QRRequirements combines all requirements for QR IOD
+var QRRequirements = append(append(append(append(
+	PatientModuleRequirements,
+	GeneralStudyModuleRequirements...),
+	GeneralSeriesModuleRequirements...),
+	SOPCommonModuleRequirements...),
+	QRAcquisitionModuleRequirements...)
+
+// conditional returns reqs as Type 1C and 2C requirements that apply when
+// cond holds, for a module that is only present in some representations
+func conditional(reqs []IODRequirement, cond func(*Dataset) bool) []IODRequirement {
+	out := make([]IODRequirement, len(reqs))
+	for i, r := range reqs {
+		switch r.Type {
+		case Type1:
+			r.Type = Type1C
+		case Type2:
+			r.Type = Type2C
+		}
+		r.Condition = cond
+		out[i] = r
+	}
+	return out
+}
+
+// hasVolume reports whether an AIT 3D dataset carries a voxel volume: one
+// with pixel data, or one without surfaces, which must then have a volume
+func hasVolume(ds *Dataset) bool {
+	return HasElement(ds, tag.PixelData) || !HasElement(ds, tag.SurfaceSequence)
+}
+
+// AITEquipmentRequirements defines required attributes of the General
+// Equipment Module of the AIT IODs, which identify the scanner
+var AITEquipmentRequirements = []IODRequirement{
+	{Tag: tag.Manufacturer, Type: Type2},
+}
+
+// AIT2DImageRequirements combines all requirements for AIT 2D Image IOD
+var AIT2DImageRequirements = append(append(append(append(append(append(
+	PatientModuleRequirements,
+	GeneralStudyModuleRequirements...),
+	GeneralSeriesModuleRequirements...),
+	AITEquipmentRequirements...),
+	ImagePixelModuleRequirements...),
+	SOPCommonModuleRequirements...),
+	// color renderings
+	IODRequirement{Tag: tag.PlanarConfiguration, Type: Type1C, Condition: func(ds *Dataset) bool {
+		return attrInt(ds, tag.SamplesPerPixel) > 1
+	}},
+)
+
+// AIT3DVolumeRequirements defines the Image Pixel, multi-frame and Image
+// Plane attributes of an AIT 3D voxel volume
+var AIT3DVolumeRequirements = append(append(
+	[]IODRequirement{},
+	ImagePixelModuleRequirements...),
+	IODRequirement{Tag: tag.NumberOfFrames, Type: Type1},
+	IODRequirement{Tag: tag.PixelSpacing, Type: Type1},
+	IODRequirement{Tag: tag.ImageOrientationPatient, Type: Type1},
+	IODRequirement{Tag: tag.ImagePositionPatient, Type: Type1},
+)
+
+// AIT3DSurfaceRequirements defines required attributes of the Surface Mesh
+// Module of an AIT 3D image
+var AIT3DSurfaceRequirements = []IODRequirement{
+	{Tag: tag.NumberOfSurfaces, Type: Type1},
+}
+
+// AIT3DImageRequirements combines all requirements for AIT 3D Image IOD. An
+// AIT 3D image is a voxel volume, surfaces, or both: the volume attributes
+// are required unless the image holds only surfaces, and the Surface Mesh
+// attributes when it has any.
+var AIT3DImageRequirements = append(append(append(append(append(append(append(
+	PatientModuleRequirements,
+	GeneralStudyModuleRequirements...),
+	GeneralSeriesModuleRequirements...),
+	AITEquipmentRequirements...),
+	SOPCommonModuleRequirements...),
+	IODRequirement{Tag: tag.FrameOfReferenceUID, Type: Type1}),
+	conditional(AIT3DVolumeRequirements, hasVolume)...),
+	conditional(AIT3DSurfaceRequirements, func(ds *Dataset) bool { return HasElement(ds, tag.SurfaceSequence) })...)
 
 // ValidateCT validates a CT Image dataset
 func ValidateCT(ds *Dataset) ValidationResult {
@@ -346,3 +492,80 @@ func ValidateDX(ds *Dataset) ValidationResult {
 func ValidateTDR(ds *Dataset) ValidationResult {
 	return ValidateDataset(ds, TDRRequirements)
 }
+
+// ValidateQR validates a QR measurement dataset
+func ValidateQR(ds *Dataset) ValidationResult {
+	return ValidateDataset(ds, QRRequirements)
+}
+
+// ValidateAIT2D validates an AIT 2D Image dataset
+func ValidateAIT2D(ds *Dataset) ValidationResult {
+	return ValidateDataset(ds, AIT2DImageRequirements)
+}
+
+// ValidateAIT3D validates an AIT 3D Image dataset, as a voxel volume,
+// surfaces or both
+func ValidateAIT3D(ds *Dataset) ValidationResult {
+	return ValidateDataset(ds, AIT3DImageRequirements)
+}
+
+// iodRequirements are the requirements checked by the Validate function of
+// each IOD, see SupportedIODs
+var iodRequirements = map[string][]IODRequirement{
+	"CT":    CTImageRequirements,
+	"DX":    DXImageRequirements,
+	"TDR":   TDRRequirements,
+	"QR":    QRRequirements,
+	"AIT2D": AIT2DImageRequirements,
+	"AIT3D": AIT3DImageRequirements,
+}
+
+// validatedSOPClasses maps the SOP classes found in files, DICOM and DICOS,
+// to the IOD whose requirements validate them
+var validatedSOPClasses = map[string]string{
+	CTImageStorageUID:         "CT",
+	DICOSCTImageStorageUID:    "CT",
+	DXImageStorageUID:         "DX",
+	DICOSDXImageStorageUID:    "DX",
+	DICOSDXForPresentationUID: "DX",
+	TDRStorageUID:             "TDR",
+	DICOSTDRStorageUID:        "TDR",
+	DICOSQRStorageUID:         "QR",
+	DICOSAIT2DImageStorageUID: "AIT2D",
+	DICOSAIT3DImageStorageUID: "AIT3D",
+}
+
+// ValidateIOD validates ds against the requirements of the IOD named by its
+// SOPClassUID, and returns that IOD's name. It fails when the SOP class has
+// no requirements, such as Secondary Capture.
+//
+// Example:
+//
+//	iod, result, err := dicos.ValidateIOD(ds)
+//	if err == nil && !result.IsValid() {
+//		fmt.Println(iod, result)
+//	}
+func ValidateIOD(ds *Dataset) (string, ValidationResult, error) {
+	uid := strings.TrimSpace(stringValue(ds, tag.SOPClassUID))
+	iod, ok := validatedSOPClasses[uid]
+	if !ok {
+		return "", ValidationResult{}, fmt.Errorf("no validation rules for SOP class %q", uid)
+	}
+	return iod, ValidateDataset(ds, iodRequirements[iod]), nil
+}
+
+// Validate validates ds against the requirements of the IOD named by its
+// SOPClassUID, as ValidateIOD does. A SOP class without requirements is
+// reported as an error on the SOPClassUID.
+func Validate(ds *Dataset) ValidationResult {
+	_, result, err := ValidateIOD(ds)
+	if err != nil {
+		result.Errors = append(result.Errors, ValidationError{
+			Tag:        tag.SOPClassUID,
+			Type:       Type1,
+			Message:    err.Error(),
+			IsCritical: true,
+		})
+	}
+	return result
+}
@@ -0,0 +1,131 @@
+package dicos
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/module"
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// PhotometricInterpretations are the enumerated values of
+// PhotometricInterpretation (PS3.3 C.7.6.3.1.2)
+var PhotometricInterpretations = []string{
+	"MONOCHROME1", "MONOCHROME2", "PALETTE COLOR", "RGB",
+	"YBR_FULL", "YBR_FULL_422", "YBR_PARTIAL_420", "YBR_ICT", "YBR_RCT",
+}
+
+// AlarmDecisions are the enumerated values of AlarmDecision
+var AlarmDecisions = []string{"ALARM", "NO_ALARM", "UNKNOWN"}
+
+// checkEnumerated returns an error naming the first value of elem that is
+// not one of values
+func checkEnumerated(elem *Element, values []string) error {
+	s, ok := elem.Value.(string)
+	if !ok || s == "" {
+		return nil
+	}
+	for _, v := range strings.Split(s, `\`) {
+		v = strings.TrimSpace(v)
+		if !slices.Contains(values, v) {
+			return fmt.Errorf("value %q is not one of %s", v, strings.Join(values, ", "))
+		}
+	}
+	return nil
+}
+
+// checkDateTimes reports DA values that are not YYYYMMDD and TM values that
+// are not HHMMSS.FFFFFF or a prefix of it. The ACR-NEMA forms, YYYY.MM.DD
+// and HH:MM:SS, are read but are not valid in a DICOM dataset.
+func checkDateTimes(ds *Dataset) []ValidationError {
+	var errs []ValidationError
+	for _, t := range sortedTags(ds) {
+		elem := ds.Elements[t]
+		s, ok := elem.Value.(string)
+		if !ok || (elem.VR != "DA" && elem.VR != "TM") {
+			continue
+		}
+		for _, v := range strings.Split(s, `\`) {
+			v = strings.TrimSpace(v)
+			if v == "" {
+				continue
+			}
+			var err error
+			switch {
+			case elem.VR == "DA" && (len(v) != 8 || strings.Contains(v, ".")):
+				err = fmt.Errorf("invalid DA %q: want YYYYMMDD", v)
+			case elem.VR == "DA":
+				_, err = module.ParseDate(v)
+			case strings.Contains(v, ":"):
+				err = fmt.Errorf("invalid TM %q: want HHMMSS.FFFFFF", v)
+			default:
+				_, err = module.ParseTime(v)
+			}
+			if err != nil {
+				errs = append(errs, ValidationError{Tag: t, Type: Type3, Message: err.Error(), IsCritical: true})
+				break
+			}
+		}
+	}
+	return errs
+}
+
+// checkImagePixel reports Image Pixel attributes that disagree: BitsStored
+// above BitsAllocated, a HighBit other than BitsStored-1, and native pixel
+// data whose length is not Rows*Columns*NumberOfFrames*SamplesPerPixel
+// samples of BitsAllocated bits
+func checkImagePixel(ds *Dataset) []ValidationError {
+	var errs []ValidationError
+	fail := func(t tag.Tag, format string, args ...any) {
+		errs = append(errs, ValidationError{Tag: t, Type: Type3, Message: fmt.Sprintf(format, args...), IsCritical: true})
+	}
+
+	allocated, hasAllocated := intValue(ds, tag.BitsAllocated)
+	stored, hasStored := intValue(ds, tag.BitsStored)
+	if hasAllocated && hasStored && (stored < 1 || stored > allocated) {
+		fail(tag.BitsStored, "bits stored %d is not between 1 and bits allocated %d", stored, allocated)
+	}
+	if highBit, ok := intValue(ds, tag.HighBit); ok && hasStored && highBit != stored-1 {
+		fail(tag.HighBit, "high bit %d is not bits stored %d - 1", highBit, stored)
+	}
+
+	elem, ok := ds.Elements[tag.PixelData]
+	rows, cols := ds.Rows(), ds.Columns()
+	if !ok || rows <= 0 || cols <= 0 || !hasAllocated {
+		return errs
+	}
+	frames, samples := max(ds.NumberOfFrames(), 1), max(ds.SamplesPerPixel(), 1)
+	want := rows * cols * frames * samples
+	describe := fmt.Sprintf("%dx%d, %d frames of %d samples", cols, rows, frames, samples)
+	var got int
+	switch v := elem.Value.(type) {
+	case *PixelData:
+		if v.IsEncapsulated {
+			return errs
+		}
+		if got = v.TotalPixels(); got != want {
+			fail(tag.PixelData, "%d samples, want %d for %s", got, want, describe)
+		}
+		return errs
+	case []byte:
+		got = len(v)
+	case []uint16:
+		got = 2 * len(v)
+	default:
+		return errs
+	}
+	wantBytes := (want*allocated + 7) / 8
+	if got != wantBytes && got != wantBytes+wantBytes%2 {
+		fail(tag.PixelData, "%d bytes, want %d for %s", got, wantBytes, describe)
+	}
+	return errs
+}
+
+// intValue returns the integer value of t in ds, if it has one
+func intValue(ds *Dataset, t Tag) (int, bool) {
+	if elem, ok := ds.FindElement(t.Group, t.Element); ok {
+		return elem.GetInt()
+	}
+	return 0, false
+}
@@ -0,0 +1,99 @@
+package dicos
+
+import (
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validCT is a CT with pixel data that passes ValidateCT
+func validCT(t *testing.T) *Dataset {
+	t.Helper()
+	ct := NewCTImage()
+	ct.Series.Modality = "CT"
+	ct.SetPixelData(4, 4, make([]uint16, 16))
+	ds, err := ct.GetDataset()
+	require.NoError(t, err)
+	result := ValidateCT(ds)
+	require.True(t, result.IsValid(), result.String())
+	return ds
+}
+
+// errorTags returns the tags of the errors in result
+func errorTags(result ValidationResult) []tag.Tag {
+	var tags []tag.Tag
+	for _, e := range result.Errors {
+		tags = append(tags, e.Tag)
+	}
+	return tags
+}
+
+func TestValidate_EnumeratedValues(t *testing.T) {
+	ds := validCT(t)
+	ds.Elements[tag.PhotometricInterpretation].Value = "MONOCHROME3"
+	result := ValidateCT(ds)
+	require.False(t, result.IsValid())
+	assert.Equal(t, []tag.Tag{tag.PhotometricInterpretation}, errorTags(result))
+	assert.Contains(t, result.Errors[0].Message, `"MONOCHROME3" is not one of MONOCHROME1, MONOCHROME2`)
+
+	tdr := NewThreatDetectionReport()
+	tdr.AlarmDecision = "MAYBE"
+	ds, err := tdr.GetDataset()
+	require.NoError(t, err)
+	assert.Contains(t, errorTags(ValidateTDR(ds)), tag.AlarmDecision)
+	ds.Elements[tag.AlarmDecision].Value = "NO_ALARM"
+	assert.NotContains(t, errorTags(ValidateTDR(ds)), tag.AlarmDecision)
+}
+
+func TestValidate_ImagePixelConsistency(t *testing.T) {
+	ds := validCT(t)
+	ds.Elements[tag.BitsStored].Value = 17
+	assert.Equal(t, []tag.Tag{tag.BitsStored, tag.HighBit}, errorTags(ValidateCT(ds)))
+
+	ds = validCT(t)
+	ds.Elements[tag.HighBit].Value = 11
+	result := ValidateCT(ds)
+	assert.Equal(t, []tag.Tag{tag.HighBit}, errorTags(result))
+	assert.Contains(t, result.Errors[0].Message, "high bit 11 is not bits stored 16 - 1")
+
+	// as built, samples, and as read, bytes
+	ds = validCT(t)
+	ds.Elements[tag.Rows].Value = 5
+	assert.Equal(t, []tag.Tag{tag.PixelData}, errorTags(ValidateCT(ds)))
+	ds = rewrite(t, validCT(t))
+	assert.True(t, ValidateCT(ds).IsValid())
+	raw := ds.Elements[tag.PixelData].Value.([]byte)
+	ds.Elements[tag.PixelData].Value = raw[:len(raw)-2]
+	result = ValidateCT(ds)
+	assert.Equal(t, []tag.Tag{tag.PixelData}, errorTags(result))
+	assert.Contains(t, result.Errors[0].Message, "30 bytes, want 32 for 4x4, 1 frames of 1 samples")
+}
+
+func TestValidate_DateTimeFormats(t *testing.T) {
+	for _, tc := range []struct {
+		tag   tag.Tag
+		value string
+		valid bool
+	}{
+		{tag.StudyDate, "20240229", true},
+		{tag.StudyDate, "", true},
+		{tag.StudyDate, "20230229", false},
+		{tag.StudyDate, "2024.01.02", false},
+		{tag.StudyDate, "240102", false},
+		{tag.StudyTime, "1230", true},
+		{tag.StudyTime, "123000.5", true},
+		{tag.StudyTime, "12:30:00", false},
+		{tag.StudyTime, "2530", false},
+		{tag.StudyTime, `1230\1231`, true},
+	} {
+		ds := validCT(t)
+		ds.Elements[tc.tag].Value = tc.value
+		result := ValidateCT(ds)
+		assert.Equal(t, tc.valid, result.IsValid(), "%s %q: %s", tc.tag, tc.value, result)
+		if !tc.valid {
+			assert.Equal(t, Type2, result.Errors[0].Type, "the type of the requirement")
+		}
+	}
+}
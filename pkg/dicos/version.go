@@ -0,0 +1,90 @@
+package dicos
+
+import (
+	"runtime/debug"
+	"sort"
+)
+
+// modulePath is the import path of this module, used to find its version in build info
+const modulePath = "github.com/jpfielding/dicos.go"
+
+// Version returns the module version of the library linked into the running
+// binary, e.g. "v0.4.1", or "(devel)" when built from a local checkout.
+func Version() string {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(devel)"
+	}
+	if bi.Main.Path == modulePath {
+		return bi.Main.Version
+	}
+	for _, dep := range bi.Deps {
+		if dep.Path == modulePath {
+			if dep.Replace != nil {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return "(devel)"
+}
+
+// TransferSyntaxSupport describes what the library can do with a transfer syntax
+type TransferSyntaxSupport struct {
+	UID          TransferSyntax
+	Name         string
+	Encapsulated bool
+	Codec        string // codec name for encapsulated syntaxes
+	Read         bool   // datasets can be parsed and their pixel data decoded
+	Write        bool   // datasets can be written and their pixel data encoded
+}
+
+// SupportedTransferSyntaxes lists the transfer syntaxes the library reads or
+// writes, in UID order
+func SupportedTransferSyntaxes() []TransferSyntaxSupport {
+	byUID := map[TransferSyntax]*TransferSyntaxSupport{
+		ImplicitVRLittleEndian: {UID: ImplicitVRLittleEndian, Read: true, Write: true},
+		ExplicitVRLittleEndian: {UID: ExplicitVRLittleEndian, Read: true, Write: true},
+	}
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	for uid, codec := range codecsByTS {
+		ts := TransferSyntax(uid)
+		byUID[ts] = &TransferSyntaxSupport{UID: ts, Codec: codec.Name(), Read: true}
+	}
+	for _, codec := range codecsByName {
+		if s, ok := byUID[TransferSyntax(codec.TransferSyntaxUID())]; ok {
+			s.Write = true
+		}
+	}
+
+	out := make([]TransferSyntaxSupport, 0, len(byUID))
+	for ts, s := range byUID {
+		s.Name = ts.Name()
+		s.Encapsulated = ts.IsEncapsulated()
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].UID < out[j].UID })
+	return out
+}
+
+// IODSupport describes an Information Object Definition the library builds
+type IODSupport struct {
+	Name        string // e.g. "CT"
+	SOPClassUID string // SOP class written by the IOD builder
+	Validate    bool   // a Validate function checks its required attributes
+}
+
+// SupportedIODs lists the IODs the library builds and reads
+func SupportedIODs() []IODSupport {
+	return []IODSupport{
+		{Name: "CT", SOPClassUID: CTImageStorageUID, Validate: true},
+		{Name: "DX", SOPClassUID: DICOSDXForPresentationUID, Validate: true},
+		{Name: "AIT2D", SOPClassUID: DICOSAIT2DImageStorageUID, Validate: true},
+		{Name: "AIT3D", SOPClassUID: DICOSAIT3DImageStorageUID, Validate: true},
+		{Name: "TDR", SOPClassUID: DICOSTDRStorageUID, Validate: true},
+		{Name: "QR", SOPClassUID: DICOSQRStorageUID, Validate: true},
+		{Name: "SC", SOPClassUID: SecondaryCaptureImageStorageUID},
+		{Name: "PDF", SOPClassUID: EncapsulatedPDFStorageUID},
+	}
+}
@@ -0,0 +1,58 @@
+package dicos
+
+import (
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersion(t *testing.T) {
+	assert.NotEmpty(t, Version())
+}
+
+func TestSupportedTransferSyntaxes(t *testing.T) {
+	byUID := map[TransferSyntax]TransferSyntaxSupport{}
+	for _, s := range SupportedTransferSyntaxes() {
+		byUID[s.UID] = s
+	}
+
+	implicit := byUID[ImplicitVRLittleEndian]
+	assert.True(t, implicit.Read)
+	assert.True(t, implicit.Write)
+
+	ls := byUID[JPEGLSLossless]
+	assert.True(t, ls.Read)
+	assert.True(t, ls.Write)
+	assert.True(t, ls.Encapsulated)
+	assert.Equal(t, "jpeg-ls", ls.Codec)
+
+	near := byUID[TransferSyntax("1.2.840.10008.1.2.4.81")]
+	assert.True(t, near.Read)
+	assert.False(t, near.Write)
+}
+
+func TestSupportedIODs_MatchBuilders(t *testing.T) {
+	builders := map[string]func() (*Dataset, error){
+		"CT":    NewCTImage().GetDataset,
+		"DX":    NewDXImage().GetDataset,
+		"AIT2D": NewAIT2DImage().GetDataset,
+		"AIT3D": NewAIT3DImage().GetDataset,
+		"TDR":   NewThreatDetectionReport().GetDataset,
+		"QR":    NewQRMeasurement().GetDataset,
+		"SC":    iodSample("SC").GetDataset,
+		"PDF":   iodSample("PDF").GetDataset,
+	}
+	iods := SupportedIODs()
+	require.Len(t, iods, len(builders))
+	for _, iod := range iods {
+		build, ok := builders[iod.Name]
+		require.True(t, ok, iod.Name)
+		ds, err := build()
+		require.NoError(t, err)
+		elem, ok := ds.FindElement(tag.SOPClassUID.Group, tag.SOPClassUID.Element)
+		require.True(t, ok)
+		assert.Equal(t, iod.SOPClassUID, elem.Value, iod.Name)
+	}
+}
@@ -1,6 +1,11 @@
 package dicos
 
-import "fmt"
+import (
+	"fmt"
+	"image"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
 
 // Volume represents a 3D volume of pixel data
 type Volume struct {
@@ -19,62 +24,162 @@ type Volume struct {
 	OriginY float64
 	OriginZ float64
 
+	// Image orientation (row then column direction cosines)
+	Orientation [6]float64
+
 	// Pixel data (row-major order, slice-by-slice)
 	Data []uint16
+
+	// Samples is the number of samples per voxel, interleaved in Data: 1 for
+	// grayscale, 3 for RGB. Zero is taken as 1.
+	Samples int
+
+	// Signed is set for PixelRepresentation 1: Data holds sign extended
+	// two's complement samples, read them with GetInt16 or Int16Data
+	Signed bool
 }
 
 // NewVolume creates a new Volume with the specified dimensions
 func NewVolume(width, height, depth int) *Volume {
+	return NewVolumeSamples(width, height, depth, 1)
+}
+
+// NewVolumeSamples creates a new Volume with samples per voxel, such as 3
+// for RGB
+func NewVolumeSamples(width, height, depth, samples int) *Volume {
+	samples = max(samples, 1)
 	return &Volume{
-		Width:    width,
-		Height:   height,
-		Depth:    depth,
-		SpacingX: 1.0,
-		SpacingY: 1.0,
-		SpacingZ: 1.0,
-		Data:     make([]uint16, width*height*depth),
+		Width:       width,
+		Height:      height,
+		Depth:       depth,
+		SpacingX:    1.0,
+		SpacingY:    1.0,
+		SpacingZ:    1.0,
+		Orientation: [6]float64{1, 0, 0, 0, 1, 0},
+		Data:        make([]uint16, width*height*depth*samples),
+		Samples:     samples,
 	}
 }
 
-// Get returns the voxel value at (x, y, z)
-func (v *Volume) Get(x, y, z int) uint16 {
+// samples returns the number of samples per voxel
+func (v *Volume) samples() int {
+	return max(v.Samples, 1)
+}
+
+// index returns the position in Data of the first sample of the voxel at
+// (x, y, z), or -1 outside the volume
+func (v *Volume) index(x, y, z int) int {
 	if x < 0 || x >= v.Width || y < 0 || y >= v.Height || z < 0 || z >= v.Depth {
+		return -1
+	}
+	return (z*v.Width*v.Height + y*v.Width + x) * v.samples()
+}
+
+// setGeometry fills spacing and origin from the dataset's image plane attributes.
+// In-plane spacing is calibrated per GetCalibratedSpacing so measurements are in mm at the object.
+func (v *Volume) setGeometry(ds *Dataset) {
+	sp := SpacingFromDataset(ds)
+	v.SpacingX, v.SpacingY, v.SpacingZ = sp.Col, sp.Row, sp.Slice
+	pos := GetImagePositionPatient(ds)
+	v.OriginX, v.OriginY, v.OriginZ = pos[0], pos[1], pos[2]
+	copy(v.Orientation[:], GetImageOrientationPatient(ds))
+}
+
+// Get returns the voxel value at (x, y, z), the first sample of a
+// multi-sample volume
+func (v *Volume) Get(x, y, z int) uint16 {
+	return v.GetSample(x, y, z, 0)
+}
+
+// GetSample returns sample s of the voxel at (x, y, z), e.g. 1 for green in
+// an RGB volume
+func (v *Volume) GetSample(x, y, z, s int) uint16 {
+	idx := v.index(x, y, z)
+	if idx < 0 || s < 0 || s >= v.samples() {
 		return 0
 	}
-	idx := z*v.Width*v.Height + y*v.Width + x
-	return v.Data[idx]
+	return v.Data[idx+s]
 }
 
-// Set sets the voxel value at (x, y, z)
+// GetInt16 returns the voxel value at (x, y, z) of a signed volume
+func (v *Volume) GetInt16(x, y, z int) int16 {
+	return int16(v.Get(x, y, z))
+}
+
+// Int16Data returns a copy of the voxels as signed values
+func (v *Volume) Int16Data() []int16 {
+	return Int16Samples(v.Data, 16)
+}
+
+// Set sets the voxel value at (x, y, z), the first sample of a
+// multi-sample volume
 func (v *Volume) Set(x, y, z int, val uint16) {
-	if x < 0 || x >= v.Width || y < 0 || y >= v.Height || z < 0 || z >= v.Depth {
+	v.SetSample(x, y, z, 0, val)
+}
+
+// SetSample sets sample s of the voxel at (x, y, z)
+func (v *Volume) SetSample(x, y, z, s int, val uint16) {
+	idx := v.index(x, y, z)
+	if idx < 0 || s < 0 || s >= v.samples() {
 		return
 	}
-	idx := z*v.Width*v.Height + y*v.Width + x
-	v.Data[idx] = val
+	v.Data[idx+s] = val
 }
 
-// Slice returns a 2D slice from the volume
+// Channel returns a copy of sample s of every voxel, a single-sample volume
+// of the same dimensions as Data
+func (v *Volume) Channel(s int) []uint16 {
+	n := v.samples()
+	if s < 0 || s >= n {
+		return nil
+	}
+	out := make([]uint16, v.Width*v.Height*v.Depth)
+	for i := range out {
+		out[i] = v.Data[i*n+s]
+	}
+	return out
+}
+
+// RGBA returns axial slice z of an 8-bit RGB volume as an image, or nil when
+// z is out of range or the volume is not RGB
+func (v *Volume) RGBA(z int) *image.RGBA {
+	if v.samples() != 3 || z < 0 || z >= v.Depth {
+		return nil
+	}
+	size := v.Width * v.Height * 3
+	return rgbImage(v.Data[z*size:(z+1)*size], v.Height, v.Width)
+}
+
+// Slice planes of a volume, for Slice and Reslice
+const (
+	SliceAxial    = 0 // XY plane at a Z index
+	SliceCoronal  = 1 // XZ plane at a Y index
+	SliceSagittal = 2 // YZ plane at an X index
+)
+
+// Slice returns a 2D slice from the volume, with every sample of each voxel
 // Orientation: 0=Axial (XY at Z), 1=Coronal (XZ at Y), 2=Sagittal (YZ at X)
 func (v *Volume) Slice(orientation int, index int) []uint16 {
+	n := v.samples()
 	switch orientation {
 	case 0: // Axial (XY plane at Z=index)
 		if index < 0 || index >= v.Depth {
 			return nil
 		}
-		slice := make([]uint16, v.Width*v.Height)
-		start := index * v.Width * v.Height
-		copy(slice, v.Data[start:start+v.Width*v.Height])
+		size := v.Width * v.Height * n
+		slice := make([]uint16, size)
+		copy(slice, v.Data[index*size:(index+1)*size])
 		return slice
 
 	case 1: // Coronal (XZ plane at Y=index)
 		if index < 0 || index >= v.Height {
 			return nil
 		}
-		slice := make([]uint16, v.Width*v.Depth)
+		slice := make([]uint16, v.Width*v.Depth*n)
 		for z := 0; z < v.Depth; z++ {
 			for x := 0; x < v.Width; x++ {
-				slice[z*v.Width+x] = v.Get(x, index, z)
+				idx := v.index(x, index, z)
+				copy(slice[(z*v.Width+x)*n:], v.Data[idx:idx+n])
 			}
 		}
 		return slice
@@ -83,10 +188,11 @@ func (v *Volume) Slice(orientation int, index int) []uint16 {
 		if index < 0 || index >= v.Width {
 			return nil
 		}
-		slice := make([]uint16, v.Height*v.Depth)
+		slice := make([]uint16, v.Height*v.Depth*n)
 		for z := 0; z < v.Depth; z++ {
 			for y := 0; y < v.Height; y++ {
-				slice[z*v.Height+y] = v.Get(index, y, z)
+				idx := v.index(index, y, z)
+				copy(slice[(z*v.Height+y)*n:], v.Data[idx:idx+n])
 			}
 		}
 		return slice
@@ -94,7 +200,8 @@ func (v *Volume) Slice(orientation int, index int) []uint16 {
 	return nil
 }
 
-// MinMax returns the minimum and maximum voxel values
+// MinMax returns the minimum and maximum voxel values, compared as unsigned.
+// Use SampleRange for signed volumes.
 func (v *Volume) MinMax() (min, max uint16) {
 	if len(v.Data) == 0 {
 		return 0, 0
@@ -111,6 +218,24 @@ func (v *Volume) MinMax() (min, max uint16) {
 	return
 }
 
+// SampleRange returns the minimum and maximum voxel values, compared as
+// signed values when the volume is Signed
+func (v *Volume) SampleRange() (lo, hi int) {
+	if !v.Signed {
+		mn, mx := v.MinMax()
+		return int(mn), int(mx)
+	}
+	if len(v.Data) == 0 {
+		return 0, 0
+	}
+	lo, hi = int(int16(v.Data[0])), int(int16(v.Data[0]))
+	for _, val := range v.Data {
+		s := int(int16(val))
+		lo, hi = min(lo, s), max(hi, s)
+	}
+	return lo, hi
+}
+
 // FromDataset creates a Volume from a Dataset's pixel data
 func VolumeFromDataset(ds *Dataset) (*Volume, error) {
 	rows := GetRows(ds)
@@ -126,7 +251,8 @@ func VolumeFromDataset(ds *Dataset) (*Volume, error) {
 		return nil, err
 	}
 
-	vol := NewVolume(cols, rows, numFrames)
+	vol := NewVolumeSamples(cols, rows, numFrames, ds.SamplesPerPixel())
+	vol.setGeometry(ds)
 
 	// Copy pixel data
 	if pd.IsEncapsulated {
@@ -136,15 +262,46 @@ func VolumeFromDataset(ds *Dataset) (*Volume, error) {
 	}
 
 	// Native pixel data - copy directly
-	idx := 0
-	for _, frame := range pd.Frames {
-		for _, val := range frame.Data {
-			if idx < len(vol.Data) {
-				vol.Data[idx] = val
-				idx++
+	vol.copyNative(ds, pd)
+	vol.applyPixelRepresentation(ds)
+
+	return vol, nil
+}
+
+// copyNative copies native frames into the volume, interleaving the samples
+// of color frames stored by plane (PlanarConfiguration 1)
+func (v *Volume) copyNative(ds *Dataset, pd *PixelData) {
+	n := v.samples()
+	frameSize := v.Width * v.Height * n
+	planar := false
+	if elem, ok := ds.FindElement(tag.PlanarConfiguration.Group, tag.PlanarConfiguration.Element); ok && n > 1 {
+		p, _ := elem.GetInt()
+		planar = p == 1
+	}
+	for z, frame := range pd.Frames {
+		if z >= v.Depth {
+			break
+		}
+		dst := v.Data[z*frameSize : (z+1)*frameSize]
+		if !planar {
+			copy(dst, frame.Data)
+			continue
+		}
+		pixels := v.Width * v.Height
+		for s := range n {
+			for i, val := range frame.Data[min(s*pixels, len(frame.Data)):min((s+1)*pixels, len(frame.Data))] {
+				dst[i*n+s] = val
 			}
 		}
 	}
+}
 
-	return vol, nil
+// applyPixelRepresentation marks the volume Signed for PixelRepresentation 1
+// and sign extends its samples from BitsStored
+func (v *Volume) applyPixelRepresentation(ds *Dataset) {
+	if ds.PixelRepresentation() != 1 {
+		return
+	}
+	v.Signed = true
+	signExtend(v.Data, ds.BitsStored())
 }
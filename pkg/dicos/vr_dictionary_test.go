@@ -0,0 +1,256 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"image"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/dict"
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The VR dictionary is generated from the declarations in tag/tag.go, whose
+// trailing comments carry the VR from the DICOS and DICOM conformance tables:
+//
+//	Rows = Tag{0x0028, 0x0010} // US - Image height
+//
+// Every declared tag must carry one, and GetVR and the IOD builders must
+// agree with it, so a tag added without a GetVR case fails here.
+
+// dictEntry is one tag declaration with its allowed VRs
+type dictEntry struct {
+	name string
+	tag  Tag
+	vrs  []string // more than one for e.g. US/SS
+}
+
+var vrComment = regexp.MustCompile(`^([A-Z]{2}(?:/[A-Z]{2})*) - `)
+
+func parseTagDictionary(t *testing.T) []dictEntry {
+	t.Helper()
+	f, err := parser.ParseFile(token.NewFileSet(), "tag/tag.go", nil, parser.ParseComments)
+	require.NoError(t, err)
+
+	var entries []dictEntry
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, name := range vs.Names {
+				lit, ok := vs.Values[i].(*ast.CompositeLit)
+				if !ok || len(lit.Elts) != 2 {
+					continue
+				}
+				var parts [2]uint16
+				for j, elt := range lit.Elts {
+					v, err := strconv.ParseUint(elt.(*ast.BasicLit).Value, 0, 16)
+					require.NoError(t, err, name.Name)
+					parts[j] = uint16(v)
+				}
+				e := dictEntry{name: name.Name, tag: Tag{Group: parts[0], Element: parts[1]}}
+				if m := vrComment.FindStringSubmatch(vs.Comment.Text()); m != nil {
+					e.vrs = strings.Split(m[1], "/")
+				}
+				entries = append(entries, e)
+			}
+		}
+	}
+	require.NotEmpty(t, entries)
+	return entries
+}
+
+func TestGetVR_Dictionary(t *testing.T) {
+	for _, e := range parseTagDictionary(t) {
+		if e.tag.Group == 0xFFFE {
+			continue // item delimiters have no VR
+		}
+		if !assert.NotEmpty(t, e.vrs, "tag.%s %s has no \"// VR - description\" comment", e.name, e.tag) {
+			continue
+		}
+		assert.Contains(t, e.vrs, GetVR(e.tag), "GetVR(tag.%s %s)", e.name, e.tag)
+	}
+}
+
+func TestGetVR_IODs(t *testing.T) {
+	dict := map[Tag][]string{}
+	for _, e := range parseTagDictionary(t) {
+		dict[e.tag] = append(dict[e.tag], e.vrs...)
+	}
+
+	data := make([]uint16, 4*4)
+	ct := NewCTImage()
+	ct.Rows, ct.Columns = 4, 4
+	ct.SetPixelData(4, 4, data)
+	dx := NewDXImage()
+	dx.SetPixelData(4, 4, data)
+	ait2d := NewAIT2DImage()
+	ait2d.SetPixelData(4, 4, data)
+	ait3d := NewAIT3DImage()
+	ait3d.SetPixelData(4, 4, 1, data)
+	sc := NewSecondaryCaptureImage()
+	sc.Image = image.NewRGBA(image.Rect(0, 0, 4, 4))
+	tdr := NewThreatDetectionReport()
+	tdr.AlarmDecision = "ALARM"
+	tdr.PTOs = append(tdr.PTOs, PotentialThreatObject{ID: 1, Label: "KNIFE", BoundingBox: &BoundingBox{BottomRight: [3]float32{1, 1, 1}}})
+	qr := NewQRMeasurement()
+	qr.Acquisition.TransmitterFrequency = []float64{3.41}
+	qr.Acquisition.SpectralWidth = 20000
+	qr.Spectrum = []float32{1, 2}
+
+	iods := []struct {
+		name  string
+		build func() (*Dataset, error)
+	}{
+		{"CT", ct.GetDataset},
+		{"DX", dx.GetDataset},
+		{"AIT2D", ait2d.GetDataset},
+		{"AIT3D", ait3d.GetDataset},
+		{"SC", sc.GetDataset},
+		{"TDR", tdr.GetDataset},
+		{"QR", qr.GetDataset},
+	}
+	for _, iod := range iods {
+		t.Run(iod.name, func(t *testing.T) {
+			ds, err := iod.build()
+			require.NoError(t, err)
+			var buf bytes.Buffer
+			_, err = Write(&buf, ds)
+			require.NoError(t, err)
+			read, err := ReadBufferContext(context.Background(), buf.Bytes())
+			require.NoError(t, err)
+
+			// Both the built and the re-read dataset, so the VR written to
+			// the file is checked and not only the one held in memory
+			for _, d := range []*Dataset{ds, read} {
+				walkElements(d, func(elem *Element) {
+					if vrs, ok := dict[elem.Tag]; ok {
+						assert.True(t, slices.Contains(vrs, elem.VR), "%s written as %s, want %s", elem.Tag, elem.VR, strings.Join(vrs, "/"))
+					}
+				})
+			}
+			walkElements(ds, func(elem *Element) {
+				assert.True(t, valueFitsVR(elem.Value, elem.VR), "%s holds %T, which does not encode as %s", elem.Tag, elem.Value, elem.VR)
+			})
+		})
+	}
+}
+
+// valueFitsVR reports whether the writer encodes v as the VR requires, e.g.
+// an int given for an LO would be written as two binary bytes
+func valueFitsVR(v any, vr string) bool {
+	switch vr {
+	case "AE", "AS", "CS", "DA", "DT", "IS", "LO", "LT", "PN", "SH", "ST", "TM", "UC", "UI", "UR", "UT":
+		switch v.(type) {
+		case string, []string:
+			return true
+		}
+	case "DS":
+		switch v.(type) {
+		case string, []string, float64:
+			return true
+		}
+	case "US", "SS":
+		switch v.(type) {
+		case uint16, []uint16, int16, int:
+			return true
+		}
+	case "UL", "SL":
+		switch v.(type) {
+		case uint32, []uint32, int32, int:
+			return true
+		}
+	case "FL":
+		switch v.(type) {
+		case float32, []float32, float64, []float64:
+			return true
+		}
+	case "FD":
+		switch v.(type) {
+		case float64, []float64:
+			return true
+		}
+	case "AT":
+		switch v.(type) {
+		case Tag, []Tag:
+			return true
+		}
+	case "SQ":
+		_, ok := v.([]*Dataset)
+		return ok
+	default: // OB, OW, UN and the other binary VRs
+		return true
+	}
+	return false
+}
+
+// walkElements calls fn for every element of ds and its sequence items
+func walkElements(ds *Dataset, fn func(*Element)) {
+	for _, elem := range ds.Elements {
+		fn(elem)
+		if items, ok := elem.Value.([]*Dataset); ok {
+			for _, item := range items {
+				walkElements(item, fn)
+			}
+		}
+	}
+}
+
+func TestDataDictionary(t *testing.T) {
+	for _, e := range parseTagDictionary(t) {
+		if e.tag.Group == 0x0011 {
+			continue // private integrity elements
+		}
+		_, ok := dict.Lookup(e.tag)
+		assert.True(t, ok, "tag.%s %s is missing from the data dictionary", e.name, e.tag)
+	}
+
+	e, ok := dict.ByKeyword("ImageOrientationPatient")
+	require.True(t, ok)
+	assert.Equal(t, tag.ImageOrientationPatient, e.Tag)
+	assert.Equal(t, "DS", e.VR)
+	assert.Equal(t, "6", e.VM)
+
+	e, ok = dict.Lookup(tag.SmallestImagePixelValue)
+	require.True(t, ok)
+	assert.Equal(t, []string{"US", "SS"}, e.VRs)
+
+	e, ok = dict.Lookup(Tag{Group: 0x6002, Element: 0x3000})
+	require.True(t, ok, "repeating overlay group")
+	assert.Equal(t, "OverlayData", e.Keyword)
+	assert.Equal(t, Tag{Group: 0x6002, Element: 0x3000}, e.Tag)
+	_, ok = dict.Lookup(Tag{Group: 0x6021, Element: 0x3000})
+	assert.False(t, ok)
+
+	assert.Equal(t, "LO", dict.VR(Tag{Group: 0x0019, Element: 0x0010}), "private creator")
+	assert.Equal(t, "UN", dict.VR(Tag{Group: 0x0019, Element: 0x1001}), "private element")
+	assert.Equal(t, "UL", dict.VR(Tag{Group: 0x0018, Element: 0x0000}), "group length")
+	assert.Equal(t, "UN", dict.VR(tag.Item), "item delimiters have no VR")
+
+	e, ok = dict.Lookup(Tag{Group: 0x0020, Element: 0x0030})
+	require.True(t, ok)
+	assert.True(t, e.Retired)
+}
+
+func TestWrite_MissingVRUsesDictionary(t *testing.T) {
+	ds := &Dataset{Elements: map[Tag]*Element{
+		tag.ExposureTimeInms: {Tag: tag.ExposureTimeInms, Value: 12.5},
+	}}
+	read := rewrite(t, ds)
+	elem, ok := read.Elements[tag.ExposureTimeInms]
+	require.True(t, ok)
+	assert.Equal(t, "FD", elem.VR)
+	assert.Equal(t, 12.5, elem.Value)
+}
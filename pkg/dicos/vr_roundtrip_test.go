@@ -0,0 +1,169 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"io"
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUnusualVRs_RoundTrip reads elements laid out the way vendor files
+// carry them, checks their typed values and rewrites them byte for byte
+func TestUnusualVRs_RoundTrip(t *testing.T) {
+	frameTime := binary.LittleEndian.AppendUint16(nil, 0x0018)
+	frameTime = binary.LittleEndian.AppendUint16(frameTime, 0x1063)
+	pointers := append(append([]byte{}, frameTime...), 0x18, 0x00, 0x65, 0x10) // (0018,1063)\(0018,1065)
+
+	var points []byte
+	for _, v := range []uint32{1, 2, 70000} {
+		points = binary.LittleEndian.AppendUint32(points, v)
+	}
+	var doubles []byte
+	for _, v := range []float64{0.5, -1.25, 1e9} {
+		doubles = binary.LittleEndian.AppendUint64(doubles, math.Float64bits(v))
+	}
+
+	values := map[Tag]struct {
+		vr   string
+		raw  []byte
+		want any
+	}{
+		{Group: 0x0008, Element: 0x0119}: {"UC", []byte("CHECKPOINT 7 LANE 3 "), "CHECKPOINT 7 LANE 3"},
+		{Group: 0x0008, Element: 0x1190}: {"UR", []byte("https://pacs.example.org/studies/1.2.3"), "https://pacs.example.org/studies/1.2.3"},
+		{Group: 0x0009, Element: 0x1002}: {"AT", pointers, []Tag{{Group: 0x0018, Element: 0x1063}, {Group: 0x0018, Element: 0x1065}}},
+		{Group: 0x0009, Element: 0x1003}: {"OD", doubles, []float64{0.5, -1.25, 1e9}},
+		{Group: 0x0028, Element: 0x0009}: {"AT", frameTime, Tag{Group: 0x0018, Element: 0x1063}},
+		{Group: 0x0066, Element: 0x0040}: {"OL", points, []uint32{1, 2, 70000}},
+	}
+
+	parts := [][]byte{rawExplicit(0x0002, 0x0010, "UI", []byte("1.2.840.10008.1.2.1\x00"))}
+	order := make([]Tag, 0, len(values))
+	for tg := range values {
+		order = append(order, tg)
+	}
+	sort.Slice(order, func(i, j int) bool { return order[i].Less(order[j]) })
+	for _, tg := range order {
+		v := values[tg]
+		if isLongVR(v.vr) {
+			parts = append(parts, rawExplicitLong(tg.Group, tg.Element, v.vr, v.raw))
+		} else {
+			parts = append(parts, rawExplicit(tg.Group, tg.Element, v.vr, v.raw))
+		}
+	}
+
+	ds, err := ReadBufferContext(context.Background(), rawFile(parts...))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	_, err = Write(&buf, ds)
+	require.NoError(t, err)
+	again, err := ReadBufferContext(context.Background(), buf.Bytes())
+	require.NoError(t, err)
+
+	for tg, v := range values {
+		for name, d := range map[string]*Dataset{"read": ds, "rewritten": again} {
+			elem, ok := d.Elements[tg]
+			require.True(t, ok, "%s %v", name, tg)
+			assert.Equal(t, v.vr, elem.VR, "%s %v", name, tg)
+			assert.Equal(t, v.want, elem.Value, "%s %v", name, tg)
+
+			b, _, err := encodeValue(elem.Value, elem.VR)
+			require.NoError(t, err)
+			assert.Equal(t, v.raw, b, "%s %v encoding", name, tg)
+		}
+	}
+
+	tags, ok := again.Elements[Tag{Group: 0x0009, Element: 0x1002}].GetTags()
+	require.True(t, ok)
+	assert.Len(t, tags, 2)
+	floats, ok := again.Elements[Tag{Group: 0x0009, Element: 0x1003}].GetFloats()
+	require.True(t, ok)
+	assert.Equal(t, []float64{0.5, -1.25, 1e9}, floats)
+}
+
+func TestWithElement_FrameIncrementPointer(t *testing.T) {
+	ds, err := NewDataset(WithElement(Tag{Group: 0x0028, Element: 0x0009}, Tag{Group: 0x0018, Element: 0x1063}))
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	_, err = Write(&buf, ds)
+	require.NoError(t, err)
+
+	again, err := ReadBufferContext(context.Background(), buf.Bytes())
+	require.NoError(t, err)
+	elem, ok := again.FindElement(0x0028, 0x0009)
+	require.True(t, ok)
+	assert.Equal(t, "AT", elem.VR)
+	assert.Equal(t, Tag{Group: 0x0018, Element: 0x1063}, elem.Value)
+}
+
+func TestWrite_ImplicitVR(t *testing.T) {
+	pixels := make([]uint16, 8*8*2)
+	for i := range pixels {
+		pixels[i] = uint16(i * 37)
+	}
+	ct := NewCTImage()
+	ct.SetPixelData(8, 8, pixels)
+	gray := image.NewGray(image.Rect(0, 0, 8, 8))
+	for i := range gray.Pix {
+		gray.Pix[i] = uint8(i * 3)
+	}
+	sc := NewSecondaryCaptureImage()
+	sc.Image = gray
+	tdr := NewThreatDetectionReport()
+	tdr.PTOs = []PotentialThreatObject{{Label: "KNIFE"}}
+
+	for name, b := range map[string]datasetBuilder{"16 bit": ct, "8 bit": sc, "sequence": tdr} {
+		src, err := b.GetDataset()
+		require.NoError(t, err, name)
+		implicit := CloneDataset(src)
+		implicit.Elements[Tag{Group: 0x0002, Element: 0x0010}].Value = string(ImplicitVRLittleEndian)
+		var buf bytes.Buffer
+		_, err = Write(&buf, implicit)
+		require.NoError(t, err, name)
+
+		got, err := ReadBuffer(buf.Bytes())
+		require.NoError(t, err, name)
+		assert.Equal(t, ImplicitVRLittleEndian, got.TransferSyntax(), name)
+		diff, err := Compare(src, got, CompareOptions{IgnoreFileMeta: true, DecodePixels: true})
+		require.NoError(t, err, name)
+		assert.True(t, diff.Equal(), "%s: %s", name, diff)
+	}
+}
+
+func TestWrite_ImplicitVRRejectsDictionarySequence(t *testing.T) {
+	ds, err := NewDataset(
+		WithFileMeta(DICOSCTImageStorageUID, "1.2.3.4.5", string(ImplicitVRLittleEndian)),
+		withVR(tag.ITDSequence, "CS", "NONE"),
+	)
+	require.NoError(t, err)
+	_, err = Write(io.Discard, ds)
+	assert.ErrorContains(t, err, "dictionary VR is SQ")
+}
+
+func TestWrite_RecomputesFileMetaGroupLength(t *testing.T) {
+	ds, err := NewDataset(
+		WithFileMeta(DICOSCTImageStorageUID, "1.2.3.4.5", string(ImplicitVRLittleEndian)),
+		withVR(tag.FileMetaInformationGroupLength, "UL", uint32(999)), // stale, as read from another file
+		WithElement(tag.Modality, "CT"),
+	)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	_, err = Write(&buf, ds)
+	require.NoError(t, err)
+
+	got, err := ReadBuffer(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, "CT", stringValue(got, tag.Modality))
+	length, ok := got.Elements[tag.FileMetaInformationGroupLength].GetUint32()
+	require.True(t, ok)
+	assert.NotEqual(t, uint32(999), length)
+	assert.Equal(t, uint32(999), ds.Elements[tag.FileMetaInformationGroupLength].Value, "dataset unchanged")
+}
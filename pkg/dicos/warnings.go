@@ -0,0 +1,62 @@
+package dicos
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+)
+
+// Warning codes recorded by the helpers that fall back on heuristics
+const (
+	// WarnImplicitRescaleIntercept: GetRescale assumed an intercept of -32768
+	// for an unsigned CT image without RescaleIntercept
+	WarnImplicitRescaleIntercept = "implicit-rescale-intercept"
+	// WarnInferredEnergyLevel: GetEnergyLevel guessed the energy from
+	// descriptions or KVP because SeriesEnergy is absent
+	WarnInferredEnergyLevel = "inferred-energy-level"
+)
+
+// Warning records a heuristic a helper applied in place of an explicit
+// attribute, changing how the dataset is interpreted. Unlike a ParseIssue it
+// says nothing about the encoding: the file is valid but its meaning was
+// guessed, so pipelines may want to flag it for vendor follow-up.
+type Warning struct {
+	Code    string // e.g. WarnImplicitRescaleIntercept
+	Tag     Tag    // attribute the heuristic stands in for
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s %v: %s", w.Code, w.Tag, w.Message)
+}
+
+// Warnings returns the heuristics applied to ds so far, in the order they
+// were first applied. Helpers such as GetRescale and GetEnergyLevel record
+// them as they are called, so check after interpreting the dataset.
+//
+// Example:
+//
+//	intercept, slope := dicos.GetRescale(ds)
+//	for _, w := range ds.Warnings() {
+//		log.Printf("%s: %s", path, w)
+//	}
+func (ds *Dataset) Warnings() []Warning {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	return slices.Clone(ds.warnings)
+}
+
+// warn records a warning once per code and tag; helpers run per frame and
+// would otherwise repeat it
+func (ds *Dataset) warn(code string, t Tag, format string, args ...any) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	for _, w := range ds.warnings {
+		if w.Code == code && w.Tag == t {
+			return
+		}
+	}
+	w := Warning{Code: code, Tag: t, Message: fmt.Sprintf(format, args...)}
+	slog.Debug("Dataset warning", slog.String("code", code), slog.String("tag", t.String()), slog.String("warning", w.Message))
+	ds.warnings = append(ds.warnings, w)
+}
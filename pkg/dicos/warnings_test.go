@@ -0,0 +1,61 @@
+package dicos
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarnings_ImplicitRescaleIntercept(t *testing.T) {
+	ds, err := NewDataset(
+		WithElement(tag.SOPClassUID, DICOSCTImageStorageUID),
+		WithElement(tag.PixelRepresentation, uint16(0)),
+	)
+	require.NoError(t, err)
+	assert.Empty(t, ds.Warnings())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			intercept, _ := GetRescale(ds)
+			assert.Equal(t, -32768.0, intercept)
+		}()
+	}
+	wg.Wait()
+
+	warnings := ds.Warnings()
+	require.Len(t, warnings, 1, "recorded once however often the helper runs")
+	assert.Equal(t, WarnImplicitRescaleIntercept, warnings[0].Code)
+	assert.Equal(t, tag.RescaleIntercept, warnings[0].Tag)
+	assert.Contains(t, warnings[0].String(), "implicit-rescale-intercept (0028,1052)")
+
+	// an explicit intercept needs no heuristic
+	explicit, err := NewDataset(
+		WithElement(tag.SOPClassUID, DICOSCTImageStorageUID),
+		WithElement(tag.PixelRepresentation, uint16(0)),
+		WithElement(tag.RescaleIntercept, "-1024"),
+	)
+	require.NoError(t, err)
+	intercept, _ := GetRescale(explicit)
+	assert.Equal(t, -1024.0, intercept)
+	assert.Empty(t, explicit.Warnings())
+}
+
+func TestWarnings_InferredEnergyLevel(t *testing.T) {
+	ds, err := NewDataset(WithElement(tag.KVP, "140"))
+	require.NoError(t, err)
+	assert.Equal(t, "he", GetEnergyLevel(ds))
+	require.Len(t, ds.Warnings(), 1)
+	assert.Equal(t, WarnInferredEnergyLevel, ds.Warnings()[0].Code)
+	assert.Contains(t, ds.Warnings()[0].Message, "from KVP")
+
+	tagged, err := NewDataset(WithElement(tag.SeriesEnergy, uint16(1)))
+	require.NoError(t, err)
+	assert.Equal(t, "le", GetEnergyLevel(tagged))
+	assert.Empty(t, tagged.Warnings())
+}
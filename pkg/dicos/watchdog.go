@@ -0,0 +1,106 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"io"
+	"time"
+)
+
+// FrameTimeoutError is returned when decoding a single compressed frame
+// exceeds the budget set with WithFrameDecodeTimeout. It matches
+// context.DeadlineExceeded with errors.Is.
+type FrameTimeoutError struct {
+	Codec   string        // codec that was decoding
+	Bytes   int           // size of the compressed frame
+	Timeout time.Duration // budget that was exceeded
+}
+
+func (e *FrameTimeoutError) Error() string {
+	return fmt.Sprintf("%s decode of %d byte frame exceeded %v", e.Codec, e.Bytes, e.Timeout)
+}
+
+func (e *FrameTimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+type frameTimeoutKey struct{}
+
+// WithFrameDecodeTimeout returns a context that limits every compressed frame
+// decoded with it to d, so a corrupt frame that sends a decoder into a
+// pathological slow path fails with a *FrameTimeoutError instead of hanging
+// the caller. Decoding stays on the caller's goroutine: codecs implementing
+// ContextDecoder, which include every built-in codec, see the deadline on
+// their context and stop at their next check of it; other codecs run to
+// completion and their frame then fails the same way.
+//
+// Example:
+//
+//	ctx := dicos.WithFrameDecodeTimeout(r.Context(), 2*time.Second)
+//	vol, err := dicos.DecodeVolumeContext(ctx, ds)
+//	var te *dicos.FrameTimeoutError
+//	if errors.As(err, &te) {
+//		http.Error(w, "poisoned frame", http.StatusUnprocessableEntity)
+//	}
+func WithFrameDecodeTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, frameTimeoutKey{}, d)
+}
+
+// frameDecodeTimeout returns the per-frame budget carried by ctx, or 0
+func frameDecodeTimeout(ctx context.Context) time.Duration {
+	d, _ := ctx.Value(frameTimeoutKey{}).(time.Duration)
+	return d
+}
+
+// watchDecode runs decode on the calling goroutine under the per-frame
+// budget of ctx. Codecs see the deadline on the context they are given; one
+// that returns after it fails with a *FrameTimeoutError. A codec panic is
+// returned as an error rather than taking down the process.
+func watchDecode(ctx context.Context, c Codec, data []byte, decode func(context.Context) (image.Image, error)) (img image.Image, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			img, err = nil, fmt.Errorf("%s decode of %d byte frame panicked: %v", c.Name(), len(data), r)
+		}
+	}()
+	timeout := frameDecodeTimeout(ctx)
+	if timeout <= 0 {
+		return decode(ctx)
+	}
+	fctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	img, err = decode(fctx)
+	if fctx.Err() != nil && ctx.Err() == nil {
+		return nil, &FrameTimeoutError{Codec: c.Name(), Bytes: len(data), Timeout: timeout}
+	}
+	return img, err
+}
+
+// contextCheckBytes is how much input a contextReader passes between
+// checks of its context
+const contextCheckBytes = 4096
+
+// contextReader feeds a streaming decoder and fails its reads once ctx is
+// done, so decoders that take no context stop within a few KiB of input
+type contextReader struct {
+	ctx       context.Context
+	r         io.Reader
+	unchecked int
+}
+
+func newContextReader(ctx context.Context, data []byte) *contextReader {
+	return &contextReader{ctx: ctx, r: bytes.NewReader(data)}
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if r.unchecked <= 0 {
+		if err := r.ctx.Err(); err != nil {
+			return 0, err
+		}
+		r.unchecked = contextCheckBytes
+	}
+	n, err := r.r.Read(p)
+	r.unchecked -= max(n, 1)
+	return n, err
+}
@@ -0,0 +1,146 @@
+package dicos
+
+import (
+	"context"
+	"errors"
+	"image"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowCodec stands in for a decoder stuck in a slow path on a corrupt frame
+type slowCodec struct {
+	delay   time.Duration
+	polling bool // implement DecodeContext and poll the context
+}
+
+func (c *slowCodec) Encode(io.Writer, image.Image) error { return nil }
+func (c *slowCodec) Decode([]byte, int, int) (image.Image, error) {
+	time.Sleep(c.delay)
+	return image.NewGray16(image.Rect(0, 0, 1, 1)), nil
+}
+func (c *slowCodec) Name() string              { return "slow" }
+func (c *slowCodec) TransferSyntaxUID() string { return "" }
+
+type pollingCodec struct{ slowCodec }
+
+func (c *pollingCodec) DecodeContext(ctx context.Context, data []byte, w, h int) (image.Image, error) {
+	for deadline := time.Now().Add(c.delay); time.Now().Before(deadline); {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return image.NewGray16(image.Rect(0, 0, w, h)), nil
+}
+
+// panicCodec stands in for a decoder that panics on a corrupt frame
+type panicCodec struct{ slowCodec }
+
+func (c *panicCodec) Decode([]byte, int, int) (image.Image, error) {
+	panic("index out of range")
+}
+
+// countdownContext reports cancellation once Err has been called checks times
+type countdownContext struct {
+	context.Context
+	checks int
+}
+
+func (c *countdownContext) Err() error {
+	if c.checks--; c.checks < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestFrameDecodeTimeout(t *testing.T) {
+	codecs := map[string]struct {
+		codec Codec
+		min   time.Duration // how long the decode runs at least
+	}{
+		// a codec without DecodeContext runs to completion and then fails
+		"ignoring": {&slowCodec{delay: 100 * time.Millisecond}, 100 * time.Millisecond},
+		"polling":  {&pollingCodec{slowCodec{delay: time.Second}}, 0},
+	}
+	for name, tc := range codecs {
+		t.Run(name, func(t *testing.T) {
+			ctx := WithFrameDecodeTimeout(context.Background(), 20*time.Millisecond)
+			start := time.Now()
+			_, err := decodeWithCodec(ctx, tc.codec, make([]byte, 10), 1, 1)
+			assert.GreaterOrEqual(t, time.Since(start), tc.min)
+			assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+			var te *FrameTimeoutError
+			require.True(t, errors.As(err, &te), "got %v", err)
+			assert.Equal(t, 10, te.Bytes)
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+		})
+	}
+
+	ctx := WithFrameDecodeTimeout(context.Background(), time.Second)
+	img, err := decodeWithCodec(ctx, &slowCodec{}, nil, 1, 1)
+	require.NoError(t, err, "within budget")
+	assert.NotNil(t, img)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = decodeWithCodec(cancelled, &slowCodec{}, nil, 1, 1)
+	assert.ErrorIs(t, err, context.Canceled)
+	var te *FrameTimeoutError
+	assert.False(t, errors.As(err, &te), "cancellation is not a frame timeout")
+}
+
+func TestFrameDecode_Panic(t *testing.T) {
+	_, err := decodeWithCodec(context.Background(), &panicCodec{}, make([]byte, 10), 1, 1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "panicked: index out of range")
+
+	ctx := WithFrameDecodeTimeout(context.Background(), time.Second)
+	_, err = decodeWithCodec(ctx, &panicCodec{}, make([]byte, 10), 1, 1)
+	assert.Error(t, err, "recovered under a budget too")
+}
+
+func TestContextDecoder_BuiltinCodecs(t *testing.T) {
+	const rows, cols = 256, 256
+	noise := func(bits int) []uint16 {
+		data := make([]uint16, rows*cols)
+		seed := uint32(7)
+		for i := range data {
+			seed = seed*1664525 + 1013904223
+			data[i] = uint16(seed >> (32 - bits))
+		}
+		return data
+	}
+	for _, tc := range []struct {
+		codec  Codec
+		format SampleFormat
+		checks int // context checks that pass before the decode is cancelled
+	}{
+		{CodecJPEGLS, SampleFormat{BitsAllocated: 16, BitsStored: 16}, 1},
+		{CodecJPEGLi, SampleFormat{BitsAllocated: 16, BitsStored: 16}, 1},
+		{CodecRLE, SampleFormat{BitsAllocated: 16, BitsStored: 16}, 2},
+		{CodecJPEG2000, SampleFormat{BitsAllocated: 16, BitsStored: 16}, 0}, // one check per tile
+		{CodecJPEGBaseline, SampleFormat{BitsAllocated: 8, BitsStored: 8}, 1},
+		{CodecJPEGExtended, SampleFormat{BitsAllocated: 16, BitsStored: 12}, 1},
+	} {
+		t.Run(tc.codec.Name(), func(t *testing.T) {
+			frag, err := encodeGrayFrame(tc.codec, noise(tc.format.BitsStored), rows, cols, tc.format)
+			require.NoError(t, err)
+			cd, ok := tc.codec.(ContextDecoder)
+			require.True(t, ok)
+
+			img, err := cd.DecodeContext(context.Background(), frag, cols, rows)
+			require.NoError(t, err)
+			assert.Equal(t, image.Rect(0, 0, cols, rows), img.Bounds())
+
+			ctx := &countdownContext{Context: context.Background(), checks: tc.checks}
+			_, err = cd.DecodeContext(ctx, frag, cols, rows)
+			assert.ErrorIs(t, err, context.Canceled)
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package web
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// jsonElement is one attribute of the DICOM JSON model (PS3.18 F.2)
+type jsonElement struct {
+	VR           string            `json:"vr"`
+	Value        []json.RawMessage `json:"Value"`
+	InlineBinary string            `json:"InlineBinary"`
+	BulkDataURI  string            `json:"BulkDataURI"`
+}
+
+// jsonPersonName is a PN value, one string per representation
+type jsonPersonName struct {
+	Alphabetic  string `json:"Alphabetic"`
+	Ideographic string `json:"Ideographic"`
+	Phonetic    string `json:"Phonetic"`
+}
+
+// decodeJSON parses an array of datasets in the DICOM JSON model, the body
+// of QIDO-RS and STOW-RS responses
+func decodeJSON(b []byte) ([]*dicos.Dataset, error) {
+	var objects []map[string]jsonElement
+	if err := json.Unmarshal(b, &objects); err != nil {
+		return nil, fmt.Errorf("dicos/web: invalid DICOM JSON: %w", err)
+	}
+	out := make([]*dicos.Dataset, len(objects))
+	for i, obj := range objects {
+		ds, err := jsonDataset(obj)
+		if err != nil {
+			return nil, fmt.Errorf("dicos/web: result %d: %w", i, err)
+		}
+		out[i] = ds
+	}
+	return out, nil
+}
+
+// jsonDataset converts one DICOM JSON object. Values are typed as the reader
+// types them, so the result writes like a parsed dataset. Bulk data referenced
+// by URI is left out; retrieve the instance to get it.
+func jsonDataset(obj map[string]jsonElement) (*dicos.Dataset, error) {
+	ds, err := dicos.NewDataset()
+	if err != nil {
+		return nil, err
+	}
+	for key, je := range obj {
+		t, err := parseTag(key)
+		if err != nil {
+			return nil, err
+		}
+		if je.BulkDataURI != "" {
+			continue
+		}
+		v, err := jsonValue(je)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", t, err)
+		}
+		ds.Elements[t] = &dicos.Element{Tag: t, VR: je.VR, Value: v}
+	}
+	return ds, nil
+}
+
+// parseTag parses a tag written as GGGGEEEE
+func parseTag(s string) (tag.Tag, error) {
+	n, err := strconv.ParseUint(s, 16, 32)
+	if err != nil || len(s) != 8 {
+		return tag.Tag{}, fmt.Errorf("invalid tag %q", s)
+	}
+	return tag.Tag{Group: uint16(n >> 16), Element: uint16(n)}, nil
+}
+
+// jsonValue converts the value of one attribute
+func jsonValue(je jsonElement) (any, error) {
+	if je.InlineBinary != "" {
+		return base64.StdEncoding.DecodeString(je.InlineBinary)
+	}
+	switch je.VR {
+	case "SQ":
+		items := make([]*dicos.Dataset, len(je.Value))
+		for i, raw := range je.Value {
+			var obj map[string]jsonElement
+			if err := json.Unmarshal(raw, &obj); err != nil {
+				return nil, err
+			}
+			item, err := jsonDataset(obj)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			items[i] = item
+		}
+		return items, nil
+	case "PN":
+		names := make([]string, len(je.Value))
+		for i, raw := range je.Value {
+			var pn jsonPersonName
+			if err := json.Unmarshal(raw, &pn); err != nil {
+				return nil, err
+			}
+			names[i] = strings.TrimRight(strings.Join([]string{pn.Alphabetic, pn.Ideographic, pn.Phonetic}, "="), "=")
+		}
+		return strings.Join(names, "\\"), nil
+	case "US", "UL", "SS", "SL", "FL", "FD", "SV", "UV":
+		nums := make([]float64, len(je.Value))
+		for i, raw := range je.Value {
+			if err := json.Unmarshal(raw, &nums[i]); err != nil {
+				return nil, err
+			}
+		}
+		return binaryNumbers(je.VR, nums), nil
+	case "AT":
+		tags := make([]tag.Tag, len(je.Value))
+		for i, raw := range je.Value {
+			var s string
+			if err := json.Unmarshal(raw, &s); err != nil {
+				return nil, err
+			}
+			t, err := parseTag(s)
+			if err != nil {
+				return nil, err
+			}
+			tags[i] = t
+		}
+		if len(tags) == 1 {
+			return tags[0], nil
+		}
+		return tags, nil
+	}
+	// Strings, and IS and DS which may be JSON numbers
+	strs := make([]string, len(je.Value))
+	for i, raw := range je.Value {
+		if string(raw) == "null" {
+			continue
+		}
+		if err := json.Unmarshal(raw, &strs[i]); err != nil {
+			strs[i] = string(raw)
+		}
+	}
+	return strings.Join(strs, "\\"), nil
+}
+
+// binaryNumbers types the values of a binary numeric VR as the reader does
+func binaryNumbers(vr string, nums []float64) any {
+	if len(nums) == 1 {
+		switch vr {
+		case "US":
+			return uint16(nums[0])
+		case "UL":
+			return uint32(nums[0])
+		case "SS":
+			return int16(nums[0])
+		case "SL":
+			return int32(nums[0])
+		case "FL":
+			return float32(nums[0])
+		case "FD":
+			return nums[0]
+		}
+	}
+	switch vr {
+	case "US":
+		out := make([]uint16, len(nums))
+		for i, n := range nums {
+			out[i] = uint16(n)
+		}
+		return out
+	case "UL":
+		out := make([]uint32, len(nums))
+		for i, n := range nums {
+			out[i] = uint32(n)
+		}
+		return out
+	case "FL":
+		out := make([]float32, len(nums))
+		for i, n := range nums {
+			out[i] = float32(n)
+		}
+		return out
+	case "FD":
+		return nums
+	}
+	// Remaining multi-valued VRs stay encoded, as the reader leaves them
+	var b []byte
+	for _, n := range nums {
+		switch vr {
+		case "SS":
+			b = binary.LittleEndian.AppendUint16(b, uint16(int16(n)))
+		case "SL":
+			b = binary.LittleEndian.AppendUint32(b, uint32(int32(n)))
+		case "SV":
+			b = binary.LittleEndian.AppendUint64(b, uint64(int64(n)))
+		default:
+			b = binary.LittleEndian.AppendUint64(b, uint64(math.Max(n, 0)))
+		}
+	}
+	return b
+}
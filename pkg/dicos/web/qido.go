@@ -0,0 +1,99 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// Query holds the parameters of a QIDO-RS search (PS3.18 10.6.1.2)
+type Query struct {
+	// Match filters on attribute values, e.g. "BAG*" as a wildcard or
+	// "20240101-20240131" as a date range
+	Match map[tag.Tag]string
+	// IncludeFields are returned with every match in addition to the
+	// defaults of the level
+	IncludeFields []tag.Tag
+	Fuzzy         bool // fuzzy matching of person names
+	Limit, Offset int  // paging, zero for the server default
+}
+
+// values encodes the query as URL parameters, tags written as GGGGEEEE
+func (q Query) values() url.Values {
+	v := url.Values{}
+	for t, s := range q.Match {
+		v.Set(tagKey(t), s)
+	}
+	for _, t := range q.IncludeFields {
+		v.Add("includefield", tagKey(t))
+	}
+	if q.Fuzzy {
+		v.Set("fuzzymatching", "true")
+	}
+	if q.Limit > 0 {
+		v.Set("limit", strconv.Itoa(q.Limit))
+	}
+	if q.Offset > 0 {
+		v.Set("offset", strconv.Itoa(q.Offset))
+	}
+	return v
+}
+
+func tagKey(t tag.Tag) string {
+	return fmt.Sprintf("%04X%04X", t.Group, t.Element)
+}
+
+// SearchStudies searches for studies with QIDO-RS
+func (c *Client) SearchStudies(ctx context.Context, q Query) ([]*dicos.Dataset, error) {
+	return c.search(ctx, c.url("studies"), q)
+}
+
+// SearchSeries searches for series with QIDO-RS, within study unless empty
+func (c *Client) SearchSeries(ctx context.Context, study string, q Query) ([]*dicos.Dataset, error) {
+	u := c.url("series")
+	if study != "" {
+		u = c.url("studies", study, "series")
+	}
+	return c.search(ctx, u, q)
+}
+
+// SearchInstances searches for instances with QIDO-RS, within study and
+// series unless empty
+func (c *Client) SearchInstances(ctx context.Context, study, series string, q Query) ([]*dicos.Dataset, error) {
+	var u string
+	switch {
+	case study == "":
+		u = c.url("instances")
+	case series == "":
+		u = c.url("studies", study, "instances")
+	default:
+		u = c.url("studies", study, "series", series, "instances")
+	}
+	return c.search(ctx, u, q)
+}
+
+// search runs a QIDO-RS query; no content means no match
+func (c *Client) search(ctx context.Context, u string, q Query) ([]*dicos.Dataset, error) {
+	if params := q.values(); len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+	rsp, err := c.do(ctx, http.MethodGet, u, nil, http.Header{"Accept": {MediaTypeDICOMJSON}}, http.StatusOK, http.StatusNoContent)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode == http.StatusNoContent {
+		return nil, nil
+	}
+	b, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSON(b)
+}
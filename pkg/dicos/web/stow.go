@@ -0,0 +1,119 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+)
+
+// STOW-RS response attributes (PS3.18 10.5.3)
+var (
+	failedSOPSequence     = tag.Tag{Group: 0x0008, Element: 0x1198}
+	referencedSOPSequence = tag.Tag{Group: 0x0008, Element: 0x1199}
+	failureReason         = tag.Tag{Group: 0x0008, Element: 0x1197}
+	retrieveURL           = tag.Tag{Group: 0x0008, Element: 0x1190}
+)
+
+// StoredInstance is one instance of a STOW-RS response
+type StoredInstance struct {
+	SOPClassUID    string
+	SOPInstanceUID string
+	RetrieveURL    string // where the instance can be retrieved, when stored
+	FailureReason  uint16 // why the instance was not stored, when failed
+}
+
+// StoreResponse lists the instances the origin server stored and refused
+type StoreResponse struct {
+	Stored []StoredInstance
+	Failed []StoredInstance
+}
+
+// Store uploads datasets with STOW-RS, each as a Part 10 application/dicom
+// part. When study is not empty every dataset must belong to that study.
+// Instances the server refuses are listed in Failed; an error is returned
+// only when none was stored or the request failed.
+func (c *Client) Store(ctx context.Context, study string, datasets ...*dicos.Dataset) (*StoreResponse, error) {
+	parts := make([]func(io.Writer) error, len(datasets))
+	for i, ds := range datasets {
+		parts[i] = func(w io.Writer) error {
+			_, err := dicos.Write(w, ds)
+			return err
+		}
+	}
+	body, contentType, err := writeParts(MediaTypeDICOM, parts...)
+	if err != nil {
+		return nil, err
+	}
+	u := c.url("studies")
+	if study != "" {
+		u = c.url("studies", study)
+	}
+	rsp, err := c.do(ctx, http.MethodPost, u, bytes.NewReader(body), http.Header{
+		"Content-Type": {contentType},
+		"Accept":       {MediaTypeDICOMJSON},
+	}, http.StatusOK, http.StatusAccepted, http.StatusConflict)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	out := &StoreResponse{}
+	b, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(b)) > 0 {
+		// The response is a single object, wrapped here like search results
+		if b[0] != '[' {
+			b = append(append([]byte{'['}, b...), ']')
+		}
+		results, err := decodeJSON(b)
+		if err != nil {
+			return nil, err
+		}
+		for _, ds := range results {
+			out.Stored = append(out.Stored, storedInstances(ds, referencedSOPSequence)...)
+			out.Failed = append(out.Failed, storedInstances(ds, failedSOPSequence)...)
+		}
+	}
+	if rsp.StatusCode == http.StatusConflict {
+		return out, fmt.Errorf("dicos/web: STOW-RS stored none of %d instances", len(datasets))
+	}
+	return out, nil
+}
+
+// storedInstances reads the items of a referenced or failed SOP sequence
+func storedInstances(ds *dicos.Dataset, seq tag.Tag) []StoredInstance {
+	elem, ok := ds.Elements[seq]
+	if !ok {
+		return nil
+	}
+	items, _ := elem.Value.([]*dicos.Dataset)
+	out := make([]StoredInstance, len(items))
+	for i, item := range items {
+		out[i] = StoredInstance{
+			SOPClassUID:    stringValue(item, tag.ReferencedSOPClassUID),
+			SOPInstanceUID: stringValue(item, tag.ReferencedSOPInstanceUID),
+			RetrieveURL:    stringValue(item, retrieveURL),
+		}
+		if elem, ok := item.Elements[failureReason]; ok {
+			out[i].FailureReason, _ = elem.Value.(uint16)
+		}
+	}
+	return out
+}
+
+// stringValue returns a string element of ds
+func stringValue(ds *dicos.Dataset, t tag.Tag) string {
+	if elem, ok := ds.Elements[t]; ok {
+		if s, ok := elem.GetString(); ok {
+			return s
+		}
+	}
+	return ""
+}
@@ -0,0 +1,80 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+)
+
+// Retrieve fetches instances with WADO-RS: the whole study when series is
+// empty, the whole series when instance is empty, otherwise one instance.
+// Each instance is parsed from its Part 10 application/dicom part.
+func (c *Client) Retrieve(ctx context.Context, study, series, instance string) ([]*dicos.Dataset, error) {
+	segments := []string{"studies", study}
+	if series != "" {
+		segments = append(segments, "series", series)
+		if instance != "" {
+			segments = append(segments, "instances", instance)
+		}
+	}
+	rsp, err := c.do(ctx, http.MethodGet, c.url(segments...), nil, http.Header{"Accept": {acceptRelated(MediaTypeDICOM)}}, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	var out []*dicos.Dataset
+	err = readParts(rsp, func(p *multipart.Part) error {
+		ds, err := dicos.ParseContext(ctx, p)
+		if err != nil {
+			return fmt.Errorf("dicos/web: instance %d: %w", len(out)+1, err)
+		}
+		out = append(out, ds)
+		return nil
+	})
+	return out, err
+}
+
+// RetrieveFrames fetches frames of an instance with WADO-RS, numbered from
+// 1, in the order requested. Frames come in the transfer syntax the instance
+// is stored in: raw little endian samples for native pixel data, otherwise
+// one compressed frame per part.
+func (c *Client) RetrieveFrames(ctx context.Context, study, series, instance string, frames ...int) ([][]byte, error) {
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("dicos/web: no frames requested")
+	}
+	list := make([]string, len(frames))
+	for i, f := range frames {
+		if f < 1 {
+			return nil, fmt.Errorf("dicos/web: invalid frame number %d", f)
+		}
+		list[i] = strconv.Itoa(f)
+	}
+	// The frame list is a comma separated segment, not escaped
+	u := c.url("studies", study, "series", series, "instances", instance) + "/frames/" + strings.Join(list, ",")
+	rsp, err := c.do(ctx, http.MethodGet, u, nil, http.Header{"Accept": {acceptRelated(MediaTypeOctetStream)}}, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	var out [][]byte
+	err = readParts(rsp, func(p *multipart.Part) error {
+		b, err := io.ReadAll(p)
+		if err != nil {
+			return fmt.Errorf("dicos/web: frame %d: %w", len(out)+1, err)
+		}
+		out = append(out, b)
+		return nil
+	})
+	if err == nil && len(out) != len(frames) {
+		err = fmt.Errorf("dicos/web: got %d frames, requested %d", len(out), len(frames))
+	}
+	return out, err
+}
@@ -0,0 +1,151 @@
+// Package web is a DICOMweb (PS3.18) client for archives that expose HTTP
+// services instead of DIMSE: STOW-RS to upload datasets, WADO-RS to retrieve
+// instances and frames, and QIDO-RS to search.
+package web
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Media types of DICOMweb payloads
+const (
+	MediaTypeDICOM       = "application/dicom"
+	MediaTypeDICOMJSON   = "application/dicom+json"
+	MediaTypeOctetStream = "application/octet-stream"
+)
+
+// maxErrorBody bounds the response body kept in an HTTPError
+const maxErrorBody = 4096
+
+// HTTPError reports a response with an unexpected status code
+type HTTPError struct {
+	Method, URL string
+	StatusCode  int
+	Body        string // start of the response body, often a reason from the server
+}
+
+func (e *HTTPError) Error() string {
+	msg := fmt.Sprintf("dicos/web: %s %s: %d %s", e.Method, e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Body != "" {
+		msg += ": " + e.Body
+	}
+	return msg
+}
+
+// Client calls the DICOMweb services rooted at BaseURL. The zero value, with
+// a BaseURL, uses http.DefaultClient.
+//
+// Example:
+//
+//	c := &web.Client{BaseURL: "https://pacs.example.com/dicom-web"}
+//	studies, err := c.SearchStudies(ctx, web.Query{Match: map[tag.Tag]string{tag.PatientID: "BAG*"}})
+type Client struct {
+	BaseURL string       // service root, e.g. https://host/dicom-web
+	HTTP    *http.Client // http.DefaultClient when nil
+	Header  http.Header  // added to every request, e.g. Authorization
+}
+
+// url joins the path segments to the base URL, escaping each
+func (c *Client) url(segments ...string) string {
+	u := strings.TrimRight(c.BaseURL, "/")
+	for _, s := range segments {
+		u += "/" + url.PathEscape(s)
+	}
+	return u
+}
+
+// do sends a request and returns the response when its status is one of ok.
+// The caller closes the body.
+func (c *Client) do(ctx context.Context, method, u string, body io.Reader, header http.Header, ok ...int) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range c.Header {
+		req.Header[k] = v
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	hc := c.HTTP
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	rsp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	for _, code := range ok {
+		if rsp.StatusCode == code {
+			return rsp, nil
+		}
+	}
+	defer rsp.Body.Close()
+	msg, _ := io.ReadAll(io.LimitReader(rsp.Body, maxErrorBody))
+	return nil, &HTTPError{Method: method, URL: u, StatusCode: rsp.StatusCode, Body: strings.TrimSpace(string(msg))}
+}
+
+// relatedType returns a multipart/related media type of parts of type typ
+func relatedType(typ, boundary string) string {
+	return mime.FormatMediaType("multipart/related", map[string]string{"type": typ, "boundary": boundary})
+}
+
+// acceptRelated asks for a multipart/related response of parts of type typ,
+// in the transfer syntax the origin server stores them in
+func acceptRelated(typ string) string {
+	return fmt.Sprintf("multipart/related; type=%q; transfer-syntax=*", typ)
+}
+
+// readParts calls visit with each part of a multipart/related response
+func readParts(rsp *http.Response, visit func(p *multipart.Part) error) error {
+	mediaType, params, err := mime.ParseMediaType(rsp.Header.Get("Content-Type"))
+	if err != nil {
+		return fmt.Errorf("dicos/web: response content type: %w", err)
+	}
+	if !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return fmt.Errorf("dicos/web: expected a multipart response, got %q", mediaType)
+	}
+	mr := multipart.NewReader(rsp.Body, params["boundary"])
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("dicos/web: reading multipart response: %w", err)
+		}
+		err = visit(p)
+		p.Close()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// writeParts encodes parts as a multipart/related body of type typ and
+// returns it with its content type
+func writeParts(typ string, parts ...func(w io.Writer) error) ([]byte, string, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for _, part := range parts {
+		pw, err := mw.CreatePart(map[string][]string{"Content-Type": {typ}})
+		if err != nil {
+			return nil, "", err
+		}
+		if err := part(pw); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), relatedType(typ, mw.Boundary()), nil
+}
@@ -0,0 +1,231 @@
+package web
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos"
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// archive is a minimal DICOMweb origin server keeping instances in memory
+type archive struct {
+	mu        sync.Mutex
+	instances map[string][]byte // Part 10 bytes by SOP Instance UID
+	query     string            // raw query of the last search
+}
+
+func newArchive(t *testing.T) (*archive, *Client) {
+	t.Helper()
+	a := &archive{instances: map[string][]byte{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /dicom-web/studies", a.store)
+	mux.HandleFunc("GET /dicom-web/studies/{study}/series/{series}/instances/{instance}", a.retrieve)
+	mux.HandleFunc("GET /dicom-web/studies/{study}/series/{series}/instances/{instance}/frames/{frames}", a.frames)
+	mux.HandleFunc("GET /dicom-web/studies", a.search)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return a, &Client{BaseURL: srv.URL + "/dicom-web/", Header: http.Header{"Authorization": {"Bearer token"}}}
+}
+
+func (a *archive) store(w http.ResponseWriter, r *http.Request) {
+	_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || params["type"] != MediaTypeDICOM {
+		http.Error(w, "bad content type", http.StatusUnsupportedMediaType)
+		return
+	}
+	var stored, failed []string
+	mr := multipart.NewReader(r.Body, params["boundary"])
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		b, _ := io.ReadAll(p)
+		ds, err := dicos.ReadBuffer(b)
+		if err != nil {
+			failed = append(failed, `{"00081197":{"vr":"US","Value":[49442]}}`)
+			continue
+		}
+		uid := stringValue(ds, tag.SOPInstanceUID)
+		a.mu.Lock()
+		a.instances[uid] = b
+		a.mu.Unlock()
+		stored = append(stored, fmt.Sprintf(`{"00081150":{"vr":"UI","Value":[%q]},"00081155":{"vr":"UI","Value":[%q]},"00081190":{"vr":"UR","Value":["http://archive/%s"]}}`,
+			stringValue(ds, tag.SOPClassUID), uid, uid))
+	}
+	w.Header().Set("Content-Type", MediaTypeDICOMJSON)
+	if len(failed) > 0 {
+		w.WriteHeader(http.StatusAccepted)
+	}
+	fmt.Fprintf(w, `{"00081199":{"vr":"SQ","Value":[%s]},"00081198":{"vr":"SQ","Value":[%s]}}`,
+		strings.Join(stored, ","), strings.Join(failed, ","))
+}
+
+func (a *archive) retrieve(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	b, ok := a.instances[r.PathValue("instance")]
+	a.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	body, contentType, _ := writeParts(MediaTypeDICOM, func(w io.Writer) error {
+		_, err := w.Write(b)
+		return err
+	})
+	w.Header().Set("Content-Type", contentType)
+	w.Write(body)
+}
+
+func (a *archive) frames(w http.ResponseWriter, r *http.Request) {
+	var parts []func(io.Writer) error
+	for _, f := range strings.Split(r.PathValue("frames"), ",") {
+		parts = append(parts, func(w io.Writer) error {
+			_, err := io.WriteString(w, "frame "+f)
+			return err
+		})
+	}
+	body, contentType, _ := writeParts(MediaTypeOctetStream, parts...)
+	w.Header().Set("Content-Type", contentType)
+	w.Write(body)
+}
+
+func (a *archive) search(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	a.query = r.URL.RawQuery
+	a.mu.Unlock()
+	if r.Header.Get("Authorization") != "Bearer token" {
+		http.Error(w, "who are you", http.StatusUnauthorized)
+		return
+	}
+	if r.URL.Query().Get("00100020") == "NOBODY" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Header().Set("Content-Type", MediaTypeDICOMJSON)
+	io.WriteString(w, `[{
+		"00080020": {"vr": "DA", "Value": ["20240115"]},
+		"00080061": {"vr": "CS", "Value": ["CT", "DX"]},
+		"00100010": {"vr": "PN", "Value": [{"Alphabetic": "Doe^Jane"}]},
+		"00100020": {"vr": "LO", "Value": ["BAG-0001"]},
+		"0020000D": {"vr": "UI", "Value": ["1.2.3.4"]},
+		"00201208": {"vr": "IS", "Value": [3]},
+		"00280010": {"vr": "US", "Value": [512]},
+		"00281050": {"vr": "DS", "Value": [40, "-1024.5"]},
+		"00200037": {"vr": "DS"},
+		"00081140": {"vr": "SQ", "Value": [{"00081155": {"vr": "UI", "Value": ["1.2.3.4.5"]}}]},
+		"00091001": {"vr": "OB", "InlineBinary": "AQID"},
+		"7FE00010": {"vr": "OW", "BulkDataURI": "http://archive/bulk"}
+	}]`)
+}
+
+func testCT(t *testing.T) *dicos.Dataset {
+	t.Helper()
+	ct := dicos.NewCTImage()
+	ct.SOPCommon.SOPClassUID = dicos.DICOSCTImageStorageUID
+	ct.Patient.PatientID = "BAG-0001"
+	ct.Rows, ct.Columns = 4, 4
+	ct.SetPixelData(4, 4, make([]uint16, 32))
+	ds, err := ct.GetDataset()
+	require.NoError(t, err)
+	return ds
+}
+
+func TestClient_StoreAndRetrieve(t *testing.T) {
+	_, c := newArchive(t)
+	ctx := context.Background()
+	sent := []*dicos.Dataset{testCT(t), testCT(t)}
+
+	rsp, err := c.Store(ctx, "", sent...)
+	require.NoError(t, err)
+	require.Len(t, rsp.Stored, 2)
+	assert.Empty(t, rsp.Failed)
+	uid := stringValue(sent[1], tag.SOPInstanceUID)
+	assert.Equal(t, uid, rsp.Stored[1].SOPInstanceUID)
+	assert.Equal(t, dicos.DICOSCTImageStorageUID, rsp.Stored[1].SOPClassUID)
+	assert.Equal(t, "http://archive/"+uid, rsp.Stored[1].RetrieveURL)
+
+	got, err := c.Retrieve(ctx, "1.2.3", "1.2.3.4", uid)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, uid, stringValue(got[0], tag.SOPInstanceUID))
+	assert.Equal(t, "BAG-0001", stringValue(got[0], tag.PatientID))
+	assert.Equal(t, 2, got[0].NumberOfFrames())
+
+	_, err = c.Retrieve(ctx, "1.2.3", "1.2.3.4", "9.9.9")
+	var he *HTTPError
+	require.ErrorAs(t, err, &he)
+	assert.Equal(t, http.StatusNotFound, he.StatusCode)
+}
+
+func TestClient_RetrieveFrames(t *testing.T) {
+	_, c := newArchive(t)
+	frames, err := c.RetrieveFrames(context.Background(), "1.2.3", "1.2.3.4", "1.2.3.4.5", 2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("frame 2"), []byte("frame 1")}, frames)
+
+	_, err = c.RetrieveFrames(context.Background(), "1.2.3", "1.2.3.4", "1.2.3.4.5", 0)
+	assert.Error(t, err)
+}
+
+func TestClient_SearchStudies(t *testing.T) {
+	a, c := newArchive(t)
+	ctx := context.Background()
+	studies, err := c.SearchStudies(ctx, Query{
+		Match:         map[tag.Tag]string{tag.PatientID: "BAG*"},
+		IncludeFields: []tag.Tag{tag.ModalitiesInStudy},
+		Limit:         10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "00100020=BAG%2A&includefield=00080061&limit=10", a.query)
+
+	require.Len(t, studies, 1)
+	ds := studies[0]
+	assert.Equal(t, "1.2.3.4", stringValue(ds, tag.StudyInstanceUID))
+	assert.Equal(t, "CT\\DX", stringValue(ds, tag.ModalitiesInStudy))
+	assert.Equal(t, "Doe^Jane", stringValue(ds, tag.PatientName))
+	assert.Equal(t, "3", stringValue(ds, tag.NumberOfStudyRelatedInstances))
+	assert.Equal(t, "40\\-1024.5", stringValue(ds, tag.WindowCenter))
+	assert.Equal(t, 512, ds.Rows())
+	assert.Equal(t, []byte{1, 2, 3}, ds.Elements[tag.Tag{Group: 0x0009, Element: 0x1001}].Value)
+	assert.False(t, dicos.HasElement(ds, tag.PixelData), "bulk data by URI is left out")
+	items, ok := ds.Elements[tag.ReferencedImageSequence].Value.([]*dicos.Dataset)
+	require.True(t, ok)
+	assert.Equal(t, "1.2.3.4.5", stringValue(items[0], tag.ReferencedSOPInstanceUID))
+
+	// Results write like a parsed dataset
+	_, err = dicos.Write(io.Discard, ds)
+	assert.NoError(t, err)
+
+	none, err := c.SearchStudies(ctx, Query{Match: map[tag.Tag]string{tag.PatientID: "NOBODY"}})
+	require.NoError(t, err)
+	assert.Empty(t, none)
+
+	c.Header = nil
+	_, err = c.SearchStudies(ctx, Query{})
+	var he *HTTPError
+	require.ErrorAs(t, err, &he)
+	assert.Equal(t, http.StatusUnauthorized, he.StatusCode)
+	assert.Equal(t, "who are you", he.Body)
+}
+
+func TestBinaryNumbers(t *testing.T) {
+	assert.Equal(t, uint16(7), binaryNumbers("US", []float64{7}))
+	assert.Equal(t, []uint16{1, 2}, binaryNumbers("US", []float64{1, 2}))
+	assert.Equal(t, int32(-5), binaryNumbers("SL", []float64{-5}))
+	b := binaryNumbers("SS", []float64{-1, 2}).([]byte)
+	assert.Equal(t, int16(-1), int16(binary.LittleEndian.Uint16(b)))
+	assert.Equal(t, []float64{0.5, 1.5}, binaryNumbers("FD", []float64{0.5, 1.5}))
+}
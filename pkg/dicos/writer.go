@@ -6,10 +6,13 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
 	"math"
 	"os"
 	"sort"
 	"sync/atomic"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/transfer"
 )
 
 // WriteFile writes a dataset to a DICOS file
@@ -22,8 +25,51 @@ func WriteFile(path string, ds *Dataset) (int64, error) {
 	return Write(f, ds)
 }
 
-// Write writes a dataset to a writer using Explicit VR Little Endian
+// WriteOptions controls how WriteWithOptions encodes a dataset
+type WriteOptions struct {
+	// GroupLengths adds a Group Length (gggg,0000) to every group of the
+	// data set, for older readers that expect them. Group lengths are
+	// retired outside the File Meta Information (PS3.5 7.2); those already
+	// present are recomputed whether or not this is set.
+	GroupLengths bool
+}
+
+// Write writes a dataset to a writer using Explicit VR Little Endian, or
+// Implicit VR Little Endian when that is its TransferSyntaxUID. The File Meta
+// Information is always explicit VR, and starts with its group length.
 func Write(w io.Writer, ds *Dataset) (int64, error) {
+	return WriteWithOptions(w, ds, WriteOptions{})
+}
+
+// WriteWithOptions is Write with control over the encoding.
+//
+// Example:
+//
+//	// group lengths for a reader that skips groups by them
+//	n, err := dicos.WriteWithOptions(f, ds, dicos.WriteOptions{GroupLengths: true})
+func WriteWithOptions(w io.Writer, ds *Dataset, opts WriteOptions) (int64, error) {
+	// Map frame metadata to functional groups and reject illegal structure
+	// before anything is written
+	ds, err := withFunctionalGroups(ds)
+	if err != nil {
+		return 0, err
+	}
+	if err := CheckStructure(ds); err != nil {
+		return 0, err
+	}
+	// The File Meta group length is computed on every write: strict readers
+	// need it to find the end of the meta, and one read from a file is stale
+	// once the meta changes, such as a new TransferSyntaxUID after a transcode
+	if hasGroup(ds, 0x0002) {
+		if ds, err = withFileMetaGroupLength(ds); err != nil {
+			return 0, err
+		}
+	}
+	implicit := ds.TransferSyntax() == transfer.ImplicitVRLittleEndian
+	if ds, err = withGroupLengths(ds, implicit, opts.GroupLengths); err != nil {
+		return 0, err
+	}
+
 	cw := &CountingWriter{Writer: w}
 
 	// 1. Write Preamble (128 bytes 0x00)
@@ -38,30 +84,66 @@ func Write(w io.Writer, ds *Dataset) (int64, error) {
 	}
 
 	// 3. Write Dataset Elements
-	return writeDataSetBody(w, ds)
+	return writeDataSetBody(w, ds, implicit)
 }
 
-func writeDataSetBody(w io.Writer, ds *Dataset) (int64, error) {
-	// 3. Collect elements and sort by Tag
-	var elements []*Element
-	for _, elem := range ds.Elements {
-		elements = append(elements, elem)
+// hasGroup reports whether ds has an element in group
+func hasGroup(ds *Dataset, group uint16) bool {
+	for t := range ds.Elements {
+		if t.Group == group {
+			return true
+		}
 	}
+	return false
+}
 
-	sort.Slice(elements, func(i, j int) bool {
-		t1 := elements[i].Tag
-		t2 := elements[j].Tag
-		if t1.Group != t2.Group {
-			return t1.Group < t2.Group
+// withGroupLengths returns ds, or a shallow copy of it, with the Group
+// Length of each data set group recomputed as encoded with implicit VR or
+// not. Groups without a length get one when all is set.
+func withGroupLengths(ds *Dataset, implicit, all bool) (*Dataset, error) {
+	lengths := map[uint16]uint32{}
+	for t := range ds.Elements {
+		if t.Group != 0x0002 && (all || t.Element == 0x0000) {
+			lengths[t.Group] = 0
+		}
+	}
+	if len(lengths) == 0 {
+		return ds, nil
+	}
+	for t, elem := range ds.Elements {
+		if _, ok := lengths[t.Group]; !ok || t.Element == 0x0000 {
+			continue
+		}
+		if bd, ok := elem.Value.(*BulkData); ok && bd.Excluded() {
+			continue
 		}
-		return t1.Element < t2.Element
-	})
+		n, err := writeElement(io.Discard, elem, implicit)
+		if err != nil {
+			return nil, fmt.Errorf("group length of %v: %w", t, err)
+		}
+		lengths[t.Group] += uint32(n)
+	}
+	out := &Dataset{Elements: maps.Clone(ds.Elements)}
+	for group, length := range lengths {
+		if err := withVR(Tag{Group: group}, "UL", length)(out); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
 
+// writeDataSetBody writes the elements of ds in tag order, with implicit VR
+// when implicit is set, except for the File Meta Information
+func writeDataSetBody(w io.Writer, ds *Dataset, implicit bool) (int64, error) {
 	cw := &CountingWriter{Writer: w}
 
-	// Write elements
-	for _, elem := range elements {
-		if _, err := writeElement(cw, elem); err != nil {
+	// Write elements in tag order
+	for _, t := range sortedTags(ds) {
+		elem := ds.Elements[t]
+		if bd, ok := elem.Value.(*BulkData); ok && bd.Excluded() {
+			continue
+		}
+		if _, err := writeElement(cw, elem, implicit && t.Group != 0x0002); err != nil {
 			return cw.Count.Load(), fmt.Errorf("failed to write element %v: %w", elem.Tag, err)
 		}
 	}
@@ -69,7 +151,19 @@ func writeDataSetBody(w io.Writer, ds *Dataset) (int64, error) {
 	return cw.Count.Load(), nil
 }
 
-func writeElement(w io.Writer, elem *Element) (int, error) {
+// sortedTags returns the dataset's tags in ascending order
+func sortedTags(ds *Dataset) []Tag {
+	tags := make([]Tag, 0, len(ds.Elements))
+	for t := range ds.Elements {
+		tags = append(tags, t)
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Less(tags[j]) })
+	return tags
+}
+
+// writeElement writes the tag, VR (left out when implicit), length and value
+// of elem
+func writeElement(w io.Writer, elem *Element, implicit bool) (int, error) {
 	cw := &CountingWriter{Writer: w}
 
 	// Write Tag
@@ -83,24 +177,49 @@ func writeElement(w io.Writer, elem *Element) (int, error) {
 	// Write VR
 	vr := elem.VR
 	if len(vr) != 2 {
-		slog.Warn("Invalid VR length, defaulting to UN", "vr", vr, "tag", elem.Tag)
-		vr = "UN"
+		vr = GetVR(elem.Tag)
+		slog.Warn("Invalid VR length, using the dictionary VR", "vr", elem.VR, "dictionary", vr, "tag", elem.Tag)
 	}
-	if _, err := cw.Write([]byte(vr)); err != nil {
-		return int(cw.Count.Load()), err
+	if implicit && vr != "SQ" && GetVR(elem.Tag) == "SQ" {
+		// readers take the VR from the dictionary and would parse items
+		return 0, fmt.Errorf("VR %s cannot be written with implicit VR, the dictionary VR is SQ", vr)
+	}
+	if !implicit {
+		if _, err := cw.Write([]byte(vr)); err != nil {
+			return int(cw.Count.Load()), err
+		}
+	}
+
+	// Bulk data is streamed from its source rather than encoded in memory
+	if bd, ok := elem.Value.(*BulkData); ok {
+		return writeBulkData(cw, vr, bd, implicit)
 	}
 
 	// Encode Value
-	valBytes, isUndefinedLength, err := encodeValue(elem.Value, vr)
+	var valBytes []byte
+	var isUndefinedLength bool
+	var err error
+	if items, ok := elem.Value.([]*Dataset); ok && implicit && vr == "SQ" {
+		valBytes, err = encodeSequence(items, true)
+		isUndefinedLength = true
+	} else {
+		valBytes, isUndefinedLength, err = encodeValue(elem.Value, vr)
+	}
 	if err != nil {
 		return int(cw.Count.Load()), err
 	}
+	// Native pixel data is kept word aligned for OW receivers
+	if elem.Tag == pixelDataTag && !isUndefinedLength {
+		valBytes = padEven(valBytes)
+	}
 
 	// Write Length and Value
-	if isLongVR(vr) {
+	if implicit || isLongVR(vr) {
 		// Reserved 2 bytes (0x00)
-		if _, err := cw.Write([]byte{0, 0}); err != nil {
-			return int(cw.Count.Load()), err
+		if !implicit {
+			if _, err := cw.Write([]byte{0, 0}); err != nil {
+				return int(cw.Count.Load()), err
+			}
 		}
 
 		length := uint32(len(valBytes))
@@ -117,7 +236,7 @@ func writeElement(w io.Writer, elem *Element) (int, error) {
 			return int(cw.Count.Load()), fmt.Errorf("undefined length not supported for Short VR %s", vr)
 		}
 		length := uint16(len(valBytes))
-		if err := binary.Write(w, binary.LittleEndian, length); err != nil {
+		if err := binary.Write(cw, binary.LittleEndian, length); err != nil {
 			return int(cw.Count.Load()), err
 		}
 	}
@@ -130,6 +249,34 @@ func writeElement(w io.Writer, elem *Element) (int, error) {
 	return int(cw.Count.Load()), nil
 }
 
+// writeBulkData writes the length and streams the payload of a bulk element whose tag and VR are already written
+func writeBulkData(cw *CountingWriter, vr string, bd *BulkData, implicit bool) (int, error) {
+	if !implicit && !isLongVR(vr) || bd.Length > math.MaxUint32-1 {
+		return int(cw.Count.Load()), fmt.Errorf("bulk data of %d bytes cannot be written with VR %s", bd.Length, vr)
+	}
+	if !implicit {
+		if _, err := cw.Write([]byte{0, 0}); err != nil {
+			return int(cw.Count.Load()), err
+		}
+	}
+	length := bd.Length
+	if length%2 != 0 {
+		length++
+	}
+	if err := binary.Write(cw, binary.LittleEndian, uint32(length)); err != nil {
+		return int(cw.Count.Load()), err
+	}
+	if _, err := bd.WriteTo(cw); err != nil {
+		return int(cw.Count.Load()), err
+	}
+	if length != bd.Length {
+		if _, err := cw.Write([]byte{0}); err != nil {
+			return int(cw.Count.Load()), err
+		}
+	}
+	return int(cw.Count.Load()), nil
+}
+
 // encodeValue returns encoded bytes and a bool indicating if undefined length used (e.g. encapsulated pixels)
 func encodeValue(v interface{}, vr string) ([]byte, bool, error) {
 	if v == nil {
@@ -145,14 +292,14 @@ func encodeValue(v interface{}, vr string) ([]byte, bool, error) {
 		// Native Pixel Data (falls through to []uint16 handling usually)
 		// But PixelData struct holds Frames []Frame.
 		// We need to flatten native frames.
-		return encodeNativePixelData(pd)
+		return encodeNativePixelData(pd, vr)
 	}
 
 	switch val := v.(type) {
 	case []*Dataset:
 		// Sequence Logic
 		if vr == "SQ" {
-			b, err := encodeSequence(val)
+			b, err := encodeSequence(val, false)
 			return b, true, err // Undefined Length for Sequence is typical/robust
 		}
 		return nil, false, fmt.Errorf("unexpected []*Dataset for VR %s", vr)
@@ -160,7 +307,7 @@ func encodeValue(v interface{}, vr string) ([]byte, bool, error) {
 		// Pad with space if odd
 		b := []byte(val)
 		if len(b)%2 != 0 {
-			b = append(b, ' ') // Default padding char
+			b = append(b, padByte(vr))
 		}
 		return b, false, nil
 	case []string:
@@ -174,7 +321,7 @@ func encodeValue(v interface{}, vr string) ([]byte, bool, error) {
 		}
 		b := []byte(joined)
 		if len(b)%2 != 0 {
-			b = append(b, ' ')
+			b = append(b, padByte(vr))
 		}
 		return b, false, nil
 	case uint16:
@@ -223,12 +370,47 @@ func encodeValue(v interface{}, vr string) ([]byte, bool, error) {
 			return b, false, nil
 		}
 		return nil, false, fmt.Errorf("float64 for VR %s not implemented", vr)
+	case uint32:
+		return binary.LittleEndian.AppendUint32(nil, val), false, nil
+	case []uint32:
+		b := make([]byte, 0, len(val)*4)
+		for _, u := range val {
+			b = binary.LittleEndian.AppendUint32(b, u)
+		}
+		return b, false, nil
+	case int16:
+		return binary.LittleEndian.AppendUint16(nil, uint16(val)), false, nil
+	case int32:
+		return binary.LittleEndian.AppendUint32(nil, uint32(val)), false, nil
+	case float32:
+		return binary.LittleEndian.AppendUint32(nil, math.Float32bits(val)), false, nil
 	case []float32:
 		b := make([]byte, len(val)*4)
 		for i, f := range val {
 			binary.LittleEndian.PutUint32(b[i*4:], math.Float32bits(f))
 		}
 		return b, false, nil
+	case []float64:
+		if vr == "FL" {
+			b := make([]byte, 0, len(val)*4)
+			for _, f := range val {
+				b = binary.LittleEndian.AppendUint32(b, math.Float32bits(float32(f)))
+			}
+			return b, false, nil
+		}
+		b := make([]byte, 0, len(val)*8)
+		for _, f := range val {
+			b = binary.LittleEndian.AppendUint64(b, math.Float64bits(f))
+		}
+		return b, false, nil
+	case Tag:
+		return appendTag(nil, val), false, nil
+	case []Tag:
+		b := make([]byte, 0, len(val)*4)
+		for _, t := range val {
+			b = appendTag(b, t)
+		}
+		return b, false, nil
 	case []byte:
 		return val, false, nil
 	}
@@ -236,7 +418,23 @@ func encodeValue(v interface{}, vr string) ([]byte, bool, error) {
 	return nil, false, fmt.Errorf("unsupported value type %T for VR %s", v, vr)
 }
 
-func encodeSequence(datasets []*Dataset) ([]byte, error) {
+// appendTag appends an AT value: the group then the element, little endian
+func appendTag(b []byte, t Tag) []byte {
+	b = binary.LittleEndian.AppendUint16(b, t.Group)
+	return binary.LittleEndian.AppendUint16(b, t.Element)
+}
+
+// padByte returns the padding for odd length strings: NUL for UI, space otherwise
+func padByte(vr string) byte {
+	if vr == "UI" {
+		return 0
+	}
+	return ' '
+}
+
+// encodeSequence encodes sequence items, their elements with implicit VR
+// when implicit is set, and the sequence delimiter
+func encodeSequence(datasets []*Dataset, implicit bool) ([]byte, error) {
 	var buf bytes.Buffer
 
 	for _, ds := range datasets {
@@ -245,7 +443,7 @@ func encodeSequence(datasets []*Dataset) ([]byte, error) {
 
 		// Encode Dataset Body to temp buffer to get length
 		var dsBuf bytes.Buffer
-		if _, err := writeDataSetBody(&dsBuf, ds); err != nil {
+		if _, err := writeDataSetBody(&dsBuf, ds, implicit); err != nil {
 			return nil, fmt.Errorf("failed to encode sequence item: %w", err)
 		}
 		dsBytes := dsBuf.Bytes()
@@ -266,46 +464,73 @@ func encodeSequence(datasets []*Dataset) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-func encodeNativePixelData(pd *PixelData) ([]byte, bool, error) {
-	// Provide flat byte buffer of native data
+// encodeNativePixelData flattens native frames into 16-bit little endian
+// samples, or single bytes padded to even length when vr is OB
+func encodeNativePixelData(pd *PixelData, vr string) ([]byte, bool, error) {
 	var buf bytes.Buffer
 	for _, frame := range pd.Frames {
-		// Native data is []uint16 (dicos.Frame)
-		// We assume 16-bit.
-		for _, pixel := range frame.Data {
-			binary.Write(&buf, binary.LittleEndian, pixel)
+		if vr == "OB" {
+			for _, sample := range frame.Data {
+				buf.WriteByte(uint8(sample))
+			}
+			continue
 		}
+		for _, sample := range frame.Data {
+			binary.Write(&buf, binary.LittleEndian, sample)
+		}
+	}
+	if buf.Len()%2 != 0 {
+		buf.WriteByte(0)
 	}
 	return buf.Bytes(), false, nil
 }
 
+// encodeEncapsulatedPixelData writes the offset table, the fragments padded
+// by pd.Padding and the delimiter. Offsets are recomputed when padding
+// changes a fragment length.
 func encodeEncapsulatedPixelData(pd *PixelData) ([]byte, error) {
 	var buf bytes.Buffer
 
+	fragments := make([][]byte, len(pd.Frames))
+	offsets := pd.Offsets
+	padded := false
+	for i, frame := range pd.Frames {
+		fragments[i] = padFragment(frame.CompressedData, pd.Padding)
+		padded = padded || len(fragments[i]) != len(frame.CompressedData)
+	}
+	if padded && len(offsets) == len(fragments) {
+		offsets = make([]uint32, len(fragments))
+		offset := uint32(0)
+		for i, f := range fragments {
+			offsets[i] = offset
+			offset += uint32(len(f)) + 8
+		}
+	}
+
 	// 1. Basic Offset Table (Item Tag)
 	// Tag FFFE,E000: Item
 	buf.Write([]byte{0xFE, 0xFF, 0x00, 0xE0})
 
 	// Length of BOT
-	botLen := uint32(len(pd.Offsets) * 4)
+	botLen := uint32(len(offsets) * 4)
 	binary.Write(&buf, binary.LittleEndian, botLen)
 
 	// Offsets
-	for _, off := range pd.Offsets {
+	for _, off := range offsets {
 		binary.Write(&buf, binary.LittleEndian, off)
 	}
 
 	// 2. Frames (Items)
-	for _, frame := range pd.Frames {
+	for _, fragment := range fragments {
 		// Item Tag
 		buf.Write([]byte{0xFE, 0xFF, 0x00, 0xE0})
 
 		// Length
-		itemLen := uint32(len(frame.CompressedData))
+		itemLen := uint32(len(fragment))
 		binary.Write(&buf, binary.LittleEndian, itemLen)
 
 		// Data
-		buf.Write(frame.CompressedData)
+		buf.Write(fragment)
 	}
 
 	// 3. Sequence Delimitation Item
@@ -0,0 +1,68 @@
+package dicos
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/jpfielding/dicos.go/pkg/dicos/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite_FileMetaGroupLength(t *testing.T) {
+	ds, err := NewDataset(
+		WithFileMeta(CTImageStorageUID, "1.2.3.4", string(ExplicitVRLittleEndian)),
+		WithElement(tag.PatientID, "ID1"),
+	)
+	require.NoError(t, err)
+	require.NotContains(t, ds.Elements, tag.FileMetaInformationGroupLength)
+
+	var buf bytes.Buffer
+	_, err = Write(&buf, ds)
+	require.NoError(t, err)
+	b := buf.Bytes()
+
+	// the meta starts with its group length, which ends it
+	require.Equal(t, []byte{0x02, 0x00, 0x00, 0x00, 'U', 'L', 0x04, 0x00}, b[132:140])
+	end := 144 + int(binary.LittleEndian.Uint32(b[140:]))
+	assert.Equal(t, uint16(0x0010), binary.LittleEndian.Uint16(b[end:]), "first data set element")
+	assert.NotContains(t, ds.Elements, tag.FileMetaInformationGroupLength, "ds is not changed")
+
+	got, err := ParseWithOptions(context.Background(), bytes.NewReader(b), ParseOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x00, 0x01}, got.Elements[tag.FileMetaInformationVersion].Value)
+	size, err := EstimateEncodedSize(ds)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(b)), size)
+}
+
+func TestWriteWithOptions_GroupLengths(t *testing.T) {
+	patientLength := Tag{Group: 0x0010}
+	ds, err := NewDataset(
+		WithFileMeta(CTImageStorageUID, "1.2.3.4", string(ExplicitVRLittleEndian)),
+		WithElement(tag.PatientName, "DOE^J"),
+		WithElement(tag.PatientID, "ID1"),
+		WithElement(tag.Modality, "CT"),
+		withVR(patientLength, "UL", uint32(999)), // stale
+	)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	_, err = Write(&buf, ds)
+	require.NoError(t, err)
+	got, err := ParseWithOptions(context.Background(), bytes.NewReader(buf.Bytes()), ParseOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(14+12), got.Elements[patientLength].Value, "recomputed")
+	assert.NotContains(t, got.Elements, Tag{Group: 0x0008}, "retired, only written on request")
+
+	buf.Reset()
+	_, err = WriteWithOptions(&buf, ds, WriteOptions{GroupLengths: true})
+	require.NoError(t, err)
+	got, err = ParseWithOptions(context.Background(), bytes.NewReader(buf.Bytes()), ParseOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(10), got.Elements[Tag{Group: 0x0008}].Value)
+	assert.Equal(t, uint32(26), got.Elements[patientLength].Value)
+	assert.Equal(t, uint32(999), ds.Elements[patientLength].Value, "ds is not changed")
+}
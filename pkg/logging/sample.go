@@ -0,0 +1,82 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+var _ slog.Handler = &SampleHandler{}
+
+// SampleHandler passes the first records of each level and message in every
+// tick, then every Nth, so a warning repeated per frame or per pixel costs a
+// counter instead of a write
+type SampleHandler struct {
+	slog.Handler
+	counts *sampleCounts
+}
+
+type sampleKey struct {
+	level slog.Level
+	msg   string
+}
+
+// sampleCounts is shared by a handler and those derived with attrs or groups
+type sampleCounts struct {
+	mu         sync.Mutex
+	first      int
+	thereafter int
+	tick       time.Duration
+	start      time.Time
+	seen       map[sampleKey]int
+}
+
+// Sample wraps h to pass the first records of each level and message in every
+// tick, then every thereafter-th of them. Zero thereafter drops the rest of
+// the tick, and zero tick never resets the counts.
+func Sample(h slog.Handler, first, thereafter int, tick time.Duration) *SampleHandler {
+	return &SampleHandler{Handler: h, counts: &sampleCounts{
+		first:      first,
+		thereafter: thereafter,
+		tick:       tick,
+		seen:       make(map[sampleKey]int),
+	}}
+}
+
+// Handle passes r to the underlying handler if it is sampled
+func (h *SampleHandler) Handle(ctx context.Context, r slog.Record) error {
+	if !h.counts.keep(sampleKey{level: r.Level, msg: r.Message}, r.Time) {
+		return nil
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+// WithAttrs returns a handler sharing the counts of h
+func (h *SampleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SampleHandler{Handler: h.Handler.WithAttrs(attrs), counts: h.counts}
+}
+
+// WithGroup returns a handler sharing the counts of h
+func (h *SampleHandler) WithGroup(name string) slog.Handler {
+	return &SampleHandler{Handler: h.Handler.WithGroup(name), counts: h.counts}
+}
+
+// keep counts a record and returns true if it is sampled
+func (c *sampleCounts) keep(k sampleKey, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if now.IsZero() {
+		now = time.Now()
+	}
+	if c.tick > 0 && now.Sub(c.start) >= c.tick {
+		c.start = now
+		clear(c.seen)
+	}
+	c.seen[k]++
+	n := c.seen[k]
+	if n <= c.first {
+		return true
+	}
+	return c.thereafter > 0 && (n-c.first)%c.thereafter == 0
+}